@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/internal/repository"
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/transport/casher"
+	"github.com/Koyo-os/form-service/pkg/transport/publisher"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Stores backfill rebuilds
+const (
+	TARGET_CACHE      = "cache"      // The Redis copies of the forms
+	TARGET_READ_MODEL = "read_model" // The respondent projection of the published forms
+	TARGET_OUTBOX     = "outbox"     // form.updated of every form, for downstream stores such as search indexes
+
+	// TARGET_SEARCH is refused: search indexes are built by consumers of
+	// the form events, so they are rebuilt through TARGET_OUTBOX
+	TARGET_SEARCH = "search"
+)
+
+const (
+	// CHECKPOINT_KEY_TEMPLATE is the Redis key of the last form backfilled
+	// into a target, so an interrupted backfill resumes after it
+	CHECKPOINT_KEY_TEMPLATE = "formctl:backfill:%s"
+
+	// CHECKPOINT_TTL is how long an interrupted backfill can be resumed
+	CHECKPOINT_TTL = 7 * 24 * time.Hour
+
+	// STORE_TIMEOUT bounds storing a single form
+	STORE_TIMEOUT = 10 * time.Second
+)
+
+// storeFunc writes a form to the store being rebuilt
+type storeFunc func(ctx context.Context, form *entity.Form) error
+
+// backfill streams every form from the database into a derived store
+func backfill(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	target := flags.String("target", "", "store to rebuild: cache, read_model or outbox")
+	rate := flags.Int("rate", 50, "most forms stored per second, 0 for no limit")
+	batch := flags.Int("batch", 100, "forms read from the database per query")
+	restart := flags.Bool("restart", false, "ignore the checkpoint of an interrupted backfill")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 0 || *batch < 1 || *rate < 0 {
+		return errors.New("backfill takes -target and optionally a positive -batch and -rate")
+	}
+
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: cfg.Urls.Redis})
+	defer client.Close()
+
+	store, closeStore, err := openStore(cfg, *target, repo, client)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	checkpoint := fmt.Sprintf(CHECKPOINT_KEY_TEMPLATE, *target)
+
+	after := uuid.Nil
+	if *restart {
+		if err = client.Del(ctx, checkpoint).Err(); err != nil {
+			return fmt.Errorf("failed to reset checkpoint: %w", err)
+		}
+	} else if after, err = loadCheckpoint(ctx, client, checkpoint); err != nil {
+		return err
+	} else if after != uuid.Nil {
+		fmt.Fprintf(os.Stderr, "resuming %s backfill after form %s\n", *target, after)
+	}
+
+	total, err := repo.CountForms()
+	if err != nil {
+		return fmt.Errorf("failed to count forms: %w", err)
+	}
+
+	var throttle <-chan time.Time
+	if *rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(*rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	done := 0
+	start := time.Now()
+
+	for {
+		forms, err := repo.ListFormsAfter(after, *batch)
+		if err != nil {
+			return fmt.Errorf("failed to list forms: %w", err)
+		}
+		if len(forms) == 0 {
+			break
+		}
+
+		for i := range forms {
+			if throttle != nil {
+				select {
+				case <-throttle:
+				case <-ctx.Done():
+				}
+			}
+
+			if err = ctx.Err(); err == nil {
+				err = storeForm(ctx, store, &forms[i])
+			}
+
+			if err != nil {
+				if saveErr := saveCheckpoint(client, checkpoint, after); saveErr != nil {
+					return errors.Join(err, saveErr)
+				}
+				return fmt.Errorf("backfill stopped after %d forms, rerun to resume: %w", done, err)
+			}
+
+			after = forms[i].ID
+			done++
+		}
+
+		if err = saveCheckpoint(client, checkpoint, after); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: %d/%d forms, %.0f/s\n", *target, done, total, float64(done)/time.Since(start).Seconds())
+	}
+
+	if err = client.Del(context.Background(), checkpoint).Err(); err != nil {
+		return fmt.Errorf("failed to clear checkpoint: %w", err)
+	}
+
+	fmt.Printf("backfilled %d forms into %s in %s\n", done, *target, time.Since(start).Round(time.Second))
+
+	return nil
+}
+
+// openStore returns the store a target names and a function releasing it
+func openStore(cfg *config.Config, target string, repo *repository.Repository, client *redis.Client) (storeFunc, func(), error) {
+	log := &logger.Logger{Logger: zap.NewNop()}
+
+	switch target {
+	case TARGET_CACHE:
+		cache := casher.Init(client, log)
+
+		return func(ctx context.Context, form *entity.Form) error {
+			return cache.AddToCash(ctx, form.ID.String(), form)
+		}, func() {}, nil
+
+	case TARGET_READ_MODEL:
+		return func(_ context.Context, form *entity.Form) error {
+			return projectForm(repo, form)
+		}, func() {}, nil
+
+	case TARGET_OUTBOX:
+		conn, err := amqp.Dial(cfg.Urls.Rabbitmq)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		}
+
+		pub, err := publisher.Init(cfg, log, conn)
+		if err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("failed to open publisher: %w", err)
+		}
+
+		return func(_ context.Context, form *entity.Form) error {
+			return pub.Publish(form, events.FormUpdated.String())
+		}, func() { pub.Close() }, nil
+
+	case TARGET_SEARCH:
+		return nil, nil, errors.New("the service has no search index of its own, backfill the outbox to reindex the search consumers")
+	}
+
+	return nil, nil, fmt.Errorf("unknown target %q, want cache, read_model or outbox", target)
+}
+
+// projectForm stores the respondent view of a form, or removes it unless
+// the form is published and has a version, like the service read model
+func projectForm(repo *repository.Repository, form *entity.Form) error {
+	if !form.AcceptsResponses() {
+		return repo.DeletePublicForm(form.ID)
+	}
+
+	version, err := repo.GetFormVersion(form.ID, 0)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return repo.DeletePublicForm(form.ID)
+	case err != nil:
+		return fmt.Errorf("failed to retrieve form version: %w", err)
+	}
+
+	public, err := entity.NewPublicForm(version, form)
+	if err != nil {
+		return err
+	}
+
+	return repo.SavePublicForm(public)
+}
+
+// storeForm writes one form, bounded by STORE_TIMEOUT
+func storeForm(ctx context.Context, store storeFunc, form *entity.Form) error {
+	ctx, cancel := context.WithTimeout(ctx, STORE_TIMEOUT)
+	defer cancel()
+
+	if err := store(ctx, form); err != nil {
+		return fmt.Errorf("failed to store form %s: %w", form.ID, err)
+	}
+
+	return nil
+}
+
+// loadCheckpoint returns the last form an interrupted backfill stored,
+// uuid.Nil for none
+func loadCheckpoint(ctx context.Context, client *redis.Client, key string) (uuid.UUID, error) {
+	last, err := client.Get(ctx, key).Result()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return uuid.Nil, nil
+	case err != nil:
+		return uuid.Nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	id, err := uuid.Parse(last)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid checkpoint %q, rerun with -restart: %w", last, err)
+	}
+
+	return id, nil
+}
+
+// saveCheckpoint records the last form stored, unless none was. It doesn't
+// take the backfill context, so the position is kept once interrupted.
+func saveCheckpoint(client *redis.Client, key string, last uuid.UUID) error {
+	if last == uuid.Nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), STORE_TIMEOUT)
+	defer cancel()
+
+	if err := client.Set(ctx, key, last.String(), CHECKPOINT_TTL).Err(); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+// Command formctl manages form definitions as portable YAML files.
+//
+//	formctl export <form-id>        print the definition of a stored form
+//	formctl apply [-dry-run] <file> save a definition, "-" reads stdin
+//	formctl cache get <form-id>     print the cached form, its size and TTL
+//	formctl cache del <form-id>     evict the cached form
+//	formctl cache ttl <form-id>     print the remaining TTL of the cached form
+//	formctl backfill [flags]        rebuild a store derived from the database
+//
+// export reads from the database, apply validates the definition locally and
+// sends it to the service as a save-form request, so the form is stored,
+// cached and published like any editor save. Database credentials come from
+// the same DB_* environment variables the service uses. cache talks to the
+// Redis instance of the service config, to debug stale cached forms.
+//
+// backfill streams every form from the database, in ID order, into the
+// cache, the read model or the outbox, for disaster recovery and new
+// stores. The outbox target publishes form.updated for every form, which
+// is how downstream stores such as search indexes are bootstrapped. It
+// reports progress on stderr, stores at most -rate forms per second and
+// keeps a checkpoint in Redis, so an interrupted backfill resumes where it
+// stopped; -restart starts it over.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/question"
+	"github.com/Koyo-os/form-service/internal/repository"
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/transport/casher"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// PUBLISH_TIMEOUT bounds sending the save-form request
+const PUBLISH_TIMEOUT = 10 * time.Second
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "path to the service config")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Init(*configPath)
+	if err != nil {
+		fatal(fmt.Errorf("failed to load config: %w", err))
+	}
+
+	switch flag.Arg(0) {
+	case "export":
+		err = export(cfg, flag.Args()[1:])
+	case "apply":
+		err = apply(cfg, flag.Args()[1:])
+	case "cache":
+		err = cache(cfg, flag.Args()[1:])
+	case "backfill":
+		err = backfill(cfg, flag.Args()[1:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  formctl [-config path] export <form-id>
+  formctl [-config path] apply [-dry-run] <file|->
+  formctl [-config path] cache get|del|ttl <form-id>
+  formctl [-config path] backfill -target=cache|read_model|outbox [-rate n] [-batch n] [-restart]`)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "formctl:", err)
+	os.Exit(1)
+}
+
+// export prints the YAML definition of a stored form
+func export(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("export takes exactly one form id")
+	}
+
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid form id: %w", err)
+	}
+
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	form, err := repo.Get(id)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	data, err := form.ToYAML()
+	if err != nil {
+		return fmt.Errorf("failed to encode form: %w", err)
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// apply validates a definition and sends it to the service
+func apply(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "validate and print the form without applying it")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("apply takes exactly one file")
+	}
+
+	data, err := readInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	def, err := entity.ParseFormDefinition(data)
+	if err != nil {
+		return err
+	}
+
+	repo, err := openRepository(cfg)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(def.ID)
+	if err != nil {
+		return fmt.Errorf("invalid form id: %w", err)
+	}
+
+	existing, err := repo.Get(id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	form, err := def.ToForm(existing)
+	if err != nil {
+		return err
+	}
+
+	for i := range form.Questions {
+		if err = question.Default.ValidateQuestion(&form.Questions[i]); err != nil {
+			return fmt.Errorf("invalid question: %w", err)
+		}
+	}
+
+	payload, err := json.Marshal(form)
+	if err != nil {
+		return fmt.Errorf("failed to encode form: %w", err)
+	}
+
+	if *dryRun {
+		fmt.Printf("form %s is valid, %d questions (existing form: %t)\n", form.ID, len(form.Questions), existing != nil)
+		return nil
+	}
+
+	if err = publish(cfg, entity.NewEvent(cfg.Reqs.SaveFormRequestType, payload)); err != nil {
+		return err
+	}
+
+	fmt.Printf("form %s applied\n", form.ID)
+
+	return nil
+}
+
+// cache inspects or evicts the cached copy of a form
+func cache(cfg *config.Config, args []string) error {
+	if len(args) != 2 {
+		return errors.New("cache takes a command, get, del or ttl, and one form id")
+	}
+
+	id, err := uuid.Parse(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid form id: %w", err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: cfg.Urls.Redis})
+	defer client.Close()
+
+	cache := casher.Init(client, &logger.Logger{Logger: zap.NewNop()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), PUBLISH_TIMEOUT)
+	defer cancel()
+
+	if args[0] == "del" {
+		if err = cache.RemoveFromCash(ctx, id.String()); err != nil {
+			return fmt.Errorf("failed to evict cached form: %w", err)
+		}
+
+		fmt.Printf("form %s evicted\n", id)
+		return nil
+	}
+
+	data, ttl, err := cache.InspectCash(ctx, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to inspect cached form: %w", err)
+	}
+
+	switch args[0] {
+	case "get":
+		out, err := json.MarshalIndent(entity.NewCacheEntry(id.String(), data, ttl), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode cached form: %w", err)
+		}
+
+		fmt.Println(string(out))
+	case "ttl":
+		switch {
+		case data == nil:
+			fmt.Printf("form %s is not cached\n", id)
+		case ttl <= 0:
+			fmt.Printf("form %s is cached without expiry\n", id)
+		default:
+			fmt.Printf("form %s expires in %s\n", id, ttl.Round(time.Second))
+		}
+	default:
+		return fmt.Errorf("unknown cache command %q, want get, del or ttl", args[0])
+	}
+
+	return nil
+}
+
+// readInput reads a file, or stdin for "-"
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return data, nil
+}
+
+// openRepository connects to the service database
+func openRepository(cfg *config.Config) (*repository.Repository, error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+	)
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger:         gormlogger.Discard,
+		NamingStrategy: repository.NamingStrategy(cfg.Database.TablePrefix, cfg.Database.SingularTables),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return repository.Init(db, &logger.Logger{Logger: zap.NewNop()}), nil
+}
+
+// publish sends a request event to the request exchange
+func publish(cfg *config.Config, event any) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	conn, err := amqp.Dial(cfg.Urls.Rabbitmq)
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer channel.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), PUBLISH_TIMEOUT)
+	defer cancel()
+
+	if err = channel.PublishWithContext(ctx,
+		cfg.Exchange.Request,
+		cfg.Reqs.SaveFormRequestType,
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+			Timestamp:   time.Now(),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to publish save request: %w", err)
+	}
+
+	return nil
+}
@@ -2,24 +2,46 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
 	"github.com/Koyo-os/form-service/internal/repository"
 	"github.com/Koyo-os/form-service/internal/service"
+	"github.com/Koyo-os/form-service/pkg/batch"
 	"github.com/Koyo-os/form-service/pkg/closer"
+	"github.com/Koyo-os/form-service/pkg/coalesce"
 	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/counters"
+	"github.com/Koyo-os/form-service/pkg/eventbus"
 	"github.com/Koyo-os/form-service/pkg/health"
+	"github.com/Koyo-os/form-service/pkg/heartbeat"
 	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/loopback"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/Koyo-os/form-service/pkg/ratelimit"
 	"github.com/Koyo-os/form-service/pkg/retrier"
+	"github.com/Koyo-os/form-service/pkg/scheduler"
+	"github.com/Koyo-os/form-service/pkg/schemaregistry"
+	"github.com/Koyo-os/form-service/pkg/storage"
+	"github.com/Koyo-os/form-service/pkg/telemetry"
+	"github.com/Koyo-os/form-service/pkg/throttle"
 	"github.com/Koyo-os/form-service/pkg/transport/casher"
 	"github.com/Koyo-os/form-service/pkg/transport/consumer"
+	"github.com/Koyo-os/form-service/pkg/transport/envelope"
 	"github.com/Koyo-os/form-service/pkg/transport/listener"
 	"github.com/Koyo-os/form-service/pkg/transport/publisher"
+	"github.com/Koyo-os/form-service/pkg/transport/stream"
+	"github.com/Koyo-os/form-service/pkg/version"
+	"github.com/Koyo-os/form-service/pkg/watchdog"
+	"github.com/Koyo-os/form-service/pkg/webhook"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -64,39 +86,110 @@ func main() {
 		os.Getenv("DB_NAME"),
 	)
 
-	logger.Info("connecting to mariadb...", zap.String("dsn", dsn))
+	dump, err := cfg.Dump()
+	if err != nil {
+		logger.Warn("error dump config", zap.Error(err))
+	}
+
+	logger.Info("effective configuration",
+		zap.Any("config", dump),
+		zap.String("database_dsn", config.MaskDSN(dsn)))
+
+	// Dependency clients report spans and metrics once instrumented
+	var tel *telemetry.Telemetry
+	if cfg.Telemetry.Enabled {
+		tel, err = telemetry.Init(context.Background(), telemetry.Options{
+			ServiceName:  "form-service",
+			OTLPEndpoint: cfg.Telemetry.OTLPEndpoint,
+			Insecure:     cfg.Telemetry.Insecure,
+		})
+		if err != nil {
+			logger.Error("error init telemetry", zap.Error(err))
+			return
+		}
+	}
+
+	logger.Info("connecting to mariadb...", zap.String("dsn", config.MaskDSN(dsn)))
 
 	db, err := retrier.Connect(10, 10, func() (*gorm.DB, error) {
-		return gorm.Open(mysql.Open(dsn))
+		return gorm.Open(mysql.Open(dsn), &gorm.Config{
+			NamingStrategy: repository.NamingStrategy(cfg.Database.TablePrefix, cfg.Database.SingularTables),
+		})
 	})
 	if err != nil {
 		logger.Error("error initialyze database",
-			zap.String("dsn", dsn),
+			zap.String("dsn", config.MaskDSN(dsn)),
 			zap.Error(err))
 
 		return
 	}
 
-	logger.Info("connected to mariadb", zap.String("dsn", dsn))
+	logger.Info("connected to mariadb", zap.String("dsn", config.MaskDSN(dsn)))
 
-	if err := db.AutoMigrate(&entity.Form{}, &entity.Question{}); err != nil {
-		logger.Error("failed to migrate database", zap.Error(err))
-		return
+	if tel != nil {
+		if err := tel.InstrumentGorm(db); err != nil {
+			logger.Error("error instrument database", zap.Error(err))
+			return
+		}
 	}
 
 	repo := repository.Init(db, logger)
 
+	// The version table is missing on a fresh database
+	previousVersion, err := repo.AppliedSchemaVersion()
+	if err != nil {
+		logger.Info("no schema version recorded", zap.Error(err))
+	}
+
+	// Never migrate a schema a newer build already moved past
+	migrated := false
+	if cfg.Migrations.Auto && previousVersion <= repository.SchemaVersion {
+		if err := repo.Migrate(); err != nil {
+			logger.Error("failed to migrate database", zap.Error(err))
+			return
+		}
+
+		migrated = previousVersion < repository.SchemaVersion
+	}
+
+	if err := repo.CheckSchemaVersion(); err != nil {
+		var mismatch *repository.SchemaMismatchError
+		if !errors.As(err, &mismatch) || cfg.Migrations.OnMismatch != "read_only" {
+			logger.Error("refusing to start on this database schema", zap.Error(err))
+			return
+		}
+
+		logger.Warn("starting read-only on a mismatched database schema", zap.Error(err))
+
+		if err := repo.SetReadOnly(); err != nil {
+			logger.Error("failed to make repository read-only", zap.Error(err))
+			return
+		}
+	}
+
+	schemaVersion, err := repo.AppliedSchemaVersion()
+	if err != nil {
+		logger.Error("failed to read schema version", zap.Error(err))
+		return
+	}
+
 	rabbitmqConns, err := retrier.MultiConnects(2, func() (*amqp.Connection, error) {
 		return amqp.Dial(cfg.Urls.Rabbitmq)
 	}, &retrier.RetrierOpts{Count: 3, Interval: 5})
 	if err != nil {
 		logger.Error("error connect to rabbitmq",
-			zap.String("url", cfg.Urls.Rabbitmq),
+			zap.String("url", config.MaskDSN(cfg.Urls.Rabbitmq)),
 			zap.Error(err))
 
 		return
 	}
 
+	if err = envelope.ValidStyle(cfg.Publisher.Envelope); err != nil {
+		logger.Error("invalid publisher config", zap.Error(err))
+
+		return
+	}
+
 	publisher, err := publisher.Init(cfg, logger, rabbitmqConns[0])
 	if err != nil {
 		logger.Error("error initialize publisher", zap.Error(err))
@@ -104,6 +197,30 @@ func main() {
 		return
 	}
 
+	// Consumers decode payloads by the schema ID sent with each event
+	if registryURL := cfg.SchemaRegistry.URL; registryURL != "" {
+		schemas, err := events.Schemas()
+		if err != nil {
+			logger.Error("error generate payload schemas", zap.Error(err))
+			return
+		}
+
+		registry := schemaregistry.New(registryURL, schemaregistry.Options{
+			Username: cfg.SchemaRegistry.Username,
+			Password: cfg.SchemaRegistry.Password,
+			Timeout:  time.Duration(cfg.SchemaRegistry.Timeout) * time.Second,
+		})
+
+		ids, err := registry.Sync(context.Background(), schemas)
+		if err != nil {
+			logger.Error("error register payload schemas", zap.Error(err))
+			return
+		}
+
+		publisher.UseSchemas(ids)
+		logger.Info("registered payload schemas", zap.Int("count", len(ids)))
+	}
+
 	consumer, err := consumer.Init(cfg, logger, rabbitmqConns[1])
 	if err != nil {
 		logger.Error("error initialize consumer", zap.Error(err))
@@ -126,30 +243,533 @@ func main() {
 		return
 	}
 
+	if tel != nil {
+		if err := tel.InstrumentRedis(redisConn); err != nil {
+			logger.Error("error instrument redis", zap.Error(err))
+			return
+		}
+	}
+
+	// The quota only applies once the usage job measured the cache
+	cacheQuota := casher.QuotaOptions{
+		MaxBytes:      cfg.Cache.MaxBytes,
+		ReducedTTL:    time.Duration(cfg.Cache.ReducedTTL) * time.Second,
+		MaxEntryBytes: cfg.Cache.MaxEntryBytes,
+	}
+
 	casher := casher.Init(redisConn, logger)
+	casher.SetQuota(cacheQuota)
+
+	// A misspelled coalesced type would silently never coalesce
+	for _, eventType := range cfg.Coalesce.EventTypes {
+		if _, err := events.Parse(eventType); err != nil {
+			logger.Warn("coalesced event type is not published", zap.Error(err))
+		}
+	}
+
+	// Domain events fan out through the bus; AMQP is one of its subscribers
+	bus := eventbus.New(logger)
+	coalescer := coalesce.New(logger, publisher.Publish, coalesce.Options{
+		Window:     time.Duration(cfg.Coalesce.WindowMs) * time.Millisecond,
+		EventTypes: cfg.Coalesce.EventTypes,
+	})
+	bus.Subscribe("amqp", eventbus.WILDCARD, coalescer.Publish)
+	bus.Subscribe("metrics", eventbus.WILDCARD, metrics.CountDomainEvent)
+
+	// The bus retries each failing subscriber on its own, so a retry doesn't
+	// deliver the event again to the subscribers that already got it
+	publishRetry := service.RetryPolicyFromConfig(cfg.Retry.Publish)
+	bus.SetRetryPolicy(publishRetry.Retries, publishRetry.Delay)
+
+	core := service.Init(casher, repo, bus, 10*time.Second)
+	core.SetLogger(logger)
+	core.SetRetryPolicy(service.RetryPolicyFromConfig(cfg.Retry.Cache), service.RetryPolicy{})
+
+	if cfg.Publisher.FormUpdates == service.FormUpdatesSnapshotDiff {
+		core.EnableFormDiffs()
+	}
+
+	core.EnableTenantSettings(repo, entity.Settings{
+		RateLimit:     cfg.Tenants.RateLimit,
+		MaxQuestions:  cfg.Tenants.MaxQuestions,
+		RetentionDays: cfg.Tenants.RetentionDays,
+	}, time.Duration(cfg.Tenants.CacheTTL)*time.Second)
+
+	core.EnableQuotas(repo, entity.QuotaLimits{
+		MaxForms:     cfg.Quotas.MaxForms,
+		MaxQuestions: cfg.Quotas.MaxQuestions,
+		MaxPerForm:   cfg.Quotas.MaxQuestionsPerForm,
+	})
+
+	rateMode := ratelimit.Mode(cfg.RateLimit.Mode)
+
+	core.EnableRateLimits(ratelimit.New(redisConn, logger, ratelimit.Options{
+		Name:   "tenant_requests",
+		Window: time.Minute,
+		Mode:   rateMode,
+	}))
+
+	core.EnableSpamProtection(ratelimit.New(redisConn, logger, ratelimit.Options{
+		Name:   "responses",
+		Window: time.Minute,
+		Mode:   rateMode,
+	}), cfg.RateLimit.ResponsesPerMinute)
+
+	for sideEffect, policy := range core.RetryPolicies() {
+		logger.Info("effective retry policy",
+			zap.String("side_effect", sideEffect),
+			zap.Uint8("retries", policy.Retries),
+			zap.Duration("delay", policy.Delay))
+	}
+
+	logger.Info("effective retry policy",
+		zap.String("side_effect", eventbus.SIDE_EFFECT),
+		zap.Uint8("retries", publishRetry.Retries),
+		zap.Duration("delay", publishRetry.Delay))
+
+	healthers := []health.Healther{publisher, casher, consumer, repo}
+	jobs := scheduler.New(logger)
+
+	if cfg.Storage.Endpoint != "" {
+		store, err := storage.Init(context.Background(), storage.Options{
+			Endpoint:  cfg.Storage.Endpoint,
+			AccessKey: os.Getenv("STORAGE_ACCESS_KEY"),
+			SecretKey: os.Getenv("STORAGE_SECRET_KEY"),
+			Bucket:    cfg.Storage.Bucket,
+			UseSSL:    cfg.Storage.UseSSL,
+		}, logger)
+		if err != nil {
+			logger.Error("error connect to storage",
+				zap.String("endpoint", cfg.Storage.Endpoint),
+				zap.Error(err))
+			return
+		}
+
+		core.EnableUploads(repo, store, time.Duration(cfg.Uploads.UrlTTL)*time.Second)
+		healthers = append(healthers, store)
+
+		jobs.Every("upload-cleanup", time.Duration(cfg.Uploads.CleanupInterval)*time.Second, func(ctx context.Context) error {
+			removed, err := core.CleanupOrphanUploads(ctx)
+			if removed > 0 {
+				logger.Info("removed orphaned uploads", zap.Int("count", removed))
+			}
+			return err
+		})
+	}
+
+	if cfg.Cache.UsageInterval > 0 {
+		jobs.Every("cache-usage", time.Duration(cfg.Cache.UsageInterval)*time.Second, func(ctx context.Context) error {
+			_, err := casher.MeasureUsage(ctx)
+			return err
+		})
+	}
+
+	if cfg.Responses.Store {
+		core.EnableResponses(repo)
+
+		if cfg.Responses.BatchIntervalMs > 0 {
+			core.EnableResponseBatching(repo, batch.Options{
+				Interval: time.Duration(cfg.Responses.BatchIntervalMs) * time.Millisecond,
+				MaxSize:  cfg.Responses.BatchSize,
+			})
+		}
+	}
+
+	if cfg.QueryCache.TTL > 0 {
+		core.EnableQueryCache(casher, time.Duration(cfg.QueryCache.TTL)*time.Second)
+		bus.Subscribe("query_cache", eventbus.WILDCARD, core.InvalidateQueries)
+	}
+
+	if cfg.ReadModel.Enabled {
+		core.EnableReadModel(repo)
+		bus.Subscribe("read_model", service.ProjectedEvents, core.ProjectEvent)
+	}
+
+	if cfg.Webhooks.Enabled {
+		sender := webhook.NewSender(time.Duration(cfg.Webhooks.Timeout) * time.Second)
+		core.EnableWebhooks(repo, sender, service.RetryPolicyFromConfig(cfg.Webhooks.Retry))
+		bus.Subscribe("webhooks", service.WebhookEvents, core.DeliverWebhooks)
+	}
+
+	core.EnableCacheInspection(casher)
+	core.SetAdmins(cfg.Admins)
+
+	// Reports of privacy requests are signed, so they're only served with a key
+	if key := os.Getenv("PRIVACY_SIGNING_KEY"); key != "" {
+		core.EnablePrivacyRequests(repo, []byte(key))
+	}
+
+	if cfg.Reconcile.OnStartup || cfg.Reconcile.Interval > 0 {
+		core.EnableCacheReconciliation(casher, service.ReconcileOptions{
+			SampleSize: cfg.Reconcile.SampleSize,
+			Repair:     cfg.Reconcile.Repair,
+		})
+
+		reconcile := func(ctx context.Context) error {
+			report, err := core.ReconcileCache(ctx)
+			if report.Stale > 0 || report.Orphaned > 0 {
+				logger.Warn("cached forms diverge from the database",
+					zap.Int("checked", report.Checked),
+					zap.Int("stale", report.Stale),
+					zap.Int("orphaned", report.Orphaned),
+					zap.Int("repaired", report.Repaired))
+			}
+			return err
+		}
+
+		if cfg.Reconcile.OnStartup {
+			jobs.Once("cache-reconcile-startup", reconcile)
+		}
+
+		if cfg.Reconcile.Interval > 0 {
+			jobs.Every("cache-reconcile", time.Duration(cfg.Reconcile.Interval)*time.Second, reconcile)
+		}
+	}
+
+	if cfg.Integrity.Interval > 0 {
+		core.EnableIntegrityChecks(repo, casher, service.IntegrityOptions{
+			SampleSize: cfg.Integrity.SampleSize,
+			Repair:     cfg.Integrity.Repair,
+		})
 
-	core := service.Init(casher, repo, publisher, 10*time.Second)
+		jobs.Every("integrity", time.Duration(cfg.Integrity.Interval)*time.Second, func(ctx context.Context) error {
+			report, err := core.CheckIntegrity(ctx)
+			if report.Orphaned > 0 || report.Divergent > 0 {
+				logger.Warn("forms and questions are inconsistent",
+					zap.Int("orphaned_questions", report.Orphaned),
+					zap.Int("checked", report.Checked),
+					zap.Int("divergent", report.Divergent),
+					zap.Int("repaired", report.Repaired))
+			}
+			return err
+		})
+	}
+
+	if cfg.Schedules.Interval > 0 {
+		jobs.Every("form-schedules", time.Duration(cfg.Schedules.Interval)*time.Second, func(ctx context.Context) error {
+			applied, err := core.ApplySchedules(ctx)
+			if applied > 0 {
+				logger.Info("applied form schedules", zap.Int("count", applied))
+			}
+			return err
+		})
+	}
+
+	// Requests acknowledged once handled may be redelivered, so forms are
+	// created in one transaction with the record of their request
+	if cfg.Delivery.ManualAck {
+		core.EnableExactlyOnce(repo)
+	}
+
+	if cfg.Delivery.ManualAck && cfg.Delivery.ProcessedRetention > 0 {
+		retention := time.Duration(cfg.Delivery.ProcessedRetention) * time.Hour
+		jobs.Every("processed-events", time.Hour, func(context.Context) error {
+			pruned, err := core.PruneProcessedEvents(retention)
+			if pruned > 0 {
+				logger.Debug("pruned processed events", zap.Int64("count", pruned))
+			}
+			return err
+		})
+	}
+
+	if cfg.Stats.PersistInterval > 0 {
+		core.EnableQuestionStats(counters.New(redisConn, logger, "question_stats"), repo)
+
+		jobs.Every("question-stats", time.Duration(cfg.Stats.PersistInterval)*time.Second, func(ctx context.Context) error {
+			persisted, err := core.PersistQuestionStats(ctx)
+			if persisted > 0 {
+				logger.Debug("persisted question stats", zap.Int("forms", persisted))
+			}
+			return err
+		})
+	}
+
+	if cfg.Stats.FormCounters {
+		core.EnableFormStats(repo)
+	}
+
+	build := version.Get("form-service", schemaVersion, cfg.Features)
+
+	logger.Info("build info",
+		zap.String("git_sha", build.GitSHA),
+		zap.String("build_time", build.BuildTime),
+		zap.Int("schema_version", build.SchemaVersion),
+		zap.Strings("features", build.Features))
+
+	if err = bus.Publish(context.Background(), build, events.ServiceVersion.String()); err != nil {
+		logger.Warn("error publish version event", zap.Error(err))
+	}
+
+	instance, _ := os.Hostname()
+
+	// ops publishes notable operational occurrences for the alerting pipeline
+	ops := func(eventType events.Type, severity, summary string, details map[string]string) {
+		if err := core.PublishOpsEvent(eventType, &entity.OpsEvent{
+			Instance:   instance,
+			Severity:   severity,
+			Summary:    summary,
+			Details:    details,
+			OccurredAt: time.Now(),
+		}); err != nil {
+			logger.Warn("error publish ops event", zap.String("type", eventType.String()), zap.Error(err))
+		}
+	}
+
+	publisher.OnReconnect(func() {
+		ops(events.OpsBrokerReconnected, entity.OpsInfo, "publisher reconnected to the broker",
+			map[string]string{"component": "publisher"})
+	})
+	consumer.OnReconnect(func() {
+		ops(events.OpsBrokerReconnected, entity.OpsInfo, "consumer reconnected to the broker",
+			map[string]string{"component": "consumer"})
+	})
+	consumer.OnMaintenance(func(queue string, paused bool) {
+		summary := "queue resumed after maintenance"
+		if paused {
+			summary = "queue paused for maintenance"
+		}
+
+		ops(events.OpsMaintenance, entity.OpsWarning, summary,
+			map[string]string{"queue": queue, "enabled": strconv.FormatBool(paused)})
+	})
+
+	if migrated {
+		if err = bus.Publish(context.Background(), &entity.MigrationApplied{
+			SchemaVersion:   schemaVersion,
+			PreviousVersion: previousVersion,
+			AppliedAt:       time.Now(),
+		}, events.MigrationApplied.String()); err != nil {
+			logger.Warn("error publish migration event", zap.Error(err))
+		}
+
+		ops(events.OpsMigrationApplied, entity.OpsInfo, "database schema migrated", map[string]string{
+			"schema_version":   strconv.Itoa(schemaVersion),
+			"previous_version": strconv.Itoa(previousVersion),
+		})
+	}
 
 	list := listener.Init(eventChan, logger, cfg, core)
+	list.SetConcurrency(cfg.Listener.Workers, cfg.Listener.Concurrency)
+	list.OnDeadLetter(core.PublishDeadLetter)
+
+	list.SetInstance(instance)
+
+	if cfg.Listener.LoadInterval > 0 {
+		jobs.Every("service-load", time.Duration(cfg.Listener.LoadInterval)*time.Second, func(context.Context) error {
+			load := list.Load()
+
+			depth, consumers, err := consumer.QueueDepth()
+			if err != nil {
+				return err
+			}
+
+			return core.PublishLoad(&entity.ServiceLoad{
+				Instance:    instance,
+				QueueDepth:  depth,
+				Consumers:   consumers,
+				Workers:     load.Workers,
+				BusyWorkers: load.Busy,
+				Deferred:    load.Deferred,
+				Handled:     load.Handled,
+				LatencyMs:   float64(load.AvgLatency) / float64(time.Millisecond),
+				Utilization: load.Utilization,
+				At:          time.Now(),
+			})
+		})
+	}
+
+	jobs.Every("forms-per-author", 15*time.Minute, func(context.Context) error {
+		return core.SampleFormsPerAuthor()
+	})
+
+	// Requests expired by the broker only show up in the dead letter queue
+	if cfg.Expiry.DeadLetterExchange != "" && cfg.Expiry.DeadLetterQueue != "" {
+		alerted := false
+
+		jobs.Every("dead-letters", time.Minute, func(context.Context) error {
+			depth, err := consumer.DeadLetterDepth()
+			if err != nil {
+				return err
+			}
+
+			metrics.DeadLetterDepth.Set(float64(depth))
+
+			// Alerted once per crossing, not on every check above it
+			threshold := cfg.Expiry.DeadLetterAlert
+			over := threshold > 0 && depth > threshold
+			if over && !alerted {
+				ops(events.OpsDeadLetters, entity.OpsWarning, "dead letter queue is over its threshold", map[string]string{
+					"queue":     cfg.Expiry.DeadLetterQueue,
+					"depth":     strconv.Itoa(depth),
+					"threshold": strconv.Itoa(threshold),
+				})
+			}
+			alerted = over
+
+			return nil
+		})
+	}
 
 	if err = consumer.Subscribe(cfg.Exchange.Request, "request.*", cfg.Queue.Request); err != nil {
 		logger.Error("error subscribe to queue", zap.Error(err))
 		return
 	}
 
+	// During a topology cutover the old queue is drained alongside the new one
+	if legacy := cfg.Topology.LegacyRequestQueue; legacy != "" {
+		exchange := cfg.Topology.LegacyRequestExchange
+		if exchange == "" {
+			exchange = cfg.Exchange.Request
+		}
+
+		if err = consumer.Subscribe(exchange, "request.*", legacy); err != nil {
+			logger.Error("error subscribe to legacy queue", zap.Error(err))
+			return
+		}
+	}
+
+	// Downstream consumers report on a control queue that they are alive
+	var heartbeats *heartbeat.Tracker
+	if control := cfg.Heartbeats.Queue; control != "" {
+		if err = consumer.Subscribe(cfg.Heartbeats.Exchange, cfg.Heartbeats.RoutingKey, control); err != nil {
+			logger.Error("error subscribe to heartbeat queue", zap.Error(err))
+			return
+		}
+
+		heartbeats = heartbeat.New(cfg.Heartbeats.Consumers, time.Duration(cfg.Heartbeats.MaxAge)*time.Second)
+		list.TrackHeartbeats(heartbeats)
+	}
+
+	// Events the publisher lost while reporting success never come back
+	if queue := cfg.Loopback.Queue; queue != "" {
+		verifier := loopback.New(loopback.Options{
+			Timeout:    time.Duration(cfg.Loopback.Timeout) * time.Second,
+			EventTypes: cfg.Loopback.EventTypes,
+		})
+
+		if err = consumer.ConsumeLoopback(queue+"."+instance, verifier.EventTypes()); err != nil {
+			logger.Error("error subscribe to loopback queue", zap.Error(err))
+			return
+		}
+
+		list.TrackLoopback(verifier)
+		publisher.Track(verifier)
+
+		jobs.Every("loopback", time.Duration(cfg.Loopback.Interval)*time.Second, func(context.Context) error {
+			var errs []error
+			for _, missing := range verifier.Missing() {
+				missing.Instance = instance
+
+				logger.Warn("published event did not come back",
+					zap.String("correlation_id", missing.CorrelationID),
+					zap.String("event_type", missing.EventType),
+					zap.Time("published_at", missing.PublishedAt))
+
+				errs = append(errs, core.PublishMissingEvent(&missing))
+			}
+			return errors.Join(errs...)
+		})
+	}
+
+	for _, b := range cfg.HeaderBindings {
+		if err = consumer.SubscribeHeaders(b.Exchange, b.Queue, b.Headers, b.Match != "any"); err != nil {
+			logger.Error("error subscribe to headers exchange",
+				zap.String("exchange", b.Exchange),
+				zap.String("queue", b.Queue),
+				zap.Error(err))
+			return
+		}
+	}
+
 	logger.Info("successsfully initialized", zap.String("app", "form-service"))
 
-	closers := closer.NewCloserGroup(logger, casher, list, consumer, publisher)
-	health := health.NewHealthChecker(logger, publisher, casher, consumer)
+	dog := watchdog.New(logger, watchdog.Options{
+		Policy:       watchdog.Policy(cfg.Watchdog.Policy),
+		RestartDelay: time.Duration(cfg.Watchdog.RestartDelay) * time.Second,
+		MaxIdle:      time.Duration(cfg.Watchdog.MaxIdle) * time.Second,
+	})
+
+	// Buffer events in Redis until handled, so a crash loses none
+	var buffer *stream.Buffer
+	if cfg.EventStream.Enabled {
+		buffer = stream.New(redisConn, logger, stream.Options{
+			Stream:    cfg.EventStream.Stream,
+			Group:     cfg.EventStream.Group,
+			Consumer:  instance,
+			MaxLen:    cfg.EventStream.MaxLen,
+			ClaimIdle: time.Duration(cfg.EventStream.ClaimIdle) * time.Second,
+		})
+		consumer.SetSink(buffer.Append)
+		list.OnHandled(buffer.Ack)
+		buffer.OnActivity(dog.Heartbeat("stream"))
+	}
+
+	list.OnActivity(dog.Heartbeat("listener"))
+	consumer.OnActivity(dog.Heartbeat("consumer"))
+
+	closers := closer.NewCloserGroup(logger)
+	closers.Add(closer.PhaseStopIntake, consumer)
+	if buffer != nil {
+		closers.Add(closer.PhaseStopIntake, buffer)
+	}
+	closers.Add(closer.PhaseDrainWorkers, list, jobs, closer.Func(core.CloseResponseBatching))
+	closers.Add(closer.PhaseFlushOutbox, coalescer)
+	closers.Add(closer.PhaseCloseConnections, publisher, casher)
+	if tel != nil {
+		closers.Add(closer.PhaseCloseConnections, tel)
+	}
+	closers.Add(closer.PhaseCloseLogger, closer.Func(logger.Sync))
+	healthOpts := health.ServerOptions{
+		Addr:         net.JoinHostPort(cfg.HealthCheck.Host, cfg.HealthCheck.Port),
+		Token:        cfg.HealthCheck.Token,
+		CertFile:     cfg.HealthCheck.CertFile,
+		KeyFile:      cfg.HealthCheck.KeyFile,
+		ClientCAFile: cfg.HealthCheck.ClientCAFile,
+	}
+
+	health := health.NewHealthChecker(logger, append(healthers, dog)...)
+	health.HandleProtected("/metrics", metrics.Handler())
+	health.Handle("/version", version.Handler(build))
+	health.HandleAdmin("/debug/config", config.Handler(cfg))
+	health.HandleAdmin("/admin/consumer/", consumer.AdminHandler("/admin/consumer/"))
+	if heartbeats != nil {
+		health.AddStatus("consumers", heartbeats)
+	}
 
-	go health.StartHealthCheckServer(":8080")
-	go list.Listen(context.Background())
-	go consumer.ConsumeMessages(eventChan)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Side effects still retried on shutdown give up instead of delaying it
+	core.SetContext(ctx)
+
+	go health.StartServer(healthOpts)
+	jobs.Start(ctx)
+
+	// Stop pulling events while the database can't serve them
+	if cfg.Throttle.Interval > 0 {
+		dbThrottle := throttle.New(logger, "database", repo, consumer, consumer.Queues(), throttle.Options{
+			Interval:          time.Duration(cfg.Throttle.Interval) * time.Second,
+			FailureThreshold:  cfg.Throttle.FailureThreshold,
+			RecoveryThreshold: cfg.Throttle.RecoveryThreshold,
+		})
+		go dbThrottle.Run(ctx)
+	}
+
+	dog.Go(ctx, "listener", list.Listen)
+	dog.Go(ctx, "consumer", func(context.Context) {
+		consumer.ConsumeMessages(eventChan)
+	})
+	if buffer != nil {
+		dog.Go(ctx, "stream", func(ctx context.Context) {
+			buffer.Run(ctx, eventChan)
+		})
+	}
 
 	logger.Info("service started")
 
 	<-signalChan
 	logger.Info("Shutting down...")
+	cancel()
 
 	if err = closers.Close(); err != nil {
 		logger.Error("error closed", zap.Error(err))
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type (
+	// CacheEntry describes what the cache holds for a form, to debug stale
+	// entries
+	CacheEntry struct {
+		FormID     string          `json:"form_id"`
+		Cached     bool            `json:"cached"`
+		Payload    json.RawMessage `json:"payload,omitempty"`     // Cached form as stored
+		Bytes      int             `json:"bytes"`                 // Size of the payload
+		TTLSeconds float64         `json:"ttl_seconds,omitempty"` // Remaining TTL, 0 for entries without expiry
+		Expires    bool            `json:"expires"`               // The entry has a TTL
+
+		Trashed bool `json:"trashed,omitempty"` // The cached form is in the trash, kept until purged
+	}
+
+	// CacheEntryReply is the reply to a cache inspection request
+	CacheEntryReply struct {
+		RequestID string      `json:"request_id"`
+		Entry     *CacheEntry `json:"entry"`
+	}
+)
+
+// NewCacheEntry describes the cached data of a form, nil when it isn't
+// cached, with its remaining TTL, negative for entries without expiry
+func NewCacheEntry(formID string, data []byte, ttl time.Duration) *CacheEntry {
+	entry := &CacheEntry{
+		FormID:  formID,
+		Cached:  data != nil,
+		Payload: data,
+		Bytes:   len(data),
+	}
+
+	var form Form
+	if entry.Cached && json.Unmarshal(data, &form) == nil {
+		entry.Trashed = form.Trashed()
+	}
+
+	if entry.Cached && ttl > 0 {
+		entry.Expires = true
+		entry.TTLSeconds = ttl.Seconds()
+	}
+
+	return entry
+}
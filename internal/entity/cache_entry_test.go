@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNewCacheEntry(t *testing.T) {
+	live, err := json.Marshal(&Form{ID: uuid.New()})
+	require.NoError(t, err)
+	trashed, err := json.Marshal(&Form{ID: uuid.New(), DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}})
+	require.NoError(t, err)
+
+	entry := NewCacheEntry("f", live, time.Minute)
+	assert.True(t, entry.Cached)
+	assert.True(t, entry.Expires)
+	assert.False(t, entry.Trashed)
+
+	entry = NewCacheEntry("f", trashed, -1)
+	assert.True(t, entry.Trashed, "trashed forms are reported as such")
+	assert.False(t, entry.Expires)
+
+	assert.False(t, NewCacheEntry("f", nil, 0).Cached)
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles of collaborators on a form
+const (
+	RoleEditor = "editor" // May change the form like its author
+	RoleViewer = "viewer" // May read the form only
+)
+
+// ErrInvalidRole is returned for unknown collaborator roles
+var ErrInvalidRole = errors.New("invalid collaborator role")
+
+type (
+	// Collaborator grants a user other than the author a role on a form
+	Collaborator struct {
+		FormID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"form_id"`
+		UserID    string    `gorm:"primaryKey;size:64" json:"user_id"`
+		Role      string    `gorm:"size:16" json:"role"` // One of the roles, e.g. RoleEditor
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// CollaboratorList is the reply to a collaborator listing request
+	CollaboratorList struct {
+		RequestID     string         `json:"request_id"`
+		FormID        string         `json:"form_id"`
+		Author        string         `json:"author"`
+		Collaborators []Collaborator `json:"collaborators"`
+	}
+)
+
+// ValidateRole checks that role is a known collaborator role
+func ValidateRole(role string) error {
+	switch role {
+	case RoleEditor, RoleViewer:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrInvalidRole, role)
+}
+
+// CanEdit reports whether the collaborator may change the form
+func (c *Collaborator) CanEdit() bool {
+	return c.Role == RoleEditor
+}
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeadLetter is a request a subscribed handler gave up on once its retries
+// ran out, published so it can be inspected and replayed to that handler
+type DeadLetter struct {
+	Handler       string          `json:"handler"` // Name the handler subscribed under
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Actor         string          `json:"actor,omitempty"`
+	Payload       json.RawMessage `json:"payload"`  // The request as received
+	Error         string          `json:"error"`    // Error of the last attempt
+	Attempts      int             `json:"attempts"` // Times the handler was called
+	FailedAt      time.Time       `json:"failed_at"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+type (
+	// PlannedEvent is an event a request would emit if it were executed
+	PlannedEvent struct {
+		Type    string `json:"type"`
+		Payload any    `json:"payload"`
+	}
+
+	// DryRunResult describes what a request would do without applying it
+	DryRunResult struct {
+		RequestID string         `json:"request_id"`        // ID of the simulated request event
+		Operation string         `json:"operation"`         // Request type that was simulated
+		Valid     bool           `json:"valid"`             // Whether the request would succeed
+		Error     string         `json:"error,omitempty"`   // Why the request would fail
+		Events    []PlannedEvent `json:"events"`            // Events that would be published
+		Cached    []string       `json:"cached,omitempty"`  // Cache keys that would be written
+		Evicted   []string       `json:"evicted,omitempty"` // Cache keys that would be removed
+		Result    any            `json:"result,omitempty"`  // Resulting state, e.g. the updated form
+
+		ErrorDetail // Code and localized messages of Error
+	}
+)
@@ -0,0 +1,8 @@
+package entity
+
+// ErrorDetail describes an error to clients by a stable code, see package
+// errcatalog, and its human-readable message in every available language
+type ErrorDetail struct {
+	Code     string            `json:"code,omitempty"`
+	Messages map[string]string `json:"messages,omitempty"` // Messages by language, e.g. "en"
+}
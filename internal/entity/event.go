@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -12,6 +14,58 @@ type Event struct {
 	Payload   []byte    `json:"payload"`
 	Type      string    `json:"type"`
 	Timestamp time.Time `json:"timestamp"`
+	DryRun    bool      `json:"dry_run,omitempty"`  // Simulate the request without writing
+	DedupID   string    `json:"dedup_id,omitempty"` // Same for every copy of the same change, see DedupID
+
+	// CorrelationID ties the event to the request flow it is part of. Requests
+	// without one are correlated by their ID.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// Actor is the user the request is made by. Changes to a form are only
+	// accepted from its author and editors. User requests without one are
+	// invalid; only the requests of respondents and other services need none.
+	Actor string `json:"actor,omitempty"`
+
+	// StreamID is the entry ID of events buffered in a Redis stream, see
+	// package stream. It is acknowledged once the event was handled.
+	StreamID string `json:"-"`
+
+	// Settle acknowledges the delivery of events consumed with manual
+	// acknowledgement once they were handled, or with requeue returns them
+	// to the broker; nil for other events
+	Settle func(requeue bool) `json:"-"`
+}
+
+// DedupSubject is implemented by payloads that know which entity they describe
+type DedupSubject interface {
+	DedupSubject() string
+}
+
+// DedupVersioned is implemented by payloads carrying the revision of the
+// entity they describe, which identifies its state in deduplication IDs
+type DedupVersioned interface {
+	DedupVersion() uint
+}
+
+// Attributed is implemented by payloads exposing attributes that are sent as
+// message headers, so consumers can bind to a headers exchange on them
+type Attributed interface {
+	Attributes() map[string]string
+}
+
+// Names of the attributes payloads expose
+const (
+	AttributeTenant     = "tenant"
+	AttributeFormStatus = "form_status"
+)
+
+// DedupID derives a deterministic deduplication ID from the entity an event
+// describes, the version of it the event carries and the operation. Retries of
+// the same change share it while their event IDs differ, so idempotent
+// consumers can discard the duplicates.
+func DedupID(subject, version, operation string) string {
+	sum := sha256.Sum256([]byte(subject + "\x00" + version + "\x00" + operation))
+	return hex.EncodeToString(sum[:16])
 }
 
 func NewEvent(Type string, payload []byte) *Event {
@@ -38,3 +92,12 @@ func (e *Event) Validate() error {
 
 	return nil
 }
+
+// Correlation returns the correlation ID of the event, its ID without one
+func (e *Event) Correlation() string {
+	if e.CorrelationID != "" {
+		return e.CorrelationID
+	}
+
+	return e.ID
+}
@@ -0,0 +1,300 @@
+// Package events lists the domain events the service publishes, the routing
+// key of each and the payload it carries. Publishing through New, instead of
+// a bare routing key, catches misspelled event types and payloads of the
+// wrong shape before they reach the broker.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/schemaregistry"
+	"github.com/Koyo-os/form-service/pkg/version"
+	"github.com/google/uuid"
+)
+
+// Type is the type of a domain event, used as its routing key
+type Type string
+
+// Events of forms
+const (
+	FormCreated       Type = "form.created"
+	FormUpdated       Type = "form.updated"
+	FormDeleted       Type = "form.deleted"
+	FormRestored      Type = "form.restored"
+	FormPurged        Type = "form.purged"
+	FormArchived      Type = "form.archived"
+	FormUnarchived    Type = "form.unarchived"
+	FormOpened        Type = "form.opened"
+	FormClosed        Type = "form.closed"
+	FormPublished     Type = "form.published"
+	FormVersion       Type = "form.version"
+	FormQuestionStats Type = "form.question.stats"
+	FormPublic        Type = "form.public"
+	FormList          Type = "form.list"
+	FormRead          Type = "form.read"
+	FormExported      Type = "form.exported"
+	FormMetadata      Type = "form.metadata"
+)
+
+// Events of collaborators
+const (
+	CollaboratorAdded   Type = "form.collaborator.added"
+	CollaboratorRemoved Type = "form.collaborator.removed"
+	CollaboratorList    Type = "form.collaborator.list"
+)
+
+// Events of webhooks
+const (
+	WebhookSaved   Type = "form.webhook.saved"
+	WebhookRemoved Type = "form.webhook.removed"
+)
+
+// Events of invites
+const (
+	InviteCreated Type = "form.invite.created"
+	InviteRevoked Type = "form.invite.revoked"
+	InviteList    Type = "form.invite.list"
+)
+
+// Events of responses
+const (
+	ResponseAccepted Type = "form.response.accepted"
+	ResponseRejected Type = "form.response.rejected"
+	ResponseStarted  Type = "form.response.started"
+	ResponseProgress Type = "form.response.progress"
+	ResponseCreated  Type = "response.created"
+	ResponseList     Type = "response.list"
+)
+
+// Events of uploads
+const (
+	UploadTicket Type = "upload.ticket"
+	UploadStored Type = "upload.stored"
+)
+
+// Events of tenants, authors and templates
+const (
+	TenantSettingsUpdated Type = "tenant.settings.updated"
+	AuthorQuota           Type = "author.quota"
+	AuthorQuotaUpdated    Type = "author.quota.updated"
+	TemplateCreated       Type = "template.created"
+)
+
+// Events of the service itself
+const (
+	DryRunResult     Type = "dry_run.result"
+	ServiceLoad      Type = "service.load"
+	ServiceVersion   Type = "service.version"
+	MigrationApplied Type = "migration.applied"
+	CacheEntry       Type = "cache.entry"
+	DeadLetter       Type = "dead_letter"
+	EventMissing     Type = "loopback.missing"
+	IntegrityIssue   Type = "integrity.violation"
+)
+
+// Operational events for the alerting pipeline, see entity.OpsEvent
+const (
+	OpsBrokerReconnected Type = "ops.broker.reconnected"
+	OpsDeadLetters       Type = "ops.dead_letter.threshold_exceeded"
+	OpsMigrationApplied  Type = "ops.migration.applied"
+	OpsMaintenance       Type = "ops.maintenance.toggled"
+)
+
+// Events of privacy requests
+const (
+	PrivacyReport Type = "privacy.report"
+)
+
+var (
+	// ErrUnknownType is returned for event types not listed in this package
+	ErrUnknownType = errors.New("unknown event type")
+
+	// ErrPayloadMismatch is returned for a payload the event type doesn't carry
+	ErrPayloadMismatch = errors.New("payload does not match event type")
+)
+
+type (
+	// FormDeletedPayload is the payload of form.deleted
+	FormDeletedPayload struct {
+		FormID string `json:"form_id"`
+	}
+
+	// FormPurgedPayload is the payload of form.purged
+	FormPurgedPayload struct {
+		FormID string `json:"form_id"`
+	}
+
+	// Event is a validated domain event ready to be published
+	Event struct {
+		Type    Type
+		Payload any
+	}
+
+	// Publisher sends a payload with a routing key within the context of
+	// an operation, like the service publisher and the local event bus
+	Publisher interface {
+		Publish(ctx context.Context, payload any, routingKey string) error
+	}
+)
+
+// payloads lists the payload types each event carries; pointers to them
+// are accepted as well
+var payloads = map[Type][]reflect.Type{
+	FormCreated:       {typeOf[entity.Form]()},
+	FormUpdated:       {typeOf[entity.Form](), typeOf[entity.FormUpdate]()},
+	FormDeleted:       {typeOf[FormDeletedPayload]()},
+	FormRestored:      {typeOf[entity.Form]()},
+	FormPurged:        {typeOf[FormPurgedPayload]()},
+	FormArchived:      {typeOf[entity.Form]()},
+	FormUnarchived:    {typeOf[entity.Form]()},
+	FormOpened:        {typeOf[entity.Form]()},
+	FormClosed:        {typeOf[entity.Form]()},
+	FormPublished:     {typeOf[entity.FormVersion]()},
+	FormVersion:       {typeOf[entity.FormVersionReply]()},
+	FormQuestionStats: {typeOf[entity.QuestionStatsReport]()},
+	FormPublic:        {typeOf[entity.PublicFormReply]()},
+	FormList:          {typeOf[entity.FormList]()},
+	FormRead:          {typeOf[entity.FormReply]()},
+	FormExported:      {typeOf[entity.FormExport]()},
+	FormMetadata:      {typeOf[entity.FormMetadataReply]()},
+
+	CollaboratorAdded:   {typeOf[entity.Collaborator]()},
+	CollaboratorRemoved: {typeOf[entity.Collaborator]()},
+	CollaboratorList:    {typeOf[entity.CollaboratorList]()},
+
+	WebhookSaved:   {typeOf[entity.Webhook]()},
+	WebhookRemoved: {typeOf[entity.Webhook]()},
+
+	InviteCreated: {typeOf[entity.OutputInvite]()},
+	InviteRevoked: {typeOf[entity.OutputInvite]()},
+	InviteList:    {typeOf[entity.InviteList]()},
+
+	ResponseAccepted: {typeOf[entity.ResponseSubmission]()},
+	ResponseRejected: {typeOf[entity.ResponseRejection]()},
+	ResponseStarted:  {typeOf[entity.ResponseProgress]()},
+	ResponseProgress: {typeOf[entity.ResponseProgress]()},
+	ResponseCreated:  {typeOf[entity.Response]()},
+	ResponseList:     {typeOf[entity.ResponseList]()},
+
+	UploadTicket: {typeOf[entity.UploadTicket]()},
+	UploadStored: {typeOf[entity.Upload]()},
+
+	TenantSettingsUpdated: {typeOf[entity.Settings]()},
+	AuthorQuota:           {typeOf[entity.OutputQuota]()},
+	AuthorQuotaUpdated:    {typeOf[entity.OutputQuota]()},
+	TemplateCreated:       {typeOf[entity.Template]()},
+
+	DryRunResult:     {typeOf[entity.DryRunResult]()},
+	ServiceLoad:      {typeOf[entity.ServiceLoad]()},
+	ServiceVersion:   {typeOf[version.Info]()},
+	MigrationApplied: {typeOf[entity.MigrationApplied]()},
+	CacheEntry:       {typeOf[entity.CacheEntryReply]()},
+	DeadLetter:       {typeOf[entity.DeadLetter]()},
+	EventMissing:     {typeOf[entity.MissingEvent]()},
+	IntegrityIssue:   {typeOf[entity.IntegrityViolation]()},
+
+	OpsBrokerReconnected: {typeOf[entity.OpsEvent]()},
+	OpsDeadLetters:       {typeOf[entity.OpsEvent]()},
+	OpsMigrationApplied:  {typeOf[entity.OpsEvent]()},
+	OpsMaintenance:       {typeOf[entity.OpsEvent]()},
+
+	PrivacyReport: {typeOf[entity.PrivacyReport]()},
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (t Type) String() string {
+	return string(t)
+}
+
+// Types lists every event type, sorted
+func Types() []Type {
+	types := make([]Type, 0, len(payloads))
+	for t := range payloads {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+
+	return types
+}
+
+// Payloads lists the payload types t carries, nil for unknown types
+func Payloads(t Type) []reflect.Type {
+	return slices.Clone(payloads[t])
+}
+
+// Schemas returns the JSON schema of the payloads of every event type, keyed
+// by event type, for the schema registry
+func Schemas() (map[string][]byte, error) {
+	schemas := make(map[string][]byte, len(payloads))
+	for t, types := range payloads {
+		schema, err := schemaregistry.Schema(types...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate schema of %s: %w", t, err)
+		}
+
+		schemas[t.String()] = schema
+	}
+
+	return schemas, nil
+}
+
+// Parse returns the event type named name, ErrUnknownType if there is none
+func Parse(name string) (Type, error) {
+	t := Type(name)
+	if _, ok := payloads[t]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownType, name)
+	}
+
+	return t, nil
+}
+
+// Validate checks that t is a known event type carrying payload
+func Validate(t Type, payload any) error {
+	allowed, ok := payloads[t]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownType, t)
+	}
+
+	given := reflect.TypeOf(payload)
+	if given != nil && given.Kind() == reflect.Pointer {
+		given = given.Elem()
+	}
+
+	if given == nil || !slices.Contains(allowed, given) {
+		return fmt.Errorf("%w: %s carries %s, got %v", ErrPayloadMismatch, t, allowed[0], given)
+	}
+
+	return nil
+}
+
+// New builds an event of type t after validating its payload
+func New(t Type, payload any) (Event, error) {
+	if err := Validate(t, payload); err != nil {
+		return Event{}, err
+	}
+
+	return Event{Type: t, Payload: payload}, nil
+}
+
+// NewFormDeleted builds the form.deleted event of a form
+func NewFormDeleted(formID uuid.UUID) Event {
+	return Event{Type: FormDeleted, Payload: &FormDeletedPayload{FormID: formID.String()}}
+}
+
+// NewFormPurged builds the form.purged event of a form
+func NewFormPurged(formID uuid.UUID) Event {
+	return Event{Type: FormPurged, Payload: &FormPurgedPayload{FormID: formID.String()}}
+}
+
+// PublishTo publishes the event through publisher within ctx
+func (e Event) PublishTo(ctx context.Context, publisher Publisher) error {
+	return publisher.Publish(ctx, e.Payload, e.Type.String())
+}
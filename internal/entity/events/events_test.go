@@ -0,0 +1,113 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	payload    any
+	routingKey string
+}
+
+func (r *recorder) Publish(_ context.Context, payload any, routingKey string) error {
+	r.payload, r.routingKey = payload, routingKey
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	t.Run("known type", func(t *testing.T) {
+		eventType, err := Parse("form.updated")
+
+		require.NoError(t, err)
+		assert.Equal(t, FormUpdated, eventType)
+	})
+
+	t.Run("misspelled type", func(t *testing.T) {
+		_, err := Parse("form.update")
+
+		assert.ErrorIs(t, err, ErrUnknownType)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("accepts values and pointers", func(t *testing.T) {
+		assert.NoError(t, Validate(FormCreated, entity.Form{}))
+		assert.NoError(t, Validate(FormCreated, &entity.Form{}))
+		assert.NoError(t, Validate(FormUpdated, &entity.FormUpdate{}))
+	})
+
+	t.Run("rejects another payload", func(t *testing.T) {
+		assert.ErrorIs(t, Validate(FormCreated, &entity.Response{}), ErrPayloadMismatch)
+		assert.ErrorIs(t, Validate(FormCreated, nil), ErrPayloadMismatch)
+	})
+
+	t.Run("rejects unknown types", func(t *testing.T) {
+		assert.ErrorIs(t, Validate(Type("form.create"), &entity.Form{}), ErrUnknownType)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("publishes under the routing key", func(t *testing.T) {
+		form := &entity.Form{ID: uuid.New()}
+
+		event, err := New(FormCreated, form)
+		require.NoError(t, err)
+
+		rec := &recorder{}
+		require.NoError(t, event.PublishTo(context.Background(), rec))
+		assert.Same(t, form, rec.payload)
+		assert.Equal(t, "form.created", rec.routingKey)
+	})
+
+	t.Run("fails on mismatched payload", func(t *testing.T) {
+		_, err := New(ResponseAccepted, &entity.Form{})
+
+		assert.ErrorIs(t, err, ErrPayloadMismatch)
+	})
+
+	t.Run("form deleted", func(t *testing.T) {
+		formID := uuid.New()
+
+		event := NewFormDeleted(formID)
+
+		assert.Equal(t, FormDeleted, event.Type)
+		assert.Equal(t, &FormDeletedPayload{FormID: formID.String()}, event.Payload)
+		assert.NoError(t, Validate(event.Type, event.Payload))
+	})
+
+	t.Run("form purged", func(t *testing.T) {
+		formID := uuid.New()
+
+		event := NewFormPurged(formID)
+
+		assert.Equal(t, FormPurged, event.Type)
+		assert.Equal(t, &FormPurgedPayload{FormID: formID.String()}, event.Payload)
+		assert.NoError(t, Validate(event.Type, event.Payload))
+	})
+}
+
+func TestTypes(t *testing.T) {
+	types := Types()
+
+	assert.Contains(t, types, FormCreated)
+	assert.Contains(t, types, MigrationApplied)
+	assert.IsIncreasing(t, types)
+}
+
+func TestSchemas(t *testing.T) {
+	schemas, err := Schemas()
+	require.NoError(t, err)
+
+	assert.Len(t, schemas, len(Types()))
+	for name, schema := range schemas {
+		assert.True(t, json.Valid(schema), name)
+	}
+	assert.Contains(t, string(schemas[FormDeleted.String()]), `"form_id"`)
+}
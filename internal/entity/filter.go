@@ -0,0 +1,148 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Operators of filter conditions
+const (
+	FilterEq       = "eq"       // Equal to the value
+	FilterNe       = "ne"       // Not equal to the value
+	FilterLt       = "lt"       // Less than the value
+	FilterLte      = "lte"      // Less than or equal to the value
+	FilterGt       = "gt"       // Greater than the value
+	FilterGte      = "gte"      // Greater than or equal to the value
+	FilterIn       = "in"       // Equal to one of the values, a JSON array
+	FilterContains = "contains" // Containing the value, a string
+)
+
+// Limits of filters, keeping the queries they translate to cheap
+const (
+	MaxFilterDepth      = 4   // Nesting levels of groups
+	MaxFilterConditions = 32  // Conditions in the whole filter
+	MaxFilterValues     = 100 // Values of an "in" condition
+)
+
+// ErrInvalidFilter is returned for malformed filters and conditions on
+// fields or with operators a listing doesn't allow
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// filterOps lists the known operators
+var filterOps = []string{FilterEq, FilterNe, FilterLt, FilterLte, FilterGt, FilterGte, FilterIn, FilterContains}
+
+// Filter narrows a listing down. It is either a condition, comparing Field
+// with Value by Op, e.g. {"field": "title", "op": "contains", "value": "hr"},
+// or a group matching when all of And or any of Or match.
+type Filter struct {
+	Field string          `json:"field,omitempty"`
+	Op    string          `json:"op,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"` // JSON value, an array for "in"
+
+	And []Filter `json:"and,omitempty"`
+	Or  []Filter `json:"or,omitempty"`
+}
+
+// IsGroup reports whether the filter is a group rather than a condition
+func (f *Filter) IsGroup() bool {
+	return f.And != nil || f.Or != nil
+}
+
+// Validate checks the structure of the filter: every part is either a
+// condition with a known operator and a value or a non-empty group, within
+// MaxFilterDepth and MaxFilterConditions. Whether its fields and operators
+// are allowed is up to the listing.
+func (f *Filter) Validate() error {
+	conditions := 0
+	return f.validate(1, &conditions)
+}
+
+func (f *Filter) validate(depth int, conditions *int) error {
+	if depth > MaxFilterDepth {
+		return fmt.Errorf("%w: nested deeper than %d levels", ErrInvalidFilter, MaxFilterDepth)
+	}
+
+	if !f.IsGroup() {
+		*conditions++
+		if *conditions > MaxFilterConditions {
+			return fmt.Errorf("%w: more than %d conditions", ErrInvalidFilter, MaxFilterConditions)
+		}
+
+		return f.validateCondition()
+	}
+
+	if f.Field != "" || f.Op != "" || f.Value != nil {
+		return fmt.Errorf("%w: a group can't be a condition as well", ErrInvalidFilter)
+	}
+
+	if f.And != nil && f.Or != nil {
+		return fmt.Errorf("%w: a group is either \"and\" or \"or\"", ErrInvalidFilter)
+	}
+
+	group := f.And
+	if f.Or != nil {
+		group = f.Or
+	}
+
+	if len(group) == 0 {
+		return fmt.Errorf("%w: empty group", ErrInvalidFilter)
+	}
+
+	for i := range group {
+		if err := group[i].validate(depth+1, conditions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *Filter) validateCondition() error {
+	if f.Field == "" {
+		return fmt.Errorf("%w: condition without a field", ErrInvalidFilter)
+	}
+
+	if !slices.Contains(filterOps, f.Op) {
+		return fmt.Errorf("%w: unknown operator %q of %s", ErrInvalidFilter, f.Op, f.Field)
+	}
+
+	value := bytes.TrimSpace(f.Value)
+	if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+		return fmt.Errorf("%w: condition on %s without a value", ErrInvalidFilter, f.Field)
+	}
+
+	if !json.Valid(value) {
+		return fmt.Errorf("%w: malformed value of %s", ErrInvalidFilter, f.Field)
+	}
+
+	if f.Op == FilterIn {
+		var values []json.RawMessage
+		if err := json.Unmarshal(value, &values); err != nil {
+			return fmt.Errorf("%w: %q of %s takes an array", ErrInvalidFilter, f.Op, f.Field)
+		}
+
+		if len(values) == 0 || len(values) > MaxFilterValues {
+			return fmt.Errorf("%w: %q of %s takes 1 to %d values", ErrInvalidFilter, f.Op, f.Field, MaxFilterValues)
+		}
+	}
+
+	return nil
+}
+
+// Key returns the filter as compact JSON, identifying it in cache keys.
+// Nil filters have an empty key; the filter must be valid.
+func (f *Filter) Key() string {
+	if f == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Validate(t *testing.T) {
+	eq := Filter{Field: "title", Op: FilterEq, Value: json.RawMessage(`"hr"`)}
+
+	nested := eq
+	for range MaxFilterDepth {
+		nested = Filter{And: []Filter{nested}}
+	}
+
+	many := make([]Filter, MaxFilterConditions+1)
+	for i := range many {
+		many[i] = eq
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		valid  bool
+	}{
+		{"condition", eq, true},
+		{"group", Filter{Or: []Filter{eq, {And: []Filter{eq, eq}}}}, true},
+		{"in", Filter{Field: "title", Op: FilterIn, Value: json.RawMessage(`["a", "b"]`)}, true},
+		{"unknown operator", Filter{Field: "title", Op: "like", Value: json.RawMessage(`"hr"`)}, false},
+		{"no field", Filter{Op: FilterEq, Value: json.RawMessage(`"hr"`)}, false},
+		{"no value", Filter{Field: "title", Op: FilterEq}, false},
+		{"null value", Filter{Field: "title", Op: FilterEq, Value: json.RawMessage(`null`)}, false},
+		{"in without an array", Filter{Field: "title", Op: FilterIn, Value: json.RawMessage(`"hr"`)}, false},
+		{"empty in", Filter{Field: "title", Op: FilterIn, Value: json.RawMessage(`[]`)}, false},
+		{"empty group", Filter{And: []Filter{}}, false},
+		{"and with or", Filter{And: []Filter{eq}, Or: []Filter{eq}}, false},
+		{"group with a condition", Filter{Field: "title", And: []Filter{eq}}, false},
+		{"too deep", nested, false},
+		{"too many conditions", Filter{Or: many}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidFilter)
+			}
+		})
+	}
+}
+
+func TestFilter_Key(t *testing.T) {
+	var none *Filter
+	assert.Empty(t, none.Key())
+
+	spaced := &Filter{Field: "title", Op: FilterIn, Value: json.RawMessage(`[ "a",  "b" ]`)}
+	compact := &Filter{Field: "title", Op: FilterIn, Value: json.RawMessage(`["a","b"]`)}
+	assert.Equal(t, compact.Key(), spaced.Key(), "keys don't depend on the spacing of values")
+}
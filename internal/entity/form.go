@@ -2,52 +2,200 @@
 package entity
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Ways questions are numbered for respondents
+const (
+	NumberingNone       = "none"        // Questions aren't numbered, the default
+	NumberingPerSection = "per_section" // Numbering restarts in every section
+	NumberingContinuous = "continuous"  // Questions are numbered through the whole form
+)
+
+// Formats descriptions and question texts are written in
+const (
+	FormatPlain    = "plain"    // Text shown as it is, the default
+	FormatMarkdown = "markdown" // Markdown, with embedded HTML limited like FormatHTML
+	FormatHTML     = "html"     // HTML limited to formatting and links, see package richtext
+)
+
+var (
+	// ErrInvalidNumbering is returned for unknown numbering modes
+	ErrInvalidNumbering = errors.New("invalid numbering")
+
+	// ErrInvalidFormat is returned for unknown text formats
+	ErrInvalidFormat = errors.New("invalid text format")
+
+	// ErrInvalidSchedule is returned for forms scheduled to close before they open
+	ErrInvalidSchedule = errors.New("invalid schedule")
+)
+
 type (
 	// Question represents a single question within a form
 	Question struct {
-		gorm.Model
-		FormID      uuid.UUID `gorm:"type:uuid"` // Reference to the parent form
-		Content     string    // The actual question text
-		OrderNumber uint      // Position of question in form
-		Form        Form      `gorm:"foreignKey:FormID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"` // Relation to parent form
+		ID           uuid.UUID       `gorm:"type:uuid;primaryKey"` // Unique identifier, assigned on creation when nil
+		FormID       uuid.UUID       `gorm:"type:uuid"`            // Reference to the parent form
+		Content      string          // The actual question text
+		Format       string          `gorm:"size:16"` // How Content and its translations are written, one of the Formats, empty for plain
+		OrderNumber  uint            // Position of question in form
+		Type         string          // Question type name, see package question; empty means text
+		Options      json.RawMessage `gorm:"type:json"`                                                      // Type-specific options
+		DefaultValue json.RawMessage `gorm:"type:json"`                                                      // Answer prefilled for respondents and taken when they skip the question, nil for none
+		SectionID    *uuid.UUID      `gorm:"type:uuid;index"`                                                // Section the question is grouped in, nil for none
+		MediaURL     string          `gorm:"size:2048"`                                                      // Image or video shown above the question, empty for none
+		MediaType    string          `gorm:"size:16"`                                                        // Kind of MediaURL, one of the Media kinds
+		Form         Form            `gorm:"foreignKey:FormID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"` // Relation to parent form
+
+		Rules *ValidationRules `gorm:"type:json"` // Answer validation rules, nil for none
+
+		Translations map[string]QuestionTranslation `gorm:"serializer:json"` // Content by locale, see Form.Localized
+
+		CreatedAt time.Time      // Creation timestamp
+		UpdatedAt time.Time      // Last change timestamp
+		DeletedAt gorm.DeletedAt `gorm:"index"` // When the question was deleted, null while live
 	}
 
 	// Form represents a questionnaire or survey form
 	Form struct {
 		ID          uuid.UUID  `gorm:"type:uuid;primaryKey"` // Unique identifier
 		Title       string     // Title of the form
+		Slug        *string    `gorm:"size:96;uniqueIndex"` // Unique name of the form in URLs, see Slugify; nil for forms created before slugs
 		Description string     // Form description or purpose
-		Closed      bool       // Whether form is closed for responses
+		Format      string     `gorm:"size:16"`                              // How Description and its translations are written, one of the Formats, empty for plain
+		Status      string     `gorm:"size:16;index;not null;default:draft"` // Lifecycle state, one of the Status states
+		Numbering   string     // How questions are numbered, one of the Numbering modes, empty for none
 		Questions   []Question `gorm:"foreignKey:FormID"` // Collection of form questions
+		Sections    []Section  `gorm:"foreignKey:FormID"` // Pages grouping the questions, in order
+		Branches    []Branch   `gorm:"foreignKey:FormID"` // Conditions showing or hiding questions
 		Author      string     // Creator of the form
+		TenantID    string     `gorm:"index;size:64"` // Tenant owning the form, empty for the default tenant
 		CreatedAt   time.Time  // Creation timestamp
+		UpdatedAt   time.Time  // Last change timestamp
+		Version     uint       `gorm:"not null;default:1"` // Revision, bumped with every write to the form or its questions, sections, logic and tags, see VersionConflictError
+
+		AllowedDomains []string `gorm:"serializer:json"` // Respondent email domains allowed to answer, empty allows any
+		InviteOnly     bool     // Whether answering requires an invite token
+
+		Tags []Tag `gorm:"many2many:form_tags;constraint:OnDelete:CASCADE"` // Categories the form is listed under
+
+		OpensAt  *time.Time `gorm:"index"` // When the form opens for responses, nil once opened or when not scheduled
+		ClosesAt *time.Time `gorm:"index"` // When the form closes for responses, nil once closed or when not scheduled
+
+		DeletedAt gorm.DeletedAt `gorm:"index"` // When the form was moved to the trash, null while live
+
+		DefaultLocale string                     `gorm:"size:35"`         // Language of the texts above, empty when unspecified
+		Translations  map[string]FormTranslation `gorm:"serializer:json"` // Title and description by locale, see Localized
+
+		Settings FormSettings `gorm:"embedded;embeddedPrefix:settings_"` // How the form behaves towards respondents
+		Theme    Theme        `gorm:"embedded;embeddedPrefix:theme_"`    // How the form looks to respondents
 	}
 
 	// OutputQuestion is a DTO for question data in API responses
 	OutputQuestion struct {
-		Content     string `json:"content"`      // Question text
-		OrderNumber uint   `json:"order_number"` // Question position
+		ID           string          `json:"id,omitempty"`            // Question identifier, empty before the question is stored
+		Content      string          `json:"content"`                 // Question text
+		Format       string          `json:"format,omitempty"`        // How the text is written
+		OrderNumber  uint            `json:"order_number"`            // Question position
+		Number       uint            `json:"number,omitempty"`        // Number shown to respondents, 0 when unnumbered
+		Type         string          `json:"type"`                    // Question type name
+		Options      json.RawMessage `json:"options,omitempty"`       // Type-specific options
+		DefaultValue json.RawMessage `json:"default_value,omitempty"` // Answer prefilled for respondents
+		MediaURL     string          `json:"media_url,omitempty"`     // Image or video shown above the question
+		MediaType    string          `json:"media_type,omitempty"`    // Kind of media, image or video
+
+		Rules *ValidationRules `json:"rules,omitempty"` // Answer validation rules
 	}
 
 	// OutputForm is a DTO for form data in API responses
 	OutputForm struct {
-		ID          string           `json:"id"`          // Form identifier
-		Closed      bool             `json:"closed"`      // Form status
-		Description string           `json:"description"` // Form description
-		Author      string           `json:"author"`      // Form creator
-		CreatedAt   string           `json:"created_at"`  // Creation time
-		Questions   []OutputQuestion `json:"questions"`   // Form questions
+		ID          string           `json:"id"`                  // Form identifier
+		Slug        string           `json:"slug,omitempty"`      // Unique name of the form in URLs
+		Status      string           `json:"status"`              // Lifecycle state
+		Numbering   string           `json:"numbering,omitempty"` // How questions are numbered
+		Description string           `json:"description"`         // Form description
+		Format      string           `json:"format,omitempty"`    // How the description is written
+		Author      string           `json:"author"`              // Form creator
+		TenantID    string           `json:"tenant_id,omitempty"` // Tenant owning the form
+		CreatedAt   string           `json:"created_at"`          // Creation time
+		Questions   []OutputQuestion `json:"questions"`           // Form questions outside of any section
+		Sections    []OutputSection  `json:"sections,omitempty"`  // Form sections with their questions
+		Branches    []OutputBranch   `json:"branches,omitempty"`  // Conditions showing or hiding questions
+		Tags        []string         `json:"tags,omitempty"`      // Categories the form is listed under
+
+		AllowedDomains []string `json:"allowed_domains,omitempty"` // Respondent email domains allowed to answer
+		InviteOnly     bool     `json:"invite_only"`               // Whether answering requires an invite token
+
+		OpensAt  *time.Time `json:"opens_at,omitempty"`  // When the form opens for responses
+		ClosesAt *time.Time `json:"closes_at,omitempty"` // When the form closes for responses
+
+		Settings FormSettings `json:"settings"`        // How the form behaves towards respondents
+		Theme    *Theme       `json:"theme,omitempty"` // How the form looks to respondents, nil for the renderer's defaults
+
+		Version uint `json:"version"` // Revision to make updates on
+	}
+
+	// FormChanges summarizes what saving a whole form changed
+	FormChanges struct {
+		Created  bool // Whether the form didn't exist before
+		Inserted int  // Questions added
+		Updated  int  // Questions whose content, position, type or options changed
+		Deleted  int  // Questions removed because the saved form no longer has them
 	}
 )
 
+// VersionConflictError is returned for updates made on a revision of a form
+// other than its current one, as when another editor changed it meanwhile
+type VersionConflictError struct {
+	FormID   uuid.UUID
+	Expected uint // Revision the update was made on
+	Actual   uint // Current revision of the form
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: form %s is at version %d, update made on version %d", e.FormID, e.Actual, e.Expected)
+}
+
+// DedupSubject identifies the form in deduplication IDs of its events
+func (f *Form) DedupSubject() string {
+	return f.ID.String()
+}
+
+// DedupVersion is the revision of the form in deduplication IDs of its
+// events, see Version
+func (f *Form) DedupVersion() uint {
+	return f.Version
+}
+
+// AcceptsResponses reports whether the form can be answered: it is
+// published
+func (f *Form) AcceptsResponses() bool {
+	return f.Status == StatusPublished
+}
+
+// Trashed reports whether the form was moved to the trash. Cached copies of
+// trashed forms carry the mark as well, so cache readers can tell them apart.
+func (f *Form) Trashed() bool {
+	return f.DeletedAt.Valid
+}
+
+// Attributes exposes the tenant and status of the form for header routing
+func (f *Form) Attributes() map[string]string {
+	attrs := map[string]string{AttributeFormStatus: f.Status}
+	if f.TenantID != "" {
+		attrs[AttributeTenant] = f.TenantID
+	}
+
+	return attrs
+}
+
 func (f *Form) Validate() error {
 	if f.ID == uuid.Nil {
 		return errors.New("form ID can not be nil")
@@ -56,40 +204,218 @@ func (f *Form) Validate() error {
 		return errors.New("author ID can not be nil")
 	}
 
+	if err := ValidateSchedule(f.OpensAt, f.ClosesAt); err != nil {
+		return err
+	}
+
+	if err := f.ValidateTranslations(); err != nil {
+		return err
+	}
+
+	if err := f.Settings.Validate(); err != nil {
+		return err
+	}
+
+	if err := ValidateFormat(f.Format); err != nil {
+		return err
+	}
+
+	if err := f.Theme.Validate(); err != nil {
+		return err
+	}
+
+	return ValidateNumbering(f.Numbering)
+}
+
+// ValidateSchedule checks that a form scheduled to both open and close
+// closes after it opens
+func ValidateSchedule(opensAt, closesAt *time.Time) error {
+	if opensAt != nil && closesAt != nil && !closesAt.After(*opensAt) {
+		return fmt.Errorf("%w: closes at %s, not after it opens at %s", ErrInvalidSchedule, closesAt, opensAt)
+	}
+
+	return nil
+}
+
+// ValidateNumbering checks that numbering is a known mode; empty is none
+func ValidateNumbering(numbering string) error {
+	switch numbering {
+	case "", NumberingNone, NumberingPerSection, NumberingContinuous:
+		return nil
+	}
+
+	return fmt.Errorf("%w: unknown mode %q", ErrInvalidNumbering, numbering)
+}
+
+// ValidateFormat checks that format is a known text format; empty is plain
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatPlain, FormatMarkdown, FormatHTML:
+		return nil
+	}
+
+	return fmt.Errorf("%w: unknown format %q", ErrInvalidFormat, format)
+}
+
+// BeforeCreate gives questions stored without an ID a new one, so every way
+// of adding questions, including along with their form, identifies them
+func (o *Question) BeforeCreate(*gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+
 	return nil
 }
 
 // ToOutput converts a Question entity to its DTO representation
 func (o *Question) ToOutput() OutputQuestion {
+	var id string
+	if o.ID != uuid.Nil {
+		id = o.ID.String()
+	}
+
 	return OutputQuestion{
-		Content:     o.Content,
-		OrderNumber: o.OrderNumber,
+		ID:           id,
+		Content:      o.Content,
+		Format:       o.Format,
+		OrderNumber:  o.OrderNumber,
+		Type:         o.Type,
+		Options:      o.Options,
+		DefaultValue: o.DefaultValue,
+		MediaURL:     o.MediaURL,
+		MediaType:    o.MediaType,
+		Rules:        o.Rules,
 	}
 }
 
 // ToOutput converts a Form entity to its DTO representation
 func (f *Form) ToOutput() OutputForm {
+	var tags []string
+	for _, tag := range f.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	var slug string
+	if f.Slug != nil {
+		slug = *f.Slug
+	}
+
+	var theme *Theme
+	if !f.Theme.IsZero() {
+		copied := f.Theme
+		theme = &copied
+	}
+
 	return OutputForm{
 		ID:          f.ID.String(),
+		Slug:        slug,
 		Description: f.Description,
+		Format:      f.Format,
 		Author:      f.Author,
+		TenantID:    f.TenantID,
 		CreatedAt:   f.CreatedAt.String(),
-		Closed:      f.Closed,
+		Status:      f.Status,
+		Numbering:   f.Numbering,
+		Tags:        tags,
+
+		AllowedDomains: f.AllowedDomains,
+		InviteOnly:     f.InviteOnly,
+
+		OpensAt:  f.OpensAt,
+		ClosesAt: f.ClosesAt,
+
+		Settings: f.Settings,
+		Theme:    theme,
+
+		Version: f.Version,
 	}
 }
 
-// ToJson converts a Form entity to its JSON representation
-// including all related questions
-func (f *Form) ToJson() ([]byte, error) {
+// ToFullOutput converts a Form entity to its DTO including all related
+// questions, nested under their sections
+func (f *Form) ToFullOutput() OutputForm {
 	form := f.ToOutput()
-	form.Questions = make([]OutputQuestion, len(f.Questions))
+	form.Questions = make([]OutputQuestion, 0, len(f.Questions))
+
+	ordered := slices.Clone(f.Sections)
+	slices.SortStableFunc(ordered, func(a, b Section) int {
+		return cmp.Compare(a.OrderNumber, b.OrderNumber)
+	})
 
-	// Convert each question to its DTO form
-	for i, fm := range f.Questions {
-		form.Questions[i] = fm.ToOutput()
+	sections := make(map[uuid.UUID]int, len(ordered))
+	for i := range ordered {
+		sections[ordered[i].ID] = i
+		form.Sections = append(form.Sections, ordered[i].ToOutput())
 	}
 
+	questions := slices.Clone(f.Questions)
+	slices.SortStableFunc(questions, func(a, b Question) int {
+		return cmp.Compare(a.OrderNumber, b.OrderNumber)
+	})
+
+	// Convert each question to its DTO form; questions of an unknown
+	// section are kept with the ungrouped ones
+	for _, fm := range questions {
+		if fm.SectionID != nil {
+			if i, ok := sections[*fm.SectionID]; ok {
+				form.Sections[i].Questions = append(form.Sections[i].Questions, fm.ToOutput())
+				continue
+			}
+		}
+
+		form.Questions = append(form.Questions, fm.ToOutput())
+	}
+
+	form.number()
+
+	if len(f.Branches) > 0 {
+		positions := make(map[uuid.UUID]uint, len(f.Questions))
+		for i := range f.Questions {
+			positions[f.Questions[i].ID] = f.Questions[i].OrderNumber
+		}
+
+		form.Branches = make([]OutputBranch, len(f.Branches))
+		for i := range f.Branches {
+			form.Branches[i] = f.Branches[i].ToOutput(positions)
+		}
+	}
+
+	return form
+}
+
+// ToJson converts a Form entity to its JSON representation
+// including all related questions, nested under their sections
+func (f *Form) ToJson() ([]byte, error) {
+	form := f.ToFullOutput()
+
 	// Marshal the complete form to JSON
 	formJson, err := json.Marshal(&form)
 	return formJson, err
 }
+
+// number numbers the questions in the order respondents see them: the
+// ungrouped questions first, then those of every section
+func (o *OutputForm) number() {
+	var next uint
+
+	numberAll := func(questions []OutputQuestion) {
+		for i := range questions {
+			next++
+			questions[i].Number = next
+		}
+	}
+
+	switch o.Numbering {
+	case NumberingContinuous:
+		numberAll(o.Questions)
+		for i := range o.Sections {
+			numberAll(o.Sections[i].Questions)
+		}
+	case NumberingPerSection:
+		numberAll(o.Questions)
+		for i := range o.Sections {
+			next = 0
+			numberAll(o.Sections[i].Questions)
+		}
+	}
+}
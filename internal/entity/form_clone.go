@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Clone returns a copy of the form owned by author, with new IDs for the
+// form, its sections and its questions, which its branches point at. The copy
+// keeps the settings of the form, its numbering, theme, respondent
+// restrictions, tags and translations, but not its lifecycle: it is a draft
+// and unscheduled.
+func (f *Form) Clone(author string) *Form {
+	clone := &Form{
+		ID:             uuid.New(),
+		Title:          f.Title,
+		Description:    f.Description,
+		Format:         f.Format,
+		Numbering:      f.Numbering,
+		Theme:          f.Theme,
+		Author:         author,
+		Status:         StatusDraft,
+		TenantID:       f.TenantID,
+		CreatedAt:      time.Now(),
+		AllowedDomains: slices.Clone(f.AllowedDomains),
+		InviteOnly:     f.InviteOnly,
+		Tags:           slices.Clone(f.Tags),
+		DefaultLocale:  f.DefaultLocale,
+		Translations:   maps.Clone(f.Translations),
+		Questions:      make([]Question, len(f.Questions)),
+		Sections:       make([]Section, len(f.Sections)),
+		Branches:       make([]Branch, len(f.Branches)),
+	}
+
+	sections := make(map[uuid.UUID]uuid.UUID, len(f.Sections))
+	for i, s := range f.Sections {
+		sections[s.ID] = uuid.New()
+		clone.Sections[i] = Section{
+			ID:          sections[s.ID],
+			FormID:      clone.ID,
+			Title:       s.Title,
+			Description: s.Description,
+			OrderNumber: s.OrderNumber,
+			CreatedAt:   clone.CreatedAt,
+		}
+	}
+
+	questions := make(map[uuid.UUID]uuid.UUID, len(f.Questions))
+	for i, q := range f.Questions {
+		questions[q.ID] = uuid.New()
+		question := Question{
+			ID:           questions[q.ID],
+			FormID:       clone.ID,
+			Content:      q.Content,
+			Format:       q.Format,
+			OrderNumber:  q.OrderNumber,
+			Type:         q.Type,
+			Options:      bytes.Clone(q.Options),
+			DefaultValue: bytes.Clone(q.DefaultValue),
+			MediaURL:     q.MediaURL,
+			MediaType:    q.MediaType,
+			Rules:        q.Rules.clone(),
+			Translations: maps.Clone(q.Translations),
+		}
+
+		if q.SectionID != nil {
+			if id, ok := sections[*q.SectionID]; ok {
+				question.SectionID = &id
+			}
+		}
+
+		clone.Questions[i] = question
+	}
+
+	for i, b := range f.Branches {
+		clone.Branches[i] = Branch{
+			FormID:   clone.ID,
+			SourceID: questions[b.SourceID],
+			Operator: b.Operator,
+			Value:    bytes.Clone(b.Value),
+			TargetID: questions[b.TargetID],
+			Action:   b.Action,
+		}
+	}
+
+	return clone
+}
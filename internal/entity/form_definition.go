@@ -0,0 +1,212 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// FormDefinitionVersion identifies the schema of portable form definitions
+const FormDefinitionVersion = "form-service/v1"
+
+type (
+	// FormDefinition is the portable representation of a form, as YAML or as
+	// an export, see ExportJSON and ExportCSV. It holds what an author
+	// controls and leaves out database state such as question IDs and
+	// timestamps, so a definition can be applied to any environment.
+	FormDefinition struct {
+		Version        string               `json:"version" yaml:"version"`
+		ID             string               `json:"id" yaml:"id"`
+		Title          string               `json:"title" yaml:"title"`
+		Description    string               `json:"description,omitempty" yaml:"description,omitempty"`
+		Format         string               `json:"format,omitempty" yaml:"format,omitempty"` // How the description is written
+		Author         string               `json:"author" yaml:"author"`
+		TenantID       string               `json:"tenant_id,omitempty" yaml:"tenant_id,omitempty"`
+		AllowedDomains []string             `json:"allowed_domains,omitempty" yaml:"allowed_domains,omitempty"`
+		InviteOnly     bool                 `json:"invite_only,omitempty" yaml:"invite_only,omitempty"`
+		Numbering      string               `json:"numbering,omitempty" yaml:"numbering,omitempty"`
+		Questions      []QuestionDefinition `json:"questions" yaml:"questions"`
+
+		DefaultLocale string                     `json:"default_locale,omitempty" yaml:"default_locale,omitempty"`
+		Translations  map[string]FormTranslation `json:"translations,omitempty" yaml:"translations,omitempty"` // Title and description by locale
+
+		Settings FormSettings `json:"settings,omitempty" yaml:"settings,omitempty"`
+		Theme    *Theme       `json:"theme,omitempty" yaml:"theme,omitempty"` // Nil for the renderer's defaults
+	}
+
+	// QuestionDefinition is a question of a FormDefinition. Its position in
+	// the list is its order number.
+	QuestionDefinition struct {
+		Content      string `json:"content" yaml:"content"`
+		Format       string `json:"format,omitempty" yaml:"format,omitempty"` // How the content is written
+		Type         string `json:"type,omitempty" yaml:"type,omitempty"`
+		Options      any    `json:"options,omitempty" yaml:"options,omitempty"`             // Type-specific options, see package question
+		DefaultValue any    `json:"default_value,omitempty" yaml:"default_value,omitempty"` // Answer prefilled for respondents
+		MediaURL     string `json:"media_url,omitempty" yaml:"media_url,omitempty"`         // Image or video shown above the question
+		MediaType    string `json:"media_type,omitempty" yaml:"media_type,omitempty"`       // Kind of media, image or video
+
+		Rules *ValidationRules `json:"rules,omitempty" yaml:"rules,omitempty"` // Answer validation rules
+
+		Translations map[string]QuestionTranslation `json:"translations,omitempty" yaml:"translations,omitempty"` // Content by locale
+	}
+)
+
+// ToDefinition converts a Form entity to its portable definition
+func (f *Form) ToDefinition() (*FormDefinition, error) {
+	def := &FormDefinition{
+		Version:        FormDefinitionVersion,
+		ID:             f.ID.String(),
+		Title:          f.Title,
+		Description:    f.Description,
+		Format:         f.Format,
+		Author:         f.Author,
+		TenantID:       f.TenantID,
+		AllowedDomains: f.AllowedDomains,
+		InviteOnly:     f.InviteOnly,
+		Numbering:      f.Numbering,
+		Questions:      make([]QuestionDefinition, len(f.Questions)),
+
+		DefaultLocale: f.DefaultLocale,
+		Translations:  f.Translations,
+
+		Settings: f.Settings,
+	}
+
+	if !f.Theme.IsZero() {
+		theme := f.Theme
+		def.Theme = &theme
+	}
+
+	for i, q := range f.Questions {
+		def.Questions[i] = QuestionDefinition{
+			Content:   q.Content,
+			Format:    q.Format,
+			Type:      q.Type,
+			MediaURL:  q.MediaURL,
+			MediaType: q.MediaType,
+			Rules:     q.Rules,
+
+			Translations: q.Translations,
+		}
+
+		if len(q.DefaultValue) > 0 {
+			if err := json.Unmarshal(q.DefaultValue, &def.Questions[i].DefaultValue); err != nil {
+				return nil, fmt.Errorf("question %d: invalid default value: %w", q.OrderNumber, err)
+			}
+		}
+
+		if len(q.Options) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(q.Options, &def.Questions[i].Options); err != nil {
+			return nil, fmt.Errorf("question %d: invalid options: %w", q.OrderNumber, err)
+		}
+	}
+
+	return def, nil
+}
+
+// ToYAML converts a Form entity to its portable YAML definition
+func (f *Form) ToYAML() ([]byte, error) {
+	def, err := f.ToDefinition()
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(def)
+}
+
+// ParseFormDefinition decodes a YAML form definition
+func ParseFormDefinition(data []byte) (*FormDefinition, error) {
+	def := new(FormDefinition)
+
+	if err := yaml.Unmarshal(data, def); err != nil {
+		return nil, fmt.Errorf("failed to decode form definition: %w", err)
+	}
+
+	if def.Version != FormDefinitionVersion {
+		return nil, fmt.Errorf("unsupported form definition version %q, want %q", def.Version, FormDefinitionVersion)
+	}
+
+	return def, nil
+}
+
+// ToForm converts the definition to a Form entity. Questions of existing,
+// the currently stored form if there is one, keep their IDs when they sit at
+// the same position, so applying a definition updates them in place instead
+// of replacing them.
+func (d *FormDefinition) ToForm(existing *Form) (*Form, error) {
+	id, err := uuid.Parse(d.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid form id: %w", err)
+	}
+
+	if d.Author == "" {
+		return nil, errors.New("author can not be empty")
+	}
+
+	form := &Form{
+		ID:             id,
+		Title:          d.Title,
+		Description:    d.Description,
+		Format:         d.Format,
+		Author:         d.Author,
+		TenantID:       d.TenantID,
+		AllowedDomains: d.AllowedDomains,
+		InviteOnly:     d.InviteOnly,
+		Numbering:      d.Numbering,
+		Questions:      make([]Question, len(d.Questions)),
+
+		DefaultLocale: d.DefaultLocale,
+		Translations:  d.Translations,
+
+		Settings: d.Settings,
+	}
+
+	if d.Theme != nil {
+		form.Theme = *d.Theme
+	}
+
+	ids := make(map[uint]uuid.UUID)
+	if existing != nil {
+		for _, q := range existing.Questions {
+			ids[q.OrderNumber] = q.ID
+		}
+	}
+
+	for i, q := range d.Questions {
+		question := Question{
+			FormID:      id,
+			Content:     q.Content,
+			Format:      q.Format,
+			OrderNumber: uint(i + 1),
+			Type:        q.Type,
+			MediaURL:    q.MediaURL,
+			MediaType:   q.MediaType,
+			Rules:       q.Rules,
+
+			Translations: q.Translations,
+		}
+		question.ID = ids[question.OrderNumber]
+
+		if q.Options != nil {
+			if question.Options, err = json.Marshal(q.Options); err != nil {
+				return nil, fmt.Errorf("question %d: invalid options: %w", question.OrderNumber, err)
+			}
+		}
+
+		if q.DefaultValue != nil {
+			if question.DefaultValue, err = json.Marshal(q.DefaultValue); err != nil {
+				return nil, fmt.Errorf("question %d: invalid default value: %w", question.OrderNumber, err)
+			}
+		}
+
+		form.Questions[i] = question
+	}
+
+	return form, nil
+}
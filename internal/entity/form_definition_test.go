@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormDefinition_RoundTrip(t *testing.T) {
+	form := &Form{
+		ID:             uuid.New(),
+		Title:          "Feedback",
+		Description:    "Tell us",
+		Author:         "author",
+		TenantID:       "acme",
+		AllowedDomains: []string{"example.com"},
+		InviteOnly:     true,
+		Questions: []Question{
+			{ID: questionID(7), Content: "Name?", OrderNumber: 1, Type: "text"},
+			{ID: questionID(8), Content: "Score?", OrderNumber: 2, Type: "number", Options: json.RawMessage(`{"max":5,"min":1}`)},
+			{ID: questionID(9), Content: "Pick", OrderNumber: 3, Type: "choice", Options: json.RawMessage(`{"choices":["a","b"],"multiple":true}`)},
+		},
+	}
+
+	data, err := form.ToYAML()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "created_at")
+
+	def, err := ParseFormDefinition(data)
+	require.NoError(t, err)
+
+	got, err := def.ToForm(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, form.ID, got.ID)
+	assert.Equal(t, form.Title, got.Title)
+	assert.Equal(t, form.AllowedDomains, got.AllowedDomains)
+	assert.True(t, got.InviteOnly)
+	require.Len(t, got.Questions, 3)
+
+	for i, q := range got.Questions {
+		assert.Zero(t, q.ID, "ids are not part of the definition")
+		assert.Equal(t, form.Questions[i].OrderNumber, q.OrderNumber)
+		assert.Equal(t, form.Questions[i].Type, q.Type)
+		if form.Questions[i].Options != nil {
+			assert.JSONEq(t, string(form.Questions[i].Options), string(q.Options))
+		}
+	}
+
+	again, err := got.ToYAML()
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+}
+
+func TestFormDefinition_ToForm(t *testing.T) {
+	id := uuid.New()
+
+	t.Run("keeps ids of questions at the same position", func(t *testing.T) {
+		def := &FormDefinition{Version: FormDefinitionVersion, ID: id.String(), Author: "author", Questions: []QuestionDefinition{
+			{Content: "first"},
+			{Content: "second"},
+		}}
+		existing := &Form{ID: id, Questions: []Question{{ID: questionID(4), OrderNumber: 1}}}
+
+		form, err := def.ToForm(existing)
+
+		require.NoError(t, err)
+		assert.Equal(t, questionID(4), form.Questions[0].ID)
+		assert.Zero(t, form.Questions[1].ID)
+	})
+
+	t.Run("rejects invalid definitions", func(t *testing.T) {
+		_, err := (&FormDefinition{ID: "nope", Author: "author"}).ToForm(nil)
+		assert.ErrorContains(t, err, "invalid form id")
+
+		_, err = (&FormDefinition{ID: id.String()}).ToForm(nil)
+		assert.ErrorContains(t, err, "author")
+
+		_, err = ParseFormDefinition([]byte("version: other/v9\n"))
+		assert.ErrorContains(t, err, "unsupported form definition version")
+	})
+}
@@ -0,0 +1,183 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// FieldChange is a change of one field between two versions of a form.
+	// Path names the field as it is encoded in the snapshot; question fields
+	// are keyed by question ID, e.g. Questions.<question ID>.Content, so
+	// changes apply regardless of reordering.
+	FieldChange struct {
+		Path string          `json:"path"`
+		Old  json.RawMessage `json:"old"` // null when the field or question was added
+		New  json.RawMessage `json:"new"` // null when the question was removed
+	}
+
+	// FormUpdate is the payload of form.updated while diffs are published:
+	// the full snapshot, encoded exactly as a form, plus the changes from the
+	// previous version. Light consumers apply the diff, heavy consumers keep
+	// reading the snapshot.
+	FormUpdate struct {
+		*Form
+		Diff []FieldChange `json:"diff"`
+
+		previous *Form // Version the diff starts from, kept to merge updates
+	}
+)
+
+// formQuestions is the snapshot field holding the questions
+const formQuestions = "Questions"
+
+// NewFormUpdate returns the update of previous to form
+func NewFormUpdate(previous, form *Form) (*FormUpdate, error) {
+	diff, err := DiffForms(previous, form)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FormUpdate{Form: form, Diff: diff, previous: previous}, nil
+}
+
+// Merge combines the update with a later one of the same form into a single
+// update from the version before u to the version after later, as
+// coalescing drops every update of a burst but the last. Payloads other
+// than updates replace u.
+func (u *FormUpdate) Merge(later any) any {
+	next, ok := later.(*FormUpdate)
+	if !ok {
+		return later
+	}
+
+	merged, err := NewFormUpdate(u.previous, next.Form)
+	if err != nil {
+		return later
+	}
+
+	return merged
+}
+
+// DiffForms computes the field-level changes from previous to next. Form
+// fields are listed in name order, followed by the questions in the order of
+// next and the questions next no longer has. Added and removed questions are
+// a single change each.
+func DiffForms(previous, next *Form) ([]FieldChange, error) {
+	before, err := encodeFields(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := encodeFields(next)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffFields("", before, after, formQuestions)
+
+	questions, err := diffQuestions(previous.Questions, next.Questions)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changes, questions...), nil
+}
+
+// diffQuestions matches questions by ID and diffs their fields
+func diffQuestions(previous, next []Question) ([]FieldChange, error) {
+	before := make(map[uuid.UUID]map[string]json.RawMessage, len(previous))
+	for i := range previous {
+		fields, err := encodeFields(&previous[i])
+		if err != nil {
+			return nil, err
+		}
+		before[previous[i].ID] = fields
+	}
+
+	var changes []FieldChange
+	seen := make(map[uuid.UUID]bool, len(next))
+
+	for i := range next {
+		q := &next[i]
+		path := fmt.Sprintf("%s.%s", formQuestions, q.ID)
+		seen[q.ID] = true
+
+		fields, err := encodeFields(q)
+		if err != nil {
+			return nil, err
+		}
+
+		old, ok := before[q.ID]
+		if !ok {
+			changes = append(changes, FieldChange{Path: path, New: encodeObject(fields)})
+			continue
+		}
+
+		// The parent form is a gorm relation, never loaded with the questions
+		changes = append(changes, diffFields(path+".", old, fields, "Form")...)
+	}
+
+	for i := range previous {
+		if !seen[previous[i].ID] {
+			path := fmt.Sprintf("%s.%s", formQuestions, previous[i].ID)
+			changes = append(changes, FieldChange{Path: path, Old: encodeObject(before[previous[i].ID])})
+		}
+	}
+
+	return changes, nil
+}
+
+// diffFields lists the fields differing between before and after, except
+// skip, in name order
+func diffFields(prefix string, before, after map[string]json.RawMessage, skip string) []FieldChange {
+	names := make([]string, 0, len(after))
+	for name := range after {
+		names = append(names, name)
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		if name == skip || bytes.Equal(before[name], after[name]) {
+			continue
+		}
+
+		changes = append(changes, FieldChange{Path: prefix + name, Old: before[name], New: after[name]})
+	}
+
+	return changes
+}
+
+// encodeFields encodes v and splits it into its top-level fields
+func encodeFields(v any) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %T: %w", v, err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode %T: %w", v, err)
+	}
+
+	return fields, nil
+}
+
+// encodeObject joins fields back into an object, leaving out the parent form
+// of questions
+func encodeObject(fields map[string]json.RawMessage) json.RawMessage {
+	delete(fields, "Form")
+
+	raw, _ := json.Marshal(fields) // Fields are valid JSON already
+	return raw
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffForms(t *testing.T) {
+	id := uuid.New()
+	previous := &Form{
+		ID:    id,
+		Title: "Feedback",
+		Questions: []Question{
+			{ID: questionID(7), Content: "Name?", OrderNumber: 1},
+			{ID: questionID(8), Content: "Score?", OrderNumber: 2},
+		},
+	}
+	next := &Form{
+		ID:    id,
+		Title: "Feedback",
+		Questions: []Question{
+			{ID: questionID(9), Content: "Email?", OrderNumber: 1},
+			{ID: questionID(7), Content: "Name?", OrderNumber: 2},
+		},
+		AllowedDomains: []string{"example.com"},
+	}
+
+	diff, err := DiffForms(previous, next)
+	require.NoError(t, err)
+
+	paths := make([]string, len(diff))
+	for i, change := range diff {
+		paths[i] = change.Path
+	}
+	assert.Equal(t, []string{
+		"AllowedDomains",
+		"Questions." + questionID(9).String(),
+		"Questions." + questionID(7).String() + ".OrderNumber",
+		"Questions." + questionID(8).String(),
+	}, paths)
+
+	assert.JSONEq(t, `null`, string(diff[0].Old))
+	assert.JSONEq(t, `["example.com"]`, string(diff[0].New))
+	assert.Contains(t, string(diff[1].New), `"Content":"Email?"`)
+	assert.NotContains(t, string(diff[1].New), `"Form"`)
+	assert.JSONEq(t, `1`, string(diff[2].Old))
+	assert.JSONEq(t, `2`, string(diff[2].New))
+	assert.Nil(t, diff[3].New)
+}
+
+func TestFormUpdate_Encoding(t *testing.T) {
+	previous := &Form{ID: uuid.New(), Title: "a"}
+	next := &Form{ID: previous.ID, Title: "b"}
+
+	update, err := NewFormUpdate(previous, next)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(update)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Form
+		Diff []FieldChange `json:"diff"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, "b", decoded.Title, "snapshot is encoded as a form")
+	assert.Equal(t, []FieldChange{{Path: "Title", Old: json.RawMessage(`"a"`), New: json.RawMessage(`"b"`)}}, decoded.Diff)
+	assert.Equal(t, next.DedupSubject(), update.DedupSubject())
+}
@@ -0,0 +1,185 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// Formats forms are exported in
+const (
+	ExportJSON = "json" // The definition as one JSON document
+	ExportCSV  = "csv"  // A kind,key,value row per form field and per question
+)
+
+// Kinds of rows of CSV exports
+const (
+	exportRowForm     = "form"
+	exportRowQuestion = "question"
+)
+
+var (
+	// ErrUnknownExportFormat is returned for export formats other than
+	// ExportJSON and ExportCSV
+	ErrUnknownExportFormat = errors.New("unknown export format")
+
+	// ErrInvalidExport is returned for documents that aren't form exports
+	ErrInvalidExport = errors.New("invalid form export")
+)
+
+// exportHeader is the header row of CSV exports
+var exportHeader = []string{"kind", "key", "value"}
+
+// FormExport is the reply to a form export request
+type FormExport struct {
+	RequestID string `json:"request_id"`
+	FormID    string `json:"form_id"`
+	Format    string `json:"format"`
+	Document  string `json:"document"` // The exported form, see FormDefinition.Export
+}
+
+// Export encodes the definition in format. JSON exports are the definition
+// as a JSON document. CSV exports hold a form row per top-level field,
+// keyed by its JSON name, then a question row per question, keyed by its
+// position; values are JSON, so nothing is lost in the conversion.
+func (d *FormDefinition) Export(format string) ([]byte, error) {
+	switch format {
+	case ExportJSON:
+		return json.MarshalIndent(d, "", "  ")
+	case ExportCSV:
+		return d.exportCSV()
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownExportFormat, format)
+}
+
+func (d *FormDefinition) exportCSV() ([]byte, error) {
+	form := *d
+	form.Questions = nil
+
+	data, err := json.Marshal(&form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode form: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode form: %w", err)
+	}
+	delete(fields, "questions")
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{exportHeader}
+	for _, key := range keys {
+		rows = append(rows, []string{exportRowForm, key, string(fields[key])})
+	}
+
+	for i := range d.Questions {
+		question, err := json.Marshal(&d.Questions[i])
+		if err != nil {
+			return nil, fmt.Errorf("question %d: failed to encode: %w", i+1, err)
+		}
+		rows = append(rows, []string{exportRowQuestion, strconv.Itoa(i + 1), string(question)})
+	}
+
+	if err = w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseFormExport decodes a form exported in format by
+// FormDefinition.Export
+func ParseFormExport(format string, data []byte) (*FormDefinition, error) {
+	def := new(FormDefinition)
+
+	switch format {
+	case ExportJSON:
+		if err := json.Unmarshal(data, def); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+		}
+	case ExportCSV:
+		if err := def.parseCSV(data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExportFormat, format)
+	}
+
+	if def.Version != FormDefinitionVersion {
+		return nil, fmt.Errorf("%w: unsupported form definition version %q, want %q", ErrInvalidExport, def.Version, FormDefinitionVersion)
+	}
+
+	return def, nil
+}
+
+func (d *FormDefinition) parseCSV(data []byte) error {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = len(exportHeader)
+
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	if !slices.Equal(header, exportHeader) {
+		return fmt.Errorf("header %v, want %v", header, exportHeader)
+	}
+
+	fields := make(map[string]json.RawMessage)
+	var questions []json.RawMessage
+
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		kind, key, value := row[0], row[1], json.RawMessage(row[2])
+		if !json.Valid(value) {
+			return fmt.Errorf("%s %s: value is not JSON", kind, key)
+		}
+
+		switch kind {
+		case exportRowForm:
+			if key == "questions" {
+				return errors.New("questions are listed in question rows")
+			}
+			fields[key] = value
+		case exportRowQuestion:
+			if key != strconv.Itoa(len(questions)+1) {
+				return fmt.Errorf("question %s out of order, want %d", key, len(questions)+1)
+			}
+			questions = append(questions, value)
+		default:
+			return fmt.Errorf("unknown row kind %q", kind)
+		}
+	}
+
+	if fields["questions"], err = json.Marshal(questions); err != nil {
+		return err
+	}
+
+	document, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(document, d)
+}
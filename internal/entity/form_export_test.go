@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormDefinition_Export(t *testing.T) {
+	form := &Form{
+		ID:         uuid.New(),
+		Title:      "Feedback, \"quoted\"",
+		Author:     "author",
+		InviteOnly: true,
+		Settings:   FormSettings{EditAfterSubmit: true, ConfirmationMessage: "Thanks!\nSee you"},
+		Questions: []Question{
+			{Content: "Name?", OrderNumber: 1, Type: "text", DefaultValue: json.RawMessage(`"anonymous"`)},
+			{Content: "Pick", OrderNumber: 2, Type: "choice", Options: json.RawMessage(`{"choices":["a","b"]}`)},
+		},
+	}
+
+	def, err := form.ToDefinition()
+	require.NoError(t, err)
+
+	for _, format := range []string{ExportJSON, ExportCSV} {
+		t.Run(format, func(t *testing.T) {
+			data, err := def.Export(format)
+			require.NoError(t, err)
+
+			got, err := ParseFormExport(format, data)
+			require.NoError(t, err)
+
+			imported, err := got.ToForm(nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, form.ID, imported.ID)
+			assert.Equal(t, form.Title, imported.Title)
+			assert.True(t, imported.InviteOnly)
+			assert.Equal(t, form.Settings, imported.Settings)
+			require.Len(t, imported.Questions, 2)
+			assert.JSONEq(t, `"anonymous"`, string(imported.Questions[0].DefaultValue))
+			assert.JSONEq(t, string(form.Questions[1].Options), string(imported.Questions[1].Options))
+		})
+	}
+
+	_, err = def.Export("xml")
+	assert.ErrorIs(t, err, ErrUnknownExportFormat)
+}
+
+func TestParseFormExport(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		data    string
+		wantErr error
+	}{
+		{name: "unknown format", format: "xml", wantErr: ErrUnknownExportFormat},
+		{name: "malformed json", format: ExportJSON, data: `{`, wantErr: ErrInvalidExport},
+		{name: "unknown version", format: ExportJSON, data: `{"version": "v0"}`, wantErr: ErrInvalidExport},
+		{name: "wrong header", format: ExportCSV, data: "a,b,c\n", wantErr: ErrInvalidExport},
+		{name: "value not json", format: ExportCSV, data: "kind,key,value\nform,title,Survey\n", wantErr: ErrInvalidExport},
+		{name: "questions out of order", format: ExportCSV, data: "kind,key,value\nquestion,2,{}\n", wantErr: ErrInvalidExport},
+		{name: "unknown row kind", format: ExportCSV, data: "kind,key,value\nsection,1,{}\n", wantErr: ErrInvalidExport},
+		{
+			name:   "csv",
+			format: ExportCSV,
+			data:   "kind,key,value\nform,version,\"\"\"form-service/v1\"\"\"\nquestion,1,\"{\"\"content\"\":\"\"Why?\"\"}\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, err := ParseFormExport(tt.format, []byte(tt.data))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []QuestionDefinition{{Content: "Why?"}}, def.Questions)
+		})
+	}
+}
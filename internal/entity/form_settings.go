@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxConfirmationMessageLength caps the characters of confirmation messages
+const MaxConfirmationMessageLength = 2000
+
+// ErrInvalidSettings is returned for form settings that can't be stored
+var ErrInvalidSettings = errors.New("invalid form settings")
+
+type (
+	// FormSettings holds how a form behaves towards its respondents
+	FormSettings struct {
+		AnonymousResponses  bool   `json:"anonymous_responses" yaml:"anonymous_responses,omitempty"`                              // Responses are stored without who gave them
+		EditAfterSubmit     bool   `json:"edit_after_submit" yaml:"edit_after_submit,omitempty"`                                  // Respondents may change their response once submitted
+		ConfirmationMessage string `gorm:"size:2000" json:"confirmation_message,omitempty" yaml:"confirmation_message,omitempty"` // Shown to respondents once their response is accepted
+	}
+
+	// FormSettingsUpdate changes some of the settings of a form; nil fields
+	// are left as they are
+	FormSettingsUpdate struct {
+		AnonymousResponses  *bool   `json:"anonymous_responses,omitempty"`
+		EditAfterSubmit     *bool   `json:"edit_after_submit,omitempty"`
+		ConfirmationMessage *string `json:"confirmation_message,omitempty"`
+	}
+)
+
+// Validate checks that the settings can be stored
+func (s *FormSettings) Validate() error {
+	if n := utf8.RuneCountInString(s.ConfirmationMessage); n > MaxConfirmationMessageLength {
+		return fmt.Errorf("%w: confirmation message of %d characters, at most %d", ErrInvalidSettings, n, MaxConfirmationMessageLength)
+	}
+
+	return nil
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u *FormSettingsUpdate) IsEmpty() bool {
+	return u.AnonymousResponses == nil && u.EditAfterSubmit == nil && u.ConfirmationMessage == nil
+}
+
+// Apply returns settings with the changes of the update
+func (u *FormSettingsUpdate) Apply(settings FormSettings) FormSettings {
+	if u.AnonymousResponses != nil {
+		settings.AnonymousResponses = *u.AnonymousResponses
+	}
+	if u.EditAfterSubmit != nil {
+		settings.EditAfterSubmit = *u.EditAfterSubmit
+	}
+	if u.ConfirmationMessage != nil {
+		settings.ConfirmationMessage = *u.ConfirmationMessage
+	}
+
+	return settings
+}
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FormStats counts how respondents use a form
+type FormStats struct {
+	FormID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"form_id"`
+	Views          int64      `gorm:"not null;default:0" json:"views"`       // Times the respondent view was served
+	Submissions    int64      `gorm:"not null;default:0" json:"submissions"` // Accepted responses
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`            // Last view or submission, nil for none
+}
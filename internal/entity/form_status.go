@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Lifecycle states of forms
+const (
+	StatusDraft     = "draft"     // Being written, not answerable yet; where every form starts
+	StatusPublished = "published" // Answerable by respondents
+	StatusClosed    = "closed"    // No longer answerable, but listed and editable
+	StatusArchived  = "archived"  // Kept for reference, left out of listings and not answerable
+)
+
+var (
+	// ErrInvalidStatus is returned for unknown form states
+	ErrInvalidStatus = errors.New("invalid form status")
+
+	// ErrInvalidTransition is returned for moves between form states the
+	// lifecycle doesn't allow, like back to draft
+	ErrInvalidTransition = errors.New("invalid form status transition")
+)
+
+// statusTransitions lists the states each state may move to. Forms never
+// go back to draft, and archived forms come back closed.
+var statusTransitions = map[string][]string{
+	StatusDraft:     {StatusPublished, StatusArchived},
+	StatusPublished: {StatusClosed, StatusArchived},
+	StatusClosed:    {StatusPublished, StatusArchived},
+	StatusArchived:  {StatusClosed},
+}
+
+// ValidateStatus checks that status is one of the form states
+func ValidateStatus(status string) error {
+	if _, ok := statusTransitions[status]; !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+
+	return nil
+}
+
+// ValidateTransition checks that a form may move from one state to another
+func ValidateTransition(from, to string) error {
+	if err := ValidateStatus(to); err != nil {
+		return err
+	}
+
+	if !slices.Contains(statusTransitions[from], to) {
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	return nil
+}
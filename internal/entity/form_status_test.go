@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     error
+	}{
+		{StatusDraft, StatusPublished, nil},
+		{StatusDraft, StatusArchived, nil},
+		{StatusPublished, StatusClosed, nil},
+		{StatusClosed, StatusPublished, nil},
+		{StatusClosed, StatusArchived, nil},
+		{StatusArchived, StatusClosed, nil},
+		{StatusPublished, StatusDraft, ErrInvalidTransition},
+		{StatusDraft, StatusClosed, ErrInvalidTransition},
+		{StatusArchived, StatusPublished, ErrInvalidTransition},
+		{StatusPublished, StatusPublished, ErrInvalidTransition},
+		{StatusPublished, "open", ErrInvalidStatus},
+		{"", StatusClosed, ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			err := ValidateTransition(tt.from, tt.to)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForm_ToJson(t *testing.T) {
+	about, work := uuid.New(), uuid.New()
+	unknown, intro := uuid.New(), uuid.New()
+
+	form := &Form{
+		ID:     uuid.New(),
+		Author: "author",
+		Sections: []Section{
+			{ID: work, Title: "Work", OrderNumber: 2},
+			{ID: about, Title: "About you", OrderNumber: 1},
+		},
+		Questions: []Question{
+			{ID: intro, Content: "Intro", OrderNumber: 1, MediaURL: "https://example.com/intro.png", MediaType: MediaImage},
+			{Content: "Name?", OrderNumber: 2, SectionID: &about},
+			{Content: "Team?", OrderNumber: 3, SectionID: &work},
+			{Content: "Role?", OrderNumber: 4, SectionID: &work},
+			{Content: "Orphan", OrderNumber: 5, SectionID: &unknown},
+		},
+	}
+
+	data, err := form.ToJson()
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"id": "`+form.ID.String()+`",
+		"status": "",
+		"description": "",
+		"author": "author",
+		"created_at": "`+form.CreatedAt.String()+`",
+		"invite_only": false,
+		"questions": [
+			{"id": "`+intro.String()+`", "content": "Intro", "order_number": 1, "type": "", "media_url": "https://example.com/intro.png", "media_type": "image"},
+			{"content": "Orphan", "order_number": 5, "type": ""}
+		],
+		"sections": [
+			{"id": "`+about.String()+`", "title": "About you", "description": "", "order_number": 1, "questions": [
+				{"content": "Name?", "order_number": 2, "type": ""}
+			]},
+			{"id": "`+work.String()+`", "title": "Work", "description": "", "order_number": 2, "questions": [
+				{"content": "Team?", "order_number": 3, "type": ""},
+				{"content": "Role?", "order_number": 4, "type": ""}
+			]}
+		],
+		"settings": {"anonymous_responses": false, "edit_after_submit": false},
+		"version": 0
+	}`, string(data))
+}
+
+func TestForm_ToJson_WithoutSections(t *testing.T) {
+	form := &Form{ID: uuid.New(), Author: "author", Questions: []Question{{Content: "Name?", OrderNumber: 1}}}
+
+	data, err := form.ToJson()
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), "sections")
+	assert.Contains(t, string(data), `"questions":[{"content":"Name?"`)
+}
+
+func TestForm_ToJson_Numbering(t *testing.T) {
+	about, work := uuid.New(), uuid.New()
+
+	form := &Form{
+		ID:     uuid.New(),
+		Author: "author",
+		Sections: []Section{
+			{ID: about, Title: "About you", OrderNumber: 1},
+			{ID: work, Title: "Work", OrderNumber: 2},
+		},
+		Questions: []Question{
+			{Content: "Role?", OrderNumber: 4, SectionID: &work},
+			{Content: "Intro", OrderNumber: 1},
+			{Content: "Team?", OrderNumber: 3, SectionID: &work},
+			{Content: "Name?", OrderNumber: 2, SectionID: &about},
+		},
+	}
+
+	numbers := func(t *testing.T) []uint {
+		data, err := form.ToJson()
+		require.NoError(t, err)
+
+		var output OutputForm
+		require.NoError(t, json.Unmarshal(data, &output))
+
+		var numbers []uint
+		for _, q := range output.Questions {
+			numbers = append(numbers, q.Number)
+		}
+		for _, s := range output.Sections {
+			for _, q := range s.Questions {
+				numbers = append(numbers, q.Number)
+			}
+		}
+		return numbers
+	}
+
+	tests := []struct {
+		numbering string
+		want      []uint
+	}{
+		{"", []uint{0, 0, 0, 0}},
+		{NumberingNone, []uint{0, 0, 0, 0}},
+		{NumberingContinuous, []uint{1, 2, 3, 4}},
+		{NumberingPerSection, []uint{1, 1, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run("numbering "+tt.numbering, func(t *testing.T) {
+			form.Numbering = tt.numbering
+			assert.Equal(t, tt.want, numbers(t))
+		})
+	}
+
+	t.Run("follows reorders", func(t *testing.T) {
+		form.Numbering = NumberingContinuous
+		form.Questions[2].OrderNumber, form.Questions[0].OrderNumber = 4, 3
+
+		data, err := form.ToJson()
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `{"content":"Role?","order_number":3,"number":3`)
+		assert.Contains(t, string(data), `{"content":"Team?","order_number":4,"number":4`)
+	})
+}
+
+func TestValidateNumbering(t *testing.T) {
+	for _, numbering := range []string{"", NumberingNone, NumberingPerSection, NumberingContinuous} {
+		assert.NoError(t, ValidateNumbering(numbering))
+	}
+
+	assert.ErrorIs(t, ValidateNumbering("roman"), ErrInvalidNumbering)
+	assert.ErrorIs(t, (&Form{ID: uuid.New(), Author: "author", Numbering: "roman"}).Validate(), ErrInvalidNumbering)
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{"", FormatPlain, FormatMarkdown, FormatHTML} {
+		assert.NoError(t, ValidateFormat(format))
+	}
+
+	assert.ErrorIs(t, ValidateFormat("rtf"), ErrInvalidFormat)
+	assert.ErrorIs(t, (&Form{ID: uuid.New(), Author: "author", Format: "rtf"}).Validate(), ErrInvalidFormat)
+}
+
+func TestValidateSchedule(t *testing.T) {
+	opensAt := time.Now()
+	closesAt := opensAt.Add(time.Hour)
+
+	assert.NoError(t, ValidateSchedule(nil, nil))
+	assert.NoError(t, ValidateSchedule(&opensAt, nil))
+	assert.NoError(t, ValidateSchedule(nil, &closesAt))
+	assert.NoError(t, ValidateSchedule(&opensAt, &closesAt))
+
+	assert.ErrorIs(t, ValidateSchedule(&closesAt, &opensAt), ErrInvalidSchedule)
+	assert.ErrorIs(t, ValidateSchedule(&opensAt, &opensAt), ErrInvalidSchedule)
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// FormVersion is an immutable snapshot of a form and its questions,
+	// created each time the form is published. Versions of a form are
+	// numbered from 1.
+	FormVersion struct {
+		ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+		FormID      uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_form_version" json:"form_id"`
+		Version     uint      `gorm:"uniqueIndex:idx_form_version" json:"version"`
+		Form        Form      `gorm:"serializer:json" json:"form"` // The form as published
+		PublishedAt time.Time `json:"published_at"`
+	}
+
+	// FormVersionReply is the reply to a form version request
+	FormVersionReply struct {
+		RequestID string       `json:"request_id"`
+		Version   *FormVersion `json:"version"`
+	}
+)
+
+// DedupSubject identifies the version in deduplication IDs of its events,
+// so republishing the same version is deduplicated
+func (v *FormVersion) DedupSubject() string {
+	return fmt.Sprintf("%s:%d", v.FormID, v.Version)
+}
+
+// DedupVersion is the number of the version, as it is never changed
+func (v *FormVersion) DedupVersion() uint {
+	return v.Version
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of integrity violations
+const (
+	IntegrityOrphanedQuestions = "orphaned_questions" // Questions of a form that is gone
+	IntegrityQuestionCount     = "question_count"     // A cached form has other questions than its row
+)
+
+// IntegrityViolation reports data found inconsistent by the integrity check
+type IntegrityViolation struct {
+	Kind        string      `json:"kind"`
+	FormID      string      `json:"form_id"`
+	QuestionIDs []uuid.UUID `json:"question_ids,omitempty"` // Orphaned questions
+	Stored      int         `json:"stored"`                 // Questions of the form in the database
+	Cached      int         `json:"cached,omitempty"`       // Questions of the cached form
+	Repaired    bool        `json:"repaired"`
+	DetectedAt  time.Time   `json:"detected_at"`
+}
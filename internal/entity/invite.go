@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// InviteToken lets a respondent answer an invite-only form. Only the
+	// SHA-256 hash of the token is stored; the token itself is handed out once.
+	InviteToken struct {
+		ID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+		FormID     uuid.UUID  `gorm:"type:uuid;index"`
+		TokenHash  string     `gorm:"uniqueIndex;size:64"` // Hex SHA-256 of the token
+		Email      string     // Respondent the token was issued for, empty for any
+		SingleUse  bool       // Whether the token is consumed by its first submission
+		CreatedAt  time.Time  // Creation timestamp
+		ExpiresAt  *time.Time // Token can't be used after this time, nil never expires
+		RevokedAt  *time.Time // Set once the token is revoked
+		RedeemedAt *time.Time // Set once a single-use token is redeemed
+	}
+
+	// OutputInvite is the DTO published when an invite is issued or revoked.
+	// Token is only set when the invite is issued.
+	OutputInvite struct {
+		ID         string     `json:"id"`
+		FormID     string     `json:"form_id"`
+		Email      string     `json:"email,omitempty"`
+		Token      string     `json:"token,omitempty"`
+		SingleUse  bool       `json:"single_use"`
+		CreatedAt  time.Time  `json:"created_at"`
+		ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+		RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+		RedeemedAt *time.Time `json:"redeemed_at,omitempty"`
+	}
+
+	// InviteList is the reply to an invite listing request
+	InviteList struct {
+		RequestID string         `json:"request_id"`
+		FormID    string         `json:"form_id"`
+		Invites   []OutputInvite `json:"invites"`
+	}
+)
+
+// IsRevoked reports whether the token was revoked
+func (i *InviteToken) IsRevoked() bool {
+	return i.RevokedAt != nil
+}
+
+// IsExpired reports whether the token expired at the given time
+func (i *InviteToken) IsExpired(at time.Time) bool {
+	return i.ExpiresAt != nil && !at.Before(*i.ExpiresAt)
+}
+
+// IsRedeemed reports whether a single-use token was already used
+func (i *InviteToken) IsRedeemed() bool {
+	return i.RedeemedAt != nil
+}
+
+// ToOutput converts an InviteToken to its DTO representation
+func (i *InviteToken) ToOutput() OutputInvite {
+	return OutputInvite{
+		ID:         i.ID.String(),
+		FormID:     i.FormID.String(),
+		Email:      i.Email,
+		SingleUse:  i.SingleUse,
+		CreatedAt:  i.CreatedAt,
+		ExpiresAt:  i.ExpiresAt,
+		RevokedAt:  i.RevokedAt,
+		RedeemedAt: i.RedeemedAt,
+	}
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// ServiceLoad is published periodically by every instance so an external
+// autoscaler can size the fleet and the worker pools
+type ServiceLoad struct {
+	Instance    string    `json:"instance"`
+	QueueDepth  int       `json:"queue_depth"`  // Requests ready in the request queues, shared by every instance
+	Consumers   int       `json:"consumers"`    // Consumers attached to the request queues
+	Workers     int       `json:"workers"`      // Size of the worker pool of the instance
+	BusyWorkers int       `json:"busy_workers"` // Workers handling a request when sampled
+	Deferred    int       `json:"deferred"`     // Requests waiting for their concurrency limit
+	Handled     int64     `json:"handled"`      // Requests handled since the previous event
+	LatencyMs   float64   `json:"latency_ms"`   // Mean handling time of those requests
+	Utilization float64   `json:"utilization"`  // Share of worker time spent handling requests, 0 to 1
+	At          time.Time `json:"at"`
+}
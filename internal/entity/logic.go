@@ -0,0 +1,259 @@
+package entity
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Actions of a branch on its target question
+const (
+	BranchShow = "show" // The target is hidden unless a show branch matches
+	BranchHide = "hide" // The target is hidden when a hide branch matches
+)
+
+// Operators testing the answer to the source question of a branch
+const (
+	BranchEquals      = "equals"       // The answer equals Value
+	BranchNotEquals   = "not_equals"   // The answer differs from Value, unanswered included
+	BranchContains    = "contains"     // The answer is a list holding Value
+	BranchAnswered    = "answered"     // The question was answered
+	BranchNotAnswered = "not_answered" // The question was left unanswered
+)
+
+// ErrInvalidLogic is returned for branches that can't be evaluated
+var ErrInvalidLogic = errors.New("invalid form logic")
+
+type (
+	// Branch shows or hides a question depending on the answer to another
+	// one. Questions are referenced by ID, so branches survive reordering.
+	Branch struct {
+		ID       uint            `gorm:"primaryKey" json:"id"`
+		FormID   uuid.UUID       `gorm:"type:uuid;index" json:"form_id"`
+		SourceID uuid.UUID       `gorm:"type:uuid" json:"source_id"` // Question whose answer is tested
+		Operator string          `json:"operator"`
+		Value    json.RawMessage `gorm:"type:json" json:"value,omitempty"` // Compared with the answer by equals, not_equals and contains
+		TargetID uuid.UUID       `gorm:"type:uuid" json:"target_id"`       // Question shown or hidden
+		Action   string          `json:"action"`
+	}
+
+	// OutputBranch is a DTO for branch data in API responses. Questions are
+	// referenced by position, like answers.
+	OutputBranch struct {
+		Source   uint            `json:"source"` // Position of the tested question
+		Operator string          `json:"operator"`
+		Value    json.RawMessage `json:"value,omitempty"`
+		Target   uint            `json:"target"` // Position of the shown or hidden question
+		Action   string          `json:"action"`
+	}
+)
+
+// ValidateBranches checks that branches only reference questions of the
+// form, use known operators and actions, and don't form a cycle, so the
+// visibility of every question can be decided
+func (f *Form) ValidateBranches(branches []Branch) error {
+	questions := make(map[uuid.UUID]bool, len(f.Questions))
+	for i := range f.Questions {
+		questions[f.Questions[i].ID] = true
+	}
+
+	for i := range branches {
+		b := &branches[i]
+
+		if !questions[b.SourceID] {
+			return fmt.Errorf("%w: source question %s is not in the form", ErrInvalidLogic, b.SourceID)
+		}
+		if !questions[b.TargetID] {
+			return fmt.Errorf("%w: target question %s is not in the form", ErrInvalidLogic, b.TargetID)
+		}
+		if b.SourceID == b.TargetID {
+			return fmt.Errorf("%w: question %s depends on itself", ErrInvalidLogic, b.SourceID)
+		}
+
+		if b.Action != BranchShow && b.Action != BranchHide {
+			return fmt.Errorf("%w: unknown action %q", ErrInvalidLogic, b.Action)
+		}
+
+		switch b.Operator {
+		case BranchEquals, BranchNotEquals, BranchContains:
+			if !json.Valid(b.Value) {
+				return fmt.Errorf("%w: operator %s needs a JSON value", ErrInvalidLogic, b.Operator)
+			}
+		case BranchAnswered, BranchNotAnswered:
+		default:
+			return fmt.Errorf("%w: unknown operator %q", ErrInvalidLogic, b.Operator)
+		}
+	}
+
+	if _, err := branchOrder(f.Questions, branches); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VisibleQuestions decides which questions are shown for the answers given
+// by question position. Hidden questions count as unanswered for the
+// branches depending on them. Branches referencing questions the form no
+// longer has are ignored.
+// Returns the positions of the visible questions
+func (f *Form) VisibleQuestions(answers map[uint]json.RawMessage) map[uint]bool {
+	visible := make(map[uint]bool, len(f.Questions))
+	for i := range f.Questions {
+		visible[f.Questions[i].OrderNumber] = true
+	}
+
+	if len(f.Branches) == 0 {
+		return visible
+	}
+
+	positions := make(map[uuid.UUID]uint, len(f.Questions))
+	for i := range f.Questions {
+		positions[f.Questions[i].ID] = f.Questions[i].OrderNumber
+	}
+
+	branches := slices.DeleteFunc(slices.Clone(f.Branches), func(b Branch) bool {
+		_, source := positions[b.SourceID]
+		_, target := positions[b.TargetID]
+		return !source || !target
+	})
+
+	order, err := branchOrder(f.Questions, branches)
+	if err != nil {
+		return visible // Stored logic is validated, a cycle can't be evaluated anyway
+	}
+
+	byTarget := make(map[uuid.UUID][]*Branch, len(branches))
+	for i := range branches {
+		byTarget[branches[i].TargetID] = append(byTarget[branches[i].TargetID], &branches[i])
+	}
+
+	for _, id := range order {
+		var shows, shown, hidden bool
+
+		for _, b := range byTarget[id] {
+			source := positions[b.SourceID]
+
+			var answer json.RawMessage
+			if visible[source] {
+				answer = answers[source]
+			}
+
+			matched := b.matches(answer)
+			switch b.Action {
+			case BranchShow:
+				shows = true
+				shown = shown || matched
+			case BranchHide:
+				hidden = hidden || matched
+			}
+		}
+
+		if hidden || (shows && !shown) {
+			visible[positions[id]] = false
+		}
+	}
+
+	return visible
+}
+
+// ToOutput converts a Branch entity to its DTO representation, given the
+// positions of the questions by ID
+func (b *Branch) ToOutput(positions map[uuid.UUID]uint) OutputBranch {
+	return OutputBranch{
+		Source:   positions[b.SourceID],
+		Operator: b.Operator,
+		Value:    b.Value,
+		Target:   positions[b.TargetID],
+		Action:   b.Action,
+	}
+}
+
+// matches tests answer, nil when unanswered, against the branch condition
+func (b *Branch) matches(answer json.RawMessage) bool {
+	var given any
+	answered := len(answer) > 0 && json.Unmarshal(answer, &given) == nil && given != nil
+
+	switch b.Operator {
+	case BranchAnswered:
+		return answered
+	case BranchNotAnswered:
+		return !answered
+	}
+
+	var want any
+	if json.Unmarshal(b.Value, &want) != nil {
+		return false
+	}
+
+	switch b.Operator {
+	case BranchEquals:
+		return answered && reflect.DeepEqual(given, want)
+	case BranchNotEquals:
+		return !answered || !reflect.DeepEqual(given, want)
+	case BranchContains:
+		list, ok := given.([]any)
+		return ok && slices.ContainsFunc(list, func(item any) bool {
+			return reflect.DeepEqual(item, want)
+		})
+	}
+
+	return false
+}
+
+// branchOrder sorts the questions so every question comes after the
+// questions its visibility depends on, in form order otherwise
+// Returns the question IDs in evaluation order, or ErrInvalidLogic on a cycle
+func branchOrder(questions []Question, branches []Branch) ([]uuid.UUID, error) {
+	ordered := slices.Clone(questions)
+	slices.SortStableFunc(ordered, func(a, b Question) int {
+		return cmp.Compare(a.OrderNumber, b.OrderNumber)
+	})
+
+	sources := make(map[uuid.UUID][]uuid.UUID, len(branches))
+	for i := range branches {
+		sources[branches[i].TargetID] = append(sources[branches[i].TargetID], branches[i].SourceID)
+	}
+
+	const (
+		visiting = iota + 1
+		done
+	)
+
+	state := make(map[uuid.UUID]int, len(ordered))
+	order := make([]uuid.UUID, 0, len(ordered))
+
+	var visit func(id uuid.UUID) error
+	visit = func(id uuid.UUID) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("%w: question %s depends on itself through other questions", ErrInvalidLogic, id)
+		case done:
+			return nil
+		}
+
+		state[id] = visiting
+		for _, source := range sources[id] {
+			if err := visit(source); err != nil {
+				return err
+			}
+		}
+		state[id] = done
+
+		order = append(order, id)
+		return nil
+	}
+
+	for i := range ordered {
+		if err := visit(ordered[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
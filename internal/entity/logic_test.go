@@ -0,0 +1,188 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// questionID is a fixed question ID, readable in failures by its last byte
+func questionID(n uint) uuid.UUID {
+	var id uuid.UUID
+	id[15] = byte(n)
+	return id
+}
+
+func logicForm(branches ...Branch) *Form {
+	form := &Form{Branches: branches}
+	for i := uint(1); i <= 4; i++ {
+		q := Question{Content: "Question", OrderNumber: i}
+		q.ID = questionID(10 + i)
+		form.Questions = append(form.Questions, q)
+	}
+
+	return form
+}
+
+func TestForm_ValidateBranches(t *testing.T) {
+	valid := Branch{SourceID: questionID(11), Operator: BranchEquals, Value: json.RawMessage(`"yes"`), TargetID: questionID(12), Action: BranchShow}
+
+	tests := []struct {
+		name     string
+		branches []Branch
+		wantErr  string
+	}{
+		{name: "valid", branches: []Branch{valid}},
+		{name: "no logic"},
+		{
+			name:     "unknown source",
+			branches: []Branch{{SourceID: questionID(99), Operator: BranchAnswered, TargetID: questionID(12), Action: BranchShow}},
+			wantErr:  "source question " + questionID(99).String(),
+		},
+		{
+			name:     "unknown target",
+			branches: []Branch{{SourceID: questionID(11), Operator: BranchAnswered, TargetID: questionID(99), Action: BranchShow}},
+			wantErr:  "target question " + questionID(99).String(),
+		},
+		{
+			name:     "self reference",
+			branches: []Branch{{SourceID: questionID(11), Operator: BranchAnswered, TargetID: questionID(11), Action: BranchHide}},
+			wantErr:  "depends on itself",
+		},
+		{
+			name: "cycle",
+			branches: []Branch{
+				{SourceID: questionID(11), Operator: BranchAnswered, TargetID: questionID(12), Action: BranchShow},
+				{SourceID: questionID(12), Operator: BranchAnswered, TargetID: questionID(13), Action: BranchShow},
+				{SourceID: questionID(13), Operator: BranchAnswered, TargetID: questionID(11), Action: BranchShow},
+			},
+			wantErr: "through other questions",
+		},
+		{
+			name:     "unknown operator",
+			branches: []Branch{{SourceID: questionID(11), Operator: "matches", TargetID: questionID(12), Action: BranchShow}},
+			wantErr:  "unknown operator",
+		},
+		{
+			name:     "missing value",
+			branches: []Branch{{SourceID: questionID(11), Operator: BranchEquals, TargetID: questionID(12), Action: BranchShow}},
+			wantErr:  "needs a JSON value",
+		},
+		{
+			name:     "unknown action",
+			branches: []Branch{{SourceID: questionID(11), Operator: BranchAnswered, TargetID: questionID(12), Action: "skip"}},
+			wantErr:  "unknown action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := logicForm().ValidateBranches(tt.branches)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidLogic)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestForm_VisibleQuestions(t *testing.T) {
+	form := logicForm(
+		// 2 is shown when 1 is "yes", 3 is hidden when 2 holds "b" and 4 is
+		// shown once 3 is answered
+		Branch{SourceID: questionID(11), Operator: BranchEquals, Value: json.RawMessage(`"yes"`), TargetID: questionID(12), Action: BranchShow},
+		Branch{SourceID: questionID(12), Operator: BranchContains, Value: json.RawMessage(`"b"`), TargetID: questionID(13), Action: BranchHide},
+		Branch{SourceID: questionID(13), Operator: BranchAnswered, TargetID: questionID(14), Action: BranchShow},
+		Branch{SourceID: questionID(99), Operator: BranchAnswered, TargetID: questionID(11), Action: BranchShow}, // Dangling, ignored
+	)
+
+	tests := []struct {
+		name    string
+		answers map[uint]json.RawMessage
+		want    map[uint]bool
+	}{
+		{
+			name: "nothing answered",
+			want: map[uint]bool{1: true, 2: false, 3: true, 4: false},
+		},
+		{
+			name:    "every question shown",
+			answers: map[uint]json.RawMessage{1: json.RawMessage(`"yes"`), 2: json.RawMessage(`["a"]`), 3: json.RawMessage(`"text"`)},
+			want:    map[uint]bool{1: true, 2: true, 3: true, 4: true},
+		},
+		{
+			name:    "hiding cascades",
+			answers: map[uint]json.RawMessage{1: json.RawMessage(`"yes"`), 2: json.RawMessage(`["a","b"]`), 3: json.RawMessage(`"text"`)},
+			want:    map[uint]bool{1: true, 2: true, 3: false, 4: false},
+		},
+		{
+			name:    "answers to hidden questions don't count",
+			answers: map[uint]json.RawMessage{1: json.RawMessage(`"no"`), 2: json.RawMessage(`["b"]`)},
+			want:    map[uint]bool{1: true, 2: false, 3: true, 4: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, form.VisibleQuestions(tt.answers))
+		})
+	}
+}
+
+func TestForm_WithDefaults(t *testing.T) {
+	// 2 is shown when 1 is "yes"
+	form := logicForm(
+		Branch{SourceID: questionID(11), Operator: BranchEquals, Value: json.RawMessage(`"yes"`), TargetID: questionID(12), Action: BranchShow},
+	)
+	form.Questions[0].DefaultValue = json.RawMessage(`"yes"`)
+	form.Questions[1].DefaultValue = json.RawMessage(`"b"`)
+	form.Questions[3].DefaultValue = json.RawMessage(`null`)
+
+	tests := []struct {
+		name    string
+		answers []Answer
+		want    []Answer
+	}{
+		{
+			name: "nothing answered",
+			want: []Answer{
+				{OrderNumber: 1, Value: json.RawMessage(`"yes"`)},
+				{OrderNumber: 2, Value: json.RawMessage(`"b"`)},
+			},
+		},
+		{
+			name: "answers are kept",
+			answers: []Answer{
+				{OrderNumber: 1, Value: json.RawMessage(`"no"`)},
+				{OrderNumber: 3, Value: json.RawMessage(`null`)},
+			},
+			want: []Answer{
+				{OrderNumber: 1, Value: json.RawMessage(`"no"`)},
+				{OrderNumber: 3, Value: json.RawMessage(`null`)}, // No default
+			},
+		},
+		{
+			name: "null answers are skipped",
+			answers: []Answer{
+				{OrderNumber: 1, Value: json.RawMessage(`"yes"`)},
+				{OrderNumber: 2, Value: json.RawMessage(`null`)},
+			},
+			want: []Answer{
+				{OrderNumber: 1, Value: json.RawMessage(`"yes"`)},
+				{OrderNumber: 2, Value: json.RawMessage(`"b"`)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, form.WithDefaults(tt.answers))
+		})
+	}
+}
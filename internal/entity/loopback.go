@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// MissingEvent is published when an event the service published didn't come
+// back on its loopback queue in time, so the publisher may have lost it
+type MissingEvent struct {
+	Instance      string    `json:"instance"`
+	CorrelationID string    `json:"correlation_id"`
+	EventType     string    `json:"event_type"`
+	PublishedAt   time.Time `json:"published_at"`
+	DetectedAt    time.Time `json:"detected_at"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "errors"
+
+// Kinds of media shown above a question
+const (
+	MediaImage = "image"
+	MediaVideo = "video"
+)
+
+// ErrInvalidMedia is returned for question media with an unknown type or a
+// URL that isn't an absolute web address
+var ErrInvalidMedia = errors.New("invalid media")
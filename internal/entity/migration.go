@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// MigrationApplied is published when an instance moves the database schema
+// to a new version, so the rest of a fleet can coordinate a rolling upgrade
+type MigrationApplied struct {
+	SchemaVersion   int       `json:"schema_version"`   // Version now recorded in the database
+	PreviousVersion int       `json:"previous_version"` // Version before the migration, 0 for a fresh database
+	AppliedAt       time.Time `json:"applied_at"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Severities of operational events
+const (
+	OpsInfo    = "info"    // Worth knowing, e.g. a migration was applied
+	OpsWarning = "warning" // May need someone to act, e.g. dead letters pile up
+)
+
+// OpsEvent is a notable operational occurrence, published for the alerting
+// pipeline so it can react without scraping logs. Alerts route on the event
+// type and the severity.
+type OpsEvent struct {
+	Instance   string            `json:"instance"`          // Instance the occurrence was observed on
+	Severity   string            `json:"severity"`          // OpsInfo or OpsWarning
+	Summary    string            `json:"summary"`           // What happened, for humans
+	Details    map[string]string `json:"details,omitempty"` // Specifics to route or group alerts on, e.g. the queue
+	OccurredAt time.Time         `json:"occurred_at"`
+}
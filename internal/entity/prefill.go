@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// HasDefault reports whether the question has a default value, a JSON null
+// counting as none
+func (q *Question) HasDefault() bool {
+	return len(q.DefaultValue) > 0 && !bytes.Equal(bytes.TrimSpace(q.DefaultValue), []byte("null"))
+}
+
+// WithDefaults returns answers with the default values of the questions
+// they skip, a null or empty answer counting as skipped. Defaults only
+// answer questions the form logic shows once they are given; skipped
+// answers they replace are dropped, every other answer is kept as it is.
+func (f *Form) WithDefaults(answers []Answer) []Answer {
+	given := make(map[uint]json.RawMessage, len(answers))
+	for _, answer := range answers {
+		if _, ok := given[answer.OrderNumber]; !ok {
+			given[answer.OrderNumber] = answer.Value
+		}
+	}
+
+	skipped := func(value json.RawMessage) bool {
+		return len(value) == 0 || bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+	}
+
+	filled := make(map[uint]json.RawMessage, len(f.Questions))
+	for orderNumber, value := range given {
+		filled[orderNumber] = value
+	}
+
+	defaulted := make([]*Question, 0)
+	for i := range f.Questions {
+		q := &f.Questions[i]
+		if q.HasDefault() && skipped(given[q.OrderNumber]) {
+			filled[q.OrderNumber] = q.DefaultValue
+			defaulted = append(defaulted, q)
+		}
+	}
+
+	if len(defaulted) == 0 {
+		return answers
+	}
+
+	visible := f.VisibleQuestions(filled)
+
+	replaced := make(map[uint]bool, len(defaulted))
+	for _, q := range defaulted {
+		replaced[q.OrderNumber] = visible[q.OrderNumber]
+	}
+
+	result := make([]Answer, 0, len(answers)+len(defaulted))
+	for _, answer := range answers {
+		if !replaced[answer.OrderNumber] {
+			result = append(result, answer)
+		}
+	}
+
+	for _, q := range defaulted {
+		if replaced[q.OrderNumber] {
+			result = append(result, Answer{OrderNumber: q.OrderNumber, Value: q.DefaultValue})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Operations of privacy requests
+const (
+	PrivacyExport = "export"
+	PrivacyErase  = "erase"
+)
+
+type (
+	// RespondentData is everything stored about a respondent, as exported
+	RespondentData struct {
+		Responses []Response     `json:"responses"`
+		Invites   []OutputInvite `json:"invites"` // Invites issued for the respondent's email
+	}
+
+	// PrivacyReport is published once a privacy request of a respondent is
+	// completed. Signature lets the requester check it was issued by the
+	// service, see Sign.
+	PrivacyReport struct {
+		RequestID   string          `json:"request_id"`
+		Operation   string          `json:"operation"`      // PrivacyExport or PrivacyErase
+		Respondent  string          `json:"respondent"`     // Respondent ID or email the request was made for
+		Forms       []string        `json:"forms"`          // Forms the respondent has data in, sorted
+		Responses   int             `json:"responses"`      // Responses exported or erased
+		Invites     int             `json:"invites"`        // Invites exported or erased
+		Data        *RespondentData `json:"data,omitempty"` // Exported data, nil for erasures
+		CompletedAt time.Time       `json:"completed_at"`
+		Signature   string          `json:"signature"` // Hex HMAC-SHA256 of the report without its signature
+	}
+)
+
+// Sign sets the signature of the report, the HMAC-SHA256 with key of its
+// JSON encoding while the signature is empty
+func (r *PrivacyReport) Sign(key []byte) error {
+	sum, err := r.sum(key)
+	if err != nil {
+		return err
+	}
+
+	r.Signature = hex.EncodeToString(sum)
+
+	return nil
+}
+
+// Verify reports whether the report was signed with key and is unchanged
+// since
+func (r *PrivacyReport) Verify(key []byte) bool {
+	signature, err := hex.DecodeString(r.Signature)
+	if err != nil {
+		return false
+	}
+
+	sum, err := r.sum(key)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(signature, sum)
+}
+
+func (r *PrivacyReport) sum(key []byte) ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = ""
+
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// ProcessedEvent records a request event whose changes were committed, in
+// the same transaction, so a redelivery of the event isn't applied twice
+type ProcessedEvent struct {
+	EventID     string    `gorm:"primaryKey;size:64"`
+	Type        string    `gorm:"size:128"` // Request type of the event
+	ProcessedAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// ResponseProgress reports how far a respondent got in a form. Only the
+// position is recorded, never the answers themselves.
+type ResponseProgress struct {
+	FormID        string    `json:"form_id"`
+	RespondentID  string    `json:"respondent_id"`
+	QuestionIndex uint      `json:"question_index"` // Index of the last question in the saved draft
+	QuestionCount int       `json:"question_count"` // Questions in the form, for drop-off ratios
+	At            time.Time `json:"at"`             // When the draft was saved
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// PublicForm is the respondent-facing projection of a form: its latest
+	// published version, kept only while the form is open and already
+	// rendered for clients. Respondents read it instead of the form the
+	// author edits.
+	PublicForm struct {
+		FormID      uuid.UUID       `gorm:"type:uuid;primaryKey" json:"form_id"`
+		Version     uint            `json:"version"`                  // Published version projected
+		Form        Form            `gorm:"serializer:json" json:"-"` // The published form answers are validated against
+		Document    json.RawMessage `gorm:"type:json" json:"form"`    // Form rendered as OutputForm
+		PublishedAt time.Time       `json:"published_at"`
+		UpdatedAt   time.Time       `json:"updated_at"`
+	}
+
+	// PublicFormReply is the reply to a respondent form request, Form is
+	// nil when the form isn't published or closed
+	PublicFormReply struct {
+		RequestID string      `json:"request_id"`
+		Form      *PublicForm `json:"form"`
+	}
+
+	// FormMetadata is what link previews and embeds show of a form. It is
+	// public, so it leaves out the questions and who owns the form.
+	FormMetadata struct {
+		FormID        uuid.UUID `json:"form_id"`
+		Slug          string    `json:"slug"`
+		Title         string    `json:"title"`
+		Description   string    `json:"description,omitempty"`
+		Format        string    `json:"format,omitempty"` // Text format of the description
+		Open          bool      `json:"open"`             // Whether the form accepts responses
+		QuestionCount int       `json:"question_count"`
+	}
+
+	// FormMetadataReply is the reply to a metadata request, Metadata is nil
+	// when no published form has the slug
+	FormMetadataReply struct {
+		RequestID string        `json:"request_id"`
+		Slug      string        `json:"slug"`
+		Metadata  *FormMetadata `json:"metadata"`
+	}
+)
+
+// NewPublicForm projects a published version. The access settings of live,
+// the form as currently stored, apply over the published ones, so access
+// changes take effect without publishing again.
+func NewPublicForm(version *FormVersion, live *Form) (*PublicForm, error) {
+	form := version.Form
+	form.Status = StatusPublished
+
+	if live != nil {
+		form.AllowedDomains = live.AllowedDomains
+		form.InviteOnly = live.InviteOnly
+	}
+
+	document, err := form.ToJson()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render form: %w", err)
+	}
+
+	return &PublicForm{
+		FormID:      version.FormID,
+		Version:     version.Version,
+		Form:        form,
+		Document:    document,
+		PublishedAt: version.PublishedAt,
+	}, nil
+}
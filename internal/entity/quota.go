@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+// Quota resources
+const (
+	QuotaForms            = "forms"
+	QuotaQuestions        = "questions"
+	QuotaQuestionsPerForm = "questions_per_form"
+)
+
+type (
+	// AuthorQuota tracks what an author owns and their limit overrides.
+	// A nil limit falls back to the global default.
+	AuthorQuota struct {
+		Author        string `gorm:"primaryKey;size:255"`
+		MaxForms      *int   // Max forms owned at once
+		MaxQuestions  *int   // Max questions over all owned forms
+		MaxPerForm    *int   // Max questions of any single owned form
+		FormCount     int    // Forms currently owned
+		QuestionCount int    // Questions over all owned forms
+		UpdatedAt     time.Time
+	}
+
+	// QuotaLimits are the effective limits of an author, 0 means unlimited
+	QuotaLimits struct {
+		MaxForms     int `json:"max_forms"`
+		MaxQuestions int `json:"max_questions"`
+		MaxPerForm   int `json:"max_questions_per_form"`
+	}
+
+	// OutputQuota is the reply to a quota request
+	OutputQuota struct {
+		RequestID string      `json:"request_id,omitempty"`
+		Author    string      `json:"author"`
+		Limits    QuotaLimits `json:"limits"`
+		Forms     int         `json:"forms"`     // Forms currently owned
+		Questions int         `json:"questions"` // Questions over all owned forms
+	}
+)
+
+// Limits returns base with the overrides of q applied
+func (q *AuthorQuota) Limits(base QuotaLimits) QuotaLimits {
+	if q.MaxForms != nil {
+		base.MaxForms = *q.MaxForms
+	}
+	if q.MaxQuestions != nil {
+		base.MaxQuestions = *q.MaxQuestions
+	}
+	if q.MaxPerForm != nil {
+		base.MaxPerForm = *q.MaxPerForm
+	}
+
+	return base
+}
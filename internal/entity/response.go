@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Answer is the answer to a single question, identified by its position
+	Answer struct {
+		OrderNumber uint            `json:"order_number"`
+		Value       json.RawMessage `json:"value"` // Shape depends on the question type
+	}
+
+	// ResponseSubmission is a completed response submitted for a form
+	ResponseSubmission struct {
+		RequestID    string   `json:"request_id"`
+		FormID       string   `json:"form_id"`
+		RespondentID string   `json:"respondent_id"`
+		Email        string   `json:"email,omitempty"`
+		Token        string   `json:"token,omitempty"` // Invite token, never published
+		Answers      []Answer `json:"answers"`
+		ResponseID   string   `json:"response_id,omitempty"` // Set on acceptance while responses are stored
+
+		Attachments []Attachment `json:"attachments,omitempty"` // Files answering file questions, set on acceptance
+
+		ConfirmationMessage string `json:"confirmation_message,omitempty"` // Message of the form for the respondent, set on acceptance
+	}
+
+	// Response is an accepted response as stored
+	Response struct {
+		ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+		FormID       uuid.UUID `gorm:"type:uuid;index" json:"form_id"`
+		RequestID    string    `gorm:"size:64" json:"request_id,omitempty"` // ID of the submission request
+		RespondentID string    `gorm:"index;size:255" json:"respondent_id"`
+		Email        string    `json:"email,omitempty"`
+		Answers      []Answer  `gorm:"serializer:json" json:"answers"`
+		CreatedAt    time.Time `gorm:"index" json:"created_at"`
+	}
+
+	// ResponseList is the reply to a response listing request
+	ResponseList struct {
+		RequestID string     `json:"request_id"`
+		FormID    string     `json:"form_id"`
+		Total     int64      `json:"total"`  // Stored responses of the form
+		Offset    int        `json:"offset"` // Responses skipped before this page
+		Responses []Response `json:"responses"`
+	}
+
+	// AnswerError lists why the answer to one question was rejected
+	AnswerError struct {
+		OrderNumber uint     `json:"order_number"`
+		Errors      []string `json:"errors"`
+	}
+
+	// ResponseRejection is published when a submitted response isn't accepted
+	ResponseRejection struct {
+		RequestID    string        `json:"request_id"`
+		FormID       string        `json:"form_id"`
+		RespondentID string        `json:"respondent_id"`
+		Reason       string        `json:"reason"`
+		Errors       []AnswerError `json:"errors,omitempty"` // Per-question errors when answers are invalid
+		ErrorDetail                // Code and localized messages of the reason
+	}
+)
@@ -0,0 +1,192 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Names of validation rules, as reported in RuleError
+const (
+	RuleRequired  = "required"
+	RulePattern   = "pattern"
+	RuleMin       = "min"
+	RuleMax       = "max"
+	RuleMinLength = "min_length"
+	RuleMaxLength = "max_length"
+)
+
+var (
+	// ErrInvalidRules is wrapped by every RuleError
+	ErrInvalidRules = errors.New("invalid validation rules")
+
+	// ErrRuleFailed is returned for answers breaking a validation rule
+	ErrRuleFailed = errors.New("answer breaks validation rule")
+)
+
+type (
+	// ValidationRules restrict the answers of a question on top of its type.
+	// Rules only apply to answers of the kind they test: Pattern and the
+	// lengths to text, Min and Max to numbers, the lengths to lists as well.
+	ValidationRules struct {
+		Required  bool     `json:"required,omitempty" yaml:"required,omitempty"`     // The question must be answered
+		Pattern   string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`       // Regular expression, RE2 syntax, text answers must match
+		Min       *float64 `json:"min,omitempty" yaml:"min,omitempty"`               // Lowest number accepted
+		Max       *float64 `json:"max,omitempty" yaml:"max,omitempty"`               // Highest number accepted
+		MinLength *int     `json:"min_length,omitempty" yaml:"min_length,omitempty"` // Fewest characters of a text, or items of a list
+		MaxLength *int     `json:"max_length,omitempty" yaml:"max_length,omitempty"` // Most characters of a text, or items of a list
+	}
+
+	// RuleError describes a malformed validation rule of a question
+	RuleError struct {
+		OrderNumber uint   // Position of the question
+		Rule        string // Name of the rule, like RulePattern
+		Reason      string
+	}
+)
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("%v: question %d: %s %s", ErrInvalidRules, e.OrderNumber, e.Rule, e.Reason)
+}
+
+func (e *RuleError) Unwrap() error {
+	return ErrInvalidRules
+}
+
+// ValidateRules checks that the validation rules of the question can be
+// enforced. Questions without rules are valid.
+// Returns a *RuleError for the first malformed rule
+func (q *Question) ValidateRules() error {
+	r := q.Rules
+	if r == nil {
+		return nil
+	}
+
+	ruleErr := func(rule, reason string) error {
+		return &RuleError{OrderNumber: q.OrderNumber, Rule: rule, Reason: reason}
+	}
+
+	if r.Pattern != "" {
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return ruleErr(RulePattern, fmt.Sprintf("is not a regular expression: %v", err))
+		}
+	}
+
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return ruleErr(RuleMin, "is greater than max")
+	}
+
+	if r.MinLength != nil && *r.MinLength < 0 {
+		return ruleErr(RuleMinLength, "is negative")
+	}
+	if r.MaxLength != nil && *r.MaxLength < 0 {
+		return ruleErr(RuleMaxLength, "is negative")
+	}
+	if r.MinLength != nil && r.MaxLength != nil && *r.MinLength > *r.MaxLength {
+		return ruleErr(RuleMinLength, "is greater than max_length")
+	}
+
+	return nil
+}
+
+// Check tests answer, JSON null when unanswered, against the rules. The
+// rules must have been validated.
+func (r *ValidationRules) Check(answer json.RawMessage) error {
+	var given any
+	if len(answer) > 0 {
+		if err := json.Unmarshal(answer, &given); err != nil {
+			return fmt.Errorf("%w: answer is not JSON", ErrRuleFailed)
+		}
+	}
+
+	length := -1
+
+	switch value := given.(type) {
+	case nil:
+		if r.Required {
+			return fmt.Errorf("%w: answer is required", ErrRuleFailed)
+		}
+		return nil
+	case string:
+		if value == "" && r.Required {
+			return fmt.Errorf("%w: answer is required", ErrRuleFailed)
+		}
+		if r.Pattern != "" && value != "" && !regexp.MustCompile(r.Pattern).MatchString(value) {
+			return fmt.Errorf("%w: answer must match %s", ErrRuleFailed, r.Pattern)
+		}
+		length = utf8.RuneCountInString(value)
+	case []any:
+		if len(value) == 0 && r.Required {
+			return fmt.Errorf("%w: answer is required", ErrRuleFailed)
+		}
+		length = len(value)
+	case float64:
+		if r.Min != nil && value < *r.Min {
+			return fmt.Errorf("%w: answer must be at least %v", ErrRuleFailed, *r.Min)
+		}
+		if r.Max != nil && value > *r.Max {
+			return fmt.Errorf("%w: answer must be at most %v", ErrRuleFailed, *r.Max)
+		}
+	}
+
+	if length < 0 {
+		return nil
+	}
+	if r.MinLength != nil && length < *r.MinLength {
+		return fmt.Errorf("%w: answer must have at least %d characters or items", ErrRuleFailed, *r.MinLength)
+	}
+	if r.MaxLength != nil && length > *r.MaxLength {
+		return fmt.Errorf("%w: answer must have at most %d characters or items", ErrRuleFailed, *r.MaxLength)
+	}
+
+	return nil
+}
+
+// clone returns a deep copy of the rules
+func (r *ValidationRules) clone() *ValidationRules {
+	if r == nil {
+		return nil
+	}
+
+	c := *r
+	if r.Min != nil {
+		c.Min = new(float64)
+		*c.Min = *r.Min
+	}
+	if r.Max != nil {
+		c.Max = new(float64)
+		*c.Max = *r.Max
+	}
+	if r.MinLength != nil {
+		c.MinLength = new(int)
+		*c.MinLength = *r.MinLength
+	}
+	if r.MaxLength != nil {
+		c.MaxLength = new(int)
+		*c.MaxLength = *r.MaxLength
+	}
+
+	return &c
+}
+
+// Value stores the rules as a JSON column
+func (r ValidationRules) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan reads the rules from a JSON column
+func (r *ValidationRules) Scan(src any) error {
+	switch data := src.(type) {
+	case []byte:
+		return json.Unmarshal(data, r)
+	case string:
+		return json.Unmarshal([]byte(data), r)
+	case nil:
+		return nil
+	}
+
+	return fmt.Errorf("failed to scan validation rules from %T", src)
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuestion_ValidateRules(t *testing.T) {
+	one, two := 1.0, 2.0
+	negative := -1
+
+	tests := []struct {
+		name  string
+		rules *ValidationRules
+		rule  string
+	}{
+		{"no rules", nil, ""},
+		{"valid rules", &ValidationRules{Required: true, Pattern: `^\d+$`, Min: &one, Max: &two}, ""},
+		{"bad pattern", &ValidationRules{Pattern: `(`}, RulePattern},
+		{"min above max", &ValidationRules{Min: &two, Max: &one}, RuleMin},
+		{"negative length", &ValidationRules{MaxLength: &negative}, RuleMaxLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Question{OrderNumber: 4, Rules: tt.rules}
+
+			err := q.ValidateRules()
+			if tt.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var ruleErr *RuleError
+			require.ErrorAs(t, err, &ruleErr)
+			assert.ErrorIs(t, err, ErrInvalidRules)
+			assert.Equal(t, uint(4), ruleErr.OrderNumber)
+			assert.Equal(t, tt.rule, ruleErr.Rule)
+		})
+	}
+}
+
+func TestValidationRules_Check(t *testing.T) {
+	minimum, maximum := 1.0, 10.0
+	minLength, maxLength := 2, 3
+
+	rules := &ValidationRules{
+		Required:  true,
+		Pattern:   `^[a-z]*$`,
+		Min:       &minimum,
+		Max:       &maximum,
+		MinLength: &minLength,
+		MaxLength: &maxLength,
+	}
+
+	tests := []struct {
+		answer string
+		valid  bool
+	}{
+		{`"abc"`, true},
+		{`5`, true},
+		{`["a","b"]`, true},
+		{`null`, false},
+		{`""`, false},
+		{`[]`, false},
+		{`"ABC"`, false},
+		{`"a"`, false},
+		{`"abcd"`, false},
+		{`0`, false},
+		{`11`, false},
+		{`["a","b","c","d"]`, false},
+		{`true`, true}, // No rule tests booleans
+	}
+
+	for _, tt := range tests {
+		err := rules.Check(json.RawMessage(tt.answer))
+		if tt.valid {
+			assert.NoError(t, err, tt.answer)
+		} else {
+			assert.ErrorIs(t, err, ErrRuleFailed, tt.answer)
+		}
+	}
+
+	t.Run("optional questions may be left unanswered", func(t *testing.T) {
+		assert.NoError(t, (&ValidationRules{Pattern: `^x$`}).Check(json.RawMessage(`null`)))
+		assert.NoError(t, (&ValidationRules{Pattern: `^x$`}).Check(json.RawMessage(`""`)))
+	})
+}
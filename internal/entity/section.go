@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Section is a page of a form grouping some of its questions. Questions
+	// without a section are shown before the first section.
+	Section struct {
+		ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+		FormID      uuid.UUID `gorm:"type:uuid;index" json:"form_id"`
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		OrderNumber uint      `json:"order_number"` // Position of the section in the form
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// OutputSection is a DTO for section data in API responses
+	OutputSection struct {
+		ID          string           `json:"id"`           // Section identifier
+		Title       string           `json:"title"`        // Section title
+		Description string           `json:"description"`  // Section description
+		OrderNumber uint             `json:"order_number"` // Section position
+		Questions   []OutputQuestion `json:"questions"`    // Questions of the section
+	}
+)
+
+func (s *Section) Validate() error {
+	if s.FormID == uuid.Nil {
+		return errors.New("form ID can not be nil")
+	}
+	if s.Title == "" {
+		return errors.New("section title can not be empty")
+	}
+
+	return nil
+}
+
+// ToOutput converts a Section entity to its DTO representation, without
+// its questions
+func (s *Section) ToOutput() OutputSection {
+	return OutputSection{
+		ID:          s.ID.String(),
+		Title:       s.Title,
+		Description: s.Description,
+		OrderNumber: s.OrderNumber,
+		Questions:   []OutputQuestion{},
+	}
+}
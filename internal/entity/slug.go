@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength bounds the length of form slugs
+const MaxSlugLength = 96
+
+// defaultSlug is the slug of forms whose title has nothing to slugify
+const defaultSlug = "form"
+
+// ErrInvalidSlug is returned for slugs other than lowercase words of ASCII
+// letters and digits joined by single hyphens
+var ErrInvalidSlug = errors.New("invalid slug")
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidateSlug checks that slug can name a form in URLs
+func ValidateSlug(slug string) error {
+	if len(slug) > MaxSlugLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidSlug, MaxSlugLength)
+	}
+
+	if !slugPattern.MatchString(slug) {
+		return fmt.Errorf("%w: %q", ErrInvalidSlug, slug)
+	}
+
+	return nil
+}
+
+// Slugify turns a title into a slug of at most maxLength characters:
+// accents are dropped, letters lowercased and every run of other
+// characters becomes a hyphen, so "Customer Survey 2024!" is
+// "customer-survey-2024". Titles without ASCII letters or digits give
+// "form".
+func Slugify(title string, maxLength int) string {
+	var b strings.Builder
+	hyphen := false
+
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			hyphen = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxLength {
+		slug = strings.TrimRight(slug[:maxLength], "-")
+	}
+
+	if slug == "" {
+		return defaultSlug
+	}
+
+	return slug
+}
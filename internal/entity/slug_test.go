@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Customer Survey 2024", "customer-survey-2024"},
+		{"  Café -- Feedback!! ", "cafe-feedback"},
+		{"Q&A: What's next?", "q-a-what-s-next"},
+		{"Опрос", "form"},
+		{"", "form"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			slug := Slugify(tt.title, MaxSlugLength)
+			assert.Equal(t, tt.want, slug)
+			assert.NoError(t, ValidateSlug(slug))
+		})
+	}
+
+	slug := Slugify(strings.Repeat("ab ", 10), 8)
+	assert.Equal(t, "ab-ab-ab", slug, "cut to the length without a trailing hyphen")
+}
+
+func TestValidateSlug(t *testing.T) {
+	assert.NoError(t, ValidateSlug("customer-survey-2024"))
+
+	for _, slug := range []string{"", "Customer", "two--hyphens", "-leading", "trailing-", "with space", strings.Repeat("a", MaxSlugLength+1)} {
+		assert.ErrorIs(t, ValidateSlug(slug), ErrInvalidSlug, slug)
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// QuestionStat is a persisted answer count of a form. A nil QuestionID
+	// counts the responses to the form; an empty Value counts the answers to the
+	// question, any other value the answers falling into that bucket.
+	QuestionStat struct {
+		FormID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+		QuestionID uuid.UUID `gorm:"type:uuid;primaryKey"`
+		Value      string    `gorm:"primaryKey;size:255"`
+		Total      int64
+		UpdatedAt  time.Time
+	}
+
+	// QuestionStats is the answer distribution of a single question
+	QuestionStats struct {
+		QuestionID   uuid.UUID        `json:"question_id"`
+		OrderNumber  uint             `json:"order_number"`
+		Type         string           `json:"type"`
+		Answered     int64            `json:"answered"`               // Responses answering the question
+		Distribution map[string]int64 `json:"distribution,omitempty"` // Answers per bucket, for types keeping a distribution
+	}
+
+	// QuestionStatsReport is the reply to a question statistics request
+	QuestionStatsReport struct {
+		RequestID string          `json:"request_id,omitempty"`
+		FormID    string          `json:"form_id"`
+		Responses int64           `json:"responses"` // Accepted responses to the form
+		Questions []QuestionStats `json:"questions"`
+	}
+)
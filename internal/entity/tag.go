@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTagLength caps the characters of a tag name
+const MaxTagLength = 64
+
+// ErrInvalidTag is returned for tag names that can't be stored
+var ErrInvalidTag = errors.New("invalid tag")
+
+type (
+	// Tag groups forms, like a category. Tags are shared by the forms of
+	// every tenant and identified by their normalized name.
+	Tag struct {
+		Name string `gorm:"primaryKey;size:64" json:"name"`
+	}
+
+	// FormList is the reply to a listing of the forms with a tag
+	FormList struct {
+		RequestID string       `json:"request_id"`
+		Tag       string       `json:"tag"`
+		Total     int64        `json:"total"`  // Forms with the tag
+		Offset    int          `json:"offset"` // Forms skipped before this page
+		Forms     []OutputForm `json:"forms"`  // Forms without their questions
+	}
+)
+
+// NormalizeTags trims and lowercases tag names, dropping empty names and
+// duplicates
+// Returns ErrInvalidTag for names longer than MaxTagLength
+func NormalizeTags(names []string) ([]Tag, error) {
+	tags := make([]Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+
+		if utf8.RuneCountInString(name) > MaxTagLength {
+			return nil, fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTag, name, MaxTagLength)
+		}
+
+		seen[name] = true
+		tags = append(tags, Tag{Name: name})
+	}
+
+	return tags, nil
+}
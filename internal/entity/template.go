@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// TemplateQuestion is a question of a template
+	TemplateQuestion struct {
+		Content      string          `json:"content"`
+		OrderNumber  uint            `json:"order_number"`
+		Type         string          `json:"type"`                    // Question type name, see package question
+		Options      json.RawMessage `json:"options,omitempty"`       // Type-specific options
+		DefaultValue json.RawMessage `json:"default_value,omitempty"` // Answer prefilled for respondents
+		MediaURL     string          `json:"media_url,omitempty"`     // Image or video shown above the question
+		MediaType    string          `json:"media_type,omitempty"`    // Kind of media, one of the Media kinds
+
+		Rules *ValidationRules `json:"rules,omitempty"` // Answer validation rules
+	}
+
+	// Template is a reusable blueprint of a form. Forms created from it get
+	// copies of its questions, so later changes of either don't affect the
+	// other.
+	Template struct {
+		ID          uuid.UUID          `gorm:"type:uuid;primaryKey" json:"id"`
+		Title       string             `json:"title"`
+		Description string             `json:"description"`
+		Author      string             `json:"author"`
+		TenantID    string             `gorm:"index;size:64" json:"tenant_id,omitempty"` // Tenant owning the template, empty for the default tenant
+		Questions   []TemplateQuestion `gorm:"serializer:json" json:"questions"`
+		CreatedAt   time.Time          `json:"created_at"`
+	}
+)
+
+func (t *Template) Validate() error {
+	if t.Title == "" {
+		return errors.New("template title can not be empty")
+	}
+	if t.Author == "" {
+		return errors.New("author ID can not be nil")
+	}
+
+	return nil
+}
+
+// NewForm returns a new form of author with deep copies of the questions
+func (t *Template) NewForm(author string) *Form {
+	form := &Form{
+		ID:          uuid.New(),
+		Title:       t.Title,
+		Description: t.Description,
+		Author:      author,
+		TenantID:    t.TenantID,
+		Questions:   make([]Question, len(t.Questions)),
+	}
+
+	for i, q := range t.Questions {
+		form.Questions[i] = Question{
+			FormID:       form.ID,
+			Content:      q.Content,
+			OrderNumber:  q.OrderNumber,
+			Type:         q.Type,
+			Options:      bytes.Clone(q.Options),
+			DefaultValue: bytes.Clone(q.DefaultValue),
+			MediaURL:     q.MediaURL,
+			MediaType:    q.MediaType,
+			Rules:        q.Rules.clone(),
+		}
+	}
+
+	return form
+}
+
+// Question returns the template question as a question of no form, e.g. to
+// validate it
+func (q *TemplateQuestion) Question() *Question {
+	return &Question{
+		Content:      q.Content,
+		OrderNumber:  q.OrderNumber,
+		Type:         q.Type,
+		Options:      q.Options,
+		DefaultValue: q.DefaultValue,
+		MediaURL:     q.MediaURL,
+		MediaType:    q.MediaType,
+		Rules:        q.Rules,
+	}
+}
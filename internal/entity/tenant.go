@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+type (
+	// TenantSettings holds per-tenant overrides of global settings.
+	// A nil field falls back to the global default.
+	TenantSettings struct {
+		TenantID      string `gorm:"primaryKey;size:64"`
+		RateLimit     *int   // Requests per minute
+		MaxQuestions  *int   // Max questions per form
+		RetentionDays *int   // Days responses are kept
+		UpdatedAt     time.Time
+	}
+
+	// Settings are the effective settings of a tenant after applying overrides
+	Settings struct {
+		TenantID      string `json:"tenant_id"`
+		RateLimit     int    `json:"rate_limit"`     // Requests per minute, 0 means unlimited
+		MaxQuestions  int    `json:"max_questions"`  // 0 means unlimited
+		RetentionDays int    `json:"retention_days"` // 0 keeps responses forever
+	}
+)
+
+// Apply returns base with the overrides of t applied
+func (t *TenantSettings) Apply(base Settings) Settings {
+	base.TenantID = t.TenantID
+
+	if t.RateLimit != nil {
+		base.RateLimit = *t.RateLimit
+	}
+	if t.MaxQuestions != nil {
+		base.MaxQuestions = *t.MaxQuestions
+	}
+	if t.RetentionDays != nil {
+		base.RetentionDays = *t.RetentionDays
+	}
+
+	return base
+}
@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// MaxLogoURLLength caps the length of theme logo URLs, the size of their column
+const MaxLogoURLLength = 2048
+
+// ErrInvalidTheme is returned for themes renderers can't apply safely
+var ErrInvalidTheme = errors.New("invalid theme")
+
+var (
+	// themeColor matches hex colors, #rgb or #rrggbb
+	themeColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+	// themeFont matches font family names, which renderers put into CSS
+	themeFont = regexp.MustCompile(`^[A-Za-z0-9 -]{1,64}$`)
+)
+
+type (
+	// Theme holds how a form looks to its respondents. Empty fields leave the
+	// renderer's defaults.
+	Theme struct {
+		PrimaryColor    string `gorm:"size:7" json:"primary_color,omitempty" yaml:"primary_color,omitempty"`       // Buttons and highlights, #rgb or #rrggbb
+		BackgroundColor string `gorm:"size:7" json:"background_color,omitempty" yaml:"background_color,omitempty"` // Page background
+		TextColor       string `gorm:"size:7" json:"text_color,omitempty" yaml:"text_color,omitempty"`             // Titles, questions and descriptions
+		LogoURL         string `gorm:"size:2048" json:"logo_url,omitempty" yaml:"logo_url,omitempty"`              // Image shown above the form, an http or https URL
+		Font            string `gorm:"size:64" json:"font,omitempty" yaml:"font,omitempty"`                        // Font family, e.g. Open Sans
+	}
+
+	// ThemeUpdate changes some of the theme of a form; nil fields are left
+	// as they are, empty ones reset to the renderer's default
+	ThemeUpdate struct {
+		PrimaryColor    *string `json:"primary_color,omitempty"`
+		BackgroundColor *string `json:"background_color,omitempty"`
+		TextColor       *string `json:"text_color,omitempty"`
+		LogoURL         *string `json:"logo_url,omitempty"`
+		Font            *string `json:"font,omitempty"`
+	}
+)
+
+// Validate checks that renderers can apply the theme: colors are hex
+// colors, the logo is a web address and the font a plain family name
+func (t *Theme) Validate() error {
+	colors := []struct{ name, value string }{
+		{"primary color", t.PrimaryColor},
+		{"background color", t.BackgroundColor},
+		{"text color", t.TextColor},
+	}
+	for _, c := range colors {
+		if c.value != "" && !themeColor.MatchString(c.value) {
+			return fmt.Errorf("%w: %s %q is not a hex color", ErrInvalidTheme, c.name, c.value)
+		}
+	}
+
+	if t.LogoURL != "" {
+		if len(t.LogoURL) > MaxLogoURLLength {
+			return fmt.Errorf("%w: logo URL longer than %d characters", ErrInvalidTheme, MaxLogoURLLength)
+		}
+
+		u, err := url.Parse(t.LogoURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: logo %q is not an http or https URL", ErrInvalidTheme, t.LogoURL)
+		}
+	}
+
+	if t.Font != "" && !themeFont.MatchString(t.Font) {
+		return fmt.Errorf("%w: font %q is not a font family name", ErrInvalidTheme, t.Font)
+	}
+
+	return nil
+}
+
+// IsZero reports whether the theme leaves every default
+func (t *Theme) IsZero() bool {
+	return *t == Theme{}
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u *ThemeUpdate) IsEmpty() bool {
+	return u.PrimaryColor == nil && u.BackgroundColor == nil && u.TextColor == nil && u.LogoURL == nil && u.Font == nil
+}
+
+// Apply returns theme with the changes of the update
+func (u *ThemeUpdate) Apply(theme Theme) Theme {
+	if u.PrimaryColor != nil {
+		theme.PrimaryColor = *u.PrimaryColor
+	}
+	if u.BackgroundColor != nil {
+		theme.BackgroundColor = *u.BackgroundColor
+	}
+	if u.TextColor != nil {
+		theme.TextColor = *u.TextColor
+	}
+	if u.LogoURL != nil {
+		theme.LogoURL = *u.LogoURL
+	}
+	if u.Font != nil {
+		theme.Font = *u.Font
+	}
+
+	return theme
+}
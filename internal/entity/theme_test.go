@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTheme_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		theme Theme
+		valid bool
+	}{
+		{"empty theme", Theme{}, true},
+		{"full theme", Theme{PrimaryColor: "#369", BackgroundColor: "#FFFFFF", TextColor: "#1a1a1a", LogoURL: "https://example.com/logo.png", Font: "Open Sans"}, true},
+		{"named color", Theme{PrimaryColor: "red"}, false},
+		{"css injection in color", Theme{TextColor: "#fff;}body{display:none"}, false},
+		{"relative logo", Theme{LogoURL: "/logo.png"}, false},
+		{"script logo", Theme{LogoURL: "javascript:alert(1)"}, false},
+		{"long logo", Theme{LogoURL: "https://example.com/" + strings.Repeat("x", MaxLogoURLLength)}, false},
+		{"css injection in font", Theme{Font: "Arial; color: red"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.theme.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidTheme)
+			}
+		})
+	}
+}
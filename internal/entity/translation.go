@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidLocale is returned for translations keyed by malformed locales
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// localeTag matches BCP 47 style locales, e.g. "de" or "pt-BR"
+var localeTag = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+
+type (
+	// FormTranslation holds the texts of a form in one locale. Empty texts
+	// fall back to the default language.
+	FormTranslation struct {
+		Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+		Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	}
+
+	// QuestionTranslation holds the text of a question in one locale
+	QuestionTranslation struct {
+		Content string `json:"content,omitempty" yaml:"content,omitempty"`
+	}
+
+	// FormReply is the reply to a form request, the form in the requested
+	// locale
+	FormReply struct {
+		RequestID string `json:"request_id"`
+		Locale    string `json:"locale"` // Locale the texts are in, empty for the default language
+		Form      *Form  `json:"form"`
+	}
+)
+
+// ValidateTranslations checks that the translations of the form and its
+// questions are keyed by well-formed locales
+func (f *Form) ValidateTranslations() error {
+	if f.DefaultLocale != "" && !localeTag.MatchString(f.DefaultLocale) {
+		return fmt.Errorf("%w: default locale %q", ErrInvalidLocale, f.DefaultLocale)
+	}
+
+	for locale := range f.Translations {
+		if !localeTag.MatchString(locale) {
+			return fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+		}
+	}
+
+	for i := range f.Questions {
+		for locale := range f.Questions[i].Translations {
+			if !localeTag.MatchString(locale) {
+				return fmt.Errorf("%w: %q of question %d", ErrInvalidLocale, locale, f.Questions[i].OrderNumber)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Localized returns a copy of the form with its title, description and
+// question contents in locale. Texts without a translation, in locale or
+// its language (e.g. "pt" for "pt-BR"), keep the default language, as does
+// the whole form for an empty locale or its default locale.
+// Returns the copy and the locale its form texts were found in, empty when
+// they are in the default language
+func (f *Form) Localized(locale string) (*Form, string) {
+	localized := *f
+	localized.Questions = make([]Question, len(f.Questions))
+	copy(localized.Questions, f.Questions)
+
+	if locale == "" || strings.EqualFold(locale, f.DefaultLocale) {
+		return &localized, ""
+	}
+
+	found := ""
+	if key, ok := matchLocale(f.Translations, locale); ok {
+		found = key
+		translation := f.Translations[key]
+		localized.Title = cmp.Or(translation.Title, f.Title)
+		localized.Description = cmp.Or(translation.Description, f.Description)
+	}
+
+	for i := range localized.Questions {
+		q := &localized.Questions[i]
+		if key, ok := matchLocale(q.Translations, locale); ok {
+			q.Content = cmp.Or(q.Translations[key].Content, q.Content)
+		}
+	}
+
+	return &localized, found
+}
+
+// matchLocale returns the key of translations for locale: locale itself or
+// else its language, compared case-insensitively
+func matchLocale[T any](translations map[string]T, locale string) (string, bool) {
+	if len(translations) == 0 {
+		return "", false
+	}
+
+	locale = strings.ReplaceAll(locale, "_", "-")
+	language, _, _ := strings.Cut(locale, "-")
+
+	var fallback string
+	for _, key := range slices.Sorted(maps.Keys(translations)) {
+		normalized := strings.ReplaceAll(key, "_", "-")
+		if strings.EqualFold(normalized, locale) {
+			return key, true
+		}
+		if fallback == "" && strings.EqualFold(normalized, language) {
+			fallback = key
+		}
+	}
+
+	return fallback, fallback != ""
+}
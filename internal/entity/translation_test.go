@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func translatedForm() *Form {
+	return &Form{
+		ID:            uuid.New(),
+		Title:         "Feedback",
+		Description:   "Tell us",
+		DefaultLocale: "en",
+		Translations: map[string]FormTranslation{
+			"de":    {Title: "Rückmeldung", Description: "Erzählen Sie"},
+			"pt":    {Title: "Opinião"},
+			"pt-BR": {Title: "Feedback do Brasil"},
+		},
+		Questions: []Question{
+			{Content: "Name?", OrderNumber: 1, Translations: map[string]QuestionTranslation{"de": {Content: "Name?"}, "pt": {Content: "Nome?"}}},
+			{Content: "Age?", OrderNumber: 2},
+		},
+	}
+}
+
+func TestForm_Localized(t *testing.T) {
+	t.Run("translates the form and its questions", func(t *testing.T) {
+		form := translatedForm()
+		form.Questions[0].Translations["de"] = QuestionTranslation{Content: "Wie heißen Sie?"}
+
+		localized, locale := form.Localized("de")
+
+		assert.Equal(t, "de", locale)
+		assert.Equal(t, "Rückmeldung", localized.Title)
+		assert.Equal(t, "Erzählen Sie", localized.Description)
+		assert.Equal(t, "Wie heißen Sie?", localized.Questions[0].Content)
+		assert.Equal(t, "Age?", localized.Questions[1].Content, "untranslated question")
+
+		assert.Equal(t, "Feedback", form.Title, "the form is left unchanged")
+		assert.Equal(t, "Name?", form.Questions[0].Content)
+	})
+
+	t.Run("falls back to the default language for missing texts", func(t *testing.T) {
+		localized, locale := translatedForm().Localized("pt")
+
+		assert.Equal(t, "pt", locale)
+		assert.Equal(t, "Opinião", localized.Title)
+		assert.Equal(t, "Tell us", localized.Description)
+	})
+
+	t.Run("prefers the exact locale over its language", func(t *testing.T) {
+		localized, locale := translatedForm().Localized("pt_br")
+
+		assert.Equal(t, "pt-BR", locale)
+		assert.Equal(t, "Feedback do Brasil", localized.Title)
+		assert.Equal(t, "Nome?", localized.Questions[0].Content, "question translated in the language only")
+	})
+
+	t.Run("falls back to the language of a regional locale", func(t *testing.T) {
+		localized, locale := translatedForm().Localized("de-AT")
+
+		assert.Equal(t, "de", locale)
+		assert.Equal(t, "Rückmeldung", localized.Title)
+	})
+
+	t.Run("keeps the default language for unknown, empty and default locales", func(t *testing.T) {
+		for _, requested := range []string{"fr", "", "EN"} {
+			localized, locale := translatedForm().Localized(requested)
+
+			assert.Empty(t, locale, requested)
+			assert.Equal(t, "Feedback", localized.Title, requested)
+			assert.Equal(t, "Name?", localized.Questions[0].Content, requested)
+		}
+	})
+}
+
+func TestForm_ValidateTranslations(t *testing.T) {
+	require.NoError(t, translatedForm().ValidateTranslations())
+	require.NoError(t, (&Form{}).ValidateTranslations())
+
+	form := translatedForm()
+	form.Translations["not a locale"] = FormTranslation{Title: "x"}
+	assert.ErrorIs(t, form.ValidateTranslations(), ErrInvalidLocale)
+
+	form = translatedForm()
+	form.Questions[1].Translations = map[string]QuestionTranslation{"d": {Content: "x"}}
+	assert.ErrorIs(t, form.ValidateTranslations(), ErrInvalidLocale)
+
+	form = translatedForm()
+	form.DefaultLocale = "english!"
+	assert.ErrorIs(t, form.ValidateTranslations(), ErrInvalidLocale)
+}
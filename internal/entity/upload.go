@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upload statuses
+const (
+	UploadPending  = "pending"  // URL issued, file not confirmed yet
+	UploadStored   = "stored"   // File confirmed in storage and within limits
+	UploadAttached = "attached" // File belongs to a submitted response
+)
+
+type (
+	// Upload is the metadata of a file uploaded as an answer to a file question.
+	// The file itself lives in object storage under ObjectKey.
+	Upload struct {
+		ID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+		FormID      uuid.UUID  `gorm:"type:uuid;index"`
+		QuestionID  uuid.UUID  `gorm:"type:uuid;index"`
+		ResponseID  *uuid.UUID `gorm:"type:uuid;index"` // Set once attached to a response
+		ObjectKey   string     // Key of the object in storage
+		FileName    string     // Original file name
+		ContentType string     // MIME type declared by the client, verified on completion
+		Size        int64      // Size in bytes
+		Status      string     `gorm:"index"`
+		CreatedAt   time.Time
+		ExpiresAt   time.Time // Pending uploads are removed after this time
+	}
+
+	// UploadTicket tells the client where to upload a file
+	UploadTicket struct {
+		RequestID string    `json:"request_id"` // ID of the request event the ticket answers
+		UploadID  string    `json:"upload_id"`
+		URL       string    `json:"url"`      // Presigned URL to PUT the file to
+		Method    string    `json:"method"`   // Always PUT
+		MaxSize   int64     `json:"max_size"` // Max accepted size in bytes, 0 means unlimited
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	// Attachment is the metadata of a file attached to an accepted response
+	Attachment struct {
+		UploadID    string `json:"upload_id"`
+		OrderNumber uint   `json:"order_number"` // Position of the question the file answers
+		FileName    string `json:"file_name"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+		ObjectKey   string `json:"object_key"` // Key of the file in storage
+	}
+)
+
+// ToAttachment converts an upload answering the question at orderNumber to
+// its attachment metadata
+func (u *Upload) ToAttachment(orderNumber uint) Attachment {
+	return Attachment{
+		UploadID:    u.ID.String(),
+		OrderNumber: orderNumber,
+		FileName:    u.FileName,
+		ContentType: u.ContentType,
+		Size:        u.Size,
+		ObjectKey:   u.ObjectKey,
+	}
+}
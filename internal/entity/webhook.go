@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MAX_WEBHOOKS bounds the webhooks of a form
+const MAX_WEBHOOKS = 10
+
+// ErrInvalidWebhook is returned for webhooks without a usable URL
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// reservedPrefixes are the non-public IPv4 ranges netip.Addr doesn't
+// classify: "this network", dialed as the local host, and carrier-grade NAT
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// HostResolver resolves the hosts of webhook URLs, net.DefaultResolver
+// outside of tests
+type HostResolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Webhook posts the responses accepted for a form to a URL. The body is the
+// response event rendered through the payload template of the webhook, or
+// the event as it is without one, see package webhook.
+type Webhook struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	FormID       uuid.UUID `gorm:"type:uuid;index" json:"form_id"`
+	URL          string    `gorm:"size:2048" json:"url"`
+	TemplateKind string    `gorm:"size:16" json:"template_kind,omitempty"` // go, jsonpath or empty to post events as they are
+	Template     string    `gorm:"type:text" json:"template,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Validate checks that the webhook posts to an absolute http or https URL
+// whose host resolves, with resolver, to public addresses only, see
+// PublicAddress. The template is checked when compiled, see package webhook.
+func (w *Webhook) Validate(ctx context.Context, resolver HostResolver) error {
+	target, err := url.Parse(w.URL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+
+	if (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: %q is not an http or https URL", ErrInvalidWebhook, w.URL)
+	}
+
+	host := target.Hostname()
+
+	addrs := []netip.Addr{}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else if addrs, err = resolver.LookupNetIP(ctx, "ip", host); err != nil {
+		return fmt.Errorf("%w: failed to resolve %s: %v", ErrInvalidWebhook, host, err)
+	}
+
+	for _, addr := range addrs {
+		if !PublicAddress(addr) {
+			return fmt.Errorf("%w: %s resolves to the non-public address %s", ErrInvalidWebhook, host, addr)
+		}
+	}
+
+	return nil
+}
+
+// PublicAddress reports whether addr is reachable on the internet, so
+// webhooks can't be pointed at the service's own network: loopback,
+// private, reserved, link-local (including the 169.254.169.254 metadata
+// address), unspecified and multicast addresses aren't
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsUnspecified() || addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
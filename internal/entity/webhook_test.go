@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeResolver resolves the hosts it maps, failing for the others
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	resolved, ok := f[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	addrs := make([]netip.Addr, 0, len(resolved))
+	for _, addr := range resolved {
+		addrs = append(addrs, netip.MustParseAddr(addr))
+	}
+	return addrs, nil
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.215.14", true},
+		{"2606:2800:21f:cb07:6820:80da:af6b:8b2c", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"fd00:ec2::254", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, PublicAddress(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestWebhook_Validate(t *testing.T) {
+	resolver := fakeResolver{
+		"example.com":  {"93.184.215.14"},
+		"internal.lan": {"93.184.215.14", "10.0.0.7"},
+	}
+
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://example.com/hook", true},
+		{"http://93.184.215.14:8080/hook", true},
+		{"ftp://example.com", false},
+		{"/hook", false},
+		{"https://internal.lan/hook", false},
+		{"https://unknown.example/hook", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://[::1]:9000/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://192.168.0.10/hook", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := (&Webhook{URL: tt.url}).Validate(context.Background(), resolver)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidWebhook)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package errcatalog maps the errors reported in result events to stable,
+// machine-readable codes with localized messages, so clients can show
+// friendly messages without matching on error text
+package errcatalog
+
+import (
+	"maps"
+	"sort"
+	"sync"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+)
+
+// DEFAULT_LANGUAGE is the language messages fall back to
+const DEFAULT_LANGUAGE = "en"
+
+// Code identifies an error kind. Codes are part of the event contract and
+// never change once released.
+type Code string
+
+// Codes of errors reported to clients
+const (
+	INTERNAL                Code = "internal"                // Unexpected failure, the request may be retried
+	NOT_FOUND               Code = "not_found"               // The form, question or version doesn't exist
+	FORM_CLOSED             Code = "form_closed"             // The form no longer accepts responses
+	ACCESS_DENIED           Code = "access_denied"           // The respondent may not answer the form
+	DOMAIN_NOT_ALLOWED      Code = "domain_not_allowed"      // The respondent's email domain isn't allowed
+	INVITE_REQUIRED         Code = "invite_required"         // The form is invite-only and no token was given
+	INVITE_UNKNOWN          Code = "invite_unknown"          // The invite token doesn't belong to the form
+	INVITE_REVOKED          Code = "invite_revoked"          // The invite was revoked
+	INVITE_EXPIRED          Code = "invite_expired"          // The invite expired
+	INVITE_USED             Code = "invite_used"             // The single-use invite was already redeemed
+	INVITE_WRONG_RESPONDENT Code = "invite_wrong_respondent" // The invite was issued for another email
+	INVALID_ANSWERS         Code = "invalid_answers"         // Answers failed validation, see the per-question errors
+	RATE_LIMITED            Code = "rate_limited"            // Too many requests, retry later
+	LIMIT_EXCEEDED          Code = "limit_exceeded"          // A tenant limit would be broken
+	QUOTA_EXCEEDED          Code = "quota_exceeded"          // An author quota would be exceeded
+	UPLOAD_REJECTED         Code = "upload_rejected"         // The uploaded file isn't accepted
+	INVALID_RULES           Code = "invalid_rules"           // A validation rule of a question is malformed
+)
+
+// Catalog holds the localized messages of error codes
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[Code]map[string]string // Messages by code and language
+}
+
+// Default is the catalog with the built-in messages
+var Default = New()
+
+// New creates an empty catalog
+func New() *Catalog {
+	return &Catalog{
+		messages: make(map[Code]map[string]string),
+	}
+}
+
+// Add sets the message of code in lang, replacing an existing one
+func (c *Catalog) Add(code Code, lang, message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages[code] == nil {
+		c.messages[code] = make(map[string]string)
+	}
+	c.messages[code][lang] = message
+}
+
+// Message returns the message of code in lang, falling back to
+// DEFAULT_LANGUAGE and, for unknown codes, to the message of INTERNAL
+func (c *Catalog) Message(code Code, lang string) string {
+	messages := c.Messages(code)
+
+	if message, ok := messages[lang]; ok {
+		return message
+	}
+
+	return messages[DEFAULT_LANGUAGE]
+}
+
+// Messages returns the messages of code by language, those of INTERNAL for
+// unknown codes
+func (c *Catalog) Messages(code Code) map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	messages, ok := c.messages[code]
+	if !ok {
+		messages = c.messages[INTERNAL]
+	}
+
+	return maps.Clone(messages)
+}
+
+// Languages returns the languages any message is available in, sorted
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, messages := range c.messages {
+		for lang := range messages {
+			seen[lang] = true
+		}
+	}
+
+	langs := make([]string, 0, len(seen))
+	for lang := range seen {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	return langs
+}
+
+// Detail returns the code with its messages in every language, as included
+// in result events
+func (c *Catalog) Detail(code Code) entity.ErrorDetail {
+	return entity.ErrorDetail{
+		Code:     string(code),
+		Messages: c.Messages(code),
+	}
+}
@@ -0,0 +1,36 @@
+package errcatalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault_Complete(t *testing.T) {
+	langs := Default.Languages()
+	assert.Equal(t, []string{"en", "ru"}, langs)
+
+	for code, messages := range builtin {
+		for _, lang := range langs {
+			assert.NotEmpty(t, messages[lang], "%s lacks a %s message", code, lang)
+		}
+	}
+}
+
+func TestCatalog_Message(t *testing.T) {
+	catalog := New()
+	catalog.Add(INTERNAL, "en", "Oops")
+	catalog.Add(FORM_CLOSED, "en", "Closed")
+	catalog.Add(FORM_CLOSED, "de", "Geschlossen")
+
+	assert.Equal(t, "Geschlossen", catalog.Message(FORM_CLOSED, "de"))
+	assert.Equal(t, "Closed", catalog.Message(FORM_CLOSED, "fr"), "falls back to the default language")
+	assert.Equal(t, "Oops", catalog.Message("hologram", "en"), "unknown codes use internal")
+
+	detail := catalog.Detail(FORM_CLOSED)
+	assert.Equal(t, "form_closed", detail.Code)
+	assert.Equal(t, map[string]string{"en": "Closed", "de": "Geschlossen"}, detail.Messages)
+
+	detail.Messages["en"] = "changed"
+	assert.Equal(t, "Closed", catalog.Message(FORM_CLOSED, "en"), "details don't share the catalog's maps")
+}
@@ -0,0 +1,81 @@
+package errcatalog
+
+func init() {
+	for code, messages := range builtin {
+		for lang, message := range messages {
+			Default.Add(code, lang, message)
+		}
+	}
+}
+
+// builtin are the messages of every code by language
+var builtin = map[Code]map[string]string{
+	INTERNAL: {
+		"en": "Something went wrong on our side. Please try again.",
+		"ru": "Что-то пошло не так на нашей стороне. Попробуйте ещё раз.",
+	},
+	NOT_FOUND: {
+		"en": "This form could not be found.",
+		"ru": "Форма не найдена.",
+	},
+	FORM_CLOSED: {
+		"en": "This form is no longer accepting responses.",
+		"ru": "Эта форма больше не принимает ответы.",
+	},
+	ACCESS_DENIED: {
+		"en": "You don't have access to this form.",
+		"ru": "У вас нет доступа к этой форме.",
+	},
+	DOMAIN_NOT_ALLOWED: {
+		"en": "Responses are only accepted from certain email addresses.",
+		"ru": "Ответы принимаются только с определённых адресов электронной почты.",
+	},
+	INVITE_REQUIRED: {
+		"en": "This form can only be answered with an invitation.",
+		"ru": "На эту форму можно ответить только по приглашению.",
+	},
+	INVITE_UNKNOWN: {
+		"en": "This invitation is not valid.",
+		"ru": "Приглашение недействительно.",
+	},
+	INVITE_REVOKED: {
+		"en": "This invitation was withdrawn.",
+		"ru": "Приглашение было отозвано.",
+	},
+	INVITE_EXPIRED: {
+		"en": "This invitation has expired.",
+		"ru": "Срок действия приглашения истёк.",
+	},
+	INVITE_USED: {
+		"en": "This invitation was already used.",
+		"ru": "Приглашение уже использовано.",
+	},
+	INVITE_WRONG_RESPONDENT: {
+		"en": "This invitation was sent to someone else.",
+		"ru": "Это приглашение отправлено другому человеку.",
+	},
+	INVALID_ANSWERS: {
+		"en": "Some answers need your attention.",
+		"ru": "Проверьте, пожалуйста, некоторые ответы.",
+	},
+	RATE_LIMITED: {
+		"en": "Too many attempts. Please wait a moment and try again.",
+		"ru": "Слишком много попыток. Подождите немного и попробуйте снова.",
+	},
+	LIMIT_EXCEEDED: {
+		"en": "This change goes beyond the limits of your plan.",
+		"ru": "Это изменение превышает ограничения вашего тарифа.",
+	},
+	QUOTA_EXCEEDED: {
+		"en": "You have reached the maximum number of forms or questions.",
+		"ru": "Достигнуто максимальное количество форм или вопросов.",
+	},
+	UPLOAD_REJECTED: {
+		"en": "This file can't be accepted. Check its size and type.",
+		"ru": "Этот файл не может быть принят. Проверьте его размер и тип.",
+	},
+	INVALID_RULES: {
+		"en": "A validation rule of a question is not valid.",
+		"ru": "Правило проверки одного из вопросов задано неверно.",
+	},
+}
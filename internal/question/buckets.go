@@ -0,0 +1,64 @@
+package question
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+)
+
+// Bucketer is implemented by question types whose answers fall into a
+// bounded set of values, so answer distributions can be counted. Free-form
+// types like text don't implement it.
+type Bucketer interface {
+	// Buckets returns the values a valid answer counts towards, none for an
+	// empty answer
+	Buckets(options json.RawMessage, answer json.RawMessage) []string
+}
+
+// Buckets returns the distribution buckets of a valid answer to the question,
+// nil when its type keeps no distribution
+func (r *Registry) Buckets(q *entity.Question, answer json.RawMessage) []string {
+	t, err := r.Lookup(q.Type)
+	if err != nil {
+		return nil
+	}
+
+	bucketer, ok := t.(Bucketer)
+	if !ok {
+		return nil
+	}
+
+	return bucketer.Buckets(q.Options, answer)
+}
+
+func (numberType) Buckets(_ json.RawMessage, answer json.RawMessage) []string {
+	var number *float64
+	if json.Unmarshal(answer, &number) != nil || number == nil {
+		return nil
+	}
+
+	return []string{strconv.FormatFloat(*number, 'f', -1, 64)}
+}
+
+func (choiceType) Buckets(options json.RawMessage, answer json.RawMessage) []string {
+	var opts choiceOptions
+	if decodeOptions(options, &opts) != nil {
+		return nil
+	}
+
+	if opts.Multiple {
+		var picked []string
+		if json.Unmarshal(answer, &picked) != nil {
+			return nil
+		}
+		return picked
+	}
+
+	var single string
+	if json.Unmarshal(answer, &single) != nil || single == "" {
+		return nil
+	}
+
+	return []string{single}
+}
@@ -0,0 +1,217 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+func init() {
+	Register(textType{})
+	Register(numberType{})
+	Register(choiceType{})
+}
+
+type (
+	// textType is a free-form text answer
+	textType struct{}
+
+	textOptions struct {
+		Required  bool `json:"required"`
+		MaxLength int  `json:"max_length"` // 0 means unlimited
+	}
+
+	// numberType is a numeric answer with optional bounds
+	numberType struct{}
+
+	numberOptions struct {
+		Required bool     `json:"required"`
+		Min      *float64 `json:"min"`
+		Max      *float64 `json:"max"`
+	}
+
+	// choiceType picks one or, when multiple is set, several of the listed choices
+	choiceType struct{}
+
+	choiceOptions struct {
+		Required bool     `json:"required"`
+		Choices  []string `json:"choices"`
+		Multiple bool     `json:"multiple"`
+	}
+)
+
+func (textType) Name() string { return "text" }
+
+func (textType) OptionSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"required":   map[string]any{"type": "boolean"},
+			"max_length": map[string]any{"type": "integer", "minimum": 0},
+		},
+	}
+}
+
+func (textType) ValidateOptions(options json.RawMessage) error {
+	var opts textOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	if opts.MaxLength < 0 {
+		return fmt.Errorf("%w: max_length can not be negative", ErrInvalidOptions)
+	}
+
+	return nil
+}
+
+func (textType) ValidateAnswer(options json.RawMessage, answer json.RawMessage) error {
+	var opts textOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	var text string
+	if err := decodeAnswer(answer, &text); err != nil {
+		return err
+	}
+
+	if opts.Required && text == "" {
+		return fmt.Errorf("%w: answer is required", ErrInvalidAnswer)
+	}
+
+	if opts.MaxLength > 0 && len([]rune(text)) > opts.MaxLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidAnswer, opts.MaxLength)
+	}
+
+	return nil
+}
+
+func (numberType) Name() string { return "number" }
+
+func (numberType) OptionSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"required": map[string]any{"type": "boolean"},
+			"min":      map[string]any{"type": "number"},
+			"max":      map[string]any{"type": "number"},
+		},
+	}
+}
+
+func (numberType) ValidateOptions(options json.RawMessage) error {
+	var opts numberOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	if opts.Min != nil && opts.Max != nil && *opts.Min > *opts.Max {
+		return fmt.Errorf("%w: min is greater than max", ErrInvalidOptions)
+	}
+
+	return nil
+}
+
+func (numberType) ValidateAnswer(options json.RawMessage, answer json.RawMessage) error {
+	var opts numberOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	var number *float64
+	if err := decodeAnswer(answer, &number); err != nil {
+		return err
+	}
+
+	if number == nil {
+		if opts.Required {
+			return fmt.Errorf("%w: answer is required", ErrInvalidAnswer)
+		}
+		return nil
+	}
+
+	if opts.Min != nil && *number < *opts.Min {
+		return fmt.Errorf("%w: less than %v", ErrInvalidAnswer, *opts.Min)
+	}
+
+	if opts.Max != nil && *number > *opts.Max {
+		return fmt.Errorf("%w: greater than %v", ErrInvalidAnswer, *opts.Max)
+	}
+
+	return nil
+}
+
+func (choiceType) Name() string { return "choice" }
+
+func (choiceType) OptionSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"choices"},
+		"properties": map[string]any{
+			"required": map[string]any{"type": "boolean"},
+			"choices": map[string]any{
+				"type":     "array",
+				"items":    map[string]any{"type": "string"},
+				"minItems": 1,
+			},
+			"multiple": map[string]any{"type": "boolean"},
+		},
+	}
+}
+
+func (choiceType) ValidateOptions(options json.RawMessage) error {
+	var opts choiceOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	if len(opts.Choices) == 0 {
+		return fmt.Errorf("%w: choices can not be empty", ErrInvalidOptions)
+	}
+
+	for i, choice := range opts.Choices {
+		if slices.Contains(opts.Choices[:i], choice) {
+			return fmt.Errorf("%w: duplicate choice %q", ErrInvalidOptions, choice)
+		}
+	}
+
+	return nil
+}
+
+func (choiceType) ValidateAnswer(options json.RawMessage, answer json.RawMessage) error {
+	var opts choiceOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	var picked []string
+	if opts.Multiple {
+		if err := decodeAnswer(answer, &picked); err != nil {
+			return err
+		}
+	} else {
+		var single string
+		if err := decodeAnswer(answer, &single); err != nil {
+			return err
+		}
+		if single != "" {
+			picked = []string{single}
+		}
+	}
+
+	if len(picked) == 0 {
+		if opts.Required {
+			return fmt.Errorf("%w: answer is required", ErrInvalidAnswer)
+		}
+		return nil
+	}
+
+	for _, choice := range picked {
+		if !slices.Contains(opts.Choices, choice) {
+			return fmt.Errorf("%w: %q is not one of the choices", ErrInvalidAnswer, choice)
+		}
+	}
+
+	return nil
+}
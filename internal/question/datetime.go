@@ -0,0 +1,154 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+func init() {
+	Register(dateTimeType{})
+}
+
+// Date/time modes and the layouts their values use
+const (
+	MODE_DATE     = "date"     // 2006-01-02
+	MODE_TIME     = "time"     // 15:04
+	MODE_DATETIME = "datetime" // RFC 3339
+)
+
+var dateTimeLayouts = map[string]string{
+	MODE_DATE:     time.DateOnly,
+	MODE_TIME:     "15:04",
+	MODE_DATETIME: time.RFC3339,
+}
+
+type (
+	// dateTimeType is a date, time of day or full timestamp answer
+	dateTimeType struct{}
+
+	// DateTimeOptions are the options of a datetime question. Min and Max use
+	// the layout of the mode and are inclusive.
+	DateTimeOptions struct {
+		Required bool   `json:"required"`
+		Mode     string `json:"mode"` // date, time or datetime; defaults to date
+		Min      string `json:"min,omitempty"`
+		Max      string `json:"max,omitempty"`
+	}
+)
+
+func (dateTimeType) Name() string { return "datetime" }
+
+func (dateTimeType) OptionSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"required": map[string]any{"type": "boolean"},
+			"mode": map[string]any{
+				"type":    "string",
+				"enum":    []string{MODE_DATE, MODE_TIME, MODE_DATETIME},
+				"default": MODE_DATE,
+			},
+			"min": map[string]any{"type": "string"},
+			"max": map[string]any{"type": "string"},
+		},
+	}
+}
+
+// layout returns the parse layout for the options' mode
+func (opts *DateTimeOptions) layout() (string, error) {
+	if opts.Mode == "" {
+		opts.Mode = MODE_DATE
+	}
+
+	layout, ok := dateTimeLayouts[opts.Mode]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown mode %q", ErrInvalidOptions, opts.Mode)
+	}
+
+	return layout, nil
+}
+
+// bounds parses Min and Max, returning zero times for unset bounds
+func (opts *DateTimeOptions) bounds(layout string) (minimum, maximum time.Time, err error) {
+	if opts.Min != "" {
+		if minimum, err = time.Parse(layout, opts.Min); err != nil {
+			return minimum, maximum, fmt.Errorf("%w: min: %v", ErrInvalidOptions, err)
+		}
+	}
+
+	if opts.Max != "" {
+		if maximum, err = time.Parse(layout, opts.Max); err != nil {
+			return minimum, maximum, fmt.Errorf("%w: max: %v", ErrInvalidOptions, err)
+		}
+	}
+
+	return minimum, maximum, nil
+}
+
+func (dateTimeType) ValidateOptions(options json.RawMessage) error {
+	var opts DateTimeOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	layout, err := opts.layout()
+	if err != nil {
+		return err
+	}
+
+	minimum, maximum, err := opts.bounds(layout)
+	if err != nil {
+		return err
+	}
+
+	if !minimum.IsZero() && !maximum.IsZero() && minimum.After(maximum) {
+		return fmt.Errorf("%w: min is after max", ErrInvalidOptions)
+	}
+
+	return nil
+}
+
+func (dateTimeType) ValidateAnswer(options json.RawMessage, answer json.RawMessage) error {
+	var opts DateTimeOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	layout, err := opts.layout()
+	if err != nil {
+		return err
+	}
+
+	var value string
+	if err = decodeAnswer(answer, &value); err != nil {
+		return err
+	}
+
+	if value == "" {
+		if opts.Required {
+			return fmt.Errorf("%w: answer is required", ErrInvalidAnswer)
+		}
+		return nil
+	}
+
+	at, err := time.Parse(layout, value)
+	if err != nil {
+		return fmt.Errorf("%w: expected %s in layout %s", ErrInvalidAnswer, opts.Mode, layout)
+	}
+
+	minimum, maximum, err := opts.bounds(layout)
+	if err != nil {
+		return err
+	}
+
+	if !minimum.IsZero() && at.Before(minimum) {
+		return fmt.Errorf("%w: before %s", ErrInvalidAnswer, opts.Min)
+	}
+
+	if !maximum.IsZero() && at.After(maximum) {
+		return fmt.Errorf("%w: after %s", ErrInvalidAnswer, opts.Max)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+func init() {
+	Register(fileType{})
+}
+
+// FILE_TYPE is the name of the file-upload question type
+const FILE_TYPE = "file"
+
+type (
+	// fileType is answered with uploads made through presigned URLs
+	fileType struct{}
+
+	// FileOptions are the options of a file-upload question
+	FileOptions struct {
+		Required  bool     `json:"required"`
+		MaxSize   int64    `json:"max_size"`   // Max size of a single file in bytes, 0 means unlimited
+		MimeTypes []string `json:"mime_types"` // Accepted types, e.g. "application/pdf" or "image/*"; empty accepts any
+		MaxFiles  int      `json:"max_files"`  // Max files per answer, 0 means 1
+	}
+)
+
+// ParseFileOptions decodes the options of a file-upload question
+func ParseFileOptions(options json.RawMessage) (FileOptions, error) {
+	var opts FileOptions
+	err := decodeOptions(options, &opts)
+
+	return opts, err
+}
+
+// AllowsSize reports whether a file of size bytes is accepted
+func (o FileOptions) AllowsSize(size int64) bool {
+	return size > 0 && (o.MaxSize == 0 || size <= o.MaxSize)
+}
+
+// AllowsMimeType reports whether contentType is accepted
+func (o FileOptions) AllowsMimeType(contentType string) bool {
+	if len(o.MimeTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range o.MimeTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+			continue
+		}
+
+		if mediaType == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o FileOptions) maxFiles() int {
+	if o.MaxFiles <= 0 {
+		return 1
+	}
+
+	return o.MaxFiles
+}
+
+func (fileType) Name() string { return FILE_TYPE }
+
+func (fileType) OptionSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"required":   map[string]any{"type": "boolean"},
+			"max_size":   map[string]any{"type": "integer", "minimum": 0},
+			"mime_types": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"max_files":  map[string]any{"type": "integer", "minimum": 0},
+		},
+	}
+}
+
+func (fileType) ValidateOptions(options json.RawMessage) error {
+	opts, err := ParseFileOptions(options)
+	if err != nil {
+		return err
+	}
+
+	if opts.MaxSize < 0 || opts.MaxFiles < 0 {
+		return fmt.Errorf("%w: limits can not be negative", ErrInvalidOptions)
+	}
+
+	for _, mimeType := range opts.MimeTypes {
+		if !strings.HasSuffix(mimeType, "/*") {
+			if _, _, err = mime.ParseMediaType(mimeType); err != nil {
+				return fmt.Errorf("%w: mime type %q: %v", ErrInvalidOptions, mimeType, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateAnswer checks the answer is a list of upload IDs within the file
+// count limit. Whether the uploads exist is checked by the service.
+func (fileType) ValidateAnswer(options json.RawMessage, answer json.RawMessage) error {
+	opts, err := ParseFileOptions(options)
+	if err != nil {
+		return err
+	}
+
+	var uploads []uuid.UUID
+	if err = decodeAnswer(answer, &uploads); err != nil {
+		return err
+	}
+
+	if len(uploads) == 0 && opts.Required {
+		return fmt.Errorf("%w: answer is required", ErrInvalidAnswer)
+	}
+
+	if len(uploads) > opts.maxFiles() {
+		return fmt.Errorf("%w: at most %d files", ErrInvalidAnswer, opts.maxFiles())
+	}
+
+	return nil
+}
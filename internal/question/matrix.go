@@ -0,0 +1,140 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+func init() {
+	Register(matrixType{})
+}
+
+type (
+	// matrixType is a grid question: every row is answered with one of the
+	// columns, or several when multiple is set
+	matrixType struct{}
+
+	// MatrixOptions are the options of a matrix question
+	MatrixOptions struct {
+		Required bool     `json:"required"` // Every row must be answered
+		Rows     []string `json:"rows"`
+		Columns  []string `json:"columns"`
+		Multiple bool     `json:"multiple"` // Allow several columns per row
+	}
+
+	// MatrixAnswer maps a row to the picked columns. Single-choice matrices
+	// are answered with one column per row, e.g. {"Speed": "Good"}, and
+	// decode into a one-element slice.
+	MatrixAnswer map[string][]string
+)
+
+func (matrixType) Name() string { return "matrix" }
+
+func (matrixType) OptionSchema() map[string]any {
+	list := map[string]any{
+		"type":     "array",
+		"items":    map[string]any{"type": "string"},
+		"minItems": 1,
+	}
+
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"rows", "columns"},
+		"properties": map[string]any{
+			"required": map[string]any{"type": "boolean"},
+			"rows":     list,
+			"columns":  list,
+			"multiple": map[string]any{"type": "boolean"},
+		},
+	}
+}
+
+func (matrixType) ValidateOptions(options json.RawMessage) error {
+	var opts MatrixOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	if len(opts.Rows) == 0 {
+		return fmt.Errorf("%w: rows can not be empty", ErrInvalidOptions)
+	}
+
+	if len(opts.Columns) == 0 {
+		return fmt.Errorf("%w: columns can not be empty", ErrInvalidOptions)
+	}
+
+	// Answers name rows and columns, so each must be told apart
+	for i, row := range opts.Rows {
+		if slices.Contains(opts.Rows[:i], row) {
+			return fmt.Errorf("%w: duplicate row %q", ErrInvalidOptions, row)
+		}
+	}
+
+	for i, column := range opts.Columns {
+		if slices.Contains(opts.Columns[:i], column) {
+			return fmt.Errorf("%w: duplicate column %q", ErrInvalidOptions, column)
+		}
+	}
+
+	return nil
+}
+
+func (matrixType) ValidateAnswer(options json.RawMessage, answer json.RawMessage) error {
+	var opts MatrixOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	answers, err := decodeMatrixAnswer(answer, opts.Multiple)
+	if err != nil {
+		return err
+	}
+
+	for row, columns := range answers {
+		if !slices.Contains(opts.Rows, row) {
+			return fmt.Errorf("%w: unknown row %q", ErrInvalidAnswer, row)
+		}
+
+		for _, column := range columns {
+			if !slices.Contains(opts.Columns, column) {
+				return fmt.Errorf("%w: unknown column %q in row %q", ErrInvalidAnswer, column, row)
+			}
+		}
+	}
+
+	if opts.Required {
+		for _, row := range opts.Rows {
+			if len(answers[row]) == 0 {
+				return fmt.Errorf("%w: row %q is not answered", ErrInvalidAnswer, row)
+			}
+		}
+	}
+
+	return nil
+}
+
+// decodeMatrixAnswer reads a matrix answer in the shape matching multiple
+func decodeMatrixAnswer(answer json.RawMessage, multiple bool) (MatrixAnswer, error) {
+	if multiple {
+		var answers MatrixAnswer
+		if err := decodeAnswer(answer, &answers); err != nil {
+			return nil, err
+		}
+		return answers, nil
+	}
+
+	var single map[string]string
+	if err := decodeAnswer(answer, &single); err != nil {
+		return nil, err
+	}
+
+	answers := make(MatrixAnswer, len(single))
+	for row, column := range single {
+		if column != "" {
+			answers[row] = []string{column}
+		}
+	}
+
+	return answers, nil
+}
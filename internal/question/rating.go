@@ -0,0 +1,117 @@
+package question
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+func init() {
+	Register(scaleType{})
+}
+
+// Default bounds of a rating scale
+const (
+	RATING_MIN = 1
+	RATING_MAX = 5
+)
+
+// RATING_MAX_STEPS caps the number of points on a rating scale
+const RATING_MAX_STEPS = 100
+
+type (
+	// scaleType rates on an integer scale, like 1 to 5 stars or a 0 to 10
+	// net promoter score
+	scaleType struct{}
+
+	// RatingOptions are the options of a rating question. Min and Max are
+	// inclusive and default to RATING_MIN and RATING_MAX.
+	RatingOptions struct {
+		Required bool   `json:"required"`
+		Min      *int   `json:"min,omitempty"`
+		Max      *int   `json:"max,omitempty"`
+		MinLabel string `json:"min_label,omitempty"` // Shown next to the lowest point, like "Unlikely"
+		MaxLabel string `json:"max_label,omitempty"` // Shown next to the highest point, like "Very likely"
+	}
+)
+
+// bounds returns the scale with defaults applied
+func (opts RatingOptions) bounds() (minimum, maximum int) {
+	minimum, maximum = RATING_MIN, RATING_MAX
+	if opts.Min != nil {
+		minimum = *opts.Min
+	}
+	if opts.Max != nil {
+		maximum = *opts.Max
+	}
+
+	return minimum, maximum
+}
+
+func (scaleType) Name() string { return "rating" }
+
+func (scaleType) OptionSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"required":  map[string]any{"type": "boolean"},
+			"min":       map[string]any{"type": "integer", "default": RATING_MIN},
+			"max":       map[string]any{"type": "integer", "default": RATING_MAX},
+			"min_label": map[string]any{"type": "string"},
+			"max_label": map[string]any{"type": "string"},
+		},
+	}
+}
+
+func (scaleType) ValidateOptions(options json.RawMessage) error {
+	var opts RatingOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	minimum, maximum := opts.bounds()
+	if minimum >= maximum {
+		return fmt.Errorf("%w: min must be less than max", ErrInvalidOptions)
+	}
+
+	if maximum-minimum >= RATING_MAX_STEPS {
+		return fmt.Errorf("%w: scale has more than %d points", ErrInvalidOptions, RATING_MAX_STEPS)
+	}
+
+	return nil
+}
+
+func (scaleType) ValidateAnswer(options json.RawMessage, answer json.RawMessage) error {
+	var opts RatingOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return err
+	}
+
+	var rating *int
+	if err := decodeAnswer(answer, &rating); err != nil {
+		return err
+	}
+
+	if rating == nil {
+		if opts.Required {
+			return fmt.Errorf("%w: answer is required", ErrInvalidAnswer)
+		}
+		return nil
+	}
+
+	minimum, maximum := opts.bounds()
+	if *rating < minimum || *rating > maximum {
+		return fmt.Errorf("%w: rating must be between %d and %d", ErrInvalidAnswer, minimum, maximum)
+	}
+
+	return nil
+}
+
+func (scaleType) Buckets(_ json.RawMessage, answer json.RawMessage) []string {
+	var rating *int
+	if json.Unmarshal(answer, &rating) != nil || rating == nil {
+		return nil
+	}
+
+	return []string{strconv.Itoa(*rating)}
+}
@@ -0,0 +1,179 @@
+// Package question makes question types pluggable. Every type describes its
+// options, validates them when a question is created and validates answers
+// given to questions of that type. Built-in types register themselves at
+// init; new types are added with Register without touching the service.
+package question
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+)
+
+// DEFAULT_TYPE is assumed for questions that don't name a type
+const DEFAULT_TYPE = "text"
+
+var (
+	ErrUnknownType    = errors.New("unknown question type")
+	ErrDuplicateType  = errors.New("question type already registered")
+	ErrInvalidOptions = errors.New("invalid question options")
+	ErrInvalidAnswer  = errors.New("invalid answer")
+	ErrInvalidDefault = errors.New("invalid default value")
+)
+
+type (
+	// Type is a question type plugin
+	Type interface {
+		// Name is the identifier stored in entity.Question.Type
+		Name() string
+
+		// OptionSchema returns a JSON Schema describing the options of the type
+		OptionSchema() map[string]any
+
+		// ValidateOptions checks the options of a question. Options may be empty.
+		ValidateOptions(options json.RawMessage) error
+
+		// ValidateAnswer checks an answer against the question options
+		ValidateAnswer(options json.RawMessage, answer json.RawMessage) error
+	}
+
+	// Registry holds the known question types
+	Registry struct {
+		mu    sync.RWMutex
+		types map[string]Type
+	}
+)
+
+// Default is the registry built-in types register with
+var Default = NewRegistry()
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		types: make(map[string]Type),
+	}
+}
+
+// Register adds t to the registry. Registering a name twice is an error.
+func (r *Registry) Register(t Type) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.types[t.Name()]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateType, t.Name())
+	}
+
+	r.types[t.Name()] = t
+
+	return nil
+}
+
+// Lookup returns the type registered under name. An empty name resolves to DEFAULT_TYPE.
+func (r *Registry) Lookup(name string) (Type, error) {
+	if name == "" {
+		name = DEFAULT_TYPE
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.types[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, name)
+	}
+
+	return t, nil
+}
+
+// Names returns the registered type names, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ValidateQuestion checks that the question has a known type with valid
+// options, well-formed validation rules and a default value that is a valid
+// answer to it
+func (r *Registry) ValidateQuestion(q *entity.Question) error {
+	t, err := r.Lookup(q.Type)
+	if err != nil {
+		return err
+	}
+
+	if err = t.ValidateOptions(q.Options); err != nil {
+		return fmt.Errorf("question %d: %w", q.OrderNumber, err)
+	}
+
+	if err = q.ValidateRules(); err != nil {
+		return err
+	}
+
+	if !q.HasDefault() {
+		return nil
+	}
+
+	if err = t.ValidateAnswer(q.Options, q.DefaultValue); err != nil {
+		return fmt.Errorf("question %d: %w: %v", q.OrderNumber, ErrInvalidDefault, err)
+	}
+
+	if q.Rules != nil {
+		if err = q.Rules.Check(q.DefaultValue); err != nil {
+			return fmt.Errorf("question %d: %w: %v", q.OrderNumber, ErrInvalidDefault, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAnswer checks an answer to the question
+func (r *Registry) ValidateAnswer(q *entity.Question, answer json.RawMessage) error {
+	t, err := r.Lookup(q.Type)
+	if err != nil {
+		return err
+	}
+
+	return t.ValidateAnswer(q.Options, answer)
+}
+
+// Register adds t to the Default registry and panics on a duplicate name.
+// It is meant to be called from init functions.
+func Register(t Type) {
+	if err := Default.Register(t); err != nil {
+		panic(err)
+	}
+}
+
+// decodeOptions unmarshals options into v, leaving v untouched when options are empty
+func decodeOptions(options json.RawMessage, v any) error {
+	if len(options) == 0 || string(options) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(options, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOptions, err)
+	}
+
+	return nil
+}
+
+// decodeAnswer unmarshals answer into v
+func decodeAnswer(answer json.RawMessage, v any) error {
+	if err := json.Unmarshal(answer, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAnswer, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package question
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliderType is a minimal plugin used to check that new types plug in
+type sliderType struct{ textType }
+
+func (sliderType) Name() string { return "slider" }
+
+func TestRegistry(t *testing.T) {
+	t.Run("built-in types are registered", func(t *testing.T) {
+		assert.Equal(t, []string{"choice", "datetime", "file", "matrix", "number", "rating", "text"}, Default.Names())
+	})
+
+	t.Run("empty type resolves to text", func(t *testing.T) {
+		typ, err := Default.Lookup("")
+
+		require.NoError(t, err)
+		assert.Equal(t, DEFAULT_TYPE, typ.Name())
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := Default.Lookup("hologram")
+
+		assert.ErrorIs(t, err, ErrUnknownType)
+	})
+
+	t.Run("custom type registers once", func(t *testing.T) {
+		registry := NewRegistry()
+
+		require.NoError(t, registry.Register(sliderType{}))
+		assert.ErrorIs(t, registry.Register(sliderType{}), ErrDuplicateType)
+
+		err := registry.ValidateQuestion(&entity.Question{Type: "slider"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestRegistry_ValidateQuestion(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		options string
+		def     string
+		wantErr error
+	}{
+		{name: "text without options", typ: "text"},
+		{name: "text default", typ: "text", def: `"n/a"`},
+		{name: "null default", typ: "number", def: `null`},
+		{name: "choice default", typ: "choice", options: `{"choices": ["a", "b"]}`, def: `"b"`},
+		{name: "default not a choice", typ: "choice", options: `{"choices": ["a", "b"]}`, def: `"c"`, wantErr: ErrInvalidDefault},
+		{name: "default of the wrong type", typ: "number", def: `"five"`, wantErr: ErrInvalidDefault},
+		{name: "text default too long", typ: "text", options: `{"max_length": 2}`, def: `"long"`, wantErr: ErrInvalidDefault},
+		{name: "negative max length", typ: "text", options: `{"max_length": -1}`, wantErr: ErrInvalidOptions},
+		{name: "number bounds", typ: "number", options: `{"min": 1, "max": 5}`},
+		{name: "number min above max", typ: "number", options: `{"min": 5, "max": 1}`, wantErr: ErrInvalidOptions},
+		{name: "choice", typ: "choice", options: `{"choices": ["a", "b"]}`},
+		{name: "choice without choices", typ: "choice", wantErr: ErrInvalidOptions},
+		{name: "duplicate choice", typ: "choice", options: `{"choices": ["a", "a"]}`, wantErr: ErrInvalidOptions},
+		{name: "malformed options", typ: "choice", options: `{"choices": "a"}`, wantErr: ErrInvalidOptions},
+		{name: "matrix", typ: "matrix", options: `{"rows": ["Speed"], "columns": ["Bad", "Good"]}`},
+		{name: "matrix without columns", typ: "matrix", options: `{"rows": ["Speed"]}`, wantErr: ErrInvalidOptions},
+		{name: "matrix without rows", typ: "matrix", options: `{"rows": [], "columns": ["Good"]}`, wantErr: ErrInvalidOptions},
+		{name: "duplicate matrix row", typ: "matrix", options: `{"rows": ["Speed", "Speed"], "columns": ["Good"]}`, wantErr: ErrInvalidOptions},
+		{name: "duplicate matrix column", typ: "matrix", options: `{"rows": ["Speed"], "columns": ["Good", "Good"]}`, wantErr: ErrInvalidOptions},
+		{name: "date defaults", typ: "datetime"},
+		{name: "time bounds", typ: "datetime", options: `{"mode": "time", "min": "09:00", "max": "18:00"}`},
+		{name: "unknown date mode", typ: "datetime", options: `{"mode": "week"}`, wantErr: ErrInvalidOptions},
+		{name: "bound in wrong layout", typ: "datetime", options: `{"min": "09:00"}`, wantErr: ErrInvalidOptions},
+		{name: "date min after max", typ: "datetime", options: `{"min": "2025-02-01", "max": "2025-01-01"}`, wantErr: ErrInvalidOptions},
+		{name: "file limits", typ: "file", options: `{"max_size": 1048576, "mime_types": ["image/*", "application/pdf"]}`},
+		{name: "negative file size", typ: "file", options: `{"max_size": -1}`, wantErr: ErrInvalidOptions},
+		{name: "malformed mime type", typ: "file", options: `{"mime_types": ["not a type;;"]}`, wantErr: ErrInvalidOptions},
+		{name: "rating defaults", typ: "rating"},
+		{name: "rating scale", typ: "rating", options: `{"min": 0, "max": 10, "min_label": "Unlikely"}`},
+		{name: "rating min above max", typ: "rating", options: `{"min": 5, "max": 1}`, wantErr: ErrInvalidOptions},
+		{name: "rating too many points", typ: "rating", options: `{"min": 0, "max": 1000}`, wantErr: ErrInvalidOptions},
+		{name: "unknown type", typ: "hologram", wantErr: ErrUnknownType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &entity.Question{Type: tt.typ}
+			if tt.options != "" {
+				q.Options = json.RawMessage(tt.options)
+			}
+			if tt.def != "" {
+				q.DefaultValue = json.RawMessage(tt.def)
+			}
+
+			err := Default.ValidateQuestion(q)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_ValidateQuestion_DefaultRules(t *testing.T) {
+	min := 3.0
+	q := &entity.Question{
+		Type:         "number",
+		DefaultValue: json.RawMessage(`1`),
+		Rules:        &entity.ValidationRules{Min: &min},
+	}
+
+	assert.ErrorIs(t, Default.ValidateQuestion(q), ErrInvalidDefault, "defaults follow the rules of answers")
+
+	q.DefaultValue = json.RawMessage(`4`)
+	assert.NoError(t, Default.ValidateQuestion(q))
+}
+
+func TestRegistry_ValidateAnswer(t *testing.T) {
+	matrix := `{"rows": ["Speed", "Price"], "columns": ["Bad", "Good"]}`
+
+	tests := []struct {
+		name    string
+		typ     string
+		options string
+		answer  string
+		wantErr bool
+	}{
+		{name: "text", typ: "text", answer: `"hello"`},
+		{name: "text too long", typ: "text", options: `{"max_length": 3}`, answer: `"hello"`, wantErr: true},
+		{name: "required text empty", typ: "text", options: `{"required": true}`, answer: `""`, wantErr: true},
+		{name: "text given a number", typ: "text", answer: `42`, wantErr: true},
+		{name: "number in range", typ: "number", options: `{"min": 1, "max": 5}`, answer: `3`},
+		{name: "number out of range", typ: "number", options: `{"min": 1, "max": 5}`, answer: `6`, wantErr: true},
+		{name: "optional number skipped", typ: "number", answer: `null`},
+		{name: "single choice", typ: "choice", options: `{"choices": ["a", "b"]}`, answer: `"a"`},
+		{name: "unknown choice", typ: "choice", options: `{"choices": ["a", "b"]}`, answer: `"c"`, wantErr: true},
+		{name: "multiple choices", typ: "choice", options: `{"choices": ["a", "b"], "multiple": true}`, answer: `["a", "b"]`},
+		{name: "matrix", typ: "matrix", options: matrix, answer: `{"Speed": "Good", "Price": "Bad"}`},
+		{name: "matrix unknown column", typ: "matrix", options: matrix, answer: `{"Speed": "Great"}`, wantErr: true},
+		{name: "matrix unknown row", typ: "matrix", options: matrix, answer: `{"Color": "Good"}`, wantErr: true},
+		{name: "required matrix row missing", typ: "matrix", options: `{"rows": ["Speed", "Price"], "columns": ["Good"], "required": true}`, answer: `{"Speed": "Good"}`, wantErr: true},
+		{name: "multiple matrix", typ: "matrix", options: `{"rows": ["Speed"], "columns": ["Bad", "Good"], "multiple": true}`, answer: `{"Speed": ["Bad", "Good"]}`},
+		{name: "date", typ: "datetime", answer: `"2025-01-31"`},
+		{name: "malformed date", typ: "datetime", answer: `"31.01.2025"`, wantErr: true},
+		{name: "date before min", typ: "datetime", options: `{"min": "2025-01-01"}`, answer: `"2024-12-31"`, wantErr: true},
+		{name: "time in range", typ: "datetime", options: `{"mode": "time", "min": "09:00", "max": "18:00"}`, answer: `"12:30"`},
+		{name: "time after max", typ: "datetime", options: `{"mode": "time", "max": "18:00"}`, answer: `"18:01"`, wantErr: true},
+		{name: "datetime", typ: "datetime", options: `{"mode": "datetime"}`, answer: `"2025-01-31T12:00:00Z"`},
+		{name: "required date empty", typ: "datetime", options: `{"required": true}`, answer: `""`, wantErr: true},
+		{name: "file upload ids", typ: "file", options: `{"max_files": 2}`, answer: `["` + uuid.NewString() + `"]`},
+		{name: "too many files", typ: "file", answer: `["` + uuid.NewString() + `", "` + uuid.NewString() + `"]`, wantErr: true},
+		{name: "file answer not an id", typ: "file", answer: `["cat.png"]`, wantErr: true},
+		{name: "rating", typ: "rating", answer: `4`},
+		{name: "rating above default max", typ: "rating", answer: `6`, wantErr: true},
+		{name: "rating on custom scale", typ: "rating", options: `{"min": 0, "max": 10}`, answer: `0`},
+		{name: "fractional rating", typ: "rating", answer: `2.5`, wantErr: true},
+		{name: "required rating missing", typ: "rating", options: `{"required": true}`, answer: `null`, wantErr: true},
+		{name: "required choice missing", typ: "choice", options: `{"choices": ["a"], "required": true, "multiple": true}`, answer: `[]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &entity.Question{Type: tt.typ}
+			if tt.options != "" {
+				q.Options = json.RawMessage(tt.options)
+			}
+
+			err := Default.ValidateAnswer(q, json.RawMessage(tt.answer))
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAnswer)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFileOptions_AllowsMimeType(t *testing.T) {
+	opts := FileOptions{MimeTypes: []string{"image/*", "application/pdf"}}
+
+	assert.True(t, opts.AllowsMimeType("image/png"))
+	assert.True(t, opts.AllowsMimeType("application/pdf; charset=binary"))
+	assert.False(t, opts.AllowsMimeType("imagefoo/png"))
+	assert.False(t, opts.AllowsMimeType("text/plain"))
+	assert.True(t, FileOptions{}.AllowsMimeType("text/plain"))
+}
+
+func TestRegistry_Buckets(t *testing.T) {
+	multiple := &entity.Question{Type: "choice", Options: json.RawMessage(`{"choices":["a","b","c"],"multiple":true}`)}
+	single := &entity.Question{Type: "choice", Options: json.RawMessage(`{"choices":["a","b"]}`)}
+
+	assert.Equal(t, []string{"a", "c"}, Default.Buckets(multiple, json.RawMessage(`["a","c"]`)))
+	assert.Equal(t, []string{"b"}, Default.Buckets(single, json.RawMessage(`"b"`)))
+	assert.Empty(t, Default.Buckets(single, json.RawMessage(`""`)))
+	assert.Equal(t, []string{"4.5"}, Default.Buckets(&entity.Question{Type: "number"}, json.RawMessage(`4.5`)))
+	assert.Equal(t, []string{"4"}, Default.Buckets(&entity.Question{Type: "rating"}, json.RawMessage(`4`)))
+	assert.Nil(t, Default.Buckets(&entity.Question{Type: "text"}, json.RawMessage(`"free text"`)), "text keeps no distribution")
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetCollaborator retrieves the collaborator entry of a user on a form
+// Returns gorm.ErrRecordNotFound if the user isn't a collaborator of the form
+func (repo *Repository) GetCollaborator(formID uuid.UUID, userID string) (*entity.Collaborator, error) {
+	var collaborator entity.Collaborator
+
+	res := repo.db.Where("form_id = ? AND user_id = ?", formID, userID).First(&collaborator)
+	if err := res.Error; err != nil {
+		return nil, err
+	}
+
+	return &collaborator, nil
+}
+
+// SaveCollaborator adds a collaborator to a form, or changes the role of an
+// existing one
+// Parameters:
+//   - collaborator: Form, user and role to store
+//
+// Returns error if the save fails
+func (repo *Repository) SaveCollaborator(collaborator *entity.Collaborator) error {
+	res := repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "form_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(collaborator)
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error save collaborator",
+			zap.String("form_id", collaborator.FormID.String()),
+			zap.String("user_id", collaborator.UserID),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCollaborator removes a collaborator from a form
+// Returns:
+//   - *entity.Collaborator: The removed collaborator
+//   - error: gorm.ErrRecordNotFound if the user isn't a collaborator of the form
+func (repo *Repository) DeleteCollaborator(formID uuid.UUID, userID string) (*entity.Collaborator, error) {
+	var removed entity.Collaborator
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("form_id = ? AND user_id = ?", formID, userID).First(&removed).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("form_id = ? AND user_id = ?", formID, userID).Delete(&entity.Collaborator{}).Error
+	})
+	if err != nil {
+		repo.logger.Error("error delete collaborator",
+			zap.String("form_id", formID.String()),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &removed, nil
+}
+
+// ListCollaborators returns the collaborators of a form, by user ID
+func (repo *Repository) ListCollaborators(formID uuid.UUID) ([]entity.Collaborator, error) {
+	var collaborators []entity.Collaborator
+
+	res := repo.db.Where("form_id = ?", formID).Order("user_id").Find(&collaborators)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error list collaborators",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return collaborators, nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kinds of filterable fields, deciding how filter values are decoded
+const (
+	filterString = iota
+	filterBool
+	filterTime
+)
+
+// likeEscape escapes the wildcards of "contains" values. It is not a
+// backslash, whose meaning in string literals differs between databases.
+const likeEscape = "!"
+
+type (
+	// filterField is a field listings may be filtered by
+	filterField struct {
+		column string
+		kind   int
+		ops    []string
+	}
+
+	// filterFields allow-lists the fields of a listing by their public name
+	filterFields map[string]filterField
+)
+
+// Operators allowed on fields, by what the field holds
+var (
+	equalityOps   = []string{entity.FilterEq, entity.FilterNe, entity.FilterIn}
+	textOps       = []string{entity.FilterEq, entity.FilterNe, entity.FilterIn, entity.FilterContains}
+	flagOps       = []string{entity.FilterEq, entity.FilterNe}
+	comparisonOps = []string{entity.FilterEq, entity.FilterNe, entity.FilterLt, entity.FilterLte, entity.FilterGt, entity.FilterGte}
+)
+
+// formFilter lists the fields forms can be filtered by
+var formFilter = filterFields{
+	"title":       {column: "title", kind: filterString, ops: textOps},
+	"author":      {column: "author", kind: filterString, ops: equalityOps},
+	"status":      {column: "status", kind: filterString, ops: equalityOps},
+	"invite_only": {column: "invite_only", kind: filterBool, ops: flagOps},
+	"numbering":   {column: "numbering", kind: filterString, ops: equalityOps},
+	"created_at":  {column: "created_at", kind: filterTime, ops: comparisonOps},
+}
+
+// responseFilter lists the fields responses can be filtered by
+var responseFilter = filterFields{
+	"respondent_id": {column: "respondent_id", kind: filterString, ops: equalityOps},
+	"email":         {column: "email", kind: filterString, ops: textOps},
+	"created_at":    {column: "created_at", kind: filterTime, ops: comparisonOps},
+}
+
+// whereFilter narrows query down to the rows matching filter, nil matching
+// every row. Fields are looked up in fields, so only allow-listed columns
+// reach the query and values are always bound as parameters.
+// Returns entity.ErrInvalidFilter for a malformed filter or fields and
+// operators fields doesn't allow
+func whereFilter(query *gorm.DB, filter *entity.Filter, fields filterFields) (*gorm.DB, error) {
+	if filter == nil {
+		return query, nil
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	expr, err := fields.expression(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return query.Where(expr), nil
+}
+
+// expression translates a validated filter to a GORM condition
+func (fields filterFields) expression(filter *entity.Filter) (clause.Expression, error) {
+	if filter.IsGroup() {
+		group := filter.And
+		if filter.Or != nil {
+			group = filter.Or
+		}
+
+		exprs := make([]clause.Expression, 0, len(group))
+		for i := range group {
+			expr, err := fields.expression(&group[i])
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		}
+
+		if filter.Or != nil {
+			return clause.Or(exprs...), nil
+		}
+
+		return clause.And(exprs...), nil
+	}
+
+	field, ok := fields[filter.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %q", entity.ErrInvalidFilter, filter.Field)
+	}
+
+	if !slices.Contains(field.ops, filter.Op) {
+		return nil, fmt.Errorf("%w: %q isn't allowed on %s", entity.ErrInvalidFilter, filter.Op, filter.Field)
+	}
+
+	column := clause.Column{Table: clause.CurrentTable, Name: field.column}
+
+	if filter.Op == entity.FilterIn {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(filter.Value, &raw); err != nil {
+			return nil, fmt.Errorf("%w: %q of %s takes an array", entity.ErrInvalidFilter, filter.Op, filter.Field)
+		}
+
+		values := make([]any, 0, len(raw))
+		for _, data := range raw {
+			value, err := field.decode(filter.Field, data)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+
+		return clause.IN{Column: column, Values: values}, nil
+	}
+
+	value, err := field.decode(filter.Field, filter.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filter.Op {
+	case entity.FilterEq:
+		return clause.Eq{Column: column, Value: value}, nil
+	case entity.FilterNe:
+		return clause.Neq{Column: column, Value: value}, nil
+	case entity.FilterLt:
+		return clause.Lt{Column: column, Value: value}, nil
+	case entity.FilterLte:
+		return clause.Lte{Column: column, Value: value}, nil
+	case entity.FilterGt:
+		return clause.Gt{Column: column, Value: value}, nil
+	case entity.FilterGte:
+		return clause.Gte{Column: column, Value: value}, nil
+	case entity.FilterContains:
+		pattern := "%" + escapeLike(value.(string)) + "%"
+		return clause.Expr{SQL: "? LIKE ? ESCAPE '" + likeEscape + "'", Vars: []any{column, pattern}}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown operator %q of %s", entity.ErrInvalidFilter, filter.Op, filter.Field)
+}
+
+// decode decodes a filter value of the field named name by its kind: a
+// string, a bool, or an RFC 3339 time
+func (field filterField) decode(name string, data json.RawMessage) (any, error) {
+	switch field.kind {
+	case filterBool:
+		var value bool
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("%w: %s takes a bool", entity.ErrInvalidFilter, name)
+		}
+		return value, nil
+	case filterTime:
+		var value time.Time
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("%w: %s takes an RFC 3339 time", entity.ErrInvalidFilter, name)
+		}
+		return value, nil
+	default:
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("%w: %s takes a string", entity.ErrInvalidFilter, name)
+		}
+		return value, nil
+	}
+}
+
+// escapeLike escapes the LIKE wildcards of s, so they match themselves
+func escapeLike(s string) string {
+	return strings.NewReplacer(
+		likeEscape, likeEscape+likeEscape,
+		"%", likeEscape+"%",
+		"_", likeEscape+"_",
+	).Replace(s)
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ListFormsByTag_Filter(t *testing.T) {
+	repo, _ := setupRepository(t)
+	start := time.Now().UTC().Truncate(time.Second)
+
+	titles := []string{"Onboarding survey", "Exit survey", "100% feedback", "Lunch poll"}
+	ids := make(map[string]uuid.UUID, len(titles))
+	for i, title := range titles {
+		form := &entity.Form{
+			ID:        uuid.New(),
+			Title:     title,
+			Author:    "author",
+			Status:    entity.StatusPublished,
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+		}
+		if i%2 == 1 {
+			form.Status = entity.StatusClosed
+		}
+		require.NoError(t, repo.Create(form))
+		require.NoError(t, repo.SetFormTags(form.ID, []entity.Tag{{Name: "hr"}}))
+		ids[title] = form.ID
+	}
+
+	cond := func(field, op string, value any) entity.Filter {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		return entity.Filter{Field: field, Op: op, Value: data}
+	}
+
+	tests := []struct {
+		name   string
+		filter entity.Filter
+		want   []string
+	}{
+		{"contains", cond("title", entity.FilterContains, "survey"), []string{"Onboarding survey", "Exit survey"}},
+		{"contains wildcards literally", cond("title", entity.FilterContains, "0%"), []string{"100% feedback"}},
+		{"underscore isn't a wildcard", cond("title", entity.FilterContains, "_"), nil},
+		{"status", cond("status", entity.FilterEq, entity.StatusClosed), []string{"Exit survey", "Lunch poll"}},
+		{"bool", cond("invite_only", entity.FilterEq, false), titles},
+		{"in", cond("title", entity.FilterIn, []string{"Lunch poll", "Exit survey"}), []string{"Exit survey", "Lunch poll"}},
+		{"time", cond("created_at", entity.FilterGte, start.Add(2*time.Hour)), []string{"100% feedback", "Lunch poll"}},
+		{"and", entity.Filter{And: []entity.Filter{
+			cond("title", entity.FilterContains, "survey"),
+			cond("status", entity.FilterNe, entity.StatusClosed),
+		}}, []string{"Onboarding survey"}},
+		{"or of and", entity.Filter{Or: []entity.Filter{
+			{And: []entity.Filter{
+				cond("title", entity.FilterContains, "survey"),
+				cond("status", entity.FilterEq, entity.StatusClosed),
+			}},
+			cond("created_at", entity.FilterLt, start.Add(time.Minute)),
+		}}, []string{"Onboarding survey", "Exit survey"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forms, err := repo.ListFormsByTag("", "hr", &tt.filter, 0, 10)
+			require.NoError(t, err)
+
+			var want []uuid.UUID
+			for _, title := range tt.want {
+				want = append(want, ids[title])
+			}
+			var got []uuid.UUID
+			for _, form := range forms {
+				got = append(got, form.ID)
+			}
+			assert.Equal(t, want, got)
+
+			count, err := repo.CountFormsByTag("", "hr", &tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), count)
+		})
+	}
+
+	t.Run("rejects fields and operators outside the allow-list", func(t *testing.T) {
+		for _, filter := range []entity.Filter{
+			cond("tenant_id", entity.FilterEq, "acme"),
+			cond("title; DROP TABLE forms; --", entity.FilterEq, "x"),
+			cond("status", entity.FilterContains, "clo"),
+			cond("created_at", entity.FilterGt, "yesterday"),
+			cond("invite_only", entity.FilterEq, "true"),
+			{Field: "title", Op: "like", Value: json.RawMessage(`"%"`)},
+			{Or: []entity.Filter{}},
+		} {
+			_, err := repo.ListFormsByTag("", "hr", &filter, 0, 10)
+			assert.ErrorIs(t, err, entity.ErrInvalidFilter, "filter %+v", filter)
+		}
+
+		count, err := repo.CountFormsByTag("", "hr", nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(titles)), count)
+	})
+}
+
+func TestRepository_ListResponses_Filter(t *testing.T) {
+	repo, _ := setupRepository(t)
+	formID := uuid.New()
+
+	for _, email := range []string{"ann@example.com", "bob@example.org", "cid@example.com"} {
+		require.NoError(t, repo.Create(&entity.Response{ID: uuid.New(), FormID: formID, RespondentID: email[:3], Email: email}))
+	}
+
+	filter := &entity.Filter{Or: []entity.Filter{
+		{Field: "email", Op: entity.FilterContains, Value: json.RawMessage(`"example.org"`)},
+		{Field: "respondent_id", Op: entity.FilterEq, Value: json.RawMessage(`"cid"`)},
+	}}
+
+	responses, err := repo.ListResponses(formID, filter, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, responses, 2)
+	assert.Equal(t, "bob", responses[0].RespondentID)
+	assert.Equal(t, "cid", responses[1].RespondentID)
+
+	count, err := repo.CountResponses(formID, filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = repo.ListResponses(formID, &entity.Filter{Field: "answers", Op: entity.FilterContains, Value: json.RawMessage(`"x"`)}, 0, 10)
+	assert.ErrorIs(t, err, entity.ErrInvalidFilter)
+}
@@ -2,13 +2,27 @@
 package repository
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+
 	"github.com/Koyo-os/form-service/internal/entity"
 	"github.com/Koyo-os/form-service/pkg/logger"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// QUESTION_BATCH_SIZE is the number of questions inserted per statement,
+// keeping large imports below the placeholder limits of the databases
+const QUESTION_BATCH_SIZE = 100
+
+// ErrQuestionNotInForm is returned by SaveForm for a question ID that
+// doesn't belong to the saved form
+var ErrQuestionNotInForm = errors.New("question does not belong to form")
+
 // Repository handles database operations using GORM
 type Repository struct {
 	db     *gorm.DB
@@ -23,15 +37,23 @@ func Init(db *gorm.DB, logger *logger.Logger) *Repository {
 	}
 }
 
-// Create persists a new entity in the database
+// Create persists a new entity in the database. Creating a question or
+// section of a form bumps the version of the form in the same transaction.
 // Parameters:
 //   - payload: Any struct that maps to a database table
 //
 // Returns error if the creation fails
 func (repo *Repository) Create(payload any) error {
-	res := repo.db.Create(payload)
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if formID, ok := formOf(payload); ok {
+			if err := bumpVersion(tx, formID, 0); err != nil {
+				return err
+			}
+		}
 
-	if err := res.Error; err != nil {
+		return tx.Create(payload).Error
+	})
+	if err != nil {
 		repo.logger.Error("error create entity", zap.Error(err))
 		return err
 	}
@@ -39,7 +61,20 @@ func (repo *Repository) Create(payload any) error {
 	return nil
 }
 
-// Get retrieves a form by its ID
+// formOf returns the ID of the form payload is part of, for the entities
+// that change the form they're created in
+func formOf(payload any) (uuid.UUID, bool) {
+	switch row := payload.(type) {
+	case *entity.Question:
+		return row.FormID, true
+	case *entity.Section:
+		return row.FormID, true
+	}
+
+	return uuid.Nil, false
+}
+
+// Get retrieves a form by its ID together with its questions and sections, in order
 // Parameters:
 //   - ID: UUID of the form to retrieve
 //
@@ -49,7 +84,12 @@ func (repo *Repository) Create(payload any) error {
 func (repo *Repository) Get(ID uuid.UUID) (*entity.Form, error) {
 	var form entity.Form
 
-	res := repo.db.Where("ID = ?", ID).First(&form)
+	res := repo.db.Preload("Questions", orderQuestions).
+		Preload("Sections", orderSections).
+		Preload("Branches").
+		Preload("Tags", orderTags).
+		Where("ID = ?", ID).
+		First(&form)
 	if err := res.Error; err != nil {
 		repo.logger.Error("error get form",
 			zap.String("form_id", ID.String()),
@@ -61,57 +101,183 @@ func (repo *Repository) Get(ID uuid.UUID) (*entity.Form, error) {
 	return &form, nil
 }
 
-// Update modifies a single column of a form
+// GetBySlug retrieves a form by its slug, see entity.Slugify
 // Parameters:
-//   - ID: UUID of the form to update
-//   - key: Column name to update
-//   - value: New value for the column
+//   - slug: Unique name of the form in URLs
 //
-// Returns error if the update fails
-func (repo *Repository) Update(ID uuid.UUID, key string, value any) error {
-	res := repo.db.Where("ID = ?", ID).Update(key, value)
+// Returns:
+//   - *entity.Form: The form with its questions, sections, branches and tags
+//   - error: gorm.ErrRecordNotFound if no live form has the slug
+func (repo *Repository) GetBySlug(slug string) (*entity.Form, error) {
+	var form entity.Form
 
+	res := repo.db.Preload("Questions", orderQuestions).
+		Preload("Sections", orderSections).
+		Preload("Branches").
+		Preload("Tags", orderTags).
+		Where("slug = ?", slug).
+		First(&form)
 	if err := res.Error; err != nil {
-		repo.logger.Error("error update form",
-			zap.String("form_id", ID.String()),
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			repo.logger.Error("error get form by slug",
+				zap.String("slug", slug),
+				zap.Error(err),
+			)
+		}
+		return nil, err
+	}
+
+	return &form, nil
+}
+
+// SlugExists reports whether a form, live or in the trash, has the slug
+// Parameters:
+//   - slug: Unique name of the form in URLs
+//
+// Returns:
+//   - bool: Whether the slug is taken
+//   - error: Any error that occurred during the lookup
+func (repo *Repository) SlugExists(slug string) (bool, error) {
+	var count int64
+
+	res := repo.db.Unscoped().Model(&entity.Form{}).Where("slug = ?", slug).Count(&count)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error check slug",
+			zap.String("slug", slug),
 			zap.Error(err),
 		)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Update modifies a single column of a form and bumps its version
+// Parameters:
+//   - ID: UUID of the form to update
+//   - version: Version the update was made on, 0 to update whatever the version
+//   - key: Column name to update
+//   - value: New value for the column
+//
+// Returns *entity.VersionConflictError if the form is at another version
+// or error if the update fails
+func (repo *Repository) Update(ID uuid.UUID, version uint, key string, value any) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, ID, version); err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Form{}).Where("ID = ?", ID).Update(key, value).Error
+	})
+	if err != nil {
+		if !isVersionConflict(err) {
+			repo.logger.Error("error update form",
+				zap.String("form_id", ID.String()),
+				zap.Error(err),
+			)
+		}
 		return err
 	}
 
 	return nil
 }
 
-// UpdateMany updates multiple columns of a form simultaneously
+// UpdateMany updates multiple columns of a form simultaneously and bumps
+// its version
 // Parameters:
 //   - ID: UUID of the form to update
+//   - version: Version the update was made on, 0 to update whatever the version
 //   - value: Struct containing the columns and values to update
 //
-// Returns error if the update fails
-func (repo *Repository) UpdateMany(ID uuid.UUID, value any) error {
-	res := repo.db.Where("ID = ?", ID).Updates(value)
+// Returns *entity.VersionConflictError if the form is at another version
+// or error if the update fails
+func (repo *Repository) UpdateMany(ID uuid.UUID, version uint, value any) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, ID, version); err != nil {
+			return err
+		}
 
-	if err := res.Error; err != nil {
-		repo.logger.Error("error update many",
-			zap.String("id", ID.String()),
-			zap.Error(err))
+		return tx.Model(&entity.Form{}).Where("ID = ?", ID).Omit("Version").Updates(value).Error
+	})
+	if err != nil {
+		if !isVersionConflict(err) {
+			repo.logger.Error("error update many",
+				zap.String("id", ID.String()),
+				zap.Error(err))
+		}
 		return err
 	}
 
 	return nil
 }
 
-// UpdateQuestion modifies a single column of a question
+// bumpVersion increments the version of a form within tx, provided it still
+// is at version; version 0 skips the check. The row stays locked until tx
+// ends, so concurrent updates made on the same version can't both pass.
+// Returns *entity.VersionConflictError if the form is at another version
+func bumpVersion(tx *gorm.DB, ID uuid.UUID, version uint) error {
+	query := tx.Model(&entity.Form{}).Where("ID = ?", ID)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	res := query.UpdateColumn("version", gorm.Expr("version + 1"))
+	if err := res.Error; err != nil {
+		return err
+	}
+
+	if res.RowsAffected > 0 || version == 0 {
+		return nil
+	}
+
+	var current entity.Form
+	if err := tx.Select("version").Where("ID = ?", ID).First(&current).Error; err != nil {
+		return err
+	}
+
+	return &entity.VersionConflictError{FormID: ID, Expected: version, Actual: current.Version}
+}
+
+// bumpVersionOf bumps the version of the form the row of model with the
+// given ID, e.g. a question, is part of within tx, see bumpVersion
+// Returns gorm.ErrRecordNotFound if there is no such row
+func bumpVersionOf(tx *gorm.DB, model any, id uuid.UUID) error {
+	var formIDs []uuid.UUID
+	if err := tx.Model(model).Where("id = ?", id).Limit(1).Pluck("form_id", &formIDs).Error; err != nil {
+		return err
+	}
+
+	if len(formIDs) == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return bumpVersion(tx, formIDs[0], 0)
+}
+
+// isVersionConflict reports whether err is a version conflict, which is
+// the caller's to resolve rather than a failure worth logging
+func isVersionConflict(err error) bool {
+	var conflict *entity.VersionConflictError
+	return errors.As(err, &conflict)
+}
+
+// UpdateQuestion modifies a single column of a question and bumps the
+// version of its form
 // Parameters:
 //   - id: UUID of the question to update
 //   - key: Column name to update
 //   - value: New value for the column
 //
-// Returns error if the update fails
+// Returns error if the update fails or no such question exists
 func (repo *Repository) UpdateQuestion(id uuid.UUID, key string, value any) error {
-	res := repo.db.Where("ID = ?", id).Update(key, value)
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersionOf(tx, &entity.Question{}, id); err != nil {
+			return err
+		}
 
-	if err := res.Error; err != nil {
+		return tx.Model(&entity.Question{}).Where("ID = ?", id).Update(key, value).Error
+	})
+	if err != nil {
 		repo.logger.Error("error update question",
 			zap.String("column", key),
 			zap.String("question_id", id.String()),
@@ -123,15 +289,21 @@ func (repo *Repository) UpdateQuestion(id uuid.UUID, key string, value any) erro
 }
 
 // UpdateQuestionMany updates multiple columns of a question simultaneously
+// and bumps the version of its form
 // Parameters:
 //   - id: UUID of the question to update
 //   - value: Struct containing the columns and values to update
 //
-// Returns error if the update fails
+// Returns error if the update fails or no such question exists
 func (repo *Repository) UpdateQuestionMany(id uuid.UUID, value any) error {
-	res := repo.db.Where("ID = ?", id).Updates(value)
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersionOf(tx, &entity.Question{}, id); err != nil {
+			return err
+		}
 
-	if err := res.Error; err != nil {
+		return tx.Model(&entity.Question{}).Where("ID = ?", id).Updates(value).Error
+	})
+	if err != nil {
 		repo.logger.Error("error update question many",
 			zap.String("question_id", id.String()),
 			zap.Error(err))
@@ -141,7 +313,8 @@ func (repo *Repository) UpdateQuestionMany(id uuid.UUID, value any) error {
 	return nil
 }
 
-// DeleteForm removes a form from the database
+// DeleteForm moves a form to the trash. Trashed forms are hidden from every
+// other query until restored with RestoreForm or removed with PurgeForm.
 // Parameters:
 //   - formID: UUID of the form to delete
 //
@@ -162,19 +335,104 @@ func (repo *Repository) DeleteForm(formID uuid.UUID) error {
 	return nil
 }
 
-// DeleteQuestion removes a question from a form
+// GetTrashedForm retrieves a form from the trash with its questions
+// Returns gorm.ErrRecordNotFound if the form isn't in the trash
+func (repo *Repository) GetTrashedForm(formID uuid.UUID) (*entity.Form, error) {
+	var form entity.Form
+
+	res := repo.db.Unscoped().
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return orderQuestions(db.Where("deleted_at IS NULL"))
+		}).
+		Where("id = ? AND deleted_at IS NOT NULL", formID).
+		First(&form)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error get trashed form",
+			zap.String("form_id", formID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return &form, nil
+}
+
+// RestoreForm takes a form out of the trash
+// Returns gorm.ErrRecordNotFound if the form isn't in the trash
+func (repo *Repository) RestoreForm(formID uuid.UUID) error {
+	res := repo.db.Unscoped().Model(&entity.Form{}).
+		Where("id = ? AND deleted_at IS NOT NULL", formID).
+		Update("deleted_at", nil)
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error restore form",
+			zap.String("form_id", formID.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// PurgeForm permanently removes a form from the trash, together with its
+// questions, sections, branches, tags, collaborators and webhooks
+// Returns gorm.ErrRecordNotFound if the form isn't in the trash
+func (repo *Repository) PurgeForm(formID uuid.UUID) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Select(clause.Associations).
+			Where("deleted_at IS NOT NULL").
+			Delete(&entity.Form{ID: formID})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("form_id = ?", formID).Delete(&entity.Collaborator{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("form_id = ?", formID).Delete(&entity.Webhook{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("form_id = ?", formID).Delete(&entity.FormStats{}).Error
+	})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		repo.logger.Error("error purge form",
+			zap.String("form_id", formID.String()),
+			zap.Error(err),
+		)
+	}
+
+	return err
+}
+
+// DeleteQuestion removes a question from a form and bumps its version
 // Parameters:
 //   - formID: UUID of the form containing the question
 //   - orderNumber: Position of the question in the form
 //
 // Returns error if the deletion fails
 func (repo *Repository) DeleteQuestion(formID uuid.UUID, orderNumber uint) error {
-	res := repo.db.Where(&entity.Question{
-		FormID:      formID,
-		OrderNumber: orderNumber,
-	}).Delete(&entity.Question{})
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, formID, 0); err != nil {
+			return err
+		}
 
-	if err := res.Error; err != nil {
+		return tx.Where(&entity.Question{
+			FormID:      formID,
+			OrderNumber: orderNumber,
+		}).Delete(&entity.Question{}).Error
+	})
+	if err != nil {
 		repo.logger.Error("error delete question",
 			zap.String("form_id", formID.String()),
 			zap.Uint("order_number", orderNumber),
@@ -185,3 +443,311 @@ func (repo *Repository) DeleteQuestion(formID uuid.UUID, orderNumber uint) error
 
 	return nil
 }
+
+// CountQuestions returns the number of questions of a form
+// Parameters:
+//   - formID: UUID of the form
+//
+// Returns:
+//   - int64: Number of questions
+//   - error: Any error that occurred during counting
+func (repo *Repository) CountQuestions(formID uuid.UUID) (int64, error) {
+	var count int64
+
+	res := repo.db.Model(&entity.Question{}).Where("form_id = ?", formID).Count(&count)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error count questions",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountFormsPerAuthor returns the number of forms of each author, leaving
+// out forms in the trash
+// Returns:
+//   - []int64: Form count of each author with forms, in no particular order
+//   - error: Any error that occurred during counting
+func (repo *Repository) CountFormsPerAuthor() ([]int64, error) {
+	var counts []int64
+
+	res := repo.db.Model(&entity.Form{}).Group("author").Pluck("COUNT(*)", &counts)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error count forms per author", zap.Error(err))
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// CountForms returns the number of forms, leaving out forms in the trash
+func (repo *Repository) CountForms() (int64, error) {
+	var count int64
+
+	if err := repo.db.Model(&entity.Form{}).Count(&count).Error; err != nil {
+		repo.logger.Error("error count forms", zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ListFormsAfter retrieves the forms following a form in ID order, with their
+// questions, sections, branches and tags like Get, to walk every form in
+// batches. Forms in the trash are left out.
+// Parameters:
+//   - after: ID of the last form of the previous batch, uuid.Nil for the first batch
+//   - limit: Most forms returned
+//
+// Returns error if the query fails
+func (repo *Repository) ListFormsAfter(after uuid.UUID, limit int) ([]entity.Form, error) {
+	var forms []entity.Form
+
+	query := repo.db.Preload("Questions", orderQuestions).
+		Preload("Sections", orderSections).
+		Preload("Branches").
+		Preload("Tags", orderTags)
+	if after != uuid.Nil {
+		query = query.Where("id > ?", after)
+	}
+
+	if err := query.Order("id").Limit(limit).Find(&forms).Error; err != nil {
+		repo.logger.Error("error list forms",
+			zap.String("after", after.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return forms, nil
+}
+
+// CreateQuestions inserts questions into a form in batches of
+// QUESTION_BATCH_SIZE and bumps the version of the form, in one transaction
+// Parameters:
+//   - formID: UUID of the form
+//   - questions: Questions to insert, which get their IDs
+//
+// Returns error if any insert fails, in which case none is stored
+func (repo *Repository) CreateQuestions(formID uuid.UUID, questions []entity.Question) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, formID, 0); err != nil {
+			return err
+		}
+
+		for i := range questions {
+			questions[i].FormID = formID
+		}
+
+		return tx.Omit("Form").CreateInBatches(questions, QUESTION_BATCH_SIZE).Error
+	})
+	if err != nil {
+		repo.logger.Error("error create questions",
+			zap.String("form_id", formID.String()),
+			zap.Int("count", len(questions)),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetQuestion retrieves a question by its ID
+// Parameters:
+//   - questionID: ID of the question to retrieve
+//
+// Returns:
+//   - *entity.Question: Retrieved question or nil if not found
+//   - error: Any error that occurred during retrieval
+func (repo *Repository) GetQuestion(questionID uuid.UUID) (*entity.Question, error) {
+	var question entity.Question
+
+	res := repo.db.Where("ID = ?", questionID).First(&question)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error get question",
+			zap.String("question_id", questionID.String()),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return &question, nil
+}
+
+// DeleteQuestionByID removes a question by its ID and bumps the version of
+// its form
+// Unlike DeleteQuestion, the ID stays stable when questions are reordered
+// Parameters:
+//   - questionID: ID of the question to delete
+//
+// Returns error if the deletion fails or no such question exists
+func (repo *Repository) DeleteQuestionByID(questionID uuid.UUID) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersionOf(tx, &entity.Question{}, questionID); err != nil {
+			return err
+		}
+
+		return tx.Where("ID = ?", questionID).Delete(&entity.Question{}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil {
+		repo.logger.Error("error delete question",
+			zap.String("question_id", questionID.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// orderQuestions sorts preloaded questions by their position
+func orderQuestions(db *gorm.DB) *gorm.DB {
+	return db.Order("order_number")
+}
+
+// notArchived leaves archived forms out of listings
+func notArchived(db *gorm.DB) *gorm.DB {
+	return db.Where("status <> ?", entity.StatusArchived)
+}
+
+// SaveForm stores a whole form with its questions in one transaction
+// A form that doesn't exist yet is created. An existing form is saved only
+// if form carries its current version, or none, bumping it; the title
+// and description are updated and the questions are diffed against the
+// stored ones: questions without an ID are inserted, questions with an ID
+// are updated when they changed and stored questions missing from form are
+// deleted.
+// Parameters:
+//   - form: Form with the complete list of its questions
+//
+// Returns:
+//   - *entity.FormChanges: What the save changed
+//   - error: ErrQuestionNotInForm for foreign question IDs, *entity.VersionConflictError
+//     for forms at another version or any database error
+func (repo *Repository) SaveForm(form *entity.Form) (*entity.FormChanges, error) {
+	changes := new(entity.FormChanges)
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var stored entity.Form
+
+		err := tx.Preload("Questions").Where("ID = ?", form.ID).First(&stored).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			changes.Created = true
+			changes.Inserted = len(form.Questions)
+			return tx.Create(form).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = bumpVersion(tx, form.ID, form.Version); err != nil {
+			return err
+		}
+
+		if err = tx.Model(&entity.Form{}).Where("ID = ?", form.ID).Updates(map[string]any{
+			"title":       form.Title,
+			"description": form.Description,
+			"format":      form.Format,
+			"numbering":   form.Numbering,
+		}).Error; err != nil {
+			return err
+		}
+
+		existing := make(map[uuid.UUID]*entity.Question, len(stored.Questions))
+		for i := range stored.Questions {
+			existing[stored.Questions[i].ID] = &stored.Questions[i]
+		}
+
+		kept := make(map[uuid.UUID]bool, len(form.Questions))
+
+		for i := range form.Questions {
+			q := &form.Questions[i]
+			q.FormID = form.ID
+
+			if q.ID == uuid.Nil {
+				if err = tx.Create(q).Error; err != nil {
+					return err
+				}
+				changes.Inserted++
+				continue
+			}
+
+			old, ok := existing[q.ID]
+			if !ok {
+				return fmt.Errorf("%w: question %s", ErrQuestionNotInForm, q.ID)
+			}
+
+			kept[q.ID] = true
+
+			if old.Content == q.Content && old.Format == q.Format && old.OrderNumber == q.OrderNumber &&
+				old.Type == q.Type && bytes.Equal(old.Options, q.Options) && bytes.Equal(old.DefaultValue, q.DefaultValue) &&
+				sameSection(old.SectionID, q.SectionID) && reflect.DeepEqual(old.Rules, q.Rules) &&
+				old.MediaURL == q.MediaURL && old.MediaType == q.MediaType {
+				continue
+			}
+
+			if err = tx.Model(&entity.Question{}).Where("ID = ?", q.ID).Updates(map[string]any{
+				"content":       q.Content,
+				"format":        q.Format,
+				"order_number":  q.OrderNumber,
+				"type":          q.Type,
+				"options":       q.Options,
+				"default_value": q.DefaultValue,
+				"section_id":    q.SectionID,
+				"media_url":     q.MediaURL,
+				"media_type":    q.MediaType,
+				"rules":         q.Rules,
+			}).Error; err != nil {
+				return err
+			}
+			changes.Updated++
+		}
+
+		for id := range existing {
+			if kept[id] {
+				continue
+			}
+
+			if err = tx.Where("ID = ?", id).Delete(&entity.Question{}).Error; err != nil {
+				return err
+			}
+			changes.Deleted++
+		}
+
+		return nil
+	})
+	if err != nil {
+		if isVersionConflict(err) {
+			return nil, err
+		}
+		repo.logger.Error("error save form",
+			zap.String("form_id", form.ID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// CloneForm stores a copy of a form, see entity.Form.Clone, with its
+// questions, sections and branches in one transaction
+// Parameters:
+//   - form: Copy to store
+func (repo *Repository) CloneForm(form *entity.Form) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(form).Error
+	})
+	if err != nil {
+		repo.logger.Error("error clone form",
+			zap.String("form_id", form.ID.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,1684 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// setupRepository opens a fresh in-memory SQLite database with the schema migrated
+func setupRepository(t *testing.T) (*Repository, *gorm.DB) {
+	t.Helper()
+
+	repo, db := setupEmptyRepository(t)
+	require.NoError(t, db.AutoMigrate(&entity.Form{}, &entity.Question{}, &entity.Upload{}, &entity.InviteToken{}, &entity.TenantSettings{}, &entity.AuthorQuota{}, &entity.QuestionStat{}, &entity.Response{}, &entity.FormVersion{}, &entity.Template{}, &entity.Section{}, &entity.Branch{}, &entity.PublicForm{}, &entity.Tag{}, &entity.Collaborator{}, &entity.ProcessedEvent{}, &entity.FormStats{}, &entity.Webhook{}))
+
+	return repo, db
+}
+
+// setupEmptyRepository returns a repository on a database without tables
+func setupEmptyRepository(t *testing.T) (*Repository, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: gormlogger.Discard,
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return Init(db, &logger.Logger{Logger: zap.NewNop()}), db
+}
+
+func createForm(t *testing.T, repo *Repository) *entity.Form {
+	t.Helper()
+
+	form := &entity.Form{
+		ID:          uuid.New(),
+		Title:       "Test Form",
+		Description: "Test Description",
+		Author:      "author",
+	}
+	require.NoError(t, repo.Create(form))
+
+	return form
+}
+
+func TestRepository_CreateAndGet(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+
+	got, err := repo.Get(form.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, form.ID, got.ID)
+	assert.Equal(t, "Test Form", got.Title)
+	assert.Equal(t, "author", got.Author)
+}
+
+func TestRepository_Create_Duplicate(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+
+	err := repo.Create(&entity.Form{ID: form.ID, Author: "other"})
+
+	assert.Error(t, err)
+}
+
+func TestRepository_Get_NotFound(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	got, err := repo.Get(uuid.New())
+
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, got)
+}
+
+func TestRepository_Update(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+	other := createForm(t, repo)
+
+	require.NoError(t, repo.Update(form.ID, 0, "Status", entity.StatusClosed))
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusClosed, got.Status)
+
+	untouched, err := repo.Get(other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusDraft, untouched.Status, "forms default to drafts")
+}
+
+func TestRepository_UpdateMany(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+
+	err := repo.UpdateMany(form.ID, 0, map[string]any{
+		"Title":       "Updated Title",
+		"Description": "Updated Description",
+	})
+	require.NoError(t, err)
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated Title", got.Title)
+	assert.Equal(t, "Updated Description", got.Description)
+}
+
+func TestRepository_DeleteForm(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+
+	require.NoError(t, repo.DeleteForm(form.ID))
+
+	_, err := repo.Get(form.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRepository_Trash(t *testing.T) {
+	repo, db := setupRepository(t)
+
+	form := createForm(t, repo)
+	for i := uint(1); i <= 2; i++ {
+		require.NoError(t, repo.Create(&entity.Question{FormID: form.ID, Content: "question", OrderNumber: i}))
+	}
+	require.NoError(t, repo.DeleteQuestion(form.ID, 2))
+	require.NoError(t, repo.SetFormTags(form.ID, []entity.Tag{{Name: "hr"}}))
+
+	_, err := repo.GetTrashedForm(form.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, repo.RestoreForm(form.ID), gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, repo.PurgeForm(form.ID), gorm.ErrRecordNotFound)
+
+	require.NoError(t, repo.DeleteForm(form.ID))
+
+	trashed, err := repo.GetTrashedForm(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, form.ID, trashed.ID)
+	assert.Len(t, trashed.Questions, 1)
+
+	forms, err := repo.ListFormsByTag("", "hr", nil, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, forms)
+
+	t.Run("restore", func(t *testing.T) {
+		require.NoError(t, repo.RestoreForm(form.ID))
+
+		got, err := repo.Get(form.ID)
+		require.NoError(t, err)
+		assert.Len(t, got.Questions, 1)
+		assert.Equal(t, "hr", got.Tags[0].Name)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		require.NoError(t, repo.DeleteForm(form.ID))
+		require.NoError(t, repo.PurgeForm(form.ID))
+
+		_, err := repo.GetTrashedForm(form.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		var questions, tagged int64
+		require.NoError(t, db.Unscoped().Model(&entity.Question{}).Where("form_id = ?", form.ID).Count(&questions).Error)
+		require.NoError(t, db.Table("form_tags").Where("form_id = ?", form.ID).Count(&tagged).Error)
+		assert.Zero(t, questions)
+		assert.Zero(t, tagged)
+	})
+}
+
+func TestRepository_DeleteQuestion(t *testing.T) {
+	repo, db := setupRepository(t)
+
+	form := createForm(t, repo)
+	for i := uint(1); i <= 2; i++ {
+		require.NoError(t, repo.Create(&entity.Question{
+			FormID:      form.ID,
+			Content:     "question",
+			OrderNumber: i,
+		}))
+	}
+
+	count, err := repo.CountQuestions(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	require.NoError(t, repo.DeleteQuestion(form.ID, 1))
+
+	var remaining []entity.Question
+	require.NoError(t, db.Where("form_id = ?", form.ID).Find(&remaining).Error)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, uint(2), remaining[0].OrderNumber)
+}
+
+func TestRepository_Versions(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+	assert.Equal(t, uint(1), form.Version, "forms start at version 1")
+
+	require.NoError(t, repo.UpdateMany(form.ID, 1, &entity.Form{ID: form.ID, Title: "Mine", Version: 1}))
+	require.NoError(t, repo.Update(form.ID, 0, "Status", entity.StatusClosed), "unchecked updates still bump")
+
+	err := repo.UpdateMany(form.ID, 1, &entity.Form{ID: form.ID, Title: "Theirs", Version: 1})
+	var conflict *entity.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, entity.VersionConflictError{FormID: form.ID, Expected: 1, Actual: 3}, *conflict)
+
+	err = repo.Update(form.ID, 2, "Status", entity.StatusPublished)
+	require.ErrorAs(t, err, &conflict)
+
+	_, err = repo.SaveForm(&entity.Form{ID: form.ID, Title: "Saved", Version: 2})
+	require.ErrorAs(t, err, &conflict)
+
+	changes, err := repo.SaveForm(&entity.Form{ID: form.ID, Title: "Saved", Version: 3})
+	require.NoError(t, err)
+	assert.False(t, changes.Created)
+
+	stored, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Saved", stored.Title)
+	assert.Equal(t, entity.StatusClosed, stored.Status)
+	assert.Equal(t, uint(4), stored.Version)
+	assert.False(t, stored.UpdatedAt.IsZero())
+
+	assert.ErrorIs(t, repo.Update(uuid.New(), 1, "Status", entity.StatusClosed), gorm.ErrRecordNotFound)
+}
+
+func TestRepository_VersionsOfContentWrites(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+	question := &entity.Question{ID: uuid.New(), FormID: form.ID, Content: "Name?", OrderNumber: 1}
+	section := &entity.Section{ID: uuid.New(), FormID: form.ID, Title: "About you", OrderNumber: 1}
+
+	writes := []struct {
+		name  string
+		write func() error
+	}{
+		{"add question", func() error { return repo.Create(question) }},
+		{"update question", func() error { return repo.UpdateQuestion(question.ID, "Content", "Full name?") }},
+		{"update question columns", func() error {
+			return repo.UpdateQuestionMany(question.ID, map[string]any{"content": "Your name?"})
+		}},
+		{"add section", func() error { return repo.Create(section) }},
+		{"update section", func() error { return repo.UpdateSection(section.ID, map[string]any{"title": "You"}) }},
+		{"reorder sections", func() error { return repo.ReorderSections(form.ID, []uuid.UUID{section.ID}) }},
+		{"move question", func() error { return repo.MoveQuestion(question.ID, &section.ID) }},
+		{"set logic", func() error { return repo.ReplaceBranches(form.ID, nil) }},
+		{"set tags", func() error { return repo.SetFormTags(form.ID, []entity.Tag{{Name: "hr"}}) }},
+		{"set access", func() error { return repo.UpdateAccess(form.ID, []string{"example.com"}, false) }},
+		{"delete section", func() error { return repo.DeleteSection(section.ID) }},
+		{"delete question", func() error { return repo.DeleteQuestionByID(question.ID) }},
+		{"delete question by position", func() error { return repo.DeleteQuestion(form.ID, 1) }},
+	}
+
+	version := form.Version
+	for _, w := range writes {
+		require.NoError(t, w.write(), w.name)
+
+		stored, err := repo.Get(form.ID)
+		require.NoError(t, err)
+		assert.Equal(t, version+1, stored.Version, w.name)
+		version = stored.Version
+	}
+
+	assert.ErrorIs(t, repo.UpdateQuestion(uuid.New(), "Content", "?"), gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, repo.DeleteQuestionByID(uuid.New()), gorm.ErrRecordNotFound)
+}
+
+func TestRepository_ClosedDatabase(t *testing.T) {
+	repo, db := setupRepository(t)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	assert.Error(t, repo.Create(&entity.Form{ID: uuid.New()}))
+	assert.Error(t, repo.Update(uuid.New(), 0, "Status", entity.StatusClosed))
+	assert.Error(t, repo.DeleteForm(uuid.New()))
+
+	_, err = repo.Get(uuid.New())
+	assert.Error(t, err)
+}
+
+func TestRepository_UpdateMany_Struct(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+
+	// The listener passes the decoded form itself as the update values
+	err := repo.UpdateMany(form.ID, 0, &entity.Form{ID: form.ID, Title: "From Event"})
+	require.NoError(t, err)
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "From Event", got.Title)
+	assert.Equal(t, "Test Description", got.Description)
+}
+
+func TestRepository_DeleteQuestionByID(t *testing.T) {
+	repo, db := setupRepository(t)
+
+	form := createForm(t, repo)
+	first := &entity.Question{FormID: form.ID, Content: "first", OrderNumber: 1}
+	second := &entity.Question{FormID: form.ID, Content: "second", OrderNumber: 2}
+	require.NoError(t, repo.Create(first))
+	require.NoError(t, repo.Create(second))
+
+	got, err := repo.GetQuestion(second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, form.ID, got.FormID)
+
+	require.NoError(t, repo.DeleteQuestionByID(second.ID))
+
+	var remaining []entity.Question
+	require.NoError(t, db.Where("form_id = ?", form.ID).Find(&remaining).Error)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, first.ID, remaining[0].ID)
+
+	assert.ErrorIs(t, repo.DeleteQuestionByID(second.ID), gorm.ErrRecordNotFound)
+}
+
+func TestRepository_Migrate(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	version, err := repo.AppliedSchemaVersion()
+	require.Error(t, err, "version table doesn't exist before Migrate")
+	assert.Zero(t, version)
+
+	require.NoError(t, repo.Migrate())
+	require.NoError(t, repo.Migrate(), "migrating twice must be a no-op")
+
+	version, err = repo.AppliedSchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+}
+
+func TestRepository_MigrateFormStatus(t *testing.T) {
+	repo, db := setupRepository(t)
+	require.NoError(t, repo.Migrate())
+
+	stmt := &gorm.Statement{DB: db}
+	require.NoError(t, stmt.Parse(&entity.Form{}))
+	table := stmt.Schema.Table
+
+	// A database of the schema version before forms had a status
+	require.NoError(t, db.Exec("ALTER TABLE "+table+" ADD COLUMN closed BOOLEAN NOT NULL DEFAULT false").Error)
+	require.NoError(t, db.Exec("ALTER TABLE "+table+" ADD COLUMN archived BOOLEAN NOT NULL DEFAULT false").Error)
+	require.NoError(t, db.Where("1 = 1").Delete(&schemaMigration{}).Error)
+	require.NoError(t, db.Create(&schemaMigration{Version: STATUS_SCHEMA_VERSION - 1, AppliedAt: time.Now()}).Error)
+
+	open, closed, archived, trashed := createForm(t, repo), createForm(t, repo), createForm(t, repo), createForm(t, repo)
+	require.NoError(t, db.Exec("UPDATE "+table+" SET closed = ? WHERE id IN ?", true, []uuid.UUID{closed.ID, trashed.ID}).Error)
+	require.NoError(t, db.Exec("UPDATE "+table+" SET archived = ? WHERE id = ?", true, archived.ID).Error)
+	require.NoError(t, repo.DeleteForm(trashed.ID))
+
+	require.NoError(t, repo.Migrate())
+
+	for form, want := range map[uuid.UUID]string{
+		open.ID:     entity.StatusPublished,
+		closed.ID:   entity.StatusClosed,
+		archived.ID: entity.StatusArchived,
+		trashed.ID:  entity.StatusClosed,
+	} {
+		var stored entity.Form
+		require.NoError(t, db.Unscoped().Where("id = ?", form).First(&stored).Error)
+		assert.Equal(t, want, stored.Status)
+	}
+
+	// Forms created since are left alone by later migrations
+	draft := createForm(t, repo)
+	require.NoError(t, repo.Migrate())
+
+	stored, err := repo.Get(draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusDraft, stored.Status)
+}
+
+func TestRepository_MigrateBaselineFormStatus(t *testing.T) {
+	repo, db := setupEmptyRepository(t)
+
+	// The forms of the first release, which recorded no schema version
+	type baselineForm struct {
+		ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+		Title       string
+		Description string
+		Closed      bool
+		Author      string
+		CreatedAt   time.Time
+	}
+
+	table, err := repo.tableOf(&entity.Form{})
+	require.NoError(t, err)
+	require.NoError(t, db.Table(table).AutoMigrate(&baselineForm{}))
+
+	open := baselineForm{ID: uuid.New(), Title: "Open", Author: "author"}
+	closed := baselineForm{ID: uuid.New(), Title: "Closed", Author: "author", Closed: true}
+	require.NoError(t, db.Table(table).Create([]baselineForm{open, closed}).Error)
+
+	require.NoError(t, repo.Migrate())
+
+	for form, want := range map[uuid.UUID]string{open.ID: entity.StatusPublished, closed.ID: entity.StatusClosed} {
+		stored, err := repo.Get(form)
+		require.NoError(t, err)
+		assert.Equal(t, want, stored.Status)
+	}
+
+	// Later migrations leave the statuses alone, though the flag is kept
+	require.NoError(t, db.Table(table).Where("id = ?", open.ID).Update("status", entity.StatusClosed).Error)
+	require.NoError(t, repo.Migrate())
+
+	stored, err := repo.Get(open.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusClosed, stored.Status)
+}
+
+func TestRepository_MigrateQuestionIDs(t *testing.T) {
+	repo, db := setupRepository(t)
+	require.NoError(t, repo.Migrate())
+
+	// The tables holding question IDs as of the schema version before UUIDs
+	type (
+		legacyQuestion struct {
+			gorm.Model
+			FormID      uuid.UUID `gorm:"type:uuid"`
+			Content     string
+			OrderNumber uint
+		}
+		legacyBranch struct {
+			ID       uint      `gorm:"primaryKey"`
+			FormID   uuid.UUID `gorm:"type:uuid;index"`
+			SourceID uint
+			Operator string
+			TargetID uint
+			Action   string
+		}
+		legacyStat struct {
+			FormID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+			QuestionID uint      `gorm:"primaryKey;autoIncrement:false"`
+			Value      string    `gorm:"primaryKey;size:255"`
+			Total      int64
+		}
+	)
+
+	form := createForm(t, repo)
+
+	for model, legacy := range map[any]any{&entity.Question{}: &legacyQuestion{}, &entity.Branch{}: &legacyBranch{}, &entity.QuestionStat{}: &legacyStat{}} {
+		table, err := repo.tableOf(model)
+		require.NoError(t, err)
+		require.NoError(t, db.Migrator().DropTable(table))
+		require.NoError(t, db.Table(table).AutoMigrate(legacy))
+	}
+
+	questions := []legacyQuestion{
+		{FormID: form.ID, Content: "Name?", OrderNumber: 1},
+		{FormID: form.ID, Content: "Team?", OrderNumber: 2},
+	}
+	require.NoError(t, db.Table("questions").Create(&questions).Error)
+	require.NoError(t, db.Table("branches").Create(&legacyBranch{
+		FormID: form.ID, SourceID: questions[0].ID, Operator: entity.BranchAnswered, TargetID: questions[1].ID, Action: entity.BranchShow,
+	}).Error)
+	require.NoError(t, db.Table("question_stats").Create([]legacyStat{
+		{FormID: form.ID, Total: 4},
+		{FormID: form.ID, QuestionID: questions[1].ID, Total: 3},
+	}).Error)
+
+	snapshot := fmt.Sprintf(`{"ID":%q,"Questions":[{"ID":%d,"Content":"Name?"},{"ID":%d,"Content":"Team?"}],"Branches":[{"id":1,"source_id":%d,"target_id":%d}]}`,
+		form.ID, questions[0].ID, questions[1].ID, questions[0].ID, questions[1].ID)
+	require.NoError(t, db.Table("form_versions").Create(map[string]any{
+		"id": uuid.New().String(), "form_id": form.ID.String(), "version": 1, "form": snapshot, "published_at": time.Now(),
+	}).Error)
+
+	require.NoError(t, db.Where("1 = 1").Delete(&schemaMigration{}).Error)
+	require.NoError(t, db.Create(&schemaMigration{Version: QUESTION_ID_SCHEMA_VERSION - 1, AppliedAt: time.Now()}).Error)
+
+	require.NoError(t, repo.Migrate())
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Questions, 2)
+	assert.Equal(t, []string{"Name?", "Team?"}, []string{got.Questions[0].Content, got.Questions[1].Content})
+	name, team := got.Questions[0].ID, got.Questions[1].ID
+	assert.NotEqual(t, uuid.Nil, name)
+
+	require.Len(t, got.Branches, 1)
+	assert.Equal(t, name, got.Branches[0].SourceID)
+	assert.Equal(t, team, got.Branches[0].TargetID)
+
+	stats, err := repo.ListQuestionStats(form.ID)
+	require.NoError(t, err)
+	totals := make(map[uuid.UUID]int64)
+	for _, stat := range stats {
+		totals[stat.QuestionID] = stat.Total
+	}
+	assert.Equal(t, map[uuid.UUID]int64{uuid.Nil: 4, team: 3}, totals)
+
+	version, err := repo.GetFormVersion(form.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, version.Form.Questions, 2)
+	assert.Equal(t, name, version.Form.Questions[0].ID)
+	assert.Equal(t, team, version.Form.Branches[0].TargetID)
+
+	assert.False(t, db.Migrator().HasTable("questions_legacy"), "legacy tables are dropped")
+
+	applied, err := repo.AppliedSchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, applied)
+
+	// Migrating again leaves the converted IDs alone
+	require.NoError(t, repo.Migrate())
+	again, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, name, again.Questions[0].ID)
+}
+
+func TestRepository_MigrateBaselineQuestionIDs(t *testing.T) {
+	repo, db := setupEmptyRepository(t)
+
+	// The tables of the first release, which recorded no schema version
+	type (
+		baselineForm struct {
+			ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+			Title       string
+			Description string
+			Closed      bool
+			Author      string
+			CreatedAt   time.Time
+		}
+		baselineQuestion struct {
+			gorm.Model
+			FormID      uuid.UUID `gorm:"type:uuid"`
+			Content     string
+			OrderNumber uint
+		}
+	)
+
+	forms, err := repo.tableOf(&entity.Form{})
+	require.NoError(t, err)
+	questions, err := repo.tableOf(&entity.Question{})
+	require.NoError(t, err)
+	require.NoError(t, db.Table(forms).AutoMigrate(&baselineForm{}))
+	require.NoError(t, db.Table(questions).AutoMigrate(&baselineQuestion{}))
+
+	form := baselineForm{ID: uuid.New(), Title: "Feedback", Author: "author"}
+	require.NoError(t, db.Table(forms).Create(&form).Error)
+	require.NoError(t, db.Table(questions).Create([]baselineQuestion{
+		{FormID: form.ID, Content: "Name?", OrderNumber: 1},
+		{FormID: form.ID, Content: "Team?", OrderNumber: 2},
+	}).Error)
+
+	require.NoError(t, repo.Migrate())
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Questions, 2)
+	assert.Equal(t, []string{"Name?", "Team?"}, []string{got.Questions[0].Content, got.Questions[1].Content})
+	assert.NotEqual(t, uuid.Nil, got.Questions[0].ID)
+	assert.NotEqual(t, got.Questions[0].ID, got.Questions[1].ID)
+
+	var ids []string
+	require.NoError(t, db.Table(questions).Pluck("id", &ids).Error)
+	for _, id := range ids {
+		_, err := uuid.Parse(id)
+		assert.NoError(t, err, "question id %s is a UUID", id)
+	}
+
+	assert.False(t, db.Migrator().HasTable(legacyTable(questions)), "legacy tables are dropped")
+
+	applied, err := repo.AppliedSchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, applied)
+}
+
+func TestRepository_CheckSchemaVersion(t *testing.T) {
+	repo, db := setupRepository(t)
+	require.NoError(t, repo.Migrate())
+	require.NoError(t, repo.CheckSchemaVersion())
+
+	// A newer build migrated the database during a rolling upgrade
+	require.NoError(t, db.Create(&schemaMigration{Version: SchemaVersion + 1, AppliedAt: time.Now()}).Error)
+
+	var mismatch *SchemaMismatchError
+	require.ErrorAs(t, repo.CheckSchemaVersion(), &mismatch)
+	assert.Equal(t, SchemaVersion, mismatch.Expected)
+	assert.Equal(t, SchemaVersion+1, mismatch.Applied)
+}
+
+func TestRepository_SetReadOnly(t *testing.T) {
+	repo, _ := setupRepository(t)
+	form := createForm(t, repo)
+
+	require.NoError(t, repo.SetReadOnly())
+
+	assert.ErrorIs(t, repo.Create(&entity.Form{ID: uuid.New(), Author: "author"}), ErrReadOnly)
+	assert.ErrorIs(t, repo.Update(form.ID, 0, "Status", entity.StatusClosed), ErrReadOnly)
+	assert.ErrorIs(t, repo.DeleteForm(form.ID), ErrReadOnly)
+
+	stored, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusDraft, stored.Status)
+}
+
+func TestRepository_NamingStrategy(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger:         gormlogger.Discard,
+		NamingStrategy: NamingStrategy("fs_", true),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	repo := Init(db, &logger.Logger{Logger: zap.NewNop()})
+	require.NoError(t, repo.Migrate())
+
+	for _, table := range []string{"fs_form", "fs_question", "fs_author_quota", "fs_schema_migration"} {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+	assert.False(t, db.Migrator().HasTable("forms"))
+
+	form := createForm(t, repo)
+	require.NoError(t, repo.Create(&entity.Question{FormID: form.ID, Content: "Name?", OrderNumber: 1}))
+
+	stored, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Questions, 1)
+
+	version, err := repo.AppliedSchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+}
+
+func TestRepository_Uploads(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	now := time.Now()
+	expired := &entity.Upload{ID: uuid.New(), Status: entity.UploadPending, ExpiresAt: now.Add(-time.Minute)}
+	fresh := &entity.Upload{ID: uuid.New(), Status: entity.UploadPending, ExpiresAt: now.Add(time.Hour)}
+	attached := &entity.Upload{ID: uuid.New(), Status: entity.UploadAttached, ExpiresAt: now.Add(-time.Minute)}
+
+	for _, upload := range []*entity.Upload{expired, fresh, attached} {
+		require.NoError(t, repo.Create(upload))
+	}
+
+	orphans, err := repo.ListOrphanUploads(now, 10)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, expired.ID, orphans[0].ID)
+
+	responseID := uuid.New()
+	require.NoError(t, repo.UpdateUpload(fresh.ID, map[string]any{
+		"status":      entity.UploadAttached,
+		"response_id": responseID,
+	}))
+
+	got, err := repo.GetUpload(fresh.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.UploadAttached, got.Status)
+	assert.Equal(t, responseID, *got.ResponseID)
+
+	assert.ErrorIs(t, repo.UpdateUpload(uuid.New(), map[string]any{"status": "x"}), gorm.ErrRecordNotFound)
+
+	require.NoError(t, repo.DeleteUpload(expired.ID))
+	_, err = repo.GetUpload(expired.ID)
+	assert.Error(t, err)
+}
+
+func TestRepository_UpdateAccess(t *testing.T) {
+	repo, _ := setupRepository(t)
+	form := createForm(t, repo)
+
+	require.NoError(t, repo.UpdateAccess(form.ID, []string{"example.com"}, true))
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com"}, got.AllowedDomains)
+	assert.True(t, got.InviteOnly)
+
+	require.NoError(t, repo.UpdateAccess(form.ID, nil, false))
+
+	got, err = repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.AllowedDomains)
+	assert.False(t, got.InviteOnly, "restrictions can be lifted")
+}
+
+func TestRepository_Invites(t *testing.T) {
+	repo, _ := setupRepository(t)
+	form := createForm(t, repo)
+
+	invite := &entity.InviteToken{ID: uuid.New(), FormID: form.ID, TokenHash: "hash"}
+	require.NoError(t, repo.Create(invite))
+
+	got, err := repo.GetInviteByHash(form.ID, "hash")
+	require.NoError(t, err)
+	assert.False(t, got.IsRevoked())
+
+	_, err = repo.GetInviteByHash(uuid.New(), "hash")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "tokens are scoped to their form")
+
+	revoked, err := repo.RevokeInvite(invite.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked.IsRevoked())
+
+	_, err = repo.RevokeInvite(invite.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRepository_RedeemInvite(t *testing.T) {
+	repo, _ := setupRepository(t)
+	form := createForm(t, repo)
+
+	expiredAt := time.Now().Add(-time.Minute)
+	invite := &entity.InviteToken{ID: uuid.New(), FormID: form.ID, TokenHash: "single", SingleUse: true}
+	expired := &entity.InviteToken{ID: uuid.New(), FormID: form.ID, TokenHash: "expired", SingleUse: true, ExpiresAt: &expiredAt}
+	require.NoError(t, repo.Create(invite))
+	require.NoError(t, repo.Create(expired))
+
+	require.NoError(t, repo.RedeemInvite(invite.ID))
+	assert.ErrorIs(t, repo.RedeemInvite(invite.ID), gorm.ErrRecordNotFound, "a token is redeemed once")
+	assert.ErrorIs(t, repo.RedeemInvite(expired.ID), gorm.ErrRecordNotFound)
+
+	invites, err := repo.ListInvites(form.ID)
+	require.NoError(t, err)
+	require.Len(t, invites, 2)
+
+	for _, i := range invites {
+		if i.ID == invite.ID {
+			assert.True(t, i.IsRedeemed())
+		}
+	}
+
+	require.NoError(t, repo.ReleaseInvite(invite.ID))
+	require.NoError(t, repo.RedeemInvite(invite.ID), "a released token is redeemed again")
+}
+
+func TestRepository_TenantSettings(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	_, err := repo.GetTenantSettings("acme")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	limit := 10
+	require.NoError(t, repo.SaveTenantSettings(&entity.TenantSettings{TenantID: "acme", MaxQuestions: &limit}))
+
+	retention := 30
+	require.NoError(t, repo.SaveTenantSettings(&entity.TenantSettings{TenantID: "acme", RetentionDays: &retention}))
+
+	got, err := repo.GetTenantSettings("acme")
+	require.NoError(t, err)
+	assert.Nil(t, got.MaxQuestions, "saving replaces all overrides")
+	assert.Equal(t, 30, *got.RetentionDays)
+}
+
+func TestRepository_SaveForm(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := &entity.Form{
+		ID:     uuid.New(),
+		Title:  "Draft",
+		Author: "author",
+		Questions: []entity.Question{
+			{Content: "keep", OrderNumber: 1},
+			{Content: "edit", OrderNumber: 2},
+			{Content: "drop", OrderNumber: 3},
+		},
+	}
+
+	changes, err := repo.SaveForm(form)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.FormChanges{Created: true, Inserted: 3}, changes)
+
+	stored, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Questions, 3)
+
+	saved := &entity.Form{
+		ID:     form.ID,
+		Title:  "Final",
+		Author: "author",
+		Questions: []entity.Question{
+			stored.Questions[0],
+			{ID: stored.Questions[1].ID, Content: "edited", OrderNumber: 3},
+			{Content: "new", OrderNumber: 2},
+		},
+	}
+
+	changes, err = repo.SaveForm(saved)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.FormChanges{Inserted: 1, Updated: 1, Deleted: 1}, changes)
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Final", got.Title)
+	require.Len(t, got.Questions, 3)
+	assert.Equal(t, []string{"keep", "new", "edited"},
+		[]string{got.Questions[0].Content, got.Questions[1].Content, got.Questions[2].Content})
+
+	t.Run("rejects questions of other forms and rolls back", func(t *testing.T) {
+		other := createForm(t, repo)
+		foreign := &entity.Question{FormID: other.ID, Content: "foreign", OrderNumber: 1}
+		require.NoError(t, repo.Create(foreign))
+
+		_, err := repo.SaveForm(&entity.Form{
+			ID:        form.ID,
+			Title:     "Broken",
+			Author:    "author",
+			Questions: []entity.Question{{ID: foreign.ID, Content: "stolen"}},
+		})
+		assert.ErrorIs(t, err, ErrQuestionNotInForm)
+
+		got, err := repo.Get(form.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Final", got.Title)
+		assert.Len(t, got.Questions, 3)
+	})
+
+	t.Run("stores validation rules", func(t *testing.T) {
+		maxLength := 10
+		questions := got.Questions
+		questions[0].Rules = &entity.ValidationRules{Required: true, MaxLength: &maxLength}
+
+		changes, err := repo.SaveForm(&entity.Form{ID: form.ID, Title: "Final", Author: "author", Questions: questions})
+		require.NoError(t, err)
+		assert.Equal(t, 1, changes.Updated)
+
+		stored, err := repo.Get(form.ID)
+		require.NoError(t, err)
+		assert.Equal(t, questions[0].Rules, stored.Questions[0].Rules)
+		assert.Nil(t, stored.Questions[1].Rules)
+	})
+
+	t.Run("stores text formats", func(t *testing.T) {
+		stored, err := repo.Get(form.ID)
+		require.NoError(t, err)
+
+		questions := stored.Questions
+		questions[1].Format = entity.FormatMarkdown
+
+		changes, err := repo.SaveForm(&entity.Form{ID: form.ID, Title: "Final", Author: "author", Format: entity.FormatHTML, Questions: questions})
+		require.NoError(t, err)
+		assert.Equal(t, 1, changes.Updated)
+
+		got, err := repo.Get(form.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.FormatHTML, got.Format)
+		assert.Equal(t, entity.FormatMarkdown, got.Questions[1].Format)
+	})
+}
+
+func TestRepository_IsHealthy(t *testing.T) {
+	repo, db := setupRepository(t)
+
+	assert.True(t, repo.IsHealthy())
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	assert.False(t, repo.IsHealthy())
+}
+
+func TestRepository_Quota(t *testing.T) {
+	repo, _ := setupRepository(t)
+	limits := entity.QuotaLimits{MaxForms: 2, MaxQuestions: 5}
+
+	ok, err := repo.ReserveQuota("alice", 1, 4, limits)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.ReserveQuota("alice", 1, 2, limits)
+	require.NoError(t, err)
+	assert.False(t, ok, "questions would exceed the limit")
+
+	ok, err = repo.ReserveQuota("alice", 1, 1, limits)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.ReserveQuota("alice", 1, 0, limits)
+	require.NoError(t, err)
+	assert.False(t, ok, "forms would exceed the limit")
+
+	require.NoError(t, repo.ReleaseQuota("alice", 1, 9))
+
+	maxForms, maxPerForm := 10, 50
+	require.NoError(t, repo.SaveQuotaLimits(&entity.AuthorQuota{Author: "alice", MaxForms: &maxForms, MaxPerForm: &maxPerForm}))
+
+	quota, err := repo.GetQuota("alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, quota.FormCount)
+	assert.Equal(t, 0, quota.QuestionCount, "counters never go below zero")
+	assert.Equal(t, 10, *quota.MaxForms)
+	assert.Equal(t, 50, *quota.MaxPerForm)
+	assert.Nil(t, quota.MaxQuestions)
+}
+
+func TestRepository_QuestionStats(t *testing.T) {
+	repo, _ := setupRepository(t)
+	formID := uuid.New()
+
+	questionID := uuid.New()
+
+	require.NoError(t, repo.AddQuestionStats(formID, []entity.QuestionStat{
+		{Total: 2},
+		{QuestionID: questionID, Value: "yes", Total: 2},
+	}))
+	require.NoError(t, repo.AddQuestionStats(formID, []entity.QuestionStat{
+		{QuestionID: questionID, Value: "yes", Total: 1},
+		{QuestionID: questionID, Value: "no", Total: 1},
+	}))
+
+	stats, err := repo.ListQuestionStats(formID)
+	require.NoError(t, err)
+
+	totals := make(map[string]int64)
+	for _, stat := range stats {
+		totals[stat.QuestionID.String()+":"+stat.Value] = stat.Total
+	}
+	question := questionID.String()
+	assert.Equal(t, map[string]int64{uuid.Nil.String() + ":": 2, question + ":yes": 3, question + ":no": 1}, totals)
+}
+
+func TestRepository_FormStats(t *testing.T) {
+	repo, _ := setupRepository(t)
+	formID := uuid.New()
+
+	stats, err := repo.GetFormStats(formID)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.FormStats{FormID: formID}, stats, "forms without activity have zero counters")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.IncrementFormViews(formID, time.Now()))
+		}()
+	}
+	wg.Wait()
+
+	last := time.Now().Add(time.Minute).Truncate(time.Second)
+	require.NoError(t, repo.IncrementFormSubmissions(formID, last))
+
+	stats, err = repo.GetFormStats(formID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), stats.Views)
+	assert.Equal(t, int64(1), stats.Submissions)
+	require.NotNil(t, stats.LastActivityAt)
+	assert.True(t, last.Equal(*stats.LastActivityAt))
+}
+
+func TestRepository_OrphanedQuestions(t *testing.T) {
+	repo, db := setupRepository(t)
+
+	live := &entity.Form{ID: uuid.New(), Author: "author", Questions: []entity.Question{{Content: "Q1", OrderNumber: 1}}}
+	trashed := &entity.Form{ID: uuid.New(), Author: "author", Questions: []entity.Question{{Content: "Q1", OrderNumber: 1}}}
+	require.NoError(t, repo.Create(live))
+	require.NoError(t, repo.Create(trashed))
+	require.NoError(t, repo.DeleteForm(trashed.ID))
+
+	gone := uuid.New()
+	orphans := []entity.Question{
+		{FormID: gone, Content: "Q1", OrderNumber: 1},
+		{FormID: gone, Content: "Q2", OrderNumber: 2},
+	}
+	require.NoError(t, db.Create(&orphans).Error)
+
+	listed, err := repo.ListOrphanedQuestions(10)
+	require.NoError(t, err)
+	require.Len(t, listed, 2, "questions of trashed forms aren't orphaned")
+	assert.ElementsMatch(t, []uuid.UUID{orphans[0].ID, orphans[1].ID}, []uuid.UUID{listed[0].ID, listed[1].ID})
+	assert.Equal(t, gone, listed[1].FormID)
+
+	limited, err := repo.ListOrphanedQuestions(1)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+
+	deleted, err := repo.DeleteOrphanedQuestions([]uuid.UUID{orphans[0].ID, orphans[1].ID, live.Questions[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted, "questions of existing forms are kept")
+
+	listed, err = repo.ListOrphanedQuestions(10)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	stored, err := repo.Get(live.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Questions, 1)
+}
+
+func TestRepository_FormSettings(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := &entity.Form{ID: uuid.New(), Author: "author", Settings: entity.FormSettings{ConfirmationMessage: "Thanks!"}}
+	require.NoError(t, repo.Create(form))
+
+	require.NoError(t, repo.UpdateMany(form.ID, 0, map[string]any{
+		"settings_anonymous_responses": true,
+		"settings_edit_after_submit":   false,
+	}))
+
+	stored, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.FormSettings{AnonymousResponses: true, ConfirmationMessage: "Thanks!"}, stored.Settings)
+}
+
+func TestRepository_CreateFormOnce(t *testing.T) {
+	repo, db := setupRepository(t)
+	event := entity.ProcessedEvent{EventID: "event-1", Type: "request.form.create"}
+
+	form := &entity.Form{ID: uuid.New(), Author: "author", Questions: []entity.Question{{OrderNumber: 1, Content: "Name?"}}}
+	created, err := repo.CreateFormOnce(event, form)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	redelivered := &entity.Form{ID: uuid.New(), Author: "author"}
+	created, err = repo.CreateFormOnce(event, redelivered)
+	require.NoError(t, err)
+	assert.False(t, created, "the event was processed before")
+
+	var forms int64
+	require.NoError(t, db.Model(&entity.Form{}).Count(&forms).Error)
+	assert.Equal(t, int64(1), forms)
+
+	stored, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Questions, 1)
+
+	pruned, err := repo.PruneProcessedEvents(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, pruned, "processed within retention")
+
+	pruned, err = repo.PruneProcessedEvents(time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	created, err = repo.CreateFormOnce(event, redelivered)
+	require.NoError(t, err)
+	assert.True(t, created, "forgotten once pruned")
+}
+
+func TestRepository_Responses(t *testing.T) {
+	repo, _ := setupRepository(t)
+	formID := uuid.New()
+	start := time.Now()
+
+	for i := range 3 {
+		require.NoError(t, repo.Create(&entity.Response{
+			ID:           uuid.New(),
+			FormID:       formID,
+			RespondentID: fmt.Sprintf("respondent-%d", i),
+			Answers:      []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hello"`)}},
+			CreatedAt:    start.Add(time.Duration(i) * time.Second),
+		}))
+	}
+	require.NoError(t, repo.Create(&entity.Response{ID: uuid.New(), FormID: uuid.New()}))
+
+	count, err := repo.CountResponses(formID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	page, err := repo.ListResponses(formID, nil, 1, 5)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "respondent-1", page[0].RespondentID)
+	assert.JSONEq(t, `"hello"`, string(page[0].Answers[0].Value))
+}
+
+func TestRepository_CreateResponses(t *testing.T) {
+	repo, _ := setupRepository(t)
+	formID := uuid.New()
+
+	responses := make([]entity.Response, RESPONSE_BATCH_SIZE+1)
+	for i := range responses {
+		responses[i] = entity.Response{ID: uuid.New(), FormID: formID, RespondentID: fmt.Sprintf("respondent-%d", i)}
+	}
+	require.NoError(t, repo.CreateResponses(responses))
+
+	count, err := repo.CountResponses(formID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(responses)), count)
+
+	t.Run("stores none of a failing batch", func(t *testing.T) {
+		err := repo.CreateResponses([]entity.Response{{ID: uuid.New(), FormID: formID}, responses[0]})
+		assert.Error(t, err)
+
+		count, err := repo.CountResponses(formID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(responses)), count)
+	})
+}
+
+func TestRepository_FormVersions(t *testing.T) {
+	repo, _ := setupRepository(t)
+	form := createForm(t, repo)
+	require.NoError(t, repo.Create(&entity.Question{FormID: form.ID, Content: "Name?", OrderNumber: 1}))
+
+	first, err := repo.CreateFormVersion(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), first.Version)
+
+	require.NoError(t, repo.Update(form.ID, 0, "title", "Renamed"))
+
+	second, err := repo.CreateFormVersion(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), second.Version)
+
+	got, err := repo.GetFormVersion(form.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Form", got.Form.Title, "versions keep the form as published")
+	require.Len(t, got.Form.Questions, 1)
+	assert.Equal(t, "Name?", got.Form.Questions[0].Content)
+
+	latest, err := repo.GetFormVersion(form.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), latest.Version)
+	assert.Equal(t, "Renamed", latest.Form.Title)
+
+	_, err = repo.GetFormVersion(form.ID, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = repo.CreateFormVersion(uuid.New())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	t.Run("at a time", func(t *testing.T) {
+		at, err := repo.GetFormVersionAt(form.ID, second.PublishedAt.Add(-time.Nanosecond))
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), at.Version)
+
+		at, err = repo.GetFormVersionAt(form.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), at.Version)
+
+		_, err = repo.GetFormVersionAt(form.ID, first.PublishedAt.Add(-time.Second))
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestRepository_PublicForms(t *testing.T) {
+	repo, _ := setupRepository(t)
+	form := createForm(t, repo)
+
+	public := &entity.PublicForm{
+		FormID:   form.ID,
+		Version:  1,
+		Form:     entity.Form{ID: form.ID, Title: "Published"},
+		Document: json.RawMessage(`{"id":"form"}`),
+	}
+	require.NoError(t, repo.SavePublicForm(public))
+
+	public.Version = 2
+	require.NoError(t, repo.SavePublicForm(public))
+
+	got, err := repo.GetPublicForm(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), got.Version)
+	assert.Equal(t, "Published", got.Form.Title)
+	assert.JSONEq(t, `{"id":"form"}`, string(got.Document))
+
+	require.NoError(t, repo.DeletePublicForm(form.ID))
+	require.NoError(t, repo.DeletePublicForm(form.ID), "deleting twice is not an error")
+
+	_, err = repo.GetPublicForm(form.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRepository_Tags(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	survey, poll := createForm(t, repo), createForm(t, repo)
+	other := &entity.Form{ID: uuid.New(), Author: "author", TenantID: "acme"}
+	require.NoError(t, repo.Create(other))
+
+	require.NoError(t, repo.SetFormTags(survey.ID, []entity.Tag{{Name: "hr"}, {Name: "onboarding"}}))
+	require.NoError(t, repo.SetFormTags(poll.ID, []entity.Tag{{Name: "hr"}}))
+	require.NoError(t, repo.SetFormTags(other.ID, []entity.Tag{{Name: "hr"}}))
+
+	stored, err := repo.Get(survey.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Tag{{Name: "hr"}, {Name: "onboarding"}}, stored.Tags)
+
+	forms, err := repo.ListFormsByTag("", "hr", nil, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, forms, 2, "forms of other tenants aren't listed")
+	assert.ElementsMatch(t, []uuid.UUID{survey.ID, poll.ID}, []uuid.UUID{forms[0].ID, forms[1].ID})
+
+	count, err := repo.CountFormsByTag("", "hr", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	forms, err = repo.ListFormsByTag("", "hr", nil, 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, forms, 1)
+
+	t.Run("replaces and clears tags", func(t *testing.T) {
+		require.NoError(t, repo.SetFormTags(survey.ID, []entity.Tag{{Name: "onboarding"}}))
+
+		count, err := repo.CountFormsByTag("", "hr", nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		require.NoError(t, repo.SetFormTags(survey.ID, nil))
+
+		stored, err := repo.Get(survey.ID)
+		require.NoError(t, err)
+		assert.Empty(t, stored.Tags)
+	})
+
+	t.Run("fails for unknown forms", func(t *testing.T) {
+		err := repo.SetFormTags(uuid.New(), []entity.Tag{{Name: "hr"}})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("leaves archived forms out", func(t *testing.T) {
+		archived := createForm(t, repo)
+		require.NoError(t, repo.SetFormTags(archived.ID, []entity.Tag{{Name: "archive"}}))
+		require.NoError(t, repo.Update(archived.ID, 0, "Status", entity.StatusArchived))
+
+		forms, err := repo.ListFormsByTag("", "archive", nil, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, forms)
+
+		count, err := repo.CountFormsByTag("", "archive", nil)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		stored, err := repo.Get(archived.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.StatusArchived, stored.Status)
+	})
+}
+
+func TestRepository_Templates(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	template := &entity.Template{
+		ID:     uuid.New(),
+		Title:  "Onboarding survey",
+		Author: "author",
+		Questions: []entity.TemplateQuestion{
+			{Content: "Rate your first week", OrderNumber: 1, Type: "rating", Options: json.RawMessage(`{"max":10}`)},
+		},
+	}
+	require.NoError(t, repo.Create(template))
+
+	got, err := repo.GetTemplate(template.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Onboarding survey", got.Title)
+	require.Len(t, got.Questions, 1)
+	assert.JSONEq(t, `{"max":10}`, string(got.Questions[0].Options))
+
+	_, err = repo.GetTemplate(uuid.New())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRepository_CreateQuestions(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+
+	questions := make([]entity.Question, QUESTION_BATCH_SIZE+5)
+	for i := range questions {
+		questions[i] = entity.Question{Content: fmt.Sprintf("Question %d", i+1), OrderNumber: uint(i + 1)}
+	}
+	require.NoError(t, repo.CreateQuestions(form.ID, questions))
+	assert.NotZero(t, questions[len(questions)-1].ID)
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Len(t, got.Questions, len(questions))
+	assert.Equal(t, "Question 1", got.Questions[0].Content)
+	assert.Equal(t, uint(2), got.Version)
+}
+
+func TestRepository_ReorderQuestions(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := &entity.Form{
+		ID: uuid.New(),
+		Questions: []entity.Question{
+			{Content: "Name?", OrderNumber: 1},
+			{Content: "Team?", OrderNumber: 2},
+			{Content: "Role?", OrderNumber: 5},
+		},
+	}
+	require.NoError(t, repo.Create(form))
+	ids := []uuid.UUID{form.Questions[2].ID, form.Questions[0].ID, form.Questions[1].ID}
+
+	require.NoError(t, repo.ReorderQuestions(form.ID, ids))
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	for i, q := range got.Questions {
+		assert.Equal(t, ids[i], q.ID)
+		assert.EqualValues(t, i+1, q.OrderNumber, "numbered without gaps")
+	}
+	assert.Equal(t, uint(2), got.Version)
+
+	other := createForm(t, repo)
+	err = repo.ReorderQuestions(other.ID, ids)
+	assert.ErrorIs(t, err, ErrQuestionNotInForm)
+
+	got, err = repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ids[0], got.Questions[0].ID)
+}
+
+func TestRepository_Sections(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := &entity.Form{
+		ID:     uuid.New(),
+		Author: "author",
+		Questions: []entity.Question{
+			{Content: "Name?", OrderNumber: 1},
+			{Content: "Team?", OrderNumber: 2},
+		},
+	}
+	require.NoError(t, repo.Create(form))
+
+	first := &entity.Section{ID: uuid.New(), FormID: form.ID, Title: "About you", OrderNumber: 1}
+	second := &entity.Section{ID: uuid.New(), FormID: form.ID, Title: "Work", OrderNumber: 2}
+	require.NoError(t, repo.Create(first))
+	require.NoError(t, repo.Create(second))
+
+	require.NoError(t, repo.MoveQuestion(form.Questions[1].ID, &second.ID))
+	require.NoError(t, repo.ReorderSections(form.ID, []uuid.UUID{second.ID, first.ID}))
+	require.NoError(t, repo.UpdateSection(first.ID, map[string]any{"title": "About yourself"}))
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Sections, 2)
+	assert.Equal(t, second.ID, got.Sections[0].ID, "sections are loaded in order")
+	assert.Equal(t, "About yourself", got.Sections[1].Title)
+	require.NotNil(t, got.Questions[1].SectionID)
+	assert.Equal(t, second.ID, *got.Questions[1].SectionID)
+
+	err = repo.ReorderSections(form.ID, []uuid.UUID{uuid.New()})
+	assert.ErrorIs(t, err, ErrSectionNotInForm)
+
+	require.NoError(t, repo.DeleteSection(second.ID))
+
+	got, err = repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Len(t, got.Sections, 1)
+	assert.Len(t, got.Questions, 2, "questions outlive their section")
+	assert.Nil(t, got.Questions[1].SectionID)
+
+	assert.ErrorIs(t, repo.DeleteSection(second.ID), gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, repo.MoveQuestion(uuid.New(), nil), gorm.ErrRecordNotFound)
+}
+
+func TestRepository_ReplaceBranches(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := &entity.Form{
+		ID:        uuid.New(),
+		Author:    "author",
+		Questions: []entity.Question{{Content: "Coming?", OrderNumber: 1}, {Content: "Diet?", OrderNumber: 2}},
+	}
+	require.NoError(t, repo.Create(form))
+
+	source, target := form.Questions[0].ID, form.Questions[1].ID
+	require.NoError(t, repo.ReplaceBranches(form.ID, []entity.Branch{
+		{SourceID: source, Operator: entity.BranchEquals, Value: json.RawMessage(`"yes"`), TargetID: target, Action: entity.BranchShow},
+	}))
+
+	got, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Branches, 1)
+	assert.Equal(t, target, got.Branches[0].TargetID)
+	assert.JSONEq(t, `"yes"`, string(got.Branches[0].Value))
+
+	require.NoError(t, repo.ReplaceBranches(form.ID, nil))
+
+	got, err = repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Branches)
+}
+
+func TestRepository_Schedules(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+
+	opening := createForm(t, repo)
+	require.NoError(t, repo.UpdateMany(opening.ID, 0, map[string]any{"status": entity.StatusClosed, "opens_at": past, "closes_at": future}))
+	closing := createForm(t, repo)
+	require.NoError(t, repo.UpdateMany(closing.ID, 0, map[string]any{"status": entity.StatusPublished, "closes_at": past}))
+	later := createForm(t, repo)
+	require.NoError(t, repo.UpdateMany(later.ID, 0, map[string]any{"opens_at": future}))
+
+	due, err := repo.ListDueSchedules(now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+
+	opened, err := repo.OpenScheduled(opening.ID, now)
+	require.NoError(t, err)
+	assert.True(t, opened)
+
+	stored, err := repo.Get(opening.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusPublished, stored.Status)
+	assert.Nil(t, stored.OpensAt)
+	assert.NotNil(t, stored.ClosesAt)
+
+	opened, err = repo.OpenScheduled(opening.ID, now)
+	require.NoError(t, err)
+	assert.False(t, opened, "an applied schedule is cleared")
+
+	closed, err := repo.CloseScheduled(later.ID, now)
+	require.NoError(t, err)
+	assert.False(t, closed, "schedules not due yet are kept")
+
+	closed, err = repo.CloseScheduled(closing.ID, now)
+	require.NoError(t, err)
+	assert.True(t, closed)
+
+	stored, err = repo.Get(closing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusClosed, stored.Status)
+
+	t.Run("drops schedules of forms in other states", func(t *testing.T) {
+		draft := createForm(t, repo)
+		require.NoError(t, repo.UpdateMany(draft.ID, 0, map[string]any{"opens_at": past, "closes_at": past}))
+
+		opened, err := repo.OpenScheduled(draft.ID, now)
+		require.NoError(t, err)
+		assert.False(t, opened, "drafts are only published explicitly")
+
+		closed, err := repo.CloseScheduled(draft.ID, now)
+		require.NoError(t, err)
+		assert.False(t, closed)
+
+		stored, err := repo.Get(draft.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.StatusDraft, stored.Status)
+		assert.Nil(t, stored.OpensAt)
+		assert.Nil(t, stored.ClosesAt)
+	})
+
+	due, err = repo.ListDueSchedules(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestRepository_Collaborators(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+
+	_, err := repo.GetCollaborator(form.ID, "bob")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	require.NoError(t, repo.SaveCollaborator(&entity.Collaborator{FormID: form.ID, UserID: "bob", Role: entity.RoleViewer}))
+	require.NoError(t, repo.SaveCollaborator(&entity.Collaborator{FormID: form.ID, UserID: "bob", Role: entity.RoleEditor}))
+	require.NoError(t, repo.SaveCollaborator(&entity.Collaborator{FormID: form.ID, UserID: "amy", Role: entity.RoleViewer}))
+
+	stored, err := repo.GetCollaborator(form.ID, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoleEditor, stored.Role, "saving again changes the role")
+
+	collaborators, err := repo.ListCollaborators(form.ID)
+	require.NoError(t, err)
+	require.Len(t, collaborators, 2)
+	assert.Equal(t, "amy", collaborators[0].UserID)
+
+	removed, err := repo.DeleteCollaborator(form.ID, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoleEditor, removed.Role)
+
+	_, err = repo.DeleteCollaborator(form.ID, "bob")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRepository_Webhooks(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+	other := createForm(t, repo)
+
+	hook := &entity.Webhook{ID: uuid.New(), FormID: form.ID, URL: "https://example.com/hook"}
+	require.NoError(t, repo.SaveWebhook(hook))
+	require.NoError(t, repo.SaveWebhook(&entity.Webhook{ID: uuid.New(), FormID: other.ID, URL: "https://example.com/other"}))
+
+	hook.TemplateKind, hook.Template = "go", `{"form": {{json .payload.form_id}}}`
+	require.NoError(t, repo.SaveWebhook(hook))
+
+	stored, err := repo.GetWebhook(hook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "go", stored.TemplateKind, "saving again replaces the template")
+
+	webhooks, err := repo.ListWebhooks(form.ID)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, `{"form": {{json .payload.form_id}}}`, webhooks[0].Template)
+
+	require.NoError(t, repo.DeleteWebhook(hook.ID))
+	assert.ErrorIs(t, repo.DeleteWebhook(hook.ID), gorm.ErrRecordNotFound)
+
+	require.NoError(t, repo.DeleteForm(other.ID))
+	require.NoError(t, repo.PurgeForm(other.ID))
+
+	webhooks, err = repo.ListWebhooks(other.ID)
+	require.NoError(t, err)
+	assert.Empty(t, webhooks, "purging a form removes its webhooks")
+}
+
+func TestRepository_RespondentData(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := createForm(t, repo)
+	other := createForm(t, repo)
+
+	require.NoError(t, repo.Create(&entity.Response{ID: uuid.New(), FormID: form.ID, RespondentID: "resp-1"}))
+	require.NoError(t, repo.Create(&entity.Response{ID: uuid.New(), FormID: other.ID, Email: "Bob@Example.com"}))
+	require.NoError(t, repo.Create(&entity.Response{ID: uuid.New(), FormID: other.ID, RespondentID: "resp-2"}))
+	require.NoError(t, repo.Create(&entity.InviteToken{ID: uuid.New(), FormID: form.ID, TokenHash: "a", Email: "bob@example.com"}))
+
+	responses, invites, err := repo.ListRespondentData("bob@example.com")
+	require.NoError(t, err)
+	assert.Len(t, responses, 1, "emails are matched case-insensitively")
+	assert.Len(t, invites, 1)
+
+	responses, invites, err = repo.EraseRespondentData("resp-1")
+	require.NoError(t, err)
+	assert.Len(t, responses, 1)
+	assert.Empty(t, invites)
+
+	_, _, err = repo.EraseRespondentData("bob@example.com")
+	require.NoError(t, err)
+
+	responses, invites, err = repo.ListRespondentData("bob@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, responses)
+	assert.Empty(t, invites)
+
+	count, err := repo.CountResponses(other.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "other respondents are kept")
+}
+
+func TestRepository_CloneForm(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	sectionID := uuid.New()
+	form := &entity.Form{
+		ID:        uuid.New(),
+		Author:    "author",
+		Sections:  []entity.Section{{ID: sectionID, Title: "Party", OrderNumber: 1}},
+		Questions: []entity.Question{{Content: "Coming?", OrderNumber: 1}, {Content: "Diet?", OrderNumber: 2, SectionID: &sectionID}},
+		Tags:      []entity.Tag{{Name: "events"}},
+	}
+	require.NoError(t, repo.Create(form))
+	require.NoError(t, repo.ReplaceBranches(form.ID, []entity.Branch{
+		{SourceID: form.Questions[0].ID, Operator: entity.BranchEquals, Value: json.RawMessage(`"yes"`), TargetID: form.Questions[1].ID, Action: entity.BranchShow},
+	}))
+
+	source, err := repo.Get(form.ID)
+	require.NoError(t, err)
+
+	clone := source.Clone("bob")
+	require.NoError(t, repo.CloneForm(clone))
+
+	got, err := repo.Get(clone.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", got.Author)
+	require.Len(t, got.Questions, 2)
+	assert.NotEqual(t, source.Questions[0].ID, got.Questions[0].ID, "questions get new IDs")
+	require.Len(t, got.Sections, 1)
+	assert.NotEqual(t, sectionID, got.Sections[0].ID)
+	require.NotNil(t, got.Questions[1].SectionID)
+	assert.Equal(t, got.Sections[0].ID, *got.Questions[1].SectionID)
+	require.Len(t, got.Branches, 1)
+	assert.Equal(t, got.Questions[0].ID, got.Branches[0].SourceID, "branches point at the copied questions")
+	assert.Equal(t, got.Questions[1].ID, got.Branches[0].TargetID)
+	require.Len(t, got.Tags, 1)
+
+	original, err := repo.Get(form.ID)
+	require.NoError(t, err)
+	assert.Len(t, original.Questions, 2, "the original is unchanged")
+	assert.Equal(t, source.Questions[0].ID, original.Branches[0].SourceID)
+}
+
+func TestRepository_CountFormsPerAuthor(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	for _, author := range []string{"ann", "ann", "bob", "ann"} {
+		require.NoError(t, repo.Create(&entity.Form{ID: uuid.New(), Author: author}))
+	}
+
+	trashed := &entity.Form{ID: uuid.New(), Author: "bob"}
+	require.NoError(t, repo.Create(trashed))
+	require.NoError(t, repo.DeleteForm(trashed.ID))
+
+	counts, err := repo.CountFormsPerAuthor()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{3, 1}, counts)
+}
+
+func TestRepository_ListFormsAfter(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	var ids []uuid.UUID
+	for range 5 {
+		form := createForm(t, repo)
+		require.NoError(t, repo.Create(&entity.Question{FormID: form.ID, Content: "Name?", OrderNumber: 1}))
+		ids = append(ids, form.ID)
+	}
+	require.NoError(t, repo.DeleteForm(ids[4]))
+
+	count, err := repo.CountForms()
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	var walked []uuid.UUID
+	after := uuid.Nil
+	for {
+		batch, err := repo.ListFormsAfter(after, 3)
+		require.NoError(t, err)
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, form := range batch {
+			assert.Len(t, form.Questions, 1, "forms come with their questions")
+			walked = append(walked, form.ID)
+		}
+		after = batch[len(batch)-1].ID
+	}
+
+	assert.ElementsMatch(t, ids[:4], walked, "every live form is walked once")
+}
+
+func TestRepository_GetBySlug(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	slug := "customer-survey-2024"
+	form := &entity.Form{ID: uuid.New(), Title: "Customer Survey", Slug: &slug, Author: "author"}
+	require.NoError(t, repo.Create(form))
+	createForm(t, repo) // Forms without a slug don't collide
+
+	got, err := repo.GetBySlug(slug)
+	require.NoError(t, err)
+	assert.Equal(t, form.ID, got.ID)
+
+	dup := &entity.Form{ID: uuid.New(), Slug: &slug, Author: "author"}
+	assert.Error(t, repo.Create(dup), "slugs are unique")
+
+	require.NoError(t, repo.DeleteForm(form.ID))
+
+	_, err = repo.GetBySlug(slug)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "trashed forms aren't resolved")
+
+	taken, err := repo.SlugExists(slug)
+	require.NoError(t, err)
+	assert.True(t, taken, "trashed forms keep their slug for a restore")
+
+	taken, err = repo.SlugExists("other")
+	require.NoError(t, err)
+	assert.False(t, taken)
+}
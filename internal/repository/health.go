@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// HEALTH_CHECK_TIMEOUT bounds a database ping started by IsHealthy
+const HEALTH_CHECK_TIMEOUT = 2 * time.Second
+
+// IsHealthy reports whether the database answers a ping
+func (repo *Repository) IsHealthy() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), HEALTH_CHECK_TIMEOUT)
+	defer cancel()
+
+	return repo.IsHealthyContext(ctx)
+}
+
+// IsHealthyContext reports whether the database answers a ping before ctx is done
+func (repo *Repository) IsHealthyContext(ctx context.Context) bool {
+	sqlDB, err := repo.db.DB()
+	if err != nil {
+		repo.logger.Error("error get database handle", zap.Error(err))
+		return false
+	}
+
+	if err = sqlDB.PingContext(ctx); err != nil {
+		repo.logger.Warn("database ping failed", zap.Error(err))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// formIDs selects the ID of every form, those in the trash included
+func (repo *Repository) formIDs() *gorm.DB {
+	return repo.db.Unscoped().Model(&entity.Form{}).Select("id")
+}
+
+// ListOrphanedQuestions retrieves questions whose form is gone, left behind
+// when deleting a form didn't cascade to them. Questions of forms in the
+// trash aren't orphaned, they come back with their form.
+// Parameters:
+//   - limit: Most questions returned
+//
+// Returns:
+//   - []entity.Question: Orphaned questions, deleted ones included, in creation order
+//   - error: Any error that occurred during the query
+func (repo *Repository) ListOrphanedQuestions(limit int) ([]entity.Question, error) {
+	var questions []entity.Question
+
+	res := repo.db.Unscoped().
+		Where("form_id NOT IN (?)", repo.formIDs()).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&questions)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error list orphaned questions", zap.Error(err))
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// DeleteOrphanedQuestions permanently removes those of the questions with
+// the given IDs whose form is still gone, so a form created meanwhile with
+// the same ID keeps them
+// Returns the number of removed questions
+func (repo *Repository) DeleteOrphanedQuestions(ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	res := repo.db.Unscoped().
+		Where("id IN ? AND form_id NOT IN (?)", ids, repo.formIDs()).
+		Delete(&entity.Question{})
+	if err := res.Error; err != nil {
+		repo.logger.Error("error delete orphaned questions",
+			zap.Int("questions", len(ids)),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// GetInviteByHash retrieves an invite token of a form by the token hash
+// Parameters:
+//   - formID: UUID of the form the token belongs to
+//   - tokenHash: Hex SHA-256 of the token
+//
+// Returns:
+//   - *entity.InviteToken: Retrieved invite or nil if not found
+//   - error: Any error that occurred during retrieval
+func (repo *Repository) GetInviteByHash(formID uuid.UUID, tokenHash string) (*entity.InviteToken, error) {
+	var invite entity.InviteToken
+
+	res := repo.db.Where("form_id = ? AND token_hash = ?", formID, tokenHash).First(&invite)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error get invite",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &invite, nil
+}
+
+// GetInvite retrieves an invite token by its ID
+// Returns gorm.ErrRecordNotFound if there is no such invite
+func (repo *Repository) GetInvite(inviteID uuid.UUID) (*entity.InviteToken, error) {
+	var invite entity.InviteToken
+
+	if err := repo.db.Where("id = ?", inviteID).First(&invite).Error; err != nil {
+		return nil, err
+	}
+
+	return &invite, nil
+}
+
+// RevokeInvite marks an invite token as revoked
+// Parameters:
+//   - inviteID: UUID of the invite to revoke
+//
+// Returns:
+//   - *entity.InviteToken: The revoked invite
+//   - error: gorm.ErrRecordNotFound if no active invite has that ID
+func (repo *Repository) RevokeInvite(inviteID uuid.UUID) (*entity.InviteToken, error) {
+	res := repo.db.Model(&entity.InviteToken{}).
+		Where("id = ? AND revoked_at IS NULL", inviteID).
+		Update("revoked_at", time.Now())
+	if err := res.Error; err != nil {
+		repo.logger.Error("error revoke invite",
+			zap.String("invite_id", inviteID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var invite entity.InviteToken
+	if err := repo.db.Where("id = ?", inviteID).First(&invite).Error; err != nil {
+		return nil, err
+	}
+
+	return &invite, nil
+}
+
+// ListInvites returns the invite tokens of a form, newest first
+// Parameters:
+//   - formID: UUID of the form
+//
+// Returns:
+//   - []entity.InviteToken: Invites of the form
+//   - error: Any error that occurred during retrieval
+func (repo *Repository) ListInvites(formID uuid.UUID) ([]entity.InviteToken, error) {
+	var invites []entity.InviteToken
+
+	res := repo.db.Where("form_id = ?", formID).Order("created_at desc").Find(&invites)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error list invites",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// RedeemInvite atomically marks a single-use invite as redeemed. The update
+// only matches an invite that is neither redeemed, revoked nor expired, so
+// concurrent redemptions of the same token can't both succeed.
+// Parameters:
+//   - inviteID: UUID of the invite to redeem
+//
+// Returns gorm.ErrRecordNotFound if the invite can't be redeemed
+func (repo *Repository) RedeemInvite(inviteID uuid.UUID) error {
+	now := time.Now()
+
+	res := repo.db.Model(&entity.InviteToken{}).
+		Where("id = ? AND redeemed_at IS NULL AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", inviteID, now).
+		Update("redeemed_at", now)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error redeem invite",
+			zap.String("invite_id", inviteID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ReleaseInvite undoes the redemption of a single-use invite, e.g. when the
+// response it was redeemed for couldn't be stored
+// Parameters:
+//   - inviteID: UUID of the invite to release
+//
+// Returns error if the update fails
+func (repo *Repository) ReleaseInvite(inviteID uuid.UUID) error {
+	res := repo.db.Model(&entity.InviteToken{}).
+		Where("id = ? AND redeemed_at IS NOT NULL", inviteID).
+		Update("redeemed_at", nil)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error release invite",
+			zap.String("invite_id", inviteID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAccess replaces the respondent restrictions of a form and bumps its
+// version
+// Parameters:
+//   - formID: UUID of the form to update
+//   - allowedDomains: Email domains allowed to answer, empty allows any
+//   - inviteOnly: Whether answering requires an invite token
+//
+// Returns error if the update fails
+func (repo *Repository) UpdateAccess(formID uuid.UUID, allowedDomains []string, inviteOnly bool) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, formID, 0); err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Form{ID: formID}).
+			Select("AllowedDomains", "InviteOnly").
+			Updates(&entity.Form{AllowedDomains: allowedDomains, InviteOnly: inviteOnly}).Error
+	})
+	if err != nil {
+		repo.logger.Error("error update form access",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
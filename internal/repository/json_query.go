@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidJSONCondition is returned for conditions on columns that aren't
+// JSON columns of the model, or on malformed paths
+var ErrInvalidJSONCondition = errors.New("invalid JSON condition")
+
+// jsonKey matches a key of a JSONCondition path
+var jsonKey = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// JSONCondition matches rows whose JSON column holds Value at Path, e.g.
+// {Column: "rules", Path: "required", Value: true}. Values are compared as
+// JSON, so numbers match whatever their notation and nil matches an
+// explicit null.
+type JSONCondition struct {
+	Column string // Column name, a JSON column of the model
+	Path   string // Keys separated by dots, e.g. "limits.max"
+	Value  any    // Bool, number, string, nil, or a value marshalled to JSON
+}
+
+// FindForms retrieves a page of the forms of a tenant matching a condition
+// on one of their JSON columns, oldest first, without their questions.
+// Archived forms are left out.
+// Parameters:
+//   - tenantID: Tenant owning the forms, empty for the default tenant
+//   - cond: Condition on a JSON column of the forms
+//   - offset, limit: Page bounds
+//
+// Returns ErrInvalidJSONCondition for a malformed condition or any database error
+func (repo *Repository) FindForms(tenantID string, cond JSONCondition, offset, limit int) ([]entity.Form, error) {
+	query, err := repo.whereJSON(repo.db.Model(&entity.Form{}), cond)
+	if err != nil {
+		return nil, err
+	}
+
+	var forms []entity.Form
+
+	res := query.Scopes(notArchived).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&forms)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error find forms by JSON condition",
+			zap.String("tenant_id", tenantID),
+			zap.String("column", cond.Column),
+			zap.String("path", cond.Path),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return forms, nil
+}
+
+// FindQuestions retrieves the questions of a form, in order, matching a
+// condition on one of their JSON columns, e.g. the required questions
+// Returns ErrInvalidJSONCondition for a malformed condition or any database error
+func (repo *Repository) FindQuestions(formID uuid.UUID, cond JSONCondition) ([]entity.Question, error) {
+	query, err := repo.whereJSON(repo.db.Model(&entity.Question{}).Where("form_id = ?", formID), cond)
+	if err != nil {
+		return nil, err
+	}
+
+	var questions []entity.Question
+
+	if err = orderQuestions(query).Find(&questions).Error; err != nil {
+		repo.logger.Error("error find questions by JSON condition",
+			zap.String("form_id", formID.String()),
+			zap.String("column", cond.Column),
+			zap.String("path", cond.Path),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// whereJSON restricts query, on the model of a JSON column, to the rows
+// matching cond. MySQL, PostgreSQL and SQLite compare in the database; on
+// other databases the column is read and the rows are matched here, use it
+// with selective queries there.
+func (repo *Repository) whereJSON(query *gorm.DB, cond JSONCondition) (*gorm.DB, error) {
+	stmt := &gorm.Statement{DB: repo.db}
+	if err := stmt.Parse(query.Statement.Model); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	field := stmt.Schema.LookUpField(cond.Column)
+	if field == nil || (!strings.EqualFold(field.TagSettings["TYPE"], "json") && !strings.EqualFold(field.TagSettings["SERIALIZER"], "json")) {
+		return nil, fmt.Errorf("%w: %s is not a JSON column of %s", ErrInvalidJSONCondition, cond.Column, stmt.Schema.Name)
+	}
+
+	keys := strings.Split(cond.Path, ".")
+	for _, key := range keys {
+		if !jsonKey.MatchString(key) {
+			return nil, fmt.Errorf("%w: malformed path %q", ErrInvalidJSONCondition, cond.Path)
+		}
+	}
+
+	value, err := json.Marshal(cond.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: value is not JSON: %v", ErrInvalidJSONCondition, err)
+	}
+
+	column := stmt.Quote(field.DBName)
+	path := "$." + cond.Path
+
+	switch repo.db.Dialector.Name() {
+	case "mysql":
+		return query.Where("JSON_EXTRACT("+column+", ?) = CAST(? AS JSON)", path, string(value)), nil
+	case "postgres":
+		return query.Where(column+"::jsonb #> ? = ?::jsonb", "{"+strings.Join(keys, ",")+"}", string(value)), nil
+	case "sqlite":
+		// json_extract turns JSON into SQL values, null included
+		if cond.Value == nil {
+			return query.Where("json_type("+column+", ?) = 'null'", path), nil
+		}
+		return query.Where("json_extract("+column+", ?) = json_extract(?, '$')", path, string(value)), nil
+	}
+
+	return repo.matchJSON(query, stmt, column, keys, value)
+}
+
+// matchJSON is the fallback of whereJSON for databases without JSON
+// functions: it reads the column of the rows of query and keeps the rows
+// whose document matches
+func (repo *Repository) matchJSON(query *gorm.DB, stmt *gorm.Statement, column string, keys []string, value []byte) (*gorm.DB, error) {
+	var want any
+	if err := json.Unmarshal(value, &want); err != nil {
+		return nil, fmt.Errorf("%w: value is not JSON: %v", ErrInvalidJSONCondition, err)
+	}
+
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, fmt.Errorf("%w: %s has no primary key", ErrInvalidJSONCondition, stmt.Schema.Name)
+	}
+
+	rows, err := query.Session(&gorm.Session{}).Select(stmt.Quote(pk.DBName), column).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON column: %w", err)
+	}
+	defer rows.Close()
+
+	var matched []any
+	for rows.Next() {
+		var (
+			id  any
+			doc []byte
+		)
+		if err = rows.Scan(&id, &doc); err != nil {
+			return nil, fmt.Errorf("failed to read JSON column: %w", err)
+		}
+
+		if got, ok := jsonAt(doc, keys); ok && reflect.DeepEqual(got, want) {
+			matched = append(matched, id)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSON column: %w", err)
+	}
+
+	if len(matched) == 0 {
+		return query.Where("1 = 0"), nil
+	}
+
+	return query.Where(stmt.Quote(pk.DBName)+" IN ?", matched), nil
+}
+
+// jsonAt returns the value of doc at the path of keys, and whether there is one
+func jsonAt(doc []byte, keys []string) (any, bool) {
+	var value any
+	if err := json.Unmarshal(doc, &value); err != nil {
+		return nil, false
+	}
+
+	for _, key := range keys {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestRepository_FindQuestions(t *testing.T) {
+	repo, _ := setupRepository(t)
+	form := createForm(t, repo)
+
+	maxLength := 20
+	for i, rules := range []*entity.ValidationRules{
+		{Required: true},
+		{Required: true, MaxLength: &maxLength},
+		{Pattern: "^[a-z]+$"},
+		nil,
+	} {
+		require.NoError(t, repo.Create(&entity.Question{FormID: form.ID, Content: "question", OrderNumber: uint(i + 1), Rules: rules}))
+	}
+
+	positions := func(questions []entity.Question) []uint {
+		var positions []uint
+		for _, q := range questions {
+			positions = append(positions, q.OrderNumber)
+		}
+		return positions
+	}
+
+	tests := []struct {
+		name string
+		cond JSONCondition
+		want []uint
+	}{
+		{"bool", JSONCondition{Column: "rules", Path: "required", Value: true}, []uint{1, 2}},
+		{"number", JSONCondition{Column: "rules", Path: "max_length", Value: 20.0}, []uint{2}},
+		{"string", JSONCondition{Column: "rules", Path: "pattern", Value: "^[a-z]+$"}, []uint{3}},
+		{"no match", JSONCondition{Column: "rules", Path: "min", Value: 1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questions, err := repo.FindQuestions(form.ID, tt.cond)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, positions(questions))
+		})
+
+		t.Run(tt.name+" without JSON functions", func(t *testing.T) {
+			stmt := &gorm.Statement{DB: repo.db}
+			require.NoError(t, stmt.Parse(&entity.Question{}))
+
+			value, err := json.Marshal(tt.cond.Value)
+			require.NoError(t, err)
+
+			query, err := repo.matchJSON(repo.db.Model(&entity.Question{}).Where("form_id = ?", form.ID), stmt, "rules", []string{tt.cond.Path}, value)
+			require.NoError(t, err)
+
+			var questions []entity.Question
+			require.NoError(t, orderQuestions(query).Find(&questions).Error)
+			assert.Equal(t, tt.want, positions(questions))
+		})
+	}
+
+	t.Run("rejects malformed conditions", func(t *testing.T) {
+		_, err := repo.FindQuestions(form.ID, JSONCondition{Column: "content", Path: "required", Value: true})
+		assert.ErrorIs(t, err, ErrInvalidJSONCondition)
+
+		_, err = repo.FindQuestions(form.ID, JSONCondition{Column: "rules", Path: "required') OR 1=1 --", Value: true})
+		assert.ErrorIs(t, err, ErrInvalidJSONCondition)
+	})
+}
+
+func TestRepository_FindForms(t *testing.T) {
+	repo, _ := setupRepository(t)
+
+	form := &entity.Form{ID: uuid.New(), Author: "author", AllowedDomains: []string{"example.com"}}
+	require.NoError(t, repo.Create(form))
+
+	forms, err := repo.FindForms("", JSONCondition{Column: "allowed_domains", Path: "domain", Value: "example.com"}, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, forms)
+
+	_, err = repo.FindForms("", JSONCondition{Column: "title", Path: "x", Value: 1}, 0, 10)
+	assert.ErrorIs(t, err, ErrInvalidJSONCondition)
+}
+
+func TestRepository_WhereJSON_MySQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user@tcp(127.0.0.1:1)/forms", SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	repo := Init(db, &logger.Logger{Logger: zap.NewNop()})
+
+	query, err := repo.whereJSON(db.Model(&entity.Question{}), JSONCondition{Column: "rules", Path: "required", Value: true})
+	require.NoError(t, err)
+
+	stmt := query.Find(&[]entity.Question{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "JSON_EXTRACT(`rules`, ?) = CAST(? AS JSON)")
+	assert.Equal(t, []any{"$.required", "true"}, stmt.Vars)
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ReplaceBranches replaces the logic of a form and bumps its version in one
+// transaction
+// Parameters:
+//   - formID: UUID of the form
+//   - branches: The complete new logic, empty to remove it
+//
+// Returns error if the replacement fails
+func (repo *Repository) ReplaceBranches(formID uuid.UUID, branches []entity.Branch) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, formID, 0); err != nil {
+			return err
+		}
+
+		if err := tx.Where("form_id = ?", formID).Delete(&entity.Branch{}).Error; err != nil {
+			return err
+		}
+
+		if len(branches) == 0 {
+			return nil
+		}
+
+		for i := range branches {
+			branches[i].FormID = formID
+		}
+
+		return tx.Create(&branches).Error
+	})
+	if err != nil {
+		repo.logger.Error("error replace branches",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
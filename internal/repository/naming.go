@@ -0,0 +1,20 @@
+package repository
+
+import "gorm.io/gorm/schema"
+
+// NamingStrategy returns the GORM naming strategy for the service tables.
+// Every table, including the schema version table, gets the prefix, so the
+// service can share a database schema with other services. Pass it to
+// gorm.Open for both migrations and queries to agree on table names.
+// Parameters:
+//   - prefix: Table name prefix, e.g. "form_"; empty keeps the plain names
+//   - singular: Use singular table names (form, question) instead of plural ones
+//
+// Returns:
+//   - schema.NamingStrategy: Strategy to set as gorm.Config.NamingStrategy
+func NamingStrategy(prefix string, singular bool) schema.NamingStrategy {
+	return schema.NamingStrategy{
+		TablePrefix:   prefix,
+		SingularTable: singular,
+	}
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// respondentResponses matches the responses of a respondent, by respondent
+// ID or by email compared case-insensitively
+func respondentResponses(db *gorm.DB, respondent string) *gorm.DB {
+	return db.Where("respondent_id = ? OR LOWER(email) = ?", respondent, strings.ToLower(respondent))
+}
+
+// ListRespondentData retrieves everything stored about a respondent across
+// forms: their responses, oldest first, and the invites issued for their
+// email
+// Parameters:
+//   - respondent: Respondent ID or email
+func (repo *Repository) ListRespondentData(respondent string) ([]entity.Response, []entity.InviteToken, error) {
+	var (
+		responses []entity.Response
+		invites   []entity.InviteToken
+	)
+
+	if err := respondentResponses(repo.db, respondent).Order("created_at, id").Find(&responses).Error; err != nil {
+		repo.logger.Error("error list respondent responses", zap.Error(err))
+		return nil, nil, err
+	}
+
+	if err := repo.db.Where("email = ?", strings.ToLower(respondent)).Order("created_at, id").Find(&invites).Error; err != nil {
+		repo.logger.Error("error list respondent invites", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return responses, invites, nil
+}
+
+// EraseRespondentData permanently removes everything stored about a
+// respondent across forms in one transaction, see ListRespondentData
+// Returns the removed responses and invites
+func (repo *Repository) EraseRespondentData(respondent string) ([]entity.Response, []entity.InviteToken, error) {
+	var (
+		responses []entity.Response
+		invites   []entity.InviteToken
+	)
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := respondentResponses(tx, respondent).Find(&responses).Error; err != nil {
+			return err
+		}
+
+		if err := respondentResponses(tx, respondent).Delete(&entity.Response{}).Error; err != nil {
+			return err
+		}
+
+		email := strings.ToLower(respondent)
+		if err := tx.Where("email = ?", email).Find(&invites).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("email = ?", email).Delete(&entity.InviteToken{}).Error
+	})
+	if err != nil {
+		repo.logger.Error("error erase respondent data", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return responses, invites, nil
+}
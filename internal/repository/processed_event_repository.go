@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateFormOnce creates a form on behalf of a request event, in one
+// transaction with the record of the event, so the form is created exactly
+// once however often the event is delivered
+// Parameters:
+//   - event: ID and type of the request event
+//   - form: Form to create, with its questions
+//
+// Returns:
+//   - bool: False when the event was processed before and nothing was created
+//   - error: Any error that occurred, in which case neither is stored
+func (repo *Repository) CreateFormOnce(event entity.ProcessedEvent, form *entity.Form) (bool, error) {
+	created := false
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		event.ProcessedAt = time.Now()
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&event)
+		if err := res.Error; err != nil {
+			return err
+		}
+
+		if res.RowsAffected == 0 {
+			return nil // Processed before
+		}
+
+		if err := tx.Create(form).Error; err != nil {
+			return err
+		}
+
+		created = true
+
+		return nil
+	})
+	if err != nil {
+		repo.logger.Error("error create form once",
+			zap.String("event_id", event.EventID),
+			zap.String("form_id", form.ID.String()),
+			zap.Error(err))
+		return false, err
+	}
+
+	return created, nil
+}
+
+// PruneProcessedEvents forgets the events processed before a time, once
+// they can no longer be redelivered
+// Returns the number of records removed
+func (repo *Repository) PruneProcessedEvents(before time.Time) (int64, error) {
+	res := repo.db.Where("processed_at < ?", before).Delete(&entity.ProcessedEvent{})
+	if err := res.Error; err != nil {
+		repo.logger.Error("error prune processed events", zap.Error(err))
+		return 0, err
+	}
+
+	return res.RowsAffected, nil
+}
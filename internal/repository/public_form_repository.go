@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SavePublicForm stores the respondent projection of a form, replacing the
+// previous one
+//
+// Returns error if the save fails
+func (repo *Repository) SavePublicForm(form *entity.PublicForm) error {
+	res := repo.db.Save(form)
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error save public form",
+			zap.String("form_id", form.FormID.String()),
+			zap.Uint("version", form.Version),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetPublicForm retrieves the respondent projection of a form
+// Parameters:
+//   - formID: UUID of the form
+//
+// Returns:
+//   - *entity.PublicForm: Retrieved projection
+//   - error: gorm.ErrRecordNotFound if the form isn't published or closed, or any database error
+func (repo *Repository) GetPublicForm(formID uuid.UUID) (*entity.PublicForm, error) {
+	var form entity.PublicForm
+
+	if err := repo.db.Where("form_id = ?", formID).First(&form).Error; err != nil {
+		repo.logger.Error("error get public form",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &form, nil
+}
+
+// DeletePublicForm removes the respondent projection of a form. Removing a
+// form without one is not an error.
+func (repo *Repository) DeletePublicForm(formID uuid.UUID) error {
+	res := repo.db.Where("form_id = ?", formID).Delete(&entity.PublicForm{})
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error delete public form",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
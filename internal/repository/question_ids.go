@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QUESTION_ID_SCHEMA_VERSION is the schema version questions got UUIDs at,
+// replacing their auto-increment IDs
+const QUESTION_ID_SCHEMA_VERSION = 34
+
+// LEGACY_COPY_BATCH is how many rows of a legacy table are copied per insert
+const LEGACY_COPY_BATCH = 500
+
+// questionIDModels are the models whose tables hold question IDs
+var questionIDModels = []any{&entity.Question{}, &entity.Branch{}, &entity.Upload{}, &entity.QuestionStat{}}
+
+// questionIDs maps legacy question IDs to the UUIDs replacing them
+type questionIDs map[uint64]uuid.UUID
+
+// of returns the UUID replacing a legacy question ID as stored. IDs met
+// the first time get a new UUID, so questions only left in snapshots keep
+// one identity; 0, counting the responses in stats, becomes the nil UUID.
+func (ids questionIDs) of(legacy any) (string, error) {
+	var text string
+	switch v := legacy.(type) {
+	case []byte:
+		text = string(v)
+	default:
+		text = fmt.Sprint(v)
+	}
+
+	id, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid legacy question id %q: %w", text, err)
+	}
+
+	if id == 0 {
+		return uuid.Nil.String(), nil
+	}
+
+	if _, ok := ids[id]; !ok {
+		ids[id] = uuid.New()
+	}
+
+	return ids[id].String(), nil
+}
+
+// legacyTable names the copy of a table kept while its question IDs are
+// converted
+func legacyTable(table string) string {
+	return table + "_legacy"
+}
+
+// tableOf returns the table of a model under the naming strategy in use
+func (repo *Repository) tableOf(model any) (string, error) {
+	stmt := &gorm.Statement{DB: repo.db}
+	if err := stmt.Parse(model); err != nil {
+		return "", fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	return stmt.Schema.Table, nil
+}
+
+// setAsideQuestionTables copies the tables holding question IDs of a
+// database from before QUESTION_ID_SCHEMA_VERSION to legacy tables and drops
+// them, for AutoMigrate to create them with UUID columns. Copies carry no
+// indexes, so the new tables can take their names. A copy left by an
+// interrupted run is kept.
+// Returns error if a table can't be set aside
+func (repo *Repository) setAsideQuestionTables() error {
+	legacy, err := repo.hasLegacyQuestionIDs()
+	if err != nil || !legacy {
+		return err
+	}
+
+	migrator := repo.db.Migrator()
+
+	for _, model := range questionIDModels {
+		table, err := repo.tableOf(model)
+		if err != nil {
+			return err
+		}
+
+		// Tables added after the first release may not exist yet
+		legacy := legacyTable(table)
+		if !migrator.HasTable(legacy) && migrator.HasTable(table) {
+			if err = repo.db.Exec("CREATE TABLE ? AS SELECT * FROM ?", clause.Table{Name: legacy}, clause.Table{Name: table}).Error; err != nil {
+				return fmt.Errorf("failed to copy %s: %w", table, err)
+			}
+		}
+
+		if migrator.HasTable(table) {
+			if err = migrator.DropTable(table); err != nil {
+				return fmt.Errorf("failed to drop %s: %w", table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasLegacyQuestionIDs reports whether questions are still identified by
+// auto-increment IDs, judged by the type of their id column since databases
+// of the first release recorded no schema version. A copy set aside by an
+// interrupted run is still to be converted unless the conversion was
+// recorded.
+func (repo *Repository) hasLegacyQuestionIDs() (bool, error) {
+	table, err := repo.tableOf(&entity.Question{})
+	if err != nil {
+		return false, err
+	}
+
+	migrator := repo.db.Migrator()
+
+	if migrator.HasTable(legacyTable(table)) {
+		if !migrator.HasTable(&schemaMigration{}) {
+			return true, nil
+		}
+
+		applied, err := repo.AppliedSchemaVersion()
+		if err != nil {
+			return false, fmt.Errorf("failed to read schema version: %w", err)
+		}
+
+		return applied < QUESTION_ID_SCHEMA_VERSION, nil
+	}
+
+	if !migrator.HasTable(table) {
+		return false, nil
+	}
+
+	columns, err := migrator.ColumnTypes(table)
+	if err != nil {
+		return false, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+
+	for _, column := range columns {
+		if column.Name() == "id" {
+			return strings.Contains(strings.ToLower(column.DatabaseTypeName()), "int"), nil
+		}
+	}
+
+	return false, nil
+}
+
+// convertQuestionIDs copies the rows set aside by setAsideQuestionTables
+// back with UUIDs for their question IDs, rewrites the form snapshots of
+// versions and public forms the same way and drops the legacy tables. The
+// copy and QUESTION_ID_SCHEMA_VERSION are recorded in one transaction, so a
+// run interrupted afterwards only drops the legacy tables.
+// Returns error if the conversion fails
+func (repo *Repository) convertQuestionIDs() error {
+	questions, err := repo.tableOf(&entity.Question{})
+	if err != nil {
+		return err
+	}
+
+	migrator := repo.db.Migrator()
+	if !migrator.HasTable(legacyTable(questions)) {
+		return nil
+	}
+
+	applied, err := repo.AppliedSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	ids := make(questionIDs)
+
+	if applied < QUESTION_ID_SCHEMA_VERSION {
+		err = repo.db.Transaction(func(tx *gorm.DB) error {
+			tables := []struct {
+				model   any
+				columns []string
+			}{
+				{&entity.Question{}, []string{"id"}},
+				{&entity.Branch{}, []string{"source_id", "target_id"}},
+				{&entity.Upload{}, []string{"question_id"}},
+				{&entity.QuestionStat{}, []string{"question_id"}},
+			}
+
+			for _, t := range tables {
+				if err := repo.copyLegacyRows(tx, t.model, t.columns, ids); err != nil {
+					return err
+				}
+			}
+
+			if err := repo.rewriteSnapshots(tx, &entity.FormVersion{}, "id", ids); err != nil {
+				return err
+			}
+			if err := repo.rewriteSnapshots(tx, &entity.PublicForm{}, "form_id", ids); err != nil {
+				return err
+			}
+
+			return tx.Create(&schemaMigration{Version: QUESTION_ID_SCHEMA_VERSION, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to convert question ids: %w", err)
+		}
+	}
+
+	for _, model := range questionIDModels {
+		table, err := repo.tableOf(model)
+		if err != nil {
+			return err
+		}
+
+		if err = migrator.DropTable(legacyTable(table)); err != nil {
+			return fmt.Errorf("failed to drop legacy %s: %w", table, err)
+		}
+	}
+
+	repo.logger.Info("converted question ids to uuids", zap.Int("questions", len(ids)))
+
+	return nil
+}
+
+// copyLegacyRows copies the rows of the legacy table of model into its table,
+// the legacy question IDs in columns replaced by their UUIDs
+func (repo *Repository) copyLegacyRows(tx *gorm.DB, model any, columns []string, ids questionIDs) error {
+	table, err := repo.tableOf(model)
+	if err != nil {
+		return err
+	}
+
+	if !tx.Migrator().HasTable(legacyTable(table)) {
+		return nil
+	}
+
+	var rows []map[string]any
+	if err = tx.Table(legacyTable(table)).Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to read legacy %s: %w", table, err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for _, row := range rows {
+		for _, column := range columns {
+			if row[column], err = ids.of(row[column]); err != nil {
+				return fmt.Errorf("%s: %w", table, err)
+			}
+		}
+	}
+
+	if err = tx.Table(table).CreateInBatches(rows, LEGACY_COPY_BATCH).Error; err != nil {
+		return fmt.Errorf("failed to copy legacy %s: %w", table, err)
+	}
+
+	return nil
+}
+
+// rewriteSnapshots replaces the legacy question IDs in the form snapshots
+// of model, whose rows are keyed by key
+func (repo *Repository) rewriteSnapshots(tx *gorm.DB, model any, key string, ids questionIDs) error {
+	table, err := repo.tableOf(model)
+	if err != nil {
+		return err
+	}
+
+	var rows []map[string]any
+	if err = tx.Table(table).Select(key, "form").Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to read %s: %w", table, err)
+	}
+
+	for _, row := range rows {
+		var data []byte
+		switch v := row["form"].(type) {
+		case []byte:
+			data = v
+		case string:
+			data = []byte(v)
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		if data, err = rewriteSnapshot(data, ids); err != nil {
+			return fmt.Errorf("%s %v: %w", table, row[key], err)
+		}
+
+		if err = tx.Table(table).Where(key+" = ?", row[key]).Update("form", string(data)).Error; err != nil {
+			return fmt.Errorf("failed to rewrite %s %v: %w", table, row[key], err)
+		}
+	}
+
+	return nil
+}
+
+// rewriteSnapshot replaces the legacy question IDs of a form encoded as
+// JSON, those of its questions and of the questions its branches reference
+func rewriteSnapshot(data []byte, ids questionIDs) ([]byte, error) {
+	var form map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&form); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	replace := func(list any, keys ...string) error {
+		items, _ := list.([]any)
+		for _, item := range items {
+			fields, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			for _, key := range keys {
+				if _, ok := fields[key].(json.Number); !ok {
+					continue
+				}
+
+				id, err := ids.of(fields[key])
+				if err != nil {
+					return err
+				}
+				fields[key] = id
+			}
+		}
+
+		return nil
+	}
+
+	if err := replace(form["Questions"], "ID"); err != nil {
+		return nil, err
+	}
+	if err := replace(form["Branches"], "source_id", "target_id"); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(form)
+}
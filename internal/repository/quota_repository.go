@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetQuota retrieves the quota of an author
+// Parameters:
+//   - author: Author identifier
+//
+// Returns:
+//   - *entity.AuthorQuota: Counters and overrides of the author
+//   - error: gorm.ErrRecordNotFound if nothing was tracked for the author yet
+func (repo *Repository) GetQuota(author string) (*entity.AuthorQuota, error) {
+	var quota entity.AuthorQuota
+
+	res := repo.db.Where("author = ?", author).First(&quota)
+	if err := res.Error; err != nil {
+		return nil, err
+	}
+
+	return &quota, nil
+}
+
+// SaveQuotaLimits replaces the limit overrides of an author, keeping the counters
+// Parameters:
+//   - quota: Author and overrides to store
+//
+// Returns error if the save fails
+func (repo *Repository) SaveQuotaLimits(quota *entity.AuthorQuota) error {
+	res := repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "author"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_forms", "max_questions", "max_per_form", "updated_at"}),
+	}).Create(quota)
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error save quota limits",
+			zap.String("author", quota.Author),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ReserveQuota atomically adds forms and questions to the counters of an
+// author unless that would exceed the given limits
+// Parameters:
+//   - author: Author identifier
+//   - forms, questions: Amounts to add
+//   - limits: Effective limits, 0 means unlimited
+//
+// Returns:
+//   - bool: Whether the amounts were reserved
+//   - error: Any database error
+func (repo *Repository) ReserveQuota(author string, forms, questions int, limits entity.QuotaLimits) (bool, error) {
+	if err := repo.ensureQuota(author); err != nil {
+		return false, err
+	}
+
+	query := repo.db.Model(&entity.AuthorQuota{}).Where("author = ?", author)
+
+	if limits.MaxForms > 0 && forms > 0 {
+		query = query.Where("form_count + ? <= ?", forms, limits.MaxForms)
+	}
+
+	if limits.MaxQuestions > 0 && questions > 0 {
+		query = query.Where("question_count + ? <= ?", questions, limits.MaxQuestions)
+	}
+
+	res := query.Updates(map[string]any{
+		"form_count":     gorm.Expr("form_count + ?", forms),
+		"question_count": gorm.Expr("question_count + ?", questions),
+	})
+	if err := res.Error; err != nil {
+		repo.logger.Error("error reserve quota",
+			zap.String("author", author),
+			zap.Error(err))
+		return false, err
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// ReleaseQuota subtracts forms and questions from the counters of an author,
+// never going below zero
+// Parameters:
+//   - author: Author identifier
+//   - forms, questions: Amounts to subtract
+//
+// Returns error if the update fails
+func (repo *Repository) ReleaseQuota(author string, forms, questions int) error {
+	res := repo.db.Model(&entity.AuthorQuota{}).Where("author = ?", author).Updates(map[string]any{
+		"form_count":     gorm.Expr("CASE WHEN form_count > ? THEN form_count - ? ELSE 0 END", forms, forms),
+		"question_count": gorm.Expr("CASE WHEN question_count > ? THEN question_count - ? ELSE 0 END", questions, questions),
+	})
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error release quota",
+			zap.String("author", author),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ensureQuota creates the quota row of an author if it doesn't exist
+func (repo *Repository) ensureQuota(author string) error {
+	res := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.AuthorQuota{Author: author})
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error create quota",
+			zap.String("author", author),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
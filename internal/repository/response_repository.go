@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RESPONSE_BATCH_SIZE is the number of responses inserted per statement,
+// keeping large batches below the placeholder limits of the databases
+const RESPONSE_BATCH_SIZE = 100
+
+// CreateResponses inserts responses in batches of RESPONSE_BATCH_SIZE, in
+// one transaction
+// Parameters:
+//   - responses: Responses to insert
+//
+// Returns error if any insert fails, in which case none is stored
+func (repo *Repository) CreateResponses(responses []entity.Response) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(responses, RESPONSE_BATCH_SIZE).Error
+	})
+	if err != nil {
+		repo.logger.Error("error create responses",
+			zap.Int("count", len(responses)),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListResponses retrieves a page of the stored responses of a form, oldest first
+// Parameters:
+//   - formID: UUID of the form
+//   - filter: Condition on the responses, nil for all of them
+//   - offset: Responses to skip
+//   - limit: Max number of responses to return
+//
+// Returns:
+//   - []entity.Response: Responses of the page
+//   - error: entity.ErrInvalidFilter for a filter responses can't be
+//     narrowed down by, or any error that occurred during retrieval
+func (repo *Repository) ListResponses(formID uuid.UUID, filter *entity.Filter, offset, limit int) ([]entity.Response, error) {
+	query, err := whereFilter(repo.db.Where("form_id = ?", formID), filter, responseFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []entity.Response
+
+	res := query.Order("created_at, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&responses)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error list responses",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+// CountResponses returns the number of stored responses of a form
+// Parameters:
+//   - formID: UUID of the form
+//   - filter: Condition on the responses, nil for all of them
+//
+// Returns:
+//   - int64: Number of responses
+//   - error: entity.ErrInvalidFilter for a filter responses can't be
+//     narrowed down by, or any error that occurred during counting
+func (repo *Repository) CountResponses(formID uuid.UUID, filter *entity.Filter) (int64, error) {
+	query, err := whereFilter(repo.db.Model(&entity.Response{}).Where("form_id = ?", formID), filter, responseFilter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+
+	res := query.Count(&count)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error count responses",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
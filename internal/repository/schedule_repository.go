@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListDueSchedules retrieves forms whose scheduled opening or closing is
+// due at now, without their questions, oldest schedule first
+// Parameters:
+//   - now: Time schedules are due at
+//   - limit: Most forms returned
+//
+// Returns error if the query fails
+func (repo *Repository) ListDueSchedules(now time.Time, limit int) ([]entity.Form, error) {
+	var forms []entity.Form
+
+	res := repo.db.Where("opens_at <= ? OR closes_at <= ?", now, now).
+		Order("COALESCE(opens_at, closes_at), id").
+		Limit(limit).
+		Find(&forms)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error list due schedules", zap.Error(err))
+		return nil, err
+	}
+
+	return forms, nil
+}
+
+// OpenScheduled reopens a closed form whose scheduled opening is due at now
+// and clears the opening. Drafts are only published explicitly, as they
+// have no version to answer yet. The update is conditional, so of
+// concurrent instances a single one applies it.
+// Returns whether the form was opened by this call
+func (repo *Repository) OpenScheduled(formID uuid.UUID, now time.Time) (bool, error) {
+	return repo.applySchedule(formID, "opens_at", []string{entity.StatusClosed}, entity.StatusPublished, now)
+}
+
+// CloseScheduled closes a published form whose scheduled closing is due at
+// now and clears the closing, see OpenScheduled
+// Returns whether the form was closed by this call
+func (repo *Repository) CloseScheduled(formID uuid.UUID, now time.Time) (bool, error) {
+	return repo.applySchedule(formID, "closes_at", []string{entity.StatusPublished}, entity.StatusClosed, now)
+}
+
+// applySchedule moves a form whose column is due at now to status if it is
+// in one of the from states, and clears the column either way
+// Returns whether the form was moved
+func (repo *Repository) applySchedule(formID uuid.UUID, column string, from []string, status string, now time.Time) (bool, error) {
+	applied := false
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		due := tx.Model(&entity.Form{}).Where("id = ? AND "+column+" <= ?", formID, now)
+
+		res := due.Session(&gorm.Session{}).Where("status IN ?", from).
+			Updates(map[string]any{"status": status, column: nil})
+		if res.Error != nil {
+			return res.Error
+		}
+		applied = res.RowsAffected > 0
+
+		// The schedule of a form in another state is dropped, e.g. once archived
+		return due.Session(&gorm.Session{}).Update(column, nil).Error
+	})
+	if err != nil {
+		repo.logger.Error("error apply form schedule",
+			zap.String("form_id", formID.String()),
+			zap.String("column", column),
+			zap.Error(err))
+		return false, err
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SchemaVersion is the database schema version this build migrates to.
+// Bump it whenever a migrated model changes.
+const SchemaVersion = 35
+
+// STATUS_SCHEMA_VERSION is the schema version forms got their status at,
+// replacing the closed and archived flags
+const STATUS_SCHEMA_VERSION = 27
+
+// schemaMigration records a schema version applied to the database
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	AppliedAt time.Time
+}
+
+// Migrate brings the schema up to date and records SchemaVersion
+// Returns error if the migration fails
+func (repo *Repository) Migrate() error {
+	if err := repo.setAsideQuestionTables(); err != nil {
+		return err
+	}
+
+	// Read before AutoMigrate adds the column, defaulting every form to a draft
+	statusless := repo.formsLackStatus()
+
+	if err := repo.db.AutoMigrate(&entity.Form{}, &entity.Question{}, &entity.Upload{}, &entity.InviteToken{}, &entity.TenantSettings{}, &entity.AuthorQuota{}, &entity.QuestionStat{}, &entity.Response{}, &entity.FormVersion{}, &entity.Template{}, &entity.Section{}, &entity.Branch{}, &entity.PublicForm{}, &entity.Tag{}, &entity.Collaborator{}, &entity.ProcessedEvent{}, &entity.FormStats{}, &entity.Webhook{}, &schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	if err := repo.backfillFormStatus(statusless); err != nil {
+		return err
+	}
+
+	if err := repo.convertQuestionIDs(); err != nil {
+		return err
+	}
+
+	migration := schemaMigration{Version: SchemaVersion}
+
+	res := repo.db.Where(schemaMigration{Version: SchemaVersion}).
+		Attrs(schemaMigration{AppliedAt: time.Now()}).
+		FirstOrCreate(&migration)
+	if err := res.Error; err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+
+	repo.logger.Info("database schema migrated",
+		zap.Int("schema_version", SchemaVersion),
+		zap.Time("applied_at", migration.AppliedAt))
+
+	return nil
+}
+
+// formsLackStatus reports whether the forms table predates the status
+// column: it has the closed flag the status replaced but no status yet. This
+// holds for databases of versions that recorded no schema version as well.
+func (repo *Repository) formsLackStatus() bool {
+	migrator := repo.db.Migrator()
+
+	return migrator.HasTable(&entity.Form{}) &&
+		migrator.HasColumn(&entity.Form{}, "closed") && !migrator.HasColumn(&entity.Form{}, "status")
+}
+
+// backfillFormStatus sets the status of the forms of a database from before
+// STATUS_SCHEMA_VERSION from their closed and archived flags, the column
+// having defaulted every form to a draft. statusless tells whether the forms
+// table had no status before migrating, see formsLackStatus; a database
+// recording an older version is backfilled as well, in case a previous run
+// added the column but stopped before backfilling it. Databases from before
+// the archived flag only have the closed one. The flags are left in place for
+// instances of the previous version during a rolling upgrade.
+// Returns error if the backfill fails
+func (repo *Repository) backfillFormStatus(statusless bool) error {
+	applied, err := repo.AppliedSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	migrator := repo.db.Migrator()
+	if !migrator.HasColumn(&entity.Form{}, "closed") {
+		return nil
+	}
+	if !statusless && (applied == 0 || applied >= STATUS_SCHEMA_VERSION) {
+		return nil
+	}
+
+	status := gorm.Expr("CASE WHEN closed = ? THEN ? ELSE ? END",
+		true, entity.StatusClosed, entity.StatusPublished)
+	if migrator.HasColumn(&entity.Form{}, "archived") {
+		status = gorm.Expr("CASE WHEN archived = ? THEN ? WHEN closed = ? THEN ? ELSE ? END",
+			true, entity.StatusArchived, true, entity.StatusClosed, entity.StatusPublished)
+	}
+
+	res := repo.db.Unscoped().Model(&entity.Form{}).Where("1 = 1").UpdateColumn("status", status)
+	if err = res.Error; err != nil {
+		return fmt.Errorf("failed to backfill form status: %w", err)
+	}
+
+	repo.logger.Info("backfilled form status", zap.Int64("forms", res.RowsAffected))
+
+	return nil
+}
+
+// AppliedSchemaVersion returns the highest schema version recorded in the database
+// Returns:
+//   - int: Applied schema version, 0 if no migration was recorded
+//   - error: Any error that occurred during retrieval
+func (repo *Repository) AppliedSchemaVersion() (int, error) {
+	var migration schemaMigration
+
+	res := repo.db.Order("version desc").First(&migration)
+	if err := res.Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+
+		repo.logger.Error("error get schema version", zap.Error(err))
+		return 0, err
+	}
+
+	return migration.Version, nil
+}
+
+// ErrReadOnly is returned by writes while the repository is read-only
+var ErrReadOnly = errors.New("repository is read-only")
+
+// SchemaMismatchError reports a database whose schema version differs from
+// the one this build expects
+type SchemaMismatchError struct {
+	Expected int // SchemaVersion of this build
+	Applied  int // Highest version recorded in the database
+}
+
+func (e *SchemaMismatchError) Error() string {
+	if e.Applied > e.Expected {
+		return fmt.Sprintf("database schema version %d is newer than version %d of this build", e.Applied, e.Expected)
+	}
+
+	return fmt.Sprintf("database schema version %d is behind version %d of this build", e.Applied, e.Expected)
+}
+
+// CheckSchemaVersion compares the applied schema version with SchemaVersion
+// Returns:
+//   - error: *SchemaMismatchError on mismatch, or any error reading the version
+func (repo *Repository) CheckSchemaVersion() error {
+	applied, err := repo.AppliedSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if applied != SchemaVersion {
+		return &SchemaMismatchError{Expected: SchemaVersion, Applied: applied}
+	}
+
+	return nil
+}
+
+// SetReadOnly makes every following create, update and delete fail with
+// ErrReadOnly while reads keep working, e.g. for an instance running against
+// a schema it wasn't built for
+// Returns error if the write guards can't be registered
+func (repo *Repository) SetReadOnly() error {
+	reject := func(db *gorm.DB) {
+		db.AddError(ErrReadOnly)
+	}
+
+	callbacks := repo.db.Callback()
+
+	if err := callbacks.Create().Before("gorm:create").Register("form_service:read_only_create", reject); err != nil {
+		return fmt.Errorf("failed to guard creates: %w", err)
+	}
+
+	if err := callbacks.Update().Before("gorm:update").Register("form_service:read_only_update", reject); err != nil {
+		return fmt.Errorf("failed to guard updates: %w", err)
+	}
+
+	if err := callbacks.Delete().Before("gorm:delete").Register("form_service:read_only_delete", reject); err != nil {
+		return fmt.Errorf("failed to guard deletes: %w", err)
+	}
+
+	repo.logger.Warn("repository is read-only, writes are rejected")
+
+	return nil
+}
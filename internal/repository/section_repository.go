@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrSectionNotInForm is returned for a section ID that doesn't belong to
+// the form at hand
+var ErrSectionNotInForm = errors.New("section does not belong to form")
+
+// orderSections sorts preloaded sections by their position
+func orderSections(db *gorm.DB) *gorm.DB {
+	return db.Order("order_number")
+}
+
+// sameSection reports whether two questions are grouped in the same section
+func sameSection(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// GetSection retrieves a form section by ID
+// Parameters:
+//   - sectionID: UUID of the section
+//
+// Returns:
+//   - *entity.Section: Retrieved section
+//   - error: gorm.ErrRecordNotFound if there is no such section or any database error
+func (repo *Repository) GetSection(sectionID uuid.UUID) (*entity.Section, error) {
+	var section entity.Section
+
+	res := repo.db.Where("id = ?", sectionID).First(&section)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error get section",
+			zap.String("section_id", sectionID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &section, nil
+}
+
+// UpdateSection modifies multiple columns of a section and bumps the version
+// of its form
+// Parameters:
+//   - sectionID: UUID of the section to update
+//   - value: Struct or map containing the new values
+//
+// Returns error if the update fails or no such section exists
+func (repo *Repository) UpdateSection(sectionID uuid.UUID, value any) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersionOf(tx, &entity.Section{}, sectionID); err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Section{}).Where("id = ?", sectionID).Updates(value).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil {
+		repo.logger.Error("error update section",
+			zap.String("section_id", sectionID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSection removes a section and bumps the version of its form. Its
+// questions stay in the form, outside of any section.
+// Parameters:
+//   - sectionID: UUID of the section to delete
+//
+// Returns error if the deletion fails or no such section exists
+func (repo *Repository) DeleteSection(sectionID uuid.UUID) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersionOf(tx, &entity.Section{}, sectionID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Question{}).
+			Where("section_id = ?", sectionID).
+			Update("section_id", nil).Error; err != nil {
+			return err
+		}
+
+		res := tx.Where("id = ?", sectionID).Delete(&entity.Section{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		repo.logger.Error("error delete section",
+			zap.String("section_id", sectionID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ReorderSections renumbers the sections of a form in the given order,
+// from 1, and bumps the version of the form, all in one transaction.
+// Sections left out keep their number.
+// Parameters:
+//   - formID: UUID of the form
+//   - order: Section IDs in their new order
+//
+// Returns error ErrSectionNotInForm for foreign section IDs or any database error
+func (repo *Repository) ReorderSections(formID uuid.UUID, order []uuid.UUID) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, formID, 0); err != nil {
+			return err
+		}
+
+		for i, id := range order {
+			res := tx.Model(&entity.Section{}).
+				Where("id = ? AND form_id = ?", id, formID).
+				Update("order_number", i+1)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return fmt.Errorf("%w: section %s", ErrSectionNotInForm, id)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		repo.logger.Error("error reorder sections",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ReorderQuestions renumbers the questions of a form in the given order,
+// from 1, and bumps the version of the form, all in one transaction.
+// Questions left out keep their number.
+// Parameters:
+//   - formID: UUID of the form
+//   - order: Question IDs in their new order
+//
+// Returns error ErrQuestionNotInForm for foreign question IDs or any database error
+func (repo *Repository) ReorderQuestions(formID uuid.UUID, order []uuid.UUID) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, formID, 0); err != nil {
+			return err
+		}
+
+		for i, id := range order {
+			res := tx.Model(&entity.Question{}).
+				Where("id = ? AND form_id = ?", id, formID).
+				Update("order_number", i+1)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return fmt.Errorf("%w: question %s", ErrQuestionNotInForm, id)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		repo.logger.Error("error reorder questions",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// MoveQuestion groups a question in a section and bumps the version of its
+// form
+// Parameters:
+//   - questionID: ID of the question
+//   - sectionID: UUID of the section, nil to take the question out of its section
+//
+// Returns error if the update fails or no such question exists
+func (repo *Repository) MoveQuestion(questionID uuid.UUID, sectionID *uuid.UUID) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersionOf(tx, &entity.Question{}, questionID); err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Question{}).Where("id = ?", questionID).Update("section_id", sectionID).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil {
+		repo.logger.Error("error move question",
+			zap.String("question_id", questionID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddQuestionStats adds answer counts to the persisted statistics of a form
+// Parameters:
+//   - formID: UUID of the form
+//   - stats: Counts to add, keyed by question and value
+//
+// Returns error if the counts can't be stored; then none of them are
+func (repo *Repository) AddQuestionStats(formID uuid.UUID, stats []entity.QuestionStat) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, stat := range stats {
+			stat.FormID = formID
+
+			res := tx.Model(&entity.QuestionStat{}).
+				Where("form_id = ? AND question_id = ? AND value = ?", formID, stat.QuestionID, stat.Value).
+				Update("total", gorm.Expr("total + ?", stat.Total))
+			if err := res.Error; err != nil {
+				return err
+			}
+
+			if res.RowsAffected > 0 {
+				continue
+			}
+
+			if err := tx.Create(&stat).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		repo.logger.Error("error add question stats",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListQuestionStats retrieves the persisted statistics of a form
+// Parameters:
+//   - formID: UUID of the form
+//
+// Returns:
+//   - []entity.QuestionStat: Persisted counts of the form
+//   - error: Any error that occurred during retrieval
+func (repo *Repository) ListQuestionStats(formID uuid.UUID) ([]entity.QuestionStat, error) {
+	var stats []entity.QuestionStat
+
+	if err := repo.db.Where("form_id = ?", formID).Find(&stats).Error; err != nil {
+		repo.logger.Error("error list question stats",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// IncrementFormViews counts a view of the respondent view of a form
+// Parameters:
+//   - formID: UUID of the form
+//   - at: Time of the view, the last activity of the form
+//
+// Returns error if the count can't be stored
+func (repo *Repository) IncrementFormViews(formID uuid.UUID, at time.Time) error {
+	return repo.incrementFormStat(formID, "views", at)
+}
+
+// IncrementFormSubmissions counts an accepted response to a form
+// Parameters:
+//   - formID: UUID of the form
+//   - at: Time of the submission, the last activity of the form
+//
+// Returns error if the count can't be stored
+func (repo *Repository) IncrementFormSubmissions(formID uuid.UUID, at time.Time) error {
+	return repo.incrementFormStat(formID, "submissions", at)
+}
+
+// incrementFormStat adds one to a counter of a form in a single UPDATE, so
+// concurrent increments aren't lost, creating the row of the form first
+func (repo *Repository) incrementFormStat(formID uuid.UUID, column string, at time.Time) error {
+	err := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.FormStats{FormID: formID}).Error
+	if err == nil {
+		err = repo.db.Model(&entity.FormStats{}).
+			Where("form_id = ?", formID).
+			UpdateColumns(map[string]any{
+				column:             gorm.Expr(column + " + 1"),
+				"last_activity_at": at,
+			}).Error
+	}
+
+	if err != nil {
+		repo.logger.Error("error increment form stats",
+			zap.String("form_id", formID.String()),
+			zap.String("counter", column),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetFormStats retrieves the counters of a form
+// Parameters:
+//   - formID: UUID of the form
+//
+// Returns:
+//   - *entity.FormStats: Counters of the form, zero before any activity
+//   - error: Any error that occurred during retrieval
+func (repo *Repository) GetFormStats(formID uuid.UUID) (*entity.FormStats, error) {
+	stats := entity.FormStats{FormID: formID}
+
+	err := repo.db.Where("form_id = ?", formID).First(&stats).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		repo.logger.Error("error get form stats",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &stats, nil
+}
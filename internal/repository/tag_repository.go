@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// orderTags sorts preloaded tags by name
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
+
+// SetFormTags replaces the tags of a form, creating the tags that don't
+// exist yet, and bumps its version
+// Parameters:
+//   - formID: UUID of the form
+//   - tags: The complete new tags, empty to untag the form
+//
+// Returns gorm.ErrRecordNotFound if the form doesn't exist or any database error
+func (repo *Repository) SetFormTags(formID uuid.UUID, tags []entity.Tag) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		form := entity.Form{ID: formID}
+		if err := tx.Select("id").First(&form).Error; err != nil {
+			return err
+		}
+
+		if err := bumpVersion(tx, formID, 0); err != nil {
+			return err
+		}
+
+		association := tx.Model(&form).Association("Tags")
+		if len(tags) == 0 {
+			return association.Clear()
+		}
+
+		return association.Replace(tags)
+	})
+	if err != nil {
+		repo.logger.Error("error set form tags",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListFormsByTag retrieves a page of the forms of a tenant with a tag,
+// oldest first, with their tags but without their questions. Archived
+// forms are left out.
+// Parameters:
+//   - tenantID: Tenant owning the forms, empty for the default tenant
+//   - tag: Normalized tag name
+//   - filter: Condition on the forms, nil for all of them
+//   - offset, limit: Page bounds
+//
+// Returns:
+//   - []entity.Form: The forms of the page
+//   - error: entity.ErrInvalidFilter for a filter forms can't be narrowed
+//     down by, or an error if the query fails
+func (repo *Repository) ListFormsByTag(tenantID, tag string, filter *entity.Filter, offset, limit int) ([]entity.Form, error) {
+	query, err := repo.taggedForms(tenantID, tag, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var forms []entity.Form
+
+	res := query.Preload("Tags", orderTags).
+		Order("created_at, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&forms)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error list forms by tag",
+			zap.String("tenant_id", tenantID),
+			zap.String("tag", tag),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return forms, nil
+}
+
+// CountFormsByTag returns the number of forms of a tenant with a tag
+// matching filter, archived forms left out
+func (repo *Repository) CountFormsByTag(tenantID, tag string, filter *entity.Filter) (int64, error) {
+	query, err := repo.taggedForms(tenantID, tag, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+
+	if err = query.Count(&count).Error; err != nil {
+		repo.logger.Error("error count forms by tag",
+			zap.String("tenant_id", tenantID),
+			zap.String("tag", tag),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// taggedForms selects the forms of a tenant with a tag matching filter. The
+// join table is looked up in the form schema, as its name depends on the
+// naming strategy.
+func (repo *Repository) taggedForms(tenantID, tag string, filter *entity.Filter) (*gorm.DB, error) {
+	stmt := &gorm.Statement{DB: repo.db}
+	if err := stmt.Parse(&entity.Form{}); err != nil {
+		return nil, fmt.Errorf("failed to parse form schema: %w", err)
+	}
+
+	rel := stmt.Schema.Relationships.Relations["Tags"]
+
+	var formColumn, tagColumn string
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey {
+			formColumn = ref.ForeignKey.DBName
+		} else {
+			tagColumn = ref.ForeignKey.DBName
+		}
+	}
+
+	tagged := repo.db.Table(rel.JoinTable.Table).
+		Select(formColumn).
+		Where(tagColumn+" = ?", tag)
+
+	query := repo.db.Model(&entity.Form{}).
+		Scopes(notArchived).
+		Where("tenant_id = ?", tenantID).
+		Where("id IN (?)", tagged)
+
+	return whereFilter(query, filter, formFilter)
+}
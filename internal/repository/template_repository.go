@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GetTemplate retrieves a form template by ID
+// Parameters:
+//   - templateID: UUID of the template
+//
+// Returns:
+//   - *entity.Template: Retrieved template
+//   - error: gorm.ErrRecordNotFound if there is no such template or any database error
+func (repo *Repository) GetTemplate(templateID uuid.UUID) (*entity.Template, error) {
+	var template entity.Template
+
+	res := repo.db.Where("id = ?", templateID).First(&template)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error get template",
+			zap.String("template_id", templateID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &template, nil
+}
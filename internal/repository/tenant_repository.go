@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"go.uber.org/zap"
+)
+
+// GetTenantSettings retrieves the setting overrides of a tenant
+// Parameters:
+//   - tenantID: Identifier of the tenant
+//
+// Returns:
+//   - *entity.TenantSettings: Overrides or nil if not found
+//   - error: gorm.ErrRecordNotFound if the tenant has no overrides
+func (repo *Repository) GetTenantSettings(tenantID string) (*entity.TenantSettings, error) {
+	var settings entity.TenantSettings
+
+	res := repo.db.Where("tenant_id = ?", tenantID).First(&settings)
+	if err := res.Error; err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// SaveTenantSettings creates or replaces the setting overrides of a tenant
+// Parameters:
+//   - settings: Overrides to store
+//
+// Returns error if the save fails
+func (repo *Repository) SaveTenantSettings(settings *entity.TenantSettings) error {
+	res := repo.db.Save(settings)
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error save tenant settings",
+			zap.String("tenant_id", settings.TenantID),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
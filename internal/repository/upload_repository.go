@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// GetUpload retrieves upload metadata by its ID
+// Parameters:
+//   - uploadID: UUID of the upload
+//
+// Returns:
+//   - *entity.Upload: Retrieved upload or nil if not found
+//   - error: Any error that occurred during retrieval
+func (repo *Repository) GetUpload(uploadID uuid.UUID) (*entity.Upload, error) {
+	var upload entity.Upload
+
+	res := repo.db.Where("id = ?", uploadID).First(&upload)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error get upload",
+			zap.String("upload_id", uploadID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// UpdateUpload updates columns of an upload
+// Parameters:
+//   - uploadID: UUID of the upload
+//   - values: Map or struct with the columns to update
+//
+// Returns error if the update fails or the upload doesn't exist
+func (repo *Repository) UpdateUpload(uploadID uuid.UUID, values any) error {
+	res := repo.db.Model(&entity.Upload{}).Where("id = ?", uploadID).Updates(values)
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error update upload",
+			zap.String("upload_id", uploadID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ListOrphanUploads returns uploads not attached to a response whose
+// expiry is before the given time
+// Parameters:
+//   - before: Uploads expiring before this time are returned
+//   - limit: Max number of uploads to return
+//
+// Returns:
+//   - []entity.Upload: Orphaned uploads
+//   - error: Any error that occurred during retrieval
+func (repo *Repository) ListOrphanUploads(before time.Time, limit int) ([]entity.Upload, error) {
+	var uploads []entity.Upload
+
+	res := repo.db.
+		Where("status <> ? AND expires_at < ?", entity.UploadAttached, before).
+		Order("expires_at").
+		Limit(limit).
+		Find(&uploads)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error list orphan uploads", zap.Error(err))
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+// DeleteUpload removes upload metadata
+// Parameters:
+//   - uploadID: UUID of the upload
+//
+// Returns error if the deletion fails
+func (repo *Repository) DeleteUpload(uploadID uuid.UUID) error {
+	res := repo.db.Where("id = ?", uploadID).Delete(&entity.Upload{})
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error delete upload",
+			zap.String("upload_id", uploadID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CreateFormVersion snapshots the current state of a form, its questions, sections and logic
+// as its next version. Concurrent publishes of the same form can't create
+// the same version, the loser fails on the unique index.
+// Parameters:
+//   - formID: UUID of the form to snapshot
+//
+// Returns:
+//   - *entity.FormVersion: The created version
+//   - error: gorm.ErrRecordNotFound if the form doesn't exist or any database error
+func (repo *Repository) CreateFormVersion(formID uuid.UUID) (*entity.FormVersion, error) {
+	var version *entity.FormVersion
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var form entity.Form
+		if err := tx.Preload("Questions", orderQuestions).
+			Preload("Sections", orderSections).
+			Preload("Branches").
+			Where("ID = ?", formID).
+			First(&form).Error; err != nil {
+			return err
+		}
+
+		var latest uint
+		if err := tx.Model(&entity.FormVersion{}).
+			Where("form_id = ?", formID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		version = &entity.FormVersion{
+			ID:          uuid.New(),
+			FormID:      formID,
+			Version:     latest + 1,
+			Form:        form,
+			PublishedAt: time.Now(),
+		}
+
+		return tx.Create(version).Error
+	})
+	if err != nil {
+		repo.logger.Error("error create form version",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// GetFormVersion retrieves a published version of a form
+// Parameters:
+//   - formID: UUID of the form
+//   - version: Version number, 0 for the latest version
+//
+// Returns:
+//   - *entity.FormVersion: Retrieved version
+//   - error: gorm.ErrRecordNotFound if the form has no such version or any database error
+func (repo *Repository) GetFormVersion(formID uuid.UUID, version uint) (*entity.FormVersion, error) {
+	var stored entity.FormVersion
+
+	query := repo.db.Where("form_id = ?", formID)
+	if version == 0 {
+		query = query.Order("version desc")
+	} else {
+		query = query.Where("version = ?", version)
+	}
+
+	if err := query.First(&stored).Error; err != nil {
+		repo.logger.Error("error get form version",
+			zap.String("form_id", formID.String()),
+			zap.Uint("version", version),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+// GetFormVersionAt retrieves the version of a form in effect at a time: the
+// latest one published by then
+// Returns gorm.ErrRecordNotFound if the form had no published version yet
+func (repo *Repository) GetFormVersionAt(formID uuid.UUID, at time.Time) (*entity.FormVersion, error) {
+	var stored entity.FormVersion
+
+	res := repo.db.Where("form_id = ? AND published_at <= ?", formID, at).
+		Order("version desc").
+		First(&stored)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error get form version at",
+			zap.String("form_id", formID.String()),
+			zap.Time("at", at),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &stored, nil
+}
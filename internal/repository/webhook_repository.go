@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetWebhook retrieves a webhook by its ID
+// Returns gorm.ErrRecordNotFound if there is no such webhook
+func (repo *Repository) GetWebhook(webhookID uuid.UUID) (*entity.Webhook, error) {
+	var webhook entity.Webhook
+
+	if err := repo.db.Where("id = ?", webhookID).First(&webhook).Error; err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// SaveWebhook adds a webhook to a form, or replaces the URL and template of
+// an existing one
+// Parameters:
+//   - webhook: Webhook to store, with its ID set
+//
+// Returns error if the save fails
+func (repo *Repository) SaveWebhook(webhook *entity.Webhook) error {
+	res := repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"url", "template_kind", "template", "updated_at"}),
+	}).Create(webhook)
+
+	if err := res.Error; err != nil {
+		repo.logger.Error("error save webhook",
+			zap.String("form_id", webhook.FormID.String()),
+			zap.String("webhook_id", webhook.ID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhook removes a webhook
+// Returns gorm.ErrRecordNotFound if there is no such webhook
+func (repo *Repository) DeleteWebhook(webhookID uuid.UUID) error {
+	res := repo.db.Where("id = ?", webhookID).Delete(&entity.Webhook{})
+	if err := res.Error; err != nil {
+		repo.logger.Error("error delete webhook",
+			zap.String("webhook_id", webhookID.String()),
+			zap.Error(err))
+		return err
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ListWebhooks returns the webhooks of a form, oldest first
+func (repo *Repository) ListWebhooks(formID uuid.UUID) ([]entity.Webhook, error) {
+	var webhooks []entity.Webhook
+
+	res := repo.db.Where("form_id = ?", formID).Order("created_at, id").Find(&webhooks)
+	if err := res.Error; err != nil {
+		repo.logger.Error("error list webhooks",
+			zap.String("form_id", formID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return webhooks, nil
+}
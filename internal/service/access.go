@@ -0,0 +1,306 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// INVITE_TOKEN_BYTES is the entropy of generated invite tokens
+const INVITE_TOKEN_BYTES = 32
+
+// InviteOptions configures a generated invite token
+type InviteOptions struct {
+	SingleUse bool          // Token is consumed by its first submission
+	TTL       time.Duration // Token lifetime, 0 never expires
+}
+
+// redemptionKey is the cache key guarding redemption of a single-use invite
+func redemptionKey(inviteID uuid.UUID) string {
+	return fmt.Sprintf("invite:%s:redeemed", inviteID)
+}
+
+var (
+	// ErrAccessDenied is returned when a respondent may not answer a form
+	ErrAccessDenied = errors.New("access denied")
+
+	// Reasons for denying access, each wrapping ErrAccessDenied
+	ErrDomainNotAllowed      = fmt.Errorf("%w: email domain is not allowed", ErrAccessDenied)
+	ErrInviteRequired        = fmt.Errorf("%w: invite token required", ErrAccessDenied)
+	ErrInviteUnknown         = fmt.Errorf("%w: unknown invite token", ErrAccessDenied)
+	ErrInviteRevoked         = fmt.Errorf("%w: invite token revoked", ErrAccessDenied)
+	ErrInviteExpired         = fmt.Errorf("%w: invite token expired", ErrAccessDenied)
+	ErrInviteUsed            = fmt.Errorf("%w: invite token already used", ErrAccessDenied)
+	ErrInviteWrongRespondent = fmt.Errorf("%w: invite token issued for another respondent", ErrAccessDenied)
+)
+
+// hashInviteToken returns the stored representation of a token
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeDomain lowercases a domain and strips a leading "@"
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+}
+
+// SetAccess replaces the respondent restrictions of a form.
+func (s *Service) SetAccess(formID uuid.UUID, allowedDomains []string, inviteOnly bool) error {
+	domains := make([]string, 0, len(allowedDomains))
+	for _, domain := range allowedDomains {
+		if domain = normalizeDomain(domain); domain != "" && !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+
+	if err := s.authorize(formID); err != nil {
+		return err
+	}
+
+	previous, err := s.previousForm(formID)
+	if err != nil {
+		return err
+	}
+
+	// 1. Critical operation first (database)
+	if err := s.repo.UpdateAccess(formID, domains, inviteOnly); err != nil {
+		return fmt.Errorf("failed to update form access in repository: %w", err)
+	}
+
+	// 2. Get updated form
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve updated form: %w", err)
+	}
+
+	payload, err := s.formUpdate(previous, form)
+	if err != nil {
+		return err
+	}
+
+	// 3. Cache and publish
+	return s.syncForm(form, payload, events.FormUpdated)
+}
+
+// CreateInvite issues an invite token for a form, optionally bound to an
+// email. The token is only returned here and in the published event; the
+// repository keeps its hash.
+func (s *Service) CreateInvite(formID uuid.UUID, email string, opts InviteOptions) (*entity.OutputInvite, error) {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeForm(form); err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, INVITE_TOKEN_BYTES)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate invite token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	invite := &entity.InviteToken{
+		ID:        uuid.New(),
+		FormID:    formID,
+		TokenHash: hashInviteToken(token),
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		SingleUse: opts.SingleUse,
+		CreatedAt: time.Now(),
+	}
+
+	if opts.TTL > 0 {
+		expiresAt := invite.CreatedAt.Add(opts.TTL)
+		invite.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.Create(invite); err != nil {
+		return nil, fmt.Errorf("failed to create invite in repository: %w", err)
+	}
+
+	output := invite.ToOutput()
+	output.Token = token
+
+	if err := s.publish(events.InviteCreated, &output); err != nil {
+		return &output, fmt.Errorf("publish error: %w", err)
+	}
+
+	return &output, nil
+}
+
+// RevokeInvite revokes an invite token so it can't be used anymore.
+func (s *Service) RevokeInvite(inviteID uuid.UUID) error {
+	if s.actor != "" {
+		invite, err := s.repo.GetInvite(inviteID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve invite: %w", err)
+		}
+
+		if err = s.authorize(invite.FormID); err != nil {
+			return err
+		}
+	}
+
+	invite, err := s.repo.RevokeInvite(inviteID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke invite in repository: %w", err)
+	}
+
+	output := invite.ToOutput()
+
+	if err = s.publish(events.InviteRevoked, &output); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
+
+// ListInvites returns the invites of a form without their tokens.
+func (s *Service) ListInvites(formID uuid.UUID) ([]entity.OutputInvite, error) {
+	if err := s.authorizeRead(formID); err != nil {
+		return nil, err
+	}
+
+	invites, err := s.repo.ListInvites(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+
+	output := make([]entity.OutputInvite, len(invites))
+	for i := range invites {
+		output[i] = invites[i].ToOutput()
+	}
+
+	return output, nil
+}
+
+// PublishInviteList sends an invite listing back to the requester
+func (s *Service) PublishInviteList(list *entity.InviteList) error {
+	return s.publish(events.InviteList, list)
+}
+
+// CheckAccess validates that a respondent may submit a response to the form
+// without consuming a single-use invite. It returns an error wrapping
+// ErrAccessDenied when the form restrictions reject the respondent.
+func (s *Service) CheckAccess(form *entity.Form, email, token string) error {
+	_, err := s.checkAccess(form, email, token)
+	return err
+}
+
+// RedeemAccess validates access like CheckAccess and redeems a single-use
+// invite. It must be called on every submission. Redemption is guarded by a
+// Redis marker so concurrent submissions fail fast, and made durable by a
+// conditional update in the repository.
+func (s *Service) RedeemAccess(form *entity.Form, email, token string) error {
+	_, err := s.redeemAccess(form, email, token)
+	return err
+}
+
+// redeemAccess is RedeemAccess, returning the single-use invite it redeemed,
+// nil for other submissions, so it can be released again, see releaseInvite
+func (s *Service) redeemAccess(form *entity.Form, email, token string) (*entity.InviteToken, error) {
+	invite, err := s.checkAccess(form, email, token)
+	if err != nil || invite == nil || !invite.SingleUse {
+		return nil, err
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	ttl := time.Duration(0)
+	if invite.ExpiresAt != nil {
+		ttl = time.Until(*invite.ExpiresAt)
+	}
+
+	first, err := s.casher.AddToCashIfAbsent(ctx, redemptionKey(invite.ID), time.Now().Unix(), ttl)
+	if err == nil && !first {
+		return nil, ErrInviteUsed
+	}
+
+	if err = s.repo.RedeemInvite(invite.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteUsed
+		}
+
+		// Let the submission be retried with the same token
+		if first {
+			_ = s.casher.RemoveFromCash(ctx, redemptionKey(invite.ID))
+		}
+
+		return nil, fmt.Errorf("failed to redeem invite: %w", err)
+	}
+
+	return invite, nil
+}
+
+// releaseInvite undoes the redemption of invite, the result of redeemAccess,
+// so the submission it was redeemed for can be retried with the same token
+func (s *Service) releaseInvite(invite *entity.InviteToken) error {
+	if invite == nil {
+		return nil
+	}
+
+	if err := s.repo.ReleaseInvite(invite.ID); err != nil {
+		return fmt.Errorf("failed to release invite: %w", err)
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	return s.casher.RemoveFromCash(ctx, redemptionKey(invite.ID))
+}
+
+// checkAccess implements CheckAccess and returns the matched invite, if any
+func (s *Service) checkAccess(form *entity.Form, email, token string) (*entity.InviteToken, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	if len(form.AllowedDomains) > 0 {
+		at := strings.LastIndexByte(email, '@')
+		if at < 0 || !slices.Contains(form.AllowedDomains, email[at+1:]) {
+			return nil, ErrDomainNotAllowed
+		}
+	}
+
+	if !form.InviteOnly {
+		return nil, nil
+	}
+
+	if token == "" {
+		return nil, ErrInviteRequired
+	}
+
+	invite, err := s.repo.GetInviteByHash(form.ID, hashInviteToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteUnknown
+		}
+		return nil, fmt.Errorf("failed to retrieve invite: %w", err)
+	}
+
+	switch {
+	case invite.IsRevoked():
+		return nil, ErrInviteRevoked
+	case invite.IsExpired(time.Now()):
+		return nil, ErrInviteExpired
+	case invite.SingleUse && invite.IsRedeemed():
+		return nil, ErrInviteUsed
+	case invite.Email != "" && invite.Email != email:
+		return nil, ErrInviteWrongRespondent
+	}
+
+	return invite, nil
+}
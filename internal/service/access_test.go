@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestService_SetAccess(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, AllowedDomains: []string{"example.com"}, InviteOnly: true}
+
+	mockRepo.On("UpdateAccess", formID, []string{"example.com"}, true).Return(nil)
+	mockRepo.On("Get", formID).Return(form, nil)
+	mockCasher.On("AddToCash", mock.Anything, formID.String(), form).Return(nil)
+	mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+	err := service.SetAccess(formID, []string{" @Example.com", "example.com", ""}, true)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestService_CreateInvite(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	var stored *entity.InviteToken
+
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*entity.InviteToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*entity.InviteToken) }).
+		Return(nil)
+	mockPublisher.On("Publish", mock.AnythingOfType("*entity.OutputInvite"), events.InviteCreated.String()).Return(nil)
+
+	invite, err := service.CreateInvite(formID, "Bob@Example.com", InviteOptions{SingleUse: true, TTL: time.Hour})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, invite.Token)
+	assert.Equal(t, "bob@example.com", invite.Email)
+	assert.Equal(t, hashInviteToken(invite.Token), stored.TokenHash)
+	assert.NotContains(t, stored.TokenHash, invite.Token, "only the hash is stored")
+	assert.True(t, stored.SingleUse)
+	require.NotNil(t, stored.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *stored.ExpiresAt, time.Minute)
+}
+
+func TestService_RevokeInvite(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+
+	inviteID := uuid.New()
+	now := time.Now()
+
+	mockRepo.On("RevokeInvite", inviteID).Return(&entity.InviteToken{ID: inviteID, RevokedAt: &now}, nil)
+	mockPublisher.On("Publish", mock.AnythingOfType("*entity.OutputInvite"), events.InviteRevoked.String()).Return(nil)
+
+	assert.NoError(t, service.RevokeInvite(inviteID))
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestService_CheckAccess(t *testing.T) {
+	formID := uuid.New()
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		form    entity.Form
+		email   string
+		token   string
+		invite  *entity.InviteToken
+		wantErr bool
+	}{
+		{name: "unrestricted", form: entity.Form{ID: formID}},
+		{name: "allowed domain", form: entity.Form{ID: formID, AllowedDomains: []string{"example.com"}}, email: "Ann@Example.com"},
+		{name: "other domain", form: entity.Form{ID: formID, AllowedDomains: []string{"example.com"}}, email: "ann@evil.com", wantErr: true},
+		{name: "subdomain trick", form: entity.Form{ID: formID, AllowedDomains: []string{"example.com"}}, email: "ann@example.com.evil.com", wantErr: true},
+		{name: "missing token", form: entity.Form{ID: formID, InviteOnly: true}, wantErr: true},
+		{name: "valid token", form: entity.Form{ID: formID, InviteOnly: true}, token: "t", invite: &entity.InviteToken{}},
+		{name: "revoked token", form: entity.Form{ID: formID, InviteOnly: true}, token: "t", invite: &entity.InviteToken{RevokedAt: &revokedAt}, wantErr: true},
+		{name: "token for another email", form: entity.Form{ID: formID, InviteOnly: true}, email: "ann@example.com", token: "t", invite: &entity.InviteToken{Email: "bob@example.com"}, wantErr: true},
+		{name: "expired token", form: entity.Form{ID: formID, InviteOnly: true}, token: "t", invite: &entity.InviteToken{ExpiresAt: &revokedAt}, wantErr: true},
+		{name: "redeemed single-use token", form: entity.Form{ID: formID, InviteOnly: true}, token: "t", invite: &entity.InviteToken{SingleUse: true, RedeemedAt: &revokedAt}, wantErr: true},
+		{name: "unknown token", form: entity.Form{ID: formID, InviteOnly: true}, token: "t", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, mockRepo, _ := setupService()
+
+			if tt.invite != nil {
+				mockRepo.On("GetInviteByHash", formID, hashInviteToken(tt.token)).Return(tt.invite, nil)
+			} else {
+				mockRepo.On("GetInviteByHash", formID, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+			}
+
+			err := service.CheckAccess(&tt.form, tt.email, tt.token)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrAccessDenied)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_RedeemAccess(t *testing.T) {
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, InviteOnly: true}
+	invite := &entity.InviteToken{ID: uuid.New(), FormID: formID, SingleUse: true}
+	key := redemptionKey(invite.ID)
+
+	t.Run("redeems a single-use token once", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+
+		mockRepo.On("GetInviteByHash", formID, hashInviteToken("t")).Return(invite, nil)
+		mockCasher.On("AddToCashIfAbsent", mock.Anything, key, mock.Anything, time.Duration(0)).Return(true, nil).Once()
+		mockRepo.On("RedeemInvite", invite.ID).Return(nil)
+
+		assert.NoError(t, service.RedeemAccess(form, "", "t"))
+
+		mockCasher.On("AddToCashIfAbsent", mock.Anything, key, mock.Anything, time.Duration(0)).Return(false, nil)
+
+		assert.ErrorIs(t, service.RedeemAccess(form, "", "t"), ErrAccessDenied)
+		mockRepo.AssertNumberOfCalls(t, "RedeemInvite", 1)
+	})
+
+	t.Run("database rejects a concurrent redemption", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+
+		mockRepo.On("GetInviteByHash", formID, hashInviteToken("t")).Return(invite, nil)
+		mockCasher.On("AddToCashIfAbsent", mock.Anything, key, mock.Anything, time.Duration(0)).Return(true, nil)
+		mockRepo.On("RedeemInvite", invite.ID).Return(gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, service.RedeemAccess(form, "", "t"), ErrAccessDenied)
+	})
+
+	t.Run("releases the marker when the database fails", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+
+		mockRepo.On("GetInviteByHash", formID, hashInviteToken("t")).Return(invite, nil)
+		mockCasher.On("AddToCashIfAbsent", mock.Anything, key, mock.Anything, time.Duration(0)).Return(true, nil)
+		mockRepo.On("RedeemInvite", invite.ID).Return(errors.New("db down"))
+		mockCasher.On("RemoveFromCash", mock.Anything, key).Return(nil)
+
+		err := service.RedeemAccess(form, "", "t")
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrAccessDenied)
+		mockCasher.AssertCalled(t, "RemoveFromCash", mock.Anything, key)
+	})
+
+	t.Run("reusable tokens are not redeemed", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		mockRepo.On("GetInviteByHash", formID, hashInviteToken("t")).Return(&entity.InviteToken{ID: uuid.New()}, nil)
+
+		assert.NoError(t, service.RedeemAccess(form, "", "t"))
+		mockRepo.AssertNotCalled(t, "RedeemInvite", mock.Anything)
+	})
+}
+
+func TestService_ListInvites(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	formID := uuid.New()
+	mockRepo.On("ListInvites", formID).Return([]entity.InviteToken{{ID: uuid.New(), FormID: formID, TokenHash: "secret"}}, nil)
+
+	invites, err := service.ListInvites(formID)
+
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	assert.Empty(t, invites[0].Token)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// ArchiveForm archives a form: unlike closing it, the form also leaves the
+// listings and the respondent projection, while staying retrievable by ID.
+// It is published as form.archived.
+func (s *Service) ArchiveForm(formID uuid.UUID) error {
+	return s.setStatus(formID, entity.StatusArchived, "")
+}
+
+// UnarchiveForm brings an archived form back closed, to be reopened with
+// SetStatus, and publishes it as form.unarchived
+// Returns entity.ErrInvalidTransition if the form isn't archived
+func (s *Service) UnarchiveForm(formID uuid.UUID) error {
+	return s.setStatus(formID, entity.StatusClosed, entity.StatusArchived)
+}
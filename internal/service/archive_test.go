@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_ArchiveForm(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	published := &entity.Form{ID: formID, Status: entity.StatusPublished, Version: 3}
+	archived := &entity.Form{ID: formID, Status: entity.StatusArchived, Version: 4}
+	mockRepo.On("Get", formID).Return(published, nil).Once()
+	mockRepo.On("Update", formID, uint(3), "Status", entity.StatusArchived).Return(nil)
+	mockRepo.On("Get", formID).Return(archived, nil).Twice()
+	mockCasher.On("AddToCash", mock.Anything, formID.String(), archived).Return(nil)
+	mockPublisher.On("Publish", archived, events.FormArchived.String()).Return(nil)
+
+	assert.NoError(t, service.ArchiveForm(formID))
+	assert.False(t, archived.AcceptsResponses())
+
+	unarchived := &entity.Form{ID: formID, Status: entity.StatusClosed, Version: 5}
+	mockRepo.On("Update", formID, uint(4), "Status", entity.StatusClosed).Return(nil)
+	mockRepo.On("Get", formID).Return(unarchived, nil).Once()
+	mockCasher.On("AddToCash", mock.Anything, formID.String(), unarchived).Return(nil)
+	mockPublisher.On("Publish", unarchived, events.FormUnarchived.String()).Return(nil)
+
+	assert.NoError(t, service.UnarchiveForm(formID))
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestService_UnarchiveForm_NotArchived(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Status: entity.StatusPublished}, nil)
+
+	err := service.UnarchiveForm(formID)
+
+	assert.ErrorIs(t, err, entity.ErrInvalidTransition)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestService_SubmitResponse_ArchivedForm(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Status: entity.StatusArchived}, nil)
+	mockPublisher.On("Publish", mock.AnythingOfType("*entity.ResponseRejection"), events.ResponseRejected.String()).Return(nil)
+
+	err := service.SubmitResponse(&entity.ResponseSubmission{FormID: formID.String(), RespondentID: "respondent"})
+
+	assert.ErrorIs(t, err, ErrFormClosed)
+	mockPublisher.AssertExpectations(t)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+type (
+	// CacheInspector reads cached entries with their TTL, see casher.InspectCash
+	CacheInspector interface {
+		InspectCash(ctx context.Context, key string) ([]byte, time.Duration, error)
+	}
+)
+
+// ErrCacheInspectionDisabled is returned when inspecting the cache without an inspector
+var ErrCacheInspectionDisabled = errors.New("cache inspection is not configured")
+
+// EnableCacheInspection lets InspectCache read cached forms through inspector
+func (s *Service) EnableCacheInspection(inspector CacheInspector) {
+	s.inspector = inspector
+}
+
+// InspectCache returns what the cache holds for a form: the cached payload,
+// its size and remaining TTL. Forms that aren't cached are reported as such.
+func (s *Service) InspectCache(formID uuid.UUID) (*entity.CacheEntry, error) {
+	if s.inspector == nil {
+		return nil, ErrCacheInspectionDisabled
+	}
+
+	if err := s.authorizeAdmin(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	data, ttl, err := s.inspector.InspectCash(ctx, formID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect cached form: %w", err)
+	}
+
+	return entity.NewCacheEntry(formID.String(), data, ttl), nil
+}
+
+// PublishCacheEntry replies to a cache inspection request
+func (s *Service) PublishCacheEntry(reply *entity.CacheEntryReply) error {
+	return s.publish(events.CacheEntry, reply)
+}
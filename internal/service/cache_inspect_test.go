@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedInspector is a CacheInspector returning the same entry for every key
+type fixedInspector struct {
+	data []byte
+	ttl  time.Duration
+	err  error
+	key  string
+}
+
+func (f *fixedInspector) InspectCash(_ context.Context, key string) ([]byte, time.Duration, error) {
+	f.key = key
+	return f.data, f.ttl, f.err
+}
+
+func TestService_InspectCache(t *testing.T) {
+	formID := uuid.New()
+
+	t.Run("is disabled without an inspector", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		_, err := service.InspectCache(formID)
+
+		assert.ErrorIs(t, err, ErrCacheInspectionDisabled)
+	})
+
+	t.Run("reports the cached payload, size and TTL", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		inspector := &fixedInspector{data: []byte(`{"title":"cached"}`), ttl: 90 * time.Second}
+		service.EnableCacheInspection(inspector)
+
+		entry, err := service.InspectCache(formID)
+
+		require.NoError(t, err)
+		assert.Equal(t, formID.String(), inspector.key)
+		assert.True(t, entry.Cached)
+		assert.JSONEq(t, `{"title":"cached"}`, string(entry.Payload))
+		assert.Equal(t, 18, entry.Bytes)
+		assert.True(t, entry.Expires)
+		assert.Equal(t, 90.0, entry.TTLSeconds)
+	})
+
+	t.Run("reports entries without expiry", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		service.EnableCacheInspection(&fixedInspector{data: []byte(`{}`), ttl: -1})
+
+		entry, err := service.InspectCache(formID)
+
+		require.NoError(t, err)
+		assert.True(t, entry.Cached)
+		assert.False(t, entry.Expires)
+	})
+
+	t.Run("reports forms that aren't cached", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		service.EnableCacheInspection(&fixedInspector{})
+
+		entry, err := service.InspectCache(formID)
+
+		require.NoError(t, err)
+		assert.False(t, entry.Cached)
+		assert.Zero(t, entry.Bytes)
+		assert.Nil(t, entry.Payload)
+	})
+
+	t.Run("returns cache errors", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		service.EnableCacheInspection(&fixedInspector{err: errors.New("redis down")})
+
+		_, err := service.InspectCache(formID)
+
+		assert.ErrorContains(t, err, "redis down")
+	})
+}
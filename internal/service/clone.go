@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// CloneForm copies a form, with its questions, sections, branches and
+// settings, into a new form of newAuthor and publishes form.created for it.
+// The copy counts against the quota of newAuthor like a created form, which
+// is released again if storing the copy fails, see storeNewForm.
+func (s *Service) CloneForm(sourceID uuid.UUID, newAuthor string) (_ *entity.Form, err error) {
+	defer observe("clone_form", time.Now(), &err)
+
+	if newAuthor == "" {
+		return nil, errors.New("author cannot be empty")
+	}
+
+	source, err := s.repo.Get(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	clone := source.Clone(newAuthor)
+
+	if err = s.assignSlug(clone); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkTenantRate(clone.TenantID); err != nil {
+		return nil, err
+	}
+
+	if err = s.storeNewForm("clone_form", clone, func() error {
+		if err := s.repo.CloneForm(clone); err != nil {
+			return fmt.Errorf("failed to clone form in repository: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CloneForm(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	sourceID := uuid.New()
+	source := &entity.Form{
+		ID:             sourceID,
+		Title:          "Party",
+		Author:         "alice",
+		Status:         entity.StatusClosed,
+		InviteOnly:     true,
+		AllowedDomains: []string{"example.com"},
+		Questions:      []entity.Question{{FormID: sourceID, Content: "Coming?", OrderNumber: 1}},
+	}
+
+	mockRepo.On("Get", sourceID).Return(source, nil)
+	mockRepo.On("CloneForm", mock.AnythingOfType("*entity.Form")).Return(nil)
+	mockCasher.On("AddToCash", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Form")).Return(nil)
+	mockPublisher.On("Publish", mock.AnythingOfType("*entity.Form"), events.FormCreated.String()).Return(nil)
+
+	clone, err := service.CloneForm(sourceID, "bob")
+
+	require.NoError(t, err)
+	assert.NotEqual(t, sourceID, clone.ID)
+	assert.Equal(t, "bob", clone.Author)
+	assert.Equal(t, "Party", clone.Title)
+	assert.True(t, clone.InviteOnly, "settings are copied")
+	assert.Equal(t, []string{"example.com"}, clone.AllowedDomains)
+	assert.Equal(t, entity.StatusDraft, clone.Status, "the copy is a draft")
+	require.Len(t, clone.Questions, 1)
+	assert.Equal(t, clone.ID, clone.Questions[0].FormID)
+	mockPublisher.AssertExpectations(t)
+
+	_, err = service.CloneForm(sourceID, "")
+	assert.Error(t, err)
+}
+
+func TestService_CloneForm_KeepsAnnouncedClone(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	sourceID := uuid.New()
+	source := &entity.Form{ID: sourceID, Title: "Party", Author: "alice"}
+
+	var clone *entity.Form
+	mockRepo.On("Get", sourceID).Return(source, nil)
+	mockRepo.On("CloneForm", mock.AnythingOfType("*entity.Form")).
+		Run(func(args mock.Arguments) { clone = args.Get(0).(*entity.Form) }).
+		Return(nil)
+	mockCasher.On("AddToCash", mock.Anything, mock.Anything, mock.AnythingOfType("*entity.Form")).Return(nil)
+	mockPublisher.On("Publish", mock.AnythingOfType("*entity.Form"), events.FormCreated.String()).
+		Return(errors.New("broker down"))
+
+	_, err := service.CloneForm(sourceID, "bob")
+
+	assert.ErrorContains(t, err, "broker down")
+	require.NotNil(t, clone)
+	mockCasher.AssertCalled(t, "AddToCash", mock.Anything, clone.ID.String(), clone)
+	mockRepo.AssertNotCalled(t, "DeleteForm", mock.Anything)
+	mockRepo.AssertNotCalled(t, "PurgeForm", mock.Anything)
+}
+
+func TestService_CloneForm_StoreFailure(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	sourceID := uuid.New()
+	mockRepo.On("Get", sourceID).Return(&entity.Form{ID: sourceID, Title: "Party", Author: "alice"}, nil)
+	mockRepo.On("CloneForm", mock.AnythingOfType("*entity.Form")).Return(errors.New("disk full"))
+
+	_, err := service.CloneForm(sourceID, "bob")
+
+	assert.ErrorContains(t, err, "disk full")
+	mockCasher.AssertNotCalled(t, "AddToCash", mock.Anything, mock.Anything, mock.Anything)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
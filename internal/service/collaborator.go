@@ -0,0 +1,232 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotEditor is returned for changes to a form by users who are
+	// neither its author nor one of its editors
+	ErrNotEditor = fmt.Errorf("%w: not the author or an editor of the form", ErrAccessDenied)
+
+	// ErrNotAuthor is returned for changes to the collaborators of a form by
+	// users other than its author
+	ErrNotAuthor = fmt.Errorf("%w: not the author of the form", ErrAccessDenied)
+
+	// ErrNotCollaborator is returned for reads of a form, e.g. its responses,
+	// by users who are neither its author nor one of its collaborators
+	ErrNotCollaborator = fmt.Errorf("%w: not the author or a collaborator of the form", ErrAccessDenied)
+
+	// ErrNotAdmin is returned for admin requests, e.g. privacy requests, by
+	// users who aren't admins
+	ErrNotAdmin = fmt.Errorf("%w: not an admin", ErrAccessDenied)
+)
+
+// As returns the service acting for actor, the user a request is made by.
+// Changes to a form through it are refused with ErrNotEditor unless actor
+// is the author of the form or one of its editors, reads of its responses
+// and collaborators with ErrNotCollaborator unless actor is its author or
+// any collaborator. Without an actor the service itself is returned, which
+// is trusted, like the background jobs using it; user requests always name
+// one, see listener.Handle.
+func (s *Service) As(actor string) *Service {
+	if actor == "" || actor == s.actor {
+		return s
+	}
+
+	acting := *s
+	acting.actor = actor
+
+	return &acting
+}
+
+// authorize checks that the actor may change the form with the given ID
+func (s *Service) authorize(formID uuid.UUID) error {
+	if s.actor == "" {
+		return nil
+	}
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	return s.authorizeForm(form)
+}
+
+// authorizeForm checks that the actor may change form: they are its author
+// or one of its editors
+func (s *Service) authorizeForm(form *entity.Form) error {
+	if s.actor == "" || s.actor == form.Author {
+		return nil
+	}
+
+	collaborator, err := s.repo.GetCollaborator(form.ID, s.actor)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotEditor
+	case err != nil:
+		return fmt.Errorf("failed to retrieve collaborator: %w", err)
+	case !collaborator.CanEdit():
+		return ErrNotEditor
+	}
+
+	return nil
+}
+
+// authorizeRead checks that the actor may read the form with the given ID
+func (s *Service) authorizeRead(formID uuid.UUID) error {
+	if s.actor == "" {
+		return nil
+	}
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	return s.authorizeReader(form)
+}
+
+// authorizeReader checks that the actor may read form: they are its author
+// or one of its collaborators, editors and viewers alike
+func (s *Service) authorizeReader(form *entity.Form) error {
+	if s.actor == "" || s.actor == form.Author {
+		return nil
+	}
+
+	_, err := s.repo.GetCollaborator(form.ID, s.actor)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotCollaborator
+	case err != nil:
+		return fmt.Errorf("failed to retrieve collaborator: %w", err)
+	}
+
+	return nil
+}
+
+// SetAdmins sets the users allowed the admin requests, e.g. privacy requests
+// and cache inspection. Without any only the trusted service itself may
+// make them.
+func (s *Service) SetAdmins(admins []string) {
+	s.admins = admins
+}
+
+// authorizeAdmin checks that the actor may make admin requests: they are
+// one of the admins
+func (s *Service) authorizeAdmin() error {
+	if s.actor != "" && !slices.Contains(s.admins, s.actor) {
+		return ErrNotAdmin
+	}
+
+	return nil
+}
+
+// authorizeAuthor checks that the actor may manage the collaborators of the
+// form: they are its author
+func (s *Service) authorizeAuthor(form *entity.Form) error {
+	if s.actor != "" && s.actor != form.Author {
+		return ErrNotAuthor
+	}
+
+	return nil
+}
+
+// AddCollaborator grants a user a role on a form, or changes the role they
+// have, and publishes form.collaborator.added. Only the author may manage
+// the collaborators of a form.
+func (s *Service) AddCollaborator(formID uuid.UUID, userID, role string) (*entity.Collaborator, error) {
+	if err := entity.ValidateRole(role); err != nil {
+		return nil, err
+	}
+
+	if userID == "" {
+		return nil, errors.New("user ID can not be empty")
+	}
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeAuthor(form); err != nil {
+		return nil, err
+	}
+
+	if userID == form.Author {
+		return nil, fmt.Errorf("%w: the author can't be a collaborator", entity.ErrInvalidRole)
+	}
+
+	collaborator := &entity.Collaborator{FormID: formID, UserID: userID, Role: role}
+	if err = s.repo.SaveCollaborator(collaborator); err != nil {
+		return nil, fmt.Errorf("failed to save collaborator in repository: %w", err)
+	}
+
+	if err = s.publish(events.CollaboratorAdded, collaborator); err != nil {
+		return collaborator, fmt.Errorf("publish error: %w", err)
+	}
+
+	return collaborator, nil
+}
+
+// RemoveCollaborator revokes the role of a user on a form and publishes
+// form.collaborator.removed. Only the author may manage the collaborators
+// of a form.
+// Returns gorm.ErrRecordNotFound if the user isn't a collaborator of the form
+func (s *Service) RemoveCollaborator(formID uuid.UUID, userID string) error {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeAuthor(form); err != nil {
+		return err
+	}
+
+	removed, err := s.repo.DeleteCollaborator(formID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete collaborator from repository: %w", err)
+	}
+
+	if err = s.publish(events.CollaboratorRemoved, removed); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
+
+// ListCollaborators returns the author of a form and its collaborators
+func (s *Service) ListCollaborators(formID uuid.UUID) (*entity.CollaboratorList, error) {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeReader(form); err != nil {
+		return nil, err
+	}
+
+	collaborators, err := s.repo.ListCollaborators(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collaborators: %w", err)
+	}
+
+	return &entity.CollaboratorList{
+		FormID:        formID.String(),
+		Author:        form.Author,
+		Collaborators: collaborators,
+	}, nil
+}
+
+// PublishCollaboratorList sends a collaborator listing back to the requester
+func (s *Service) PublishCollaboratorList(list *entity.CollaboratorList) error {
+	return s.publish(events.CollaboratorList, list)
+}
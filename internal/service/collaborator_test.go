@@ -0,0 +1,145 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestService_AuthorizeForm(t *testing.T) {
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Author: "alice"}
+
+	tests := []struct {
+		name         string
+		actor        string
+		collaborator *entity.Collaborator
+		wantErr      error
+	}{
+		{name: "trusted without actor", actor: ""},
+		{name: "author", actor: "alice"},
+		{name: "editor", actor: "bob", collaborator: &entity.Collaborator{Role: entity.RoleEditor}},
+		{name: "viewer", actor: "bob", collaborator: &entity.Collaborator{Role: entity.RoleViewer}, wantErr: ErrNotEditor},
+		{name: "stranger", actor: "eve", wantErr: ErrNotEditor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, mockRepo, _ := setupService()
+
+			if tt.collaborator != nil {
+				mockRepo.On("GetCollaborator", formID, tt.actor).Return(tt.collaborator, nil)
+			} else {
+				mockRepo.On("GetCollaborator", formID, tt.actor).Return(nil, gorm.ErrRecordNotFound)
+			}
+
+			err := service.As(tt.actor).authorizeForm(form)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrAccessDenied)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_AuthorizeReader(t *testing.T) {
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Author: "alice"}
+
+	tests := []struct {
+		name         string
+		actor        string
+		collaborator *entity.Collaborator
+		wantErr      error
+	}{
+		{name: "trusted without actor", actor: ""},
+		{name: "author", actor: "alice"},
+		{name: "editor", actor: "bob", collaborator: &entity.Collaborator{Role: entity.RoleEditor}},
+		{name: "viewer", actor: "bob", collaborator: &entity.Collaborator{Role: entity.RoleViewer}},
+		{name: "stranger", actor: "eve", wantErr: ErrNotCollaborator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, mockRepo, _ := setupService()
+
+			if tt.collaborator != nil {
+				mockRepo.On("GetCollaborator", formID, tt.actor).Return(tt.collaborator, nil)
+			} else {
+				mockRepo.On("GetCollaborator", formID, tt.actor).Return(nil, gorm.ErrRecordNotFound)
+			}
+
+			err := service.As(tt.actor).authorizeReader(form)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrAccessDenied)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_UpdateDescription_NotEditor(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Author: "alice"}, nil)
+	mockRepo.On("GetCollaborator", formID, "bob").Return(&entity.Collaborator{Role: entity.RoleViewer}, nil)
+
+	err := service.As("bob").UpdateDescription(formID, "changed")
+
+	assert.ErrorIs(t, err, ErrNotEditor)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_AddCollaborator(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Author: "alice"}, nil)
+	mockRepo.On("SaveCollaborator", mock.AnythingOfType("*entity.Collaborator")).Return(nil)
+	mockPublisher.On("Publish", mock.AnythingOfType("*entity.Collaborator"), events.CollaboratorAdded.String()).Return(nil)
+
+	collaborator, err := service.As("alice").AddCollaborator(formID, "bob", entity.RoleEditor)
+
+	require.NoError(t, err)
+	assert.Equal(t, "bob", collaborator.UserID)
+	assert.True(t, collaborator.CanEdit())
+	mockPublisher.AssertExpectations(t)
+
+	_, err = service.As("bob").AddCollaborator(formID, "eve", entity.RoleEditor)
+	assert.ErrorIs(t, err, ErrNotAuthor)
+
+	_, err = service.AddCollaborator(formID, "alice", entity.RoleViewer)
+	assert.ErrorIs(t, err, entity.ErrInvalidRole)
+
+	_, err = service.AddCollaborator(formID, "bob", "owner")
+	assert.ErrorIs(t, err, entity.ErrInvalidRole)
+}
+
+func TestService_RemoveCollaborator(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	removed := &entity.Collaborator{FormID: formID, UserID: "bob", Role: entity.RoleEditor}
+
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Author: "alice"}, nil)
+	mockRepo.On("DeleteCollaborator", formID, "bob").Return(removed, nil)
+	mockPublisher.On("Publish", removed, events.CollaboratorRemoved.String()).Return(nil)
+
+	assert.ErrorIs(t, service.As("bob").RemoveCollaborator(formID, "bob"), ErrNotAuthor)
+	assert.NoError(t, service.As("alice").RemoveCollaborator(formID, "bob"))
+	mockRepo.AssertNumberOfCalls(t, "DeleteCollaborator", 1)
+	mockPublisher.AssertExpectations(t)
+}
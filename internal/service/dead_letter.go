@@ -0,0 +1,18 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+)
+
+// PublishDeadLetter publishes a request a subscribed handler of the
+// listener gave up on, so it can be inspected and replayed
+func (s *Service) PublishDeadLetter(letter *entity.DeadLetter) error {
+	if err := s.publish(events.DeadLetter, letter); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
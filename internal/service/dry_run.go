@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// DryRunDeleteForm simulates DeleteForm without writing anything. Like
+// DeleteForm it is only valid for the author and editors of the form.
+func (s *Service) DryRunDeleteForm(formID uuid.UUID) *entity.DryRunResult {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return s.invalidDryRun(fmt.Errorf("failed to retrieve form: %w", err))
+	}
+
+	if err = s.authorizeForm(form); err != nil {
+		return s.invalidDryRun(err)
+	}
+
+	return &entity.DryRunResult{
+		Valid:  true,
+		Events: []entity.PlannedEvent{plannedEvent(events.NewFormDeleted(formID))},
+	}
+}
+
+// DryRunDeleteQuestion simulates DeleteQuestion without writing anything,
+// checking the actor like DeleteQuestion.
+func (s *Service) DryRunDeleteQuestion(formID uuid.UUID, orderNumber uint) *entity.DryRunResult {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return s.invalidDryRun(fmt.Errorf("failed to retrieve form: %w", err))
+	}
+
+	if err = s.authorizeForm(form); err != nil {
+		return s.invalidDryRun(err)
+	}
+
+	preview := withoutQuestion(form, func(q entity.Question) bool {
+		return q.OrderNumber == orderNumber
+	})
+
+	return updatedFormDryRun(preview)
+}
+
+// DryRunDeleteQuestionByID simulates DeleteQuestionByID without writing
+// anything, checking the actor like DeleteQuestionByID.
+func (s *Service) DryRunDeleteQuestionByID(questionID uuid.UUID) *entity.DryRunResult {
+	question, err := s.repo.GetQuestion(questionID)
+	if err != nil {
+		return s.invalidDryRun(fmt.Errorf("failed to retrieve question: %w", err))
+	}
+
+	form, err := s.repo.Get(question.FormID)
+	if err != nil {
+		return s.invalidDryRun(fmt.Errorf("failed to retrieve form: %w", err))
+	}
+
+	if err = s.authorizeForm(form); err != nil {
+		return s.invalidDryRun(err)
+	}
+
+	preview := withoutQuestion(form, func(q entity.Question) bool {
+		return q.ID == questionID
+	})
+
+	return updatedFormDryRun(preview)
+}
+
+// PublishDryRun publishes a dry-run result so the requester can read it.
+func (s *Service) PublishDryRun(result *entity.DryRunResult) error {
+	if err := s.publish(events.DryRunResult, result); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
+
+// withoutQuestion returns a copy of form without the questions matching drop
+func withoutQuestion(form *entity.Form, drop func(entity.Question) bool) *entity.Form {
+	preview := *form
+	preview.Questions = make([]entity.Question, 0, len(form.Questions))
+
+	for _, q := range form.Questions {
+		if !drop(q) {
+			preview.Questions = append(preview.Questions, q)
+		}
+	}
+
+	return &preview
+}
+
+func updatedFormDryRun(form *entity.Form) *entity.DryRunResult {
+	return &entity.DryRunResult{
+		Valid:  true,
+		Events: []entity.PlannedEvent{plannedEvent(events.Event{Type: events.FormUpdated, Payload: form})},
+		Cached: []string{form.ID.String()},
+		Result: form,
+	}
+}
+
+func (s *Service) invalidDryRun(err error) *entity.DryRunResult {
+	return &entity.DryRunResult{
+		Valid:       false,
+		Error:       err.Error(),
+		ErrorDetail: s.errorDetail(err),
+		Events:      []entity.PlannedEvent{},
+	}
+}
+
+// plannedEvent describes an event a dry run would publish
+func plannedEvent(event events.Event) entity.PlannedEvent {
+	return entity.PlannedEvent{Type: event.Type.String(), Payload: event.Payload}
+}
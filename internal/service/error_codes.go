@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/errcatalog"
+	"gorm.io/gorm"
+)
+
+// errorCodes maps errors to their catalog codes, more specific errors first
+var errorCodes = []struct {
+	err  error
+	code errcatalog.Code
+}{
+	{ErrFormClosed, errcatalog.FORM_CLOSED},
+	{ErrFormNotPublic, errcatalog.FORM_CLOSED},
+	{ErrDomainNotAllowed, errcatalog.DOMAIN_NOT_ALLOWED},
+	{ErrInviteRequired, errcatalog.INVITE_REQUIRED},
+	{ErrInviteUnknown, errcatalog.INVITE_UNKNOWN},
+	{ErrInviteRevoked, errcatalog.INVITE_REVOKED},
+	{ErrInviteExpired, errcatalog.INVITE_EXPIRED},
+	{ErrInviteUsed, errcatalog.INVITE_USED},
+	{ErrInviteWrongRespondent, errcatalog.INVITE_WRONG_RESPONDENT},
+	{ErrAccessDenied, errcatalog.ACCESS_DENIED},
+	{ErrInvalidResponse, errcatalog.INVALID_ANSWERS},
+	{ErrRateLimited, errcatalog.RATE_LIMITED},
+	{ErrLimitExceeded, errcatalog.LIMIT_EXCEEDED},
+	{ErrUploadRejected, errcatalog.UPLOAD_REJECTED},
+	{entity.ErrInvalidRules, errcatalog.INVALID_RULES},
+	{ErrNotInTrash, errcatalog.NOT_FOUND},
+	{gorm.ErrRecordNotFound, errcatalog.NOT_FOUND},
+}
+
+// SetErrorCatalog replaces the catalog error messages are taken from
+func (s *Service) SetErrorCatalog(catalog *errcatalog.Catalog) {
+	s.catalog = catalog
+}
+
+// ErrorCode returns the catalog code of err, errcatalog.INTERNAL for errors
+// without one
+func ErrorCode(err error) errcatalog.Code {
+	var quotaErr *QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		return errcatalog.QUOTA_EXCEEDED
+	}
+
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			return known.code
+		}
+	}
+
+	return errcatalog.INTERNAL
+}
+
+// errorDetail describes err to clients by its code and localized messages
+func (s *Service) errorDetail(err error) entity.ErrorDetail {
+	return s.catalog.Detail(ErrorCode(err))
+}
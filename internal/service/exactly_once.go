@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"gorm.io/gorm"
+)
+
+// ProcessedEventRepository stores changes in one transaction with the
+// record of the request event they were made for, see package repository
+type ProcessedEventRepository interface {
+	CreateFormOnce(event entity.ProcessedEvent, form *entity.Form) (bool, error)
+	PruneProcessedEvents(before time.Time) (int64, error)
+}
+
+// EnableExactlyOnce records the request events forms are created for in
+// repo, so CreateFormOnce creates the form of a redelivered event only once.
+// Together with acknowledging requests once handled this makes form
+// creation exactly once across crashes.
+func (s *Service) EnableExactlyOnce(repo ProcessedEventRepository) {
+	s.processed = repo
+}
+
+// CreateFormOnce creates a form for the request event with the given ID and
+// type. While exactly-once creation is enabled the form is stored in one
+// transaction with the record of the event, and a redelivery of the event
+// only caches and publishes the stored form again, in case the first
+// delivery stopped before it did. Otherwise it is CreateForm.
+func (s *Service) CreateFormOnce(eventID, eventType string, form *entity.Form) error {
+	if s.processed == nil || eventID == "" || form == nil {
+		return s.CreateForm(form)
+	}
+
+	return s.createForm(form, func() (bool, error) {
+		return s.processed.CreateFormOnce(entity.ProcessedEvent{EventID: eventID, Type: eventType}, form)
+	})
+}
+
+// storedDuplicate releases the quota reserved for a form an earlier delivery
+// of its event already created and returns the stored form, nil once it's gone
+func (s *Service) storedDuplicate(form *entity.Form) (*entity.Form, error) {
+	if err := s.releaseQuota(form.Author, 1, len(form.Questions)); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repo.Get(form.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve created form: %w", err)
+	}
+
+	return stored, nil
+}
+
+// PruneProcessedEvents forgets the request events processed longer than
+// retention ago, which the broker no longer redelivers
+// Returns the number of events forgotten
+func (s *Service) PruneProcessedEvents(retention time.Duration) (int64, error) {
+	if s.processed == nil {
+		return 0, nil
+	}
+
+	pruned, err := s.processed.PruneProcessedEvents(time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune processed events: %w", err)
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockProcessedEventRepository is a mock implementation of the
+// ProcessedEventRepository interface
+type MockProcessedEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockProcessedEventRepository) CreateFormOnce(event entity.ProcessedEvent, form *entity.Form) (bool, error) {
+	args := m.Called(event, form)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProcessedEventRepository) PruneProcessedEvents(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestService_CreateFormOnce(t *testing.T) {
+	event := entity.ProcessedEvent{EventID: "event-1", Type: "request.form.create"}
+
+	t.Run("first delivery", func(t *testing.T) {
+		service, mockCasher, _, mockPublisher := setupService()
+		processed := &MockProcessedEventRepository{}
+		service.EnableExactlyOnce(processed)
+
+		form := &entity.Form{ID: uuid.New(), Title: "Test Form"}
+
+		processed.On("CreateFormOnce", event, form).Return(true, nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.created").Return(nil)
+
+		require.NoError(t, service.CreateFormOnce(event.EventID, event.Type, form))
+		processed.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("redelivery publishes the stored form again", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		processed := &MockProcessedEventRepository{}
+		service.EnableExactlyOnce(processed)
+
+		form := &entity.Form{ID: uuid.New(), Title: "Test Form"}
+		stored := &entity.Form{ID: form.ID, Title: "Test Form", CreatedAt: time.Now()}
+
+		processed.On("CreateFormOnce", event, form).Return(false, nil)
+		mockRepo.On("Get", form.ID).Return(stored, nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), stored).Return(nil)
+		mockPublisher.On("Publish", stored, "form.created").Return(nil)
+
+		require.NoError(t, service.CreateFormOnce(event.EventID, event.Type, form))
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("redelivery of a form gone since", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		processed := &MockProcessedEventRepository{}
+		service.EnableExactlyOnce(processed)
+
+		form := &entity.Form{ID: uuid.New(), Title: "Test Form"}
+
+		processed.On("CreateFormOnce", event, form).Return(false, nil)
+		mockRepo.On("Get", form.ID).Return(nil, gorm.ErrRecordNotFound)
+
+		require.NoError(t, service.CreateFormOnce(event.EventID, event.Type, form))
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		service, _, _, mockPublisher := setupService()
+		processed := &MockProcessedEventRepository{}
+		service.EnableExactlyOnce(processed)
+
+		form := &entity.Form{ID: uuid.New(), Title: "Test Form"}
+
+		processed.On("CreateFormOnce", event, form).Return(false, errors.New("database error"))
+
+		err := service.CreateFormOnce(event.EventID, event.Type, form)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create form in repository")
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		form := &entity.Form{ID: uuid.New(), Title: "Test Form"}
+
+		mockRepo.On("Create", form).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.created").Return(nil)
+
+		require.NoError(t, service.CreateFormOnce(event.EventID, event.Type, form))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestService_PruneProcessedEvents(t *testing.T) {
+	service, _, _, _ := setupService()
+
+	pruned, err := service.PruneProcessedEvents(time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, pruned, "nothing to prune while disabled")
+
+	processed := &MockProcessedEventRepository{}
+	service.EnableExactlyOnce(processed)
+
+	processed.On("PruneProcessedEvents", mock.MatchedBy(func(before time.Time) bool {
+		return time.Until(before) < -59*time.Minute
+	})).Return(int64(3), nil)
+
+	pruned, err = service.PruneProcessedEvents(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), pruned)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// ExportForm returns the form, with its questions and settings, as a
+// self-contained document in format, entity.ExportJSON or entity.ExportCSV,
+// to back it up or move it to another environment with ImportForm
+func (s *Service) ExportForm(formID uuid.UUID, format string) (_ []byte, err error) {
+	defer observe("export_form", time.Now(), &err)
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeForm(form); err != nil {
+		return nil, err
+	}
+
+	def, err := form.ToDefinition()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert form %s: %w", formID, err)
+	}
+
+	return def.Export(format)
+}
+
+// ImportForm creates a form from a document of ExportForm and publishes
+// form.created for it. The form gets a new ID, so a document can be
+// imported more than once, and belongs to author, or to the author of the
+// document when empty. It counts against the quota of its author like a
+// created form, which is released again if storing the form fails, see
+// storeNewForm.
+func (s *Service) ImportForm(format string, data []byte, author string) (_ *entity.Form, err error) {
+	defer observe("import_form", time.Now(), &err)
+
+	def, err := entity.ParseFormExport(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if author != "" {
+		def.Author = author
+	}
+
+	form, err := def.ToForm(nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidExport, err)
+	}
+
+	form.ID = uuid.New()
+	for i := range form.Questions {
+		form.Questions[i].FormID = form.ID
+	}
+
+	if err = s.prepareForm(form); err != nil {
+		return nil, err
+	}
+
+	if err = s.storeNewForm("import_form", form, func() error {
+		if err := s.repo.Create(form); err != nil {
+			return fmt.Errorf("failed to create form in repository: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return form, nil
+}
+
+// PublishFormExport sends an exported form back to the requester
+func (s *Service) PublishFormExport(export *entity.FormExport) error {
+	return s.publish(events.FormExported, export)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestService_ExportForm(t *testing.T) {
+	form := &entity.Form{
+		ID:     uuid.New(),
+		Title:  "Feedback",
+		Author: "author",
+		Questions: []entity.Question{
+			{Content: "Score?", OrderNumber: 1, Type: "number", Options: json.RawMessage(`{"min":1,"max":5}`)},
+		},
+	}
+
+	t.Run("exports and imports as a new form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		mockRepo.On("Get", form.ID).Return(form, nil)
+
+		document, err := service.ExportForm(form.ID, entity.ExportCSV)
+		require.NoError(t, err)
+
+		var created *entity.Form
+		mockRepo.On("Create", mock.AnythingOfType("*entity.Form")).
+			Run(func(args mock.Arguments) { created = args.Get(0).(*entity.Form) }).
+			Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockPublisher.On("Publish", mock.AnythingOfType("*entity.Form"), "form.created").Return(nil)
+
+		imported, err := service.ImportForm(entity.ExportCSV, document, "other")
+		require.NoError(t, err)
+
+		assert.Same(t, created, imported)
+		assert.NotEqual(t, form.ID, imported.ID)
+		assert.Equal(t, "other", imported.Author)
+		assert.Equal(t, "Feedback", imported.Title)
+		require.Len(t, imported.Questions, 1)
+		assert.Equal(t, imported.ID, imported.Questions[0].FormID)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("keeps the imported form when caching it fails", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		mockRepo.On("Get", form.ID).Return(form, nil)
+
+		document, err := service.ExportForm(form.ID, entity.ExportJSON)
+		require.NoError(t, err)
+
+		var created *entity.Form
+		mockRepo.On("Create", mock.AnythingOfType("*entity.Form")).
+			Run(func(args mock.Arguments) { created = args.Get(0).(*entity.Form) }).
+			Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("cache down"))
+		mockPublisher.On("Publish", mock.AnythingOfType("*entity.Form"), events.FormCreated.String()).Return(nil)
+
+		_, err = service.ImportForm(entity.ExportJSON, document, "")
+
+		assert.ErrorContains(t, err, "cache error: cache down")
+		require.NotNil(t, created)
+		mockPublisher.AssertCalled(t, "Publish", created, events.FormCreated.String())
+		mockRepo.AssertNotCalled(t, "DeleteForm", mock.Anything)
+		mockRepo.AssertNotCalled(t, "PurgeForm", mock.Anything)
+	})
+
+	t.Run("only editors export", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockRepo.On("Get", form.ID).Return(form, nil)
+		mockRepo.On("GetCollaborator", form.ID, "mallory").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.As("mallory").ExportForm(form.ID, entity.ExportJSON)
+		assert.ErrorIs(t, err, ErrNotEditor)
+	})
+
+	t.Run("rejects malformed documents", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		_, err := service.ImportForm(entity.ExportJSON, []byte(`{"version": "form-service/v1", "id": "nope"}`), "")
+		assert.ErrorIs(t, err, entity.ErrInvalidExport)
+
+		_, err = service.ImportForm("xml", nil, "")
+		assert.ErrorIs(t, err, entity.ErrUnknownExportFormat)
+
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"gorm.io/gorm"
+)
+
+// GetFormMetadata returns the public metadata of the form named slug, for
+// link previews and embeds. It describes the latest published version, so
+// edits nobody published yet don't leak, and is served from the query cache
+// when enabled; embeds are read far more often than forms change.
+// Returns ErrFormNotPublic for drafts, archived forms and unknown slugs
+func (s *Service) GetFormMetadata(slug string) (*entity.FormMetadata, error) {
+	if err := entity.ValidateSlug(slug); err != nil {
+		return nil, err
+	}
+
+	return cachedQuery(s, queryScopeForms, "metadata:"+slug, func() (*entity.FormMetadata, error) {
+		return s.formMetadata(slug)
+	})
+}
+
+// formMetadata builds the metadata of the form named slug
+func (s *Service) formMetadata(slug string) (*entity.FormMetadata, error) {
+	form, err := s.repo.GetBySlug(slug)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("%w: %s", ErrFormNotPublic, slug)
+	case err != nil:
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if form.Status != entity.StatusPublished && form.Status != entity.StatusClosed {
+		return nil, fmt.Errorf("%w: %s is %s", ErrFormNotPublic, slug, form.Status)
+	}
+
+	published := form
+	version, err := s.repo.GetFormVersion(form.ID, 0)
+	switch {
+	case err == nil:
+		published = &version.Form
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to retrieve form version: %w", err)
+	}
+
+	return &entity.FormMetadata{
+		FormID:        form.ID,
+		Slug:          slug,
+		Title:         published.Title,
+		Description:   published.Description,
+		Format:        published.Format,
+		Open:          form.AcceptsResponses(),
+		QuestionCount: len(published.Questions),
+	}, nil
+}
+
+// PublishFormMetadata sends the metadata of a form back to the requester
+func (s *Service) PublishFormMetadata(reply *entity.FormMetadataReply) error {
+	return s.publish(events.FormMetadata, reply)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestService_GetFormMetadata(t *testing.T) {
+	t.Run("describes the published version", func(t *testing.T) {
+		service, _, mockRepo, _ := newSlugService()
+
+		formID := uuid.New()
+		live := &entity.Form{ID: formID, Title: "Unpublished edit", Status: entity.StatusPublished}
+		mockRepo.On("GetBySlug", "survey").Return(live, nil)
+		mockRepo.On("GetFormVersion", formID, uint(0)).Return(publishedVersion(formID), nil)
+
+		metadata, err := service.GetFormMetadata("survey")
+
+		require.NoError(t, err)
+		assert.Equal(t, &entity.FormMetadata{
+			FormID:        formID,
+			Slug:          "survey",
+			Title:         "Published",
+			Open:          true,
+			QuestionCount: 1,
+		}, metadata)
+	})
+
+	t.Run("closed forms are described as closed", func(t *testing.T) {
+		service, _, mockRepo, _ := newSlugService()
+
+		formID := uuid.New()
+		mockRepo.On("GetBySlug", "survey").Return(&entity.Form{ID: formID, Status: entity.StatusClosed}, nil)
+		mockRepo.On("GetFormVersion", formID, uint(0)).Return(publishedVersion(formID), nil)
+
+		metadata, err := service.GetFormMetadata("survey")
+
+		require.NoError(t, err)
+		assert.False(t, metadata.Open)
+	})
+
+	t.Run("drafts and unknown slugs are not public", func(t *testing.T) {
+		service, _, mockRepo, _ := newSlugService()
+
+		mockRepo.On("GetBySlug", "draft").Return(&entity.Form{ID: uuid.New(), Status: entity.StatusDraft}, nil)
+		mockRepo.On("GetBySlug", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.GetFormMetadata("draft")
+		assert.ErrorIs(t, err, ErrFormNotPublic)
+
+		_, err = service.GetFormMetadata("missing")
+		assert.ErrorIs(t, err, ErrFormNotPublic)
+	})
+
+	t.Run("rejects invalid slugs", func(t *testing.T) {
+		service, _, mockRepo, _ := newSlugService()
+
+		_, err := service.GetFormMetadata("Not A Slug")
+
+		assert.ErrorIs(t, err, entity.ErrInvalidSlug)
+		mockRepo.AssertNotCalled(t, "GetBySlug", "Not A Slug")
+	})
+
+	t.Run("is served from the query cache until forms change", func(t *testing.T) {
+		service, _, mockRepo, _ := newSlugService()
+		service.EnableQueryCache(newMemoryQueries(), time.Minute)
+
+		formID := uuid.New()
+		mockRepo.On("GetBySlug", "survey").Return(&entity.Form{ID: formID, Status: entity.StatusPublished}, nil)
+		mockRepo.On("GetFormVersion", formID, uint(0)).Return(publishedVersion(formID), nil)
+
+		for range 2 {
+			_, err := service.GetFormMetadata("survey")
+			require.NoError(t, err)
+		}
+		mockRepo.AssertNumberOfCalls(t, "GetBySlug", 1)
+
+		require.NoError(t, service.InvalidateQueries(publishedVersion(formID), events.FormPublished.String()))
+		_, err := service.GetFormMetadata("survey")
+		require.NoError(t, err)
+		mockRepo.AssertNumberOfCalls(t, "GetBySlug", 2)
+	})
+}
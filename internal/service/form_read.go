@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetForm returns a form with its sections, questions and branches, read
+// from the cache first. A miss, an unreachable cache or an entry that no
+// longer decodes falls back to the repository, and the form read there is
+// cached again. Forms in the trash are not found on either path: their
+// cached copy, kept until purged, is marked as trashed, see DeleteForm.
+func (s *Service) GetForm(ctx context.Context, formID uuid.UUID) (_ *entity.OutputForm, err error) {
+	defer observe("get_form", time.Now(), &err)
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if form, ok := s.cachedForm(ctx, formID); ok {
+		if form.Trashed() {
+			return nil, fmt.Errorf("failed to retrieve form: %w", gorm.ErrRecordNotFound)
+		}
+
+		output := form.ToFullOutput()
+		return &output, nil
+	}
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	// Repopulating the cache is best effort: the casher logs failures and
+	// the next read retries it
+	_ = s.casher.AddToCash(ctx, form.ID.String(), form)
+
+	output := form.ToFullOutput()
+	return &output, nil
+}
+
+// cachedForm returns the cached copy of a form, if there is one that decodes
+func (s *Service) cachedForm(ctx context.Context, formID uuid.UUID) (*entity.Form, bool) {
+	data, err := s.casher.GetCashFor(ctx, formID.String())
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+
+	form := new(entity.Form)
+	if err := json.Unmarshal(data, form); err != nil {
+		return nil, false
+	}
+
+	return form, true
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestService_GetForm(t *testing.T) {
+	formID := uuid.New()
+	stored := &entity.Form{
+		ID:          formID,
+		Description: "Tell us what you think",
+		Questions: []entity.Question{
+			{ID: questionID(1), Content: "Name?", OrderNumber: 1},
+		},
+	}
+
+	cached, err := json.Marshal(stored)
+	require.NoError(t, err)
+
+	t.Run("is served from the cache", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+		mockCasher.On("GetCashFor", mock.Anything, formID.String()).Return(cached, nil)
+
+		form, err := service.GetForm(context.Background(), formID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Tell us what you think", form.Description)
+		require.Len(t, form.Questions, 1)
+		assert.Equal(t, questionID(1).String(), form.Questions[0].ID)
+		mockRepo.AssertNotCalled(t, "Get", formID)
+	})
+
+	t.Run("reads misses from the repository and caches them", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+		mockCasher.On("GetCashFor", mock.Anything, formID.String()).Return(nil, errors.New("redis: nil"))
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), stored).Return(nil)
+		mockRepo.On("Get", formID).Return(stored, nil)
+
+		form, err := service.GetForm(context.Background(), formID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Tell us what you think", form.Description)
+		mockCasher.AssertExpectations(t)
+	})
+
+	t.Run("falls back on entries that don't decode", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+		mockCasher.On("GetCashFor", mock.Anything, formID.String()).Return([]byte("{"), nil)
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), stored).Return(nil)
+		mockRepo.On("Get", formID).Return(stored, nil)
+
+		form, err := service.GetForm(context.Background(), formID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Tell us what you think", form.Description)
+		mockRepo.AssertCalled(t, "Get", formID)
+	})
+
+	t.Run("ignores failures to cache the form", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+		mockCasher.On("GetCashFor", mock.Anything, formID.String()).Return(nil, errors.New("redis: nil"))
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), stored).Return(errors.New("cache down"))
+		mockRepo.On("Get", formID).Return(stored, nil)
+
+		_, err := service.GetForm(context.Background(), formID)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("doesn't find trashed forms in a warm cache", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		trashed := *stored
+		trashed.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+		// The cache is warm with the live form until DeleteForm replaces it
+		var recached []byte
+		mockCasher.On("GetCashFor", mock.Anything, formID.String()).Return(cached, nil).Once()
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), &trashed).Run(func(args mock.Arguments) {
+			data, err := json.Marshal(args.Get(2))
+			require.NoError(t, err)
+			recached = data
+		}).Return(nil)
+		mockRepo.On("DeleteForm", formID).Return(nil)
+		mockRepo.On("GetTrashedForm", formID).Return(&trashed, nil)
+		mockPublisher.On("Publish", mock.Anything, "form.deleted").Return(nil)
+
+		_, err := service.GetForm(context.Background(), formID)
+		require.NoError(t, err)
+
+		require.NoError(t, service.DeleteForm(formID))
+		mockCasher.On("GetCashFor", mock.Anything, formID.String()).Return(recached, nil)
+
+		_, err = service.GetForm(context.Background(), formID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockRepo.AssertNotCalled(t, "Get", formID)
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+		mockCasher.On("GetCashFor", mock.Anything, formID.String()).Return(nil, errors.New("redis: nil"))
+		mockRepo.On("Get", formID).Return(nil, errors.New("db down"))
+
+		_, err := service.GetForm(context.Background(), formID)
+
+		assert.ErrorContains(t, err, "db down")
+		mockCasher.AssertNotCalled(t, "AddToCash", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
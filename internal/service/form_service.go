@@ -8,50 +8,125 @@ import (
 	"time"
 
 	"github.com/Koyo-os/form-service/internal/entity"
-	"github.com/Koyo-os/form-service/pkg/retrier"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/internal/errcatalog"
+	"github.com/Koyo-os/form-service/internal/question"
+	"github.com/Koyo-os/form-service/pkg/batch"
+	"github.com/Koyo-os/form-service/pkg/logger"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 const (
-	DefaultRetryAttempts = 3
-	DefaultRetryDelay    = 5
+	DefaultRetryAttempts = 3 // Total attempts, including the first one
+	DefaultRetryDelay    = 5 // Pause between attempts in milliseconds
 )
 
 // Service provides business logic for form management operations.
 // It coordinates between repository, cache, and event publishing systems.
 type Service struct {
-	casher    Casher     // Handles caching operations for forms
-	repo      Repository // Provides persistence layer access
-	publisher Publisher  // Manages event publishing
-	timeout   time.Duration
+	casher       Casher     // Handles caching operations for forms
+	repo         Repository // Provides persistence layer access
+	publisher    Publisher  // Manages event publishing
+	timeout      time.Duration
+	ctx          context.Context     // Parent of the operation contexts, see SetContext; nil for none
+	cacheRetry   RetryPolicy         // Retry policy for cache writes and removals
+	publishRetry RetryPolicy         // Retry policy for event publishing
+	questions    *question.Registry  // Question types questions are validated against
+	uploads      UploadRepository    // Upload metadata, nil while uploads are disabled
+	storage      Storage             // Object storage for uploaded files
+	uploadTTL    time.Duration       // Validity of upload URLs and pending uploads
+	settings     *tenantSettings     // Per-tenant settings, nil uses defaults for everyone
+	tenantLimit  RateLimiter         // Limits requests per tenant, nil disables
+	spamLimit    RateLimiter         // Limits submissions per respondent, nil disables
+	spamPerMin   int                 // Submissions allowed per respondent, form and minute
+	quotas       *quotas             // Per-author quotas, nil leaves authors unlimited
+	stats        *questionStats      // Answer statistics, nil keeps none
+	formStats    FormStatsRepository // View and submission counters, nil keeps none
+	responses    ResponseRepository  // Stored responses, nil keeps none
+	reconcile    *cacheReconciler    // Cache reconciliation, nil disables
+	integrity    *integrityChecker   // Checks of forms and questions, nil disables
+	diffs        bool                // Whether form.updated carries the diff from the previous version
+
+	catalog   *errcatalog.Catalog // Messages of the errors reported in result events
+	readModel ReadModelRepository // Respondent projection, nil serves respondents from the stored forms
+	inspector CacheInspector      // Reads cached forms with their TTL, nil disables inspection
+	actor     string              // User requests are made by, see As; empty is trusted
+	admins    []string            // Users allowed the admin requests, see SetAdmins
+
+	privacy    PrivacyRepository // Respondent data for privacy requests, nil disables them
+	privacyKey []byte            // Key privacy reports are signed with
+	queries    *queryCache       // Cached list query results, nil queries the database every time
+
+	processed ProcessedEventRepository // Request events forms were created for, nil creates them again on redelivery
+
+	responseBatch *batch.Writer[entity.Response] // Batches response inserts, nil inserts each response on its own
+
+	webhooks      WebhookRepository   // Webhooks of the forms, nil disables them
+	webhookSender WebhookSender       // Posts webhook bodies
+	webhookRetry  RetryPolicy         // Retry policy of each webhook delivery
+	resolver      entity.HostResolver // Resolves webhook hosts to check they are public
+
+	logger *logger.Logger // Logs multi-step operations and webhook deliveries, see SetLogger
 }
 
 // Init initializes and returns a new Service instance with dependencies.
 func Init(casher Casher, repo Repository, publisher Publisher, timeout time.Duration) *Service {
 	return &Service{
-		casher:    casher,
-		repo:      repo,
-		publisher: publisher,
-		timeout:   timeout,
+		casher:       casher,
+		repo:         repo,
+		publisher:    publisher,
+		timeout:      timeout,
+		cacheRetry:   DefaultRetryPolicy,
+		publishRetry: DefaultRetryPolicy,
+		questions:    question.Default,
+		catalog:      errcatalog.Default,
+		logger:       &logger.Logger{Logger: zap.NewNop()},
 	}
 }
 
-func (s *Service) getContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), s.timeout)
+// SetQuestionTypes replaces the registry questions are validated against
+func (s *Service) SetQuestionTypes(registry *question.Registry) {
+	s.questions = registry
 }
 
-// CreateForm creates a new form in the system.
-func (s *Service) CreateForm(form *entity.Form) error {
-	if form == nil {
-		return errors.New("form cannot be nil")
+// SetContext makes ctx the parent of the context of every operation, so
+// canceling it on shutdown aborts the side effects still being retried. It
+// must be called before the service starts handling requests.
+func (s *Service) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// getContext returns the context of an operation, bounded by the service
+// timeout and canceled with the context of the service, see SetContext
+func (s *Service) getContext() (context.Context, context.CancelFunc) {
+	parent := s.ctx
+	if parent == nil {
+		parent = context.Background()
 	}
 
-	// 1. Critical operation first (database)
-	if err := s.repo.Create(form); err != nil {
-		return fmt.Errorf("failed to create form in repository: %w", err)
+	return context.WithTimeout(parent, s.timeout)
+}
+
+// publish validates payload against the event type, see package events, and
+// publishes it within the timeout of an operation
+func (s *Service) publish(eventType events.Type, payload any) error {
+	event, err := events.New(eventType, payload)
+	if err != nil {
+		return err
 	}
 
-	// 2. Run non-critical operations concurrently
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	return s.publishRetry.do(ctx, func() error {
+		return event.PublishTo(ctx, s.publisher)
+	})
+}
+
+// syncForm caches the form and publishes payload as eventType concurrently,
+// returning the first error of either.
+func (s *Service) syncForm(form *entity.Form, payload any, eventType events.Type) error {
 	var wg sync.WaitGroup
 	errChan := make(chan error, 2)
 
@@ -62,7 +137,7 @@ func (s *Service) CreateForm(form *entity.Form) error {
 		ctx, cancel := s.getContext()
 		defer cancel()
 
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
+		if err := s.cacheRetry.do(ctx, func() error {
 			return s.casher.AddToCash(ctx, form.ID.String(), form)
 		}); err != nil {
 			errChan <- fmt.Errorf("cache error: %w", err)
@@ -73,9 +148,7 @@ func (s *Service) CreateForm(form *entity.Form) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.publisher.Publish(form, "form.created")
-		}); err != nil {
+		if err := s.publish(eventType, payload); err != nil {
 			errChan <- fmt.Errorf("publish error: %w", err)
 		}
 	}()
@@ -91,24 +164,51 @@ func (s *Service) CreateForm(form *entity.Form) error {
 	return nil
 }
 
-// CreateQuestion adds a new question to an existing form.
-func (s *Service) CreateQuestion(question *entity.Question) error {
-	if question == nil {
-		return errors.New("question cannot be nil")
+// CreateForm creates a new form in the system, inserting its questions in
+// the same batch. Questions without an order number are numbered after the
+// others, in the order given.
+func (s *Service) CreateForm(form *entity.Form) error {
+	return s.createForm(form, func() (bool, error) {
+		return true, s.repo.Create(form)
+	})
+}
+
+// createForm validates form, then stores it with store, which reports
+// whether it stored the form, and caches and publishes the stored form, see
+// announceForm
+func (s *Service) createForm(form *entity.Form, store func() (bool, error)) (err error) {
+	defer observe("create_form", time.Now(), &err)
+
+	if err := s.prepareForm(form); err != nil {
+		return err
 	}
 
-	// 1. Critical operation first (database)
-	if err := s.repo.Create(question); err != nil {
-		return fmt.Errorf("failed to create question in repository: %w", err)
+	if err := s.reserveQuota(form.Author, 1, len(form.Questions), len(form.Questions)); err != nil {
+		return err
 	}
 
-	// 2. Get updated form
-	form, err := s.repo.Get(question.FormID)
+	// 1. Critical operation first (database)
+	created, err := store()
 	if err != nil {
-		return fmt.Errorf("failed to retrieve updated form: %w", err)
+		s.releaseQuota(form.Author, 1, len(form.Questions))
+		return fmt.Errorf("failed to create form in repository: %w", err)
 	}
 
-	// 3. Run non-critical operations concurrently
+	if !created {
+		if form, err = s.storedDuplicate(form); form == nil || err != nil {
+			return err
+		}
+	}
+
+	// 2. Non-critical operations
+	return s.announceForm(form)
+}
+
+// announceForm caches a stored new form and publishes form.created for it
+// concurrently. Their failures are returned, but the form stays stored, as
+// consumers may have seen form.created already; the cache catches up on
+// the next read of the form.
+func (s *Service) announceForm(form *entity.Form) error {
 	var wg sync.WaitGroup
 	errChan := make(chan error, 2)
 
@@ -119,7 +219,7 @@ func (s *Service) CreateQuestion(question *entity.Question) error {
 		ctx, cancel := s.getContext()
 		defer cancel()
 
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
+		if err := s.cacheRetry.do(ctx, func() error {
 			return s.casher.AddToCash(ctx, form.ID.String(), form)
 		}); err != nil {
 			errChan <- fmt.Errorf("cache error: %w", err)
@@ -130,9 +230,7 @@ func (s *Service) CreateQuestion(question *entity.Question) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.publisher.Publish(form, "form.updated")
-		}); err != nil {
+		if err := s.publish(events.FormCreated, form); err != nil {
 			errChan <- fmt.Errorf("publish error: %w", err)
 		}
 	}()
@@ -148,19 +246,120 @@ func (s *Service) CreateQuestion(question *entity.Question) error {
 	return nil
 }
 
-// UpdateStatus changes the closed/open status of a form.
-func (s *Service) UpdateStatus(formID uuid.UUID, closed bool) error {
+// prepareForm validates a new form and readies it to be stored: it is made
+// a draft, given a slug and its questions numbered and sanitized
+func (s *Service) prepareForm(form *entity.Form) error {
+	if form == nil {
+		return errors.New("form cannot be nil")
+	}
+
+	if err := s.checkQuestionLimit(form.TenantID, len(form.Questions)); err != nil {
+		return err
+	}
+
+	// Forms start as drafts, see SetStatus
+	if form.Status != "" && form.Status != entity.StatusDraft {
+		return fmt.Errorf("%w: forms are created as drafts, not %s", entity.ErrInvalidTransition, form.Status)
+	}
+	form.Status = entity.StatusDraft
+
+	if err := s.assignSlug(form); err != nil {
+		return err
+	}
+
+	numberQuestions(form.Questions, 0)
+
+	if err := entity.ValidateNumbering(form.Numbering); err != nil {
+		return err
+	}
+
+	if err := form.ValidateTranslations(); err != nil {
+		return err
+	}
+
+	if err := form.Settings.Validate(); err != nil {
+		return err
+	}
+
+	if err := form.Theme.Validate(); err != nil {
+		return err
+	}
+
+	for i := range form.Questions {
+		if err := s.validateQuestion(&form.Questions[i]); err != nil {
+			return fmt.Errorf("invalid question: %w", err)
+		}
+	}
+
+	// Markup is only stored sanitized, see sanitizeForm
+	if err := sanitizeForm(form); err != nil {
+		return err
+	}
+
+	return s.checkTenantRate(form.TenantID)
+}
+
+// CreateQuestion adds a new question to an existing form.
+func (s *Service) CreateQuestion(question *entity.Question) (err error) {
+	defer observe("create_question", time.Now(), &err)
+
+	if question == nil {
+		return errors.New("question cannot be nil")
+	}
+
+	if err := s.validateQuestion(question); err != nil {
+		return fmt.Errorf("invalid question: %w", err)
+	}
+
+	if err := sanitizeQuestion(question); err != nil {
+		return fmt.Errorf("invalid question: %w", err)
+	}
+
+	if err := s.authorize(question.FormID); err != nil {
+		return err
+	}
+
+	if s.settings != nil {
+		if err := s.checkFormQuestionLimit(question.FormID); err != nil {
+			return err
+		}
+	}
+
+	previous, err := s.previousForm(question.FormID)
+	if err != nil {
+		return err
+	}
+
+	var author string
+	if s.quotas != nil {
+		parent, err := s.repo.Get(question.FormID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve form: %w", err)
+		}
+
+		author = parent.Author
+		if err := s.reserveQuota(author, 0, 1, len(parent.Questions)+1); err != nil {
+			return err
+		}
+	}
+
 	// 1. Critical operation first (database)
-	if err := s.repo.Update(formID, "Closed", closed); err != nil {
-		return fmt.Errorf("failed to update form status in repository: %w", err)
+	if err := s.repo.Create(question); err != nil {
+		s.releaseQuota(author, 0, 1)
+		return fmt.Errorf("failed to create question in repository: %w", err)
 	}
 
 	// 2. Get updated form
-	form, err := s.repo.Get(formID)
+	form, err := s.repo.Get(question.FormID)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve updated form: %w", err)
 	}
 
+	payload, err := s.formUpdate(previous, form)
+	if err != nil {
+		return err
+	}
+
 	// 3. Run non-critical operations concurrently
 	var wg sync.WaitGroup
 	errChan := make(chan error, 2)
@@ -172,8 +371,8 @@ func (s *Service) UpdateStatus(formID uuid.UUID, closed bool) error {
 		ctx, cancel := s.getContext()
 		defer cancel()
 
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.casher.AddToCash(ctx, formID.String(), form)
+		if err := s.cacheRetry.do(ctx, func() error {
+			return s.casher.AddToCash(ctx, form.ID.String(), form)
 		}); err != nil {
 			errChan <- fmt.Errorf("cache error: %w", err)
 		}
@@ -183,9 +382,7 @@ func (s *Service) UpdateStatus(formID uuid.UUID, closed bool) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.publisher.Publish(form, "form.updated")
-		}); err != nil {
+		if err := s.publish(events.FormUpdated, payload); err != nil {
 			errChan <- fmt.Errorf("publish error: %w", err)
 		}
 	}()
@@ -201,14 +398,51 @@ func (s *Service) UpdateStatus(formID uuid.UUID, closed bool) error {
 	return nil
 }
 
-// Update modifies multiple fields of a form at once.
-func (s *Service) Update(formID uuid.UUID, values any) error {
+// Update modifies multiple fields of a form at once. A form carrying a
+// version is only updated while still at that version, otherwise the
+// update fails with an *entity.VersionConflictError so editors can't
+// overwrite each other's changes.
+func (s *Service) Update(formID uuid.UUID, values any) (err error) {
+	defer observe("update_form", time.Now(), &err)
+
 	if values == nil {
 		return errors.New("values cannot be nil")
 	}
 
+	var version uint
+	if form, ok := values.(*entity.Form); ok {
+		if err := entity.ValidateNumbering(form.Numbering); err != nil {
+			return err
+		}
+		version = form.Version
+
+		// The status only changes through its transitions, see SetStatus
+		update := *form
+		update.Status = ""
+		values = &update
+	}
+
+	if err := s.authorize(formID); err != nil {
+		return err
+	}
+
+	if form, ok := values.(*entity.Form); ok {
+		if err := s.checkSlugChange(formID, form.Slug); err != nil {
+			return err
+		}
+
+		if err := s.sanitizeUpdate(formID, form); err != nil {
+			return err
+		}
+	}
+
+	previous, err := s.previousForm(formID)
+	if err != nil {
+		return err
+	}
+
 	// 1. Critical operation first (database)
-	if err := s.repo.UpdateMany(formID, values); err != nil {
+	if err := s.repo.UpdateMany(formID, version, values); err != nil {
 		return fmt.Errorf("failed to update form in repository: %w", err)
 	}
 
@@ -218,6 +452,11 @@ func (s *Service) Update(formID uuid.UUID, values any) error {
 		return fmt.Errorf("failed to retrieve updated form: %w", err)
 	}
 
+	payload, err := s.formUpdate(previous, form)
+	if err != nil {
+		return err
+	}
+
 	// 3. Run non-critical operations concurrently
 	var wg sync.WaitGroup
 	errChan := make(chan error, 2)
@@ -229,7 +468,7 @@ func (s *Service) Update(formID uuid.UUID, values any) error {
 		ctx, cancel := s.getContext()
 		defer cancel()
 
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
+		if err := s.cacheRetry.do(ctx, func() error {
 			return s.casher.AddToCash(ctx, formID.String(), form)
 		}); err != nil {
 			errChan <- fmt.Errorf("cache error: %w", err)
@@ -240,9 +479,7 @@ func (s *Service) Update(formID uuid.UUID, values any) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.publisher.Publish(form, "form.updated")
-		}); err != nil {
+		if err := s.publish(events.FormUpdated, payload); err != nil {
 			errChan <- fmt.Errorf("publish error: %w", err)
 		}
 	}()
@@ -258,10 +495,26 @@ func (s *Service) Update(formID uuid.UUID, values any) error {
 	return nil
 }
 
-// UpdateDescription changes the description of a form.
+// UpdateDescription changes the description of a form, sanitized in the
+// format of the form.
 func (s *Service) UpdateDescription(formID uuid.UUID, desc string) error {
+	if err := s.authorize(formID); err != nil {
+		return err
+	}
+
+	update := &entity.Form{Description: desc}
+	if err := s.sanitizeUpdate(formID, update); err != nil {
+		return err
+	}
+	desc = update.Description
+
+	previous, err := s.previousForm(formID)
+	if err != nil {
+		return err
+	}
+
 	// 1. Critical operation first (database)
-	if err := s.repo.Update(formID, "Description", desc); err != nil {
+	if err := s.repo.Update(formID, 0, "Description", desc); err != nil {
 		return fmt.Errorf("failed to update form description in repository: %w", err)
 	}
 
@@ -271,6 +524,11 @@ func (s *Service) UpdateDescription(formID uuid.UUID, desc string) error {
 		return fmt.Errorf("failed to retrieve updated form: %w", err)
 	}
 
+	payload, err := s.formUpdate(previous, form)
+	if err != nil {
+		return err
+	}
+
 	// 3. Run non-critical operations concurrently
 	var wg sync.WaitGroup
 	errChan := make(chan error, 2)
@@ -282,7 +540,7 @@ func (s *Service) UpdateDescription(formID uuid.UUID, desc string) error {
 		ctx, cancel := s.getContext()
 		defer cancel()
 
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
+		if err := s.cacheRetry.do(ctx, func() error {
 			return s.casher.AddToCash(ctx, formID.String(), form)
 		}); err != nil {
 			errChan <- fmt.Errorf("cache error: %w", err)
@@ -293,9 +551,7 @@ func (s *Service) UpdateDescription(formID uuid.UUID, desc string) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.publisher.Publish(form, "form.updated")
-		}); err != nil {
+		if err := s.publish(events.FormUpdated, payload); err != nil {
 			errChan <- fmt.Errorf("publish error: %w", err)
 		}
 	}()
@@ -311,28 +567,123 @@ func (s *Service) UpdateDescription(formID uuid.UUID, desc string) error {
 	return nil
 }
 
-// DeleteForm removes a form from the system.
-func (s *Service) DeleteForm(formID uuid.UUID) error {
+// SetNumbering changes how the questions of a form are numbered for
+// respondents, one of the entity.Numbering modes
+func (s *Service) SetNumbering(formID uuid.UUID, numbering string) error {
+	if err := entity.ValidateNumbering(numbering); err != nil {
+		return err
+	}
+
+	return s.changeForm(formID, func(*entity.Form) error {
+		if err := s.repo.Update(formID, 0, "Numbering", numbering); err != nil {
+			return fmt.Errorf("failed to update form numbering in repository: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteForm moves a form to the trash, from where RestoreForm brings it
+// back and PurgeForm removes it for good. A trashed form doesn't count
+// against its author's quota.
+func (s *Service) DeleteForm(formID uuid.UUID) (err error) {
+	defer observe("delete_form", time.Now(), &err)
+
+	if err := s.authorize(formID); err != nil {
+		return err
+	}
+
+	// Resolve what the form counts against its author's quota before it's gone
+	var owned *entity.Form
+	if s.quotas != nil {
+		form, err := s.repo.Get(formID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve form: %w", err)
+		}
+		owned = form
+	}
+
 	// 1. Critical operation first (database)
 	if err := s.repo.DeleteForm(formID); err != nil {
 		return fmt.Errorf("failed to delete form from repository: %w", err)
 	}
 
-	// 2. Run non-critical operations concurrently
+	if owned != nil {
+		if err := s.releaseQuota(owned.Author, 1, len(owned.Questions)); err != nil {
+			return err
+		}
+	}
+
+	// 2. The cached form is kept until purged, see PurgeForm, but marked as
+	// trashed so it isn't served as live
+	trashed, err := s.repo.GetTrashedForm(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve trashed form: %w", err)
+	}
+
+	return s.syncForm(trashed, events.NewFormDeleted(formID).Payload, events.FormDeleted)
+}
+
+// DeleteQuestion removes a question from a form.
+func (s *Service) DeleteQuestion(formID uuid.UUID, orderNumber uint) (err error) {
+	defer observe("delete_question", time.Now(), &err)
+
+	if err := s.authorize(formID); err != nil {
+		return err
+	}
+
+	// The order number may match nothing, count before and after to release the quota
+	before := 0
+	if s.quotas != nil {
+		form, err := s.repo.Get(formID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve form: %w", err)
+		}
+		before = len(form.Questions)
+	}
+
+	previous, err := s.previousForm(formID)
+	if err != nil {
+		return err
+	}
+
+	// 1. Critical operation first (database)
+	if err := s.repo.DeleteQuestion(formID, orderNumber); err != nil {
+		return fmt.Errorf("failed to delete question from repository: %w", err)
+	}
+
+	// 2. Get updated form
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve updated form: %w", err)
+	}
+
+	if s.quotas != nil && before > len(form.Questions) {
+		if err := s.releaseQuota(form.Author, 0, before-len(form.Questions)); err != nil {
+			return err
+		}
+	}
+
+	payload, err := s.formUpdate(previous, form)
+	if err != nil {
+		return err
+	}
+
+	// 3. Run non-critical operations concurrently
 	var wg sync.WaitGroup
 	errChan := make(chan error, 2)
 
-	// Cache removal operation
+	// Cache operation
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		ctx, cancel := s.getContext()
 		defer cancel()
 
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.casher.RemoveFromCash(ctx, formID.String())
+		if err := s.cacheRetry.do(ctx, func() error {
+			return s.casher.AddToCash(ctx, formID.String(), form)
 		}); err != nil {
-			errChan <- fmt.Errorf("cache removal error: %w", err)
+			errChan <- fmt.Errorf("cache error: %w", err)
 		}
 	}()
 
@@ -340,13 +691,7 @@ func (s *Service) DeleteForm(formID uuid.UUID) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.publisher.Publish(struct {
-				FormID string `json:"form_id"`
-			}{
-				FormID: formID.String(),
-			}, "form.deleted")
-		}); err != nil {
+		if err := s.publish(events.FormUpdated, payload); err != nil {
 			errChan <- fmt.Errorf("publish error: %w", err)
 		}
 	}()
@@ -362,20 +707,47 @@ func (s *Service) DeleteForm(formID uuid.UUID) error {
 	return nil
 }
 
-// DeleteQuestion removes a question from a form.
-func (s *Service) DeleteQuestion(formID uuid.UUID, orderNumber uint) error {
-	// 1. Critical operation first (database)
-	if err := s.repo.DeleteQuestion(formID, orderNumber); err != nil {
+// DeleteQuestionByID removes a question identified by its ID.
+// Prefer it over DeleteQuestion, whose order number is ambiguous after reorders.
+func (s *Service) DeleteQuestionByID(questionID uuid.UUID) error {
+	// 1. Resolve the parent form before the row is gone
+	question, err := s.repo.GetQuestion(questionID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve question: %w", err)
+	}
+
+	formID := question.FormID
+
+	if err = s.authorize(formID); err != nil {
+		return err
+	}
+
+	previous, err := s.previousForm(formID)
+	if err != nil {
+		return err
+	}
+
+	// 2. Critical operation (database)
+	if err := s.repo.DeleteQuestionByID(questionID); err != nil {
 		return fmt.Errorf("failed to delete question from repository: %w", err)
 	}
 
-	// 2. Get updated form
+	// 3. Get updated form
 	form, err := s.repo.Get(formID)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve updated form: %w", err)
 	}
 
-	// 3. Run non-critical operations concurrently
+	if err := s.releaseQuota(form.Author, 0, 1); err != nil {
+		return err
+	}
+
+	payload, err := s.formUpdate(previous, form)
+	if err != nil {
+		return err
+	}
+
+	// 4. Run non-critical operations concurrently
 	var wg sync.WaitGroup
 	errChan := make(chan error, 2)
 
@@ -386,7 +758,7 @@ func (s *Service) DeleteQuestion(formID uuid.UUID, orderNumber uint) error {
 		ctx, cancel := s.getContext()
 		defer cancel()
 
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
+		if err := s.cacheRetry.do(ctx, func() error {
 			return s.casher.AddToCash(ctx, formID.String(), form)
 		}); err != nil {
 			errChan <- fmt.Errorf("cache error: %w", err)
@@ -397,9 +769,7 @@ func (s *Service) DeleteQuestion(formID uuid.UUID, orderNumber uint) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := retrier.Do(DefaultRetryAttempts, DefaultRetryDelay, func() error {
-			return s.publisher.Publish(form, "form.updated")
-		}); err != nil {
+		if err := s.publish(events.FormUpdated, payload); err != nil {
 			errChan <- fmt.Errorf("publish error: %w", err)
 		}
 	}()
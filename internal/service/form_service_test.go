@@ -3,13 +3,19 @@ package service
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/pkg/config"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 // MockCasher is a mock implementation of the Casher interface
@@ -27,6 +33,11 @@ func (m *MockCasher) RemoveFromCash(ctx context.Context, key string) error {
 	return args.Error(0)
 }
 
+func (m *MockCasher) AddToCashIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, key, value, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockCasher) GetCashFor(ctx context.Context, key string) ([]byte, error) {
 	args := m.Called(ctx, key)
 	if args.Get(0) == nil {
@@ -53,13 +64,26 @@ func (m *MockRepository) Get(id uuid.UUID) (*entity.Form, error) {
 	return args.Get(0).(*entity.Form), args.Error(1)
 }
 
-func (m *MockRepository) Update(id uuid.UUID, field string, value interface{}) error {
-	args := m.Called(id, field, value)
+func (m *MockRepository) GetBySlug(slug string) (*entity.Form, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Form), args.Error(1)
+}
+
+func (m *MockRepository) SlugExists(slug string) (bool, error) {
+	args := m.Called(slug)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) Update(id uuid.UUID, version uint, field string, value interface{}) error {
+	args := m.Called(id, version, field, value)
 	return args.Error(0)
 }
 
-func (m *MockRepository) UpdateMany(id uuid.UUID, values interface{}) error {
-	args := m.Called(id, values)
+func (m *MockRepository) UpdateMany(id uuid.UUID, version uint, values interface{}) error {
+	args := m.Called(id, version, values)
 	return args.Error(0)
 }
 
@@ -68,26 +92,279 @@ func (m *MockRepository) DeleteForm(id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetTrashedForm(id uuid.UUID) (*entity.Form, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Form), args.Error(1)
+}
+
+func (m *MockRepository) RestoreForm(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) PurgeForm(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockRepository) DeleteQuestion(formID uuid.UUID, orderNumber uint) error {
 	args := m.Called(formID, orderNumber)
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetQuestion(questionID uuid.UUID) (*entity.Question, error) {
+	args := m.Called(questionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Question), args.Error(1)
+}
+
+func (m *MockRepository) DeleteQuestionByID(questionID uuid.UUID) error {
+	args := m.Called(questionID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateAccess(formID uuid.UUID, domains []string, inviteOnly bool) error {
+	args := m.Called(formID, domains, inviteOnly)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetInviteByHash(formID uuid.UUID, tokenHash string) (*entity.InviteToken, error) {
+	args := m.Called(formID, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.InviteToken), args.Error(1)
+}
+
+func (m *MockRepository) RevokeInvite(inviteID uuid.UUID) (*entity.InviteToken, error) {
+	args := m.Called(inviteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.InviteToken), args.Error(1)
+}
+
+func (m *MockRepository) ListInvites(formID uuid.UUID) ([]entity.InviteToken, error) {
+	args := m.Called(formID)
+	return args.Get(0).([]entity.InviteToken), args.Error(1)
+}
+
+func (m *MockRepository) RedeemInvite(inviteID uuid.UUID) error {
+	args := m.Called(inviteID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReleaseInvite(inviteID uuid.UUID) error {
+	args := m.Called(inviteID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CountQuestions(formID uuid.UUID) (int64, error) {
+	args := m.Called(formID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) SaveForm(form *entity.Form) (*entity.FormChanges, error) {
+	args := m.Called(form)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FormChanges), args.Error(1)
+}
+
+func (m *MockRepository) CreateFormVersion(formID uuid.UUID) (*entity.FormVersion, error) {
+	args := m.Called(formID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FormVersion), args.Error(1)
+}
+
+func (m *MockRepository) GetFormVersion(formID uuid.UUID, version uint) (*entity.FormVersion, error) {
+	args := m.Called(formID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FormVersion), args.Error(1)
+}
+
+func (m *MockRepository) GetFormVersionAt(formID uuid.UUID, at time.Time) (*entity.FormVersion, error) {
+	args := m.Called(formID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FormVersion), args.Error(1)
+}
+
+func (m *MockRepository) GetTemplate(templateID uuid.UUID) (*entity.Template, error) {
+	args := m.Called(templateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Template), args.Error(1)
+}
+
+func (m *MockRepository) GetSection(sectionID uuid.UUID) (*entity.Section, error) {
+	args := m.Called(sectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Section), args.Error(1)
+}
+
+func (m *MockRepository) UpdateSection(sectionID uuid.UUID, value any) error {
+	args := m.Called(sectionID, value)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteSection(sectionID uuid.UUID) error {
+	args := m.Called(sectionID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateQuestions(formID uuid.UUID, questions []entity.Question) error {
+	args := m.Called(formID, questions)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReorderQuestions(formID uuid.UUID, order []uuid.UUID) error {
+	args := m.Called(formID, order)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReorderSections(formID uuid.UUID, order []uuid.UUID) error {
+	args := m.Called(formID, order)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReplaceBranches(formID uuid.UUID, branches []entity.Branch) error {
+	args := m.Called(formID, branches)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetFormTags(formID uuid.UUID, tags []entity.Tag) error {
+	args := m.Called(formID, tags)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListFormsByTag(tenantID, tag string, filter *entity.Filter, offset, limit int) ([]entity.Form, error) {
+	args := m.Called(tenantID, tag, filter, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Form), args.Error(1)
+}
+
+func (m *MockRepository) CountFormsByTag(tenantID, tag string, filter *entity.Filter) (int64, error) {
+	args := m.Called(tenantID, tag, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) ListDueSchedules(now time.Time, limit int) ([]entity.Form, error) {
+	args := m.Called(now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Form), args.Error(1)
+}
+
+func (m *MockRepository) OpenScheduled(formID uuid.UUID, now time.Time) (bool, error) {
+	args := m.Called(formID, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CloseScheduled(formID uuid.UUID, now time.Time) (bool, error) {
+	args := m.Called(formID, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) MoveQuestion(questionID uuid.UUID, sectionID *uuid.UUID) error {
+	args := m.Called(questionID, sectionID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetInvite(inviteID uuid.UUID) (*entity.InviteToken, error) {
+	args := m.Called(inviteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.InviteToken), args.Error(1)
+}
+
+func (m *MockRepository) CountFormsPerAuthor() ([]int64, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockRepository) GetCollaborator(formID uuid.UUID, userID string) (*entity.Collaborator, error) {
+	args := m.Called(formID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Collaborator), args.Error(1)
+}
+
+func (m *MockRepository) SaveCollaborator(collaborator *entity.Collaborator) error {
+	args := m.Called(collaborator)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CloneForm(form *entity.Form) error {
+	args := m.Called(form)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteCollaborator(formID uuid.UUID, userID string) (*entity.Collaborator, error) {
+	args := m.Called(formID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Collaborator), args.Error(1)
+}
+
+func (m *MockRepository) ListCollaborators(formID uuid.UUID) ([]entity.Collaborator, error) {
+	args := m.Called(formID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Collaborator), args.Error(1)
+}
+
 // MockPublisher is a mock implementation of the Publisher interface
 type MockPublisher struct {
 	mock.Mock
 }
 
-func (m *MockPublisher) Publish(data interface{}, event string) error {
+func (m *MockPublisher) Publish(_ context.Context, data interface{}, event string) error {
 	args := m.Called(data, event)
 	return args.Error(0)
 }
 
+// questionID is a fixed question ID, readable in failures by its last byte
+func questionID(n uint) uuid.UUID {
+	var id uuid.UUID
+	id[15] = byte(n)
+	return id
+}
+
 func setupService() (*Service, *MockCasher, *MockRepository, *MockPublisher) {
 	mockCasher := &MockCasher{}
 	mockRepo := &MockRepository{}
 	mockPublisher := &MockPublisher{}
 	service := Init(mockCasher, mockRepo, mockPublisher, 5*time.Second)
+
+	// Slugs are free unless a test takes them, see newSlugService
+	mockRepo.On("SlugExists", mock.Anything).Return(false, nil).Maybe()
+	mockRepo.On("GetBySlug", mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+
 	return service, mockCasher, mockRepo, mockPublisher
 }
 
@@ -195,6 +472,22 @@ func TestService_CreateQuestion_NilQuestion(t *testing.T) {
 	assert.Contains(t, err.Error(), "question cannot be nil")
 }
 
+func TestService_CreateQuestion_InvalidType(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	question := &entity.Question{
+		FormID:      uuid.New(),
+		OrderNumber: 1,
+		Type:        "choice",
+	}
+
+	err := service.CreateQuestion(question)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid question")
+	mockRepo.AssertNotCalled(t, "Create", question)
+}
+
 func TestService_CreateQuestion_RepositoryError(t *testing.T) {
 	service, _, mockRepo, _ := setupService()
 
@@ -227,43 +520,6 @@ func TestService_CreateQuestion_FormRetrievalError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to retrieve updated form")
 }
 
-func TestService_UpdateStatus_Success(t *testing.T) {
-	service, mockCasher, mockRepo, mockPublisher := setupService()
-
-	formID := uuid.New()
-	form := &entity.Form{
-		ID:     formID,
-		Title:  "Test Form",
-		Closed: true,
-	}
-
-	mockRepo.On("Update", formID, "Closed", true).Return(nil)
-	mockRepo.On("Get", formID).Return(form, nil)
-	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), formID.String(), form).
-		Return(nil)
-	mockPublisher.On("Publish", form, "form.updated").Return(nil)
-
-	err := service.UpdateStatus(formID, true)
-
-	assert.NoError(t, err)
-	mockRepo.AssertExpectations(t)
-	mockCasher.AssertExpectations(t)
-	mockPublisher.AssertExpectations(t)
-}
-
-func TestService_UpdateStatus_RepositoryError(t *testing.T) {
-	service, _, mockRepo, _ := setupService()
-
-	formID := uuid.New()
-
-	mockRepo.On("Update", formID, "Closed", false).Return(errors.New("database error"))
-
-	err := service.UpdateStatus(formID, false)
-
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to update form status in repository")
-}
-
 func TestService_Update_Success(t *testing.T) {
 	service, mockCasher, mockRepo, mockPublisher := setupService()
 
@@ -276,7 +532,7 @@ func TestService_Update_Success(t *testing.T) {
 		Title: "Updated Title",
 	}
 
-	mockRepo.On("UpdateMany", formID, values).Return(nil)
+	mockRepo.On("UpdateMany", formID, uint(0), values).Return(nil)
 	mockRepo.On("Get", formID).Return(form, nil)
 	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), formID.String(), form).
 		Return(nil)
@@ -309,7 +565,7 @@ func TestService_Update_RepositoryError(t *testing.T) {
 		"Title": "Updated Title",
 	}
 
-	mockRepo.On("UpdateMany", formID, values).Return(errors.New("database error"))
+	mockRepo.On("UpdateMany", formID, uint(0), values).Return(errors.New("database error"))
 
 	err := service.Update(formID, values)
 
@@ -317,6 +573,23 @@ func TestService_Update_RepositoryError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to update form in repository")
 }
 
+func TestService_Update_VersionConflict(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Title: "Updated Title", Version: 2}
+	conflict := &entity.VersionConflictError{FormID: formID, Expected: 2, Actual: 3}
+
+	mockRepo.On("UpdateMany", formID, uint(2), form).Return(conflict)
+
+	err := service.Update(formID, form)
+
+	var got *entity.VersionConflictError
+	require.ErrorAs(t, err, &got)
+	assert.Equal(t, uint(3), got.Actual)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
 func TestService_UpdateDescription_Success(t *testing.T) {
 	service, mockCasher, mockRepo, mockPublisher := setupService()
 
@@ -328,7 +601,7 @@ func TestService_UpdateDescription_Success(t *testing.T) {
 		Description: description,
 	}
 
-	mockRepo.On("Update", formID, "Description", description).Return(nil)
+	mockRepo.On("Update", formID, uint(0), "Description", description).Return(nil)
 	mockRepo.On("Get", formID).Return(form, nil)
 	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), formID.String(), form).
 		Return(nil)
@@ -348,7 +621,8 @@ func TestService_UpdateDescription_RepositoryError(t *testing.T) {
 	formID := uuid.New()
 	description := "Updated Description"
 
-	mockRepo.On("Update", formID, "Description", description).Return(errors.New("database error"))
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID}, nil)
+	mockRepo.On("Update", formID, uint(0), "Description", description).Return(errors.New("database error"))
 
 	err := service.UpdateDescription(formID, description)
 
@@ -356,28 +630,49 @@ func TestService_UpdateDescription_RepositoryError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to update form description in repository")
 }
 
+func TestService_SetNumbering(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Numbering: entity.NumberingContinuous}
+
+	mockRepo.On("Update", formID, uint(0), "Numbering", entity.NumberingContinuous).Return(nil)
+	mockRepo.On("Get", formID).Return(form, nil)
+	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), formID.String(), form).
+		Return(nil)
+	mockPublisher.On("Publish", form, events.FormUpdated.String()).Return(nil)
+
+	assert.NoError(t, service.SetNumbering(formID, entity.NumberingContinuous))
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+
+	err := service.SetNumbering(formID, "roman")
+	assert.ErrorIs(t, err, entity.ErrInvalidNumbering)
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
 func TestService_DeleteForm_Success(t *testing.T) {
 	service, mockCasher, mockRepo, mockPublisher := setupService()
 
 	formID := uuid.New()
+	trashed := &entity.Form{ID: formID, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
 
 	mockRepo.On("DeleteForm", formID).Return(nil)
-	mockCasher.On("RemoveFromCash", mock.AnythingOfType("*context.timerCtx"), formID.String()).
-		Return(nil)
+	mockRepo.On("GetTrashedForm", formID).Return(trashed, nil)
+	mockCasher.On("AddToCash", mock.Anything, formID.String(), trashed).Return(nil)
 	mockPublisher.On("Publish", mock.MatchedBy(func(data interface{}) bool {
-		if payload, ok := data.(struct {
-			FormID string `json:"form_id"`
-		}); ok {
+		if payload, ok := data.(*events.FormDeletedPayload); ok {
 			return payload.FormID == formID.String()
 		}
 		return false
-	}), "form.deleted").Return(nil)
+	}), events.FormDeleted.String()).Return(nil)
 
 	err := service.DeleteForm(formID)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 	mockCasher.AssertExpectations(t)
+	mockCasher.AssertNotCalled(t, "RemoveFromCash", mock.Anything, mock.Anything)
 	mockPublisher.AssertExpectations(t)
 }
 
@@ -393,3 +688,261 @@ func TestService_DeleteForm_RepositoryError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to delete form from repository")
 }
+
+func TestService_DeleteQuestionByID_Success(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	question := &entity.Question{FormID: formID, OrderNumber: 2}
+	question.ID = questionID(7)
+	form := &entity.Form{
+		ID:    formID,
+		Title: "Test Form",
+	}
+
+	mockRepo.On("GetQuestion", questionID(7)).Return(question, nil)
+	mockRepo.On("DeleteQuestionByID", questionID(7)).Return(nil)
+	mockRepo.On("Get", formID).Return(form, nil)
+	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), formID.String(), form).
+		Return(nil)
+	mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+	err := service.DeleteQuestionByID(questionID(7))
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockCasher.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestService_DeleteQuestionByID_QuestionNotFound(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	mockRepo.On("GetQuestion", questionID(7)).Return(nil, errors.New("record not found"))
+
+	err := service.DeleteQuestionByID(questionID(7))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve question")
+	mockRepo.AssertNotCalled(t, "DeleteQuestionByID", questionID(7))
+}
+
+func TestService_DeleteQuestionByID_RepositoryError(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	question := &entity.Question{FormID: uuid.New()}
+	question.ID = questionID(7)
+
+	mockRepo.On("GetQuestion", questionID(7)).Return(question, nil)
+	mockRepo.On("DeleteQuestionByID", questionID(7)).Return(errors.New("database error"))
+
+	err := service.DeleteQuestionByID(questionID(7))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete question from repository")
+}
+
+func TestService_RetryPolicy_ZeroRetries(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+	service.SetRetryPolicy(RetryPolicy{}, DefaultRetryPolicy)
+
+	form := &entity.Form{ID: uuid.New()}
+
+	mockRepo.On("Create", form).Return(nil)
+	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), form.ID.String(), form).
+		Return(errors.New("cache error"))
+	mockPublisher.On("Publish", form, "form.created").Return(nil)
+
+	err := service.CreateForm(form)
+
+	assert.Error(t, err)
+	mockCasher.AssertNumberOfCalls(t, "AddToCash", 1)
+	mockPublisher.AssertNumberOfCalls(t, "Publish", 1)
+}
+
+func TestService_RetryPolicy_Retries(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+	service.SetRetryPolicy(RetryPolicy{Retries: 2}, RetryPolicy{Retries: 1})
+
+	form := &entity.Form{ID: uuid.New()}
+
+	mockRepo.On("Create", form).Return(nil)
+	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), form.ID.String(), form).
+		Return(errors.New("cache error"))
+	mockPublisher.On("Publish", form, "form.created").Return(errors.New("publish error"))
+
+	err := service.CreateForm(form)
+
+	assert.Error(t, err)
+	mockCasher.AssertNumberOfCalls(t, "AddToCash", 3)
+	mockPublisher.AssertNumberOfCalls(t, "Publish", 2)
+	assert.Equal(t, RetryPolicy{Retries: 2}, service.RetryPolicies()[SideEffectCache])
+}
+
+func TestService_RetryPolicy_FromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("retry:\n  cache:\n    retries: 4\n    delay_ms: 20\n"), 0o600))
+	t.Setenv("FORM_SERVICE_RETRY_PUBLISH_RETRIES", "0")
+
+	cfg, err := config.Init(path)
+	require.NoError(t, err)
+
+	service, _, _, _ := setupService()
+	service.SetRetryPolicy(RetryPolicyFromConfig(cfg.Retry.Cache), RetryPolicyFromConfig(cfg.Retry.Publish))
+
+	assert.Equal(t, RetryPolicy{Retries: 4, Delay: 20 * time.Millisecond}, service.RetryPolicies()[SideEffectCache])
+	assert.Equal(t, RetryPolicy{Retries: 0, Delay: 5 * time.Millisecond}, service.RetryPolicies()[SideEffectPublish])
+}
+
+func TestService_RetryPolicy_Shutdown(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+	service.SetRetryPolicy(RetryPolicy{Retries: 5, Delay: time.Hour}, RetryPolicy{Retries: 5, Delay: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	service.SetContext(ctx)
+
+	form := &entity.Form{ID: uuid.New()}
+
+	mockRepo.On("Create", form).Return(nil)
+	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), form.ID.String(), form).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(errors.New("cache error"))
+	mockPublisher.On("Publish", form, "form.created").Return(errors.New("publish error"))
+
+	done := make(chan error, 1)
+	go func() { done <- service.CreateForm(form) }()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("retries kept waiting after the service context was canceled")
+	}
+
+	mockCasher.AssertNumberOfCalls(t, "AddToCash", 1)
+	assert.LessOrEqual(t, len(mockPublisher.Calls), 1, "no publish attempt after the cancellation")
+}
+
+func TestService_DryRunDeleteForm(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID}, nil)
+
+	result := service.DryRunDeleteForm(formID)
+
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Evicted)
+	assert.Len(t, result.Events, 1)
+	assert.Equal(t, "form.deleted", result.Events[0].Type)
+	mockRepo.AssertNotCalled(t, "DeleteForm", formID)
+	mockCasher.AssertNotCalled(t, "RemoveFromCash", mock.Anything, mock.Anything)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestService_DryRunDeleteForm_NotFound(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(nil, errors.New("record not found"))
+
+	result := service.DryRunDeleteForm(formID)
+
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Error, "failed to retrieve form")
+	assert.Empty(t, result.Events)
+}
+
+func TestService_DryRunDeleteForm_NotEditor(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Author: "alice"}, nil)
+	mockRepo.On("GetCollaborator", formID, "bob").Return(&entity.Collaborator{Role: entity.RoleViewer}, nil)
+
+	result := service.As("bob").DryRunDeleteForm(formID)
+
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Error, ErrNotEditor.Error())
+	assert.Empty(t, result.Events)
+}
+
+func TestService_DryRunDeleteQuestion(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	formID := uuid.New()
+	form := &entity.Form{
+		ID: formID,
+		Questions: []entity.Question{
+			{FormID: formID, OrderNumber: 1},
+			{FormID: formID, OrderNumber: 2},
+		},
+	}
+	mockRepo.On("Get", formID).Return(form, nil)
+
+	result := service.DryRunDeleteQuestion(formID, 1)
+
+	assert.True(t, result.Valid)
+	preview := result.Result.(*entity.Form)
+	assert.Len(t, preview.Questions, 1)
+	assert.Equal(t, uint(2), preview.Questions[0].OrderNumber)
+	assert.Len(t, form.Questions, 2, "stored form must not be modified")
+	assert.Equal(t, "form.updated", result.Events[0].Type)
+	mockRepo.AssertNotCalled(t, "DeleteQuestion", formID, uint(1))
+}
+
+func TestService_PublishDryRun(t *testing.T) {
+	service, _, _, mockPublisher := setupService()
+
+	result := &entity.DryRunResult{RequestID: "req-1", Valid: true}
+	mockPublisher.On("Publish", result, events.DryRunResult.String()).Return(nil)
+
+	assert.NoError(t, service.PublishDryRun(result))
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestService_TrackDraftProgress(t *testing.T) {
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Status: entity.StatusPublished, Questions: []entity.Question{{}, {}, {}}}
+	key := formID.String() + ":progress:resp-1"
+
+	t.Run("first draft emits started and progress", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockCasher.On("AddToCashIfAbsent", mock.Anything, key, mock.Anything, ProgressMarkerTTL).Return(true, nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(p *entity.ResponseProgress) bool {
+			return p.QuestionIndex == 1 && p.QuestionCount == 3
+		}), mock.Anything).Return(nil)
+
+		err := service.TrackDraftProgress(formID, "resp-1", 1)
+
+		assert.NoError(t, err)
+		mockPublisher.AssertCalled(t, "Publish", mock.Anything, events.ResponseStarted.String())
+		mockPublisher.AssertCalled(t, "Publish", mock.Anything, events.ResponseProgress.String())
+	})
+
+	t.Run("later drafts only emit progress", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockCasher.On("AddToCashIfAbsent", mock.Anything, key, mock.Anything, ProgressMarkerTTL).Return(false, nil)
+		mockPublisher.On("Publish", mock.Anything, events.ResponseProgress.String()).Return(nil)
+
+		err := service.TrackDraftProgress(formID, "resp-1", 2)
+
+		assert.NoError(t, err)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, events.ResponseStarted.String())
+	})
+
+	t.Run("closed form", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Status: entity.StatusClosed}, nil)
+
+		err := service.TrackDraftProgress(formID, "resp-1", 0)
+
+		assert.Error(t, err)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// UpdateSettings changes the settings of a form set in update, leaving the
+// others as they are, and publishes form.updated with the changed form
+func (s *Service) UpdateSettings(formID uuid.UUID, update entity.FormSettingsUpdate) error {
+	if update.IsEmpty() {
+		return nil
+	}
+
+	return s.changeForm(formID, func(form *entity.Form) error {
+		settings := update.Apply(form.Settings)
+		if err := settings.Validate(); err != nil {
+			return err
+		}
+
+		values := map[string]any{
+			"settings_anonymous_responses":  settings.AnonymousResponses,
+			"settings_edit_after_submit":    settings.EditAfterSubmit,
+			"settings_confirmation_message": settings.ConfirmationMessage,
+		}
+
+		if err := s.repo.UpdateMany(formID, 0, values); err != nil {
+			return fmt.Errorf("failed to update form settings in repository: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_UpdateSettings(t *testing.T) {
+	t.Run("changes only the given settings", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		formID := uuid.New()
+		form := &entity.Form{ID: formID, Settings: entity.FormSettings{EditAfterSubmit: true, ConfirmationMessage: "Thanks!"}}
+		anonymous := true
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockRepo.On("UpdateMany", formID, uint(0), map[string]any{
+			"settings_anonymous_responses":  true,
+			"settings_edit_after_submit":    true,
+			"settings_confirmation_message": "Thanks!",
+		}).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, events.FormUpdated.String()).Return(nil)
+
+		assert.NoError(t, service.UpdateSettings(formID, entity.FormSettingsUpdate{AnonymousResponses: &anonymous}))
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects long confirmation messages", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		formID := uuid.New()
+		message := strings.Repeat("x", entity.MaxConfirmationMessageLength+1)
+
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID}, nil)
+
+		err := service.UpdateSettings(formID, entity.FormSettingsUpdate{ConfirmationMessage: &message})
+		assert.ErrorIs(t, err, entity.ErrInvalidSettings)
+		mockRepo.AssertNotCalled(t, "UpdateMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ignores empty updates", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		assert.NoError(t, service.UpdateSettings(uuid.New(), entity.FormSettingsUpdate{}))
+		mockRepo.AssertNotCalled(t, "Get", mock.Anything)
+	})
+}
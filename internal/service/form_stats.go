@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// ErrFormStatsDisabled is returned when reading form stats that aren't kept
+var ErrFormStatsDisabled = errors.New("form stats are not enabled")
+
+// EnableFormStats counts the views of the respondent view of every form and
+// its accepted responses in repo. Without it no counters are kept.
+func (s *Service) EnableFormStats(repo FormStatsRepository) {
+	s.formStats = repo
+}
+
+// formStatsScope is the query cache scope of the counters of a form
+func formStatsScope(formID uuid.UUID) string {
+	return "form_stats:" + formID.String()
+}
+
+// GetFormStats returns the view and submission counters of a form. They
+// are served from the query cache when enabled, so they may lag the
+// database by its TTL.
+func (s *Service) GetFormStats(formID uuid.UUID) (_ *entity.FormStats, err error) {
+	defer observe("get_form_stats", time.Now(), &err)
+
+	if s.formStats == nil {
+		return nil, ErrFormStatsDisabled
+	}
+
+	return cachedQuery(s, formStatsScope(formID), "", func() (*entity.FormStats, error) {
+		stats, err := s.formStats.GetFormStats(formID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve form stats: %w", err)
+		}
+		return stats, nil
+	})
+}
+
+// countFormView counts a view of the respondent view of a form. Counting is
+// best effort: the repository logs failures, which don't keep respondents
+// from the form.
+func (s *Service) countFormView(formID uuid.UUID) {
+	if s.formStats != nil {
+		_ = s.formStats.IncrementFormViews(formID, time.Now())
+	}
+}
+
+// countFormSubmission counts an accepted response to a form, best effort
+// like countFormView
+func (s *Service) countFormSubmission(formID uuid.UUID) {
+	if s.formStats != nil {
+		_ = s.formStats.IncrementFormSubmissions(formID, time.Now())
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockFormStatsRepository is a mock implementation of the FormStatsRepository interface
+type MockFormStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockFormStatsRepository) IncrementFormViews(formID uuid.UUID, at time.Time) error {
+	args := m.Called(formID, at)
+	return args.Error(0)
+}
+
+func (m *MockFormStatsRepository) IncrementFormSubmissions(formID uuid.UUID, at time.Time) error {
+	args := m.Called(formID, at)
+	return args.Error(0)
+}
+
+func (m *MockFormStatsRepository) GetFormStats(formID uuid.UUID) (*entity.FormStats, error) {
+	args := m.Called(formID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FormStats), args.Error(1)
+}
+
+func TestService_GetFormStats(t *testing.T) {
+	t.Run("requires form stats", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		_, err := service.GetFormStats(uuid.New())
+		assert.ErrorIs(t, err, ErrFormStatsDisabled)
+	})
+
+	t.Run("caches the counters", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		statsRepo := &MockFormStatsRepository{}
+		service.EnableFormStats(statsRepo)
+		service.EnableQueryCache(newMemoryQueries(), time.Minute)
+
+		formID := uuid.New()
+		statsRepo.On("GetFormStats", formID).Return(&entity.FormStats{FormID: formID, Views: 4, Submissions: 1}, nil)
+
+		for range 2 {
+			stats, err := service.GetFormStats(formID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(4), stats.Views)
+			assert.Equal(t, int64(1), stats.Submissions)
+		}
+		statsRepo.AssertNumberOfCalls(t, "GetFormStats", 1)
+	})
+}
+
+func TestService_FormStats_Counting(t *testing.T) {
+	t.Run("counts served views", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		statsRepo := &MockFormStatsRepository{}
+		service.EnableFormStats(statsRepo)
+
+		version := publishedVersion(uuid.New())
+		mockRepo.On("Get", version.FormID).Return(&entity.Form{ID: version.FormID, Status: entity.StatusPublished}, nil)
+		mockRepo.On("GetFormVersion", version.FormID, uint(0)).Return(version, nil)
+		statsRepo.On("IncrementFormViews", version.FormID, mock.Anything).Return(errors.New("database down"))
+
+		_, err := service.GetPublicForm(version.FormID)
+		require.NoError(t, err, "failing to count doesn't fail the view")
+		statsRepo.AssertNumberOfCalls(t, "IncrementFormViews", 1)
+	})
+
+	t.Run("doesn't count forms not served", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		statsRepo := &MockFormStatsRepository{}
+		service.EnableFormStats(statsRepo)
+
+		formID := uuid.New()
+		mockRepo.On("Get", formID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.GetPublicForm(formID)
+		assert.ErrorIs(t, err, ErrFormNotPublic)
+		statsRepo.AssertNotCalled(t, "IncrementFormViews", mock.Anything, mock.Anything)
+	})
+
+	t.Run("counts accepted responses only", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		statsRepo := &MockFormStatsRepository{}
+		service.EnableFormStats(statsRepo)
+
+		formID := uuid.New()
+		mockRepo.On("Get", formID).Return(responseForm(formID), nil)
+		mockPublisher.On("Publish", mock.Anything, events.ResponseAccepted.String()).Return(nil)
+		mockPublisher.On("Publish", mock.Anything, events.ResponseRejected.String()).Return(nil)
+		statsRepo.On("IncrementFormSubmissions", formID, mock.Anything).Return(nil)
+
+		require.NoError(t, service.SubmitResponse(&entity.ResponseSubmission{
+			FormID:  formID.String(),
+			Answers: []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hi"`)}},
+		}))
+		assert.ErrorIs(t, service.SubmitResponse(&entity.ResponseSubmission{
+			FormID:  formID.String(),
+			Answers: []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`42`)}},
+		}), ErrInvalidResponse)
+
+		statsRepo.AssertNumberOfCalls(t, "IncrementFormSubmissions", 1)
+	})
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// UpdateTheme changes the theme fields set in update, leaving the others as
+// they are, and publishes form.updated with the restyled form
+func (s *Service) UpdateTheme(formID uuid.UUID, update entity.ThemeUpdate) error {
+	if update.IsEmpty() {
+		return nil
+	}
+
+	return s.changeForm(formID, func(form *entity.Form) error {
+		theme := update.Apply(form.Theme)
+		if err := theme.Validate(); err != nil {
+			return err
+		}
+
+		values := map[string]any{
+			"theme_primary_color":    theme.PrimaryColor,
+			"theme_background_color": theme.BackgroundColor,
+			"theme_text_color":       theme.TextColor,
+			"theme_logo_url":         theme.LogoURL,
+			"theme_font":             theme.Font,
+		}
+
+		if err := s.repo.UpdateMany(formID, 0, values); err != nil {
+			return fmt.Errorf("failed to update form theme in repository: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_UpdateTheme(t *testing.T) {
+	t.Run("changes only the given fields", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		formID := uuid.New()
+		form := &entity.Form{ID: formID, Theme: entity.Theme{PrimaryColor: "#336699", Font: "Open Sans"}}
+		logo := "https://example.com/logo.png"
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockRepo.On("UpdateMany", formID, uint(0), map[string]any{
+			"theme_primary_color":    "#336699",
+			"theme_background_color": "",
+			"theme_text_color":       "",
+			"theme_logo_url":         logo,
+			"theme_font":             "Open Sans",
+		}).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, events.FormUpdated.String()).Return(nil)
+
+		assert.NoError(t, service.UpdateTheme(formID, entity.ThemeUpdate{LogoURL: &logo}))
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid colors", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		formID := uuid.New()
+		color := "red; background: url(x)"
+
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID}, nil)
+
+		err := service.UpdateTheme(formID, entity.ThemeUpdate{TextColor: &color})
+		assert.ErrorIs(t, err, entity.ErrInvalidTheme)
+		mockRepo.AssertNotCalled(t, "UpdateMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ignores empty updates", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		assert.NoError(t, service.UpdateTheme(uuid.New(), entity.ThemeUpdate{}))
+		mockRepo.AssertNotCalled(t, "Get", mock.Anything)
+	})
+}
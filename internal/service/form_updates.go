@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Payloads form.updated can carry
+const (
+	FormUpdatesSnapshot     = "snapshot"      // The updated form
+	FormUpdatesSnapshotDiff = "snapshot_diff" // The updated form and its diff from the previous version
+)
+
+// EnableFormDiffs makes form.updated carry the field-level diff from the
+// previous version next to the snapshot, see entity.FormUpdate. Every update
+// then reads the form once more before writing.
+func (s *Service) EnableFormDiffs() {
+	s.diffs = true
+}
+
+// previousForm returns the version of a form an update is diffed against,
+// nil while diffs are disabled or when the form doesn't exist yet
+func (s *Service) previousForm(formID uuid.UUID) (*entity.Form, error) {
+	if !s.diffs {
+		return nil, nil
+	}
+
+	form, err := s.repo.Get(formID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	return form, nil
+}
+
+// formUpdate returns the form.updated payload of form: the form itself
+// without a previous version, or the form with its diff
+func (s *Service) formUpdate(previous, form *entity.Form) (any, error) {
+	if previous == nil {
+		return form, nil
+	}
+
+	update, err := entity.NewFormUpdate(previous, form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff form: %w", err)
+	}
+
+	return update, nil
+}
+
+// changeForm applies change to a form given its current version, then
+// caches the updated form and publishes form.updated
+func (s *Service) changeForm(formID uuid.UUID, change func(form *entity.Form) error) error {
+	// 1. The current form, also the version the update is diffed against
+	current, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeForm(current); err != nil {
+		return err
+	}
+
+	// 2. Critical operation (database)
+	if err = change(current); err != nil {
+		return err
+	}
+
+	// 3. Get updated form
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve updated form: %w", err)
+	}
+
+	var previous *entity.Form
+	if s.diffs {
+		previous = current
+	}
+
+	payload, err := s.formUpdate(previous, form)
+	if err != nil {
+		return err
+	}
+
+	return s.syncForm(form, payload, events.FormUpdated)
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/google/uuid"
+)
+
+// DefaultIntegritySample is the number of forms checked per run
+const DefaultIntegritySample = 100
+
+type (
+	// IntegrityOptions configures the integrity check
+	IntegrityOptions struct {
+		SampleSize int  // Forms checked and most orphaned questions handled per run, 0 uses DefaultIntegritySample
+		Repair     bool // Remove orphaned questions and replace divergent cached forms, otherwise only report them
+	}
+
+	// IntegrityReport summarizes an integrity check
+	IntegrityReport struct {
+		Orphaned  int // Questions whose form is gone
+		Checked   int // Forms whose cached questions were compared with the database
+		Divergent int // Cached forms with another number of questions than their row
+		Repaired  int // Violations fixed
+	}
+
+	// integrityChecker remembers the last form the previous run checked, so
+	// runs cover every form over time
+	integrityChecker struct {
+		repo  IntegrityRepository
+		cache CacheInspector
+		opts  IntegrityOptions
+
+		mu     sync.Mutex // Serializes runs
+		cursor uuid.UUID
+	}
+)
+
+// EnableIntegrityChecks lets CheckIntegrity look for orphaned questions in
+// repo and compare the forms in repo with their copies read through cache
+func (s *Service) EnableIntegrityChecks(repo IntegrityRepository, cache CacheInspector, opts IntegrityOptions) {
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = DefaultIntegritySample
+	}
+
+	s.integrity = &integrityChecker{
+		repo:  repo,
+		cache: cache,
+		opts:  opts,
+	}
+}
+
+// CheckIntegrity looks for questions whose form is gone, left behind when
+// deleting a form didn't cascade, and compares the number of questions of a
+// batch of forms, continuing where the previous run stopped, with their
+// cached copies. Every violation is counted in metrics and published as an
+// integrity.violation event; with Repair, orphaned questions are removed and
+// divergent cached forms replaced by their rows.
+func (s *Service) CheckIntegrity(ctx context.Context) (report IntegrityReport, err error) {
+	defer observe("check_integrity", time.Now(), &err)
+
+	if s.integrity == nil {
+		return report, nil
+	}
+
+	c := s.integrity
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = s.checkOrphanedQuestions(&report); err != nil {
+		return report, err
+	}
+
+	err = s.checkQuestionCounts(ctx, &report)
+
+	return report, err
+}
+
+// checkOrphanedQuestions reports the orphaned questions, one violation per
+// form they belonged to
+func (s *Service) checkOrphanedQuestions(report *IntegrityReport) error {
+	c := s.integrity
+
+	questions, err := c.repo.ListOrphanedQuestions(c.opts.SampleSize)
+	if err != nil {
+		return fmt.Errorf("failed to list orphaned questions: %w", err)
+	}
+
+	report.Orphaned += len(questions)
+
+	var formIDs []uuid.UUID
+	byForm := make(map[uuid.UUID][]uuid.UUID)
+	for _, q := range questions {
+		if _, ok := byForm[q.FormID]; !ok {
+			formIDs = append(formIDs, q.FormID)
+		}
+		byForm[q.FormID] = append(byForm[q.FormID], q.ID)
+	}
+
+	for _, formID := range formIDs {
+		ids := byForm[formID]
+
+		violation := &entity.IntegrityViolation{
+			Kind:        entity.IntegrityOrphanedQuestions,
+			FormID:      formID.String(),
+			QuestionIDs: ids,
+			Stored:      len(ids),
+			DetectedAt:  time.Now(),
+		}
+
+		if c.opts.Repair {
+			deleted, err := c.repo.DeleteOrphanedQuestions(ids)
+			if err != nil {
+				return fmt.Errorf("failed to delete orphaned questions of form %s: %w", formID, err)
+			}
+
+			if deleted > 0 {
+				violation.Repaired = true
+				report.Repaired++
+			}
+		}
+
+		if err := s.reportViolation(violation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkQuestionCounts compares the next batch of forms with their cached
+// copies. Forms that aren't cached can't diverge.
+func (s *Service) checkQuestionCounts(ctx context.Context, report *IntegrityReport) error {
+	c := s.integrity
+
+	forms, err := c.repo.ListFormsAfter(c.cursor, c.opts.SampleSize)
+	if err == nil && len(forms) == 0 && c.cursor != uuid.Nil {
+		// The previous batch ended with the last form
+		forms, err = c.repo.ListFormsAfter(uuid.Nil, c.opts.SampleSize)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list forms: %w", err)
+	}
+
+	// Start over once every form was visited
+	c.cursor = uuid.Nil
+	if len(forms) == c.opts.SampleSize {
+		c.cursor = forms[len(forms)-1].ID
+	}
+
+	for i := range forms {
+		stored := &forms[i]
+		report.Checked++
+
+		data, _, err := c.cache.InspectCash(ctx, stored.ID.String())
+		if err != nil {
+			return fmt.Errorf("failed to read cached form %s: %w", stored.ID, err)
+		}
+		if data == nil {
+			continue
+		}
+
+		var cached entity.Form
+		if err = json.Unmarshal(data, &cached); err != nil {
+			continue // Undecodable entries are left to cache reconciliation
+		}
+
+		if len(cached.Questions) == len(stored.Questions) {
+			continue
+		}
+
+		report.Divergent++
+
+		violation := &entity.IntegrityViolation{
+			Kind:       entity.IntegrityQuestionCount,
+			FormID:     stored.ID.String(),
+			Stored:     len(stored.Questions),
+			Cached:     len(cached.Questions),
+			DetectedAt: time.Now(),
+		}
+
+		if c.opts.Repair {
+			if err = s.casher.AddToCash(ctx, stored.ID.String(), stored); err != nil {
+				return fmt.Errorf("failed to repair cached form %s: %w", stored.ID, err)
+			}
+
+			violation.Repaired = true
+			report.Repaired++
+		}
+
+		if err = s.reportViolation(violation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportViolation counts violation and publishes it
+func (s *Service) reportViolation(violation *entity.IntegrityViolation) error {
+	metrics.IntegrityViolations.WithLabelValues(violation.Kind).Inc()
+
+	if err := s.publish(events.IntegrityIssue, violation); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
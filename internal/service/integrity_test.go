@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIntegrityRepository is an IntegrityRepository over in-memory forms and
+// orphaned questions
+type fakeIntegrityRepository struct {
+	forms   []entity.Form // In ID order
+	orphans []entity.Question
+	deleted []uuid.UUID
+}
+
+func (f *fakeIntegrityRepository) ListOrphanedQuestions(limit int) ([]entity.Question, error) {
+	return f.orphans[:min(limit, len(f.orphans))], nil
+}
+
+func (f *fakeIntegrityRepository) DeleteOrphanedQuestions(ids []uuid.UUID) (int64, error) {
+	f.deleted = append(f.deleted, ids...)
+	f.orphans = slices.DeleteFunc(f.orphans, func(q entity.Question) bool { return slices.Contains(ids, q.ID) })
+	return int64(len(ids)), nil
+}
+
+func (f *fakeIntegrityRepository) ListFormsAfter(after uuid.UUID, limit int) ([]entity.Form, error) {
+	var forms []entity.Form
+	for _, form := range f.forms {
+		if after == uuid.Nil || form.ID.String() > after.String() {
+			forms = append(forms, form)
+		}
+	}
+
+	return forms[:min(limit, len(forms))], nil
+}
+
+// keyedInspector is a CacheInspector over cached entries by key
+type keyedInspector map[string][]byte
+
+func (k keyedInspector) InspectCash(_ context.Context, key string) ([]byte, time.Duration, error) {
+	return k[key], time.Minute, nil
+}
+
+func TestService_CheckIntegrity(t *testing.T) {
+	question := func(id uint, formID uuid.UUID) entity.Question {
+		q := entity.Question{FormID: formID, Content: "q", OrderNumber: id}
+		q.ID = questionID(id)
+		return q
+	}
+
+	newForms := func() (entity.Form, entity.Form) {
+		first, second := uuid.New(), uuid.New()
+		if first.String() > second.String() {
+			first, second = second, first
+		}
+
+		return entity.Form{ID: first, Questions: []entity.Question{question(1, first)}},
+			entity.Form{ID: second, Questions: []entity.Question{question(2, second), question(3, second)}}
+	}
+
+	t.Run("is a no-op when disabled", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		report, err := service.CheckIntegrity(context.Background())
+
+		require.NoError(t, err)
+		assert.Zero(t, report)
+	})
+
+	t.Run("reports orphaned questions and divergent cached forms", func(t *testing.T) {
+		service, mockCasher, _, mockPublisher := setupService()
+		inSync, divergent := newForms()
+		gone := uuid.New()
+
+		repo := &fakeIntegrityRepository{
+			forms:   []entity.Form{inSync, divergent},
+			orphans: []entity.Question{question(4, gone), question(5, gone)},
+		}
+		service.EnableIntegrityChecks(repo, keyedInspector{
+			inSync.ID.String():    encodeForm(t, &inSync),
+			divergent.ID.String(): encodeForm(t, &entity.Form{ID: divergent.ID, Questions: divergent.Questions[:1]}),
+		}, IntegrityOptions{})
+
+		var violations []*entity.IntegrityViolation
+		mockPublisher.On("Publish", mock.Anything, "integrity.violation").Run(func(args mock.Arguments) {
+			violations = append(violations, args.Get(0).(*entity.IntegrityViolation))
+		}).Return(nil)
+
+		report, err := service.CheckIntegrity(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, IntegrityReport{Orphaned: 2, Checked: 2, Divergent: 1}, report)
+		require.Len(t, violations, 2)
+
+		assert.Equal(t, entity.IntegrityOrphanedQuestions, violations[0].Kind)
+		assert.Equal(t, gone.String(), violations[0].FormID)
+		assert.Equal(t, []uuid.UUID{questionID(4), questionID(5)}, violations[0].QuestionIDs)
+		assert.False(t, violations[0].Repaired)
+
+		assert.Equal(t, entity.IntegrityQuestionCount, violations[1].Kind)
+		assert.Equal(t, divergent.ID.String(), violations[1].FormID)
+		assert.Equal(t, 2, violations[1].Stored)
+		assert.Equal(t, 1, violations[1].Cached)
+
+		assert.Empty(t, repo.deleted, "only reported")
+		mockCasher.AssertNotCalled(t, "AddToCash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("repairs violations", func(t *testing.T) {
+		service, mockCasher, _, mockPublisher := setupService()
+		_, divergent := newForms()
+
+		repo := &fakeIntegrityRepository{
+			forms:   []entity.Form{divergent},
+			orphans: []entity.Question{question(4, uuid.New())},
+		}
+		service.EnableIntegrityChecks(repo, keyedInspector{
+			divergent.ID.String(): encodeForm(t, &entity.Form{ID: divergent.ID}),
+		}, IntegrityOptions{Repair: true})
+
+		mockPublisher.On("Publish", mock.Anything, "integrity.violation").Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, divergent.ID.String(), &repo.forms[0]).Return(nil)
+
+		report, err := service.CheckIntegrity(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, report.Repaired)
+		assert.Equal(t, []uuid.UUID{questionID(4)}, repo.deleted)
+		mockCasher.AssertExpectations(t)
+	})
+
+	t.Run("continues with the next batch of forms", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		first, second := newForms()
+
+		repo := &fakeIntegrityRepository{forms: []entity.Form{first, second}}
+		service.EnableIntegrityChecks(repo, keyedInspector{}, IntegrityOptions{SampleSize: 1})
+
+		for _, expected := range []uuid.UUID{first.ID, second.ID, first.ID} {
+			report, err := service.CheckIntegrity(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1, report.Checked)
+			assert.Equal(t, expected, service.integrity.cursor)
+		}
+	})
+}
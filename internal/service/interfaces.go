@@ -2,28 +2,157 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/storage"
 	"github.com/google/uuid"
 )
 
 type (
 	Repository interface {
 		Create(any) error
-		Update(uuid.UUID, string, any) error
-		UpdateMany(uuid.UUID, any) error
+		Update(uuid.UUID, uint, string, any) error
+		UpdateMany(uuid.UUID, uint, any) error
 		Get(uuid.UUID) (*entity.Form, error)
+		GetBySlug(string) (*entity.Form, error)
+		SlugExists(string) (bool, error)
 		DeleteForm(uuid.UUID) error
+		GetTrashedForm(uuid.UUID) (*entity.Form, error)
+		RestoreForm(uuid.UUID) error
+		PurgeForm(uuid.UUID) error
 		DeleteQuestion(uuid.UUID, uint) error
+		GetQuestion(uuid.UUID) (*entity.Question, error)
+		DeleteQuestionByID(uuid.UUID) error
+		UpdateAccess(uuid.UUID, []string, bool) error
+		GetInviteByHash(uuid.UUID, string) (*entity.InviteToken, error)
+		RevokeInvite(uuid.UUID) (*entity.InviteToken, error)
+		ListInvites(uuid.UUID) ([]entity.InviteToken, error)
+		RedeemInvite(uuid.UUID) error
+		ReleaseInvite(uuid.UUID) error
+		CountQuestions(uuid.UUID) (int64, error)
+		CountFormsPerAuthor() ([]int64, error)
+		SaveForm(*entity.Form) (*entity.FormChanges, error)
+		CreateFormVersion(uuid.UUID) (*entity.FormVersion, error)
+		GetFormVersion(uuid.UUID, uint) (*entity.FormVersion, error)
+		GetFormVersionAt(uuid.UUID, time.Time) (*entity.FormVersion, error)
+		GetTemplate(uuid.UUID) (*entity.Template, error)
+		GetSection(uuid.UUID) (*entity.Section, error)
+		UpdateSection(uuid.UUID, any) error
+		DeleteSection(uuid.UUID) error
+		ReorderSections(uuid.UUID, []uuid.UUID) error
+		ReorderQuestions(uuid.UUID, []uuid.UUID) error
+		CreateQuestions(uuid.UUID, []entity.Question) error
+		MoveQuestion(uuid.UUID, *uuid.UUID) error
+		ReplaceBranches(uuid.UUID, []entity.Branch) error
+		SetFormTags(uuid.UUID, []entity.Tag) error
+		ListFormsByTag(tenantID, tag string, filter *entity.Filter, offset, limit int) ([]entity.Form, error)
+		CountFormsByTag(tenantID, tag string, filter *entity.Filter) (int64, error)
+		ListDueSchedules(now time.Time, limit int) ([]entity.Form, error)
+		OpenScheduled(formID uuid.UUID, now time.Time) (bool, error)
+		CloseScheduled(formID uuid.UUID, now time.Time) (bool, error)
+		GetInvite(uuid.UUID) (*entity.InviteToken, error)
+		GetCollaborator(formID uuid.UUID, userID string) (*entity.Collaborator, error)
+		SaveCollaborator(*entity.Collaborator) error
+		DeleteCollaborator(formID uuid.UUID, userID string) (*entity.Collaborator, error)
+		ListCollaborators(uuid.UUID) ([]entity.Collaborator, error)
+		CloneForm(*entity.Form) error
+	}
+
+	ReadModelRepository interface {
+		SavePublicForm(*entity.PublicForm) error
+		GetPublicForm(uuid.UUID) (*entity.PublicForm, error)
+		DeletePublicForm(uuid.UUID) error
+	}
+
+	WebhookRepository interface {
+		GetWebhook(uuid.UUID) (*entity.Webhook, error)
+		SaveWebhook(*entity.Webhook) error
+		DeleteWebhook(uuid.UUID) error
+		ListWebhooks(formID uuid.UUID) ([]entity.Webhook, error)
+	}
+
+	WebhookSender interface {
+		Send(ctx context.Context, url string, body []byte) error
+	}
+
+	UploadRepository interface {
+		Create(any) error
+		GetUpload(uuid.UUID) (*entity.Upload, error)
+		UpdateUpload(uuid.UUID, any) error
+		ListOrphanUploads(before time.Time, limit int) ([]entity.Upload, error)
+		DeleteUpload(uuid.UUID) error
+	}
+
+	ResponseRepository interface {
+		Create(any) error
+		ListResponses(formID uuid.UUID, filter *entity.Filter, offset, limit int) ([]entity.Response, error)
+		CountResponses(uuid.UUID, *entity.Filter) (int64, error)
+	}
+
+	// BulkResponseRepository inserts many responses at once, all or none
+	BulkResponseRepository interface {
+		CreateResponses([]entity.Response) error
+	}
+
+	PrivacyRepository interface {
+		ListRespondentData(respondent string) ([]entity.Response, []entity.InviteToken, error)
+		EraseRespondentData(respondent string) ([]entity.Response, []entity.InviteToken, error)
+	}
+
+	SettingsRepository interface {
+		GetTenantSettings(string) (*entity.TenantSettings, error)
+		SaveTenantSettings(*entity.TenantSettings) error
+	}
+
+	QuotaRepository interface {
+		GetQuota(string) (*entity.AuthorQuota, error)
+		SaveQuotaLimits(*entity.AuthorQuota) error
+		ReserveQuota(author string, forms, questions int, limits entity.QuotaLimits) (bool, error)
+		ReleaseQuota(author string, forms, questions int) error
+	}
+
+	StatsRepository interface {
+		AddQuestionStats(uuid.UUID, []entity.QuestionStat) error
+		ListQuestionStats(uuid.UUID) ([]entity.QuestionStat, error)
+	}
+
+	FormStatsRepository interface {
+		IncrementFormViews(formID uuid.UUID, at time.Time) error
+		IncrementFormSubmissions(formID uuid.UUID, at time.Time) error
+		GetFormStats(uuid.UUID) (*entity.FormStats, error)
+	}
+
+	IntegrityRepository interface {
+		ListOrphanedQuestions(limit int) ([]entity.Question, error)
+		DeleteOrphanedQuestions(ids []uuid.UUID) (int64, error)
+		ListFormsAfter(after uuid.UUID, limit int) ([]entity.Form, error)
+	}
+
+	StatsStore interface {
+		Incr(ctx context.Context, key string, fields map[string]int64) error
+		Get(ctx context.Context, key string) (map[string]int64, error)
+		Drain(ctx context.Context, batch int) (map[string]map[string]int64, error)
+	}
+
+	Storage interface {
+		PresignUpload(ctx context.Context, key string, ttl time.Duration) (string, error)
+		Stat(ctx context.Context, key string) (*storage.ObjectInfo, error)
+		Delete(ctx context.Context, key string) error
+	}
+
+	RateLimiter interface {
+		AllowLimit(ctx context.Context, key string, limit int) (bool, error)
 	}
 
 	Publisher interface {
-		Publish(any, string) error
+		Publish(context.Context, any, string) error
 	}
 
 	Casher interface {
 		AddToCash(ctx context.Context, key string, payload any) error // payload must be pointer
 		GetCashFor(ctx context.Context, key string) ([]byte, error)
 		RemoveFromCash(ctx context.Context, key string) error
+		AddToCashIfAbsent(ctx context.Context, key string, payload any, ttl time.Duration) (bool, error)
 	}
 )
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+)
+
+// PublishLoad publishes a load report of this instance for autoscalers
+func (s *Service) PublishLoad(load *entity.ServiceLoad) error {
+	if err := s.publish(events.ServiceLoad, load); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// SetLogic replaces the branches showing and hiding questions of a form.
+// The branches are validated as a whole against the questions of the form,
+// see entity.Form.ValidateBranches; invalid logic is never stored.
+func (s *Service) SetLogic(formID uuid.UUID, branches []entity.Branch) error {
+	return s.changeForm(formID, func(form *entity.Form) error {
+		for i := range branches {
+			branches[i].ID = 0
+			branches[i].FormID = formID
+		}
+
+		if err := form.ValidateBranches(branches); err != nil {
+			return err
+		}
+
+		if err := s.repo.ReplaceBranches(formID, branches); err != nil {
+			return fmt.Errorf("failed to store form logic in repository: %w", err)
+		}
+
+		return nil
+	})
+}
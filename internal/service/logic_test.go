@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_SetLogic(t *testing.T) {
+	form := &entity.Form{ID: uuid.New()}
+	for i := uint(1); i <= 2; i++ {
+		q := entity.Question{OrderNumber: i}
+		q.ID = questionID(i)
+		form.Questions = append(form.Questions, q)
+	}
+
+	t.Run("stores valid logic and publishes the form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		branches := []entity.Branch{{SourceID: questionID(1), Operator: entity.BranchAnswered, TargetID: questionID(2), Action: entity.BranchShow}}
+
+		mockRepo.On("Get", form.ID).Return(form, nil)
+		mockRepo.On("ReplaceBranches", form.ID, branches).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+		require.NoError(t, service.SetLogic(form.ID, branches))
+		assert.Equal(t, form.ID, branches[0].FormID)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("never stores invalid logic", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		branches := []entity.Branch{
+			{SourceID: questionID(1), Operator: entity.BranchAnswered, TargetID: questionID(2), Action: entity.BranchShow},
+			{SourceID: questionID(2), Operator: entity.BranchAnswered, TargetID: questionID(1), Action: entity.BranchShow},
+		}
+
+		mockRepo.On("Get", form.ID).Return(form, nil)
+
+		err := service.SetLogic(form.ID, branches)
+
+		assert.ErrorIs(t, err, entity.ErrInvalidLogic)
+		mockRepo.AssertNotCalled(t, "ReplaceBranches", mock.Anything, mock.Anything)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+)
+
+// PublishMissingEvent alerts that an event published by this instance never
+// came back on its loopback queue, see package loopback
+func (s *Service) PublishMissingEvent(missing *entity.MissingEvent) error {
+	if err := s.publish(events.EventMissing, missing); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+)
+
+// MaxMediaURLLength caps the length of question media URLs, the size of
+// their column
+const MaxMediaURLLength = 2048
+
+// validateQuestion checks question against its type, see
+// question.Registry.ValidateQuestion, and checks its media
+func (s *Service) validateQuestion(question *entity.Question) error {
+	if err := s.questions.ValidateQuestion(question); err != nil {
+		return err
+	}
+
+	return validateMedia(question)
+}
+
+// validateMedia checks that a question with media has a known media type
+// and an http or https URL respondents' browsers can load it from
+func validateMedia(question *entity.Question) error {
+	if question.MediaURL == "" && question.MediaType == "" {
+		return nil
+	}
+
+	switch question.MediaType {
+	case entity.MediaImage, entity.MediaVideo:
+	default:
+		return fmt.Errorf("%w: question %d: unknown media type %q", entity.ErrInvalidMedia, question.OrderNumber, question.MediaType)
+	}
+
+	if len(question.MediaURL) > MaxMediaURLLength {
+		return fmt.Errorf("%w: question %d: URL longer than %d characters", entity.ErrInvalidMedia, question.OrderNumber, MaxMediaURLLength)
+	}
+
+	u, err := url.Parse(question.MediaURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: question %d: %q is not an http or https URL", entity.ErrInvalidMedia, question.OrderNumber, question.MediaURL)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateMedia(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaURL  string
+		mediaType string
+		valid     bool
+	}{
+		{"no media", "", "", true},
+		{"image", "https://cdn.example.com/q1.png", entity.MediaImage, true},
+		{"video", "http://videos.example.com/watch?v=1", entity.MediaVideo, true},
+		{"unknown type", "https://cdn.example.com/q1.mp3", "audio", false},
+		{"type without url", "", entity.MediaImage, false},
+		{"relative url", "/q1.png", entity.MediaImage, false},
+		{"script url", "javascript:alert(1)", entity.MediaImage, false},
+		{"data url", "data:image/png;base64,AAAA", entity.MediaImage, false},
+		{"no host", "https:///q1.png", entity.MediaImage, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMedia(&entity.Question{MediaURL: tt.mediaURL, MediaType: tt.mediaType})
+
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, entity.ErrInvalidMedia)
+			}
+		})
+	}
+}
+
+func TestService_CreateQuestion_InvalidMedia(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	question := &entity.Question{
+		FormID:    uuid.New(),
+		MediaURL:  "ftp://example.com/q1.png",
+		MediaType: entity.MediaImage,
+	}
+
+	err := service.CreateQuestion(question)
+
+	assert.ErrorIs(t, err, entity.ErrInvalidMedia)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
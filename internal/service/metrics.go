@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/errcatalog"
+	"github.com/Koyo-os/form-service/internal/question"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+)
+
+// Outcomes of operations in the service metrics
+const (
+	OutcomeOK       = "ok"
+	OutcomeRejected = "rejected"
+	OutcomeError    = "error"
+)
+
+// rejectionReasons names the errors refusing an operation in the
+// rejection metrics, more specific errors first. Errors not listed are
+// named by their catalog code.
+var rejectionReasons = []struct {
+	err    error
+	reason string
+}{
+	{entity.ErrInvalidRules, "rules"},
+	{entity.ErrInvalidNumbering, "numbering"},
+	{entity.ErrInvalidFormat, "format"},
+	{entity.ErrInvalidMedia, "media"},
+	{entity.ErrInvalidLocale, "locale"},
+	{entity.ErrInvalidSettings, "settings"},
+	{entity.ErrInvalidTheme, "theme"},
+	{entity.ErrInvalidLogic, "logic"},
+	{entity.ErrInvalidSchedule, "schedule"},
+	{entity.ErrInvalidTag, "tag"},
+	{entity.ErrInvalidExport, "export"},
+	{entity.ErrUnknownExportFormat, "export_format"},
+	{entity.ErrInvalidStatus, "status"},
+	{entity.ErrInvalidTransition, "transition"},
+	{entity.ErrInvalidSlug, "slug"},
+	{ErrSlugTaken, "slug_taken"},
+	{question.ErrUnknownType, "question_type"},
+	{question.ErrInvalidOptions, "question_options"},
+	{question.ErrInvalidDefault, "default_value"},
+	{ErrInvalidOrder, "order"},
+	{ErrNoQuestions, "no_questions"},
+	{ErrSectionNotInForm, "section"},
+	{ErrNotEditor, "not_editor"},
+	{ErrNotAuthor, "not_author"},
+}
+
+// formsPerAuthorBuckets are the upper bounds of metrics.FormsPerAuthor
+var formsPerAuthorBuckets = []int64{1, 5, 10, 50, 100, 500}
+
+// observe records an operation that started at start and failed with *err,
+// nil for success, in the service metrics. It is deferred by operations
+// with a named error result:
+//
+//	defer observe("create_form", time.Now(), &err)
+func observe(operation string, start time.Time, err *error) {
+	outcome := OutcomeOK
+
+	if *err != nil {
+		outcome = OutcomeError
+
+		if reason := rejectionReason(*err); reason != "" {
+			outcome = OutcomeRejected
+			metrics.ServiceRejections.WithLabelValues(operation, reason).Inc()
+		}
+	}
+
+	metrics.ServiceOperationDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+}
+
+// rejectionReason names why err refused an operation, empty for failures
+func rejectionReason(err error) string {
+	var conflict *entity.VersionConflictError
+	if errors.As(err, &conflict) {
+		return "version_conflict"
+	}
+
+	for _, known := range rejectionReasons {
+		if errors.Is(err, known.err) {
+			return known.reason
+		}
+	}
+
+	if code := ErrorCode(err); code != errcatalog.INTERNAL {
+		return string(code)
+	}
+
+	return ""
+}
+
+// SampleFormsPerAuthor sets metrics.FormsPerAuthor from the live forms of
+// every author
+func (s *Service) SampleFormsPerAuthor() error {
+	counts, err := s.repo.CountFormsPerAuthor()
+	if err != nil {
+		return err
+	}
+
+	for bound, authors := range bucketFormCounts(counts) {
+		metrics.FormsPerAuthor.WithLabelValues(bound).Set(float64(authors))
+	}
+
+	return nil
+}
+
+// bucketFormCounts counts the authors with at most each bound of
+// formsPerAuthorBuckets forms, given the form count of each author, "+Inf"
+// counting every author
+func bucketFormCounts(counts []int64) map[string]int {
+	buckets := make(map[string]int, len(formsPerAuthorBuckets)+1)
+
+	for _, bound := range formsPerAuthorBuckets {
+		label := strconv.FormatInt(bound, 10)
+		buckets[label] = 0
+
+		for _, count := range counts {
+			if count <= bound {
+				buckets[label]++
+			}
+		}
+	}
+
+	buckets["+Inf"] = len(counts)
+
+	return buckets
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/question"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectionReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: fmt.Errorf("invalid question: %w", entity.ErrInvalidRules), want: "rules"},
+		{err: fmt.Errorf("question 2: %w", question.ErrInvalidDefault), want: "default_value"},
+		{err: &QuotaExceededError{Author: "author", Resource: entity.QuotaForms}, want: "quota_exceeded"},
+		{err: &entity.VersionConflictError{Expected: 1, Actual: 2}, want: "version_conflict"},
+		{err: ErrInvalidResponse, want: "invalid_answers"},
+		{err: errors.New("database error"), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.want, rejectionReason(tt.err))
+		})
+	}
+}
+
+func TestBucketFormCounts(t *testing.T) {
+	buckets := bucketFormCounts([]int64{1, 3, 7, 700})
+
+	assert.Equal(t, 1, buckets["1"])
+	assert.Equal(t, 2, buckets["5"])
+	assert.Equal(t, 3, buckets["10"])
+	assert.Equal(t, 3, buckets["500"])
+	assert.Equal(t, 4, buckets["+Inf"])
+}
+
+func TestService_SampleFormsPerAuthor(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+	mockRepo.On("CountFormsPerAuthor").Return(nil, errors.New("database error"))
+
+	assert.Error(t, service.SampleFormsPerAuthor())
+}
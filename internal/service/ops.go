@@ -0,0 +1,23 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+)
+
+// PublishOpsEvent publishes a notable operational occurrence as eventType,
+// one of the ops.* events, for the alerting pipeline
+func (s *Service) PublishOpsEvent(eventType events.Type, event *entity.OpsEvent) error {
+	if !strings.HasPrefix(eventType.String(), "ops.") {
+		return fmt.Errorf("%w: %s is not an ops event", events.ErrPayloadMismatch, eventType)
+	}
+
+	if err := s.publish(eventType, event); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
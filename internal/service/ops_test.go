@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_PublishOpsEvent(t *testing.T) {
+	t.Run("publishes ops events", func(t *testing.T) {
+		service, _, _, mockPublisher := setupService()
+
+		event := &entity.OpsEvent{
+			Instance:   "form-service-1",
+			Severity:   entity.OpsWarning,
+			Summary:    "queue paused for maintenance",
+			Details:    map[string]string{"queue": "request", "enabled": "true"},
+			OccurredAt: time.Now(),
+		}
+		mockPublisher.On("Publish", event, events.OpsMaintenance.String()).Return(nil)
+
+		assert.NoError(t, service.PublishOpsEvent(events.OpsMaintenance, event))
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects other event types", func(t *testing.T) {
+		service, _, _, mockPublisher := setupService()
+
+		err := service.PublishOpsEvent(events.FormCreated, &entity.OpsEvent{})
+
+		assert.ErrorIs(t, err, events.ErrPayloadMismatch)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPrivacyRequestsDisabled is returned for privacy requests without a
+	// repository or signing key
+	ErrPrivacyRequestsDisabled = errors.New("privacy requests are not configured")
+
+	// ErrNoRespondent is returned for privacy requests without a respondent
+	ErrNoRespondent = errors.New("respondent can not be empty")
+)
+
+// EnablePrivacyRequests lets ExportRespondent and EraseRespondent read and
+// remove respondent data in repo, signing their reports with key
+func (s *Service) EnablePrivacyRequests(repo PrivacyRepository, key []byte) {
+	s.privacy = repo
+	s.privacyKey = key
+}
+
+// ExportRespondent collects everything stored about a respondent, given by
+// respondent ID or email, across forms: their responses and the invites
+// issued for them. Returns the report carrying the data, signed once published.
+func (s *Service) ExportRespondent(respondent string) (*entity.PrivacyReport, error) {
+	respondent, err := s.checkPrivacyRequest(respondent)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, invites, err := s.privacy.ListRespondentData(respondent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list respondent data: %w", err)
+	}
+
+	report := newPrivacyReport(entity.PrivacyExport, respondent, responses, invites)
+	report.Data = &entity.RespondentData{
+		Responses: responses,
+		Invites:   make([]entity.OutputInvite, 0, len(invites)),
+	}
+	for i := range invites {
+		report.Data.Invites = append(report.Data.Invites, invites[i].ToOutput())
+	}
+
+	return report, nil
+}
+
+// EraseRespondent permanently removes everything stored about a respondent
+// across forms, see ExportRespondent, along with the draft progress markers
+// cached for those forms. Returns the report of what was removed, signed once
+// published.
+func (s *Service) EraseRespondent(respondent string) (*entity.PrivacyReport, error) {
+	respondent, err := s.checkPrivacyRequest(respondent)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, invites, err := s.privacy.EraseRespondentData(respondent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase respondent data: %w", err)
+	}
+
+	report := newPrivacyReport(entity.PrivacyErase, respondent, responses, invites)
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	for _, formID := range report.Forms {
+		id, _ := uuid.Parse(formID)
+		if err = s.cacheRetry.do(ctx, func() error {
+			return s.casher.RemoveFromCash(ctx, progressKey(id, respondent))
+		}); err != nil {
+			return nil, fmt.Errorf("failed to remove draft progress of form %s: %w", formID, err)
+		}
+	}
+
+	return report, nil
+}
+
+// PublishPrivacyReport signs the report of a completed privacy request and
+// sends it back to the requester
+func (s *Service) PublishPrivacyReport(report *entity.PrivacyReport) error {
+	if len(s.privacyKey) == 0 {
+		return ErrPrivacyRequestsDisabled
+	}
+
+	if err := report.Sign(s.privacyKey); err != nil {
+		return fmt.Errorf("failed to sign privacy report: %w", err)
+	}
+
+	return s.publish(events.PrivacyReport, report)
+}
+
+// checkPrivacyRequest checks that privacy requests are enabled and the actor
+// is an admin, as they span the responses of every form, and returns the
+// trimmed respondent
+func (s *Service) checkPrivacyRequest(respondent string) (string, error) {
+	if s.privacy == nil || len(s.privacyKey) == 0 {
+		return "", ErrPrivacyRequestsDisabled
+	}
+
+	if err := s.authorizeAdmin(); err != nil {
+		return "", err
+	}
+
+	respondent = strings.TrimSpace(respondent)
+	if respondent == "" {
+		return "", ErrNoRespondent
+	}
+
+	return respondent, nil
+}
+
+// newPrivacyReport builds the unsigned report of a privacy request that
+// found the given responses and invites
+func newPrivacyReport(operation, respondent string, responses []entity.Response, invites []entity.InviteToken) *entity.PrivacyReport {
+	forms := make([]string, 0, len(responses)+len(invites))
+	for i := range responses {
+		forms = append(forms, responses[i].FormID.String())
+	}
+	for i := range invites {
+		forms = append(forms, invites[i].FormID.String())
+	}
+	slices.Sort(forms)
+
+	return &entity.PrivacyReport{
+		Operation:   operation,
+		Respondent:  respondent,
+		Forms:       slices.Compact(forms),
+		Responses:   len(responses),
+		Invites:     len(invites),
+		CompletedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePrivacy keeps the data of respondents in memory
+type fakePrivacy struct {
+	responses []entity.Response
+	invites   []entity.InviteToken
+}
+
+func (f *fakePrivacy) ListRespondentData(string) ([]entity.Response, []entity.InviteToken, error) {
+	return f.responses, f.invites, nil
+}
+
+func (f *fakePrivacy) EraseRespondentData(respondent string) ([]entity.Response, []entity.InviteToken, error) {
+	responses, invites := f.responses, f.invites
+	f.responses, f.invites = nil, nil
+
+	return responses, invites, nil
+}
+
+func TestService_ExportRespondent(t *testing.T) {
+	key := []byte("secret")
+	formID := uuid.New()
+
+	t.Run("disabled", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		_, err := service.ExportRespondent("resp-1")
+
+		assert.ErrorIs(t, err, ErrPrivacyRequestsDisabled)
+	})
+
+	t.Run("signed report", func(t *testing.T) {
+		service, _, _, mockPublisher := setupService()
+		service.EnablePrivacyRequests(&fakePrivacy{
+			responses: []entity.Response{{ID: uuid.New(), FormID: formID, RespondentID: "resp-1"}},
+			invites:   []entity.InviteToken{{ID: uuid.New(), FormID: formID, Email: "resp@example.com"}},
+		}, key)
+		mockPublisher.On("Publish", mock.AnythingOfType("*entity.PrivacyReport"), events.PrivacyReport.String()).Return(nil)
+
+		report, err := service.ExportRespondent(" resp-1 ")
+		require.NoError(t, err)
+		assert.Equal(t, entity.PrivacyExport, report.Operation)
+		assert.Equal(t, "resp-1", report.Respondent)
+		assert.Equal(t, []string{formID.String()}, report.Forms)
+		require.NotNil(t, report.Data)
+		assert.Len(t, report.Data.Invites, 1)
+
+		report.RequestID = "req-1"
+		require.NoError(t, service.PublishPrivacyReport(report))
+		assert.True(t, report.Verify(key))
+
+		report.Responses = 0
+		assert.False(t, report.Verify(key), "changed reports don't verify")
+	})
+
+	t.Run("empty respondent", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		service.EnablePrivacyRequests(&fakePrivacy{}, key)
+
+		_, err := service.ExportRespondent(" ")
+
+		assert.ErrorIs(t, err, ErrNoRespondent)
+	})
+
+	t.Run("admins only", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		service.EnablePrivacyRequests(&fakePrivacy{
+			responses: []entity.Response{{ID: uuid.New(), FormID: formID, RespondentID: "resp-1"}},
+		}, key)
+		service.SetAdmins([]string{"root"})
+
+		_, err := service.As("alice").ExportRespondent("resp-1")
+		assert.ErrorIs(t, err, ErrNotAdmin)
+
+		_, err = service.As("alice").EraseRespondent("resp-1")
+		assert.ErrorIs(t, err, ErrNotAdmin)
+
+		report, err := service.As("root").ExportRespondent("resp-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Responses)
+	})
+}
+
+func TestService_EraseRespondent(t *testing.T) {
+	service, mockCasher, _, _ := setupService()
+
+	formID := uuid.New()
+	service.EnablePrivacyRequests(&fakePrivacy{
+		responses: []entity.Response{
+			{ID: uuid.New(), FormID: formID, RespondentID: "resp-1"},
+			{ID: uuid.New(), FormID: formID, RespondentID: "resp-1"},
+		},
+	}, []byte("secret"))
+	mockCasher.On("RemoveFromCash", mock.Anything, progressKey(formID, "resp-1")).Return(nil)
+
+	report, err := service.EraseRespondent("resp-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.PrivacyErase, report.Operation)
+	assert.Equal(t, 2, report.Responses)
+	assert.Nil(t, report.Data, "erasures carry no data")
+	mockCasher.AssertNumberOfCalls(t, "RemoveFromCash", 1)
+}
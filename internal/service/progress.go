@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// ProgressMarkerTTL bounds how long a started response is remembered. A draft
+// saved after that emits form.response.started again.
+const ProgressMarkerTTL = 24 * time.Hour
+
+// progressKey is the cache key marking a respondent as started
+func progressKey(formID uuid.UUID, respondentID string) string {
+	return fmt.Sprintf("%s:progress:%s", formID, respondentID)
+}
+
+// TrackDraftProgress publishes progress events for a saved draft. The first
+// draft of a respondent emits form.response.started, every draft emits
+// form.response.progress. If the started marker can't be read from the cache
+// the started event is published anyway, so consumers must tolerate duplicates.
+func (s *Service) TrackDraftProgress(formID uuid.UUID, respondentID string, questionIndex uint) error {
+	if respondentID == "" {
+		return errors.New("respondent id cannot be empty")
+	}
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if !form.AcceptsResponses() {
+		return fmt.Errorf("%w: %s", ErrFormClosed, formID)
+	}
+
+	progress := &entity.ResponseProgress{
+		FormID:        formID.String(),
+		RespondentID:  respondentID,
+		QuestionIndex: questionIndex,
+		QuestionCount: len(form.Questions),
+		At:            time.Now(),
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	started := true
+	if err = s.cacheRetry.do(ctx, func() error {
+		started, err = s.casher.AddToCashIfAbsent(ctx, progressKey(formID, respondentID), progress.At.Unix(), ProgressMarkerTTL)
+		return err
+	}); err != nil {
+		started = true
+	}
+
+	if started {
+		if err = s.publish(events.ResponseStarted, progress); err != nil {
+			return fmt.Errorf("publish error: %w", err)
+		}
+	}
+
+	if err = s.publish(events.ResponseProgress, progress); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// DefaultQueryCacheTTL is used when enabling the query cache without a TTL
+const DefaultQueryCacheTTL = 30 * time.Second
+
+// queryScopeForms is the scope of the form listings, invalidated by any
+// change to a form since it may move the form between listings
+const queryScopeForms = "forms"
+
+type (
+	// QueryCache caches the results of list queries, see casher.GetQuery.
+	// Results are grouped in scopes, each invalidated at once.
+	QueryCache interface {
+		GetQuery(ctx context.Context, key string) ([]byte, error)
+		SetQuery(ctx context.Context, key string, data []byte, ttl time.Duration) error
+		QueryGeneration(ctx context.Context, scope string) (int64, error)
+		InvalidateQueries(ctx context.Context, scope string) error
+	}
+
+	// queryCache is the query cache of the service and the TTL of results
+	queryCache struct {
+		store QueryCache
+		ttl   time.Duration
+	}
+)
+
+// EnableQueryCache caches the results of form and response listings in
+// store for ttl, so dashboards refreshing them don't reach the database
+// every time. InvalidateQueries must receive the domain events to drop
+// results once the forms or responses they list change.
+func (s *Service) EnableQueryCache(store QueryCache, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultQueryCacheTTL
+	}
+
+	s.queries = &queryCache{store: store, ttl: ttl}
+}
+
+// responsesScope is the scope of the response listings of a form
+func responsesScope(formID uuid.UUID) string {
+	return "responses:" + formID.String()
+}
+
+// cachedQuery returns the cached result of a query of scope identified by
+// params, the normalized query parameters, or loads and caches it. The
+// cache failing falls back to the database.
+func cachedQuery[T any](s *Service, scope, params string, load func() (*T, error)) (*T, error) {
+	if s.queries == nil {
+		return load()
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	gen, err := s.queries.store.QueryGeneration(ctx, scope)
+	if err != nil {
+		return load()
+	}
+	key := fmt.Sprintf("%s:%d:%s", scope, gen, params)
+
+	if data, err := s.queries.store.GetQuery(ctx, key); err == nil && data != nil {
+		result := new(T)
+		if err = json.Unmarshal(data, result); err == nil {
+			return result, nil
+		}
+	}
+
+	result, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.queries.store.SetQuery(ctx, key, data, s.queries.ttl)
+	}
+
+	return result, nil
+}
+
+// InvalidateQueries drops the cached query results the domain event may
+// have made stale. It has the signature of an event bus handler and ignores
+// events that change nothing listed.
+func (s *Service) InvalidateQueries(payload any, eventType string) error {
+	if s.queries == nil {
+		return nil
+	}
+
+	var scopes []string
+
+	switch events.Type(eventType) {
+	case events.FormCreated, events.FormUpdated, events.FormDeleted, events.FormRestored, events.FormPurged,
+		events.FormArchived, events.FormUnarchived, events.FormOpened, events.FormClosed, events.FormPublished:
+		scopes = append(scopes, queryScopeForms)
+	case events.ResponseCreated:
+		if response, ok := payload.(*entity.Response); ok {
+			scopes = append(scopes, responsesScope(response.FormID))
+		}
+	case events.PrivacyReport:
+		if report, ok := payload.(*entity.PrivacyReport); ok && report.Operation == entity.PrivacyErase {
+			for _, formID := range report.Forms {
+				if id, err := uuid.Parse(formID); err == nil {
+					scopes = append(scopes, responsesScope(id))
+				}
+			}
+		}
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	for _, scope := range scopes {
+		if err := s.queries.store.InvalidateQueries(ctx, scope); err != nil {
+			return fmt.Errorf("failed to invalidate %s queries: %w", scope, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryQueries keeps query results in memory, ignoring their TTL
+type memoryQueries struct {
+	mu          sync.Mutex
+	results     map[string][]byte
+	generations map[string]int64
+}
+
+func newMemoryQueries() *memoryQueries {
+	return &memoryQueries{results: map[string][]byte{}, generations: map[string]int64{}}
+}
+
+func (m *memoryQueries) GetQuery(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.results[key], nil
+}
+
+func (m *memoryQueries) SetQuery(_ context.Context, key string, data []byte, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[key] = data
+	return nil
+}
+
+func (m *memoryQueries) QueryGeneration(_ context.Context, scope string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.generations[scope], nil
+}
+
+func (m *memoryQueries) InvalidateQueries(_ context.Context, scope string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generations[scope]++
+	return nil
+}
+
+func TestService_QueryCache(t *testing.T) {
+	service, _, _, _ := setupService()
+	responses := new(MockResponseRepository)
+	service.EnableResponses(responses)
+	service.EnableQueryCache(newMemoryQueries(), time.Minute)
+
+	formID := uuid.New()
+	responses.On("CountResponses", formID, (*entity.Filter)(nil)).Return(int64(1), nil)
+	responses.On("ListResponses", formID, (*entity.Filter)(nil), 0, DefaultResponsePageSize).
+		Return([]entity.Response{{ID: uuid.New(), FormID: formID}}, nil)
+
+	for range 2 {
+		list, err := service.ListResponses(formID, nil, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), list.Total)
+		assert.Len(t, list.Responses, 1)
+	}
+	responses.AssertNumberOfCalls(t, "ListResponses", 1)
+
+	require.NoError(t, service.InvalidateQueries(&entity.Response{FormID: uuid.New()}, events.ResponseCreated.String()))
+	_, err := service.ListResponses(formID, nil, 0, 0)
+	require.NoError(t, err)
+	responses.AssertNumberOfCalls(t, "ListResponses", 1) // Responses of other forms don't invalidate
+
+	require.NoError(t, service.InvalidateQueries(&entity.Response{FormID: formID}, events.ResponseCreated.String()))
+	_, err = service.ListResponses(formID, nil, 0, 0)
+	require.NoError(t, err)
+	responses.AssertNumberOfCalls(t, "ListResponses", 2)
+
+	require.NoError(t, service.InvalidateQueries(&entity.FormList{}, events.FormList.String()), "replies are ignored")
+}
+
+func TestService_QueryCache_FormsByTag(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+	service.EnableQueryCache(newMemoryQueries(), time.Minute)
+
+	mockRepo.On("CountFormsByTag", "acme", "hr", (*entity.Filter)(nil)).Return(int64(1), nil)
+	mockRepo.On("ListFormsByTag", "acme", "hr", (*entity.Filter)(nil), 0, DefaultFormPageSize).Return([]entity.Form{{ID: uuid.New()}}, nil)
+
+	for _, tag := range []string{"hr", " HR "} {
+		list, err := service.ListFormsByTag("acme", tag, nil, 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, list.Forms, 1)
+	}
+	mockRepo.AssertNumberOfCalls(t, "ListFormsByTag", 1)
+
+	require.NoError(t, service.InvalidateQueries(&entity.Form{}, events.FormUpdated.String()))
+	_, err := service.ListFormsByTag("acme", "hr", nil, 0, 0)
+	require.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "ListFormsByTag", 2)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// MaxImportedQuestions caps the questions imported into a form at once
+const MaxImportedQuestions = 500
+
+// ErrNoQuestions is returned for imports without questions
+var ErrNoQuestions = errors.New("no questions to import")
+
+// ImportQuestions appends questions to an existing form in one batch, then
+// publishes the whole form. Questions without an order number are numbered
+// after the last question of the form, in the order given. Either every
+// question is imported or none.
+func (s *Service) ImportQuestions(formID uuid.UUID, questions []entity.Question) (err error) {
+	defer observe("import_questions", time.Now(), &err)
+
+	if len(questions) == 0 {
+		return ErrNoQuestions
+	}
+
+	if len(questions) > MaxImportedQuestions {
+		return fmt.Errorf("%w: at most %d questions per import", ErrLimitExceeded, MaxImportedQuestions)
+	}
+
+	for i := range questions {
+		if err := s.validateQuestion(&questions[i]); err != nil {
+			return fmt.Errorf("invalid question %d: %w", i+1, err)
+		}
+
+		if err := sanitizeQuestion(&questions[i]); err != nil {
+			return fmt.Errorf("invalid question %d: %w", i+1, err)
+		}
+	}
+
+	return s.changeForm(formID, func(form *entity.Form) error {
+		if err := s.checkQuestionLimit(form.TenantID, len(form.Questions)+len(questions)); err != nil {
+			return err
+		}
+
+		sections := make(map[uuid.UUID]bool, len(form.Sections))
+		for _, section := range form.Sections {
+			sections[section.ID] = true
+		}
+
+		var last uint
+		for _, q := range form.Questions {
+			last = max(last, q.OrderNumber)
+		}
+
+		for i := range questions {
+			q := &questions[i]
+			q.ID = uuid.Nil
+			q.FormID = formID
+
+			if q.SectionID != nil && !sections[*q.SectionID] {
+				return fmt.Errorf("%w: section %s", ErrSectionNotInForm, q.SectionID)
+			}
+		}
+		numberQuestions(questions, last)
+
+		if err := s.reserveQuota(form.Author, 0, len(questions), len(form.Questions)+len(questions)); err != nil {
+			return err
+		}
+
+		if err := s.repo.CreateQuestions(formID, questions); err != nil {
+			s.releaseQuota(form.Author, 0, len(questions))
+			return fmt.Errorf("failed to import questions in repository: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// numberQuestions numbers the questions without an order number after the
+// highest of last and the given order numbers, in the order of questions
+func numberQuestions(questions []entity.Question, last uint) {
+	for _, q := range questions {
+		last = max(last, q.OrderNumber)
+	}
+
+	for i := range questions {
+		if questions[i].OrderNumber == 0 {
+			last++
+			questions[i].OrderNumber = last
+		}
+	}
+}
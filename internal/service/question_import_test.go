@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/question"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ImportQuestions(t *testing.T) {
+	section := uuid.New()
+	form := &entity.Form{
+		ID:        uuid.New(),
+		Author:    "author",
+		Questions: []entity.Question{{ID: questionID(1), OrderNumber: 3}},
+		Sections:  []entity.Section{{ID: section}},
+	}
+
+	t.Run("numbers the questions after the last one and publishes the form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		questions := []entity.Question{{Content: "Name?"}, {Content: "Team?", OrderNumber: 7}, {Content: "Role?", SectionID: &section}}
+
+		mockRepo.On("Get", form.ID).Return(form, nil)
+		mockRepo.On("CreateQuestions", form.ID, mock.Anything).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+		require.NoError(t, service.ImportQuestions(form.ID, questions))
+
+		for i, number := range []uint{8, 7, 9} {
+			assert.Equal(t, number, questions[i].OrderNumber)
+			assert.Equal(t, form.ID, questions[i].FormID)
+		}
+		mockRepo.AssertNumberOfCalls(t, "CreateQuestions", 1)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects imports it can't store whole", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockRepo.On("Get", form.ID).Return(form, nil)
+
+		assert.ErrorIs(t, service.ImportQuestions(form.ID, nil), ErrNoQuestions)
+		assert.ErrorIs(t, service.ImportQuestions(form.ID, make([]entity.Question, MaxImportedQuestions+1)), ErrLimitExceeded)
+		assert.ErrorIs(t, service.ImportQuestions(form.ID, []entity.Question{{Content: "Why?", Type: "unknown"}}), question.ErrUnknownType)
+
+		foreign := uuid.New()
+		assert.ErrorIs(t, service.ImportQuestions(form.ID, []entity.Question{{Content: "Why?", SectionID: &foreign}}), ErrSectionNotInForm)
+
+		mockRepo.AssertNotCalled(t, "CreateQuestions", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_CreateForm_NumbersQuestions(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	form := &entity.Form{
+		ID:        uuid.New(),
+		Questions: []entity.Question{{Content: "Name?", OrderNumber: 2}, {Content: "Team?"}, {Content: "Role?"}},
+	}
+
+	mockRepo.On("Create", form).Return(nil)
+	mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+	mockPublisher.On("Publish", form, "form.created").Return(nil)
+
+	require.NoError(t, service.CreateForm(form))
+
+	for i, number := range []uint{2, 3, 4} {
+		assert.Equal(t, number, form.Questions[i].OrderNumber)
+	}
+}
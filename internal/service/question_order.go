@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidOrder is returned for question orders that don't list every
+// question of the form exactly once
+var ErrInvalidOrder = errors.New("invalid question order")
+
+// ReorderQuestions renumbers the questions of a form in the given order, from
+// 1, in one transaction, then publishes the whole form. The order must list
+// every question of the form exactly once, so no numbers are skipped or
+// shared.
+func (s *Service) ReorderQuestions(formID uuid.UUID, order []uuid.UUID) (err error) {
+	defer observe("reorder_questions", time.Now(), &err)
+
+	return s.changeForm(formID, func(form *entity.Form) error {
+		if len(order) != len(form.Questions) {
+			return fmt.Errorf("%w: lists %d questions, form has %d", ErrInvalidOrder, len(order), len(form.Questions))
+		}
+
+		questions := make(map[uuid.UUID]bool, len(form.Questions))
+		for _, q := range form.Questions {
+			questions[q.ID] = true
+		}
+
+		seen := make(map[uuid.UUID]bool, len(order))
+		for _, id := range order {
+			if !questions[id] {
+				return fmt.Errorf("%w: question %s isn't in the form", ErrInvalidOrder, id)
+			}
+			if seen[id] {
+				return fmt.Errorf("%w: question %s listed twice", ErrInvalidOrder, id)
+			}
+			seen[id] = true
+		}
+
+		if err := s.repo.ReorderQuestions(formID, order); err != nil {
+			return fmt.Errorf("failed to reorder questions in repository: %w", err)
+		}
+
+		return nil
+	})
+}
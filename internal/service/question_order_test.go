@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ReorderQuestions(t *testing.T) {
+	form := &entity.Form{ID: uuid.New(), Questions: []entity.Question{
+		{ID: questionID(1), OrderNumber: 1},
+		{ID: questionID(2), OrderNumber: 2},
+		{ID: questionID(3), OrderNumber: 4},
+	}}
+
+	t.Run("renumbers every question and publishes the form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		order := []uuid.UUID{questionID(3), questionID(1), questionID(2)}
+
+		mockRepo.On("Get", form.ID).Return(form, nil)
+		mockRepo.On("ReorderQuestions", form.ID, order).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+		require.NoError(t, service.ReorderQuestions(form.ID, order))
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects incomplete orders", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockRepo.On("Get", form.ID).Return(form, nil)
+
+		for _, order := range [][]uuid.UUID{
+			{questionID(1), questionID(2)},
+			{questionID(1), questionID(1), questionID(2)},
+			{questionID(1), questionID(2), questionID(9)},
+		} {
+			assert.ErrorIs(t, service.ReorderQuestions(form.ID, order), ErrInvalidOrder, "%v", order)
+		}
+		mockRepo.AssertNotCalled(t, "ReorderQuestions", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"gorm.io/gorm"
+)
+
+type (
+	// QuotaExceededError is returned when a write would exceed an author quota
+	QuotaExceededError struct {
+		Author   string
+		Resource string // entity.QuotaForms, entity.QuotaQuestions or entity.QuotaQuestionsPerForm
+		Limit    int
+		Used     int // Usage before the rejected write, the questions of the form for entity.QuotaQuestionsPerForm
+	}
+
+	// quotas enforces per-author limits over counters kept in the repository
+	quotas struct {
+		repo     QuotaRepository
+		defaults entity.QuotaLimits
+	}
+)
+
+func (e *QuotaExceededError) Error() string {
+	if e.Resource == entity.QuotaQuestionsPerForm {
+		return fmt.Sprintf("quota exceeded: forms of author %s may hold at most %d questions, form holds %d", e.Author, e.Limit, e.Used)
+	}
+
+	return fmt.Sprintf("quota exceeded: author %s may own at most %d %s, owns %d", e.Author, e.Limit, e.Resource, e.Used)
+}
+
+// EnableQuotas limits the forms and questions every author may own, and the
+// questions of each of their forms, to defaults, unless the author has
+// overrides. Without it authors are unlimited.
+// Counters only track forms written while quotas are enabled.
+func (s *Service) EnableQuotas(repo QuotaRepository, defaults entity.QuotaLimits) {
+	s.quotas = &quotas{
+		repo:     repo,
+		defaults: defaults,
+	}
+}
+
+// GetQuota returns the usage and effective limits of an author
+func (s *Service) GetQuota(author string) (*entity.OutputQuota, error) {
+	if s.quotas == nil {
+		return nil, errors.New("quotas are not enabled")
+	}
+
+	quota, err := s.quotas.get(author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve quota: %w", err)
+	}
+
+	return &entity.OutputQuota{
+		Author:    author,
+		Limits:    quota.Limits(s.quotas.defaults),
+		Forms:     quota.FormCount,
+		Questions: quota.QuestionCount,
+	}, nil
+}
+
+// PublishQuota sends a quota back to the requester
+func (s *Service) PublishQuota(quota *entity.OutputQuota) error {
+	return s.publish(events.AuthorQuota, quota)
+}
+
+// UpdateQuota replaces the limit overrides of an author. Nil limits fall back
+// to the defaults. Lowering a limit below the current usage only blocks
+// further writes.
+func (s *Service) UpdateQuota(overrides *entity.AuthorQuota) error {
+	if s.quotas == nil {
+		return errors.New("quotas are not enabled")
+	}
+
+	if overrides.Author == "" {
+		return errors.New("author cannot be empty")
+	}
+
+	if err := s.authorizeAdmin(); err != nil {
+		return err
+	}
+
+	if err := s.quotas.repo.SaveQuotaLimits(overrides); err != nil {
+		return fmt.Errorf("failed to save quota limits: %w", err)
+	}
+
+	quota, err := s.GetQuota(overrides.Author)
+	if err != nil {
+		return err
+	}
+
+	if err := s.publish(events.AuthorQuotaUpdated, quota); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
+
+// reserveQuota counts forms and questions against the quota of author,
+// failing with a QuotaExceededError when that would exceed a limit.
+// formQuestions are the questions of the written form once the questions
+// are added, checked against the per-form limit; 0 leaves them unchecked.
+func (s *Service) reserveQuota(author string, forms, questions, formQuestions int) error {
+	if s.quotas == nil || (forms == 0 && questions == 0) {
+		return nil
+	}
+
+	quota, err := s.quotas.get(author)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve quota: %w", err)
+	}
+
+	limits := quota.Limits(s.quotas.defaults)
+
+	if limits.MaxPerForm > 0 && questions > 0 && formQuestions > limits.MaxPerForm {
+		return &QuotaExceededError{Author: author, Resource: entity.QuotaQuestionsPerForm, Limit: limits.MaxPerForm, Used: formQuestions - questions}
+	}
+
+	ok, err := s.quotas.repo.ReserveQuota(author, forms, questions, limits)
+	if err != nil {
+		return fmt.Errorf("failed to reserve quota: %w", err)
+	}
+
+	if ok {
+		return nil
+	}
+
+	// Re-read the counters, they may have moved since the first read
+	if quota, err = s.quotas.get(author); err != nil {
+		return fmt.Errorf("failed to retrieve quota: %w", err)
+	}
+
+	if limits.MaxForms > 0 && forms > 0 && quota.FormCount+forms > limits.MaxForms {
+		return &QuotaExceededError{Author: author, Resource: entity.QuotaForms, Limit: limits.MaxForms, Used: quota.FormCount}
+	}
+
+	return &QuotaExceededError{Author: author, Resource: entity.QuotaQuestions, Limit: limits.MaxQuestions, Used: quota.QuestionCount}
+}
+
+// releaseQuota gives forms and questions back to the quota of author
+func (s *Service) releaseQuota(author string, forms, questions int) error {
+	if s.quotas == nil || (forms == 0 && questions == 0) {
+		return nil
+	}
+
+	if err := s.quotas.repo.ReleaseQuota(author, forms, questions); err != nil {
+		return fmt.Errorf("failed to release quota: %w", err)
+	}
+
+	return nil
+}
+
+// get returns the quota of author, treating an untracked author as empty
+func (q *quotas) get(author string) (*entity.AuthorQuota, error) {
+	quota, err := q.repo.GetQuota(author)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &entity.AuthorQuota{Author: author}, nil
+	}
+
+	return quota, err
+}
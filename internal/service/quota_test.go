@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockQuotaRepository is a mock implementation of the QuotaRepository interface
+type MockQuotaRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuotaRepository) GetQuota(author string) (*entity.AuthorQuota, error) {
+	args := m.Called(author)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AuthorQuota), args.Error(1)
+}
+
+func (m *MockQuotaRepository) SaveQuotaLimits(quota *entity.AuthorQuota) error {
+	args := m.Called(quota)
+	return args.Error(0)
+}
+
+func (m *MockQuotaRepository) ReserveQuota(author string, forms, questions int, limits entity.QuotaLimits) (bool, error) {
+	args := m.Called(author, forms, questions, limits)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockQuotaRepository) ReleaseQuota(author string, forms, questions int) error {
+	args := m.Called(author, forms, questions)
+	return args.Error(0)
+}
+
+var testQuotaLimits = entity.QuotaLimits{MaxForms: 2, MaxQuestions: 10}
+
+func TestService_CreateFormQuota(t *testing.T) {
+	t.Run("rejects forms over the author quota", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		quotas := &MockQuotaRepository{}
+		service.EnableQuotas(quotas, testQuotaLimits)
+
+		form := &entity.Form{ID: uuid.New(), Author: "alice", Questions: []entity.Question{{OrderNumber: 1, Content: "Name?"}}}
+		quotas.On("GetQuota", "alice").Return(&entity.AuthorQuota{Author: "alice", FormCount: 2}, nil)
+		quotas.On("ReserveQuota", "alice", 1, 1, testQuotaLimits).Return(false, nil)
+
+		err := service.CreateForm(form)
+
+		var quotaErr *QuotaExceededError
+		assert.True(t, errors.As(err, &quotaErr))
+		assert.Equal(t, &QuotaExceededError{Author: "alice", Resource: entity.QuotaForms, Limit: 2, Used: 2}, quotaErr)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("applies the author overrides", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		quotas := &MockQuotaRepository{}
+		service.EnableQuotas(quotas, testQuotaLimits)
+
+		maxForms := 5
+		form := &entity.Form{ID: uuid.New(), Author: "bob"}
+		limits := entity.QuotaLimits{MaxForms: 5, MaxQuestions: 10}
+		quotas.On("GetQuota", "bob").Return(&entity.AuthorQuota{Author: "bob", MaxForms: &maxForms, FormCount: 2}, nil)
+		quotas.On("ReserveQuota", "bob", 1, 0, limits).Return(true, nil)
+		mockRepo.On("Create", form).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.created").Return(nil)
+
+		assert.NoError(t, service.CreateForm(form))
+		quotas.AssertExpectations(t)
+	})
+
+	t.Run("releases the reservation when the create fails", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		quotas := &MockQuotaRepository{}
+		service.EnableQuotas(quotas, testQuotaLimits)
+
+		form := &entity.Form{ID: uuid.New(), Author: "carol"}
+		quotas.On("GetQuota", "carol").Return(nil, gorm.ErrRecordNotFound)
+		quotas.On("ReserveQuota", "carol", 1, 0, testQuotaLimits).Return(true, nil)
+		quotas.On("ReleaseQuota", "carol", 1, 0).Return(nil)
+		mockRepo.On("Create", form).Return(errors.New("db down"))
+
+		assert.Error(t, service.CreateForm(form))
+		quotas.AssertExpectations(t)
+	})
+
+	t.Run("rejects forms over the questions per form", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		quotas := &MockQuotaRepository{}
+		service.EnableQuotas(quotas, entity.QuotaLimits{MaxForms: 2, MaxQuestions: 10, MaxPerForm: 5})
+
+		maxPerForm := 2
+		form := &entity.Form{ID: uuid.New(), Author: "dave", Questions: make([]entity.Question, 3)}
+		quotas.On("GetQuota", "dave").Return(&entity.AuthorQuota{Author: "dave", MaxPerForm: &maxPerForm}, nil)
+
+		err := service.CreateForm(form)
+
+		var quotaErr *QuotaExceededError
+		assert.True(t, errors.As(err, &quotaErr))
+		assert.Equal(t, &QuotaExceededError{Author: "dave", Resource: entity.QuotaQuestionsPerForm, Limit: 2, Used: 0}, quotaErr)
+		assert.EqualError(t, err, "quota exceeded: forms of author dave may hold at most 2 questions, form holds 0")
+		quotas.AssertNotCalled(t, "ReserveQuota", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestService_ImportQuestionsQuota(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+	quotas := &MockQuotaRepository{}
+	service.EnableQuotas(quotas, entity.QuotaLimits{MaxPerForm: 3})
+
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Author: "alice", Questions: []entity.Question{{OrderNumber: 1}, {OrderNumber: 2}}}
+	mockRepo.On("Get", formID).Return(form, nil)
+	quotas.On("GetQuota", "alice").Return(&entity.AuthorQuota{Author: "alice"}, nil)
+
+	err := service.ImportQuestions(formID, []entity.Question{{Content: "Name?"}, {Content: "Email?"}})
+
+	var quotaErr *QuotaExceededError
+	assert.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, &QuotaExceededError{Author: "alice", Resource: entity.QuotaQuestionsPerForm, Limit: 3, Used: 2}, quotaErr)
+	mockRepo.AssertNotCalled(t, "CreateQuestions", mock.Anything, mock.Anything)
+}
+
+func TestService_DeleteFormReleasesQuota(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+	quotas := &MockQuotaRepository{}
+	service.EnableQuotas(quotas, testQuotaLimits)
+
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Author: "alice", Questions: make([]entity.Question, 3)}
+	mockRepo.On("Get", formID).Return(form, nil)
+	mockRepo.On("DeleteForm", formID).Return(nil)
+	mockRepo.On("GetTrashedForm", formID).Return(form, nil)
+	mockCasher.On("AddToCash", mock.Anything, formID.String(), form).Return(nil)
+	quotas.On("ReleaseQuota", "alice", 1, 3).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, "form.deleted").Return(nil)
+
+	assert.NoError(t, service.DeleteForm(formID))
+	quotas.AssertExpectations(t)
+}
+
+func TestService_UpdateQuota(t *testing.T) {
+	service, _, _, mockPublisher := setupService()
+	quotas := &MockQuotaRepository{}
+	service.EnableQuotas(quotas, testQuotaLimits)
+
+	maxQuestions := 50
+	overrides := &entity.AuthorQuota{Author: "alice", MaxQuestions: &maxQuestions}
+	quotas.On("SaveQuotaLimits", overrides).Return(nil)
+	quotas.On("GetQuota", "alice").Return(&entity.AuthorQuota{Author: "alice", MaxQuestions: &maxQuestions, FormCount: 1, QuestionCount: 4}, nil)
+	mockPublisher.On("Publish", &entity.OutputQuota{
+		Author:    "alice",
+		Limits:    entity.QuotaLimits{MaxForms: 2, MaxQuestions: 50},
+		Forms:     1,
+		Questions: 4,
+	}, events.AuthorQuotaUpdated.String()).Return(nil)
+
+	assert.NoError(t, service.UpdateQuota(overrides))
+	mockPublisher.AssertExpectations(t)
+}
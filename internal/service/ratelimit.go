@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// ErrRateLimited is returned when a tenant or respondent sends requests faster than allowed
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// EnableRateLimits limits form writes per tenant to the tenant's RateLimit
+// setting. limiter must count hits per minute.
+func (s *Service) EnableRateLimits(limiter RateLimiter) {
+	s.tenantLimit = limiter
+}
+
+// EnableSpamProtection limits response submissions to perMinute per
+// respondent and form. limiter must count hits per minute.
+func (s *Service) EnableSpamProtection(limiter RateLimiter, perMinute int) {
+	s.spamLimit = limiter
+	s.spamPerMin = perMinute
+}
+
+// checkTenantRate fails when the tenant exceeded its request rate
+func (s *Service) checkTenantRate(tenantID string) error {
+	if s.tenantLimit == nil {
+		return nil
+	}
+
+	limit := s.Settings(tenantID).RateLimit
+	if limit <= 0 {
+		return nil
+	}
+
+	return s.allow(s.tenantLimit, "tenant:"+tenantID, limit, fmt.Sprintf("at most %d requests per minute", limit))
+}
+
+// checkResponseRate fails when the respondent submits responses to the form too often
+func (s *Service) checkResponseRate(formID uuid.UUID, submission *entity.ResponseSubmission) error {
+	if s.spamLimit == nil || s.spamPerMin <= 0 {
+		return nil
+	}
+
+	respondent := submission.RespondentID
+	if respondent == "" {
+		respondent = submission.Email
+	}
+
+	if respondent == "" {
+		return nil
+	}
+
+	key := fmt.Sprintf("response:%s:%s", formID, respondent)
+
+	return s.allow(s.spamLimit, key, s.spamPerMin, fmt.Sprintf("at most %d responses per minute", s.spamPerMin))
+}
+
+// allow records a hit on limiter. Limiter failures only reject the request
+// when the limiter is configured to fail closed.
+func (s *Service) allow(limiter RateLimiter, key string, limit int, rule string) error {
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	allowed, err := limiter.AllowLimit(ctx, key, limit)
+	if allowed {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRateLimited, err)
+	}
+
+	return fmt.Errorf("%w: %s", ErrRateLimited, rule)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockRateLimiter is a mock implementation of the RateLimiter interface
+type MockRateLimiter struct {
+	mock.Mock
+}
+
+func (m *MockRateLimiter) AllowLimit(ctx context.Context, key string, limit int) (bool, error) {
+	args := m.Called(key, limit)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestService_TenantRateLimit(t *testing.T) {
+	t.Run("rejects forms over the tenant rate", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockSettings := &MockSettingsRepository{}
+		limiter := &MockRateLimiter{}
+
+		service.EnableTenantSettings(mockSettings, testDefaults, time.Minute)
+		service.EnableRateLimits(limiter)
+
+		mockSettings.On("GetTenantSettings", "acme").Return(nil, gorm.ErrRecordNotFound)
+		limiter.On("AllowLimit", "tenant:acme", testDefaults.RateLimit).Return(false, nil)
+
+		err := service.CreateForm(&entity.Form{ID: uuid.New(), TenantID: "acme"})
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("fails open when the limiter allows despite an error", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		mockSettings := &MockSettingsRepository{}
+		limiter := &MockRateLimiter{}
+		service.EnableTenantSettings(mockSettings, testDefaults, time.Minute)
+		service.EnableRateLimits(limiter)
+
+		form := &entity.Form{ID: uuid.New()}
+		mockSettings.On("GetTenantSettings", "").Return(nil, gorm.ErrRecordNotFound)
+		limiter.On("AllowLimit", "tenant:", testDefaults.RateLimit).Return(true, errors.New("redis down"))
+		mockRepo.On("Create", form).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.created").Return(nil)
+
+		assert.NoError(t, service.CreateForm(form))
+	})
+}
+
+func TestService_SpamProtection(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+	limiter := &MockRateLimiter{}
+	service.EnableSpamProtection(limiter, 3)
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(responseForm(formID), nil)
+	limiter.On("AllowLimit", "response:"+formID.String()+":bob", 3).Return(false, nil)
+	mockPublisher.On("Publish", mock.MatchedBy(func(r *entity.ResponseRejection) bool {
+		return r.RespondentID == "bob"
+	}), events.ResponseRejected.String()).Return(nil)
+
+	err := service.SubmitResponse(&entity.ResponseSubmission{
+		FormID:       formID.String(),
+		RespondentID: "bob",
+		Answers:      []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hi"`)}},
+	})
+
+	assert.ErrorIs(t, err, ErrRateLimited)
+	mockPublisher.AssertExpectations(t)
+}
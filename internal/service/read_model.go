@@ -0,0 +1,198 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProjectedEvents is the pattern of the events ProjectEvent handles
+const ProjectedEvents = "form.*"
+
+// ErrFormNotPublic is returned for forms respondents can't see, because
+// they aren't published or are closed
+var ErrFormNotPublic = errors.New("form is not published")
+
+// EnableReadModel serves respondents from a projection of the published,
+// open forms kept in repo, isolating their traffic from the forms authors
+// edit. ProjectEvent must receive the form events to keep it up to date.
+// Forms published before it was enabled are projected on their next update
+// or publish.
+func (s *Service) EnableReadModel(repo ReadModelRepository) {
+	s.readModel = repo
+}
+
+// ProjectEvent updates the respondent projection on form events. It has
+// the signature of an event bus handler and ignores other events.
+func (s *Service) ProjectEvent(payload any, eventType string) error {
+	if s.readModel == nil {
+		return nil
+	}
+
+	switch events.Type(eventType) {
+	case events.FormPublished:
+		if version, ok := payload.(*entity.FormVersion); ok {
+			return s.projectVersion(version, nil)
+		}
+	case events.FormUpdated:
+		switch form := payload.(type) {
+		case *entity.Form:
+			return s.refreshPublicForm(form)
+		case *entity.FormUpdate:
+			return s.refreshPublicForm(form.Form)
+		}
+	case events.FormRestored, events.FormArchived, events.FormUnarchived, events.FormOpened, events.FormClosed:
+		if form, ok := payload.(*entity.Form); ok {
+			return s.refreshPublicForm(form)
+		}
+	case events.FormDeleted:
+		if deleted, ok := payload.(*events.FormDeletedPayload); ok {
+			formID, err := uuid.Parse(deleted.FormID)
+			if err != nil {
+				return fmt.Errorf("failed to parse form id: %w", err)
+			}
+			return s.removePublicForm(formID)
+		}
+	}
+
+	return nil
+}
+
+// GetPublicForm returns the respondent view of a form, read from the
+// projection when enabled and built from the stored form otherwise
+// Returns ErrFormNotPublic if the form isn't published or is closed
+// Served views are counted in the form stats.
+func (s *Service) GetPublicForm(formID uuid.UUID) (*entity.PublicForm, error) {
+	public, err := s.publicForm(formID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.countFormView(formID)
+
+	return public, nil
+}
+
+// publicForm builds or reads the respondent view of a form
+func (s *Service) publicForm(formID uuid.UUID) (*entity.PublicForm, error) {
+	if s.readModel != nil {
+		public, err := s.readModel.GetPublicForm(formID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, fmt.Errorf("%w: %s", ErrFormNotPublic, formID)
+		case err != nil:
+			return nil, fmt.Errorf("failed to retrieve public form: %w", err)
+		}
+		return public, nil
+	}
+
+	form, err := s.repo.Get(formID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("%w: %s", ErrFormNotPublic, formID)
+	case err != nil:
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if !form.AcceptsResponses() {
+		return nil, fmt.Errorf("%w: %s is closed", ErrFormNotPublic, formID)
+	}
+
+	version, err := s.repo.GetFormVersion(formID, 0)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("%w: %s", ErrFormNotPublic, formID)
+	case err != nil:
+		return nil, fmt.Errorf("failed to retrieve form version: %w", err)
+	}
+
+	return entity.NewPublicForm(version, form)
+}
+
+// PublishPublicForm sends the respondent view of a form back to the requester
+func (s *Service) PublishPublicForm(reply *entity.PublicFormReply) error {
+	return s.publish(events.FormPublic, reply)
+}
+
+// responseForm returns the form submissions are validated against: the
+// published version with the read model, the stored form otherwise
+// Returns ErrFormClosed if the read model has no such form
+func (s *Service) responseForm(formID uuid.UUID) (*entity.Form, error) {
+	if s.readModel == nil {
+		form, err := s.repo.Get(formID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve form: %w", err)
+		}
+		return form, nil
+	}
+
+	public, err := s.readModel.GetPublicForm(formID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("%w: %w: %s", ErrFormClosed, ErrFormNotPublic, formID)
+	case err != nil:
+		return nil, fmt.Errorf("failed to retrieve public form: %w", err)
+	}
+
+	return &public.Form, nil
+}
+
+// refreshPublicForm projects the latest published version of an updated
+// form with its current access settings, or removes it once closed or
+// archived
+func (s *Service) refreshPublicForm(form *entity.Form) error {
+	if form == nil {
+		return nil
+	}
+
+	if !form.AcceptsResponses() {
+		return s.removePublicForm(form.ID)
+	}
+
+	version, err := s.repo.GetFormVersion(form.ID, 0)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil // Never published
+	case err != nil:
+		return fmt.Errorf("failed to retrieve form version: %w", err)
+	}
+
+	return s.projectVersion(version, form)
+}
+
+// projectVersion stores the projection of a published version, see
+// entity.NewPublicForm, unless the form is closed or archived
+func (s *Service) projectVersion(version *entity.FormVersion, live *entity.Form) error {
+	open := version.Form.AcceptsResponses()
+	if live != nil {
+		open = live.AcceptsResponses()
+	}
+
+	if !open {
+		return s.removePublicForm(version.FormID)
+	}
+
+	public, err := entity.NewPublicForm(version, live)
+	if err != nil {
+		return err
+	}
+
+	if err = s.readModel.SavePublicForm(public); err != nil {
+		return fmt.Errorf("failed to save public form: %w", err)
+	}
+
+	return nil
+}
+
+// removePublicForm takes a form out of the projection
+func (s *Service) removePublicForm(formID uuid.UUID) error {
+	if err := s.readModel.DeletePublicForm(formID); err != nil {
+		return fmt.Errorf("failed to delete public form: %w", err)
+	}
+
+	return nil
+}
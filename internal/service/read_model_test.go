@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockReadModelRepository is a mock implementation of the ReadModelRepository interface
+type MockReadModelRepository struct {
+	mock.Mock
+}
+
+func (m *MockReadModelRepository) SavePublicForm(form *entity.PublicForm) error {
+	args := m.Called(form)
+	return args.Error(0)
+}
+
+func (m *MockReadModelRepository) GetPublicForm(formID uuid.UUID) (*entity.PublicForm, error) {
+	args := m.Called(formID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PublicForm), args.Error(1)
+}
+
+func (m *MockReadModelRepository) DeletePublicForm(formID uuid.UUID) error {
+	args := m.Called(formID)
+	return args.Error(0)
+}
+
+func publishedVersion(formID uuid.UUID) *entity.FormVersion {
+	return &entity.FormVersion{
+		FormID:  formID,
+		Version: 3,
+		Form: entity.Form{
+			ID:        formID,
+			Title:     "Published",
+			Status:    entity.StatusPublished,
+			Questions: []entity.Question{{Content: "Name?", OrderNumber: 1}},
+		},
+	}
+}
+
+func TestService_ProjectEvent(t *testing.T) {
+	t.Run("projects published versions", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		readModel := &MockReadModelRepository{}
+		service.EnableReadModel(readModel)
+
+		version := publishedVersion(uuid.New())
+		readModel.On("SavePublicForm", mock.MatchedBy(func(public *entity.PublicForm) bool {
+			var rendered entity.OutputForm
+			return public.Version == 3 &&
+				json.Unmarshal(public.Document, &rendered) == nil &&
+				len(rendered.Questions) == 1
+		})).Return(nil)
+
+		require.NoError(t, service.ProjectEvent(version, events.FormPublished.String()))
+		readModel.AssertExpectations(t)
+	})
+
+	t.Run("applies the current access settings of updated forms", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		readModel := &MockReadModelRepository{}
+		service.EnableReadModel(readModel)
+
+		version := publishedVersion(uuid.New())
+		live := &entity.Form{ID: version.FormID, Title: "Draft edits", Status: entity.StatusPublished, InviteOnly: true}
+
+		mockRepo.On("GetFormVersion", version.FormID, uint(0)).Return(version, nil)
+		readModel.On("SavePublicForm", mock.MatchedBy(func(public *entity.PublicForm) bool {
+			return public.Form.Title == "Published" && public.Form.InviteOnly
+		})).Return(nil)
+
+		require.NoError(t, service.ProjectEvent(live, events.FormUpdated.String()))
+		readModel.AssertExpectations(t)
+	})
+
+	t.Run("ignores updates of unpublished forms", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		readModel := &MockReadModelRepository{}
+		service.EnableReadModel(readModel)
+
+		formID := uuid.New()
+		mockRepo.On("GetFormVersion", formID, uint(0)).Return(nil, gorm.ErrRecordNotFound)
+
+		require.NoError(t, service.ProjectEvent(&entity.Form{ID: formID, Status: entity.StatusPublished}, events.FormUpdated.String()))
+		readModel.AssertNotCalled(t, "SavePublicForm", mock.Anything)
+	})
+
+	t.Run("removes closed and deleted forms", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		readModel := &MockReadModelRepository{}
+		service.EnableReadModel(readModel)
+
+		closed, deleted := uuid.New(), uuid.New()
+		readModel.On("DeletePublicForm", closed).Return(nil)
+		readModel.On("DeletePublicForm", deleted).Return(nil)
+
+		update := &entity.FormUpdate{Form: &entity.Form{ID: closed, Status: entity.StatusClosed}}
+		require.NoError(t, service.ProjectEvent(update, events.FormUpdated.String()))
+
+		event := events.NewFormDeleted(deleted)
+		require.NoError(t, service.ProjectEvent(event.Payload, event.Type.String()))
+		readModel.AssertExpectations(t)
+	})
+}
+
+func TestService_GetPublicForm(t *testing.T) {
+	t.Run("reads the projection", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		readModel := &MockReadModelRepository{}
+		service.EnableReadModel(readModel)
+
+		formID := uuid.New()
+		readModel.On("GetPublicForm", formID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.GetPublicForm(formID)
+		assert.ErrorIs(t, err, ErrFormNotPublic)
+		mockRepo.AssertNotCalled(t, "Get", mock.Anything)
+	})
+
+	t.Run("builds the view from the stored form without the read model", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		version := publishedVersion(uuid.New())
+		mockRepo.On("Get", version.FormID).Return(&entity.Form{ID: version.FormID, Status: entity.StatusPublished}, nil)
+		mockRepo.On("GetFormVersion", version.FormID, uint(0)).Return(version, nil)
+
+		public, err := service.GetPublicForm(version.FormID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), public.Version)
+	})
+
+	t.Run("hides closed forms", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		formID := uuid.New()
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Status: entity.StatusClosed}, nil)
+
+		_, err := service.GetPublicForm(formID)
+		assert.ErrorIs(t, err, ErrFormNotPublic)
+	})
+}
+
+func TestService_SubmitResponse_ReadModel(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+	readModel := &MockReadModelRepository{}
+	service.EnableReadModel(readModel)
+
+	formID := uuid.New()
+	readModel.On("GetPublicForm", formID).Return(nil, gorm.ErrRecordNotFound)
+	mockPublisher.On("Publish", mock.AnythingOfType("*entity.ResponseRejection"), events.ResponseRejected.String()).Return(nil)
+
+	err := service.SubmitResponse(&entity.ResponseSubmission{FormID: formID.String()})
+
+	assert.ErrorIs(t, err, ErrFormClosed)
+	mockRepo.AssertNotCalled(t, "Get", mock.Anything)
+	mockPublisher.AssertExpectations(t)
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of drift between the cache and the database
+const (
+	CacheDriftStale    = "stale"    // The cached form differs from its row
+	CacheDriftOrphaned = "orphaned" // The row of the cached form is gone
+)
+
+// errTrashed tells reconcileForm the row of a cached form is in the trash
+var errTrashed = errors.New("form is in the trash")
+
+// DefaultReconcileSample is the number of cached forms checked per run
+const DefaultReconcileSample = 100
+
+type (
+	// CacheSampler walks the cached forms, see casher.SampleForms
+	CacheSampler interface {
+		SampleForms(ctx context.Context, cursor uint64, count int64) (map[string][]byte, uint64, error)
+	}
+
+	// ReconcileOptions configures cache reconciliation
+	ReconcileOptions struct {
+		SampleSize int  // Cached forms checked per run, 0 uses DefaultReconcileSample
+		Repair     bool // Overwrite stale and remove orphaned forms, otherwise only report them
+	}
+
+	// ReconcileReport summarizes a reconciliation run
+	ReconcileReport struct {
+		Checked  int // Cached forms compared with the database
+		Stale    int // Cached forms differing from their row
+		Orphaned int // Cached forms without a row
+		Repaired int // Divergent forms fixed in the cache
+	}
+
+	// cacheReconciler remembers where the previous run stopped, so runs
+	// cover the whole cache over time
+	cacheReconciler struct {
+		sampler CacheSampler
+		opts    ReconcileOptions
+
+		mu     sync.Mutex // Serializes runs
+		cursor uint64
+	}
+)
+
+// EnableCacheReconciliation lets ReconcileCache compare cached forms sampled
+// through sampler with the database
+func (s *Service) EnableCacheReconciliation(sampler CacheSampler, opts ReconcileOptions) {
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = DefaultReconcileSample
+	}
+
+	s.reconcile = &cacheReconciler{
+		sampler: sampler,
+		opts:    opts,
+	}
+}
+
+// ReconcileCache checks a sample of the cached forms against the database,
+// continuing where the previous run stopped. A cached form is stale when
+// any field but the timestamps differs from its row, which points at a
+// write that reached the database but not the cache. Forms in the trash
+// aren't orphaned, their cached copy only has to be marked as trashed.
+// Drift is counted in metrics and, with Repair, fixed.
+func (s *Service) ReconcileCache(ctx context.Context) (ReconcileReport, error) {
+	var report ReconcileReport
+
+	if s.reconcile == nil {
+		return report, nil
+	}
+
+	r := s.reconcile
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for report.Checked < r.opts.SampleSize {
+		forms, next, err := r.sampler.SampleForms(ctx, r.cursor, int64(r.opts.SampleSize))
+		if err != nil {
+			return report, fmt.Errorf("failed to sample cached forms: %w", err)
+		}
+
+		for key, cached := range forms {
+			if err = s.reconcileForm(ctx, key, cached, &report); err != nil {
+				return report, err
+			}
+		}
+
+		r.cursor = next
+		if next == 0 {
+			break // Every cached form was visited
+		}
+	}
+
+	return report, nil
+}
+
+// reconcileForm compares a cached form with its row
+func (s *Service) reconcileForm(ctx context.Context, key string, cached []byte, report *ReconcileReport) error {
+	formID, err := uuid.Parse(key)
+	if err != nil {
+		return nil // Not a form
+	}
+
+	report.Checked++
+	metrics.CacheReconcileChecked.Inc()
+
+	stored, err := s.storedForm(formID)
+	trashed := errors.Is(err, errTrashed)
+	switch {
+	case trashed:
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		report.Orphaned++
+		metrics.CacheDrift.WithLabelValues(CacheDriftOrphaned).Inc()
+
+		if s.reconcile.opts.Repair {
+			if err = s.casher.RemoveFromCash(ctx, key); err != nil {
+				return fmt.Errorf("failed to remove orphaned form %s: %w", key, err)
+			}
+			report.Repaired++
+		}
+
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to retrieve form %s: %w", key, err)
+	}
+
+	// Trashed forms stay cached until purged, marked as trashed; only the
+	// mark of their cached copy is checked
+	var form entity.Form
+	if err = json.Unmarshal(cached, &form); err == nil {
+		diverges := form.Trashed() != trashed
+		if !diverges && !trashed {
+			if diverges, err = formsDiverge(&form, stored); err != nil {
+				return err
+			}
+		}
+		if !diverges {
+			return nil
+		}
+	}
+
+	// Undecodable entries are stale as well
+	report.Stale++
+	metrics.CacheDrift.WithLabelValues(CacheDriftStale).Inc()
+
+	if s.reconcile.opts.Repair {
+		if err = s.casher.AddToCash(ctx, key, stored); err != nil {
+			return fmt.Errorf("failed to repair cached form %s: %w", key, err)
+		}
+		report.Repaired++
+	}
+
+	return nil
+}
+
+// storedForm retrieves the row of a cached form, including forms in the
+// trash, for which it returns errTrashed along with the form
+func (s *Service) storedForm(formID uuid.UUID) (*entity.Form, error) {
+	stored, err := s.repo.Get(formID)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return stored, err
+	}
+
+	trashed, trashErr := s.repo.GetTrashedForm(formID)
+	if trashErr != nil {
+		return nil, err
+	}
+
+	return trashed, errTrashed
+}
+
+// formsDiverge reports whether the cached form differs from the stored one
+// in anything but timestamps, which lose precision in the database, and the
+// formatting of question options
+func formsDiverge(cached, stored *entity.Form) (bool, error) {
+	normalizeOptions(cached)
+	normalizeOptions(stored)
+
+	changes, err := entity.DiffForms(cached, stored)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare cached form: %w", err)
+	}
+
+	for _, change := range changes {
+		field := change.Path[strings.LastIndex(change.Path, ".")+1:]
+		if field != "CreatedAt" && field != "UpdatedAt" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// normalizeOptions re-encodes question options so equal options compare equal
+func normalizeOptions(form *entity.Form) {
+	for i := range form.Questions {
+		var options any
+		if err := json.Unmarshal(form.Questions[i].Options, &options); err != nil {
+			continue
+		}
+
+		if normalized, err := json.Marshal(options); err == nil {
+			form.Questions[i].Options = normalized
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// pagedSampler is a CacheSampler returning one page of forms per call
+type pagedSampler struct {
+	pages []map[string][]byte
+}
+
+func (p *pagedSampler) SampleForms(_ context.Context, cursor uint64, _ int64) (map[string][]byte, uint64, error) {
+	next := cursor + 1
+	if int(next) == len(p.pages) {
+		next = 0
+	}
+
+	return p.pages[cursor], next, nil
+}
+
+func encodeForm(t *testing.T, form *entity.Form) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(form)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestService_ReconcileCache(t *testing.T) {
+	service, mockCasher, mockRepo, _ := setupService()
+
+	created := time.Now()
+	inSync := &entity.Form{ID: uuid.New(), Title: "same", CreatedAt: created.Truncate(time.Millisecond),
+		Questions: []entity.Question{{Content: "q", Options: json.RawMessage(`{"max": 5, "min": 1}`)}}}
+	stale := &entity.Form{ID: uuid.New(), Title: "new"}
+	orphaned := uuid.New()
+
+	cachedInSync := *inSync
+	cachedInSync.CreatedAt = created
+	cachedInSync.Questions = []entity.Question{{Content: "q", Options: json.RawMessage(`{"min":1,"max":5}`)}}
+
+	service.EnableCacheReconciliation(&pagedSampler{pages: []map[string][]byte{
+		{inSync.ID.String(): encodeForm(t, &cachedInSync), "invite:x:redeemed": []byte("1")},
+		{stale.ID.String(): encodeForm(t, &entity.Form{ID: stale.ID, Title: "old"}), orphaned.String(): []byte("{}")},
+	}}, ReconcileOptions{SampleSize: 10, Repair: true})
+
+	mockRepo.On("Get", inSync.ID).Return(inSync, nil)
+	mockRepo.On("Get", stale.ID).Return(stale, nil)
+	mockRepo.On("Get", orphaned).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetTrashedForm", orphaned).Return(nil, gorm.ErrRecordNotFound)
+	mockCasher.On("AddToCash", mock.Anything, stale.ID.String(), stale).Return(nil)
+	mockCasher.On("RemoveFromCash", mock.Anything, orphaned.String()).Return(nil)
+
+	report, err := service.ReconcileCache(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, ReconcileReport{Checked: 3, Stale: 1, Orphaned: 1, Repaired: 2}, report)
+	mockCasher.AssertExpectations(t)
+}
+
+func TestService_ReconcileCache_ReportOnly(t *testing.T) {
+	service, mockCasher, mockRepo, _ := setupService()
+	stale := &entity.Form{ID: uuid.New(), Title: "new"}
+
+	service.EnableCacheReconciliation(&pagedSampler{pages: []map[string][]byte{
+		{stale.ID.String(): []byte("not json")},
+	}}, ReconcileOptions{})
+	mockRepo.On("Get", stale.ID).Return(stale, nil)
+
+	report, err := service.ReconcileCache(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, ReconcileReport{Checked: 1, Stale: 1}, report)
+	mockCasher.AssertNotCalled(t, "AddToCash", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ReconcileCache_Trashed(t *testing.T) {
+	service, mockCasher, mockRepo, _ := setupService()
+
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+	marked := &entity.Form{ID: uuid.New(), Title: "marked", DeletedAt: deletedAt}
+	unmarked := &entity.Form{ID: uuid.New(), Title: "unmarked", DeletedAt: deletedAt}
+
+	service.EnableCacheReconciliation(&pagedSampler{pages: []map[string][]byte{
+		{
+			marked.ID.String():   encodeForm(t, &entity.Form{ID: marked.ID, Title: "edited since", DeletedAt: deletedAt}),
+			unmarked.ID.String(): encodeForm(t, &entity.Form{ID: unmarked.ID, Title: "unmarked"}),
+		},
+	}}, ReconcileOptions{Repair: true})
+
+	for _, form := range []*entity.Form{marked, unmarked} {
+		mockRepo.On("Get", form.ID).Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("GetTrashedForm", form.ID).Return(form, nil)
+	}
+	mockCasher.On("AddToCash", mock.Anything, unmarked.ID.String(), unmarked).Return(nil)
+
+	report, err := service.ReconcileCache(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, ReconcileReport{Checked: 2, Stale: 1, Repaired: 1}, report, "trashed forms aren't orphaned, only an unmarked copy is stale")
+	mockCasher.AssertExpectations(t)
+	mockCasher.AssertNotCalled(t, "RemoveFromCash", mock.Anything, mock.Anything)
+}
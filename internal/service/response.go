@@ -0,0 +1,316 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// Page sizes of response listings
+const (
+	DefaultResponsePageSize = 50
+	MaxResponsePageSize     = 500
+)
+
+var (
+	// ErrInvalidResponse is returned when answers of a submitted response are invalid
+	ErrInvalidResponse = errors.New("invalid response")
+
+	// ErrResponsesDisabled is returned when listing responses that aren't stored
+	ErrResponsesDisabled = errors.New("response storage is not configured")
+
+	// ErrFormClosed is returned when answering a closed form
+	ErrFormClosed = errors.New("form is closed")
+)
+
+// nullAnswer stands in for questions left unanswered, so their type can
+// reject it when the question is required
+var nullAnswer = json.RawMessage("null")
+
+// ValidateAnswers checks every answer against its question's type, options
+// and validation rules. Unanswered questions are validated as null, questions hidden by
+// the form logic aren't validated. The result lists the
+// errors of each failing question, ordered by question position, and is
+// empty when the answers are valid.
+func (s *Service) ValidateAnswers(form *entity.Form, answers []entity.Answer) []entity.AnswerError {
+	given := make(map[uint]json.RawMessage, len(answers))
+	failed := make(map[uint][]string)
+
+	for _, answer := range answers {
+		if _, ok := given[answer.OrderNumber]; ok {
+			failed[answer.OrderNumber] = append(failed[answer.OrderNumber], "question answered more than once")
+			continue
+		}
+
+		given[answer.OrderNumber] = answer.Value
+	}
+
+	known := make(map[uint]bool, len(form.Questions))
+	visible := form.VisibleQuestions(given)
+
+	for i := range form.Questions {
+		q := &form.Questions[i]
+		known[q.OrderNumber] = true
+
+		if !visible[q.OrderNumber] {
+			continue
+		}
+
+		value, ok := given[q.OrderNumber]
+		if !ok || len(value) == 0 {
+			value = nullAnswer
+		}
+
+		if err := s.questions.ValidateAnswer(q, value); err != nil {
+			failed[q.OrderNumber] = append(failed[q.OrderNumber], err.Error())
+			continue
+		}
+
+		if q.Rules != nil {
+			if err := q.Rules.Check(value); err != nil {
+				failed[q.OrderNumber] = append(failed[q.OrderNumber], err.Error())
+			}
+		}
+	}
+
+	for orderNumber := range given {
+		if !known[orderNumber] {
+			failed[orderNumber] = append(failed[orderNumber], "no such question")
+		}
+	}
+
+	result := make([]entity.AnswerError, 0, len(failed))
+	for orderNumber, errs := range failed {
+		result = append(result, entity.AnswerError{OrderNumber: orderNumber, Errors: errs})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].OrderNumber < result[j].OrderNumber
+	})
+
+	return result
+}
+
+// EnableResponses stores accepted responses in repo, publishing
+// response.created for each. Without it responses are only published.
+func (s *Service) EnableResponses(repo ResponseRepository) {
+	s.responses = repo
+}
+
+// SubmitResponse checks that the respondent may answer the form and that
+// every answer is valid, then stores the response, when enabled, and
+// publishes form.response.accepted listing the files attached to file
+// questions. A rejected
+// submission publishes form.response.rejected with the reason and, for
+// invalid answers, the per-question errors. Single-use invites are only
+// redeemed by accepted submissions, and released again if the response
+// can't be stored.
+func (s *Service) SubmitResponse(submission *entity.ResponseSubmission) (err error) {
+	defer observe("submit_response", time.Now(), &err)
+
+	formID, err := uuid.Parse(submission.FormID)
+	if err != nil {
+		return fmt.Errorf("failed to parse form id: %w", err)
+	}
+
+	form, err := s.responseForm(formID)
+	if errors.Is(err, ErrFormClosed) {
+		return s.rejectResponse(submission, err, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !form.AcceptsResponses() {
+		return s.rejectResponse(submission, fmt.Errorf("%w: %s", ErrFormClosed, formID), nil)
+	}
+
+	if err = s.CheckAccess(form, submission.Email, submission.Token); err != nil {
+		return s.rejectResponse(submission, err, nil)
+	}
+
+	if err = s.checkResponseRate(formID, submission); err != nil {
+		return s.rejectResponse(submission, err, nil)
+	}
+
+	// Skipped questions are answered with their defaults, which validate
+	// and are stored like any other answer
+	submission.Answers = form.WithDefaults(submission.Answers)
+
+	if errs := s.ValidateAnswers(form, submission.Answers); len(errs) > 0 {
+		return s.rejectResponse(submission, ErrInvalidResponse, errs)
+	}
+
+	uploads, errs, err := s.answerUploads(form, submission.Answers)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return s.rejectResponse(submission, ErrInvalidResponse, errs)
+	}
+
+	redeemed, err := s.redeemAccess(form, submission.Email, submission.Token)
+	if err != nil {
+		if errors.Is(err, ErrAccessDenied) {
+			return s.rejectResponse(submission, err, nil)
+		}
+		return err
+	}
+
+	accepted := *submission
+	accepted.Token = ""
+	accepted.ConfirmationMessage = form.Settings.ConfirmationMessage
+
+	stored, err := s.storeResponse(form, &accepted)
+	if err != nil {
+		return errors.Join(err, s.releaseInvite(redeemed))
+	}
+
+	if err = s.attachAnswerUploads(stored, &accepted, uploads); err != nil {
+		return err
+	}
+
+	if err = s.publish(events.ResponseAccepted, &accepted); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	if stored != nil {
+		if err = s.publish(events.ResponseCreated, stored); err != nil {
+			return fmt.Errorf("publish error: %w", err)
+		}
+	}
+
+	s.countFormSubmission(formID)
+
+	if err = s.recordAnswerStats(form, submission.Answers); err != nil {
+		return fmt.Errorf("failed to record question stats: %w", err)
+	}
+
+	return nil
+}
+
+// storeResponse stores an accepted submission and sets its response ID,
+// nil while responses aren't stored. Responses to forms with anonymous
+// responses are stored without their respondent and email.
+func (s *Service) storeResponse(form *entity.Form, accepted *entity.ResponseSubmission) (*entity.Response, error) {
+	if s.responses == nil {
+		return nil, nil
+	}
+
+	response := &entity.Response{
+		ID:           uuid.New(),
+		FormID:       form.ID,
+		RequestID:    accepted.RequestID,
+		RespondentID: accepted.RespondentID,
+		Email:        accepted.Email,
+		Answers:      accepted.Answers,
+		CreatedAt:    time.Now(),
+	}
+
+	if form.Settings.AnonymousResponses {
+		response.RespondentID = ""
+		response.Email = ""
+	}
+
+	if err := s.insertResponse(response); err != nil {
+		return nil, fmt.Errorf("failed to store response: %w", err)
+	}
+
+	accepted.ResponseID = response.ID.String()
+
+	return response, nil
+}
+
+// attachAnswerUploads attaches the uploads answering file questions to the
+// stored response, nil while responses aren't stored, and lists them on the
+// accepted submission
+func (s *Service) attachAnswerUploads(stored *entity.Response, accepted *entity.ResponseSubmission, uploads []answerUpload) error {
+	responseID := uuid.Nil
+	if stored != nil {
+		responseID = stored.ID
+	}
+
+	for i := range uploads {
+		if err := s.attachUpload(responseID, &uploads[i].upload); err != nil {
+			return err
+		}
+
+		accepted.Attachments = append(accepted.Attachments, uploads[i].upload.ToAttachment(uploads[i].orderNumber))
+	}
+
+	return nil
+}
+
+// ListResponses returns a page of the stored responses of a form, oldest
+// first, narrowed down by filter unless nil. A limit of 0 returns
+// DefaultResponsePageSize responses; larger limits than MaxResponsePageSize
+// are capped.
+func (s *Service) ListResponses(formID uuid.UUID, filter *entity.Filter, offset, limit int) (*entity.ResponseList, error) {
+	if s.responses == nil {
+		return nil, ErrResponsesDisabled
+	}
+
+	if err := s.authorizeRead(formID); err != nil {
+		return nil, err
+	}
+
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit <= 0 {
+		limit = DefaultResponsePageSize
+	}
+	limit = min(limit, MaxResponsePageSize)
+	offset = max(offset, 0)
+
+	return cachedQuery(s, responsesScope(formID), fmt.Sprintf("%d:%d:%s", offset, limit, filter.Key()), func() (*entity.ResponseList, error) {
+		total, err := s.responses.CountResponses(formID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count responses: %w", err)
+		}
+
+		responses, err := s.responses.ListResponses(formID, filter, offset, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list responses: %w", err)
+		}
+
+		return &entity.ResponseList{
+			FormID:    formID.String(),
+			Total:     total,
+			Offset:    offset,
+			Responses: responses,
+		}, nil
+	})
+}
+
+// PublishResponseList sends a response listing back to the requester
+func (s *Service) PublishResponseList(list *entity.ResponseList) error {
+	return s.publish(events.ResponseList, list)
+}
+
+// rejectResponse publishes the rejection of a submission and returns reason
+func (s *Service) rejectResponse(submission *entity.ResponseSubmission, reason error, errs []entity.AnswerError) error {
+	rejection := &entity.ResponseRejection{
+		RequestID:    submission.RequestID,
+		FormID:       submission.FormID,
+		RespondentID: submission.RespondentID,
+		Reason:       reason.Error(),
+		Errors:       errs,
+		ErrorDetail:  s.errorDetail(reason),
+	}
+
+	if err := s.publish(events.ResponseRejected, rejection); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return reason
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/batch"
+)
+
+// EnableResponseBatching inserts stored responses through repo in batches,
+// see package batch. A submission waits until the batch of its response is
+// inserted, so responses are still stored before they are accepted and a
+// failed insert fails the submission, which is retried.
+func (s *Service) EnableResponseBatching(repo BulkResponseRepository, opts batch.Options) {
+	s.responseBatch = batch.New("responses", repo.CreateResponses, opts)
+}
+
+// CloseResponseBatching inserts the responses waiting for their batch
+func (s *Service) CloseResponseBatching() error {
+	if s.responseBatch == nil {
+		return nil
+	}
+
+	return s.responseBatch.Close()
+}
+
+// insertResponse stores response, with others when batching
+func (s *Service) insertResponse(response *entity.Response) error {
+	if s.responseBatch == nil {
+		return s.responses.Create(response)
+	}
+
+	return s.responseBatch.Write(*response)
+}
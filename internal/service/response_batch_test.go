@@ -0,0 +1,89 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/pkg/batch"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBulkResponses records the batches of responses inserted
+type fakeBulkResponses struct {
+	mu      sync.Mutex
+	batches [][]entity.Response
+	err     error
+}
+
+func (f *fakeBulkResponses) CreateResponses(responses []entity.Response) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	f.batches = append(f.batches, responses)
+	return nil
+}
+
+func TestService_SubmitResponse_Batching(t *testing.T) {
+	submission := func(formID uuid.UUID, respondent string) *entity.ResponseSubmission {
+		return &entity.ResponseSubmission{
+			FormID:       formID.String(),
+			RespondentID: respondent,
+			Answers:      []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hi"`)}},
+		}
+	}
+
+	t.Run("inserts concurrent submissions at once", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		service.EnableResponses(new(MockResponseRepository))
+		bulk := &fakeBulkResponses{}
+		service.EnableResponseBatching(bulk, batch.Options{Interval: time.Hour, MaxSize: 3})
+		formID := uuid.New()
+
+		mockRepo.On("Get", formID).Return(responseForm(formID), nil)
+		mockPublisher.On("Publish", mock.Anything, events.ResponseAccepted.String()).Return(nil)
+		mockPublisher.On("Publish", mock.Anything, events.ResponseCreated.String()).Return(nil)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 3)
+		for i, respondent := range []string{"ann", "bob", "cid"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = service.SubmitResponse(submission(formID, respondent))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, []error{nil, nil, nil}, errs)
+		require.Len(t, bulk.batches, 1)
+		assert.Len(t, bulk.batches[0], 3)
+		mockPublisher.AssertNumberOfCalls(t, "Publish", 6)
+	})
+
+	t.Run("doesn't accept responses that failed to insert", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		service.EnableResponses(new(MockResponseRepository))
+		service.EnableResponseBatching(&fakeBulkResponses{err: errors.New("database down")}, batch.Options{Interval: time.Millisecond})
+		formID := uuid.New()
+
+		mockRepo.On("Get", formID).Return(responseForm(formID), nil)
+
+		err := service.SubmitResponse(submission(formID, "ann"))
+
+		assert.ErrorContains(t, err, "failed to store response")
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+		assert.NoError(t, service.CloseResponseBatching())
+	})
+}
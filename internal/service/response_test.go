@@ -0,0 +1,345 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/internal/errcatalog"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockResponseRepository is a mock implementation of the ResponseRepository interface
+type MockResponseRepository struct {
+	mock.Mock
+}
+
+func (m *MockResponseRepository) Create(entity any) error {
+	args := m.Called(entity)
+	return args.Error(0)
+}
+
+func (m *MockResponseRepository) ListResponses(formID uuid.UUID, filter *entity.Filter, offset, limit int) ([]entity.Response, error) {
+	args := m.Called(formID, filter, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Response), args.Error(1)
+}
+
+func (m *MockResponseRepository) CountResponses(formID uuid.UUID, filter *entity.Filter) (int64, error) {
+	args := m.Called(formID, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func responseForm(formID uuid.UUID) *entity.Form {
+	return &entity.Form{
+		ID:     formID,
+		Status: entity.StatusPublished,
+		Questions: []entity.Question{
+			{OrderNumber: 1, Type: "text", Options: json.RawMessage(`{"required":true}`)},
+			{OrderNumber: 2, Type: "number", Options: json.RawMessage(`{"min":1,"max":5}`)},
+			{OrderNumber: 3, Type: "choice", Options: json.RawMessage(`{"choices":["a","b"]}`)},
+		},
+	}
+}
+
+func TestService_ValidateAnswers(t *testing.T) {
+	service, _, _, _ := setupService()
+	form := responseForm(uuid.New())
+
+	t.Run("valid answers", func(t *testing.T) {
+		errs := service.ValidateAnswers(form, []entity.Answer{
+			{OrderNumber: 1, Value: json.RawMessage(`"hello"`)},
+			{OrderNumber: 2, Value: json.RawMessage(`3`)},
+		})
+
+		assert.Empty(t, errs)
+	})
+
+	t.Run("reports every failing question", func(t *testing.T) {
+		errs := service.ValidateAnswers(form, []entity.Answer{
+			{OrderNumber: 2, Value: json.RawMessage(`9`)},
+			{OrderNumber: 3, Value: json.RawMessage(`"c"`)},
+			{OrderNumber: 3, Value: json.RawMessage(`"a"`)},
+			{OrderNumber: 7, Value: json.RawMessage(`"x"`)},
+		})
+
+		require.Len(t, errs, 4)
+		assert.Equal(t, []uint{1, 2, 3, 7}, []uint{errs[0].OrderNumber, errs[1].OrderNumber, errs[2].OrderNumber, errs[3].OrderNumber})
+		assert.Contains(t, errs[0].Errors[0], "answer is required")
+		assert.Contains(t, errs[1].Errors[0], "greater than 5")
+		assert.Len(t, errs[2].Errors, 2, "duplicate and unknown choice")
+		assert.Equal(t, []string{"no such question"}, errs[3].Errors)
+	})
+
+	t.Run("skips questions hidden by the logic", func(t *testing.T) {
+		form := responseForm(uuid.New())
+		for i := range form.Questions {
+			form.Questions[i].ID = questionID(uint(i + 1))
+		}
+		form.Branches = []entity.Branch{
+			{SourceID: questionID(2), Operator: entity.BranchEquals, Value: json.RawMessage(`1`), TargetID: questionID(1), Action: entity.BranchHide},
+		}
+
+		errs := service.ValidateAnswers(form, []entity.Answer{
+			{OrderNumber: 2, Value: json.RawMessage(`1`)},
+		})
+
+		assert.Empty(t, errs, "the required question is hidden")
+	})
+
+	t.Run("enforces validation rules", func(t *testing.T) {
+		form := responseForm(uuid.New())
+		form.Questions[0].Rules = &entity.ValidationRules{Pattern: `^[a-z]+$`}
+
+		errs := service.ValidateAnswers(form, []entity.Answer{
+			{OrderNumber: 1, Value: json.RawMessage(`"Hello!"`)},
+			{OrderNumber: 2, Value: json.RawMessage(`3`)},
+		})
+
+		require.Len(t, errs, 1)
+		assert.Equal(t, uint(1), errs[0].OrderNumber)
+		assert.Contains(t, errs[0].Errors[0], "must match")
+	})
+}
+
+func TestService_SubmitResponse(t *testing.T) {
+	t.Run("publishes accepted responses without the token", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+
+		mockRepo.On("Get", formID).Return(responseForm(formID), nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(s *entity.ResponseSubmission) bool {
+			return s.Token == "" && len(s.Answers) == 1
+		}), events.ResponseAccepted.String()).Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{
+			FormID:  formID.String(),
+			Token:   "secret",
+			Answers: []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hi"`)}},
+		})
+
+		assert.NoError(t, err)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("stores accepted responses", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		responses := new(MockResponseRepository)
+		service.EnableResponses(responses)
+		formID := uuid.New()
+
+		var stored *entity.Response
+		mockRepo.On("Get", formID).Return(responseForm(formID), nil)
+		responses.On("Create", mock.AnythingOfType("*entity.Response")).
+			Run(func(args mock.Arguments) { stored = args.Get(0).(*entity.Response) }).
+			Return(nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(s *entity.ResponseSubmission) bool {
+			return s.ResponseID == stored.ID.String()
+		}), events.ResponseAccepted.String()).Return(nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(r *entity.Response) bool {
+			return r == stored
+		}), events.ResponseCreated.String()).Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{
+			RequestID:    "req-1",
+			FormID:       formID.String(),
+			RespondentID: "respondent",
+			Answers:      []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hi"`)}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, formID, stored.FormID)
+		assert.Equal(t, "respondent", stored.RespondentID)
+		assert.Len(t, stored.Answers, 1)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("releases the invite when the response can't be stored", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		responses := new(MockResponseRepository)
+		service.EnableResponses(responses)
+		formID := uuid.New()
+		form := responseForm(formID)
+		form.InviteOnly = true
+		invite := &entity.InviteToken{ID: uuid.New(), FormID: formID, SingleUse: true}
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockRepo.On("GetInviteByHash", formID, hashInviteToken("t")).Return(invite, nil)
+		mockCasher.On("AddToCashIfAbsent", mock.Anything, redemptionKey(invite.ID), mock.Anything, time.Duration(0)).Return(true, nil)
+		mockRepo.On("RedeemInvite", invite.ID).Return(nil)
+		responses.On("Create", mock.AnythingOfType("*entity.Response")).Return(errors.New("db down"))
+		mockRepo.On("ReleaseInvite", invite.ID).Return(nil)
+		mockCasher.On("RemoveFromCash", mock.Anything, redemptionKey(invite.ID)).Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{
+			FormID:  formID.String(),
+			Token:   "t",
+			Answers: []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hi"`)}},
+		})
+
+		assert.ErrorContains(t, err, "db down")
+		mockRepo.AssertCalled(t, "ReleaseInvite", invite.ID)
+		mockCasher.AssertCalled(t, "RemoveFromCash", mock.Anything, redemptionKey(invite.ID))
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, events.ResponseAccepted.String())
+	})
+
+	t.Run("stores anonymous responses without the respondent", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		responses := new(MockResponseRepository)
+		service.EnableResponses(responses)
+		formID := uuid.New()
+		form := responseForm(formID)
+		form.Settings = entity.FormSettings{AnonymousResponses: true, ConfirmationMessage: "Thanks!"}
+
+		var stored *entity.Response
+		mockRepo.On("Get", formID).Return(form, nil)
+		responses.On("Create", mock.AnythingOfType("*entity.Response")).
+			Run(func(args mock.Arguments) { stored = args.Get(0).(*entity.Response) }).
+			Return(nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(s *entity.ResponseSubmission) bool {
+			return s.RespondentID == "respondent" && s.ConfirmationMessage == "Thanks!"
+		}), events.ResponseAccepted.String()).Return(nil)
+		mockPublisher.On("Publish", mock.AnythingOfType("*entity.Response"), events.ResponseCreated.String()).Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{
+			FormID:       formID.String(),
+			RespondentID: "respondent",
+			Email:        "ann@example.com",
+			Answers:      []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hi"`)}},
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, stored.RespondentID)
+		assert.Empty(t, stored.Email)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid answers with per-question errors", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+
+		mockRepo.On("Get", formID).Return(responseForm(formID), nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(r *entity.ResponseRejection) bool {
+			return r.RequestID == "req-1" && len(r.Errors) == 1 && r.Errors[0].OrderNumber == 1 &&
+				r.Code == "invalid_answers"
+		}), events.ResponseRejected.String()).Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{RequestID: "req-1", FormID: formID.String()})
+
+		assert.ErrorIs(t, err, ErrInvalidResponse)
+		mockPublisher.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "RedeemInvite", mock.Anything)
+	})
+
+	t.Run("answers skipped questions with their defaults", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+		form := responseForm(formID)
+		form.Questions[1].DefaultValue = json.RawMessage(`3`)
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(s *entity.ResponseSubmission) bool {
+			return assert.ObjectsAreEqual([]entity.Answer{
+				{OrderNumber: 1, Value: json.RawMessage(`"hi"`)},
+				{OrderNumber: 2, Value: json.RawMessage(`3`)},
+			}, s.Answers)
+		}), events.ResponseAccepted.String()).Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{
+			FormID:  formID.String(),
+			Answers: []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hi"`)}},
+		})
+
+		assert.NoError(t, err)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects respondents without access", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+		form := responseForm(formID)
+		form.AllowedDomains = []string{"example.com"}
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(r *entity.ResponseRejection) bool {
+			return len(r.Errors) == 0 && r.Code == "domain_not_allowed" && r.Messages["ru"] != ""
+		}), events.ResponseRejected.String()).Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{FormID: formID.String(), Email: "eve@other.org"})
+
+		assert.ErrorIs(t, err, ErrAccessDenied)
+		mockPublisher.AssertExpectations(t)
+	})
+}
+
+func TestService_ListResponses(t *testing.T) {
+	t.Run("fails while responses aren't stored", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		_, err := service.ListResponses(uuid.New(), nil, 0, 0)
+		assert.ErrorIs(t, err, ErrResponsesDisabled)
+	})
+
+	t.Run("pages responses", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		responses := new(MockResponseRepository)
+		service.EnableResponses(responses)
+		formID := uuid.New()
+
+		responses.On("CountResponses", formID, (*entity.Filter)(nil)).Return(int64(700), nil)
+		responses.On("ListResponses", formID, (*entity.Filter)(nil), 0, MaxResponsePageSize).Return([]entity.Response{{ID: uuid.New()}}, nil)
+
+		list, err := service.ListResponses(formID, nil, -5, 10000)
+		require.NoError(t, err)
+		assert.Equal(t, int64(700), list.Total)
+		assert.Equal(t, formID.String(), list.FormID)
+		assert.Len(t, list.Responses, 1)
+	})
+
+	t.Run("refuses strangers", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		responses := new(MockResponseRepository)
+		service.EnableResponses(responses)
+		formID := uuid.New()
+
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Author: "alice"}, nil)
+		mockRepo.On("GetCollaborator", formID, "eve").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.As("eve").ListResponses(formID, nil, 0, 0)
+
+		assert.ErrorIs(t, err, ErrNotCollaborator)
+		responses.AssertNotCalled(t, "ListResponses", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want errcatalog.Code
+	}{
+		{fmt.Errorf("%w: %s", ErrFormClosed, uuid.New()), errcatalog.FORM_CLOSED},
+		{ErrInviteExpired, errcatalog.INVITE_EXPIRED},
+		{fmt.Errorf("failed: %w", ErrAccessDenied), errcatalog.ACCESS_DENIED},
+		{&QuotaExceededError{Author: "a", Resource: entity.QuotaForms}, errcatalog.QUOTA_EXCEEDED},
+		{fmt.Errorf("invalid question: %w", &entity.RuleError{Rule: entity.RulePattern}), errcatalog.INVALID_RULES},
+		{fmt.Errorf("%w: %s", ErrNotInTrash, uuid.New()), errcatalog.NOT_FOUND},
+		{fmt.Errorf("failed to retrieve form: %w", gorm.ErrRecordNotFound), errcatalog.NOT_FOUND},
+		{errors.New("connection refused"), errcatalog.INTERNAL},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ErrorCode(tt.err), tt.err.Error())
+	}
+
+	assert.ErrorIs(t, ErrInviteExpired, ErrAccessDenied, "access reasons wrap ErrAccessDenied")
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/Koyo-os/form-service/pkg/retrier"
+)
+
+// Side effect names used for retry policies in logs and metrics
+const (
+	SideEffectCache   = "cache"
+	SideEffectPublish = "publish"
+)
+
+// RetryPolicy controls how a non-critical side effect is retried
+type RetryPolicy struct {
+	Retries uint8         // Retries after the first attempt, 0 disables retrying
+	Delay   time.Duration // Pause between attempts
+}
+
+// DefaultRetryPolicy is applied to every side effect unless overridden
+var DefaultRetryPolicy = RetryPolicy{
+	Retries: DefaultRetryAttempts - 1,
+	Delay:   DefaultRetryDelay * time.Millisecond,
+}
+
+// RetryPolicyFromConfig converts a configured policy into a RetryPolicy
+func RetryPolicyFromConfig(cfg config.RetryPolicy) RetryPolicy {
+	return RetryPolicy{
+		Retries: cfg.Retries,
+		Delay:   time.Duration(cfg.DelayMs) * time.Millisecond,
+	}
+}
+
+// do runs try once plus up to Retries more times, stopping early once ctx,
+// the context of the operation, is done
+func (p RetryPolicy) do(ctx context.Context, try retrier.Try) error {
+	attempts := p.Retries
+	if attempts < math.MaxUint8 {
+		attempts++
+	}
+
+	return retrier.Do(ctx, attempts, p.Delay, try)
+}
+
+// String renders the policy for startup logs
+func (p RetryPolicy) String() string {
+	return fmt.Sprintf("retries=%d delay=%s", p.Retries, p.Delay)
+}
+
+// SetRetryPolicy overrides the retry policies of the cache and publish side
+// effects and exports them as metrics. It must be called before the service
+// starts handling requests.
+func (s *Service) SetRetryPolicy(cache, publish RetryPolicy) {
+	s.cacheRetry = cache
+	s.publishRetry = publish
+
+	report(SideEffectCache, cache)
+	report(SideEffectPublish, publish)
+}
+
+// RetryPolicies returns the effective policy of each side effect
+func (s *Service) RetryPolicies() map[string]RetryPolicy {
+	return map[string]RetryPolicy{
+		SideEffectCache:   s.cacheRetry,
+		SideEffectPublish: s.publishRetry,
+	}
+}
+
+func report(sideEffect string, policy RetryPolicy) {
+	metrics.RetryPolicy.WithLabelValues(sideEffect, "retries").Set(float64(policy.Retries))
+	metrics.RetryPolicy.WithLabelValues(sideEffect, "delay_seconds").Set(policy.Delay.Seconds())
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/richtext"
+	"github.com/google/uuid"
+)
+
+// sanitizeText returns text written in format cleaned of everything but
+// formatting and safe links, plain text is kept as it is
+func sanitizeText(format, text string) string {
+	switch format {
+	case entity.FormatHTML:
+		return richtext.SanitizeHTML(text)
+	case entity.FormatMarkdown:
+		return richtext.SanitizeMarkdown(text)
+	}
+
+	return text
+}
+
+// sanitizeForm sanitizes the description of form and its questions, their
+// translations included, before they are stored
+func sanitizeForm(form *entity.Form) error {
+	if err := entity.ValidateFormat(form.Format); err != nil {
+		return err
+	}
+
+	form.Description = sanitizeText(form.Format, form.Description)
+	form.Translations = sanitizeTranslations(form.Format, form.Translations)
+
+	for i := range form.Questions {
+		if err := sanitizeQuestion(&form.Questions[i]); err != nil {
+			return fmt.Errorf("invalid question: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// sanitizeQuestion sanitizes the content of question and its translations
+func sanitizeQuestion(question *entity.Question) error {
+	if err := entity.ValidateFormat(question.Format); err != nil {
+		return err
+	}
+
+	question.Content = sanitizeText(question.Format, question.Content)
+
+	if question.Translations == nil {
+		return nil
+	}
+
+	translations := make(map[string]entity.QuestionTranslation, len(question.Translations))
+	for locale, t := range question.Translations {
+		t.Content = sanitizeText(question.Format, t.Content)
+		translations[locale] = t
+	}
+	question.Translations = translations
+
+	return nil
+}
+
+// sanitizeTranslations returns copies of the form translations with their
+// descriptions sanitized, the callers' maps are left alone
+func sanitizeTranslations(format string, translations map[string]entity.FormTranslation) map[string]entity.FormTranslation {
+	if translations == nil {
+		return nil
+	}
+
+	sanitized := maps.Clone(translations)
+	for locale, t := range sanitized {
+		t.Description = sanitizeText(format, t.Description)
+		sanitized[locale] = t
+	}
+
+	return sanitized
+}
+
+// sanitizeUpdate sanitizes the description and translations an update of a
+// form writes. Changing the format of a form sanitizes the stored texts the
+// update leaves alone in the new format as well, so none of them is shown in
+// a format it wasn't sanitized for.
+func (s *Service) sanitizeUpdate(formID uuid.UUID, update *entity.Form) error {
+	if err := entity.ValidateFormat(update.Format); err != nil {
+		return err
+	}
+
+	if update.Format == "" && update.Description == "" && update.Translations == nil {
+		return nil
+	}
+
+	stored, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	format := stored.Format
+	if update.Format != "" && update.Format != stored.Format {
+		format = update.Format
+
+		if update.Description == "" {
+			update.Description = stored.Description
+		}
+		if update.Translations == nil {
+			update.Translations = stored.Translations
+		}
+	}
+
+	update.Description = sanitizeText(format, update.Description)
+	update.Translations = sanitizeTranslations(format, update.Translations)
+
+	return nil
+}
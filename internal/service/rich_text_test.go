@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreateForm_SanitizesRichText(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	form := &entity.Form{
+		ID:          uuid.New(),
+		Title:       "Test Form",
+		Description: `<p onclick="steal()">Welcome</p><script>steal()</script>`,
+		Format:      entity.FormatHTML,
+		Translations: map[string]entity.FormTranslation{
+			"de": {Description: "<b>Willkommen</b><style>*{}</style>"},
+		},
+		Questions: []entity.Question{
+			{Content: "[Read this](javascript:steal())", Format: entity.FormatMarkdown},
+			{Content: "<b>kept as written</b>"},
+		},
+	}
+
+	mockRepo.On("Create", form).Return(nil)
+	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), form.ID.String(), form).
+		Return(nil)
+	mockPublisher.On("Publish", form, "form.created").Return(nil)
+
+	require.NoError(t, service.CreateForm(form))
+
+	assert.Equal(t, "<p>Welcome</p>", form.Description)
+	assert.Equal(t, "<b>Willkommen</b>", form.Translations["de"].Description)
+	assert.Equal(t, "[Read this](#)", form.Questions[0].Content)
+	assert.Equal(t, "<b>kept as written</b>", form.Questions[1].Content, "plain text is escaped by renderers")
+}
+
+func TestService_CreateForm_InvalidFormat(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+
+	form := &entity.Form{ID: uuid.New(), Author: "alice", Format: "rtf"}
+
+	err := service.CreateForm(form)
+
+	assert.ErrorIs(t, err, entity.ErrInvalidFormat)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestService_Update_FormatChangeSanitizesStoredDescription(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	stored := &entity.Form{ID: formID, Description: "<img src=x onerror=steal()>Hello"}
+	update := &entity.Form{ID: formID, Format: entity.FormatHTML}
+	want := &entity.Form{ID: formID, Format: entity.FormatHTML, Description: "Hello"}
+
+	mockRepo.On("Get", formID).Return(stored, nil)
+	mockRepo.On("UpdateMany", formID, uint(0), want).Return(nil)
+	mockCasher.On("AddToCash", mock.AnythingOfType("*context.timerCtx"), formID.String(), stored).
+		Return(nil)
+	mockPublisher.On("Publish", stored, "form.updated").Return(nil)
+
+	require.NoError(t, service.Update(formID, update))
+
+	mockRepo.AssertExpectations(t)
+	assert.Empty(t, update.Description, "the caller's form is left alone")
+}
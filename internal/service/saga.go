@@ -0,0 +1,40 @@
+package service
+
+import (
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/saga"
+	"go.uber.org/zap"
+)
+
+// SetLogger sets the logger the steps of multi-step operations, see
+// storeNewForm, and failed webhook deliveries are logged with. Without one
+// they aren't logged.
+func (s *Service) SetLogger(log *logger.Logger) {
+	if log == nil || log.Logger == nil {
+		log = &logger.Logger{Logger: zap.NewNop()}
+	}
+	s.logger = log
+}
+
+// storeNewForm reserves the quota of a new form and stores it with store,
+// as the saga name, releasing the quota again when storing fails. The stored
+// form is then cached and form.created published like a created one, see
+// announceForm: once the event may have reached consumers the form is kept,
+// as nothing would tell them it's gone.
+func (s *Service) storeNewForm(name string, form *entity.Form, store func() error) error {
+	questions := len(form.Questions)
+
+	if err := saga.New(name, s.logger).
+		Add("reserve quota", func() error {
+			return s.reserveQuota(form.Author, 1, questions, questions)
+		}, func() error {
+			return s.releaseQuota(form.Author, 1, questions)
+		}).
+		Add("store form", store, nil).
+		Run(); err != nil {
+		return err
+	}
+
+	return s.announceForm(form)
+}
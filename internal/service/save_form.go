@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"gorm.io/gorm"
+)
+
+// SaveForm stores a whole form as an editor saves it: the form is created if
+// it doesn't exist yet, otherwise its questions are inserted, updated and
+// deleted to match form in a single transaction. Questions are identified by
+// ID; questions without one are new. A form carrying a version fails with an
+// *entity.VersionConflictError once another save or update changed it.
+func (s *Service) SaveForm(form *entity.Form) (err error) {
+	defer observe("save_form", time.Now(), &err)
+
+	if form == nil {
+		return errors.New("form cannot be nil")
+	}
+
+	if err := form.Validate(); err != nil {
+		return fmt.Errorf("invalid form: %w", err)
+	}
+
+	// Saves leave the status of forms alone, new ones start as drafts
+	form.Status = entity.StatusDraft
+
+	// Only the author and editors may change an existing form
+	if err := s.authorize(form.ID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err := s.checkQuestionLimit(form.TenantID, len(form.Questions)); err != nil {
+		return err
+	}
+
+	positions := make(map[uint]bool, len(form.Questions))
+
+	for i := range form.Questions {
+		q := &form.Questions[i]
+
+		if positions[q.OrderNumber] {
+			return fmt.Errorf("invalid question: order number %d used twice", q.OrderNumber)
+		}
+		positions[q.OrderNumber] = true
+
+		if err := s.validateQuestion(q); err != nil {
+			return fmt.Errorf("invalid question: %w", err)
+		}
+	}
+
+	// Markup is only stored sanitized, see sanitizeForm
+	if err := sanitizeForm(form); err != nil {
+		return err
+	}
+
+	if err := s.checkTenantRate(form.TenantID); err != nil {
+		return err
+	}
+
+	// New forms get a slug from their title, saves leave the slug of
+	// existing forms alone
+	if form.Slug == nil {
+		err = s.assignSlug(form)
+	} else {
+		err = s.checkSlugChange(form.ID, form.Slug)
+	}
+	if err != nil {
+		return err
+	}
+
+	usage, err := s.saveFormUsage(form)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.previousForm(form.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reserveQuota(usage.author, usage.forms, max(usage.questions, 0), len(form.Questions)); err != nil {
+		return err
+	}
+
+	// 1. Critical operation first (database)
+	changes, err := s.repo.SaveForm(form)
+	if err != nil {
+		s.releaseQuota(usage.author, usage.forms, max(usage.questions, 0))
+		return fmt.Errorf("failed to save form in repository: %w", err)
+	}
+
+	if usage.questions < 0 {
+		if err := s.releaseQuota(usage.author, 0, -usage.questions); err != nil {
+			return err
+		}
+	}
+
+	// 2. Get the stored form, with question IDs assigned
+	stored, err := s.repo.Get(form.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve saved form: %w", err)
+	}
+
+	if changes.Created {
+		return s.syncForm(stored, stored, events.FormCreated)
+	}
+
+	payload, err := s.formUpdate(previous, stored)
+	if err != nil {
+		return err
+	}
+
+	// 3. Cache and publish
+	return s.syncForm(stored, payload, events.FormUpdated)
+}
+
+// quotaUsage is how much a write changes the quota of an author
+type quotaUsage struct {
+	author    string
+	forms     int
+	questions int // Negative when questions are removed
+}
+
+// saveFormUsage computes how saving form changes its author's quota. The
+// stored author owns an existing form, whatever form says.
+func (s *Service) saveFormUsage(form *entity.Form) (quotaUsage, error) {
+	if s.quotas == nil {
+		return quotaUsage{}, nil
+	}
+
+	existing, err := s.repo.Get(form.ID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return quotaUsage{author: form.Author, forms: 1, questions: len(form.Questions)}, nil
+	case err != nil:
+		return quotaUsage{}, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	return quotaUsage{author: existing.Author, questions: len(form.Questions) - len(existing.Questions)}, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_SaveForm(t *testing.T) {
+	t.Run("publishes form.created for new forms", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		form := &entity.Form{ID: uuid.New(), Author: "author", Questions: []entity.Question{{Content: "q", OrderNumber: 1}}}
+		stored := &entity.Form{ID: form.ID, Author: "author", Questions: form.Questions}
+
+		mockRepo.On("SaveForm", form).Return(&entity.FormChanges{Created: true, Inserted: 1}, nil)
+		mockRepo.On("Get", form.ID).Return(stored, nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), stored).Return(nil)
+		mockPublisher.On("Publish", stored, "form.created").Return(nil)
+
+		assert.NoError(t, service.SaveForm(form))
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("publishes form.updated for existing forms", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		form := &entity.Form{ID: uuid.New(), Author: "author"}
+
+		mockRepo.On("SaveForm", form).Return(&entity.FormChanges{Deleted: 2}, nil)
+		mockRepo.On("Get", form.ID).Return(form, nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+		assert.NoError(t, service.SaveForm(form))
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid questions before writing", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		err := service.SaveForm(&entity.Form{ID: uuid.New(), Author: "author", Questions: []entity.Question{
+			{Content: "a", OrderNumber: 1},
+			{Content: "b", OrderNumber: 1},
+		}})
+		assert.ErrorContains(t, err, "used twice")
+
+		err = service.SaveForm(&entity.Form{ID: uuid.New(), Author: "author", Questions: []entity.Question{
+			{Content: "a", OrderNumber: 1, Type: "unknown"},
+		}})
+		assert.ErrorContains(t, err, "invalid question")
+
+		mockRepo.AssertNotCalled(t, "SaveForm", mock.Anything)
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		form := &entity.Form{ID: uuid.New(), Author: "author"}
+
+		mockRepo.On("SaveForm", form).Return(nil, errors.New("db down"))
+
+		assert.ErrorContains(t, service.SaveForm(form), "failed to save form")
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// ScheduleBatch is the number of due forms ApplySchedules handles per query
+const ScheduleBatch = 100
+
+// SetSchedule schedules a form to open and close at the given times, nil
+// for none. A published form opening later is closed until then; drafts
+// and archived forms are left as they are and only closed forms are opened.
+// Schedules are applied by ApplySchedules.
+func (s *Service) SetSchedule(formID uuid.UUID, opensAt, closesAt *time.Time) error {
+	if err := entity.ValidateSchedule(opensAt, closesAt); err != nil {
+		return err
+	}
+
+	return s.changeForm(formID, func(form *entity.Form) error {
+		values := map[string]any{"opens_at": opensAt, "closes_at": closesAt}
+		if opensAt != nil && opensAt.After(time.Now()) && form.Status == entity.StatusPublished {
+			values["status"] = entity.StatusClosed
+		}
+
+		if err := s.repo.UpdateMany(formID, 0, values); err != nil {
+			return fmt.Errorf("failed to update form schedule in repository: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ApplySchedules opens and closes the forms whose schedule is due, then
+// refreshes their cache entry and publishes form.opened or form.closed.
+// It is meant to run periodically, schedules apply at the next run after
+// they are due.
+// Returns the number of forms opened or closed
+func (s *Service) ApplySchedules(ctx context.Context) (int, error) {
+	now := time.Now()
+	applied := 0
+
+	for ctx.Err() == nil {
+		due, err := s.repo.ListDueSchedules(now, ScheduleBatch)
+		if err != nil {
+			return applied, fmt.Errorf("failed to list due schedules: %w", err)
+		}
+
+		batch := 0
+		for i := range due {
+			n, err := s.applySchedule(&due[i], now)
+			batch += n
+			if err != nil {
+				return applied + batch, err
+			}
+		}
+		applied += batch
+
+		// The schedules left were applied by other instances meanwhile
+		if len(due) < ScheduleBatch || batch == 0 {
+			break
+		}
+	}
+
+	return applied, ctx.Err()
+}
+
+// applySchedule applies the due opening, then the due closing, of form
+// Returns the number of transitions applied
+func (s *Service) applySchedule(form *entity.Form, now time.Time) (int, error) {
+	applied := 0
+
+	if form.OpensAt != nil && !form.OpensAt.After(now) {
+		opened, err := s.repo.OpenScheduled(form.ID, now)
+		if err != nil {
+			return applied, fmt.Errorf("failed to open form %s: %w", form.ID, err)
+		}
+		if opened {
+			applied++
+			if err = s.syncScheduled(form.ID, events.FormOpened); err != nil {
+				return applied, err
+			}
+		}
+	}
+
+	if form.ClosesAt != nil && !form.ClosesAt.After(now) {
+		closed, err := s.repo.CloseScheduled(form.ID, now)
+		if err != nil {
+			return applied, fmt.Errorf("failed to close form %s: %w", form.ID, err)
+		}
+		if closed {
+			applied++
+			if err = s.syncScheduled(form.ID, events.FormClosed); err != nil {
+				return applied, err
+			}
+		}
+	}
+
+	return applied, nil
+}
+
+// syncScheduled caches a form a schedule was applied to and publishes it
+// as eventType
+func (s *Service) syncScheduled(formID uuid.UUID, eventType events.Type) error {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	return s.syncForm(form, form, eventType)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_SetSchedule(t *testing.T) {
+	t.Run("closes forms opening later", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		formID := uuid.New()
+		opensAt, closesAt := time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)
+		form := &entity.Form{ID: formID, Status: entity.StatusPublished, OpensAt: &opensAt, ClosesAt: &closesAt}
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockRepo.On("UpdateMany", formID, uint(0), map[string]any{"opens_at": &opensAt, "closes_at": &closesAt, "status": entity.StatusClosed}).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, events.FormUpdated.String()).Return(nil)
+
+		assert.NoError(t, service.SetSchedule(formID, &opensAt, &closesAt))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("leaves drafts as they are", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		formID := uuid.New()
+		opensAt := time.Now().Add(time.Hour)
+		form := &entity.Form{ID: formID, Status: entity.StatusDraft, OpensAt: &opensAt}
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockRepo.On("UpdateMany", formID, uint(0), map[string]any{"opens_at": &opensAt, "closes_at": (*time.Time)(nil)}).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, events.FormUpdated.String()).Return(nil)
+
+		assert.NoError(t, service.SetSchedule(formID, &opensAt, nil))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects closing before opening", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		opensAt := time.Now()
+		closesAt := opensAt.Add(-time.Hour)
+
+		assert.ErrorIs(t, service.SetSchedule(uuid.New(), &opensAt, &closesAt), entity.ErrInvalidSchedule)
+		mockRepo.AssertNotCalled(t, "UpdateMany", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_ApplySchedules(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	past := time.Now().Add(-time.Minute)
+	opening := entity.Form{ID: uuid.New(), Status: entity.StatusClosed, OpensAt: &past}
+	closing := entity.Form{ID: uuid.New(), ClosesAt: &past}
+	raced := entity.Form{ID: uuid.New(), ClosesAt: &past}
+
+	opened := &entity.Form{ID: opening.ID, Status: entity.StatusPublished}
+	closed := &entity.Form{ID: closing.ID, Status: entity.StatusClosed}
+
+	mockRepo.On("ListDueSchedules", mock.AnythingOfType("time.Time"), ScheduleBatch).
+		Return([]entity.Form{opening, closing, raced}, nil)
+	mockRepo.On("OpenScheduled", opening.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	mockRepo.On("CloseScheduled", closing.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+	mockRepo.On("CloseScheduled", raced.ID, mock.AnythingOfType("time.Time")).Return(false, nil) // Applied by another instance
+	mockRepo.On("Get", opening.ID).Return(opened, nil)
+	mockRepo.On("Get", closing.ID).Return(closed, nil)
+	mockCasher.On("AddToCash", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockPublisher.On("Publish", opened, events.FormOpened.String()).Return(nil)
+	mockPublisher.On("Publish", closed, events.FormClosed.String()).Return(nil)
+
+	applied, err := service.ApplySchedules(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	mockRepo.AssertExpectations(t)
+	mockCasher.AssertNumberOfCalls(t, "AddToCash", 2)
+	mockPublisher.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Get", raced.ID)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// ErrSectionNotInForm is returned when a question is moved to a section of
+// another form
+var ErrSectionNotInForm = errors.New("section does not belong to the form of the question")
+
+// CreateSection adds a section to a form. A section without an ID gets a
+// new one, one without a position is appended after the existing sections.
+func (s *Service) CreateSection(section *entity.Section) error {
+	if section == nil {
+		return errors.New("section cannot be nil")
+	}
+
+	if err := section.Validate(); err != nil {
+		return err
+	}
+
+	return s.changeForm(section.FormID, func(form *entity.Form) error {
+		if section.ID == uuid.Nil {
+			section.ID = uuid.New()
+		}
+		if section.OrderNumber == 0 {
+			for i := range form.Sections {
+				section.OrderNumber = max(section.OrderNumber, form.Sections[i].OrderNumber)
+			}
+			section.OrderNumber++
+		}
+		section.CreatedAt = time.Now()
+
+		if err := s.repo.Create(section); err != nil {
+			return fmt.Errorf("failed to create section in repository: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UpdateSection changes the title and description of a section
+func (s *Service) UpdateSection(sectionID uuid.UUID, title, description string) error {
+	if title == "" {
+		return errors.New("section title cannot be empty")
+	}
+
+	section, err := s.repo.GetSection(sectionID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve section: %w", err)
+	}
+
+	return s.changeForm(section.FormID, func(*entity.Form) error {
+		if err := s.repo.UpdateSection(sectionID, map[string]any{
+			"title":       title,
+			"description": description,
+		}); err != nil {
+			return fmt.Errorf("failed to update section in repository: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteSection removes a section. Its questions stay in the form, outside
+// of any section.
+func (s *Service) DeleteSection(sectionID uuid.UUID) error {
+	section, err := s.repo.GetSection(sectionID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve section: %w", err)
+	}
+
+	return s.changeForm(section.FormID, func(*entity.Form) error {
+		if err := s.repo.DeleteSection(sectionID); err != nil {
+			return fmt.Errorf("failed to delete section from repository: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ReorderSections renumbers the sections of a form in the given order. The
+// order must list every section of the form exactly once.
+func (s *Service) ReorderSections(formID uuid.UUID, order []uuid.UUID) error {
+	return s.changeForm(formID, func(form *entity.Form) error {
+		if len(order) != len(form.Sections) {
+			return fmt.Errorf("order lists %d sections, form has %d", len(order), len(form.Sections))
+		}
+
+		seen := make(map[uuid.UUID]bool, len(order))
+		for _, id := range order {
+			if seen[id] {
+				return fmt.Errorf("section %s listed twice", id)
+			}
+			seen[id] = true
+		}
+
+		if err := s.repo.ReorderSections(formID, order); err != nil {
+			return fmt.Errorf("failed to reorder sections in repository: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// MoveQuestion groups a question in a section of its form, or takes it out
+// of its section for a nil sectionID
+func (s *Service) MoveQuestion(questionID uuid.UUID, sectionID *uuid.UUID) error {
+	question, err := s.repo.GetQuestion(questionID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve question: %w", err)
+	}
+
+	if sectionID != nil {
+		section, err := s.repo.GetSection(*sectionID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve section: %w", err)
+		}
+
+		if section.FormID != question.FormID {
+			return fmt.Errorf("%w: section %s", ErrSectionNotInForm, section.ID)
+		}
+	}
+
+	return s.changeForm(question.FormID, func(*entity.Form) error {
+		if err := s.repo.MoveQuestion(questionID, sectionID); err != nil {
+			return fmt.Errorf("failed to move question in repository: %w", err)
+		}
+
+		return nil
+	})
+}
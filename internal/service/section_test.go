@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreateSection(t *testing.T) {
+	t.Run("appends the section and publishes the form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		form := &entity.Form{ID: uuid.New(), Sections: []entity.Section{{OrderNumber: 1}, {OrderNumber: 3}}}
+		section := &entity.Section{FormID: form.ID, Title: "Work"}
+
+		mockRepo.On("Get", form.ID).Return(form, nil)
+		mockRepo.On("Create", section).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+		require.NoError(t, service.CreateSection(section))
+		assert.NotEqual(t, uuid.Nil, section.ID)
+		assert.EqualValues(t, 4, section.OrderNumber)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects sections without a title", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		err := service.CreateSection(&entity.Section{FormID: uuid.New()})
+
+		assert.ErrorContains(t, err, "title")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestService_ReorderSections(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	form := &entity.Form{ID: uuid.New(), Sections: []entity.Section{{ID: first}, {ID: second}}}
+
+	t.Run("renumbers the sections", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		order := []uuid.UUID{second, first}
+
+		mockRepo.On("Get", form.ID).Return(form, nil)
+		mockRepo.On("ReorderSections", form.ID, order).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+		require.NoError(t, service.ReorderSections(form.ID, order))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("requires every section once", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockRepo.On("Get", form.ID).Return(form, nil)
+
+		assert.Error(t, service.ReorderSections(form.ID, []uuid.UUID{first}))
+		assert.Error(t, service.ReorderSections(form.ID, []uuid.UUID{first, first}))
+		mockRepo.AssertNotCalled(t, "ReorderSections", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_MoveQuestion(t *testing.T) {
+	formID := uuid.New()
+	section := &entity.Section{ID: uuid.New(), FormID: uuid.New()}
+
+	service, _, mockRepo, _ := setupService()
+	mockRepo.On("GetQuestion", questionID(7)).Return(&entity.Question{FormID: formID}, nil)
+	mockRepo.On("GetSection", section.ID).Return(section, nil)
+
+	err := service.MoveQuestion(questionID(7), &section.ID)
+
+	assert.ErrorIs(t, err, ErrSectionNotInForm)
+	mockRepo.AssertNotCalled(t, "MoveQuestion", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultSettingsTTL is how long resolved tenant settings are cached
+const DefaultSettingsTTL = time.Minute
+
+// ErrLimitExceeded is returned when an operation breaks a tenant limit
+var ErrLimitExceeded = errors.New("tenant limit exceeded")
+
+type (
+	// cachedSettings is a resolved settings entry of the settings cache
+	cachedSettings struct {
+		settings  entity.Settings
+		expiresAt time.Time
+	}
+
+	// tenantSettings resolves per-tenant settings from the repository over
+	// global defaults and caches the result in memory
+	tenantSettings struct {
+		repo     SettingsRepository
+		defaults entity.Settings
+		ttl      time.Duration
+
+		mu    sync.Mutex
+		cache map[string]cachedSettings
+	}
+)
+
+// EnableTenantSettings makes the service resolve settings per tenant, falling
+// back to defaults for tenants without overrides. Without it every tenant
+// uses defaults.
+func (s *Service) EnableTenantSettings(repo SettingsRepository, defaults entity.Settings, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultSettingsTTL
+	}
+
+	s.settings = &tenantSettings{
+		repo:     repo,
+		defaults: defaults,
+		ttl:      ttl,
+		cache:    make(map[string]cachedSettings),
+	}
+}
+
+// Settings returns the effective settings of a tenant. A failing repository
+// falls back to the defaults rather than failing the request.
+func (s *Service) Settings(tenantID string) entity.Settings {
+	if s.settings == nil {
+		return entity.Settings{TenantID: tenantID}
+	}
+
+	return s.settings.get(tenantID)
+}
+
+// UpdateTenantSettings replaces the overrides of a tenant.
+func (s *Service) UpdateTenantSettings(overrides *entity.TenantSettings) error {
+	if s.settings == nil {
+		return errors.New("tenant settings are not enabled")
+	}
+
+	if overrides.TenantID == "" {
+		return errors.New("tenant id cannot be empty")
+	}
+
+	if err := s.settings.repo.SaveTenantSettings(overrides); err != nil {
+		return fmt.Errorf("failed to save tenant settings: %w", err)
+	}
+
+	s.settings.invalidate(overrides.TenantID)
+
+	effective := s.settings.get(overrides.TenantID)
+
+	if err := s.publish(events.TenantSettingsUpdated, &effective); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
+
+// checkQuestionLimit fails when a form of the tenant would hold more questions than allowed
+func (s *Service) checkQuestionLimit(tenantID string, questions int) error {
+	limit := s.Settings(tenantID).MaxQuestions
+	if limit > 0 && questions > limit {
+		return fmt.Errorf("%w: at most %d questions per form", ErrLimitExceeded, limit)
+	}
+
+	return nil
+}
+
+// checkFormQuestionLimit fails when one more question would break the limit of the form's tenant
+func (s *Service) checkFormQuestionLimit(formID uuid.UUID) error {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	count, err := s.repo.CountQuestions(formID)
+	if err != nil {
+		return fmt.Errorf("failed to count questions: %w", err)
+	}
+
+	return s.checkQuestionLimit(form.TenantID, int(count)+1)
+}
+
+func (t *tenantSettings) get(tenantID string) entity.Settings {
+	t.mu.Lock()
+	cached, ok := t.cache[tenantID]
+	t.mu.Unlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.settings
+	}
+
+	settings := t.defaults
+	settings.TenantID = tenantID
+
+	overrides, err := t.repo.GetTenantSettings(tenantID)
+	switch {
+	case err == nil:
+		settings = overrides.Apply(t.defaults)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		// Don't cache a fallback caused by a transient error
+		return settings
+	}
+
+	t.mu.Lock()
+	t.cache[tenantID] = cachedSettings{settings: settings, expiresAt: time.Now().Add(t.ttl)}
+	t.mu.Unlock()
+
+	return settings
+}
+
+func (t *tenantSettings) invalidate(tenantID string) {
+	t.mu.Lock()
+	delete(t.cache, tenantID)
+	t.mu.Unlock()
+}
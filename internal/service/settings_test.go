@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockSettingsRepository is a mock implementation of the SettingsRepository interface
+type MockSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockSettingsRepository) GetTenantSettings(tenantID string) (*entity.TenantSettings, error) {
+	args := m.Called(tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TenantSettings), args.Error(1)
+}
+
+func (m *MockSettingsRepository) SaveTenantSettings(settings *entity.TenantSettings) error {
+	args := m.Called(settings)
+	return args.Error(0)
+}
+
+var testDefaults = entity.Settings{RateLimit: 60, MaxQuestions: 50, RetentionDays: 365}
+
+func TestService_Settings(t *testing.T) {
+	t.Run("overrides apply over defaults and are cached", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		mockSettings := &MockSettingsRepository{}
+		service.EnableTenantSettings(mockSettings, testDefaults, time.Minute)
+
+		limit := 5
+		mockSettings.On("GetTenantSettings", "acme").
+			Return(&entity.TenantSettings{TenantID: "acme", MaxQuestions: &limit}, nil).Once()
+
+		for range 2 {
+			settings := service.Settings("acme")
+			assert.Equal(t, entity.Settings{TenantID: "acme", RateLimit: 60, MaxQuestions: 5, RetentionDays: 365}, settings)
+		}
+		mockSettings.AssertExpectations(t)
+	})
+
+	t.Run("tenant without overrides uses defaults", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		mockSettings := &MockSettingsRepository{}
+		service.EnableTenantSettings(mockSettings, testDefaults, time.Minute)
+
+		mockSettings.On("GetTenantSettings", "other").Return(nil, gorm.ErrRecordNotFound)
+
+		assert.Equal(t, 50, service.Settings("other").MaxQuestions)
+	})
+
+	t.Run("repository errors fall back without caching", func(t *testing.T) {
+		service, _, _, _ := setupService()
+		mockSettings := &MockSettingsRepository{}
+		service.EnableTenantSettings(mockSettings, testDefaults, time.Minute)
+
+		mockSettings.On("GetTenantSettings", "acme").Return(nil, errors.New("db down"))
+
+		assert.Equal(t, 50, service.Settings("acme").MaxQuestions)
+		assert.Equal(t, 50, service.Settings("acme").MaxQuestions)
+		mockSettings.AssertNumberOfCalls(t, "GetTenantSettings", 2)
+	})
+}
+
+func TestService_UpdateTenantSettings(t *testing.T) {
+	service, _, _, mockPublisher := setupService()
+	mockSettings := &MockSettingsRepository{}
+	service.EnableTenantSettings(mockSettings, testDefaults, time.Minute)
+
+	limit := 5
+	overrides := &entity.TenantSettings{TenantID: "acme", MaxQuestions: &limit}
+
+	mockSettings.On("GetTenantSettings", "acme").Return(nil, gorm.ErrRecordNotFound).Once()
+	assert.Equal(t, 50, service.Settings("acme").MaxQuestions)
+
+	mockSettings.On("SaveTenantSettings", overrides).Return(nil)
+	mockSettings.On("GetTenantSettings", "acme").Return(overrides, nil)
+	mockPublisher.On("Publish", mock.AnythingOfType("*entity.Settings"), events.TenantSettingsUpdated.String()).Return(nil)
+
+	require.NoError(t, service.UpdateTenantSettings(overrides))
+	assert.Equal(t, 5, service.Settings("acme").MaxQuestions, "cache is invalidated on update")
+}
+
+func TestService_QuestionLimit(t *testing.T) {
+	limit := 2
+	overrides := &entity.TenantSettings{TenantID: "acme", MaxQuestions: &limit}
+
+	t.Run("create form over the limit", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockSettings := &MockSettingsRepository{}
+		service.EnableTenantSettings(mockSettings, testDefaults, time.Minute)
+		mockSettings.On("GetTenantSettings", "acme").Return(overrides, nil)
+
+		form := &entity.Form{ID: uuid.New(), TenantID: "acme", Questions: make([]entity.Question, 3)}
+
+		err := service.CreateForm(form)
+
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("create question over the limit", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockSettings := &MockSettingsRepository{}
+		service.EnableTenantSettings(mockSettings, testDefaults, time.Minute)
+		mockSettings.On("GetTenantSettings", "acme").Return(overrides, nil)
+
+		formID := uuid.New()
+		question := &entity.Question{FormID: formID}
+
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, TenantID: "acme"}, nil)
+		mockRepo.On("CountQuestions", formID).Return(int64(2), nil)
+
+		err := service.CreateQuestion(question)
+
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+		mockRepo.AssertNotCalled(t, "Create", question)
+	})
+}
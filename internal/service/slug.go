@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// slugSuffixLength is the length of the form ID prefix telling apart forms
+// whose titles give the same slug
+const slugSuffixLength = 8
+
+// ErrSlugTaken is returned when a form is given the slug of another form
+var ErrSlugTaken = errors.New("slug is taken")
+
+// assignSlug gives a form without a slug one made from its title, and checks
+// that a slug chosen by the author is valid and free. Titles giving a slug
+// already taken get the start of the form ID appended, so the form still
+// gets a readable slug; the unique index on the column settles the rare
+// race between two forms taking the same slug.
+func (s *Service) assignSlug(form *entity.Form) error {
+	if form.Slug != nil {
+		if err := entity.ValidateSlug(*form.Slug); err != nil {
+			return err
+		}
+
+		taken, err := s.repo.SlugExists(*form.Slug)
+		if err != nil {
+			return fmt.Errorf("failed to check slug: %w", err)
+		}
+		if taken {
+			return fmt.Errorf("%w: %s", ErrSlugTaken, *form.Slug)
+		}
+
+		return nil
+	}
+
+	slug := entity.Slugify(form.Title, entity.MaxSlugLength-slugSuffixLength-1)
+
+	taken, err := s.repo.SlugExists(slug)
+	if err != nil {
+		return fmt.Errorf("failed to check slug: %w", err)
+	}
+	if taken {
+		slug += "-" + form.ID.String()[:slugSuffixLength]
+	}
+
+	form.Slug = &slug
+
+	return nil
+}
+
+// checkSlugChange checks the slug an update gives a form, nil for none
+func (s *Service) checkSlugChange(formID uuid.UUID, slug *string) error {
+	if slug == nil {
+		return nil
+	}
+
+	if err := entity.ValidateSlug(*slug); err != nil {
+		return err
+	}
+
+	current, err := s.repo.GetBySlug(*slug)
+	switch {
+	case err == nil && current.ID != formID:
+		return fmt.Errorf("%w: %s", ErrSlugTaken, *slug)
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("failed to check slug: %w", err)
+	}
+
+	return nil
+}
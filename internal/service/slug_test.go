@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newSlugService is setupService without the free slugs, for tests taking
+// them
+func newSlugService() (*Service, *MockCasher, *MockRepository, *MockPublisher) {
+	mockCasher := &MockCasher{}
+	mockRepo := &MockRepository{}
+	mockPublisher := &MockPublisher{}
+	return Init(mockCasher, mockRepo, mockPublisher, 5*time.Second), mockCasher, mockRepo, mockPublisher
+}
+
+func TestService_CreateForm_Slug(t *testing.T) {
+	create := func(form *entity.Form, taken bool) error {
+		service, mockCasher, mockRepo, mockPublisher := newSlugService()
+
+		mockRepo.On("SlugExists", mock.Anything).Return(taken, nil)
+		mockRepo.On("Create", form).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.created").Return(nil)
+
+		return service.CreateForm(form)
+	}
+
+	t.Run("derives the slug from the title", func(t *testing.T) {
+		form := &entity.Form{ID: uuid.New(), Title: "Customer Survey 2024"}
+
+		require.NoError(t, create(form, false))
+		require.NotNil(t, form.Slug)
+		assert.Equal(t, "customer-survey-2024", *form.Slug)
+	})
+
+	t.Run("tells apart titles giving a taken slug", func(t *testing.T) {
+		form := &entity.Form{ID: uuid.New(), Title: "Customer Survey 2024"}
+
+		require.NoError(t, create(form, true))
+		require.NotNil(t, form.Slug)
+		assert.Equal(t, "customer-survey-2024-"+form.ID.String()[:8], *form.Slug)
+	})
+
+	t.Run("keeps a chosen slug", func(t *testing.T) {
+		slug := "feedback"
+		form := &entity.Form{ID: uuid.New(), Title: "Customer Survey", Slug: &slug}
+
+		require.NoError(t, create(form, false))
+		assert.Equal(t, "feedback", *form.Slug)
+	})
+
+	t.Run("rejects a taken slug", func(t *testing.T) {
+		slug := "feedback"
+		form := &entity.Form{ID: uuid.New(), Slug: &slug}
+
+		assert.ErrorIs(t, create(form, true), ErrSlugTaken)
+	})
+
+	t.Run("rejects an invalid slug", func(t *testing.T) {
+		slug := "Not A Slug"
+		form := &entity.Form{ID: uuid.New(), Slug: &slug}
+
+		assert.ErrorIs(t, create(form, false), entity.ErrInvalidSlug)
+	})
+}
+
+func TestService_Update_Slug(t *testing.T) {
+	formID := uuid.New()
+	slug := "feedback"
+
+	t.Run("rejects the slug of another form", func(t *testing.T) {
+		service, _, mockRepo, _ := newSlugService()
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID}, nil)
+		mockRepo.On("GetBySlug", slug).Return(&entity.Form{ID: uuid.New()}, nil)
+
+		err := service.Update(formID, &entity.Form{ID: formID, Slug: &slug})
+		assert.ErrorIs(t, err, ErrSlugTaken)
+		mockRepo.AssertNotCalled(t, "UpdateMany", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("takes a free slug", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := newSlugService()
+		form := &entity.Form{ID: formID, Slug: &slug}
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockRepo.On("GetBySlug", slug).Return(nil, gorm.ErrRecordNotFound)
+		mockRepo.On("UpdateMany", formID, uint(0), mock.Anything).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+		require.NoError(t, service.Update(formID, &entity.Form{ID: formID, Slug: &slug}))
+		mockRepo.AssertCalled(t, "UpdateMany", formID, uint(0), mock.Anything)
+	})
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// MaxStatValueLength bounds the stored length of an answer bucket
+const MaxStatValueLength = 255
+
+// Counter fields of a form: responses counts accepted responses, q:<id>
+// answers to a question and q:<id>:<value> answers in a bucket
+const (
+	statsResponsesField = "responses"
+	statsQuestionPrefix = "q:"
+)
+
+// emptyAnswers are answer values that don't count as answering a question
+var emptyAnswers = [][]byte{[]byte("null"), []byte(`""`), []byte("[]"), []byte("{}")}
+
+// questionStats maintains per-question answer counts incrementally
+type questionStats struct {
+	store StatsStore
+	repo  StatsRepository
+}
+
+// EnableQuestionStats counts the answers of accepted responses in store and
+// persists them to repo with PersistQuestionStats. Without it no statistics
+// are kept.
+func (s *Service) EnableQuestionStats(store StatsStore, repo StatsRepository) {
+	s.stats = &questionStats{
+		store: store,
+		repo:  repo,
+	}
+}
+
+// recordAnswerStats counts the answers of an accepted response
+func (s *Service) recordAnswerStats(form *entity.Form, answers []entity.Answer) error {
+	if s.stats == nil {
+		return nil
+	}
+
+	byOrder := make(map[uint]*entity.Question, len(form.Questions))
+	for i := range form.Questions {
+		byOrder[form.Questions[i].OrderNumber] = &form.Questions[i]
+	}
+
+	fields := map[string]int64{statsResponsesField: 1}
+
+	for _, answer := range answers {
+		q, ok := byOrder[answer.OrderNumber]
+		if !ok || isEmptyAnswer(answer.Value) {
+			continue
+		}
+
+		fields[statsField(q.ID, "")]++
+
+		for _, bucket := range s.questions.Buckets(q, answer.Value) {
+			fields[statsField(q.ID, bucket)]++
+		}
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	return s.cacheRetry.do(ctx, func() error {
+		return s.stats.store.Incr(ctx, form.ID.String(), fields)
+	})
+}
+
+// QuestionStats returns the answer statistics of every question of a form,
+// combining persisted counts with those not persisted yet
+func (s *Service) QuestionStats(formID uuid.UUID) (*entity.QuestionStatsReport, error) {
+	if s.stats == nil {
+		return nil, errors.New("question stats are not enabled")
+	}
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeReader(form); err != nil {
+		return nil, err
+	}
+
+	persisted, err := s.stats.repo.ListQuestionStats(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve question stats: %w", err)
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	pending, err := s.stats.store.Get(ctx, formID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pending question stats: %w", err)
+	}
+
+	totals := make(map[string]int64, len(persisted)+len(pending))
+	for _, stat := range persisted {
+		totals[statsField(stat.QuestionID, stat.Value)] += stat.Total
+	}
+	for field, amount := range pending {
+		totals[field] += amount
+	}
+
+	report := &entity.QuestionStatsReport{
+		FormID:    formID.String(),
+		Responses: totals[statsResponsesField],
+		Questions: make([]entity.QuestionStats, 0, len(form.Questions)),
+	}
+
+	for _, q := range form.Questions {
+		stats := entity.QuestionStats{
+			QuestionID:  q.ID,
+			OrderNumber: q.OrderNumber,
+			Type:        q.Type,
+			Answered:    totals[statsField(q.ID, "")],
+		}
+
+		prefix := statsField(q.ID, "") + ":"
+		for field, amount := range totals {
+			bucket, ok := strings.CutPrefix(field, prefix)
+			if !ok {
+				continue
+			}
+
+			if stats.Distribution == nil {
+				stats.Distribution = make(map[string]int64)
+			}
+			stats.Distribution[bucket] = amount
+		}
+
+		report.Questions = append(report.Questions, stats)
+	}
+
+	return report, nil
+}
+
+// PublishQuestionStats sends question statistics back to the requester
+func (s *Service) PublishQuestionStats(report *entity.QuestionStatsReport) error {
+	return s.publish(events.FormQuestionStats, report)
+}
+
+// PersistQuestionStats moves the counts not persisted yet into the stats
+// table. Counts that fail to persist are put back for the next run.
+// Returns the number of forms whose counts were persisted.
+func (s *Service) PersistQuestionStats(ctx context.Context) (int, error) {
+	if s.stats == nil {
+		return 0, nil
+	}
+
+	drained, err := s.stats.store.Drain(ctx, 0)
+
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	persisted := 0
+
+	for key, fields := range drained {
+		formID, err := uuid.Parse(key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid stats key %q: %w", key, err))
+			continue
+		}
+
+		if err := s.stats.repo.AddQuestionStats(formID, statsRows(fields)); err != nil {
+			if restoreErr := s.stats.store.Incr(ctx, key, fields); restoreErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to restore stats of form %s: %w", key, restoreErr))
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		persisted++
+	}
+
+	return persisted, errors.Join(errs...)
+}
+
+// statsField is the counter field of a question, or of one of its buckets
+func statsField(questionID uuid.UUID, value string) string {
+	if questionID == uuid.Nil {
+		return statsResponsesField
+	}
+
+	field := statsQuestionPrefix + questionID.String()
+	if value == "" {
+		return field
+	}
+
+	if utf8.RuneCountInString(value) > MaxStatValueLength {
+		value = string([]rune(value)[:MaxStatValueLength])
+	}
+
+	return field + ":" + value
+}
+
+// statsRows converts counter fields to stats table rows
+func statsRows(fields map[string]int64) []entity.QuestionStat {
+	rows := make([]entity.QuestionStat, 0, len(fields))
+
+	for field, amount := range fields {
+		if field == statsResponsesField {
+			rows = append(rows, entity.QuestionStat{Total: amount})
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(field, statsQuestionPrefix)
+		if !ok {
+			continue
+		}
+
+		id, value, _ := strings.Cut(rest, ":")
+
+		questionID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+
+		rows = append(rows, entity.QuestionStat{QuestionID: questionID, Value: value, Total: amount})
+	}
+
+	return rows
+}
+
+func isEmptyAnswer(value []byte) bool {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 {
+		return true
+	}
+
+	for _, empty := range emptyAnswers {
+		if bytes.Equal(value, empty) {
+			return true
+		}
+	}
+
+	return false
+}
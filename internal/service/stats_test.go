@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockStatsRepository is a mock implementation of the StatsRepository interface
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepository) AddQuestionStats(formID uuid.UUID, stats []entity.QuestionStat) error {
+	args := m.Called(formID, stats)
+	return args.Error(0)
+}
+
+func (m *MockStatsRepository) ListQuestionStats(formID uuid.UUID) ([]entity.QuestionStat, error) {
+	args := m.Called(formID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.QuestionStat), args.Error(1)
+}
+
+// memoryStatsStore is an in-memory StatsStore
+type memoryStatsStore struct {
+	counters map[string]map[string]int64
+}
+
+func newMemoryStatsStore() *memoryStatsStore {
+	return &memoryStatsStore{counters: make(map[string]map[string]int64)}
+}
+
+func (m *memoryStatsStore) Incr(_ context.Context, key string, fields map[string]int64) error {
+	if m.counters[key] == nil {
+		m.counters[key] = make(map[string]int64)
+	}
+	for field, amount := range fields {
+		m.counters[key][field] += amount
+	}
+	return nil
+}
+
+func (m *memoryStatsStore) Get(_ context.Context, key string) (map[string]int64, error) {
+	return m.counters[key], nil
+}
+
+func (m *memoryStatsStore) Drain(context.Context, int) (map[string]map[string]int64, error) {
+	drained := m.counters
+	m.counters = make(map[string]map[string]int64)
+	return drained, nil
+}
+
+func statsForm(formID uuid.UUID) *entity.Form {
+	form := responseForm(formID)
+	for i := range form.Questions {
+		form.Questions[i].ID = questionID(uint(i + 1))
+	}
+	return form
+}
+
+func TestService_QuestionStats(t *testing.T) {
+	service, _, mockRepo, mockPublisher := setupService()
+	store := newMemoryStatsStore()
+	statsRepo := &MockStatsRepository{}
+	service.EnableQuestionStats(store, statsRepo)
+
+	formID := uuid.New()
+	mockRepo.On("Get", formID).Return(statsForm(formID), nil)
+	mockPublisher.On("Publish", mock.Anything, events.ResponseAccepted.String()).Return(nil)
+
+	for _, choice := range []string{`"a"`, `"b"`, `"a"`} {
+		require.NoError(t, service.SubmitResponse(&entity.ResponseSubmission{
+			FormID: formID.String(),
+			Answers: []entity.Answer{
+				{OrderNumber: 1, Value: json.RawMessage(`"hello"`)},
+				{OrderNumber: 3, Value: json.RawMessage(choice)},
+			},
+		}))
+	}
+
+	first, third := "q:"+questionID(1).String(), "q:"+questionID(3).String()
+	assert.Equal(t, map[string]int64{"responses": 3, first: 3, third: 3, third + ":a": 2, third + ":b": 1}, store.counters[formID.String()])
+
+	t.Run("persists the pending counts", func(t *testing.T) {
+		var rows []entity.QuestionStat
+		statsRepo.On("AddQuestionStats", formID, mock.Anything).Run(func(args mock.Arguments) {
+			rows = args.Get(1).([]entity.QuestionStat)
+		}).Return(nil).Once()
+
+		persisted, err := service.PersistQuestionStats(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, persisted)
+		assert.Empty(t, store.counters)
+		assert.ElementsMatch(t, []entity.QuestionStat{
+			{Total: 3},
+			{QuestionID: questionID(1), Total: 3},
+			{QuestionID: questionID(3), Total: 3},
+			{QuestionID: questionID(3), Value: "a", Total: 2},
+			{QuestionID: questionID(3), Value: "b", Total: 1},
+		}, rows)
+	})
+
+	t.Run("puts the counts back when persisting fails", func(t *testing.T) {
+		require.NoError(t, store.Incr(context.Background(), formID.String(), map[string]int64{"responses": 1}))
+		statsRepo.On("AddQuestionStats", formID, mock.Anything).Return(errors.New("db down")).Once()
+
+		_, err := service.PersistQuestionStats(context.Background())
+
+		assert.Error(t, err)
+		assert.Equal(t, map[string]int64{"responses": 1}, store.counters[formID.String()])
+	})
+
+	t.Run("combines persisted and pending counts", func(t *testing.T) {
+		statsRepo.On("ListQuestionStats", formID).Return([]entity.QuestionStat{
+			{FormID: formID, Total: 3},
+			{FormID: formID, QuestionID: questionID(3), Total: 3},
+			{FormID: formID, QuestionID: questionID(3), Value: "a", Total: 2},
+			{FormID: formID, QuestionID: questionID(3), Value: "b", Total: 1},
+		}, nil)
+
+		report, err := service.QuestionStats(formID)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(4), report.Responses)
+		require.Len(t, report.Questions, 3)
+		assert.Equal(t, entity.QuestionStats{
+			QuestionID:   questionID(3),
+			OrderNumber:  3,
+			Type:         "choice",
+			Answered:     3,
+			Distribution: map[string]int64{"a": 2, "b": 1},
+		}, report.Questions[2])
+		assert.Nil(t, report.Questions[0].Distribution, "text keeps no distribution")
+	})
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// SetStatus moves a form to another state of its lifecycle, see
+// entity.ValidateTransition, then caches the form and publishes the
+// transition: form.opened, form.closed, form.archived or, for archived
+// forms coming back, form.unarchived. Publishing a draft snapshots its
+// first version as PublishForm does.
+// Returns entity.ErrInvalidTransition if the form can't move to status
+func (s *Service) SetStatus(formID uuid.UUID, status string) (err error) {
+	defer observe("set_status", time.Now(), &err)
+
+	return s.setStatus(formID, status, "")
+}
+
+// setStatus moves a form to status, if it is in state from or, when
+// empty, any state status can be reached from
+func (s *Service) setStatus(formID uuid.UUID, status, from string) error {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeForm(form); err != nil {
+		return err
+	}
+
+	if from != "" && form.Status != from {
+		return fmt.Errorf("%w: form %s is %s, not %s", entity.ErrInvalidTransition, formID, form.Status, from)
+	}
+
+	if err = entity.ValidateTransition(form.Status, status); err != nil {
+		return err
+	}
+
+	if form.Status == entity.StatusDraft && status == entity.StatusPublished {
+		_, err = s.PublishForm(formID)
+		return err
+	}
+
+	eventType := statusEvents[status]
+	if form.Status == entity.StatusArchived {
+		eventType = events.FormUnarchived
+	}
+
+	// 1. Critical operation first (database), failing if the form changed meanwhile
+	if err = s.repo.Update(formID, form.Version, "Status", status); err != nil {
+		return fmt.Errorf("failed to update form status in repository: %w", err)
+	}
+
+	if form, err = s.repo.Get(formID); err != nil {
+		return fmt.Errorf("failed to retrieve updated form: %w", err)
+	}
+
+	// 2. Cache and announce the form
+	return s.syncForm(form, form, eventType)
+}
+
+// statusEvents are the events forms moving to each state are published as
+var statusEvents = map[string]events.Type{
+	entity.StatusPublished: events.FormOpened,
+	entity.StatusClosed:    events.FormClosed,
+	entity.StatusArchived:  events.FormArchived,
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestService_SetStatus(t *testing.T) {
+	t.Run("closes and reopens forms", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		formID := uuid.New()
+		published := &entity.Form{ID: formID, Status: entity.StatusPublished, Version: 2}
+		closed := &entity.Form{ID: formID, Status: entity.StatusClosed, Version: 3}
+		reopened := &entity.Form{ID: formID, Status: entity.StatusPublished, Version: 4}
+
+		mockRepo.On("Get", formID).Return(published, nil).Once()
+		mockRepo.On("Update", formID, uint(2), "Status", entity.StatusClosed).Return(nil)
+		mockRepo.On("Get", formID).Return(closed, nil).Twice()
+		mockRepo.On("Update", formID, uint(3), "Status", entity.StatusPublished).Return(nil)
+		mockRepo.On("Get", formID).Return(reopened, nil).Once()
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), mock.AnythingOfType("*entity.Form")).Return(nil)
+		mockPublisher.On("Publish", closed, events.FormClosed.String()).Return(nil)
+		mockPublisher.On("Publish", reopened, events.FormOpened.String()).Return(nil)
+
+		assert.NoError(t, service.SetStatus(formID, entity.StatusClosed))
+		assert.NoError(t, service.SetStatus(formID, entity.StatusPublished))
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("publishes drafts with their first version", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		formID := uuid.New()
+		draft := &entity.Form{ID: formID, Status: entity.StatusDraft, Version: 1}
+		published := &entity.Form{ID: formID, Status: entity.StatusPublished, Version: 2}
+		version := &entity.FormVersion{FormID: formID, Version: 1}
+
+		mockRepo.On("Get", formID).Return(draft, nil).Twice()
+		mockRepo.On("Update", formID, uint(1), "Status", entity.StatusPublished).Return(nil)
+		mockRepo.On("CreateFormVersion", formID).Return(version, nil)
+		mockRepo.On("Get", formID).Return(published, nil).Once()
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), published).Return(nil)
+		mockPublisher.On("Publish", version, events.FormPublished.String()).Return(nil)
+		mockPublisher.On("Publish", published, events.FormOpened.String()).Return(nil)
+
+		assert.NoError(t, service.SetStatus(formID, entity.StatusPublished))
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("refuses transitions the lifecycle doesn't allow", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+
+		formID := uuid.New()
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Status: entity.StatusPublished}, nil)
+
+		assert.ErrorIs(t, service.SetStatus(formID, entity.StatusDraft), entity.ErrInvalidTransition)
+		assert.ErrorIs(t, service.SetStatus(formID, "open"), entity.ErrInvalidStatus)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_CreateForm_Status(t *testing.T) {
+	t.Run("starts forms as drafts", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		form := &entity.Form{ID: uuid.New(), Author: "author"}
+		mockRepo.On("Create", form).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, events.FormCreated.String()).Return(nil)
+
+		assert.NoError(t, service.CreateForm(form))
+		assert.Equal(t, entity.StatusDraft, form.Status)
+	})
+
+	t.Run("refuses forms created in another state", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		form := &entity.Form{ID: uuid.New(), Author: "author", Status: entity.StatusPublished}
+
+		assert.ErrorIs(t, service.CreateForm(form), entity.ErrInvalidTransition)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestService_Update_KeepsStatus(t *testing.T) {
+	service, mockCasher, mockRepo, mockPublisher := setupService()
+
+	formID := uuid.New()
+	stored := &entity.Form{ID: formID, Title: "New", Status: entity.StatusClosed}
+	mockRepo.On("UpdateMany", formID, uint(0), mock.MatchedBy(func(form *entity.Form) bool {
+		return form.Title == "New" && form.Status == ""
+	})).Return(nil)
+	mockRepo.On("Get", formID).Return(stored, nil)
+	mockCasher.On("AddToCash", mock.Anything, formID.String(), stored).Return(nil)
+	mockPublisher.On("Publish", stored, events.FormUpdated.String()).Return(nil)
+
+	err := service.Update(formID, &entity.Form{ID: formID, Title: "New", Status: entity.StatusPublished})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
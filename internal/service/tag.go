@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// Page sizes of form listings
+const (
+	DefaultFormPageSize = 50
+	MaxFormPageSize     = 500
+)
+
+// SetTags replaces the tags of a form. Names are normalized, see
+// entity.NormalizeTags; an empty list untags the form.
+func (s *Service) SetTags(formID uuid.UUID, names []string) error {
+	tags, err := entity.NormalizeTags(names)
+	if err != nil {
+		return err
+	}
+
+	return s.changeForm(formID, func(_ *entity.Form) error {
+		if err := s.repo.SetFormTags(formID, tags); err != nil {
+			return fmt.Errorf("failed to store form tags in repository: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ListFormsByTag returns a page of the forms of a tenant with a tag, oldest
+// first, narrowed down by filter unless nil. A limit of 0 returns
+// DefaultFormPageSize forms; larger limits than MaxFormPageSize are capped.
+func (s *Service) ListFormsByTag(tenantID, tag string, filter *entity.Filter, offset, limit int) (*entity.FormList, error) {
+	tags, err := entity.NormalizeTags([]string{tag})
+	if err != nil {
+		return nil, err
+	}
+
+	if filter != nil {
+		if err = filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	list := &entity.FormList{
+		Offset: max(offset, 0),
+		Forms:  []entity.OutputForm{},
+	}
+	if len(tags) == 0 {
+		return list, nil // No form has an empty tag
+	}
+	list.Tag = tags[0].Name
+
+	if limit <= 0 {
+		limit = DefaultFormPageSize
+	}
+	limit = min(limit, MaxFormPageSize)
+
+	params := fmt.Sprintf("by_tag:%s:%s:%d:%d:%s", tenantID, list.Tag, list.Offset, limit, filter.Key())
+
+	return cachedQuery(s, queryScopeForms, params, func() (*entity.FormList, error) {
+		if list.Total, err = s.repo.CountFormsByTag(tenantID, list.Tag, filter); err != nil {
+			return nil, fmt.Errorf("failed to count forms: %w", err)
+		}
+
+		forms, err := s.repo.ListFormsByTag(tenantID, list.Tag, filter, list.Offset, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list forms: %w", err)
+		}
+
+		for i := range forms {
+			list.Forms = append(list.Forms, forms[i].ToOutput())
+		}
+
+		return list, nil
+	})
+}
+
+// PublishFormList sends a form listing back to the requester
+func (s *Service) PublishFormList(list *entity.FormList) error {
+	return s.publish(events.FormList, list)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_SetTags(t *testing.T) {
+	t.Run("stores normalized tags and publishes the form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		form := &entity.Form{ID: uuid.New()}
+
+		mockRepo.On("Get", form.ID).Return(form, nil)
+		mockRepo.On("SetFormTags", form.ID, []entity.Tag{{Name: "hr"}, {Name: "q3 survey"}}).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, form.ID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, "form.updated").Return(nil)
+
+		require.NoError(t, service.SetTags(form.ID, []string{" HR ", "hr", "", "Q3 Survey"}))
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects overlong tags", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		err := service.SetTags(uuid.New(), []string{strings.Repeat("x", entity.MaxTagLength+1)})
+
+		assert.ErrorIs(t, err, entity.ErrInvalidTag)
+		mockRepo.AssertNotCalled(t, "Get", mock.Anything)
+	})
+}
+
+func TestService_ListFormsByTag(t *testing.T) {
+	t.Run("pages the forms with the tag", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		form := entity.Form{ID: uuid.New(), Tags: []entity.Tag{{Name: "hr"}}}
+
+		mockRepo.On("CountFormsByTag", "acme", "hr", (*entity.Filter)(nil)).Return(int64(3), nil)
+		mockRepo.On("ListFormsByTag", "acme", "hr", (*entity.Filter)(nil), 2, MaxFormPageSize).Return([]entity.Form{form}, nil)
+
+		list, err := service.ListFormsByTag("acme", "HR", nil, 2, MaxFormPageSize+1)
+		require.NoError(t, err)
+		assert.Equal(t, "hr", list.Tag)
+		assert.Equal(t, int64(3), list.Total)
+		require.Len(t, list.Forms, 1)
+		assert.Equal(t, []string{"hr"}, list.Forms[0].Tags)
+	})
+
+	t.Run("lists nothing for an empty tag", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		list, err := service.ListFormsByTag("", " ", nil, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, list.Forms)
+		mockRepo.AssertNotCalled(t, "ListFormsByTag", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// CreateTemplate validates and stores a form template. A template without
+// an ID gets a new one.
+func (s *Service) CreateTemplate(template *entity.Template) error {
+	if template == nil {
+		return errors.New("template cannot be nil")
+	}
+
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	for i := range template.Questions {
+		if err := s.validateQuestion(template.Questions[i].Question()); err != nil {
+			return fmt.Errorf("invalid question: %w", err)
+		}
+	}
+
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+	}
+	template.CreatedAt = time.Now()
+
+	if err := s.repo.Create(template); err != nil {
+		return fmt.Errorf("failed to create template in repository: %w", err)
+	}
+
+	if err := s.publish(events.TemplateCreated, template); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
+
+// CreateFormFromTemplate creates a form of author with copies of the
+// questions of a template, like CreateForm, and returns it
+func (s *Service) CreateFormFromTemplate(templateID uuid.UUID, author string) (*entity.Form, error) {
+	if author == "" {
+		return nil, errors.New("author cannot be empty")
+	}
+
+	template, err := s.repo.GetTemplate(templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve template: %w", err)
+	}
+
+	form := template.NewForm(author)
+
+	if err = s.CreateForm(form); err != nil {
+		return nil, err
+	}
+
+	return form, nil
+}
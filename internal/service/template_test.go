@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/internal/question"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestService_CreateTemplate(t *testing.T) {
+	t.Run("stores and publishes the template", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		template := &entity.Template{
+			Title:     "Onboarding survey",
+			Author:    "author",
+			Questions: []entity.TemplateQuestion{{Content: "Team?", OrderNumber: 1, Type: "choice", Options: json.RawMessage(`{"choices":["a","b"]}`)}},
+		}
+
+		mockRepo.On("Create", template).Return(nil)
+		mockPublisher.On("Publish", template, events.TemplateCreated.String()).Return(nil)
+
+		require.NoError(t, service.CreateTemplate(template))
+		assert.NotEqual(t, uuid.Nil, template.ID)
+		assert.False(t, template.CreatedAt.IsZero())
+	})
+
+	t.Run("rejects invalid questions", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		template := &entity.Template{
+			Title:     "Onboarding survey",
+			Author:    "author",
+			Questions: []entity.TemplateQuestion{{Content: "Team?", Type: "choice"}},
+		}
+
+		err := service.CreateTemplate(template)
+
+		assert.ErrorIs(t, err, question.ErrInvalidOptions)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestService_CreateFormFromTemplate(t *testing.T) {
+	t.Run("copies the questions into a new form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		template := &entity.Template{
+			ID:       uuid.New(),
+			Title:    "Onboarding survey",
+			TenantID: "acme",
+			Questions: []entity.TemplateQuestion{
+				{Content: "Rate your first week", OrderNumber: 1, Type: "rating", Options: json.RawMessage(`{"max":10}`)},
+				{Content: "Anything else?", OrderNumber: 2},
+			},
+		}
+
+		mockRepo.On("GetTemplate", template.ID).Return(template, nil)
+		mockRepo.On("Create", mock.AnythingOfType("*entity.Form")).Return(nil)
+		mockCasher.On("AddToCash", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockPublisher.On("Publish", mock.AnythingOfType("*entity.Form"), "form.created").Return(nil)
+
+		form, err := service.CreateFormFromTemplate(template.ID, "bob")
+
+		require.NoError(t, err)
+		assert.Equal(t, "bob", form.Author)
+		assert.Equal(t, "acme", form.TenantID)
+		assert.Equal(t, "Onboarding survey", form.Title)
+		require.Len(t, form.Questions, 2)
+		assert.Equal(t, form.ID, form.Questions[0].FormID)
+		assert.Equal(t, "rating", form.Questions[0].Type)
+
+		form.Questions[0].Options[0] = '['
+		assert.JSONEq(t, `{"max":10}`, string(template.Questions[0].Options), "options are copied")
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		templateID := uuid.New()
+
+		mockRepo.On("GetTemplate", templateID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.CreateFormFromTemplate(templateID, "bob")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("requires an author", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		_, err := service.CreateFormFromTemplate(uuid.New(), "")
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+)
+
+// GetLocalizedForm returns a form with its title, description and question contents
+// in locale. Texts without a translation fall back to the default language,
+// as does the whole form for an empty locale.
+// Returns the form and the locale its texts were found in, empty for the
+// default language
+func (s *Service) GetLocalizedForm(formID uuid.UUID, locale string) (*entity.Form, string, error) {
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	localized, found := form.Localized(locale)
+
+	return localized, found, nil
+}
+
+// PublishFormReply sends a form back to the requester
+func (s *Service) PublishFormReply(reply *entity.FormReply) error {
+	return s.publish(events.FormRead, reply)
+}
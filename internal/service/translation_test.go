@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_GetLocalizedForm(t *testing.T) {
+	formID := uuid.New()
+	stored := &entity.Form{
+		ID:           formID,
+		Title:        "Feedback",
+		Translations: map[string]entity.FormTranslation{"de": {Title: "Rückmeldung"}},
+		Questions: []entity.Question{
+			{Content: "Name?", Translations: map[string]entity.QuestionTranslation{"de": {Content: "Wie heißen Sie?"}}},
+		},
+	}
+
+	t.Run("returns the form in the locale", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockRepo.On("Get", formID).Return(stored, nil)
+
+		form, locale, err := service.GetLocalizedForm(formID, "de-CH")
+
+		require.NoError(t, err)
+		assert.Equal(t, "de", locale)
+		assert.Equal(t, "Rückmeldung", form.Title)
+		assert.Equal(t, "Wie heißen Sie?", form.Questions[0].Content)
+		assert.Equal(t, "Feedback", stored.Title, "the stored form is left unchanged")
+	})
+
+	t.Run("falls back to the default language", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockRepo.On("Get", formID).Return(stored, nil)
+
+		form, locale, err := service.GetLocalizedForm(formID, "fr")
+
+		require.NoError(t, err)
+		assert.Empty(t, locale)
+		assert.Equal(t, "Feedback", form.Title)
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockRepo.On("Get", formID).Return(nil, errors.New("db down"))
+
+		_, _, err := service.GetLocalizedForm(formID, "de")
+
+		assert.ErrorContains(t, err, "db down")
+	})
+}
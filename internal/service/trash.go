@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrNotInTrash is returned for restoring or purging a form that wasn't
+// deleted
+var ErrNotInTrash = errors.New("form is not in the trash")
+
+// RestoreForm takes a deleted form out of the trash, counting it against
+// its author's quota again, and publishes it as form.restored
+func (s *Service) RestoreForm(formID uuid.UUID) error {
+	trashed, err := s.repo.GetTrashedForm(formID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("%w: %s", ErrNotInTrash, formID)
+	case err != nil:
+		return fmt.Errorf("failed to retrieve trashed form: %w", err)
+	}
+
+	if err = s.authorizeForm(trashed); err != nil {
+		return err
+	}
+
+	if err = s.reserveQuota(trashed.Author, 1, len(trashed.Questions), 0); err != nil {
+		return err
+	}
+
+	// 1. Critical operation first (database)
+	if err = s.repo.RestoreForm(formID); err != nil {
+		s.releaseQuota(trashed.Author, 1, len(trashed.Questions))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", ErrNotInTrash, formID)
+		}
+		return fmt.Errorf("failed to restore form in repository: %w", err)
+	}
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve restored form: %w", err)
+	}
+
+	// 2. Refresh the cached form, kept while trashed, and announce it
+	return s.syncForm(form, form, events.FormRestored)
+}
+
+// PurgeForm permanently removes a form from the trash, evicting it from
+// the cache, and publishes form.purged
+func (s *Service) PurgeForm(formID uuid.UUID) error {
+	if s.actor != "" {
+		trashed, err := s.repo.GetTrashedForm(formID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("%w: %s", ErrNotInTrash, formID)
+		case err != nil:
+			return fmt.Errorf("failed to retrieve trashed form: %w", err)
+		}
+
+		if err = s.authorizeForm(trashed); err != nil {
+			return err
+		}
+	}
+
+	// 1. Critical operation first (database)
+	if err := s.repo.PurgeForm(formID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", ErrNotInTrash, formID)
+		}
+		return fmt.Errorf("failed to purge form from repository: %w", err)
+	}
+
+	// 2. Run non-critical operations concurrently
+	var wg sync.WaitGroup
+	errChan := make(chan error, 2)
+
+	// Cache removal operation
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx, cancel := s.getContext()
+		defer cancel()
+
+		if err := s.cacheRetry.do(ctx, func() error {
+			return s.casher.RemoveFromCash(ctx, formID.String())
+		}); err != nil {
+			errChan <- fmt.Errorf("cache removal error: %w", err)
+		}
+	}()
+
+	// Publish operation
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx, cancel := s.getContext()
+		defer cancel()
+
+		event := events.NewFormPurged(formID)
+		if err := s.publishRetry.do(ctx, func() error {
+			return event.PublishTo(ctx, s.publisher)
+		}); err != nil {
+			errChan <- fmt.Errorf("publish error: %w", err)
+		}
+	}()
+
+	wg.Wait()
+	close(errChan)
+
+	// Return first error if any
+	for err := range errChan {
+		return err
+	}
+
+	return nil
+}
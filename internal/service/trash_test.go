@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestService_RestoreForm(t *testing.T) {
+	t.Run("restores a trashed form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		quotas := &MockQuotaRepository{}
+		service.EnableQuotas(quotas, testQuotaLimits)
+
+		formID := uuid.New()
+		form := &entity.Form{ID: formID, Author: "alice", Questions: make([]entity.Question, 2)}
+		mockRepo.On("GetTrashedForm", formID).Return(form, nil)
+		quotas.On("GetQuota", "alice").Return(&entity.AuthorQuota{Author: "alice"}, nil)
+		quotas.On("ReserveQuota", "alice", 1, 2, testQuotaLimits).Return(true, nil)
+		mockRepo.On("RestoreForm", formID).Return(nil)
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), form).Return(nil)
+		mockPublisher.On("Publish", form, events.FormRestored.String()).Return(nil)
+
+		assert.NoError(t, service.RestoreForm(formID))
+		quotas.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("fails for forms not in the trash", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+
+		formID := uuid.New()
+		mockRepo.On("GetTrashedForm", formID).Return(nil, gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, service.RestoreForm(formID), ErrNotInTrash)
+		mockRepo.AssertNotCalled(t, "RestoreForm", formID)
+	})
+
+	t.Run("releases the reservation when the restore fails", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		quotas := &MockQuotaRepository{}
+		service.EnableQuotas(quotas, testQuotaLimits)
+
+		formID := uuid.New()
+		mockRepo.On("GetTrashedForm", formID).Return(&entity.Form{ID: formID, Author: "bob"}, nil)
+		quotas.On("GetQuota", "bob").Return(&entity.AuthorQuota{Author: "bob"}, nil)
+		quotas.On("ReserveQuota", "bob", 1, 0, testQuotaLimits).Return(true, nil)
+		quotas.On("ReleaseQuota", "bob", 1, 0).Return(nil)
+		mockRepo.On("RestoreForm", formID).Return(gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, service.RestoreForm(formID), ErrNotInTrash)
+		quotas.AssertExpectations(t)
+	})
+}
+
+func TestService_PurgeForm(t *testing.T) {
+	t.Run("purges and evicts a trashed form", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+
+		formID := uuid.New()
+		mockRepo.On("PurgeForm", formID).Return(nil)
+		mockCasher.On("RemoveFromCash", mock.Anything, formID.String()).Return(nil)
+		mockPublisher.On("Publish", &events.FormPurgedPayload{FormID: formID.String()}, events.FormPurged.String()).Return(nil)
+
+		assert.NoError(t, service.PurgeForm(formID))
+		mockRepo.AssertExpectations(t)
+		mockCasher.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("fails for forms not in the trash", func(t *testing.T) {
+		service, mockCasher, mockRepo, _ := setupService()
+
+		formID := uuid.New()
+		mockRepo.On("PurgeForm", formID).Return(gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, service.PurgeForm(formID), ErrNotInTrash)
+		mockCasher.AssertNotCalled(t, "RemoveFromCash", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/internal/question"
+	"github.com/Koyo-os/form-service/pkg/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultUploadTTL   = 15 * time.Minute // How long an upload URL stays valid
+	OrphanCleanupBatch = 100              // Max uploads removed per cleanup run
+)
+
+var (
+	ErrUploadsDisabled = errors.New("file uploads are not configured")
+	ErrUploadRejected  = errors.New("upload rejected")
+)
+
+// EnableUploads turns on file-upload questions. ttl bounds how long an issued
+// upload URL is valid and how long an unattached upload is kept.
+func (s *Service) EnableUploads(repo UploadRepository, storage Storage, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultUploadTTL
+	}
+
+	s.uploads = repo
+	s.storage = storage
+	s.uploadTTL = ttl
+}
+
+// uploadKey is the storage key of an upload
+func uploadKey(formID, questionID, uploadID uuid.UUID) string {
+	return fmt.Sprintf("forms/%s/questions/%s/%s", formID, questionID, uploadID)
+}
+
+// fileOptions returns the options of a file-upload question
+func (s *Service) fileOptions(questionID uuid.UUID) (*entity.Question, question.FileOptions, error) {
+	q, err := s.repo.GetQuestion(questionID)
+	if err != nil {
+		return nil, question.FileOptions{}, fmt.Errorf("failed to retrieve question: %w", err)
+	}
+
+	if q.Type != question.FILE_TYPE {
+		return nil, question.FileOptions{}, fmt.Errorf("%w: question %s is not a file question", ErrUploadRejected, questionID)
+	}
+
+	opts, err := question.ParseFileOptions(q.Options)
+	if err != nil {
+		return nil, question.FileOptions{}, err
+	}
+
+	return q, opts, nil
+}
+
+// RequestUpload validates the declared file against the question limits,
+// records a pending upload and returns a presigned URL to upload it to.
+func (s *Service) RequestUpload(questionID uuid.UUID, fileName, contentType string, size int64) (*entity.UploadTicket, error) {
+	if s.uploads == nil {
+		return nil, ErrUploadsDisabled
+	}
+
+	q, opts, err := s.fileOptions(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.AllowsSize(size) {
+		return nil, fmt.Errorf("%w: size %d exceeds limit %d", ErrUploadRejected, size, opts.MaxSize)
+	}
+
+	if !opts.AllowsMimeType(contentType) {
+		return nil, fmt.Errorf("%w: mime type %q is not accepted", ErrUploadRejected, contentType)
+	}
+
+	now := time.Now()
+	upload := &entity.Upload{
+		ID:          uuid.New(),
+		FormID:      q.FormID,
+		QuestionID:  q.ID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		Status:      entity.UploadPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.uploadTTL),
+	}
+	upload.ObjectKey = uploadKey(q.FormID, q.ID, upload.ID)
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	url, err := s.storage.PresignUpload(ctx, upload.ObjectKey, s.uploadTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	if err = s.uploads.Create(upload); err != nil {
+		return nil, fmt.Errorf("failed to create upload in repository: %w", err)
+	}
+
+	return &entity.UploadTicket{
+		UploadID:  upload.ID.String(),
+		URL:       url,
+		Method:    "PUT",
+		MaxSize:   opts.MaxSize,
+		ExpiresAt: upload.ExpiresAt,
+	}, nil
+}
+
+// PublishUploadTicket sends an issued ticket back to the requester
+func (s *Service) PublishUploadTicket(ticket *entity.UploadTicket) error {
+	return s.publish(events.UploadTicket, ticket)
+}
+
+// CompleteUpload checks the uploaded object against the question limits.
+// Objects breaking the limits are removed from storage and rejected.
+func (s *Service) CompleteUpload(uploadID uuid.UUID) (*entity.Upload, error) {
+	if s.uploads == nil {
+		return nil, ErrUploadsDisabled
+	}
+
+	upload, err := s.uploads.GetUpload(uploadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve upload: %w", err)
+	}
+
+	if upload.Status != entity.UploadPending {
+		return upload, nil
+	}
+
+	_, opts, err := s.fileOptions(upload.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	info, err := s.storage.Stat(ctx, upload.ObjectKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("%w: file was not uploaded", ErrUploadRejected)
+		}
+		return nil, fmt.Errorf("failed to stat upload: %w", err)
+	}
+
+	if !opts.AllowsSize(info.Size) || !opts.AllowsMimeType(info.ContentType) {
+		if err = s.discardUpload(ctx, upload); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: stored file (%d bytes, %q) breaks the question limits",
+			ErrUploadRejected, info.Size, info.ContentType)
+	}
+
+	upload.Size = info.Size
+	upload.ContentType = info.ContentType
+	upload.Status = entity.UploadStored
+
+	if err = s.uploads.UpdateUpload(upload.ID, map[string]any{
+		"size":         upload.Size,
+		"content_type": upload.ContentType,
+		"status":       upload.Status,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update upload in repository: %w", err)
+	}
+
+	if err = s.publish(events.UploadStored, upload); err != nil {
+		return upload, fmt.Errorf("publish error: %w", err)
+	}
+
+	return upload, nil
+}
+
+// AttachUploads records stored uploads as part of a response, which keeps
+// them from being cleaned up. A nil response ID attaches them to a response
+// that isn't stored.
+func (s *Service) AttachUploads(responseID uuid.UUID, uploadIDs []uuid.UUID) error {
+	if s.uploads == nil {
+		return ErrUploadsDisabled
+	}
+
+	for _, uploadID := range uploadIDs {
+		upload, err := s.uploads.GetUpload(uploadID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve upload: %w", err)
+		}
+
+		if upload.Status != entity.UploadStored {
+			return fmt.Errorf("%w: upload %s is %s", ErrUploadRejected, uploadID, upload.Status)
+		}
+
+		if err = s.attachUpload(responseID, upload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachUpload marks a stored upload as attached to a response
+func (s *Service) attachUpload(responseID uuid.UUID, upload *entity.Upload) error {
+	fields := map[string]any{"status": entity.UploadAttached}
+	if responseID != uuid.Nil {
+		fields["response_id"] = responseID
+	}
+
+	if err := s.uploads.UpdateUpload(upload.ID, fields); err != nil {
+		return fmt.Errorf("failed to attach upload: %w", err)
+	}
+
+	upload.Status = entity.UploadAttached
+
+	return nil
+}
+
+// answerUpload is an upload answering the question at orderNumber
+type answerUpload struct {
+	upload      entity.Upload
+	orderNumber uint
+}
+
+// answerUploads retrieves the uploads referenced by the answers to file
+// questions. They must be stored, not attached to another response yet, and
+// belong to the question they answer; answers breaking that are reported
+// like invalid answers.
+func (s *Service) answerUploads(form *entity.Form, answers []entity.Answer) ([]answerUpload, []entity.AnswerError, error) {
+	questions := make(map[uint]*entity.Question, len(form.Questions))
+	for i := range form.Questions {
+		questions[form.Questions[i].OrderNumber] = &form.Questions[i]
+	}
+
+	var (
+		uploads []answerUpload
+		failed  []entity.AnswerError
+	)
+
+	for _, answer := range answers {
+		q, ok := questions[answer.OrderNumber]
+		if !ok || q.Type != question.FILE_TYPE {
+			continue
+		}
+
+		var ids []uuid.UUID
+		if len(answer.Value) > 0 {
+			if err := json.Unmarshal(answer.Value, &ids); err != nil {
+				return nil, nil, fmt.Errorf("failed to decode file answer: %w", err)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		if s.uploads == nil {
+			failed = append(failed, entity.AnswerError{OrderNumber: q.OrderNumber, Errors: []string{ErrUploadsDisabled.Error()}})
+			continue
+		}
+
+		var errs []string
+		for _, id := range ids {
+			upload, err := s.uploads.GetUpload(id)
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				errs = append(errs, fmt.Sprintf("upload %s not found", id))
+			case err != nil:
+				return nil, nil, fmt.Errorf("failed to retrieve upload: %w", err)
+			case upload.QuestionID != q.ID:
+				errs = append(errs, fmt.Sprintf("upload %s belongs to another question", id))
+			case upload.Status != entity.UploadStored:
+				errs = append(errs, fmt.Sprintf("upload %s is %s", id, upload.Status))
+			default:
+				uploads = append(uploads, answerUpload{upload: *upload, orderNumber: q.OrderNumber})
+			}
+		}
+
+		if len(errs) > 0 {
+			failed = append(failed, entity.AnswerError{OrderNumber: q.OrderNumber, Errors: errs})
+		}
+	}
+
+	return uploads, failed, nil
+}
+
+// CleanupOrphanUploads removes expired uploads that were never attached to
+// a response, both from storage and the repository. It is meant to run
+// periodically from the scheduler and returns how many uploads were removed.
+func (s *Service) CleanupOrphanUploads(ctx context.Context) (int, error) {
+	if s.uploads == nil {
+		return 0, nil
+	}
+
+	orphans, err := s.uploads.ListOrphanUploads(time.Now(), OrphanCleanupBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list orphan uploads: %w", err)
+	}
+
+	var errs []error
+	removed := 0
+
+	for i := range orphans {
+		if err = s.discardUpload(ctx, &orphans[i]); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed++
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+// discardUpload removes the object and then its metadata
+func (s *Service) discardUpload(ctx context.Context, upload *entity.Upload) error {
+	if err := s.storage.Delete(ctx, upload.ObjectKey); err != nil {
+		return fmt.Errorf("failed to delete upload %s from storage: %w", upload.ID, err)
+	}
+
+	if err := s.uploads.DeleteUpload(upload.ID); err != nil {
+		return fmt.Errorf("failed to delete upload %s from repository: %w", upload.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUploadRepository is a mock implementation of the UploadRepository interface
+type MockUploadRepository struct {
+	mock.Mock
+}
+
+func (m *MockUploadRepository) Create(entity interface{}) error {
+	args := m.Called(entity)
+	return args.Error(0)
+}
+
+func (m *MockUploadRepository) GetUpload(id uuid.UUID) (*entity.Upload, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Upload), args.Error(1)
+}
+
+func (m *MockUploadRepository) UpdateUpload(id uuid.UUID, values interface{}) error {
+	args := m.Called(id, values)
+	return args.Error(0)
+}
+
+func (m *MockUploadRepository) ListOrphanUploads(before time.Time, limit int) ([]entity.Upload, error) {
+	args := m.Called(before, limit)
+	return args.Get(0).([]entity.Upload), args.Error(1)
+}
+
+func (m *MockUploadRepository) DeleteUpload(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockStorage is a mock implementation of the Storage interface
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) PresignUpload(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	args := m.Called(ctx, key, ttl)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorage) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.ObjectInfo), args.Error(1)
+}
+
+func (m *MockStorage) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func setupUploadService() (*Service, *MockRepository, *MockUploadRepository, *MockStorage, *MockPublisher) {
+	service, _, mockRepo, mockPublisher := setupService()
+	mockUploads := &MockUploadRepository{}
+	mockStorage := &MockStorage{}
+	service.EnableUploads(mockUploads, mockStorage, time.Minute)
+	return service, mockRepo, mockUploads, mockStorage, mockPublisher
+}
+
+func fileQuestion(formID uuid.UUID) *entity.Question {
+	q := &entity.Question{
+		FormID:  formID,
+		Type:    "file",
+		Options: json.RawMessage(`{"max_size": 1024, "mime_types": ["image/*"]}`),
+	}
+	q.ID = questionID(7)
+	return q
+}
+
+func TestService_RequestUpload(t *testing.T) {
+	formID := uuid.New()
+
+	t.Run("issues a presigned url", func(t *testing.T) {
+		service, mockRepo, mockUploads, mockStorage, _ := setupUploadService()
+
+		mockRepo.On("GetQuestion", questionID(7)).Return(fileQuestion(formID), nil)
+		mockStorage.On("PresignUpload", mock.Anything, mock.AnythingOfType("string"), time.Minute).
+			Return("https://storage/upload", nil)
+		mockUploads.On("Create", mock.MatchedBy(func(u *entity.Upload) bool {
+			return u.Status == entity.UploadPending && u.FormID == formID && u.Size == 512
+		})).Return(nil)
+
+		ticket, err := service.RequestUpload(questionID(7), "cat.png", "image/png", 512)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://storage/upload", ticket.URL)
+		assert.Equal(t, "PUT", ticket.Method)
+		assert.Equal(t, int64(1024), ticket.MaxSize)
+		mockUploads.AssertExpectations(t)
+	})
+
+	t.Run("rejects files over the size limit", func(t *testing.T) {
+		service, mockRepo, mockUploads, _, _ := setupUploadService()
+		mockRepo.On("GetQuestion", questionID(7)).Return(fileQuestion(formID), nil)
+
+		_, err := service.RequestUpload(questionID(7), "cat.png", "image/png", 2048)
+
+		assert.ErrorIs(t, err, ErrUploadRejected)
+		mockUploads.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("rejects mime types not accepted", func(t *testing.T) {
+		service, mockRepo, _, _, _ := setupUploadService()
+		mockRepo.On("GetQuestion", questionID(7)).Return(fileQuestion(formID), nil)
+
+		_, err := service.RequestUpload(questionID(7), "cv.pdf", "application/pdf", 10)
+
+		assert.ErrorIs(t, err, ErrUploadRejected)
+	})
+
+	t.Run("uploads disabled", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		_, err := service.RequestUpload(questionID(7), "cat.png", "image/png", 10)
+
+		assert.ErrorIs(t, err, ErrUploadsDisabled)
+	})
+}
+
+func TestService_CompleteUpload(t *testing.T) {
+	formID := uuid.New()
+	pending := func() *entity.Upload {
+		return &entity.Upload{
+			ID:         uuid.New(),
+			FormID:     formID,
+			QuestionID: questionID(7),
+			ObjectKey:  "forms/key",
+			Status:     entity.UploadPending,
+		}
+	}
+
+	t.Run("stores a file within limits", func(t *testing.T) {
+		service, mockRepo, mockUploads, mockStorage, mockPublisher := setupUploadService()
+		upload := pending()
+
+		mockUploads.On("GetUpload", upload.ID).Return(upload, nil)
+		mockRepo.On("GetQuestion", questionID(7)).Return(fileQuestion(formID), nil)
+		mockStorage.On("Stat", mock.Anything, "forms/key").
+			Return(&storage.ObjectInfo{Size: 100, ContentType: "image/jpeg"}, nil)
+		mockUploads.On("UpdateUpload", upload.ID, mock.Anything).Return(nil)
+		mockPublisher.On("Publish", upload, "upload.stored").Return(nil)
+
+		got, err := service.CompleteUpload(upload.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, entity.UploadStored, got.Status)
+		assert.Equal(t, "image/jpeg", got.ContentType)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("discards a file breaking the limits", func(t *testing.T) {
+		service, mockRepo, mockUploads, mockStorage, _ := setupUploadService()
+		upload := pending()
+
+		mockUploads.On("GetUpload", upload.ID).Return(upload, nil)
+		mockRepo.On("GetQuestion", questionID(7)).Return(fileQuestion(formID), nil)
+		mockStorage.On("Stat", mock.Anything, "forms/key").
+			Return(&storage.ObjectInfo{Size: 100, ContentType: "application/x-msdownload"}, nil)
+		mockStorage.On("Delete", mock.Anything, "forms/key").Return(nil)
+		mockUploads.On("DeleteUpload", upload.ID).Return(nil)
+
+		_, err := service.CompleteUpload(upload.ID)
+
+		assert.ErrorIs(t, err, ErrUploadRejected)
+		mockStorage.AssertExpectations(t)
+		mockUploads.AssertExpectations(t)
+	})
+
+	t.Run("file never uploaded", func(t *testing.T) {
+		service, mockRepo, mockUploads, mockStorage, _ := setupUploadService()
+		upload := pending()
+
+		mockUploads.On("GetUpload", upload.ID).Return(upload, nil)
+		mockRepo.On("GetQuestion", questionID(7)).Return(fileQuestion(formID), nil)
+		mockStorage.On("Stat", mock.Anything, "forms/key").Return(nil, storage.ErrNotFound)
+
+		_, err := service.CompleteUpload(upload.ID)
+
+		assert.ErrorIs(t, err, ErrUploadRejected)
+	})
+}
+
+func TestService_CleanupOrphanUploads(t *testing.T) {
+	service, _, mockUploads, mockStorage, _ := setupUploadService()
+
+	orphans := []entity.Upload{
+		{ID: uuid.New(), ObjectKey: "forms/a"},
+		{ID: uuid.New(), ObjectKey: "forms/b"},
+	}
+
+	mockUploads.On("ListOrphanUploads", mock.AnythingOfType("time.Time"), OrphanCleanupBatch).Return(orphans, nil)
+	mockStorage.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	mockUploads.On("DeleteUpload", mock.Anything).Return(nil)
+
+	removed, err := service.CleanupOrphanUploads(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	mockStorage.AssertNumberOfCalls(t, "Delete", 2)
+}
+
+func TestService_SubmitResponse_Uploads(t *testing.T) {
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Status: entity.StatusPublished, Questions: []entity.Question{{
+		FormID:      formID,
+		OrderNumber: 1,
+		Type:        "file",
+		Options:     json.RawMessage(`{"max_files": 2}`),
+	}}}
+	form.Questions[0].ID = questionID(7)
+
+	t.Run("attaches stored uploads and lists them", func(t *testing.T) {
+		service, mockRepo, mockUploads, _, mockPublisher := setupUploadService()
+		upload := &entity.Upload{ID: uuid.New(), FormID: formID, QuestionID: questionID(7), FileName: "cv.pdf", Size: 10, ObjectKey: "key", Status: entity.UploadStored}
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockUploads.On("GetUpload", upload.ID).Return(upload, nil)
+		mockUploads.On("UpdateUpload", upload.ID, map[string]any{"status": entity.UploadAttached}).Return(nil)
+		mockPublisher.On("Publish", mock.MatchedBy(func(s *entity.ResponseSubmission) bool {
+			return len(s.Attachments) == 1 && s.Attachments[0].FileName == "cv.pdf" && s.Attachments[0].OrderNumber == 1
+		}), "form.response.accepted").Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{
+			FormID:  formID.String(),
+			Answers: []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`["` + upload.ID.String() + `"]`)}},
+		})
+
+		assert.NoError(t, err)
+		mockUploads.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects uploads of other questions", func(t *testing.T) {
+		service, mockRepo, mockUploads, _, mockPublisher := setupUploadService()
+		upload := &entity.Upload{ID: uuid.New(), FormID: formID, QuestionID: questionID(8), Status: entity.UploadStored}
+
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockUploads.On("GetUpload", upload.ID).Return(upload, nil)
+		mockPublisher.On("Publish", mock.AnythingOfType("*entity.ResponseRejection"), "form.response.rejected").Return(nil)
+
+		err := service.SubmitResponse(&entity.ResponseSubmission{
+			FormID:  formID.String(),
+			Answers: []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`["` + upload.ID.String() + `"]`)}},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidResponse)
+		mockUploads.AssertNotCalled(t, "UpdateUpload", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrNoVersionAt is returned by GetFormAt for a point the form had no
+// published version at
+var ErrNoVersionAt = errors.New("form has no published version at that point")
+
+// PublishForm snapshots the form and its questions as its next immutable
+// version and publishes form.published with it. Later edits of the form
+// don't change published versions. A draft is published as well, then
+// cached and announced as form.opened.
+// Returns entity.ErrInvalidTransition for archived forms
+func (s *Service) PublishForm(formID uuid.UUID) (_ *entity.FormVersion, err error) {
+	defer observe("publish_form", time.Now(), &err)
+
+	form, err := s.repo.Get(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeForm(form); err != nil {
+		return nil, err
+	}
+
+	if form.Status == entity.StatusArchived {
+		return nil, fmt.Errorf("%w: form %s is archived", entity.ErrInvalidTransition, formID)
+	}
+
+	// The snapshot is taken once the draft is published, so it is answerable
+	draft := form.Status == entity.StatusDraft
+	if draft {
+		if err = s.repo.Update(formID, form.Version, "Status", entity.StatusPublished); err != nil {
+			return nil, fmt.Errorf("failed to update form status in repository: %w", err)
+		}
+	}
+
+	version, err := s.repo.CreateFormVersion(formID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form version: %w", err)
+	}
+
+	if err = s.publish(events.FormPublished, version); err != nil {
+		return nil, fmt.Errorf("publish error: %w", err)
+	}
+
+	if draft {
+		if form, err = s.repo.Get(formID); err != nil {
+			return nil, fmt.Errorf("failed to retrieve published form: %w", err)
+		}
+		if err = s.syncForm(form, form, events.FormOpened); err != nil {
+			return nil, err
+		}
+	}
+
+	return version, nil
+}
+
+// GetFormVersion returns a published version of a form, the latest one for
+// version 0
+func (s *Service) GetFormVersion(formID uuid.UUID, version uint) (*entity.FormVersion, error) {
+	stored, err := s.repo.GetFormVersion(formID, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form version: %w", err)
+	}
+
+	return stored, nil
+}
+
+// PublishFormVersion sends a form version back to the requester
+func (s *Service) PublishFormVersion(reply *entity.FormVersionReply) error {
+	return s.publish(events.FormVersion, reply)
+}
+
+// GetFormAt returns the form as respondents were shown it at a past point:
+// the given version, or with version 0 the latest version published by at
+// Returns ErrNoVersionAt if there is no such version
+func (s *Service) GetFormAt(formID uuid.UUID, at time.Time, version uint) (*entity.FormVersion, error) {
+	var (
+		stored *entity.FormVersion
+		err    error
+	)
+
+	switch {
+	case version > 0:
+		stored, err = s.repo.GetFormVersion(formID, version)
+	case at.IsZero():
+		return nil, errors.New("either a time or a version is required")
+	default:
+		stored, err = s.repo.GetFormVersionAt(formID, at)
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("%w: form %s", ErrNoVersionAt, formID)
+	case err != nil:
+		return nil, fmt.Errorf("failed to retrieve form version: %w", err)
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestService_PublishForm(t *testing.T) {
+	t.Run("publishes the new version", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+		version := &entity.FormVersion{ID: uuid.New(), FormID: formID, Version: 3}
+
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Status: entity.StatusPublished}, nil)
+		mockRepo.On("CreateFormVersion", formID).Return(version, nil)
+		mockPublisher.On("Publish", version, events.FormPublished.String()).Return(nil)
+
+		got, err := service.PublishForm(formID)
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), got.Version)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("publishes drafts", func(t *testing.T) {
+		service, mockCasher, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+		draft := &entity.Form{ID: formID, Status: entity.StatusDraft, Version: 2}
+		published := &entity.Form{ID: formID, Status: entity.StatusPublished, Version: 3}
+		version := &entity.FormVersion{FormID: formID, Version: 1}
+
+		mockRepo.On("Get", formID).Return(draft, nil).Once()
+		mockRepo.On("Update", formID, uint(2), "Status", entity.StatusPublished).Return(nil)
+		mockRepo.On("CreateFormVersion", formID).Return(version, nil)
+		mockRepo.On("Get", formID).Return(published, nil).Once()
+		mockCasher.On("AddToCash", mock.Anything, formID.String(), published).Return(nil)
+		mockPublisher.On("Publish", version, events.FormPublished.String()).Return(nil)
+		mockPublisher.On("Publish", published, events.FormOpened.String()).Return(nil)
+
+		_, err := service.PublishForm(formID)
+
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("refuses archived forms", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Status: entity.StatusArchived}, nil)
+
+		_, err := service.PublishForm(formID)
+
+		assert.ErrorIs(t, err, entity.ErrInvalidTransition)
+		mockRepo.AssertNotCalled(t, "CreateFormVersion", mock.Anything)
+		mockPublisher.AssertNotCalled(t, "Publish")
+	})
+
+	t.Run("missing form publishes nothing", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+
+		mockRepo.On("Get", formID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.PublishForm(formID)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockPublisher.AssertNotCalled(t, "Publish")
+	})
+
+	t.Run("publish failure", func(t *testing.T) {
+		service, _, mockRepo, mockPublisher := setupService()
+		formID := uuid.New()
+		version := &entity.FormVersion{FormID: formID, Version: 1}
+
+		mockRepo.On("Get", formID).Return(&entity.Form{ID: formID, Status: entity.StatusClosed}, nil)
+		mockRepo.On("CreateFormVersion", formID).Return(version, nil)
+		mockPublisher.On("Publish", version, events.FormPublished.String()).Return(errors.New("broker down"))
+
+		_, err := service.PublishForm(formID)
+
+		assert.ErrorContains(t, err, "publish error")
+	})
+}
+
+func TestService_GetFormVersion(t *testing.T) {
+	service, _, mockRepo, _ := setupService()
+	formID := uuid.New()
+	version := &entity.FormVersion{FormID: formID, Version: 2}
+
+	mockRepo.On("GetFormVersion", formID, uint(0)).Return(version, nil)
+	mockRepo.On("GetFormVersion", formID, uint(5)).Return(nil, gorm.ErrRecordNotFound)
+
+	got, err := service.GetFormVersion(formID, 0)
+	require.NoError(t, err)
+	assert.Same(t, version, got)
+
+	_, err = service.GetFormVersion(formID, 5)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestService_GetFormAt(t *testing.T) {
+	formID := uuid.New()
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("by time", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		version := &entity.FormVersion{FormID: formID, Version: 2}
+		mockRepo.On("GetFormVersionAt", formID, at).Return(version, nil)
+
+		got, err := service.GetFormAt(formID, at, 0)
+
+		require.NoError(t, err)
+		assert.Same(t, version, got)
+	})
+
+	t.Run("by version", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		version := &entity.FormVersion{FormID: formID, Version: 1}
+		mockRepo.On("GetFormVersion", formID, uint(1)).Return(version, nil)
+
+		got, err := service.GetFormAt(formID, at, 1)
+
+		require.NoError(t, err)
+		assert.Same(t, version, got)
+		mockRepo.AssertNotCalled(t, "GetFormVersionAt", formID, at)
+	})
+
+	t.Run("before the first version", func(t *testing.T) {
+		service, _, mockRepo, _ := setupService()
+		mockRepo.On("GetFormVersionAt", formID, at).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.GetFormAt(formID, at, 0)
+
+		assert.ErrorIs(t, err, ErrNoVersionAt)
+	})
+
+	t.Run("without a point", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		_, err := service.GetFormAt(formID, time.Time{}, 0)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/pkg/webhook"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// WebhookEvents is the pattern of the events DeliverWebhooks posts
+const WebhookEvents = string(events.ResponseAccepted)
+
+var (
+	// ErrWebhooksDisabled is returned for webhook requests while webhooks
+	// aren't configured
+	ErrWebhooksDisabled = errors.New("webhooks are not configured")
+
+	// ErrTooManyWebhooks is returned for webhooks beyond entity.MAX_WEBHOOKS
+	ErrTooManyWebhooks = fmt.Errorf("%w: a form has at most %d webhooks", entity.ErrInvalidWebhook, entity.MAX_WEBHOOKS)
+)
+
+// EnableWebhooks stores the webhooks of the forms in repo and posts the
+// accepted responses to them with sender, retrying each delivery with
+// retry. DeliverWebhooks must receive the WebhookEvents. Webhook hosts are
+// resolved with net.DefaultResolver and refused unless public.
+func (s *Service) EnableWebhooks(repo WebhookRepository, sender WebhookSender, retry RetryPolicy) {
+	s.webhooks = repo
+	s.webhookSender = sender
+	s.webhookRetry = retry
+	s.resolver = net.DefaultResolver
+}
+
+// SetWebhook adds a webhook to its form, or replaces the URL and template
+// of the webhook with its ID, and publishes form.webhook.saved. The URL
+// must point at a public host, see entity.Webhook.Validate, and the
+// template must compile, see package webhook.
+func (s *Service) SetWebhook(hook *entity.Webhook) (*entity.Webhook, error) {
+	if s.webhooks == nil {
+		return nil, ErrWebhooksDisabled
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	if err := hook.Validate(ctx, s.resolver); err != nil {
+		return nil, err
+	}
+
+	if _, err := webhook.Compile(hook.TemplateKind, hook.Template); err != nil {
+		return nil, err
+	}
+
+	form, err := s.repo.Get(hook.FormID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve form: %w", err)
+	}
+
+	if err = s.authorizeForm(form); err != nil {
+		return nil, err
+	}
+
+	if hook.ID != uuid.Nil {
+		stored, err := s.webhooks.GetWebhook(hook.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve webhook: %w", err)
+		}
+
+		// Webhooks are only changed through their own form
+		if stored.FormID != form.ID {
+			return nil, fmt.Errorf("failed to retrieve webhook: %w", gorm.ErrRecordNotFound)
+		}
+	} else {
+		existing, err := s.webhooks.ListWebhooks(form.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+
+		if len(existing) >= entity.MAX_WEBHOOKS {
+			return nil, ErrTooManyWebhooks
+		}
+
+		hook.ID = uuid.New()
+	}
+
+	if err = s.webhooks.SaveWebhook(hook); err != nil {
+		return nil, fmt.Errorf("failed to save webhook in repository: %w", err)
+	}
+
+	if err = s.publish(events.WebhookSaved, hook); err != nil {
+		return hook, fmt.Errorf("publish error: %w", err)
+	}
+
+	return hook, nil
+}
+
+// RemoveWebhook removes a webhook of a form and publishes
+// form.webhook.removed
+// Returns gorm.ErrRecordNotFound if the form has no such webhook
+func (s *Service) RemoveWebhook(formID, webhookID uuid.UUID) error {
+	if s.webhooks == nil {
+		return ErrWebhooksDisabled
+	}
+
+	hook, err := s.webhooks.GetWebhook(webhookID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve webhook: %w", err)
+	}
+
+	if hook.FormID != formID {
+		return fmt.Errorf("failed to retrieve webhook: %w", gorm.ErrRecordNotFound)
+	}
+
+	if err = s.authorize(formID); err != nil {
+		return err
+	}
+
+	if err = s.webhooks.DeleteWebhook(webhookID); err != nil {
+		return fmt.Errorf("failed to delete webhook from repository: %w", err)
+	}
+
+	if err = s.publish(events.WebhookRemoved, hook); err != nil {
+		return fmt.Errorf("publish error: %w", err)
+	}
+
+	return nil
+}
+
+// DeliverWebhooks posts an accepted response to the webhooks of its form,
+// rendered through the template of each. It has the signature of an event
+// bus handler and ignores other events. The webhooks are posted in the
+// background, so a slow one doesn't hold up the response, and each is
+// retried on its own; one still failing is logged and skipped.
+func (s *Service) DeliverWebhooks(payload any, eventType string) error {
+	if s.webhooks == nil {
+		return nil
+	}
+
+	submission, ok := payload.(*entity.ResponseSubmission)
+	if !ok || events.Type(eventType) != events.ResponseAccepted {
+		return nil
+	}
+
+	formID, err := uuid.Parse(submission.FormID)
+	if err != nil {
+		return fmt.Errorf("failed to parse form id: %w", err)
+	}
+
+	hooks, err := s.webhooks.ListWebhooks(formID)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	event := webhook.Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Payload:   submission,
+	}
+
+	for _, hook := range hooks {
+		go func() {
+			if err := s.deliverWebhook(&hook, event); err != nil {
+				s.logger.Error("webhook delivery failed",
+					zap.String("form_id", formID.String()),
+					zap.String("webhook_id", hook.ID.String()),
+					zap.String("event_type", eventType),
+					zap.Error(err))
+			}
+		}()
+	}
+
+	return nil
+}
+
+// deliverWebhook renders event through the template of hook and posts it
+func (s *Service) deliverWebhook(hook *entity.Webhook, event webhook.Event) error {
+	tmpl, err := webhook.Compile(hook.TemplateKind, hook.Template)
+	if err != nil {
+		return err
+	}
+
+	body, err := tmpl.Render(event)
+	if err != nil {
+		return fmt.Errorf("failed to render webhook body: %w", err)
+	}
+
+	ctx, cancel := s.getContext()
+	defer cancel()
+
+	return s.webhookRetry.do(ctx, func() error {
+		return s.webhookSender.Send(ctx, hook.URL, body)
+	})
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/entity/events"
+	"github.com/Koyo-os/form-service/pkg/webhook"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) GetWebhook(id uuid.UUID) (*entity.Webhook, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) SaveWebhook(webhook *entity.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) DeleteWebhook(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListWebhooks(formID uuid.UUID) ([]entity.Webhook, error) {
+	args := m.Called(formID)
+	return args.Get(0).([]entity.Webhook), args.Error(1)
+}
+
+// recordingSender records the bodies posted to each URL, failing the URLs
+// in fail
+type recordingSender struct {
+	mu     sync.Mutex
+	fail   map[string]bool
+	bodies map[string][]string
+}
+
+func (r *recordingSender) Send(_ context.Context, url string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.bodies == nil {
+		r.bodies = map[string][]string{}
+	}
+	r.bodies[url] = append(r.bodies[url], string(body))
+
+	if r.fail[url] {
+		return errors.New("webhook down")
+	}
+	return nil
+}
+
+func (r *recordingSender) posted(url string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.bodies[url]...)
+}
+
+// hostResolver resolves example.com and its subdomains publicly and
+// internal.example to the metadata address
+type hostResolver struct{}
+
+func (hostResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	if host == "internal.example" {
+		return []netip.Addr{netip.MustParseAddr("169.254.169.254")}, nil
+	}
+	return []netip.Addr{netip.MustParseAddr("93.184.215.14")}, nil
+}
+
+func setupWebhookService() (*Service, *MockWebhookRepository, *recordingSender, *MockRepository, *MockPublisher) {
+	service, _, mockRepo, mockPublisher := setupService()
+	mockWebhooks := &MockWebhookRepository{}
+	sender := &recordingSender{}
+	service.EnableWebhooks(mockWebhooks, sender, RetryPolicy{Retries: 1, Delay: time.Millisecond})
+	service.resolver = hostResolver{}
+
+	return service, mockWebhooks, sender, mockRepo, mockPublisher
+}
+
+func TestService_SetWebhook(t *testing.T) {
+	formID := uuid.New()
+	form := &entity.Form{ID: formID, Author: "alice"}
+
+	t.Run("adds a webhook with its template", func(t *testing.T) {
+		service, mockWebhooks, _, mockRepo, mockPublisher := setupWebhookService()
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockWebhooks.On("ListWebhooks", formID).Return([]entity.Webhook{}, nil)
+		mockWebhooks.On("SaveWebhook", mock.AnythingOfType("*entity.Webhook")).Return(nil)
+		mockPublisher.On("Publish", mock.AnythingOfType("*entity.Webhook"), events.WebhookSaved.String()).Return(nil)
+
+		hook, err := service.As("alice").SetWebhook(&entity.Webhook{
+			FormID:       formID,
+			URL:          "https://example.com/hook",
+			TemplateKind: webhook.KIND_JSONPATH,
+			Template:     `{"form": "$.payload.form_id"}`,
+		})
+
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, hook.ID)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid webhooks", func(t *testing.T) {
+		service, mockWebhooks, _, _, _ := setupWebhookService()
+
+		_, err := service.SetWebhook(&entity.Webhook{FormID: formID, URL: "ftp://example.com"})
+		assert.ErrorIs(t, err, entity.ErrInvalidWebhook)
+
+		_, err = service.SetWebhook(&entity.Webhook{FormID: formID, URL: "https://example.com", TemplateKind: webhook.KIND_GO, Template: "{{.payload"})
+		assert.ErrorIs(t, err, webhook.ErrInvalidTemplate)
+
+		mockWebhooks.AssertNotCalled(t, "SaveWebhook", mock.Anything)
+	})
+
+	t.Run("rejects webhooks of non-public hosts", func(t *testing.T) {
+		service, mockWebhooks, _, _, _ := setupWebhookService()
+
+		for _, url := range []string{"http://127.0.0.1:6379", "http://10.0.0.5/hook", "https://internal.example/latest/meta-data"} {
+			_, err := service.SetWebhook(&entity.Webhook{FormID: formID, URL: url})
+			assert.ErrorIs(t, err, entity.ErrInvalidWebhook, url)
+		}
+
+		mockWebhooks.AssertNotCalled(t, "SaveWebhook", mock.Anything)
+	})
+
+	t.Run("only editors set webhooks", func(t *testing.T) {
+		service, mockWebhooks, _, mockRepo, _ := setupWebhookService()
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockRepo.On("GetCollaborator", formID, "mallory").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.As("mallory").SetWebhook(&entity.Webhook{FormID: formID, URL: "https://example.com"})
+
+		assert.ErrorIs(t, err, ErrNotEditor)
+		mockWebhooks.AssertNotCalled(t, "SaveWebhook", mock.Anything)
+	})
+
+	t.Run("doesn't change webhooks of other forms", func(t *testing.T) {
+		service, mockWebhooks, _, mockRepo, _ := setupWebhookService()
+		hookID := uuid.New()
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockWebhooks.On("GetWebhook", hookID).Return(&entity.Webhook{ID: hookID, FormID: uuid.New()}, nil)
+
+		_, err := service.SetWebhook(&entity.Webhook{ID: hookID, FormID: formID, URL: "https://example.com"})
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockWebhooks.AssertNotCalled(t, "SaveWebhook", mock.Anything)
+	})
+
+	t.Run("bounds the webhooks of a form", func(t *testing.T) {
+		service, mockWebhooks, _, mockRepo, _ := setupWebhookService()
+		mockRepo.On("Get", formID).Return(form, nil)
+		mockWebhooks.On("ListWebhooks", formID).Return(make([]entity.Webhook, entity.MAX_WEBHOOKS), nil)
+
+		_, err := service.SetWebhook(&entity.Webhook{FormID: formID, URL: "https://example.com"})
+
+		assert.ErrorIs(t, err, ErrTooManyWebhooks)
+	})
+
+	t.Run("fails while webhooks are disabled", func(t *testing.T) {
+		service, _, _, _ := setupService()
+
+		_, err := service.SetWebhook(&entity.Webhook{FormID: formID, URL: "https://example.com"})
+		assert.ErrorIs(t, err, ErrWebhooksDisabled)
+	})
+}
+
+func TestService_RemoveWebhook(t *testing.T) {
+	formID, hookID := uuid.New(), uuid.New()
+	hook := &entity.Webhook{ID: hookID, FormID: formID, URL: "https://example.com"}
+
+	t.Run("removes the webhook", func(t *testing.T) {
+		service, mockWebhooks, _, _, mockPublisher := setupWebhookService()
+		mockWebhooks.On("GetWebhook", hookID).Return(hook, nil)
+		mockWebhooks.On("DeleteWebhook", hookID).Return(nil)
+		mockPublisher.On("Publish", hook, events.WebhookRemoved.String()).Return(nil)
+
+		require.NoError(t, service.RemoveWebhook(formID, hookID))
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("doesn't find webhooks of other forms", func(t *testing.T) {
+		service, mockWebhooks, _, _, _ := setupWebhookService()
+		mockWebhooks.On("GetWebhook", hookID).Return(hook, nil)
+
+		err := service.RemoveWebhook(uuid.New(), hookID)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockWebhooks.AssertNotCalled(t, "DeleteWebhook", hookID)
+	})
+}
+
+func TestService_DeliverWebhooks(t *testing.T) {
+	formID := uuid.New()
+	submission := &entity.ResponseSubmission{
+		FormID:  formID.String(),
+		Answers: []entity.Answer{{OrderNumber: 1, Value: json.RawMessage(`"hello"`)}},
+	}
+
+	t.Run("posts the response rendered by each template", func(t *testing.T) {
+		service, mockWebhooks, sender, _, _ := setupWebhookService()
+		sender.fail = map[string]bool{"https://down.example.com": true}
+		mockWebhooks.On("ListWebhooks", formID).Return([]entity.Webhook{
+			{URL: "https://down.example.com"},
+			{URL: "https://chat.example.com", TemplateKind: webhook.KIND_GO, Template: `{"text": {{json .payload.form_id}}}`},
+			{URL: "https://crm.example.com", TemplateKind: webhook.KIND_JSONPATH, Template: `{"form": "$.payload.form_id", "first": "$.payload.answers[0].value"}`},
+		}, nil)
+
+		require.NoError(t, service.DeliverWebhooks(submission, events.ResponseAccepted.String()))
+
+		assert.Eventually(t, func() bool {
+			return len(sender.posted("https://chat.example.com")) == 1 &&
+				len(sender.posted("https://crm.example.com")) == 1 &&
+				len(sender.posted("https://down.example.com")) == 2
+		}, time.Second, time.Millisecond)
+
+		assert.JSONEq(t, `{"text": "`+formID.String()+`"}`, sender.posted("https://chat.example.com")[0])
+		assert.JSONEq(t, `{"form": "`+formID.String()+`", "first": "hello"}`, sender.posted("https://crm.example.com")[0])
+		assert.Contains(t, sender.posted("https://down.example.com")[0], `"type":"form.response.accepted"`, "without a template the event is posted as is")
+	})
+
+	t.Run("doesn't need a logger for failed deliveries", func(t *testing.T) {
+		service, mockWebhooks, sender, _, _ := setupWebhookService()
+		service.SetLogger(nil)
+		sender.fail = map[string]bool{"https://down.example.com": true}
+		mockWebhooks.On("ListWebhooks", formID).Return([]entity.Webhook{{URL: "https://down.example.com"}}, nil)
+
+		require.NoError(t, service.DeliverWebhooks(submission, events.ResponseAccepted.String()))
+
+		assert.Eventually(t, func() bool {
+			return len(sender.posted("https://down.example.com")) == 2
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("ignores other events", func(t *testing.T) {
+		service, mockWebhooks, _, _, _ := setupWebhookService()
+
+		require.NoError(t, service.DeliverWebhooks(&entity.Form{ID: formID}, events.FormCreated.String()))
+		mockWebhooks.AssertNotCalled(t, "ListWebhooks", mock.Anything)
+	})
+}
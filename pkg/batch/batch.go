@@ -0,0 +1,158 @@
+// Package batch groups writes into batched inserts. Writers block until the
+// batch their item joined is flushed, either once the batch is full or when
+// its interval elapses, so concurrent writers share one statement and still
+// learn whether their item was stored. The longest an item waits adds to the
+// latency of its write; in exchange the database sees a fraction of the
+// statements under load.
+package batch
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/metrics"
+)
+
+const (
+	// DEFAULT_INTERVAL is used when no interval is configured
+	DEFAULT_INTERVAL = 50 * time.Millisecond
+
+	// DEFAULT_MAX_SIZE is used when no batch size is configured
+	DEFAULT_MAX_SIZE = 100
+)
+
+// ErrClosed is returned for writes after Close
+var ErrClosed = errors.New("batch writer is closed")
+
+type (
+	// Flusher stores a batch of items at once. A flusher failing the batch
+	// must store none of it, e.g. by inserting it in one transaction.
+	Flusher[T any] func(items []T) error
+
+	// Options configures a Writer
+	Options struct {
+		Interval time.Duration // Longest an item waits for its batch to be flushed
+		MaxSize  int           // Items per batch; a full batch is flushed right away
+	}
+
+	// pending is the batch items join until it is flushed
+	pending[T any] struct {
+		items []T
+		errs  []error // Result of each item, set once done is closed
+		done  chan struct{}
+		timer *time.Timer
+	}
+
+	// Writer collects items into batches flushed by a Flusher
+	Writer[T any] struct {
+		name  string
+		flush Flusher[T]
+		opts  Options
+
+		mu      sync.Mutex
+		current *pending[T]
+		closed  bool
+	}
+)
+
+// New creates a writer flushing batches through flush. name labels the
+// metrics of the writer.
+func New[T any](name string, flush Flusher[T], opts Options) *Writer[T] {
+	if opts.Interval <= 0 {
+		opts.Interval = DEFAULT_INTERVAL
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DEFAULT_MAX_SIZE
+	}
+
+	return &Writer[T]{
+		name:  name,
+		flush: flush,
+		opts:  opts,
+	}
+}
+
+// Write adds item to the current batch and waits until the batch is flushed
+// Returns the error storing item, ErrClosed once the writer is closed
+func (w *Writer[T]) Write(item T) error {
+	w.mu.Lock()
+
+	if w.closed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+
+	b := w.current
+	if b == nil {
+		b = &pending[T]{done: make(chan struct{})}
+		b.timer = time.AfterFunc(w.opts.Interval, func() { w.flushBatch(b) })
+		w.current = b
+	}
+
+	i := len(b.items)
+	b.items = append(b.items, item)
+	full := len(b.items) >= w.opts.MaxSize
+
+	w.mu.Unlock()
+
+	if full {
+		w.flushBatch(b)
+	}
+
+	<-b.done
+
+	return b.errs[i]
+}
+
+// Close flushes the current batch; later writes fail with ErrClosed
+func (w *Writer[T]) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	b := w.current
+	w.mu.Unlock()
+
+	if b == nil {
+		return nil
+	}
+
+	w.flushBatch(b)
+
+	return errors.Join(b.errs...)
+}
+
+// flushBatch stores b unless it was flushed already. A failing batch is
+// retried item by item, so one bad item doesn't fail the writes of others.
+func (w *Writer[T]) flushBatch(b *pending[T]) {
+	w.mu.Lock()
+	if w.current != b {
+		w.mu.Unlock()
+		return
+	}
+
+	w.current = nil
+	b.timer.Stop()
+	w.mu.Unlock()
+
+	defer close(b.done)
+
+	b.errs = make([]error, len(b.items))
+	metrics.BatchSize.WithLabelValues(w.name).Observe(float64(len(b.items)))
+
+	err := w.flush(b.items)
+	if err == nil {
+		metrics.BatchFlushes.WithLabelValues(w.name, "ok").Inc()
+		return
+	}
+
+	metrics.BatchFlushes.WithLabelValues(w.name, "error").Inc()
+
+	if len(b.items) == 1 {
+		b.errs[0] = err
+		return
+	}
+
+	for i := range b.items {
+		b.errs[i] = w.flush(b.items[i : i+1])
+	}
+}
@@ -0,0 +1,117 @@
+package batch
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a flusher remembering its batches
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	fail    func(items []int) error
+}
+
+func (r *recorder) flush(items []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fail != nil {
+		if err := r.fail(items); err != nil {
+			return err
+		}
+	}
+
+	r.batches = append(r.batches, slices.Clone(items))
+	return nil
+}
+
+// writeAll writes items concurrently and returns their errors by item
+func writeAll(w *Writer[int], items ...int) map[int]error {
+	var mu sync.Mutex
+	errs := make(map[int]error, len(items))
+
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := w.Write(item)
+
+			mu.Lock()
+			errs[item] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func TestWriter_FlushesFullBatches(t *testing.T) {
+	rec := &recorder{}
+	w := New("test", rec.flush, Options{Interval: time.Hour, MaxSize: 3})
+
+	errs := writeAll(w, 1, 2, 3)
+
+	assert.Equal(t, map[int]error{1: nil, 2: nil, 3: nil}, errs)
+	require.Len(t, rec.batches, 1)
+	assert.ElementsMatch(t, []int{1, 2, 3}, rec.batches[0])
+}
+
+func TestWriter_FlushesAfterInterval(t *testing.T) {
+	rec := &recorder{}
+	w := New("test", rec.flush, Options{Interval: 10 * time.Millisecond, MaxSize: 100})
+
+	start := time.Now()
+	errs := writeAll(w, 1, 2)
+
+	assert.Equal(t, map[int]error{1: nil, 2: nil}, errs)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	require.Len(t, rec.batches, 1)
+	assert.ElementsMatch(t, []int{1, 2}, rec.batches[0])
+}
+
+func TestWriter_RetriesFailedBatchesItemByItem(t *testing.T) {
+	bad := errors.New("bad item")
+	rec := &recorder{fail: func(items []int) error {
+		if slices.Contains(items, 2) {
+			return bad
+		}
+		return nil
+	}}
+	w := New("test", rec.flush, Options{Interval: time.Hour, MaxSize: 3})
+
+	errs := writeAll(w, 1, 2, 3)
+
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], bad)
+	assert.NoError(t, errs[3])
+	assert.ElementsMatch(t, [][]int{{1}, {3}}, rec.batches)
+}
+
+func TestWriter_Close(t *testing.T) {
+	rec := &recorder{}
+	w := New("test", rec.flush, Options{Interval: time.Hour, MaxSize: 100})
+
+	done := make(chan error)
+	go func() { done <- w.Write(1) }()
+
+	require.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.current != nil
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, w.Close())
+	assert.NoError(t, <-done)
+	assert.Equal(t, [][]int{{1}}, rec.batches)
+
+	assert.ErrorIs(t, w.Write(2), ErrClosed)
+}
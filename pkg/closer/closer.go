@@ -1,35 +1,107 @@
 package closer
 
 import (
+	"errors"
+	"sort"
+
 	"github.com/Koyo-os/form-service/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// Phase orders shutdown: closers in a lower phase are closed before
+// closers in a higher one. Within a phase, closers run in registration order.
+type Phase int
+
+const (
+	PhaseStopIntake       Phase = iota * 10 // Stop accepting new work (consumers, servers)
+	PhaseDrainWorkers                       // Let in-flight handlers finish
+	PhaseFlushOutbox                        // Deliver buffered outgoing events
+	PhaseCloseConnections                   // Close broker, cache and database connections
+	PhaseCloseLogger                        // Flush and close the logger last
+)
+
+var phaseNames = map[Phase]string{
+	PhaseStopIntake:       "stop_intake",
+	PhaseDrainWorkers:     "drain_workers",
+	PhaseFlushOutbox:      "flush_outbox",
+	PhaseCloseConnections: "close_connections",
+	PhaseCloseLogger:      "close_logger",
+}
+
+func (p Phase) String() string {
+	if name, ok := phaseNames[p]; ok {
+		return name
+	}
+
+	return "custom"
+}
+
 type (
 	Closer interface {
 		Close() error
 	}
 
+	// Func adapts a plain function to the Closer interface
+	Func func() error
+
+	// entry is a closer registered in a phase
+	entry struct {
+		phase  Phase
+		closer Closer
+	}
+
 	CloserGroup struct {
-		closers []Closer
+		closers []entry
 		logger  *logger.Logger
 	}
 )
 
+// Close calls f
+func (f Func) Close() error {
+	return f()
+}
+
+// NewCloserGroup creates a group. Closers passed here are registered in
+// PhaseCloseConnections; use Add to place closers in other phases.
 func NewCloserGroup(logger *logger.Logger, closers ...Closer) *CloserGroup {
-	return &CloserGroup{
-		closers: closers,
-		logger:  logger,
+	group := &CloserGroup{
+		logger: logger,
+	}
+
+	group.Add(PhaseCloseConnections, closers...)
+
+	return group
+}
+
+// Add registers closers in the given phase
+func (c *CloserGroup) Add(phase Phase, closers ...Closer) {
+	for _, closer := range closers {
+		c.closers = append(c.closers, entry{phase: phase, closer: closer})
 	}
 }
 
+// Close closes every registered closer phase by phase. A failing closer
+// doesn't stop the shutdown; all failures are joined into the returned error
 func (c *CloserGroup) Close() error {
-	var err error
+	entries := make([]entry, len(c.closers))
+	copy(entries, c.closers)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].phase < entries[j].phase
+	})
 
-	for _, closer := range c.closers {
-		if err := closer.Close(); err != nil {
-			c.logger.Error("failed close", zap.Error(err))
+	var errs []error
+
+	for _, e := range entries {
+		c.logger.Debug("closing", zap.Stringer("phase", e.phase))
+
+		if err := e.closer.Close(); err != nil {
+			c.logger.Error("failed close",
+				zap.Stringer("phase", e.phase),
+				zap.Error(err))
+			errs = append(errs, err)
 		}
 	}
-	return err
+
+	return errors.Join(errs...)
 }
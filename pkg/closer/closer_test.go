@@ -0,0 +1,52 @@
+package closer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCloserGroup_Close(t *testing.T) {
+	testLogger := &logger.Logger{Logger: zap.NewNop()}
+
+	t.Run("closes phases in order regardless of registration order", func(t *testing.T) {
+		var order []string
+		record := func(name string) Closer {
+			return Func(func() error {
+				order = append(order, name)
+				return nil
+			})
+		}
+
+		group := NewCloserGroup(testLogger, record("redis"))
+		group.Add(PhaseCloseLogger, record("logger"))
+		group.Add(PhaseStopIntake, record("consumer"))
+		group.Add(PhaseCloseConnections, record("rabbitmq"))
+		group.Add(PhaseDrainWorkers, record("listener"))
+
+		assert.NoError(t, group.Close())
+		assert.Equal(t, []string{"consumer", "listener", "redis", "rabbitmq", "logger"}, order)
+	})
+
+	t.Run("keeps closing after a failure and returns it", func(t *testing.T) {
+		closed := false
+
+		group := NewCloserGroup(testLogger)
+		group.Add(PhaseStopIntake, Func(func() error { return errors.New("consumer busy") }))
+		group.Add(PhaseCloseConnections, Func(func() error { closed = true; return nil }))
+
+		err := group.Close()
+
+		assert.EqualError(t, err, "consumer busy")
+		assert.True(t, closed)
+	})
+}
+
+func TestPhase_String(t *testing.T) {
+	assert.Equal(t, "stop_intake", PhaseStopIntake.String())
+	assert.Equal(t, "close_logger", PhaseCloseLogger.String())
+	assert.Equal(t, "custom", Phase(5).String())
+}
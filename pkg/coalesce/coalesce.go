@@ -0,0 +1,194 @@
+// Package coalesce merges bursts of events about the same entity. Rapid
+// successive edits of a form each emit a form.updated carrying the whole
+// form; coalescing holds such events back for a window and publishes only the
+// latest one, so downstream consumers see one event per burst.
+package coalesce
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+type (
+	// Handler publishes an event, e.g. an event bus subscriber
+	Handler func(payload any, eventType string) error
+
+	// Merger is implemented by payloads carrying changes besides the state
+	// of their entity, as form updates with diffs do. Merge returns the
+	// payload replacing both the held back one and the later one, so the
+	// changes of dropped events aren't lost.
+	Merger interface {
+		Merge(later any) any
+	}
+
+	// Options configures a Coalescer
+	Options struct {
+		Window     time.Duration // How long events are held back after the first of a burst
+		EventTypes []string      // Event types that are coalesced, others pass through
+	}
+
+	// pendingEvent is the latest held back event of an entity
+	pendingEvent struct {
+		payload   any
+		eventType string
+		merged    int // Events replaced by this one
+		timer     *time.Timer
+	}
+
+	// Coalescer holds back events of the configured types per entity and
+	// publishes the latest one once the window elapses. Payloads must carry
+	// the complete state of the entity, as forms do, since every event but
+	// the last of a burst is dropped, or implement Merger.
+	Coalescer struct {
+		next       Handler
+		opts       Options
+		eventTypes map[string]struct{}
+		logger     *logger.Logger
+
+		mu      sync.Mutex
+		pending map[string]*pendingEvent // By entity
+		closed  bool
+	}
+)
+
+// New creates a coalescer publishing through next
+func New(logger *logger.Logger, next Handler, opts Options) *Coalescer {
+	eventTypes := make(map[string]struct{}, len(opts.EventTypes))
+	for _, eventType := range opts.EventTypes {
+		eventTypes[eventType] = struct{}{}
+	}
+
+	return &Coalescer{
+		next:       next,
+		opts:       opts,
+		eventTypes: eventTypes,
+		logger:     logger,
+		pending:    make(map[string]*pendingEvent),
+	}
+}
+
+// Publish holds back events of the coalesced types whose payload identifies
+// its entity, replacing an event of the same entity held back already.
+// Any other event is published right away, after flushing the event held
+// back for its entity, so events of an entity keep their order.
+func (c *Coalescer) Publish(payload any, eventType string) error {
+	subject, ok := payload.(entity.DedupSubject)
+	if !ok {
+		return c.next(payload, eventType)
+	}
+
+	key := subject.DedupSubject()
+
+	c.mu.Lock()
+
+	held := c.pending[key]
+	_, coalesced := c.eventTypes[eventType]
+
+	if coalesced && !c.closed && c.opts.Window > 0 {
+		if held != nil && held.eventType == eventType {
+			held.payload = merge(held.payload, payload)
+			held.merged++
+			c.mu.Unlock()
+
+			metrics.EventsCoalesced.WithLabelValues(eventType).Inc()
+			return nil
+		}
+
+		c.takeLocked(key, held)
+
+		event := &pendingEvent{payload: payload, eventType: eventType}
+		event.timer = time.AfterFunc(c.opts.Window, func() { c.flush(key, event) })
+		c.pending[key] = event
+		c.mu.Unlock()
+
+		return c.publishHeld(key, held)
+	}
+
+	c.takeLocked(key, held)
+	c.mu.Unlock()
+
+	return errors.Join(c.publishHeld(key, held), c.next(payload, eventType))
+}
+
+// merge returns the payload replacing held by later
+func merge(held, later any) any {
+	if m, ok := held.(Merger); ok {
+		return m.Merge(later)
+	}
+
+	return later
+}
+
+// Close publishes every held back event; later events pass through
+func (c *Coalescer) Close() error {
+	c.mu.Lock()
+	c.closed = true
+
+	held := c.pending
+	c.pending = make(map[string]*pendingEvent)
+
+	for _, event := range held {
+		event.timer.Stop()
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for key, event := range held {
+		errs = append(errs, c.publishHeld(key, event))
+	}
+
+	return errors.Join(errs...)
+}
+
+// flush publishes event once its window elapsed, unless it was published
+// meanwhile
+func (c *Coalescer) flush(key string, event *pendingEvent) {
+	c.mu.Lock()
+	held := c.pending[key]
+	if held != event {
+		c.mu.Unlock()
+		return
+	}
+
+	c.takeLocked(key, held)
+	c.mu.Unlock()
+
+	if err := c.publishHeld(key, held); err != nil {
+		c.logger.Error("failed to publish coalesced event",
+			zap.String("subject", key),
+			zap.String("event_type", held.eventType),
+			zap.Error(err))
+	}
+}
+
+// takeLocked removes held from the pending events
+func (c *Coalescer) takeLocked(key string, held *pendingEvent) {
+	if held == nil {
+		return
+	}
+
+	held.timer.Stop()
+	delete(c.pending, key)
+}
+
+// publishHeld publishes an event taken from the pending events, nil is a no-op
+func (c *Coalescer) publishHeld(key string, held *pendingEvent) error {
+	if held == nil {
+		return nil
+	}
+
+	if held.merged > 0 {
+		c.logger.Debug("publishing coalesced event",
+			zap.String("subject", key),
+			zap.String("event_type", held.eventType),
+			zap.Int("merged", held.merged))
+	}
+
+	return c.next(held.payload, held.eventType)
+}
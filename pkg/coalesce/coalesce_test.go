@@ -0,0 +1,136 @@
+package coalesce
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type published struct {
+	payload   any
+	eventType string
+}
+
+// recorder is a Handler recording every published event
+type recorder struct {
+	mu     sync.Mutex
+	events []published
+}
+
+func (r *recorder) publish(payload any, eventType string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, published{payload: payload, eventType: eventType})
+	return nil
+}
+
+func (r *recorder) published() []published {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]published(nil), r.events...)
+}
+
+func setupCoalescer(window time.Duration) (*Coalescer, *recorder) {
+	rec := &recorder{}
+	return New(&logger.Logger{Logger: zap.NewNop()}, rec.publish, Options{
+		Window:     window,
+		EventTypes: []string{"form.updated"},
+	}), rec
+}
+
+func TestCoalescer_Publish(t *testing.T) {
+	t.Run("publishes the latest event of a burst", func(t *testing.T) {
+		c, rec := setupCoalescer(20 * time.Millisecond)
+		id := uuid.New()
+
+		for _, title := range []string{"a", "ab", "abc"} {
+			require.NoError(t, c.Publish(&entity.Form{ID: id, Title: title}, "form.updated"))
+		}
+		assert.Empty(t, rec.published())
+
+		assert.Eventually(t, func() bool { return len(rec.published()) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, "abc", rec.published()[0].payload.(*entity.Form).Title)
+	})
+
+	t.Run("merges the diffs of a burst", func(t *testing.T) {
+		c, rec := setupCoalescer(time.Hour)
+		id := uuid.New()
+
+		versions := []*entity.Form{{ID: id, Title: "a"}, {ID: id, Title: "ab"}, {ID: id, Title: "ab", Status: entity.StatusClosed}}
+		for i := 1; i < len(versions); i++ {
+			update, err := entity.NewFormUpdate(versions[i-1], versions[i])
+			require.NoError(t, err)
+			require.NoError(t, c.Publish(update, "form.updated"))
+		}
+		require.NoError(t, c.Close())
+
+		require.Len(t, rec.published(), 1)
+		update := rec.published()[0].payload.(*entity.FormUpdate)
+		assert.Same(t, versions[2], update.Form)
+		assert.Equal(t, []entity.FieldChange{
+			{Path: "Status", Old: []byte(`""`), New: []byte(`"closed"`)},
+			{Path: "Title", Old: []byte(`"a"`), New: []byte(`"ab"`)},
+		}, update.Diff)
+	})
+
+	t.Run("keeps forms apart", func(t *testing.T) {
+		c, rec := setupCoalescer(time.Hour)
+
+		require.NoError(t, c.Publish(&entity.Form{ID: uuid.New()}, "form.updated"))
+		require.NoError(t, c.Publish(&entity.Form{ID: uuid.New()}, "form.updated"))
+		require.NoError(t, c.Close())
+
+		assert.Len(t, rec.published(), 2)
+	})
+
+	t.Run("flushes before other events of the form", func(t *testing.T) {
+		c, rec := setupCoalescer(time.Hour)
+		form := &entity.Form{ID: uuid.New()}
+
+		require.NoError(t, c.Publish(form, "form.updated"))
+		require.NoError(t, c.Publish(form, "form.deleted"))
+
+		assert.Equal(t, []published{
+			{payload: form, eventType: "form.updated"},
+			{payload: form, eventType: "form.deleted"},
+		}, rec.published())
+	})
+
+	t.Run("passes other events through", func(t *testing.T) {
+		c, rec := setupCoalescer(time.Hour)
+
+		require.NoError(t, c.Publish(&entity.Form{ID: uuid.New()}, "form.created"))
+		require.NoError(t, c.Publish("not a form", "form.updated"))
+
+		assert.Len(t, rec.published(), 2)
+	})
+
+	t.Run("disabled without a window", func(t *testing.T) {
+		c, rec := setupCoalescer(0)
+
+		require.NoError(t, c.Publish(&entity.Form{ID: uuid.New()}, "form.updated"))
+
+		assert.Len(t, rec.published(), 1)
+	})
+
+	t.Run("publishes held events on close", func(t *testing.T) {
+		c, rec := setupCoalescer(time.Hour)
+		form := &entity.Form{ID: uuid.New()}
+
+		require.NoError(t, c.Publish(form, "form.updated"))
+		require.NoError(t, c.Close())
+		assert.Len(t, rec.published(), 1)
+
+		require.NoError(t, c.Publish(form, "form.updated"))
+		assert.Len(t, rec.published(), 2, "events after close pass through")
+	})
+}
@@ -1,11 +1,80 @@
 package config
 
+import "os"
+
+// RetryPolicy configures how a single side effect is retried
+type RetryPolicy struct {
+	Retries uint8 `yaml:"retries"`  // Retries after the first attempt, 0 disables retrying
+	DelayMs int   `yaml:"delay_ms"` // Pause between attempts in milliseconds
+}
+
+// HeaderBinding binds a queue to a headers exchange on message attributes
+type HeaderBinding struct {
+	Exchange string            `yaml:"exchange"`
+	Queue    string            `yaml:"queue"`
+	Headers  map[string]string `yaml:"headers"` // Header values messages must carry
+	Match    string            `yaml:"match"`   // "all" headers or "any" of them, all when empty
+}
+
 type Config struct {
 	Reqs struct {
-		CreateRequestType         string `yaml:"create_req_type"`
-		UpdateRequestType         string `yaml:"update_req_type"`
-		DeleteQuestionRequestType string `yaml:"delete_question_req_type"`
-		DeleteFormRequestType     string `yaml:"delete_form_req_type"`
+		CreateRequestType             string `yaml:"create_req_type"`
+		UpdateRequestType             string `yaml:"update_req_type"`
+		DeleteQuestionRequestType     string `yaml:"delete_question_req_type"`
+		DeleteFormRequestType         string `yaml:"delete_form_req_type"`
+		RequestUploadRequestType      string `yaml:"request_upload_req_type"`
+		CompleteUploadRequestType     string `yaml:"complete_upload_req_type"`
+		SaveDraftRequestType          string `yaml:"save_draft_req_type"`
+		SetAccessRequestType          string `yaml:"set_access_req_type"`
+		CreateInviteRequestType       string `yaml:"create_invite_req_type"`
+		RevokeInviteRequestType       string `yaml:"revoke_invite_req_type"`
+		ListInvitesRequestType        string `yaml:"list_invites_req_type"`
+		TenantSettingsRequestType     string `yaml:"tenant_settings_req_type"`
+		SubmitResponseRequestType     string `yaml:"submit_response_req_type"`
+		SaveFormRequestType           string `yaml:"save_form_req_type"`
+		QuotaGetRequestType           string `yaml:"quota_get_req_type"`
+		QuotaUpdateRequestType        string `yaml:"quota_update_req_type"`
+		QuestionStatsRequestType      string `yaml:"question_stats_req_type"`
+		ListResponsesRequestType      string `yaml:"list_responses_req_type"`
+		PublishFormRequestType        string `yaml:"publish_form_req_type"`
+		FormVersionRequestType        string `yaml:"form_version_req_type"`
+		SetWorkersRequestType         string `yaml:"set_workers_req_type"`
+		CreateTemplateRequestType     string `yaml:"create_template_req_type"`
+		FromTemplateRequestType       string `yaml:"from_template_req_type"`
+		CloneFormRequestType          string `yaml:"clone_form_req_type"`
+		SettingsRequestType           string `yaml:"settings_req_type"`
+		ThemeRequestType              string `yaml:"theme_req_type"`
+		CreateSectionRequestType      string `yaml:"create_section_req_type"`
+		UpdateSectionRequestType      string `yaml:"update_section_req_type"`
+		DeleteSectionRequestType      string `yaml:"delete_section_req_type"`
+		ReorderSectionRequestType     string `yaml:"reorder_section_req_type"`
+		MoveQuestionRequestType       string `yaml:"move_question_req_type"`
+		ReorderQuestionRequestType    string `yaml:"reorder_question_req_type"`
+		ImportQuestionsRequestType    string `yaml:"import_questions_req_type"`
+		ExportFormRequestType         string `yaml:"export_form_req_type"`
+		ImportFormRequestType         string `yaml:"import_form_req_type"`
+		SetLogicRequestType           string `yaml:"set_logic_req_type"`
+		PublicFormRequestType         string `yaml:"public_form_req_type"`
+		FormMetadataRequestType       string `yaml:"form_metadata_req_type"`
+		SetTagsRequestType            string `yaml:"set_tags_req_type"`
+		FormsByTagRequestType         string `yaml:"forms_by_tag_req_type"`
+		NumberingRequestType          string `yaml:"numbering_req_type"`
+		RestoreFormRequestType        string `yaml:"restore_form_req_type"`
+		PurgeFormRequestType          string `yaml:"purge_form_req_type"`
+		ArchiveFormRequestType        string `yaml:"archive_form_req_type"`
+		UnarchiveRequestType          string `yaml:"unarchive_req_type"`
+		SetStatusRequestType          string `yaml:"set_status_req_type"`
+		SetScheduleRequestType        string `yaml:"set_schedule_req_type"`
+		FormAtRequestType             string `yaml:"form_at_req_type"`
+		InspectCacheRequestType       string `yaml:"inspect_cache_req_type"`
+		GetFormRequestType            string `yaml:"get_form_req_type"`
+		AddCollaboratorRequestType    string `yaml:"add_collaborator_req_type"`
+		RemoveCollaboratorRequestType string `yaml:"remove_collaborator_req_type"`
+		ListCollaboratorsRequestType  string `yaml:"list_collaborators_req_type"`
+		SetWebhookRequestType         string `yaml:"set_webhook_req_type"`
+		RemoveWebhookRequestType      string `yaml:"remove_webhook_req_type"`
+		ExportRespondentRequestType   string `yaml:"export_respondent_req_type"`
+		EraseRespondentRequestType    string `yaml:"erase_respondent_req_type"`
 	} `yaml:"reqs"`
 	Urls struct {
 		Redis    string `yaml:"redis"`
@@ -14,29 +83,301 @@ type Config struct {
 	Exchange struct {
 		Request string `yaml:"request"`
 		Output  string `yaml:"output"`
+		Headers string `yaml:"headers"` // Headers exchange events are also published to, empty disables
 	} `yaml:"exchange"`
 	Queue struct {
 		Request string `yaml:"request"`
 		Output  string `yaml:"output"`
 	} `yaml:"queue"`
 	HealthCheck struct {
-		Port string `yaml:"port"`
-		Use  bool   `yaml:"use"`
+		Port         string `yaml:"port"`
+		Use          bool   `yaml:"use"`
+		Host         string `yaml:"host"`           // Interface to bind, empty binds every interface
+		Token        string `yaml:"token"`          // Bearer token protecting /metrics, /debug/* and /admin/*; empty leaves /metrics open and disables /debug/* and /admin/*
+		CertFile     string `yaml:"cert_file"`      // Server certificate, enables TLS together with key_file
+		KeyFile      string `yaml:"key_file"`       // Server private key
+		ClientCAFile string `yaml:"client_ca_file"` // CA of client certificates accepted instead of the token
 	} `yaml:"health"`
+	Database struct {
+		TablePrefix    string `yaml:"table_prefix"`    // Prefix of every service table, for sharing a schema with other services
+		SingularTables bool   `yaml:"singular_tables"` // Name tables form, question instead of forms, questions
+	} `yaml:"database"`
+	Migrations struct {
+		Auto       bool   `yaml:"auto"`        // Migrate the schema on startup, unless the database is already newer
+		OnMismatch string `yaml:"on_mismatch"` // refuse to exit or read_only to start rejecting writes when versions differ
+	} `yaml:"migrations"`
+	Publisher struct {
+		PendingBuffer     int    `yaml:"pending_buffer"`      // Max messages kept while the broker is unreachable
+		ReconnectDelay    int    `yaml:"reconnect_delay"`     // Initial reconnect delay in seconds
+		MaxReconnectDelay int    `yaml:"max_reconnect_delay"` // Upper bound for the reconnect backoff in seconds
+		PendingFile       string `yaml:"pending_file"`        // File buffered messages are persisted to and replayed from, empty keeps them in memory
+		FormUpdates       string `yaml:"form_updates"`        // snapshot or snapshot_diff to add the field-level diff from the previous version to form.updated
+		Envelope          string `yaml:"envelope"`            // Casing of the envelope fields, snake or the deprecated legacy (TimeStamp) for older consumers
+	} `yaml:"publisher"`
+	Retry struct {
+		Cache   RetryPolicy `yaml:"cache"`
+		Publish RetryPolicy `yaml:"publish"`
+	} `yaml:"retry"`
+	Watchdog struct {
+		Policy       string `yaml:"policy"`        // none, restart or exit
+		RestartDelay int    `yaml:"restart_delay"` // Seconds before a restart
+		MaxIdle      int    `yaml:"max_idle"`      // Seconds without activity before unhealthy, 0 disables
+	} `yaml:"watchdog"`
+	Storage struct {
+		Endpoint string `yaml:"endpoint"` // S3-compatible endpoint, empty disables uploads
+		Bucket   string `yaml:"bucket"`
+		UseSSL   bool   `yaml:"use_ssl"`
+	} `yaml:"storage"`
+	Uploads struct {
+		UrlTTL          int `yaml:"url_ttl"`          // Seconds an upload URL stays valid
+		CleanupInterval int `yaml:"cleanup_interval"` // Seconds between orphaned upload cleanups
+	} `yaml:"uploads"`
+	Tenants struct {
+		RateLimit     int `yaml:"rate_limit"`     // Default requests per minute, 0 means unlimited
+		MaxQuestions  int `yaml:"max_questions"`  // Default max questions per form, 0 means unlimited
+		RetentionDays int `yaml:"retention_days"` // Default response retention, 0 keeps forever
+		CacheTTL      int `yaml:"cache_ttl"`      // Seconds resolved tenant settings are cached
+	} `yaml:"tenants"`
+	Topology struct {
+		LegacyRequestQueue    string `yaml:"legacy_request_queue"`    // Old request queue drained alongside queue.request during a cutover, empty disables
+		LegacyRequestExchange string `yaml:"legacy_request_exchange"` // Exchange the old queue is bound to, defaults to exchange.request
+		LegacyOutputExchange  string `yaml:"legacy_output_exchange"`  // Old output exchange published to while dual_publish is on
+		DualPublish           bool   `yaml:"dual_publish"`            // Publish every event to both output exchanges
+		DedupWindow           int    `yaml:"dedup_window"`            // Seconds an event ID is remembered to drop copies read from both queues
+	} `yaml:"topology"`
+	Throttle struct {
+		Interval          int `yaml:"interval"`           // Seconds between database checks, 0 disables throttling
+		FailureThreshold  int `yaml:"failure_threshold"`  // Failed checks in a row before consumption is paused
+		RecoveryThreshold int `yaml:"recovery_threshold"` // Passed checks in a row before consumption resumes
+	} `yaml:"throttle"`
+	RateLimit struct {
+		Mode               string `yaml:"mode"`                 // open or closed, the outcome while Redis is unavailable
+		ResponsesPerMinute int    `yaml:"responses_per_minute"` // Submissions per respondent and form, 0 disables spam protection
+	} `yaml:"rate_limit"`
+	Quotas struct {
+		MaxForms            int `yaml:"max_forms"`              // Default forms an author may own, 0 means unlimited
+		MaxQuestions        int `yaml:"max_questions"`          // Default questions over all forms of an author, 0 means unlimited
+		MaxQuestionsPerForm int `yaml:"max_questions_per_form"` // Default questions of any single form of an author, 0 means unlimited
+	} `yaml:"quotas"`
+	Stats struct {
+		PersistInterval int  `yaml:"persist_interval"` // Seconds between moving question stats from Redis to the database, 0 disables stats
+		FormCounters    bool `yaml:"form_counters"`    // Whether views and submissions of every form are counted
+	} `yaml:"stats"`
+	Coalesce struct {
+		WindowMs   int      `yaml:"window_ms"`   // Milliseconds events of a form are held back to publish only the latest, 0 disables coalescing
+		EventTypes []string `yaml:"event_types"` // Event types that are coalesced
+	} `yaml:"coalesce"`
+	Listener struct {
+		Workers      int            `yaml:"workers"`       // Events handled concurrently; events of a form may finish out of order above 1
+		Concurrency  map[string]int `yaml:"concurrency"`   // Events of a request type handled at once, e.g. to cap heavy imports
+		LoadInterval int            `yaml:"load_interval"` // Seconds between service.load events for autoscalers, 0 disables them
+	} `yaml:"listener"`
+	Telemetry struct {
+		Enabled      bool   `yaml:"enabled"`       // Instrument the database, Redis and AMQP clients
+		OTLPEndpoint string `yaml:"otlp_endpoint"` // host:port of an OTLP/HTTP trace collector, empty exports no spans
+		Insecure     bool   `yaml:"insecure"`      // Export spans over plain HTTP
+	} `yaml:"telemetry"`
+	Cache struct {
+		MaxBytes      int64 `yaml:"max_bytes"`       // Soft limit of the bytes cached, 0 disables the quota
+		ReducedTTL    int   `yaml:"reduced_ttl"`     // Seconds forms are cached for while over the limit, 0 keeps them until deleted
+		MaxEntryBytes int64 `yaml:"max_entry_bytes"` // Forms larger than this aren't cached while over the limit, 0 caches any size
+		UsageInterval int   `yaml:"usage_interval"`  // Seconds between measuring the cache size, 0 disables measuring and the quota
+	} `yaml:"cache"`
+	Reconcile struct {
+		OnStartup  bool `yaml:"on_startup"`  // Check a sample of the cached forms against the database at boot
+		Interval   int  `yaml:"interval"`    // Seconds between checks, 0 disables scheduled checks
+		SampleSize int  `yaml:"sample_size"` // Cached forms checked per run
+		Repair     bool `yaml:"repair"`      // Fix divergent cached forms, otherwise only report them
+	} `yaml:"reconcile"`
+	Integrity struct {
+		Interval   int  `yaml:"interval"`    // Seconds between checks for orphaned questions and divergent question counts, 0 disables them
+		SampleSize int  `yaml:"sample_size"` // Forms checked and most orphaned questions handled per run
+		Repair     bool `yaml:"repair"`      // Remove orphaned questions and fix divergent cached forms, otherwise only report them
+	} `yaml:"integrity"`
+	Responses struct {
+		Store           bool `yaml:"store"`             // Store accepted responses in the database, otherwise they are only published
+		BatchIntervalMs int  `yaml:"batch_interval_ms"` // Milliseconds a stored response may wait to be inserted with others, 0 inserts each on its own
+		BatchSize       int  `yaml:"batch_size"`        // Responses inserted at once, a full batch is inserted right away
+	} `yaml:"responses"`
+	QueryCache struct {
+		TTL int `yaml:"ttl"` // Seconds form and response listings are cached in Redis, 0 disables caching
+	} `yaml:"query_cache"`
+	ReadModel struct {
+		Enabled bool `yaml:"enabled"` // Serve respondents from a projection of the published, open forms
+	} `yaml:"read_model"`
+	Webhooks struct {
+		Enabled bool        `yaml:"enabled"` // Let form owners add webhooks the accepted responses are posted to
+		Timeout int         `yaml:"timeout"` // Seconds a webhook has to answer
+		Retry   RetryPolicy `yaml:"retry"`   // Retries of a webhook that fails or doesn't answer
+	} `yaml:"webhooks"`
+	Schedules struct {
+		Interval int `yaml:"interval"` // Seconds between opening and closing the scheduled forms that are due, 0 disables schedules
+	} `yaml:"schedules"`
+	Heartbeats struct {
+		Queue      string   `yaml:"queue"`       // Control queue downstream consumers send heartbeats to, empty disables tracking
+		Exchange   string   `yaml:"exchange"`    // Exchange the heartbeats are published on
+		RoutingKey string   `yaml:"routing_key"` // Binding of the control queue
+		Type       string   `yaml:"type"`        // Event type of heartbeats
+		Consumers  []string `yaml:"consumers"`   // Consumers expected to send heartbeats, reported on /statusz
+		MaxAge     int      `yaml:"max_age"`     // Seconds after which the last heartbeat of a consumer is stale
+	} `yaml:"heartbeats"`
+	Loopback struct {
+		Queue      string   `yaml:"queue"`       // Queue the published events come back on, suffixed with the instance name; empty disables verifying them
+		Timeout    int      `yaml:"timeout"`     // Seconds a published event has to come back before it counts as missing
+		Interval   int      `yaml:"interval"`    // Seconds between checks for missing events
+		EventTypes []string `yaml:"event_types"` // Events verified, the mutations of forms by default
+	} `yaml:"loopback"`
+	Delivery struct {
+		ManualAck          bool `yaml:"manual_ack"`          // Acknowledge requests once handled instead of on receipt, and create forms exactly once
+		Prefetch           int  `yaml:"prefetch"`            // Unacknowledged requests delivered at once with manual_ack
+		ProcessedRetention int  `yaml:"processed_retention"` // Hours the IDs of processed requests are kept to recognize redeliveries, 0 keeps them
+	} `yaml:"delivery"`
+	Expiry struct {
+		MessageTTL         int    `yaml:"message_ttl"`          // Seconds a request may wait in its queue before the broker expires it, 0 never; changing it needs the queues recreated
+		MaxAge             int    `yaml:"max_age"`              // Seconds since a request was sent after which it expires on receipt, 0 never
+		DeadLetterExchange string `yaml:"dead_letter_exchange"` // Exchange expired and rejected requests are routed to, empty drops them
+		DeadLetterQueue    string `yaml:"dead_letter_queue"`    // Queue keeping the dead-lettered requests, empty declares none
+		DeadLetterAlert    int    `yaml:"dead_letter_alert"`    // Requests in the dead letter queue above which ops.dead_letter.threshold_exceeded is published, 0 never
+	} `yaml:"expiry"`
+	EventStream struct {
+		Enabled   bool   `yaml:"enabled"`    // Buffer received events in a Redis stream until handled, so they survive a crash
+		Stream    string `yaml:"stream"`     // Stream key
+		Group     string `yaml:"group"`      // Consumer group shared by every instance
+		MaxLen    int64  `yaml:"max_len"`    // Cap on the events not handled yet, beyond it events skip the stream; 0 doesn't cap it
+		ClaimIdle int    `yaml:"claim_idle"` // Seconds after which events left by a crashed instance are handled by another
+	} `yaml:"event_stream"`
+	SchemaRegistry struct {
+		URL      string `yaml:"url"`      // Confluent-compatible registry the payload schemas are checked against and registered with at startup, empty disables it
+		Username string `yaml:"username"` // Basic auth user, empty for none
+		Password string `yaml:"password"` // Basic auth password
+		Timeout  int    `yaml:"timeout"`  // Seconds each registry request may take
+	} `yaml:"schema_registry"`
+	HeaderBindings []HeaderBinding `yaml:"header_bindings"` // Extra request queue bindings matching message headers
+	Admins         []string        `yaml:"admins"`          // Users allowed privacy requests, cache inspection and quota overrides
+	Features       map[string]bool `yaml:"features"`        // Feature flags reported on /version
 }
 
+// Init resolves the configuration: the defaults below, overridden by the
+// config file at path, if there is one, and then by the environment, see
+// ENV_PREFIX. An empty path skips the file.
 func Init(path string) (*Config, error) {
-	return &Config{
+	cfg := &Config{
 		Reqs: struct {
-			CreateRequestType         string `yaml:"create_req_type"`
-			UpdateRequestType         string `yaml:"update_req_type"`
-			DeleteQuestionRequestType string `yaml:"delete_question_req_type"`
-			DeleteFormRequestType     string `yaml:"delete_form_req_type"`
+			CreateRequestType             string `yaml:"create_req_type"`
+			UpdateRequestType             string `yaml:"update_req_type"`
+			DeleteQuestionRequestType     string `yaml:"delete_question_req_type"`
+			DeleteFormRequestType         string `yaml:"delete_form_req_type"`
+			RequestUploadRequestType      string `yaml:"request_upload_req_type"`
+			CompleteUploadRequestType     string `yaml:"complete_upload_req_type"`
+			SaveDraftRequestType          string `yaml:"save_draft_req_type"`
+			SetAccessRequestType          string `yaml:"set_access_req_type"`
+			CreateInviteRequestType       string `yaml:"create_invite_req_type"`
+			RevokeInviteRequestType       string `yaml:"revoke_invite_req_type"`
+			ListInvitesRequestType        string `yaml:"list_invites_req_type"`
+			TenantSettingsRequestType     string `yaml:"tenant_settings_req_type"`
+			SubmitResponseRequestType     string `yaml:"submit_response_req_type"`
+			SaveFormRequestType           string `yaml:"save_form_req_type"`
+			QuotaGetRequestType           string `yaml:"quota_get_req_type"`
+			QuotaUpdateRequestType        string `yaml:"quota_update_req_type"`
+			QuestionStatsRequestType      string `yaml:"question_stats_req_type"`
+			ListResponsesRequestType      string `yaml:"list_responses_req_type"`
+			PublishFormRequestType        string `yaml:"publish_form_req_type"`
+			FormVersionRequestType        string `yaml:"form_version_req_type"`
+			SetWorkersRequestType         string `yaml:"set_workers_req_type"`
+			CreateTemplateRequestType     string `yaml:"create_template_req_type"`
+			FromTemplateRequestType       string `yaml:"from_template_req_type"`
+			CloneFormRequestType          string `yaml:"clone_form_req_type"`
+			SettingsRequestType           string `yaml:"settings_req_type"`
+			ThemeRequestType              string `yaml:"theme_req_type"`
+			CreateSectionRequestType      string `yaml:"create_section_req_type"`
+			UpdateSectionRequestType      string `yaml:"update_section_req_type"`
+			DeleteSectionRequestType      string `yaml:"delete_section_req_type"`
+			ReorderSectionRequestType     string `yaml:"reorder_section_req_type"`
+			MoveQuestionRequestType       string `yaml:"move_question_req_type"`
+			ReorderQuestionRequestType    string `yaml:"reorder_question_req_type"`
+			ImportQuestionsRequestType    string `yaml:"import_questions_req_type"`
+			ExportFormRequestType         string `yaml:"export_form_req_type"`
+			ImportFormRequestType         string `yaml:"import_form_req_type"`
+			SetLogicRequestType           string `yaml:"set_logic_req_type"`
+			PublicFormRequestType         string `yaml:"public_form_req_type"`
+			FormMetadataRequestType       string `yaml:"form_metadata_req_type"`
+			SetTagsRequestType            string `yaml:"set_tags_req_type"`
+			FormsByTagRequestType         string `yaml:"forms_by_tag_req_type"`
+			NumberingRequestType          string `yaml:"numbering_req_type"`
+			RestoreFormRequestType        string `yaml:"restore_form_req_type"`
+			PurgeFormRequestType          string `yaml:"purge_form_req_type"`
+			ArchiveFormRequestType        string `yaml:"archive_form_req_type"`
+			UnarchiveRequestType          string `yaml:"unarchive_req_type"`
+			SetStatusRequestType          string `yaml:"set_status_req_type"`
+			SetScheduleRequestType        string `yaml:"set_schedule_req_type"`
+			FormAtRequestType             string `yaml:"form_at_req_type"`
+			InspectCacheRequestType       string `yaml:"inspect_cache_req_type"`
+			GetFormRequestType            string `yaml:"get_form_req_type"`
+			AddCollaboratorRequestType    string `yaml:"add_collaborator_req_type"`
+			RemoveCollaboratorRequestType string `yaml:"remove_collaborator_req_type"`
+			ListCollaboratorsRequestType  string `yaml:"list_collaborators_req_type"`
+			SetWebhookRequestType         string `yaml:"set_webhook_req_type"`
+			RemoveWebhookRequestType      string `yaml:"remove_webhook_req_type"`
+			ExportRespondentRequestType   string `yaml:"export_respondent_req_type"`
+			EraseRespondentRequestType    string `yaml:"erase_respondent_req_type"`
 		}{
-			CreateRequestType:         "request.form.created",
-			UpdateRequestType:         "request.form.updated",
-			DeleteQuestionRequestType: "request.question.deleted",
-			DeleteFormRequestType:     "request.form.deleted",
+			CreateRequestType:             "request.form.created",
+			UpdateRequestType:             "request.form.updated",
+			DeleteQuestionRequestType:     "request.question.deleted",
+			DeleteFormRequestType:         "request.form.deleted",
+			RequestUploadRequestType:      "request.upload.requested",
+			CompleteUploadRequestType:     "request.upload.completed",
+			SaveDraftRequestType:          "request.response.draft",
+			SetAccessRequestType:          "request.form.access",
+			CreateInviteRequestType:       "request.invite.created",
+			RevokeInviteRequestType:       "request.invite.revoked",
+			ListInvitesRequestType:        "request.invite.list",
+			TenantSettingsRequestType:     "request.tenant.settings",
+			SubmitResponseRequestType:     "request.response.submitted",
+			SaveFormRequestType:           "request.form.saved",
+			QuotaGetRequestType:           "request.quota.get",
+			QuotaUpdateRequestType:        "request.quota.updated",
+			QuestionStatsRequestType:      "request.question.stats",
+			ListResponsesRequestType:      "request.response.list",
+			PublishFormRequestType:        "request.form.publish",
+			FormVersionRequestType:        "request.form.version",
+			SetWorkersRequestType:         "request.admin.workers",
+			CreateTemplateRequestType:     "request.template.created",
+			FromTemplateRequestType:       "request.form.from_template",
+			CloneFormRequestType:          "request.form.clone",
+			SettingsRequestType:           "request.form.settings",
+			ThemeRequestType:              "request.form.theme",
+			CreateSectionRequestType:      "request.section.created",
+			UpdateSectionRequestType:      "request.section.updated",
+			DeleteSectionRequestType:      "request.section.deleted",
+			ReorderSectionRequestType:     "request.section.reordered",
+			MoveQuestionRequestType:       "request.question.moved",
+			ReorderQuestionRequestType:    "request.question.reordered",
+			ImportQuestionsRequestType:    "request.question.imported",
+			ExportFormRequestType:         "request.form.export",
+			ImportFormRequestType:         "request.form.import",
+			SetLogicRequestType:           "request.form.logic",
+			PublicFormRequestType:         "request.form.public",
+			FormMetadataRequestType:       "request.form.metadata",
+			SetTagsRequestType:            "request.form.tags",
+			FormsByTagRequestType:         "request.form.by_tag",
+			NumberingRequestType:          "request.form.numbering",
+			RestoreFormRequestType:        "request.form.restored",
+			PurgeFormRequestType:          "request.form.purged",
+			ArchiveFormRequestType:        "request.form.archived",
+			UnarchiveRequestType:          "request.form.unarchived",
+			SetStatusRequestType:          "request.form.status",
+			SetScheduleRequestType:        "request.form.schedule",
+			FormAtRequestType:             "request.form.get_at",
+			InspectCacheRequestType:       "request.admin.cache",
+			GetFormRequestType:            "request.form.get",
+			AddCollaboratorRequestType:    "request.form.collaborator.add",
+			RemoveCollaboratorRequestType: "request.form.collaborator.remove",
+			ListCollaboratorsRequestType:  "request.form.collaborator.list",
+			SetWebhookRequestType:         "request.form.webhook.set",
+			RemoveWebhookRequestType:      "request.form.webhook.remove",
+			ExportRespondentRequestType:   "privacy.export_respondent",
+			EraseRespondentRequestType:    "privacy.erase_respondent",
 		},
 		Urls: struct {
 			Redis    string `yaml:"redis"`
@@ -48,6 +389,7 @@ func Init(path string) (*Config, error) {
 		Exchange: struct {
 			Request string `yaml:"request"`
 			Output  string `yaml:"output"`
+			Headers string `yaml:"headers"`
 		}{
 			Request: "request",
 			Output:  "output",
@@ -59,5 +401,115 @@ func Init(path string) (*Config, error) {
 			Request: "request",
 			Output:  "output",
 		},
-	}, nil
+	}
+
+	cfg.HealthCheck.Port = "8080"
+	cfg.HealthCheck.Use = true
+
+	cfg.Migrations.Auto = true
+	cfg.Migrations.OnMismatch = "refuse"
+
+	cfg.Publisher.PendingBuffer = 100
+	cfg.Publisher.ReconnectDelay = 1
+	cfg.Publisher.MaxReconnectDelay = 30
+	cfg.Publisher.PendingFile = "data/pending-events.json"
+	cfg.Publisher.FormUpdates = "snapshot"
+	cfg.Publisher.Envelope = "snake"
+
+	cfg.Retry.Cache = RetryPolicy{Retries: 2, DelayMs: 5}
+	cfg.Retry.Publish = RetryPolicy{Retries: 2, DelayMs: 5}
+
+	cfg.Webhooks.Timeout = 10
+	cfg.Webhooks.Retry = RetryPolicy{Retries: 2, DelayMs: 500}
+
+	cfg.Watchdog.Policy = "restart"
+	cfg.Watchdog.RestartDelay = 1
+	cfg.Watchdog.MaxIdle = 60
+
+	cfg.Storage.Endpoint = "minio:9000"
+	cfg.Storage.Bucket = "form-uploads"
+
+	cfg.Uploads.UrlTTL = 900
+	cfg.Uploads.CleanupInterval = 300
+
+	cfg.Tenants.RateLimit = 600
+	cfg.Tenants.MaxQuestions = 100
+	cfg.Tenants.RetentionDays = 365
+	cfg.Tenants.CacheTTL = 60
+
+	cfg.Topology.DedupWindow = 600
+
+	cfg.Throttle.Interval = 5
+	cfg.Throttle.FailureThreshold = 3
+	cfg.Throttle.RecoveryThreshold = 2
+
+	cfg.RateLimit.Mode = "open"
+	cfg.RateLimit.ResponsesPerMinute = 5
+
+	cfg.Quotas.MaxForms = 100
+	cfg.Quotas.MaxQuestions = 5000
+	cfg.Quotas.MaxQuestionsPerForm = 500
+
+	cfg.Stats.PersistInterval = 60
+	cfg.Stats.FormCounters = true
+
+	cfg.Coalesce.EventTypes = []string{"form.updated"}
+
+	cfg.Listener.Workers = 1
+
+	cfg.Telemetry.Enabled = true
+
+	cfg.Cache.ReducedTTL = 300
+	cfg.Cache.MaxEntryBytes = 64 * 1024
+	cfg.Cache.UsageInterval = 60
+
+	cfg.Reconcile.SampleSize = 100
+	cfg.Reconcile.Repair = true
+
+	cfg.Integrity.Interval = 3600
+	cfg.Integrity.SampleSize = 100
+
+	cfg.Responses.Store = true
+	cfg.Responses.BatchSize = 100
+
+	cfg.Schedules.Interval = 30
+
+	cfg.Heartbeats.Exchange = "control"
+	cfg.Heartbeats.RoutingKey = "heartbeat.*"
+	cfg.Heartbeats.Type = "consumer.heartbeat"
+	cfg.Heartbeats.MaxAge = 90
+
+	cfg.Loopback.Timeout = 30
+	cfg.Loopback.Interval = 10
+	cfg.Loopback.EventTypes = []string{
+		"form.created", "form.updated", "form.deleted", "form.restored", "form.purged",
+		"form.archived", "form.unarchived", "form.opened", "form.closed", "form.published",
+	}
+
+	cfg.Delivery.Prefetch = 50
+	cfg.Delivery.ProcessedRetention = 168
+
+	cfg.EventStream.Stream = "form-service:requests"
+	cfg.EventStream.Group = "listener"
+	cfg.EventStream.MaxLen = 100000
+	cfg.EventStream.ClaimIdle = 60
+
+	cfg.SchemaRegistry.Timeout = 10
+
+	cfg.Features = map[string]bool{
+		"dry_run":         true,
+		"progress_events": false, // Emit form.response.* events for saved drafts
+	}
+
+	if path != "" {
+		if err := cfg.load(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
 }
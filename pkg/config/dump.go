@@ -0,0 +1,111 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MASK replaces secret values in configuration dumps
+const MASK = "***"
+
+// secretKeys are key fragments whose values are always masked
+var secretKeys = []string{"password", "secret", "token", "credential", "access_key", "dsn"}
+
+// dsnCredentials matches the user:password@ prefix of a database DSN
+var dsnCredentials = regexp.MustCompile(`^([^:@/]*):([^@]*)@`)
+
+// Dump returns the effective configuration keyed like the config file,
+// with secrets masked, e.g. for logging at startup
+func (c *Config) Dump() (map[string]any, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	dump := make(map[string]any)
+	if err = yaml.Unmarshal(data, &dump); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	return maskMap(dump), nil
+}
+
+// MaskDSN hides the password of a DSN or URL, keeping the rest readable
+func MaskDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), MASK)
+			return strings.Replace(u.String(), url.QueryEscape(MASK), MASK, 1)
+		}
+		return dsn
+	}
+
+	return dsnCredentials.ReplaceAllString(dsn, "${1}:"+MASK+"@")
+}
+
+// Handler serves the masked effective configuration as JSON
+func Handler(cfg *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dump, err := cfg.Dump()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err = json.NewEncoder(w).Encode(dump); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// maskMap masks values under secret keys and credentials embedded in strings
+func maskMap(m map[string]any) map[string]any {
+	for key, value := range m {
+		if isSecretKey(key) {
+			if value != nil && value != "" {
+				m[key] = MASK
+			}
+			continue
+		}
+
+		m[key] = maskValue(value)
+	}
+
+	return m
+}
+
+func maskValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return maskMap(v)
+	case []any:
+		for i := range v {
+			v[i] = maskValue(v[i])
+		}
+		return v
+	case string:
+		return MaskDSN(v)
+	default:
+		return v
+	}
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, fragment := range secretKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig writes a config file into a temporary directory
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestInit_ShippedConfigMatchesDefaults(t *testing.T) {
+	defaults, err := Init("")
+	require.NoError(t, err)
+
+	shipped, err := Init("../../config.yaml")
+	require.NoError(t, err)
+
+	want, err := defaults.Dump()
+	require.NoError(t, err)
+	got, err := shipped.Dump()
+	require.NoError(t, err)
+
+	assert.Equal(t, want, got)
+}
+
+func TestInit_File(t *testing.T) {
+	t.Run("overrides the defaults it sets", func(t *testing.T) {
+		path := writeConfig(t, "health:\n  host: 127.0.0.1\nretry:\n  cache:\n    retries: 5\n")
+
+		cfg, err := Init(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, "127.0.0.1", cfg.HealthCheck.Host)
+		assert.Equal(t, uint8(5), cfg.Retry.Cache.Retries)
+		assert.Equal(t, 5, cfg.Retry.Cache.DelayMs, "keys the file leaves out keep their default")
+		assert.Equal(t, "8080", cfg.HealthCheck.Port)
+	})
+
+	t.Run("rejects unknown keys", func(t *testing.T) {
+		path := writeConfig(t, "urls:\n  rabbbitmq: amqp://rabbitmq:5672\n")
+
+		_, err := Init(path)
+
+		assert.ErrorContains(t, err, "rabbbitmq")
+	})
+
+	t.Run("keeps the defaults without a file", func(t *testing.T) {
+		cfg, err := Init(filepath.Join(t.TempDir(), "missing.yaml"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "request.form.created", cfg.Reqs.CreateRequestType)
+	})
+}
+
+func TestInit_Env(t *testing.T) {
+	path := writeConfig(t, "health:\n  token: from-file\n")
+	t.Setenv("FORM_SERVICE_HEALTH_TOKEN", "from-env")
+	t.Setenv("FORM_SERVICE_RECONCILE_REPAIR", "false")
+	t.Setenv("FORM_SERVICE_RETRY_PUBLISH_DELAY_MS", "250")
+	t.Setenv("FORM_SERVICE_LOOPBACK_EVENT_TYPES", "form.created, form.deleted")
+
+	cfg, err := Init(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "from-env", cfg.HealthCheck.Token, "the environment overrides the file")
+	assert.False(t, cfg.Reconcile.Repair)
+	assert.Equal(t, 250, cfg.Retry.Publish.DelayMs)
+	assert.Equal(t, []string{"form.created", "form.deleted"}, cfg.Loopback.EventTypes)
+
+	dump, err := cfg.Dump()
+	require.NoError(t, err)
+
+	assert.Equal(t, MASK, dump["health"].(map[string]any)["token"])
+	assert.Equal(t, 250, dump["retry"].(map[string]any)["publish"].(map[string]any)["delay_ms"])
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		t.Setenv("FORM_SERVICE_LISTENER_WORKERS", "many")
+
+		_, err := Init("")
+
+		assert.ErrorContains(t, err, "FORM_SERVICE_LISTENER_WORKERS")
+	})
+}
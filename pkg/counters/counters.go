@@ -0,0 +1,139 @@
+// Package counters keeps hash counters in Redis that every service instance
+// increments cheaply and that are periodically drained into durable
+// storage. Redis only holds the increments not persisted yet, so losing it
+// loses at most one persistence interval of counts.
+package counters
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// KEY_PREFIX namespaces the Redis keys of all counter stores
+	KEY_PREFIX = "counters"
+
+	// DEFAULT_DRAIN_BATCH is the number of keys drained at once when no batch size is given
+	DEFAULT_DRAIN_BATCH = 100
+)
+
+// drain returns the fields of a hash and deletes it atomically, so
+// increments racing with a drain land in a fresh hash
+var drain = redis.NewScript(`
+local fields = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return fields
+`)
+
+// Store is a named group of hash counters
+type Store struct {
+	client *redis.Client
+	name   string
+	logger *logger.Logger
+}
+
+// New creates a counter store keeping its hashes under name
+func New(client *redis.Client, logger *logger.Logger, name string) *Store {
+	return &Store{
+		client: client,
+		name:   name,
+		logger: logger,
+	}
+}
+
+func (s *Store) hashKey(key string) string {
+	return fmt.Sprintf("%s:%s:%s", KEY_PREFIX, s.name, key)
+}
+
+func (s *Store) dirtyKey() string {
+	return fmt.Sprintf("%s:%s:dirty", KEY_PREFIX, s.name)
+}
+
+// Incr adds the given amounts to the fields of the counter key and marks the
+// key for draining
+func (s *Store) Incr(ctx context.Context, key string, fields map[string]int64) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	pipe := s.client.TxPipeline()
+	for field, amount := range fields {
+		pipe.HIncrBy(ctx, s.hashKey(key), field, amount)
+	}
+	pipe.SAdd(ctx, s.dirtyKey(), key)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to increment counters: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the increments of the counter key not drained yet
+func (s *Store) Get(ctx context.Context, key string) (map[string]int64, error) {
+	values, err := s.client.HGetAll(ctx, s.hashKey(key)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read counters: %w", err)
+	}
+
+	fields := make(map[string]int64, len(values))
+	for field, value := range values {
+		fields[field] = s.parse(key, field, value)
+	}
+
+	return fields, nil
+}
+
+// Drain removes up to batch keys with pending increments and returns their
+// increments by key. Callers must Incr the increments of a key back if they
+// fail to persist them.
+func (s *Store) Drain(ctx context.Context, batch int) (map[string]map[string]int64, error) {
+	if batch <= 0 {
+		batch = DEFAULT_DRAIN_BATCH
+	}
+
+	keys, err := s.client.SPopN(ctx, s.dirtyKey(), int64(batch)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pop dirty counters: %w", err)
+	}
+
+	drained := make(map[string]map[string]int64, len(keys))
+
+	for i, key := range keys {
+		values, err := drain.Run(ctx, s.client, []string{s.hashKey(key)}).StringSlice()
+		if err != nil {
+			// Put back what wasn't drained so the next run picks it up
+			s.client.SAdd(ctx, s.dirtyKey(), keys[i:])
+			return drained, fmt.Errorf("failed to drain counters: %w", err)
+		}
+
+		fields := make(map[string]int64, len(values)/2)
+		for j := 0; j+1 < len(values); j += 2 {
+			fields[values[j]] = s.parse(key, values[j], values[j+1])
+		}
+
+		if len(fields) > 0 {
+			drained[key] = fields
+		}
+	}
+
+	return drained, nil
+}
+
+func (s *Store) parse(key, field, value string) int64 {
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		s.logger.Warn("ignoring malformed counter",
+			zap.String("store", s.name),
+			zap.String("key", key),
+			zap.String("field", field),
+			zap.Error(err))
+	}
+
+	return amount
+}
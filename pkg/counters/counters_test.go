@@ -0,0 +1,51 @@
+package counters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupStore(t *testing.T) *Store {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return New(client, &logger.Logger{Logger: zap.NewNop()}, "test")
+}
+
+func TestStore(t *testing.T) {
+	store := setupStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Incr(ctx, "form-1", map[string]int64{"a": 1, "b": 2}))
+	require.NoError(t, store.Incr(ctx, "form-1", map[string]int64{"a": 1}))
+	require.NoError(t, store.Incr(ctx, "form-2", map[string]int64{"a": 5}))
+
+	fields, err := store.Get(ctx, "form-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 2, "b": 2}, fields)
+
+	drained, err := store.Drain(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]int64{
+		"form-1": {"a": 2, "b": 2},
+		"form-2": {"a": 5},
+	}, drained)
+
+	fields, err = store.Get(ctx, "form-1")
+	require.NoError(t, err)
+	assert.Empty(t, fields, "drained increments are gone")
+
+	drained, err = store.Drain(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, drained, "nothing is dirty after a drain")
+}
@@ -0,0 +1,146 @@
+// Package eventbus provides an in-process fan-out of domain events.
+// The service publishes each domain event once to the bus, and every
+// interested side effect (AMQP publishing, query cache invalidation, the
+// read model, webhooks, metrics, ...) subscribes to the event types it cares
+// about instead of being called from the core service methods.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/Koyo-os/form-service/pkg/retrier"
+	"go.uber.org/zap"
+)
+
+// WILDCARD matches every event type when used as a subscription pattern
+const WILDCARD = "*"
+
+// SIDE_EFFECT names the subscriber retry policy in the retry policy metric
+const SIDE_EFFECT = "event_subscriber"
+
+type (
+	// Handler receives a published domain event. Handlers run synchronously
+	// in subscription order. A failing handler is retried on its own, see
+	// SetRetryPolicy, so it should be idempotent; the other handlers still
+	// get the event once.
+	Handler func(payload any, eventType string) error
+
+	// subscription binds a handler to an event type pattern
+	subscription struct {
+		name    string
+		pattern string
+		handler Handler
+	}
+
+	// Bus dispatches published events to all matching subscribers.
+	// It satisfies the service Publisher interface.
+	Bus struct {
+		mu            sync.RWMutex
+		subscriptions []subscription
+		retries       uint8         // Retries of a failing handler after its first attempt
+		delay         time.Duration // Pause between attempts of a failing handler
+		logger        *logger.Logger
+	}
+)
+
+// New creates an empty event bus
+func New(logger *logger.Logger) *Bus {
+	return &Bus{
+		logger: logger,
+	}
+}
+
+// Subscribe registers a handler for events matching pattern.
+// Patterns are either an exact event type ("form.created"), a prefix
+// followed by ".*" ("form.*") or WILDCARD for every event.
+// The name identifies the subscriber in logs and errors.
+func (b *Bus) Subscribe(name, pattern string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions = append(b.subscriptions, subscription{
+		name:    name,
+		pattern: pattern,
+		handler: handler,
+	})
+}
+
+// SetRetryPolicy sets how often a failing handler is retried after its
+// first attempt and the pause between attempts. By default handlers are
+// not retried.
+func (b *Bus) SetRetryPolicy(retries uint8, delay time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.retries = retries
+	b.delay = delay
+
+	metrics.RetryPolicy.WithLabelValues(SIDE_EFFECT, "retries").Set(float64(retries))
+	metrics.RetryPolicy.WithLabelValues(SIDE_EFFECT, "delay_seconds").Set(delay.Seconds())
+}
+
+// Publish delivers the event to every matching subscriber, retrying each
+// failing subscriber on its own. A failing subscriber doesn't stop delivery
+// to the others; the failures left after retrying are joined into the
+// returned error. Retrying stops once ctx, the context of the publishing
+// operation, is done. Callers shouldn't retry Publish themselves, that would
+// deliver the event again to the subscribers that already got it.
+func (b *Bus) Publish(ctx context.Context, payload any, eventType string) error {
+	b.mu.RLock()
+	subscriptions := make([]subscription, 0, len(b.subscriptions))
+	for _, sub := range b.subscriptions {
+		if matches(sub.pattern, eventType) {
+			subscriptions = append(subscriptions, sub)
+		}
+	}
+	attempts, delay := b.retries, b.delay
+	b.mu.RUnlock()
+
+	if attempts < math.MaxUint8 {
+		attempts++
+	}
+
+	if len(subscriptions) == 0 {
+		b.logger.Debug("no subscribers for event", zap.String("event_type", eventType))
+		return nil
+	}
+
+	var errs []error
+
+	for _, sub := range subscriptions {
+		err := retrier.Do(ctx, attempts, delay, func() error {
+			return sub.handler(payload, eventType)
+		})
+		if err != nil {
+			metrics.EventSubscriberFailures.WithLabelValues(sub.name).Inc()
+			b.logger.Error("event subscriber failed",
+				zap.String("subscriber", sub.name),
+				zap.String("event_type", eventType),
+				zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// matches reports whether an event type satisfies a subscription pattern
+func matches(pattern, eventType string) bool {
+	if pattern == WILDCARD || pattern == eventType {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(pattern, ".*"); ok {
+		return strings.HasPrefix(eventType, prefix+".")
+	}
+
+	return false
+}
@@ -0,0 +1,157 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newTestBus() *Bus {
+	return New(&logger.Logger{Logger: zap.NewNop()})
+}
+
+func TestBus_Publish(t *testing.T) {
+	t.Run("delivers to matching subscribers only", func(t *testing.T) {
+		bus := newTestBus()
+		var got []string
+
+		record := func(name string) Handler {
+			return func(_ any, eventType string) error {
+				got = append(got, name+":"+eventType)
+				return nil
+			}
+		}
+
+		bus.Subscribe("all", WILDCARD, record("all"))
+		bus.Subscribe("forms", "form.*", record("forms"))
+		bus.Subscribe("created", "form.created", record("created"))
+		bus.Subscribe("responses", "response.*", record("responses"))
+
+		err := bus.Publish(context.Background(), "payload", "form.created")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"all:form.created", "forms:form.created", "created:form.created"}, got)
+	})
+
+	t.Run("passes the payload through", func(t *testing.T) {
+		bus := newTestBus()
+		var got any
+
+		bus.Subscribe("capture", WILDCARD, func(payload any, _ string) error {
+			got = payload
+			return nil
+		})
+
+		payload := struct{ ID string }{ID: "42"}
+		assert.NoError(t, bus.Publish(context.Background(), payload, "form.updated"))
+		assert.Equal(t, payload, got)
+	})
+
+	t.Run("keeps delivering after a subscriber fails", func(t *testing.T) {
+		bus := newTestBus()
+		delivered := false
+
+		bus.Subscribe("broken", WILDCARD, func(any, string) error {
+			return errors.New("webhook down")
+		})
+		bus.Subscribe("amqp", WILDCARD, func(any, string) error {
+			delivered = true
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), "payload", "form.deleted")
+
+		assert.True(t, delivered)
+		assert.ErrorContains(t, err, "broken: webhook down")
+	})
+
+	t.Run("retries only the failing subscriber", func(t *testing.T) {
+		bus := newTestBus()
+		bus.SetRetryPolicy(2, time.Millisecond)
+		deliveries := map[string]int{}
+
+		bus.Subscribe("amqp", WILDCARD, func(any, string) error {
+			deliveries["amqp"]++
+			return nil
+		})
+		bus.Subscribe("flaky", WILDCARD, func(any, string) error {
+			deliveries["flaky"]++
+			if deliveries["flaky"] < 3 {
+				return errors.New("index unavailable")
+			}
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), "payload", "form.created")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"amqp": 1, "flaky": 3}, deliveries)
+	})
+
+	t.Run("reports subscribers that keep failing", func(t *testing.T) {
+		bus := newTestBus()
+		bus.SetRetryPolicy(1, time.Millisecond)
+		attempts, delivered := 0, 0
+
+		bus.Subscribe("broken", WILDCARD, func(any, string) error {
+			attempts++
+			return errors.New("webhook down")
+		})
+		bus.Subscribe("amqp", WILDCARD, func(any, string) error {
+			delivered++
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), "payload", "form.updated")
+
+		assert.ErrorContains(t, err, "broken: webhook down")
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, 1, delivered)
+	})
+
+	t.Run("stops retrying once the operation is done", func(t *testing.T) {
+		bus := newTestBus()
+		bus.SetRetryPolicy(5, time.Hour)
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+
+		bus.Subscribe("broken", WILDCARD, func(any, string) error {
+			attempts++
+			cancel()
+			return errors.New("webhook down")
+		})
+
+		err := bus.Publish(ctx, "payload", "form.updated")
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("no subscribers is not an error", func(t *testing.T) {
+		assert.NoError(t, newTestBus().Publish(context.Background(), "payload", "form.created"))
+	})
+}
+
+func TestMatches(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		eventType string
+		want      bool
+	}{
+		{WILDCARD, "form.created", true},
+		{"form.created", "form.created", true},
+		{"form.created", "form.updated", false},
+		{"form.*", "form.updated", true},
+		{"form.*", "formula.updated", false},
+		{"form.*", "form", false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, matches(c.pattern, c.eventType), "%s vs %s", c.pattern, c.eventType)
+	}
+}
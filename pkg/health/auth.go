@@ -0,0 +1,123 @@
+package health
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ServerOptions configures the network binding and authentication of the
+// health check server.
+type ServerOptions struct {
+	Addr string // Address to listen on, e.g. "127.0.0.1:8080"; an empty host binds every interface
+
+	// Token is the bearer token protected endpoints require. Empty disables
+	// token authentication.
+	Token string
+
+	// CertFile and KeyFile enable TLS. ClientCAFile additionally verifies
+	// client certificates against the given CA; protected endpoints then
+	// accept a verified client certificate instead of the token.
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+// authenticated reports whether opts configure any authentication method
+func (o ServerOptions) authenticated() bool {
+	return o.Token != "" || o.ClientCAFile != ""
+}
+
+// HandleProtected registers an endpoint that requires authentication, e.g.
+// /metrics or admin endpoints. Requests must carry the configured bearer
+// token or a verified client certificate. Without configured authentication
+// the endpoint is served like one registered via Handle.
+// It must be called before StartHealthCheckServer.
+func (h *HealthChecker) HandleProtected(pattern string, handler http.Handler) {
+	h.mux.Handle(pattern, h.protect(handler))
+}
+
+// HandleAdmin registers an endpoint that inspects or changes the service,
+// e.g. /debug/config or /admin/*. Like HandleProtected it requires the
+// bearer token or a verified client certificate, but without configured
+// authentication it isn't served at all.
+// It must be called before StartHealthCheckServer.
+func (h *HealthChecker) HandleAdmin(pattern string, handler http.Handler) {
+	h.admin[pattern] = handler
+}
+
+// protect wraps handler with the authentication of the server options
+func (h *HealthChecker) protect(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.opts.authenticated() || h.authorized(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		h.logger.Warn("unauthorized request",
+			zap.String("path", r.URL.Path),
+			zap.String("remote_addr", r.RemoteAddr))
+
+		if h.opts.Token != "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="form-service"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// authorized reports whether r passes any configured authentication method
+func (h *HealthChecker) authorized(r *http.Request) bool {
+	if h.opts.ClientCAFile != "" && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return true
+	}
+
+	if h.opts.Token == "" {
+		return false
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.opts.Token)) == 1
+}
+
+// tlsConfig builds the server TLS configuration, nil when TLS is disabled.
+// Client certificates are optional at the handshake so unauthenticated
+// endpoints like /health stay reachable for probes.
+func (o ServerOptions) tlsConfig() (*tls.Config, error) {
+	if o.CertFile == "" && o.KeyFile == "" {
+		if o.ClientCAFile != "" {
+			return nil, errors.New("client certificate verification requires a server certificate")
+		}
+
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if o.ClientCAFile != "" {
+		pem, err := os.ReadFile(o.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("client CA file contains no certificates")
+		}
+
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return cfg, nil
+}
@@ -4,7 +4,9 @@
 package health
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/Koyo-os/form-service/pkg/logger"
 	"go.uber.org/zap"
@@ -21,15 +23,35 @@ type (
 		IsHealthy() bool
 	}
 
+	// ContextHealther is an optional extension of Healther for components whose
+	// checks perform I/O. The health checker passes a context bounded by its
+	// check timeout so a slow dependency can't block the endpoint.
+	ContextHealther interface {
+		Healther
+
+		// IsHealthyContext behaves like IsHealthy but must return once ctx is done.
+		IsHealthyContext(ctx context.Context) bool
+	}
+
 	// HealthChecker aggregates multiple Healther implementations and provides
 	// a unified health check mechanism. It checks all registered health checkers
 	// and reports the overall system health.
 	HealthChecker struct {
 		logger    *logger.Logger
-		healthers []Healther // Collection of health checker implementations
+		healthers []Healther     // Collection of health checker implementations
+		timeout   time.Duration  // Upper bound for a single check pass
+		mux       *http.ServeMux // Routes served next to /health
+		opts      ServerOptions  // Binding and authentication of the server
+
+		admin map[string]http.Handler // Endpoints registered via HandleAdmin, served once authenticated
+
+		reporters map[string]StatusReporter // Sections of /statusz by name
 	}
 )
 
+// DEFAULT_CHECK_TIMEOUT bounds a single pass of HealthCheck over all healthers
+const DEFAULT_CHECK_TIMEOUT = 3 * time.Second
+
 // NewHealthChecker creates and returns a new HealthChecker instance with
 // the provided health checker implementations.
 //
@@ -48,6 +70,44 @@ func NewHealthChecker(logger *logger.Logger, healthers ...Healther) *HealthCheck
 	return &HealthChecker{
 		healthers: healthers,
 		logger:    logger,
+		timeout:   DEFAULT_CHECK_TIMEOUT,
+		mux:       http.NewServeMux(),
+		admin:     make(map[string]http.Handler),
+	}
+}
+
+// Handle registers an additional endpoint (e.g. /metrics) on the health
+// check server. It must be called before StartHealthCheckServer.
+func (h *HealthChecker) Handle(pattern string, handler http.Handler) {
+	h.mux.Handle(pattern, handler)
+}
+
+// SetTimeout changes the upper bound applied to a health check pass.
+// Non-positive values are ignored.
+func (h *HealthChecker) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		h.timeout = timeout
+	}
+}
+
+// check runs a single healther bounded by ctx. ContextHealthers receive the
+// context directly; plain Healthers are run in a goroutine and reported as
+// unhealthy if they don't answer before ctx is done.
+func (h *HealthChecker) check(ctx context.Context, healther Healther) bool {
+	if ch, ok := healther.(ContextHealther); ok {
+		return ch.IsHealthyContext(ctx)
+	}
+
+	result := make(chan bool, 1)
+	go func() {
+		result <- healther.IsHealthy()
+	}()
+
+	select {
+	case ok := <-result:
+		return ok
+	case <-ctx.Done():
+		return false
 	}
 }
 
@@ -58,38 +118,65 @@ func NewHealthChecker(logger *logger.Logger, healthers ...Healther) *HealthCheck
 //   - HTTP 200 OK with "OK" body if all health checkers report healthy status
 //   - HTTP 500 Internal Server Error with "Not OK" body if any health checker reports unhealthy status
 //
-// This method iterates through all registered health checkers and stops checking
-// once the first unhealthy component is found for performance optimization.
+// Every health checker is checked and the whole pass is bounded by the checker timeout;
+// a component that doesn't answer in time is reported as unhealthy.
 //
 // Parameters:
 //   - w: HTTP response writer for sending the response
 //   - r: HTTP request (not used but required for http.HandlerFunc signature)
 func (h *HealthChecker) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	// Set response based on overall health status
+	if h.healthy(ctx) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Not OK"))
+	}
+}
+
+// healthy checks all registered health checkers within ctx
+func (h *HealthChecker) healthy(ctx context.Context) bool {
 	ok := true
 
-	// Check all registered health checkers
 	for _, healther := range h.healthers {
-		if !healther.IsHealthy() {
+		if !h.check(ctx, healther) {
 			ok = false
 			h.logger.Error("health check failed")
 		}
 	}
 
-	// Set response based on overall health status
-	if ok {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	} else {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("Not OK"))
+	return ok
+}
+
+// configure registers the endpoints of the server with the authentication
+// of opts. Admin endpoints are left out unless opts authenticate requests.
+func (h *HealthChecker) configure(opts ServerOptions) {
+	h.opts = opts
+	h.mux.HandleFunc("/health", h.HealthCheck)
+	h.mux.Handle("/statusz", h.protect(http.HandlerFunc(h.Statusz)))
+
+	if !opts.authenticated() {
+		h.logger.Warn("health check server has no authentication, protected endpoints are open and admin endpoints disabled")
+		return
+	}
+
+	for pattern, handler := range h.admin {
+		h.mux.Handle(pattern, h.protect(handler))
 	}
 }
 
 // StartHealthCheckServer starts a dedicated HTTP server for health check endpoints.
 // This function blocks and should typically be run in a separate goroutine.
 //
-// The server exposes a single endpoint:
+// The server exposes the following endpoints:
 //   - GET /health - Returns the health status of all registered components
+//   - GET /statusz - Returns the health status and the sections added via AddStatus, see Statusz
+//   - Any endpoint registered via Handle or HandleProtected, and via HandleAdmin
+//     once authentication is configured
 //
 // Parameters:
 //   - port: The port to listen on (e.g., ":8080" or ":8081")
@@ -100,13 +187,41 @@ func (h *HealthChecker) HealthCheck(w http.ResponseWriter, r *http.Request) {
 //	checker := NewHealthChecker(dbHealther, redisHealther)
 //	go StartHealthCheckServer(":8081", checker)
 //
-// Note: This function uses the default HTTP server mux. If you need more control
-// over the server configuration, consider using http.Server directly.
+// Note: This function uses the checker's own mux rather than the default one.
+// Use StartServer to bind a specific interface or enable authentication.
 func (h *HealthChecker) StartHealthCheckServer(port string) {
-	http.HandleFunc("/health", h.HealthCheck)
-	h.logger.Info("Starting health check server", zap.String("port", port))
+	h.StartServer(ServerOptions{Addr: port})
+}
+
+// StartServer starts the health check server like StartHealthCheckServer,
+// with the binding, TLS and authentication of opts. It blocks until the
+// server fails.
+func (h *HealthChecker) StartServer(opts ServerOptions) {
+	tlsConfig, err := opts.tlsConfig()
+	if err != nil {
+		h.logger.Error("Failed to configure health check server TLS", zap.Error(err))
+		return
+	}
+
+	h.configure(opts)
+
+	server := &http.Server{
+		Addr:      opts.Addr,
+		Handler:   h.mux,
+		TLSConfig: tlsConfig,
+	}
+
+	h.logger.Info("Starting health check server",
+		zap.String("addr", opts.Addr),
+		zap.Bool("tls", tlsConfig != nil))
+
+	if tlsConfig != nil {
+		err = server.ListenAndServeTLS(opts.CertFile, opts.KeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
 
-	if err := http.ListenAndServe(port, nil); err != nil {
+	if err != nil {
 		h.logger.Error("Failed to start health check server", zap.Error(err))
 	}
 }
@@ -1,9 +1,14 @@
 package health
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Koyo-os/form-service/pkg/logger"
 	"github.com/stretchr/testify/assert"
@@ -238,3 +243,226 @@ func TestHealthChecker_HealthCheck(t *testing.T) {
 		}
 	})
 }
+
+// slowHealther never answers before the test releases it
+type slowHealther struct {
+	release chan struct{}
+}
+
+func (s *slowHealther) IsHealthy() bool {
+	<-s.release
+	return true
+}
+
+// ctxHealther records the context it was checked with
+type ctxHealther struct {
+	healthy     bool
+	hadDeadline bool
+}
+
+func (c *ctxHealther) IsHealthy() bool {
+	return c.healthy
+}
+
+func (c *ctxHealther) IsHealthyContext(ctx context.Context) bool {
+	_, c.hadDeadline = ctx.Deadline()
+	return c.healthy
+}
+
+func TestHealthChecker_Timeout(t *testing.T) {
+	t.Run("reports Not OK when a healther exceeds the timeout", func(t *testing.T) {
+		testLogger, _ := createTestLogger()
+
+		slow := &slowHealther{release: make(chan struct{})}
+		defer close(slow.release)
+
+		checker := NewHealthChecker(testLogger, slow)
+		checker.SetTimeout(20 * time.Millisecond)
+
+		req := httptest.NewRequest("GET", "/health", nil)
+		w := httptest.NewRecorder()
+
+		start := time.Now()
+		checker.HealthCheck(w, req)
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "Not OK", w.Body.String())
+	})
+
+	t.Run("passes a bounded context to context healthers", func(t *testing.T) {
+		testLogger, _ := createTestLogger()
+
+		healther := &ctxHealther{healthy: true}
+		checker := NewHealthChecker(testLogger, healther)
+
+		req := httptest.NewRequest("GET", "/health", nil)
+		w := httptest.NewRecorder()
+
+		checker.HealthCheck(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, healther.hadDeadline)
+	})
+
+	t.Run("ignores non-positive timeouts", func(t *testing.T) {
+		testLogger, _ := createTestLogger()
+		checker := NewHealthChecker(testLogger)
+
+		checker.SetTimeout(0)
+
+		assert.Equal(t, DEFAULT_CHECK_TIMEOUT, checker.timeout)
+	})
+}
+
+func TestHealthChecker_HandleProtected(t *testing.T) {
+	testLogger, _ := createTestLogger()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("metrics")) })
+
+	serve := func(opts ServerOptions, req *http.Request) *httptest.ResponseRecorder {
+		checker := NewHealthChecker(testLogger)
+		checker.opts = opts
+		checker.HandleProtected("/metrics", ok)
+
+		w := httptest.NewRecorder()
+		checker.mux.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("is open without configured authentication", func(t *testing.T) {
+		w := serve(ServerOptions{}, httptest.NewRequest("GET", "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rejects a missing or wrong token", func(t *testing.T) {
+		opts := ServerOptions{Token: "s3cret"}
+
+		w := serve(opts, httptest.NewRequest("GET", "/metrics", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+		assert.Equal(t, http.StatusUnauthorized, serve(opts, req).Code)
+	})
+
+	t.Run("accepts the bearer token", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+
+		w := serve(ServerOptions{Token: "s3cret"}, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "metrics", w.Body.String())
+	})
+
+	t.Run("accepts a verified client certificate", func(t *testing.T) {
+		opts := ServerOptions{Token: "s3cret", ClientCAFile: "ca.pem"}
+
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		req.TLS = &tls.ConnectionState{}
+		assert.Equal(t, http.StatusUnauthorized, serve(opts, req).Code, "unverified connection")
+
+		req.TLS.VerifiedChains = [][]*x509.Certificate{{{}}}
+		assert.Equal(t, http.StatusOK, serve(opts, req).Code)
+	})
+}
+
+func TestHealthChecker_HandleAdmin(t *testing.T) {
+	testLogger, _ := createTestLogger()
+	pause := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("paused")) })
+
+	serve := func(opts ServerOptions, req *http.Request) *httptest.ResponseRecorder {
+		checker := NewHealthChecker(testLogger)
+		checker.HandleAdmin("/admin/consumer/", pause)
+		checker.configure(opts)
+
+		w := httptest.NewRecorder()
+		checker.mux.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("rejects unauthenticated requests", func(t *testing.T) {
+		w := serve(ServerOptions{Token: "s3cret"}, httptest.NewRequest("POST", "/admin/consumer/request/pause", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("accepts the bearer token", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/admin/consumer/request/pause", nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+
+		w := serve(ServerOptions{Token: "s3cret"}, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "paused", w.Body.String())
+	})
+
+	t.Run("is not served without configured authentication", func(t *testing.T) {
+		w := serve(ServerOptions{}, httptest.NewRequest("POST", "/admin/consumer/request/pause", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestServerOptions_TLSConfig(t *testing.T) {
+	t.Run("disabled without certificate", func(t *testing.T) {
+		cfg, err := ServerOptions{}.tlsConfig()
+
+		assert.NoError(t, err)
+		assert.Nil(t, cfg)
+	})
+
+	t.Run("client verification requires a server certificate", func(t *testing.T) {
+		_, err := ServerOptions{ClientCAFile: "ca.pem"}.tlsConfig()
+
+		assert.Error(t, err)
+	})
+}
+
+// statusFunc is a StatusReporter returning a fixed section
+type statusFunc func() any
+
+func (f statusFunc) Status() any { return f() }
+
+func TestHealthChecker_Statusz(t *testing.T) {
+	testLogger, _ := createTestLogger()
+
+	serve := func(checker *HealthChecker) (*httptest.ResponseRecorder, map[string]any) {
+		w := httptest.NewRecorder()
+		checker.Statusz(w, httptest.NewRequest("GET", "/statusz", nil))
+
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w, body
+	}
+
+	t.Run("reports health and sections", func(t *testing.T) {
+		healther := &MockHealther{}
+		healther.On("IsHealthy").Return(true)
+
+		checker := NewHealthChecker(testLogger, healther)
+		checker.AddStatus("consumers", statusFunc(func() any {
+			return map[string]bool{"fresh": true}
+		}))
+
+		w, body := serve(checker)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, true, body["healthy"])
+		assert.Equal(t, map[string]any{"consumers": map[string]any{"fresh": true}}, body["sections"])
+		assert.NotEmpty(t, body["checked_at"])
+	})
+
+	t.Run("answers while unhealthy", func(t *testing.T) {
+		healther := &MockHealther{}
+		healther.On("IsHealthy").Return(false)
+
+		w, body := serve(NewHealthChecker(testLogger, healther))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, false, body["healthy"])
+	})
+}
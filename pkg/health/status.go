@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// StatusReporter is implemented by components contributing a section to
+	// the /statusz report, e.g. the freshness of downstream consumers
+	StatusReporter interface {
+		// Status returns the section, marshalled to JSON
+		Status() any
+	}
+
+	// StatusReport is the body of /statusz
+	StatusReport struct {
+		Healthy   bool           `json:"healthy"` // Every healther passed, like /health
+		CheckedAt time.Time      `json:"checked_at"`
+		Sections  map[string]any `json:"sections,omitempty"` // Sections by reporter name
+	}
+)
+
+// AddStatus adds the section of reporter, named name, to the /statusz
+// report. It must be called before StartHealthCheckServer.
+func (h *HealthChecker) AddStatus(name string, reporter StatusReporter) {
+	if h.reporters == nil {
+		h.reporters = make(map[string]StatusReporter)
+	}
+	h.reporters[name] = reporter
+}
+
+// Statusz is an HTTP handler reporting the overall health, as /health does,
+// along with the sections added via AddStatus as JSON. It always answers
+// 200 OK, so the report can be read while the service is unhealthy.
+func (h *HealthChecker) Statusz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	report := StatusReport{
+		Healthy:   h.healthy(ctx),
+		CheckedAt: time.Now(),
+		Sections:  make(map[string]any, len(h.reporters)),
+	}
+
+	for name, reporter := range h.reporters {
+		report.Sections[name] = reporter.Status()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.logger.Error("failed to write status report", zap.Error(err))
+	}
+}
@@ -0,0 +1,118 @@
+// Package heartbeat tracks the heartbeats downstream consumers of the
+// service's events send on a control queue. It remembers when each known
+// consumer was last heard from and reports whether that is recent enough,
+// so operators can tell from /statusz whether anyone is still reading what
+// the service publishes.
+package heartbeat
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DEFAULT_MAX_AGE is used when no max age is configured
+const DEFAULT_MAX_AGE = 90 * time.Second
+
+type (
+	// ConsumerStatus is the freshness of the heartbeats of one consumer
+	ConsumerStatus struct {
+		Consumer   string     `json:"consumer"`
+		LastSeen   *time.Time `json:"last_seen"`             // Nil until the first heartbeat
+		AgeSeconds float64    `json:"age_seconds,omitempty"` // Time since the last heartbeat
+		Fresh      bool       `json:"fresh"`                 // The last heartbeat is younger than the max age
+	}
+
+	// Report is the freshness of every known consumer, sorted by name
+	Report struct {
+		MaxAgeSeconds float64          `json:"max_age_seconds"`
+		Fresh         bool             `json:"fresh"` // Every known consumer is fresh
+		Consumers     []ConsumerStatus `json:"consumers"`
+	}
+
+	// Tracker records the last heartbeat of the known consumers
+	Tracker struct {
+		mu     sync.RWMutex
+		seen   map[string]time.Time // Last heartbeat by consumer
+		known  []string             // Consumers reported, sorted
+		maxAge time.Duration
+		now    func() time.Time // Replaced in tests
+	}
+)
+
+// New creates a tracker of the heartbeats of consumers, stale once older
+// than maxAge
+func New(consumers []string, maxAge time.Duration) *Tracker {
+	if maxAge <= 0 {
+		maxAge = DEFAULT_MAX_AGE
+	}
+
+	known := slices.Clone(consumers)
+	slices.Sort(known)
+
+	return &Tracker{
+		seen:   make(map[string]time.Time, len(known)),
+		known:  slices.Compact(known),
+		maxAge: maxAge,
+		now:    time.Now,
+	}
+}
+
+// Record notes a heartbeat consumer sent at the given time, now when zero.
+// Heartbeats from the future, sent with a skewed clock, count as sent now
+// and heartbeats older than the last one are ignored.
+// Returns false for consumers the tracker doesn't know
+func (t *Tracker) Record(consumer string, at time.Time) bool {
+	if _, ok := slices.BinarySearch(t.known, consumer); !ok {
+		return false
+	}
+
+	now := t.now()
+	if at.IsZero() || at.After(now) {
+		at = now
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.seen[consumer]; !ok || at.After(last) {
+		t.seen[consumer] = at
+	}
+
+	return true
+}
+
+// Report returns the freshness of every known consumer
+func (t *Tracker) Report() Report {
+	now := t.now()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	report := Report{
+		MaxAgeSeconds: t.maxAge.Seconds(),
+		Fresh:         true,
+		Consumers:     make([]ConsumerStatus, 0, len(t.known)),
+	}
+
+	for _, consumer := range t.known {
+		status := ConsumerStatus{Consumer: consumer}
+
+		if last, ok := t.seen[consumer]; ok {
+			age := now.Sub(last)
+			status.LastSeen = &last
+			status.AgeSeconds = age.Seconds()
+			status.Fresh = age <= t.maxAge
+		}
+
+		report.Fresh = report.Fresh && status.Fresh
+		report.Consumers = append(report.Consumers, status)
+	}
+
+	return report
+}
+
+// Status implements health.StatusReporter
+func (t *Tracker) Status() any {
+	return t.Report()
+}
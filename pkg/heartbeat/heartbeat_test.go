@@ -0,0 +1,77 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	newTracker := func() *Tracker {
+		tracker := New([]string{"notifier", "analytics", "notifier"}, time.Minute)
+		tracker.now = func() time.Time { return now }
+		return tracker
+	}
+
+	t.Run("reports known consumers never heard from as stale", func(t *testing.T) {
+		report := newTracker().Report()
+
+		assert.False(t, report.Fresh)
+		assert.Equal(t, 60.0, report.MaxAgeSeconds)
+		require.Len(t, report.Consumers, 2)
+		assert.Equal(t, "analytics", report.Consumers[0].Consumer)
+		assert.Equal(t, "notifier", report.Consumers[1].Consumer)
+		assert.Nil(t, report.Consumers[0].LastSeen)
+		assert.False(t, report.Consumers[0].Fresh)
+	})
+
+	t.Run("reports the age of the last heartbeat", func(t *testing.T) {
+		tracker := newTracker()
+
+		assert.True(t, tracker.Record("analytics", now.Add(-10*time.Second)))
+		assert.True(t, tracker.Record("notifier", now.Add(-2*time.Minute)))
+
+		report := tracker.Report()
+		assert.False(t, report.Fresh)
+
+		analytics, notifier := report.Consumers[0], report.Consumers[1]
+		assert.True(t, analytics.Fresh)
+		assert.Equal(t, 10.0, analytics.AgeSeconds)
+		assert.False(t, notifier.Fresh)
+		assert.Equal(t, now.Add(-2*time.Minute), *notifier.LastSeen)
+
+		assert.True(t, tracker.Record("notifier", now))
+		assert.True(t, tracker.Report().Fresh)
+	})
+
+	t.Run("ignores unknown consumers", func(t *testing.T) {
+		tracker := newTracker()
+
+		assert.False(t, tracker.Record("billing", now))
+		assert.Len(t, tracker.Report().Consumers, 2)
+	})
+
+	t.Run("keeps the latest heartbeat", func(t *testing.T) {
+		tracker := newTracker()
+
+		tracker.Record("analytics", now.Add(-time.Second))
+		tracker.Record("analytics", now.Add(-time.Hour))
+
+		assert.Equal(t, 1.0, tracker.Report().Consumers[0].AgeSeconds)
+	})
+
+	t.Run("counts heartbeats without or ahead of time as sent now", func(t *testing.T) {
+		tracker := newTracker()
+
+		tracker.Record("analytics", time.Time{})
+		tracker.Record("notifier", now.Add(time.Hour))
+
+		for _, status := range tracker.Report().Consumers {
+			assert.Equal(t, now, *status.LastSeen, status.Consumer)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+// Package loopback verifies that the events the service publishes reach the
+// broker. The service consumes its own output exchange on a queue of its own,
+// and every event handed to the publisher is expected back there, by its
+// correlation ID, within a timeout. Events that don't come back are reported
+// as missing, catching a publisher that loses events while reporting success.
+package loopback
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+)
+
+// DEFAULT_TIMEOUT is used when no timeout is configured
+const DEFAULT_TIMEOUT = 30 * time.Second
+
+type (
+	// Options configures a Verifier
+	Options struct {
+		Timeout    time.Duration // How long a published event has to come back
+		EventTypes []string      // Event types verified, others are ignored
+	}
+
+	// expectation is a published event that didn't come back yet
+	expectation struct {
+		eventType   string
+		publishedAt time.Time
+	}
+
+	// Verifier tracks the published events of the verified types until they
+	// come back on the loopback queue
+	Verifier struct {
+		mu         sync.Mutex
+		pending    map[string]expectation // By correlation ID
+		eventTypes map[string]struct{}
+		opts       Options
+		now        func() time.Time // Replaced in tests
+	}
+)
+
+// New creates a verifier with the given options
+func New(opts Options) *Verifier {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DEFAULT_TIMEOUT
+	}
+
+	eventTypes := make(map[string]struct{}, len(opts.EventTypes))
+	for _, eventType := range opts.EventTypes {
+		eventTypes[eventType] = struct{}{}
+	}
+
+	return &Verifier{
+		pending:    make(map[string]expectation),
+		eventTypes: eventTypes,
+		opts:       opts,
+		now:        time.Now,
+	}
+}
+
+// EventTypes returns the verified event types, sorted
+func (v *Verifier) EventTypes() []string {
+	eventTypes := make([]string, 0, len(v.eventTypes))
+	for eventType := range v.eventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+	slices.Sort(eventTypes)
+
+	return eventTypes
+}
+
+// Expect records that the event with correlationID was published. Events of
+// types that aren't verified are ignored.
+func (v *Verifier) Expect(correlationID, eventType string) {
+	if _, ok := v.eventTypes[eventType]; !ok || correlationID == "" {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.pending[correlationID] = expectation{eventType: eventType, publishedAt: v.now()}
+}
+
+// Forget stops expecting the event with correlationID, e.g. because
+// publishing it failed and the failure was reported already
+func (v *Verifier) Forget(correlationID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.pending, correlationID)
+}
+
+// Observe records that the event with correlationID came back
+// Returns whether it was expected
+func (v *Verifier) Observe(correlationID string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.pending[correlationID]; !ok {
+		return false
+	}
+
+	delete(v.pending, correlationID)
+
+	return true
+}
+
+// Pending returns the number of events expected back
+func (v *Verifier) Pending() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return len(v.pending)
+}
+
+// Missing returns the events that didn't come back within the timeout, oldest
+// first, and stops expecting them
+func (v *Verifier) Missing() []entity.MissingEvent {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+
+	var missing []entity.MissingEvent
+	for correlationID, expected := range v.pending {
+		if now.Sub(expected.publishedAt) < v.opts.Timeout {
+			continue
+		}
+
+		delete(v.pending, correlationID)
+		metrics.LoopbackMissing.WithLabelValues(expected.eventType).Inc()
+
+		missing = append(missing, entity.MissingEvent{
+			CorrelationID: correlationID,
+			EventType:     expected.eventType,
+			PublishedAt:   expected.publishedAt,
+			DetectedAt:    now,
+		})
+	}
+
+	slices.SortFunc(missing, func(a, b entity.MissingEvent) int {
+		return a.PublishedAt.Compare(b.PublishedAt)
+	})
+
+	return missing
+}
@@ -0,0 +1,79 @@
+package loopback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	newVerifier := func() *Verifier {
+		verifier := New(Options{
+			Timeout:    30 * time.Second,
+			EventTypes: []string{"form.updated", "form.created"},
+		})
+		verifier.now = func() time.Time { return now }
+		return verifier
+	}
+
+	t.Run("lists the verified event types", func(t *testing.T) {
+		assert.Equal(t, []string{"form.created", "form.updated"}, newVerifier().EventTypes())
+	})
+
+	t.Run("ignores events of other types", func(t *testing.T) {
+		verifier := newVerifier()
+
+		verifier.Expect("1", "form.list")
+
+		assert.Zero(t, verifier.Pending())
+	})
+
+	t.Run("events that came back are not missing", func(t *testing.T) {
+		verifier := newVerifier()
+		verifier.Expect("1", "form.created")
+
+		assert.True(t, verifier.Observe("1"))
+		assert.False(t, verifier.Observe("1"), "observed once")
+
+		now = now.Add(time.Minute)
+		assert.Empty(t, verifier.Missing())
+	})
+
+	t.Run("reports events not back within the timeout once", func(t *testing.T) {
+		verifier := newVerifier()
+		publishedAt := now
+
+		verifier.Expect("1", "form.updated")
+		now = now.Add(10 * time.Second)
+		verifier.Expect("2", "form.created")
+		now = now.Add(25 * time.Second)
+
+		missing := verifier.Missing()
+		require.Len(t, missing, 1)
+		assert.Equal(t, "1", missing[0].CorrelationID)
+		assert.Equal(t, "form.updated", missing[0].EventType)
+		assert.Equal(t, publishedAt, missing[0].PublishedAt)
+		assert.Equal(t, now, missing[0].DetectedAt)
+		assert.Equal(t, 1, verifier.Pending())
+
+		now = now.Add(10 * time.Second)
+		missing = verifier.Missing()
+		require.Len(t, missing, 1)
+		assert.Equal(t, "2", missing[0].CorrelationID)
+		assert.Zero(t, verifier.Pending())
+	})
+
+	t.Run("forgotten events are not missing", func(t *testing.T) {
+		verifier := newVerifier()
+		verifier.Expect("1", "form.created")
+
+		verifier.Forget("1")
+
+		now = now.Add(time.Minute)
+		assert.Empty(t, verifier.Missing())
+	})
+}
@@ -0,0 +1,238 @@
+// Package metrics defines the Prometheus metrics exported by the service
+// and the HTTP handler that serves them.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NAMESPACE prefixes every metric exported by the service
+const NAMESPACE = "form_service"
+
+// RetryPolicy exposes the effective retry policy of each service side effect.
+// The "setting" label is either "retries" or "delay_seconds".
+var RetryPolicy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "retry_policy",
+	Help:      "Effective retry policy per service side effect.",
+}, []string{"side_effect", "setting"})
+
+// ConsumerThrottled is 1 while consumption of a queue is paused because a
+// dependency is unhealthy, 0 otherwise
+var ConsumerThrottled = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "consumer_throttled",
+	Help:      "Whether consumption of a queue is paused because a dependency is unhealthy.",
+}, []string{"queue", "dependency"})
+
+// RateLimitDecisions counts rate limit checks by outcome. The "decision"
+// label is "allowed", "limited" or "error".
+var RateLimitDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "ratelimit_decisions_total",
+	Help:      "Rate limit checks by limiter and outcome.",
+}, []string{"limiter", "decision"})
+
+// PublisherBacklog is the number of events buffered while the broker is unreachable
+var PublisherBacklog = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "publisher_backlog",
+	Help:      "Events buffered while the broker is unreachable.",
+})
+
+// PublisherBacklogOldest is the unix time the oldest buffered event was
+// buffered at, 0 while the buffer is empty. Subtract it from time() for the
+// backlog age.
+var PublisherBacklogOldest = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "publisher_backlog_oldest_timestamp_seconds",
+	Help:      "Unix time the oldest buffered event was buffered at, 0 while empty.",
+})
+
+// EventsCoalesced counts events dropped because a later event about the same
+// entity replaced them within the coalescing window
+var EventsCoalesced = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "events_coalesced_total",
+	Help:      "Events replaced by a later event about the same entity before publishing.",
+}, []string{"event_type"})
+
+// ListenerHandlerDuration observes how long request handlers take. The
+// "outcome" label is "ok", "rejected", "invalid" or "error".
+var ListenerHandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: NAMESPACE,
+	Name:      "listener_handler_duration_seconds",
+	Help:      "Duration of request handlers by handler and outcome.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"handler", "outcome"})
+
+// ListenerDeadLetters counts the requests subscribed handlers gave up on
+// after their retries
+var ListenerDeadLetters = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "listener_dead_letters_total",
+	Help:      "Requests subscribed handlers gave up on, by handler.",
+}, []string{"handler"})
+
+// ListenerWorkers is the size of the listener worker pool
+var ListenerWorkers = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "listener_workers",
+	Help:      "Size of the listener worker pool.",
+})
+
+// CacheBytes is the approximate size of the cache per namespace, the length
+// of every key and value, as last measured
+var CacheBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "cache_bytes",
+	Help:      "Approximate bytes cached per namespace.",
+}, []string{"namespace"})
+
+// CacheOverQuota is 1 while the cache exceeds its soft memory quota, 0 otherwise
+var CacheOverQuota = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "cache_over_quota",
+	Help:      "Whether the cache exceeds its soft memory quota.",
+})
+
+// CacheWritesDegraded counts forms cached with a shorter TTL or not cached
+// while over the memory quota. The "action" label is "short_ttl" or "skipped".
+var CacheWritesDegraded = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "cache_writes_degraded_total",
+	Help:      "Cache writes degraded by the soft memory quota, by action.",
+}, []string{"action"})
+
+// CacheReconcileChecked counts cached forms compared with the database by
+// cache reconciliation
+var CacheReconcileChecked = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "cache_reconcile_checked_total",
+	Help:      "Cached forms compared with the database.",
+})
+
+// CacheDrift counts cached forms found diverging from the database. The
+// "kind" label is "stale" for forms differing from their row or "orphaned"
+// for forms whose row is gone.
+var CacheDrift = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "cache_drift_total",
+	Help:      "Cached forms diverging from the database, by kind.",
+}, []string{"kind"})
+
+// IntegrityViolations counts inconsistencies found by the integrity check,
+// by kind, see entity.IntegrityViolation
+var IntegrityViolations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "integrity_violations_total",
+	Help:      "Inconsistent forms and questions found by the integrity check, by kind.",
+}, []string{"kind"})
+
+// LegacyEnvelopes counts events read or written with the deprecated casing
+// of the envelope fields, see package envelope. The "direction" label is
+// "read" or "write".
+var LegacyEnvelopes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "legacy_envelopes_total",
+	Help:      "Events read or written with the deprecated envelope field casing.",
+}, []string{"direction"})
+
+// RequestsExpired counts requests dropped unhandled on receipt because they
+// were sent longer than expiry.max_age ago
+var RequestsExpired = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "requests_expired_total",
+	Help:      "Requests dropped on receipt for being older than the max age.",
+})
+
+// DeadLetterDepth is the number of requests waiting in the dead letter
+// queue, expired by the broker or rejected, as last sampled
+var DeadLetterDepth = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "dead_letter_depth",
+	Help:      "Requests waiting in the dead letter queue.",
+})
+
+// ServiceOperationDuration observes how long business operations of the
+// service take, whichever transport triggered them. The "outcome" label is
+// "ok", "rejected" for operations refused by validation, quotas or access
+// rules, or "error".
+var ServiceOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: NAMESPACE,
+	Name:      "service_operation_duration_seconds",
+	Help:      "Duration of service operations by operation and outcome.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"operation", "outcome"})
+
+// ServiceRejections counts service operations refused by why, like
+// "rules" for malformed validation rules or "quota_exceeded"
+var ServiceRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "service_rejections_total",
+	Help:      "Service operations refused, by operation and reason.",
+}, []string{"operation", "reason"})
+
+// FormsPerAuthor is the number of authors with at most "forms" live forms,
+// cumulative like histogram buckets, as last sampled
+var FormsPerAuthor = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: NAMESPACE,
+	Name:      "forms_per_author",
+	Help:      "Authors with at most the given number of live forms.",
+}, []string{"forms"})
+
+// LoopbackMissing counts published events that didn't come back on the
+// loopback queue in time, by event type
+var LoopbackMissing = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "loopback_missing_events_total",
+	Help:      "Published events not received back on the loopback queue, by event type.",
+}, []string{"event_type"})
+
+// BatchSize observes the number of items flushed per batch by the batch
+// writers, see package batch
+var BatchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: NAMESPACE,
+	Name:      "batch_size",
+	Help:      "Items flushed per batch by writer.",
+	Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+}, []string{"writer"})
+
+// BatchFlushes counts the batches flushed by the batch writers. The
+// "outcome" label is "ok" or "error" for batches retried item by item.
+var BatchFlushes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "batch_flushes_total",
+	Help:      "Batches flushed by writer and outcome.",
+}, []string{"writer", "outcome"})
+
+// EventSubscriberFailures counts domain events an event bus subscriber
+// still failed to handle after its retries, by subscriber
+var EventSubscriberFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "event_subscriber_failures_total",
+	Help:      "Domain events a subscriber failed to handle after retrying, by subscriber.",
+}, []string{"subscriber"})
+
+// DomainEvents counts the domain events published by the service, by event
+// type. CountDomainEvent subscribes it to the event bus.
+var DomainEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: NAMESPACE,
+	Name:      "domain_events_total",
+	Help:      "Domain events published by the service, by event type.",
+}, []string{"event_type"})
+
+// CountDomainEvent counts a published domain event, as an event bus handler
+func CountDomainEvent(_ any, eventType string) error {
+	DomainEvents.WithLabelValues(eventType).Inc()
+	return nil
+}
+
+// Handler returns the HTTP handler serving all registered metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,152 @@
+// Package ratelimit provides a distributed sliding-window rate limiter
+// backed by Redis, so every service instance shares the same counters.
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Mode decides the outcome of a check when Redis can't be reached
+type Mode string
+
+const (
+	// FailOpen allows requests while Redis is unavailable
+	FailOpen Mode = "open"
+	// FailClosed rejects requests while Redis is unavailable
+	FailClosed Mode = "closed"
+)
+
+const (
+	// KEY_PREFIX namespaces the Redis keys of all limiters
+	KEY_PREFIX = "ratelimit"
+
+	// DEFAULT_WINDOW is used when no window is configured
+	DEFAULT_WINDOW = time.Minute
+)
+
+// ErrInvalidLimit is returned for checks with a non-positive limit
+var ErrInvalidLimit = errors.New("rate limit must be positive")
+
+// slidingWindow records a hit in a sorted set of hit timestamps unless the
+// window already holds limit hits. Returns {allowed, hits in window}.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+
+local count = redis.call('ZCARD', key)
+if count >= limit then
+	return {0, count}
+end
+
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, window)
+
+return {1, count + 1}
+`)
+
+type (
+	// Options configures a Limiter
+	Options struct {
+		Name   string        // Identifies the limiter in keys and metrics
+		Limit  int           // Hits allowed per window by Allow
+		Window time.Duration // Length of the sliding window
+		Mode   Mode          // Outcome while Redis is unavailable, FailOpen by default
+	}
+
+	// Limiter limits hits per key within a sliding window
+	Limiter struct {
+		client *redis.Client
+		opts   Options
+		logger *logger.Logger
+		now    func() time.Time // Replaced in tests
+	}
+)
+
+// New creates a limiter storing its windows in Redis
+func New(client *redis.Client, logger *logger.Logger, opts Options) *Limiter {
+	if opts.Window <= 0 {
+		opts.Window = DEFAULT_WINDOW
+	}
+
+	if opts.Mode == "" {
+		opts.Mode = FailOpen
+	}
+
+	return &Limiter{
+		client: client,
+		opts:   opts,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Allow records a hit for key and reports whether it is within the
+// configured limit
+func (l *Limiter) Allow(ctx context.Context, key string) (bool, error) {
+	return l.AllowLimit(ctx, key, l.opts.Limit)
+}
+
+// AllowLimit records a hit for key and reports whether it is within limit
+// hits per window. Rejected hits are not recorded. When Redis fails the
+// error is returned together with the outcome chosen by the limiter mode.
+func (l *Limiter) AllowLimit(ctx context.Context, key string, limit int) (bool, error) {
+	if limit <= 0 {
+		return false, ErrInvalidLimit
+	}
+
+	res, err := slidingWindow.Run(ctx, l.client,
+		[]string{l.key(key)},
+		l.now().UnixMilli(),
+		l.opts.Window.Milliseconds(),
+		limit,
+		member(l.now()),
+	).Int64Slice()
+	if err != nil {
+		allowed := l.opts.Mode == FailOpen
+
+		l.logger.Error("rate limit check failed",
+			zap.String("limiter", l.opts.Name),
+			zap.String("key", key),
+			zap.Bool("allowed", allowed),
+			zap.Error(err))
+		metrics.RateLimitDecisions.WithLabelValues(l.opts.Name, "error").Inc()
+
+		return allowed, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	if res[0] == 0 {
+		metrics.RateLimitDecisions.WithLabelValues(l.opts.Name, "limited").Inc()
+		return false, nil
+	}
+
+	metrics.RateLimitDecisions.WithLabelValues(l.opts.Name, "allowed").Inc()
+
+	return true, nil
+}
+
+// key returns the Redis key holding the window of key
+func (l *Limiter) key(key string) string {
+	return fmt.Sprintf("%s:%s:%s", KEY_PREFIX, l.opts.Name, key)
+}
+
+// member returns a unique sorted set member for a hit at now
+func member(now time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return fmt.Sprintf("%d-%s", now.UnixNano(), hex.EncodeToString(suffix))
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupLimiter(t *testing.T, opts Options) (*Limiter, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return New(client, &logger.Logger{Logger: zap.NewNop()}, opts), server
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	limiter, _ := setupLimiter(t, Options{Name: "test", Limit: 2, Window: time.Minute})
+	ctx := context.Background()
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		allowed, err := limiter.Allow(ctx, "tenant")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, err := limiter.Allow(ctx, "tenant")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = limiter.Allow(ctx, "other")
+	require.NoError(t, err)
+	assert.True(t, allowed, "keys are limited separately")
+
+	now = now.Add(61 * time.Second)
+
+	allowed, err = limiter.Allow(ctx, "tenant")
+	require.NoError(t, err)
+	assert.True(t, allowed, "old hits slide out of the window")
+}
+
+func TestLimiter_AllowLimit(t *testing.T) {
+	limiter, _ := setupLimiter(t, Options{Name: "test"})
+	ctx := context.Background()
+
+	allowed, err := limiter.AllowLimit(ctx, "tenant", 1)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = limiter.AllowLimit(ctx, "tenant", 1)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	_, err = limiter.AllowLimit(ctx, "tenant", 0)
+	assert.ErrorIs(t, err, ErrInvalidLimit)
+}
+
+func TestLimiter_Modes(t *testing.T) {
+	for _, tt := range []struct {
+		mode    Mode
+		allowed bool
+	}{
+		{FailOpen, true},
+		{FailClosed, false},
+	} {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			limiter, server := setupLimiter(t, Options{Name: "test", Limit: 1, Mode: tt.mode})
+			server.Close()
+
+			allowed, err := limiter.Allow(context.Background(), "tenant")
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.allowed, allowed)
+		})
+	}
+}
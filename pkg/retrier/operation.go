@@ -1,10 +1,23 @@
 package retrier
 
-import "time"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 type Try func() error
 
-func Do(number uint8, duration time.Duration, try Try) error {
+// Do runs try up to number times, pausing duration between failed attempts,
+// and returns nil once an attempt succeeds or the error of the last one.
+// Once ctx is done no further attempt is made: the pause is cut short and
+// the last error is returned joined with the error of ctx. A ctx done
+// before the first attempt returns its error without trying.
+func Do(ctx context.Context, number uint8, duration time.Duration, try Try) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	var err error
 
 	for i := uint8(0); i < number; i++ {
@@ -15,9 +28,28 @@ func Do(number uint8, duration time.Duration, try Try) error {
 		}
 
 		if i < number-1 {
-			time.Sleep(duration)
+			if waitErr := wait(ctx, duration); waitErr != nil {
+				return errors.Join(err, waitErr)
+			}
 		}
 	}
 
 	return err
 }
+
+// wait pauses for duration, returning the error of ctx if it is done first
+func wait(ctx context.Context, duration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
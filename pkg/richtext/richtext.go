@@ -0,0 +1,205 @@
+// Package richtext sanitizes the HTML and Markdown authors write in form
+// descriptions and questions before they are stored. Only an allowlist of
+// formatting elements and attributes survives; scripts, styles, event
+// handlers and links to anything but web pages and mail addresses are removed,
+// so renderers can display the texts to respondents as they are.
+package richtext
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowed are the elements kept, with the attributes kept on them
+var allowed = map[atom.Atom][]string{
+	atom.A:          {"href", "title"},
+	atom.B:          nil,
+	atom.Blockquote: nil,
+	atom.Br:         nil,
+	atom.Code:       nil,
+	atom.Em:         nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Li:         nil,
+	atom.Ol:         nil,
+	atom.P:          nil,
+	atom.Pre:        nil,
+	atom.S:          nil,
+	atom.Span:       nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Sup:        nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+}
+
+// dropped are the elements removed together with their content, other
+// elements that aren't allowed only lose their tags
+var dropped = map[atom.Atom]bool{
+	atom.Embed:    true,
+	atom.Iframe:   true,
+	atom.Noscript: true,
+	atom.Object:   true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Template: true,
+	atom.Textarea: true,
+	atom.Title:    true,
+}
+
+// SAFE_SCHEMES are the URL schemes links may use, links without a scheme
+// are relative and kept as well
+var SAFE_SCHEMES = []string{"http", "https", "mailto"}
+
+// UNSAFE_LINK replaces the targets of links that aren't safe
+const UNSAFE_LINK = "#"
+
+var (
+	// markdownLink matches the target of inline links and images,
+	// [text](target), which may contain balanced parentheses
+	markdownLink = regexp.MustCompile(`(\]\(\s*<?)((?:[^\s()<>]|\([^\s()]*\))*)`)
+
+	// markdownReference matches the target of reference definitions, [name]: target
+	markdownReference = regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:\s*<?)([^\s>]*)`)
+)
+
+// textEscaper escapes text for HTML, leaving the quotes alone that only
+// matter in attributes
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// SanitizeHTML returns text with only allowed elements and attributes and
+// with its text escaped, so it is safe to insert into a page
+func SanitizeHTML(text string) string {
+	return sanitize(text, true)
+}
+
+// SanitizeMarkdown returns text with the HTML embedded in it sanitized like
+// SanitizeHTML and unsafe link targets replaced by UNSAFE_LINK. The Markdown
+// itself is kept, HTML inside code spans is sanitized as well.
+func SanitizeMarkdown(text string) string {
+	text = sanitize(text, false)
+	text = markdownLink.ReplaceAllStringFunc(text, safeMarkdownLink(markdownLink))
+
+	return markdownReference.ReplaceAllStringFunc(text, safeMarkdownLink(markdownReference))
+}
+
+// sanitize keeps the allowed elements of text. Text between them is
+// escaped for HTML or, when escape is false, kept as it was written.
+func sanitize(text string, escape bool) string {
+	z := html.NewTokenizer(strings.NewReader(text))
+
+	var b strings.Builder
+	skip := 0 // Depth inside dropped elements
+
+	for {
+		tt := z.Next()
+
+		switch tt {
+		case html.ErrorToken:
+			return b.String()
+
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+
+			if escape {
+				b.WriteString(textEscaper.Replace(string(z.Text())))
+			} else {
+				b.Write(z.Raw())
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+
+			if dropped[tok.DataAtom] {
+				if tt == html.StartTagToken {
+					skip++
+				}
+				continue
+			}
+
+			if attrs, ok := allowed[tok.DataAtom]; ok && skip == 0 {
+				writeStartTag(&b, tok, attrs)
+			}
+
+		case html.EndTagToken:
+			tok := z.Token()
+
+			if dropped[tok.DataAtom] {
+				skip = max(skip-1, 0)
+				continue
+			}
+
+			if _, ok := allowed[tok.DataAtom]; ok && skip == 0 {
+				b.WriteString("</" + tok.Data + ">")
+			}
+		}
+
+		// Comments and doctypes are dropped
+	}
+}
+
+// writeStartTag writes the start tag of tok with the attributes of attrs it
+// has, links only with safe targets
+func writeStartTag(b *strings.Builder, tok html.Token, attrs []string) {
+	b.WriteString("<" + tok.Data)
+
+	for _, attr := range tok.Attr {
+		if attr.Namespace != "" || !slices.Contains(attrs, attr.Key) {
+			continue
+		}
+		if attr.Key == "href" && !SafeURL(attr.Val) {
+			continue
+		}
+
+		b.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+
+	b.WriteString(">")
+}
+
+// safeMarkdownLink returns a replacement for matches of link, whose second
+// group is a link target, replacing unsafe targets
+func safeMarkdownLink(link *regexp.Regexp) func(string) string {
+	return func(match string) string {
+		groups := link.FindStringSubmatch(match)
+		if SafeURL(groups[2]) {
+			return match
+		}
+
+		return groups[1] + UNSAFE_LINK
+	}
+}
+
+// SafeURL reports whether a link to target is safe to follow: it is
+// relative or uses one of SAFE_SCHEMES
+func SafeURL(target string) bool {
+	// Markdown renderers decode character references in targets and
+	// browsers ignore whitespace and control characters in schemes, so
+	// &#106;ava\tscript: runs as well
+	target = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, html.UnescapeString(target))
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "" || slices.Contains(SAFE_SCHEMES, strings.ToLower(u.Scheme))
+}
@@ -0,0 +1,62 @@
+package richtext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"formatting is kept", "<p>Hello <strong>there</strong><br/></p>", "<p>Hello <strong>there</strong><br></p>"},
+		{"scripts are removed with their content", "Hi<script>alert(1)</script>!", "Hi!"},
+		{"unknown elements lose their tags", `<div class="x">text</div>`, "text"},
+		{"event handlers are removed", `<p onclick="alert(1)">text</p>`, "<p>text</p>"},
+		{"safe links are kept", `<a href="https://example.com?a=1&b=2" target="_blank">link</a>`, `<a href="https://example.com?a=1&amp;b=2">link</a>`},
+		{"unsafe links lose their target", `<a href="javascript:alert(1)">link</a>`, "<a>link</a>"},
+		{"obfuscated schemes are unsafe", `<a href="&#106;ava&#x09;script:alert(1)">link</a>`, "<a>link</a>"},
+		{"text is escaped", "1 &lt; 2 & 3 > 2", "1 &lt; 2 &amp; 3 &gt; 2"},
+		{"comments are removed", "a<!-- <script> -->b", "ab"},
+		{"plain text is unchanged", `Say "hi"`, `Say "hi"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizeHTML(tt.text))
+		})
+	}
+}
+
+func TestSanitizeMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"markdown is kept", "# Title\n\n**bold** & _em_ > quote", "# Title\n\n**bold** & _em_ > quote"},
+		{"embedded html is sanitized", "Hi <img src=x onerror=alert(1)> <b>there</b>", "Hi  <b>there</b>"},
+		{"safe links are kept", "[docs](https://example.com/docs) and [top](#top)", "[docs](https://example.com/docs) and [top](#top)"},
+		{"unsafe links lose their target", "[x](javascript:alert`1`)", "[x](#)"},
+		{"targets may contain parentheses", "[x](javascript:alert(1)) [y](https://example.com/a_(b))", "[x](#) [y](https://example.com/a_(b))"},
+		{"unsafe references lose their target", "[x]\n\n[x]: data:text/html,hi", "[x]\n\n[x]: #"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizeMarkdown(tt.text))
+		})
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	assert.True(t, SafeURL("https://example.com"))
+	assert.True(t, SafeURL("MAILTO:someone@example.com"))
+	assert.True(t, SafeURL("/forms/1"))
+	assert.False(t, SafeURL("JavaScript:alert(1)"))
+	assert.False(t, SafeURL(" java\nscript:alert(1)"))
+	assert.False(t, SafeURL("vbscript:msgbox"))
+}
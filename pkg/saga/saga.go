@@ -0,0 +1,106 @@
+// Package saga provides a small coordinator for operations that touch several
+// resources (rows, cache keys, events) without a shared transaction.
+// Each step pairs an action with a compensating action; when a step fails,
+// the compensations of all previously completed steps run in reverse order.
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type (
+	// Step is a single unit of work within a saga
+	Step struct {
+		Name       string       // Step name used in logs and errors
+		Action     func() error // Forward action
+		Compensate func() error // Undo action, nil if the step has nothing to undo
+	}
+
+	// Saga runs steps in order and compensates completed ones on failure
+	Saga struct {
+		name   string
+		steps  []Step
+		logger *logger.Logger
+	}
+)
+
+// New creates an empty saga identified by name in logs
+func New(name string, logger *logger.Logger) *Saga {
+	return &Saga{
+		name:   name,
+		logger: logger,
+	}
+}
+
+// Add appends a step and returns the saga for chaining
+func (s *Saga) Add(name string, action, compensate func() error) *Saga {
+	s.steps = append(s.steps, Step{
+		Name:       name,
+		Action:     action,
+		Compensate: compensate,
+	})
+
+	return s
+}
+
+// Run executes the steps in order. If a step fails, the compensations of the
+// steps that already completed are run in reverse order and the step error is
+// returned, joined with any compensation failures.
+func (s *Saga) Run() error {
+	for i, step := range s.steps {
+		start := time.Now()
+
+		if err := step.Action(); err != nil {
+			s.logger.Error("saga step failed",
+				zap.String("saga", s.name),
+				zap.String("step", step.Name),
+				zap.Duration("duration", time.Since(start)),
+				zap.Error(err))
+
+			stepErr := fmt.Errorf("saga %s: step %s: %w", s.name, step.Name, err)
+
+			return errors.Join(stepErr, s.compensate(s.steps[:i]))
+		}
+
+		s.logger.Debug("saga step completed",
+			zap.String("saga", s.name),
+			zap.String("step", step.Name),
+			zap.Duration("duration", time.Since(start)))
+	}
+
+	return nil
+}
+
+// compensate undoes completed steps from last to first. Every compensation is
+// attempted even if an earlier one fails
+func (s *Saga) compensate(completed []Step) error {
+	var errs []error
+
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.Compensate == nil {
+			continue
+		}
+
+		if err := step.Compensate(); err != nil {
+			s.logger.Error("saga compensation failed",
+				zap.String("saga", s.name),
+				zap.String("step", step.Name),
+				zap.Error(err))
+
+			errs = append(errs, fmt.Errorf("compensate %s: %w", step.Name, err))
+			continue
+		}
+
+		s.logger.Info("saga step compensated",
+			zap.String("saga", s.name),
+			zap.String("step", step.Name))
+	}
+
+	return errors.Join(errs...)
+}
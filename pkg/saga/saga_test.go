@@ -0,0 +1,66 @@
+package saga
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newTestSaga() *Saga {
+	return New("test", &logger.Logger{Logger: zap.NewNop()})
+}
+
+func TestSaga_Run(t *testing.T) {
+	t.Run("runs every step in order", func(t *testing.T) {
+		var calls []string
+
+		err := newTestSaga().
+			Add("create rows", func() error { calls = append(calls, "rows"); return nil }, nil).
+			Add("cache", func() error { calls = append(calls, "cache"); return nil }, nil).
+			Run()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"rows", "cache"}, calls)
+	})
+
+	t.Run("compensates completed steps in reverse order", func(t *testing.T) {
+		var calls []string
+
+		step := func(name string) (func() error, func() error) {
+			return func() error { calls = append(calls, name); return nil },
+				func() error { calls = append(calls, "undo "+name); return nil }
+		}
+
+		rows, undoRows := step("rows")
+		cache, undoCache := step("cache")
+
+		err := newTestSaga().
+			Add("rows", rows, undoRows).
+			Add("cache", cache, undoCache).
+			Add("publish", func() error { return errors.New("broker down") }, func() error {
+				calls = append(calls, "undo publish")
+				return nil
+			}).
+			Run()
+
+		assert.ErrorContains(t, err, "step publish: broker down")
+		assert.Equal(t, []string{"rows", "cache", "undo cache", "undo rows"}, calls)
+	})
+
+	t.Run("reports compensation failures and keeps compensating", func(t *testing.T) {
+		undone := false
+
+		err := newTestSaga().
+			Add("rows", func() error { return nil }, func() error { undone = true; return nil }).
+			Add("cache", func() error { return nil }, func() error { return errors.New("redis down") }).
+			Add("publish", func() error { return errors.New("broker down") }, nil).
+			Run()
+
+		assert.ErrorContains(t, err, "broker down")
+		assert.ErrorContains(t, err, "compensate cache: redis down")
+		assert.True(t, undone)
+	})
+}
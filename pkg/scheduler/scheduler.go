@@ -0,0 +1,106 @@
+// Package scheduler runs periodic maintenance jobs such as cleaning up
+// orphaned uploads. Every job runs in its own goroutine; a job never
+// overlaps with itself.
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type (
+	// Job is a unit of periodic work. It should return once ctx is done.
+	Job func(ctx context.Context) error
+
+	job struct {
+		name     string
+		interval time.Duration // 0 runs the job once
+		run      Job
+	}
+
+	// Scheduler runs registered jobs at their interval until closed
+	Scheduler struct {
+		jobs   []job
+		logger *logger.Logger
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+	}
+)
+
+// New creates a scheduler without jobs
+func New(logger *logger.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		cancel: func() {},
+	}
+}
+
+// Every registers a job run once per interval. Jobs must be registered before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, run Job) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Once registers a job run a single time right after Start, e.g. a check at
+// boot. Jobs must be registered before Start.
+func (s *Scheduler) Once(name string, run Job) {
+	s.jobs = append(s.jobs, job{name: name, run: run})
+}
+
+// Start runs every registered job until ctx is done or Close is called
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	if j.interval == 0 {
+		s.runJob(ctx, j)
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runJob(ctx, j)
+		}
+	}
+}
+
+// runJob runs j once, logging its outcome
+func (s *Scheduler) runJob(ctx context.Context, j job) {
+	started := time.Now()
+
+	if err := j.run(ctx); err != nil {
+		s.logger.Error("scheduled job failed",
+			zap.String("job", j.name),
+			zap.Error(err))
+		return
+	}
+
+	s.logger.Debug("scheduled job finished",
+		zap.String("job", j.name),
+		zap.Duration("took", time.Since(started)))
+}
+
+// Close stops the jobs and waits for running ones to return
+func (s *Scheduler) Close() error {
+	s.cancel()
+	s.wg.Wait()
+
+	return nil
+}
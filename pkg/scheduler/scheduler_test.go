@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestScheduler(t *testing.T) {
+	s := New(&logger.Logger{Logger: zap.NewNop()})
+
+	var ok, failing atomic.Int32
+	s.Every("ok", time.Millisecond, func(context.Context) error {
+		ok.Add(1)
+		return nil
+	})
+	s.Every("failing", time.Millisecond, func(context.Context) error {
+		failing.Add(1)
+		return errors.New("boom")
+	})
+
+	s.Start(context.Background())
+
+	assert.Eventually(t, func() bool { return ok.Load() >= 3 && failing.Load() >= 3 },
+		time.Second, time.Millisecond, "a failing job keeps being scheduled")
+
+	assert.NoError(t, s.Close())
+
+	runs := ok.Load()
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, runs, ok.Load(), "jobs stop after Close")
+}
+
+func TestScheduler_Once(t *testing.T) {
+	s := New(&logger.Logger{Logger: zap.NewNop()})
+
+	var runs atomic.Int32
+	s.Once("boot", func(context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	s.Start(context.Background())
+	assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+	assert.NoError(t, s.Close())
+	assert.Equal(t, int32(1), runs.Load())
+}
@@ -0,0 +1,210 @@
+// Package schemaregistry registers the schemas of event payloads with a
+// Confluent-compatible schema registry. Payloads are JSON, so their schemas
+// are JSON schemas generated from the Go types each event carries. At
+// startup every schema is checked against the latest version registered for
+// its subject and registered; the IDs it gets are sent in the headers of the
+// events, so consumers can fetch the exact schema a payload was written with.
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// SCHEMA_TYPE is the registry type of the registered schemas
+	SCHEMA_TYPE = "JSON"
+
+	// SUBJECT_SUFFIX follows the event type in subjects, as the registry's
+	// default naming does for message values
+	SUBJECT_SUFFIX = "-value"
+
+	// CONTENT_TYPE is the media type of the registry API
+	CONTENT_TYPE = "application/vnd.schemaregistry.v1+json"
+
+	// DEFAULT_TIMEOUT bounds each request when no timeout is configured
+	DEFAULT_TIMEOUT = 10 * time.Second
+)
+
+// Registry error codes of subjects and versions that don't exist yet
+const (
+	codeSubjectNotFound = 40401
+	codeVersionNotFound = 40402
+)
+
+// ErrIncompatible is returned for schemas breaking the compatibility rules
+// of their subject
+var ErrIncompatible = errors.New("schema is incompatible with the registered one")
+
+type (
+	// Options configures a Client
+	Options struct {
+		Username string        // Basic auth user, empty for none
+		Password string        // Basic auth password
+		Timeout  time.Duration // Bounds each request
+	}
+
+	// Client talks to the REST API of a schema registry
+	Client struct {
+		url  string
+		http *http.Client
+		opts Options
+	}
+
+	// Error is an error response of the registry
+	Error struct {
+		Status  int    `json:"-"`
+		Code    int    `json:"error_code"`
+		Message string `json:"message"`
+	}
+
+	// schemaRequest is the body registering or checking a schema
+	schemaRequest struct {
+		SchemaType string `json:"schemaType"`
+		Schema     string `json:"schema"`
+	}
+)
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("schema registry responded %d (%d): %s", e.Status, e.Code, e.Message)
+}
+
+// New creates a client of the registry at baseURL
+func New(baseURL string, opts Options) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DEFAULT_TIMEOUT
+	}
+
+	return &Client{
+		url:  strings.TrimSuffix(baseURL, "/"),
+		http: &http.Client{Timeout: opts.Timeout},
+		opts: opts,
+	}
+}
+
+// Subject returns the subject the payload schema of eventType is registered under
+func Subject(eventType string) string {
+	return eventType + SUBJECT_SUFFIX
+}
+
+// Compatible reports whether schema may be registered as the next version
+// of subject. Any schema is compatible with a subject without versions.
+func (c *Client) Compatible(ctx context.Context, subject string, schema []byte) (bool, error) {
+	var res struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+
+	err := c.post(ctx, "/compatibility/subjects/"+url.PathEscape(subject)+"/versions/latest", schema, &res)
+
+	var regErr *Error
+	if errors.As(err, &regErr) && (regErr.Code == codeSubjectNotFound || regErr.Code == codeVersionNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return res.IsCompatible, nil
+}
+
+// Register registers schema under subject and returns its ID. Registering a
+// schema again returns the ID it already has.
+func (c *Client) Register(ctx context.Context, subject string, schema []byte) (int, error) {
+	var res struct {
+		ID int `json:"id"`
+	}
+
+	if err := c.post(ctx, "/subjects/"+url.PathEscape(subject)+"/versions", schema, &res); err != nil {
+		return 0, err
+	}
+
+	return res.ID, nil
+}
+
+// Sync checks the schemas, keyed by event type, for compatibility and then
+// registers them. Nothing is registered unless every schema is compatible,
+// so a release doesn't leave the registry half updated.
+// Returns the schema IDs by event type
+func (c *Client) Sync(ctx context.Context, schemas map[string][]byte) (map[string]int, error) {
+	types := make([]string, 0, len(schemas))
+	for eventType := range schemas {
+		types = append(types, eventType)
+	}
+	slices.Sort(types)
+
+	var errs []error
+	for _, eventType := range types {
+		ok, err := c.Compatible(ctx, Subject(eventType), schemas[eventType])
+		if err != nil {
+			return nil, fmt.Errorf("failed to check schema of %s: %w", eventType, err)
+		}
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrIncompatible, Subject(eventType)))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]int, len(types))
+	for _, eventType := range types {
+		id, err := c.Register(ctx, Subject(eventType), schemas[eventType])
+		if err != nil {
+			return nil, fmt.Errorf("failed to register schema of %s: %w", eventType, err)
+		}
+
+		ids[eventType] = id
+	}
+
+	return ids, nil
+}
+
+// post sends schema to path and decodes the response into out
+func (c *Client) post(ctx context.Context, path string, schema []byte, out any) error {
+	body, err := json.Marshal(schemaRequest{SchemaType: SCHEMA_TYPE, Schema: string(schema)})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", CONTENT_TYPE)
+	req.Header.Set("Accept", CONTENT_TYPE)
+	if c.opts.Username != "" {
+		req.SetBasicAuth(c.opts.Username, c.opts.Password)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		regErr := &Error{Status: res.StatusCode}
+		if json.Unmarshal(data, regErr) != nil || regErr.Message == "" {
+			regErr.Message = strings.TrimSpace(string(data))
+		}
+
+		return regErr
+	}
+
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,111 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry is a registry keeping the schemas registered per subject
+type fakeRegistry struct {
+	mu           sync.Mutex
+	subjects     map[string][]string
+	incompatible map[string]bool
+	auth         string
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auth, _, _ = r.BasicAuth()
+
+	var req schemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SchemaType != SCHEMA_TYPE {
+		http.Error(w, `{"error_code":42201,"message":"invalid schema"}`, http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", CONTENT_TYPE)
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/compatibility/subjects/"):
+		subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/compatibility/subjects/"), "/versions/latest")
+		if len(f.subjects[subject]) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40401,"message":"Subject not found."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]bool{"is_compatible": !f.incompatible[subject]})
+
+	case strings.HasPrefix(r.URL.Path, "/subjects/"):
+		subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+		f.subjects[subject] = append(f.subjects[subject], req.Schema)
+		json.NewEncoder(w).Encode(map[string]int{"id": len(f.subjects)*100 + len(f.subjects[subject])})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func setupRegistry(t *testing.T) (*fakeRegistry, *Client) {
+	t.Helper()
+
+	fake := &fakeRegistry{subjects: make(map[string][]string), incompatible: make(map[string]bool)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, New(server.URL+"/", Options{Username: "service", Password: "secret"})
+}
+
+func TestClient_Sync(t *testing.T) {
+	t.Run("registers every schema", func(t *testing.T) {
+		fake, client := setupRegistry(t)
+		fake.subjects["form.created-value"] = []string{`{"type":"object"}`}
+
+		ids, err := client.Sync(context.Background(), map[string][]byte{
+			"form.created": []byte(`{"type":"object","required":[]}`),
+			"form.deleted": []byte(`{"type":"object"}`),
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, ids, 2)
+		assert.Len(t, fake.subjects["form.created-value"], 2)
+		assert.Equal(t, []string{`{"type":"object"}`}, fake.subjects["form.deleted-value"])
+		assert.Equal(t, "service", fake.auth)
+	})
+
+	t.Run("registers nothing when a schema is incompatible", func(t *testing.T) {
+		fake, client := setupRegistry(t)
+		fake.subjects["form.created-value"] = []string{`{"type":"object"}`}
+		fake.incompatible["form.created-value"] = true
+
+		_, err := client.Sync(context.Background(), map[string][]byte{
+			"form.created": []byte(`{"type":"string"}`),
+			"form.deleted": []byte(`{"type":"object"}`),
+		})
+
+		assert.ErrorIs(t, err, ErrIncompatible)
+		assert.ErrorContains(t, err, "form.created-value")
+		assert.Len(t, fake.subjects["form.created-value"], 1)
+		assert.Empty(t, fake.subjects["form.deleted-value"])
+	})
+}
+
+func TestClient_RegistryError(t *testing.T) {
+	_, client := setupRegistry(t)
+	client.url += "/unknown"
+
+	_, err := client.Register(context.Background(), "form.created-value", []byte(`{}`))
+
+	var regErr *Error
+	require.ErrorAs(t, err, &regErr)
+	assert.Equal(t, http.StatusNotFound, regErr.Status)
+}
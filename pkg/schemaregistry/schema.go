@@ -0,0 +1,214 @@
+package schemaregistry
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DRAFT is the JSON schema dialect of the generated schemas
+const DRAFT = "http://json-schema.org/draft-07/schema#"
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	uuidType      = reflect.TypeOf(uuid.UUID{})
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	unsafeName    = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// generator builds a schema, collecting the named structs it refers to
+type generator struct {
+	definitions map[string]any
+}
+
+// Schema returns the JSON schema of payloads of the given types, as
+// encoding/json encodes them. A payload may be of any of the types. Named
+// structs become definitions, so recursive types are described as well.
+// Types encoding themselves, other than times and UUIDs, accept any value.
+func Schema(types ...reflect.Type) ([]byte, error) {
+	g := &generator{definitions: make(map[string]any)}
+
+	alternatives := make([]any, 0, len(types))
+	for _, t := range types {
+		alternatives = append(alternatives, g.schema(t))
+	}
+
+	doc := map[string]any{
+		"$schema": DRAFT,
+		"anyOf":   alternatives,
+	}
+	if len(g.definitions) > 0 {
+		doc["definitions"] = g.definitions
+	}
+
+	return json.Marshal(doc)
+}
+
+// schema returns the schema of values of t
+func (g *generator) schema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return nullable(g.schema(t.Elem()))
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]any{"type": "string", "format": "uuid"}
+	}
+
+	if t.Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(jsonMarshaler) {
+		return map[string]any{}
+	}
+	if t.Implements(textMarshaler) || reflect.PointerTo(t).Implements(textMarshaler) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nullable(map[string]any{"type": "string", "contentEncoding": "base64"})
+		}
+		return nullable(map[string]any{"type": "array", "items": g.schema(t.Elem())})
+	case reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem()), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Map:
+		return nullable(map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())})
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return g.definition(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+// definition returns a reference to the definition of the named struct t,
+// adding the definition the first time
+func (g *generator) definition(t reflect.Type) map[string]any {
+	name := unsafeName.ReplaceAllString(path.Base(t.PkgPath())+"."+t.Name(), "_")
+	ref := map[string]any{"$ref": "#/definitions/" + name}
+
+	if _, ok := g.definitions[name]; ok {
+		return ref
+	}
+
+	// Set before describing the fields, which may refer back to t
+	g.definitions[name] = true
+	g.definitions[name] = g.object(t)
+
+	return ref
+}
+
+// object returns the schema of the struct t, with the fields encoding/json
+// encodes: those of embedded structs are promoted unless a shallower field
+// has the same name, and fields without omitempty are required
+func (g *generator) object(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	g.fields(t, properties, &required)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// fields adds the fields of t to properties. Fields promoted from embedded
+// structs are added after the direct ones, so the direct ones win.
+func (g *generator) fields(t reflect.Type, properties map[string]any, required *[]string) {
+	var promoted []reflect.Type
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				promoted = append(promoted, ft)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := properties[name]; ok {
+			continue
+		}
+
+		schema := g.schema(f.Type)
+		if hasOption(opts, "string") {
+			schema = map[string]any{"type": "string"}
+		}
+		properties[name] = schema
+
+		if !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+
+	for _, ft := range promoted {
+		g.fields(ft, properties, required)
+	}
+}
+
+// hasOption reports whether the options of a json tag include option
+func hasOption(opts, option string) bool {
+	for opts != "" {
+		var name string
+		name, opts, _ = strings.Cut(opts, ",")
+		if name == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nullable extends schema to accept null, which nil pointers, slices and
+// maps encode to
+func nullable(schema map[string]any) map[string]any {
+	if len(schema) == 0 {
+		return schema
+	}
+
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
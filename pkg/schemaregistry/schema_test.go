@@ -0,0 +1,88 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	base struct {
+		ID      uuid.UUID `json:"id"`
+		Created time.Time `json:"created_at"`
+		Title   string    `json:"title"`
+	}
+
+	node struct {
+		base
+		Title    string          `json:"title,omitempty"` // Hides the title of base
+		Count    int64           `json:"count,string"`
+		Children []node          `json:"children,omitempty"`
+		Parent   *node           `json:"parent"`
+		Labels   map[string]bool `json:"labels,omitempty"`
+		Raw      json.RawMessage `json:"raw,omitempty"`
+		Secret   string          `json:"-"`
+		hidden   string
+	}
+)
+
+func decodeSchema(t *testing.T, types ...reflect.Type) map[string]any {
+	t.Helper()
+
+	data, err := Schema(types...)
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	return schema
+}
+
+func TestSchema(t *testing.T) {
+	schema := decodeSchema(t, reflect.TypeOf(node{}))
+
+	assert.Equal(t, DRAFT, schema["$schema"])
+	assert.Equal(t, []any{map[string]any{"$ref": "#/definitions/schemaregistry.node"}}, schema["anyOf"])
+
+	def := schema["definitions"].(map[string]any)["schemaregistry.node"].(map[string]any)
+	props := def["properties"].(map[string]any)
+
+	assert.ElementsMatch(t, []any{"id", "created_at", "title", "count", "children", "parent", "labels", "raw"}, keys(props))
+	assert.Equal(t, map[string]any{"type": "string", "format": "uuid"}, props["id"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, props["created_at"])
+	assert.Equal(t, map[string]any{"type": "string"}, props["count"], "the string option encodes numbers as strings")
+	assert.Equal(t, map[string]any{
+		"type":  []any{"array", "null"},
+		"items": map[string]any{"$ref": "#/definitions/schemaregistry.node"},
+	}, props["children"])
+	assert.Equal(t, map[string]any{"anyOf": []any{
+		map[string]any{"$ref": "#/definitions/schemaregistry.node"},
+		map[string]any{"type": "null"},
+	}}, props["parent"])
+	assert.Equal(t, map[string]any{}, props["raw"], "self-encoding types accept any value")
+
+	assert.ElementsMatch(t, []any{"id", "created_at", "count", "parent"}, def["required"],
+		"the title of node is optional and hides the required one of base")
+}
+
+func TestSchema_Alternatives(t *testing.T) {
+	schema := decodeSchema(t, reflect.TypeOf(base{}), reflect.TypeOf(""))
+
+	assert.Equal(t, []any{
+		map[string]any{"$ref": "#/definitions/schemaregistry.base"},
+		map[string]any{"type": "string"},
+	}, schema["anyOf"])
+}
+
+func keys(m map[string]any) []any {
+	keys := make([]any, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
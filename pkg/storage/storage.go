@@ -0,0 +1,137 @@
+// Package storage talks to the S3-compatible object store that keeps files
+// uploaded as answers. Clients upload directly with presigned URLs, so file
+// contents never pass through the service.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.uber.org/zap"
+)
+
+// ErrNotFound is returned when an object doesn't exist
+var ErrNotFound = errors.New("object not found")
+
+type (
+	// ObjectInfo describes a stored object
+	ObjectInfo struct {
+		Key         string
+		Size        int64
+		ContentType string
+		UploadedAt  time.Time
+	}
+
+	// Options configures the connection to the object store
+	Options struct {
+		Endpoint  string
+		AccessKey string
+		SecretKey string
+		Bucket    string
+		UseSSL    bool
+	}
+
+	// Storage is an S3-compatible object store client
+	Storage struct {
+		client *minio.Client
+		bucket string
+		logger *logger.Logger
+	}
+)
+
+// Init connects to the object store and makes sure the bucket exists
+func Init(ctx context.Context, opts Options, logger *logger.Logger) (*Storage, error) {
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: opts.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", opts.Bucket, err)
+	}
+
+	if !exists {
+		if err = client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", opts.Bucket, err)
+		}
+
+		logger.Info("created storage bucket", zap.String("bucket", opts.Bucket))
+	}
+
+	return &Storage{
+		client: client,
+		bucket: opts.Bucket,
+		logger: logger,
+	}, nil
+}
+
+// PresignUpload returns a URL the client can PUT the object to until ttl passes
+func (s *Storage) PresignUpload(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := s.client.PresignedPutObject(ctx, s.bucket, key, ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign upload for %s: %w", key, err)
+	}
+
+	return u.String(), nil
+}
+
+// PresignDownload returns a URL the object can be fetched from until ttl passes
+func (s *Storage) PresignDownload(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, ttl, url.Values{})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign download for %s: %w", key, err)
+	}
+
+	return u.String(), nil
+}
+
+// Stat returns the object metadata or ErrNotFound
+func (s *Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+
+	return &ObjectInfo{
+		Key:         info.Key,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		UploadedAt:  info.LastModified,
+	}, nil
+}
+
+// Delete removes the object. Deleting a missing object is not an error.
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		s.logger.Error("error delete object",
+			zap.String("key", key),
+			zap.Error(err))
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// IsHealthy reports whether the bucket is reachable
+func (s *Storage) IsHealthy() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := s.client.BucketExists(ctx, s.bucket)
+
+	return err == nil
+}
@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AMQP operations
+const (
+	AMQP_PUBLISH = "publish"
+	AMQP_RECEIVE = "receive"
+)
+
+// amqpCarrier carries trace context in AMQP message headers
+type amqpCarrier amqp.Table
+
+func (c amqpCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c amqpCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c amqpCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+var (
+	amqpDurationOnce sync.Once
+	amqpDuration     metric.Float64Histogram
+)
+
+// amqpHistogram creates the AMQP duration histogram on the global meter
+// provider, which forwards to the one Init installs even when created first
+func amqpHistogram() metric.Float64Histogram {
+	amqpDurationOnce.Do(func() {
+		amqpDuration, _ = otel.Meter(INSTRUMENTATION_NAME).Float64Histogram(
+			"messaging.client.operation.duration",
+			metric.WithUnit("s"),
+			metric.WithDescription("Duration of AMQP publishes and delivery receipts."),
+		)
+	})
+
+	return amqpDuration
+}
+
+// StartAMQP starts a span and timing for an AMQP operation on the exchange
+// destination. Publishes inject the trace context into headers, which must
+// not be nil; received deliveries continue the trace found in headers.
+// The returned function ends the operation with its error.
+func StartAMQP(ctx context.Context, operation, destination, routingKey string, headers amqp.Table) (context.Context, func(error)) {
+	kind := trace.SpanKindProducer
+	if operation == AMQP_RECEIVE {
+		kind = trace.SpanKindConsumer
+		ctx = otel.GetTextMapPropagator().Extract(ctx, amqpCarrier(headers))
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("messaging.system", "rabbitmq"),
+		attribute.String("messaging.operation.name", operation),
+		attribute.String("messaging.destination.name", destination),
+	}
+
+	ctx, span := otel.Tracer(INSTRUMENTATION_NAME).Start(ctx, operation+" "+destination,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(append(attrs, attribute.String("messaging.rabbitmq.destination.routing_key", routingKey))...),
+	)
+
+	if operation == AMQP_PUBLISH {
+		otel.GetTextMapPropagator().Inject(ctx, amqpCarrier(headers))
+	}
+
+	started := time.Now()
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			attrs = append(attrs, attribute.String("error.type", fmt.Sprintf("%T", err)))
+		}
+
+		amqpHistogram().Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(attrs...))
+		span.End()
+	}
+}
@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gorm.io/gorm"
+)
+
+// startKey stores the start time of a statement in its instance settings
+const startKey = "telemetry:start"
+
+// queryMetrics records the duration of every GORM statement by operation
+// and outcome. The tracing plugin only reports connection pool stats.
+type queryMetrics struct {
+	duration metric.Float64Histogram
+}
+
+func (queryMetrics) Name() string {
+	return "telemetry:query_metrics"
+}
+
+// Initialize registers the timing callbacks around every operation
+func (m queryMetrics) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	hooks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, h := range hooks {
+		if err := h.before("telemetry:before_"+h.operation, start); err != nil {
+			return fmt.Errorf("failed to register %s timing: %w", h.operation, err)
+		}
+
+		if err := h.after("telemetry:after_"+h.operation, m.observe(h.operation)); err != nil {
+			return fmt.Errorf("failed to register %s timing: %w", h.operation, err)
+		}
+	}
+
+	return nil
+}
+
+func start(tx *gorm.DB) {
+	tx.InstanceSet(startKey, time.Now())
+}
+
+// observe records the statement duration of operation
+func (m queryMetrics) observe(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+
+		started, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		attrs := []attribute.KeyValue{
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", tx.Statement.Table),
+		}
+
+		if err := tx.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			attrs = append(attrs, attribute.String("error.type", fmt.Sprintf("%T", err)))
+		}
+
+		m.duration.Record(tx.Statement.Context, time.Since(started).Seconds(), metric.WithAttributes(attrs...))
+	}
+}
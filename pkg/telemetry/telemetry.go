@@ -0,0 +1,147 @@
+// Package telemetry wires OpenTelemetry into the dependency clients. GORM,
+// Redis and AMQP calls get spans and latency/error metrics without
+// instrumenting each call site. Metrics are exported through the Prometheus
+// registry, so they are served on /metrics next to the application
+// metrics; spans are exported over OTLP when an endpoint is configured.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
+)
+
+// INSTRUMENTATION_NAME names the tracer and meter of the AMQP instrumentation
+const INSTRUMENTATION_NAME = "github.com/Koyo-os/form-service/pkg/telemetry"
+
+type (
+	// Options configures telemetry
+	Options struct {
+		ServiceName  string // Reported as service.name
+		OTLPEndpoint string // host:port of an OTLP/HTTP trace collector, empty records no spans
+		Insecure     bool   // Export spans over plain HTTP
+	}
+
+	// Telemetry owns the OpenTelemetry providers. It installs them globally,
+	// so the AMQP publisher and consumer pick them up.
+	Telemetry struct {
+		meters  *sdkmetric.MeterProvider
+		tracers *sdktrace.TracerProvider
+	}
+)
+
+// Init creates the providers, registers the metric exporter with the
+// default Prometheus registry and installs the providers globally
+func Init(ctx context.Context, opts Options) (*Telemetry, error) {
+	res := resource.NewSchemaless(attribute.String("service.name", opts.ServiceName))
+
+	exporter, err := otelprom.New(
+		otelprom.WithRegisterer(prometheus.DefaultRegisterer),
+		otelprom.WithNamespace(metrics.NAMESPACE),
+		otelprom.WithoutScopeInfo(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+
+	t := &Telemetry{
+		meters: sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(exporter),
+			sdkmetric.WithResource(res),
+		),
+	}
+
+	traceOpts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	if opts.OTLPEndpoint != "" {
+		exportOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.Insecure {
+			exportOpts = append(exportOpts, otlptracehttp.WithInsecure())
+		}
+
+		spans, err := otlptracehttp.New(ctx, exportOpts...)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to create span exporter: %w", err), t.meters.Shutdown(ctx))
+		}
+
+		traceOpts = append(traceOpts, sdktrace.WithBatcher(spans))
+	}
+
+	t.tracers = sdktrace.NewTracerProvider(traceOpts...)
+
+	otel.SetMeterProvider(t.meters)
+	otel.SetTracerProvider(t.tracers)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return t, nil
+}
+
+// InstrumentGorm records a span and query metrics for every statement run
+// through db. Query parameters are left out of the spans, they may carry
+// respondent data.
+func (t *Telemetry) InstrumentGorm(db *gorm.DB) error {
+	if err := db.Use(tracing.NewPlugin(
+		tracing.WithTracerProvider(t.tracers),
+		tracing.WithoutQueryVariables(),
+	)); err != nil {
+		return fmt.Errorf("failed to instrument database: %w", err)
+	}
+
+	duration, err := t.meters.Meter(INSTRUMENTATION_NAME).Float64Histogram(
+		"db.client.operation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of database statements."),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create query histogram: %w", err)
+	}
+
+	if err := db.Use(queryMetrics{duration: duration}); err != nil {
+		return fmt.Errorf("failed to instrument database metrics: %w", err)
+	}
+
+	return nil
+}
+
+// InstrumentRedis records a span and command metrics for every command
+// sent through client
+func (t *Telemetry) InstrumentRedis(client *redis.Client) error {
+	if err := redisotel.InstrumentTracing(client,
+		redisotel.WithTracerProvider(t.tracers),
+		redisotel.WithDBStatement(false),
+	); err != nil {
+		return fmt.Errorf("failed to instrument redis tracing: %w", err)
+	}
+
+	if err := redisotel.InstrumentMetrics(client, redisotel.WithMeterProvider(t.meters)); err != nil {
+		return fmt.Errorf("failed to instrument redis metrics: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes pending spans and stops the providers
+func (t *Telemetry) Close() error {
+	ctx := context.Background()
+
+	return errors.Join(t.tracers.Shutdown(ctx), t.meters.Shutdown(ctx))
+}
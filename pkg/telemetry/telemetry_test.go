@@ -0,0 +1,104 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTelemetry returns telemetry recording into in-memory readers
+func setupTelemetry() (*Telemetry, *sdkmetric.ManualReader, *tracetest.InMemoryExporter) {
+	reader := sdkmetric.NewManualReader()
+	spans := tracetest.NewInMemoryExporter()
+
+	return &Telemetry{
+		meters:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		tracers: sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans)),
+	}, reader, spans
+}
+
+// histogram finds the histogram called name among the collected metrics
+func histogram(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Histogram[float64] {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m.Data.(metricdata.Histogram[float64])
+			}
+		}
+	}
+
+	t.Fatalf("metric %s not recorded", name)
+	return metricdata.Histogram[float64]{}
+}
+
+func TestTelemetry_InstrumentGorm(t *testing.T) {
+	tel, reader, spans := setupTelemetry()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, tel.InstrumentGorm(db))
+
+	type item struct {
+		ID   uint
+		Name string
+	}
+	require.NoError(t, db.AutoMigrate(&item{}))
+	require.NoError(t, db.Create(&item{Name: "a"}).Error)
+
+	var found item
+	require.NoError(t, db.First(&found).Error)
+
+	ops := map[string]bool{}
+	for _, point := range histogram(t, reader, "db.client.operation.duration").DataPoints {
+		op, _ := point.Attributes.Value("db.operation.name")
+		ops[op.AsString()] = true
+	}
+
+	assert.True(t, ops["create"])
+	assert.True(t, ops["query"])
+	assert.NotEmpty(t, spans.GetSpans())
+}
+
+func TestStartAMQP(t *testing.T) {
+	tracers, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tracers)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	tel, _, spans := setupTelemetry()
+	otel.SetTracerProvider(tel.tracers)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	headers := amqp.Table{}
+	_, end := StartAMQP(context.Background(), AMQP_PUBLISH, "output", "form.updated", headers)
+	end(nil)
+
+	require.Contains(t, headers, "traceparent")
+
+	_, end = StartAMQP(context.Background(), AMQP_RECEIVE, "output", "form.updated", headers)
+	end(errors.New("output channel is full"))
+
+	recorded := spans.GetSpans()
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "publish output", recorded[0].Name)
+	assert.Equal(t, recorded[0].SpanContext.TraceID(), recorded[1].SpanContext.TraceID(), "receipt continues the publisher trace")
+	assert.Len(t, recorded[1].Events, 1, "error recorded")
+}
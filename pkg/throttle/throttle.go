@@ -0,0 +1,171 @@
+// Package throttle pauses event consumption while a dependency the handlers
+// need is unhealthy, so events aren't pulled just to fail and be retried or
+// dead-lettered, and resumes it once the dependency recovers.
+//
+// The consumer acknowledges deliveries automatically, which makes the broker
+// ignore prefetch limits, so throttling pauses consumption instead of
+// lowering the prefetch count.
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/health"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// PAUSE_REASON is the reason consumption is paused for while throttled
+	PAUSE_REASON = "dependency_unhealthy"
+
+	// Defaults used when the options leave them empty
+	DEFAULT_INTERVAL           = 5 * time.Second
+	DEFAULT_FAILURE_THRESHOLD  = 3
+	DEFAULT_RECOVERY_THRESHOLD = 2
+)
+
+type (
+	// Consumer is the part of the event consumer a Throttle controls
+	Consumer interface {
+		PauseFor(queue, reason string) error
+		ResumeFor(queue, reason string) error
+	}
+
+	// Options configures a Throttle
+	Options struct {
+		Interval          time.Duration // Time between dependency checks
+		FailureThreshold  int           // Consecutive failed checks before pausing
+		RecoveryThreshold int           // Consecutive passed checks before resuming
+	}
+
+	// Throttle watches a dependency and pauses queues while it is unhealthy
+	Throttle struct {
+		name       string
+		dependency health.Healther
+		consumer   Consumer
+		queues     []string
+		opts       Options
+		logger     *logger.Logger
+
+		mu        sync.RWMutex
+		throttled bool
+		failures  int
+		successes int
+	}
+)
+
+// New creates a throttle pausing queues of consumer while dependency,
+// identified by name in logs and metrics, is unhealthy
+func New(logger *logger.Logger, name string, dependency health.Healther, consumer Consumer, queues []string, opts Options) *Throttle {
+	if opts.Interval <= 0 {
+		opts.Interval = DEFAULT_INTERVAL
+	}
+
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = DEFAULT_FAILURE_THRESHOLD
+	}
+
+	if opts.RecoveryThreshold <= 0 {
+		opts.RecoveryThreshold = DEFAULT_RECOVERY_THRESHOLD
+	}
+
+	return &Throttle{
+		name:       name,
+		dependency: dependency,
+		consumer:   consumer,
+		queues:     queues,
+		opts:       opts,
+		logger:     logger,
+	}
+}
+
+// Run checks the dependency every interval until ctx is done
+func (t *Throttle) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Check(ctx)
+		}
+	}
+}
+
+// Check runs a single dependency check and pauses or resumes the queues
+// once the failure or recovery threshold is reached
+func (t *Throttle) Check(ctx context.Context) {
+	healthy := t.healthy(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if healthy {
+		t.failures = 0
+		t.successes++
+
+		if t.throttled && t.successes >= t.opts.RecoveryThreshold {
+			t.throttled = false
+			t.logger.Info("dependency recovered, resuming consumption", zap.String("dependency", t.name))
+			t.apply(t.consumer.ResumeFor)
+		}
+		return
+	}
+
+	t.successes = 0
+	t.failures++
+
+	if !t.throttled && t.failures >= t.opts.FailureThreshold {
+		t.throttled = true
+		t.logger.Warn("dependency unhealthy, pausing consumption",
+			zap.String("dependency", t.name),
+			zap.Int("failed_checks", t.failures))
+		t.apply(t.consumer.PauseFor)
+	}
+}
+
+// Throttled reports whether consumption is currently paused by the throttle
+func (t *Throttle) Throttled() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.throttled
+}
+
+// healthy checks the dependency, bounded by the check interval
+func (t *Throttle) healthy(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, t.opts.Interval)
+	defer cancel()
+
+	if ch, ok := t.dependency.(health.ContextHealther); ok {
+		return ch.IsHealthyContext(ctx)
+	}
+
+	return t.dependency.IsHealthy()
+}
+
+// apply pauses or resumes every queue and updates the throttled gauge
+// Callers must hold t.mu
+func (t *Throttle) apply(change func(queue, reason string) error) {
+	value := 0.0
+	if t.throttled {
+		value = 1
+	}
+
+	for _, queue := range t.queues {
+		if err := change(queue, PAUSE_REASON); err != nil {
+			t.logger.Error("failed to change consumption",
+				zap.String("dependency", t.name),
+				zap.String("queue", queue),
+				zap.Error(err))
+		}
+
+		metrics.ConsumerThrottled.WithLabelValues(queue, t.name).Set(value)
+	}
+}
@@ -0,0 +1,75 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeDependency struct {
+	healthy bool
+}
+
+func (f *fakeDependency) IsHealthy() bool { return f.healthy }
+
+type fakeConsumer struct {
+	mu     sync.Mutex
+	paused map[string]bool
+}
+
+func (f *fakeConsumer) PauseFor(queue, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.paused[queue+"/"+reason] = true
+	return nil
+}
+
+func (f *fakeConsumer) ResumeFor(queue, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.paused, queue+"/"+reason)
+	return nil
+}
+
+func TestThrottle_Check(t *testing.T) {
+	dependency := &fakeDependency{healthy: true}
+	consumer := &fakeConsumer{paused: make(map[string]bool)}
+
+	throttle := New(&logger.Logger{Logger: zap.NewNop()}, "database", dependency, consumer,
+		[]string{"request", "request.v1"}, Options{FailureThreshold: 2, RecoveryThreshold: 2})
+
+	ctx := context.Background()
+
+	throttle.Check(ctx)
+	assert.False(t, throttle.Throttled())
+
+	dependency.healthy = false
+
+	throttle.Check(ctx)
+	assert.False(t, throttle.Throttled(), "a single failure doesn't throttle")
+
+	throttle.Check(ctx)
+	assert.True(t, throttle.Throttled())
+	assert.Equal(t, map[string]bool{"request/" + PAUSE_REASON: true, "request.v1/" + PAUSE_REASON: true}, consumer.paused)
+
+	dependency.healthy = true
+
+	throttle.Check(ctx)
+	assert.True(t, throttle.Throttled(), "recovery needs consecutive passed checks")
+
+	dependency.healthy = false
+	throttle.Check(ctx)
+	dependency.healthy = true
+	throttle.Check(ctx)
+	assert.True(t, throttle.Throttled(), "a failure resets the recovery count")
+
+	throttle.Check(ctx)
+	assert.False(t, throttle.Throttled())
+	assert.Empty(t, consumer.paused)
+}
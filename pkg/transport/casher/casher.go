@@ -3,9 +3,16 @@ package casher
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/metrics"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -14,20 +21,38 @@ import (
 // It prefixes all form keys with "form:" to create a namespace
 const FORM_KEY_TEMPLATE = "form:%s"
 
+// DEFAULT_HEALTH_TIMEOUT bounds a health ping when the caller context has no deadline
+const DEFAULT_HEALTH_TIMEOUT = 2 * time.Second
+
 // Casher handles caching operations using Redis as the backend
 // Note: The name could be "Cacher" for better spelling, but maintaining existing naming
 type Casher struct {
 	client *redis.Client  // Redis client for storage operations
 	logger *logger.Logger // Logger for error tracking and debugging
+
+	mu          sync.RWMutex  // Guards the health fields below
+	lastHealthy time.Time     // Time of the last successful ping
+	latency     time.Duration // Round-trip time of the last ping
+
+	quota     QuotaOptions // Soft memory quota, see SetQuota
+	overQuota atomic.Bool  // Whether the last measured usage exceeded the quota
 }
 
+// RemoveFromCash deletes the cached data for the specified key
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - key: Unique identifier for the form data to remove
+//
+// Returns an error if the Redis operation fails. Removing a key that
+// is not cached is not an error
 func (c *Casher) RemoveFromCash(ctx context.Context, key string) error {
-	res := c.client.Del(ctx, key)
+	res := c.client.Del(ctx, fmt.Sprintf(FORM_KEY_TEMPLATE, key))
 
-	if res.Err() != nil {
+	if err := res.Err(); err != nil {
 		c.logger.Error("error delete from redis",
 			zap.String("key", key),
-			zap.Error(res.Err()))
+			zap.Error(err))
+		return err
 	}
 
 	return nil
@@ -46,21 +71,98 @@ func (c *Casher) Close() error {
 	return c.client.Close()
 }
 
+// IsHealthy reports whether Redis answers a ping within DEFAULT_HEALTH_TIMEOUT
 func (c *Casher) IsHealthy() bool {
-	return c.client.Ping(context.Background()).Err() == nil
+	return c.IsHealthyContext(context.Background())
+}
+
+// IsHealthyContext pings Redis bounded by the caller context. If the context
+// carries no deadline, DEFAULT_HEALTH_TIMEOUT is applied so the check can't hang
+func (c *Casher) IsHealthyContext(ctx context.Context) bool {
+	_, err := c.Ping(ctx)
+	return err == nil
+}
+
+// Ping sends a PING to Redis and returns its round-trip latency
+// Successful pings update the values reported by LastHealthy and Latency
+func (c *Casher) Ping(ctx context.Context) (time.Duration, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DEFAULT_HEALTH_TIMEOUT)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := c.client.Ping(ctx).Err()
+	latency := time.Since(start)
+
+	if err != nil {
+		c.logger.Error("redis ping failed",
+			zap.Duration("latency", latency),
+			zap.Error(err))
+		return latency, err
+	}
+
+	c.mu.Lock()
+	c.lastHealthy = time.Now()
+	c.latency = latency
+	c.mu.Unlock()
+
+	return latency, nil
+}
+
+// LastHealthy returns the time of the last successful ping (zero if none yet)
+func (c *Casher) LastHealthy() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lastHealthy
+}
+
+// Latency returns the round-trip time measured by the last successful ping
+func (c *Casher) Latency() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.latency
 }
 
 // AddToCash stores a payload in Redis using the provided key
 // The payload is stored with no expiration time (persistence until explicit deletion)
+// While over the memory quota it is stored with the reduced TTL instead, or
+// not at all if it's too large; a copy cached before is removed then, as it
+// would be stale
 // Parameters:
 //   - ctx: Context for cancellation and timeouts
 //   - key: Unique identifier for the form data
-//   - payload: Raw bytes of the data to be cached
+//   - payload: Raw bytes or a string, other values are JSON encoded
 //
-// Returns an error if the Redis operation fails
+// Returns an error if the payload can't be encoded or the Redis operation fails
 func (c *Casher) AddToCash(ctx context.Context, key string, payload any) error {
+	data, err := encode(payload)
+	if err != nil {
+		c.logger.Error("failed to encode payload to cash",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	var ttl time.Duration
+
+	if c.overQuota.Load() {
+		if limit := c.quota.MaxEntryBytes; limit > 0 && int64(len(data)) > limit {
+			metrics.CacheWritesDegraded.WithLabelValues(QUOTA_SKIPPED).Inc()
+			return c.RemoveFromCash(ctx, key)
+		}
+
+		if ttl = c.quota.ReducedTTL; ttl > 0 {
+			metrics.CacheWritesDegraded.WithLabelValues(QUOTA_SHORT_TTL).Inc()
+		}
+	}
+
 	// Format the key using the template and store the payload
-	res := c.client.Set(ctx, fmt.Sprintf(FORM_KEY_TEMPLATE, key), payload, 0)
+	res := c.client.Set(ctx, fmt.Sprintf(FORM_KEY_TEMPLATE, key), data, ttl)
 
 	if err := res.Err(); err != nil {
 		c.logger.Error("failed to cash payload with",
@@ -73,6 +175,42 @@ func (c *Casher) AddToCash(ctx context.Context, key string, payload any) error {
 	return nil
 }
 
+// encode returns the bytes payload is cached as
+func encode(payload any) ([]byte, error) {
+	switch p := payload.(type) {
+	case []byte:
+		return p, nil
+	case string:
+		return []byte(p), nil
+	default:
+		return json.Marshal(payload)
+	}
+}
+
+// AddToCashIfAbsent stores a payload only if the key is not cached yet
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - key: Unique identifier for the data
+//   - payload: Data to be cached
+//   - ttl: Expiration of the key, 0 keeps it until explicit deletion
+//
+// Returns:
+//   - bool: true if the payload was stored, false if the key already existed
+//   - error: Error if the Redis operation fails
+func (c *Casher) AddToCashIfAbsent(ctx context.Context, key string, payload any, ttl time.Duration) (bool, error) {
+	res := c.client.SetNX(ctx, fmt.Sprintf(FORM_KEY_TEMPLATE, key), payload, ttl)
+
+	if err := res.Err(); err != nil {
+		c.logger.Error("failed to cash payload if absent",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+		return false, err
+	}
+
+	return res.Val(), nil
+}
+
 // GetCashFor retrieves cached data from Redis for the specified key
 // Parameters:
 //   - ctx: Context for cancellation and timeouts
@@ -89,6 +227,11 @@ func (c *Casher) GetCashFor(ctx context.Context, key string) ([]byte, error) {
 	// Attempt to retrieve the data from Redis
 	res := c.client.Get(ctx, fmt.Sprintf(FORM_KEY_TEMPLATE, key))
 	if err := res.Err(); err != nil {
+		// Misses are expected of readers going through the cache first
+		if errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+
 		c.logger.Error("error get cash",
 			zap.String("key", key),
 			zap.Error(err),
@@ -108,3 +251,86 @@ func (c *Casher) GetCashFor(ctx context.Context, key string) ([]byte, error) {
 
 	return data, nil
 }
+
+// InspectCash returns the cached data for the specified key along with its
+// remaining TTL, to debug stale entries
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - key: Unique identifier for the cached form data
+//
+// Returns:
+//   - []byte: Cached data, nil if the key is not cached
+//   - time.Duration: Remaining TTL, negative for keys without expiry
+//   - error: Error if the Redis operations fail
+func (c *Casher) InspectCash(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	pipe := c.client.Pipeline()
+
+	get := pipe.Get(ctx, fmt.Sprintf(FORM_KEY_TEMPLATE, key))
+	ttl := pipe.PTTL(ctx, fmt.Sprintf(FORM_KEY_TEMPLATE, key))
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		c.logger.Error("error inspect cash",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+		return nil, 0, err
+	}
+
+	if errors.Is(get.Err(), redis.Nil) {
+		return nil, 0, nil
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return data, ttl.Val(), nil
+}
+
+// SampleForms returns a batch of cached forms, walking the keyspace with a
+// cursor so successive calls eventually visit every form
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - cursor: Cursor returned by the previous call, 0 to start over
+//   - count: Keys scanned per call, fewer forms may be returned
+//
+// Returns:
+//   - map[string][]byte: Cached forms by form ID
+//   - uint64: Cursor of the next call, 0 once the walk is complete
+//   - error: Error if the Redis operations fail
+func (c *Casher) SampleForms(ctx context.Context, cursor uint64, count int64) (map[string][]byte, uint64, error) {
+	keys, next, err := c.client.Scan(ctx, cursor, USAGE_SCAN_PATTERN, count).Result()
+	if err != nil {
+		c.logger.Error("error scan cashed forms", zap.Error(err))
+		return nil, 0, err
+	}
+
+	formKeys := keys[:0]
+	for _, key := range keys {
+		if namespace(key) == FORM_NAMESPACE {
+			formKeys = append(formKeys, key)
+		}
+	}
+
+	forms := make(map[string][]byte, len(formKeys))
+	if len(formKeys) == 0 {
+		return forms, next, nil
+	}
+
+	values, err := c.client.MGet(ctx, formKeys...).Result()
+	if err != nil {
+		c.logger.Error("error get sampled forms", zap.Error(err))
+		return nil, 0, err
+	}
+
+	prefix := fmt.Sprintf(FORM_KEY_TEMPLATE, "")
+	for i, value := range values {
+		// Forms expired since the scan are nil
+		if data, ok := value.(string); ok {
+			forms[strings.TrimPrefix(formKeys[i], prefix)] = []byte(data)
+		}
+	}
+
+	return forms, next, nil
+}
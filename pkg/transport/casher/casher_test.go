@@ -0,0 +1,187 @@
+package casher
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// setupCasher starts an in-memory Redis and returns a Casher bound to it
+func setupCasher(t *testing.T) (*Casher, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	return Init(client, &logger.Logger{Logger: zap.NewNop()}), server
+}
+
+func TestCasher_RemoveFromCash(t *testing.T) {
+	t.Run("removes the templated key", func(t *testing.T) {
+		casher, server := setupCasher(t)
+		ctx := context.Background()
+
+		require.NoError(t, casher.AddToCash(ctx, "form-id", "payload"))
+		require.True(t, server.Exists(fmt.Sprintf(FORM_KEY_TEMPLATE, "form-id")))
+
+		err := casher.RemoveFromCash(ctx, "form-id")
+
+		assert.NoError(t, err)
+		assert.False(t, server.Exists(fmt.Sprintf(FORM_KEY_TEMPLATE, "form-id")))
+	})
+
+	t.Run("does not touch the raw key", func(t *testing.T) {
+		casher, server := setupCasher(t)
+
+		require.NoError(t, server.Set("form-id", "unrelated"))
+
+		err := casher.RemoveFromCash(context.Background(), "form-id")
+
+		assert.NoError(t, err)
+		assert.True(t, server.Exists("form-id"))
+	})
+
+	t.Run("missing key is not an error", func(t *testing.T) {
+		casher, _ := setupCasher(t)
+
+		assert.NoError(t, casher.RemoveFromCash(context.Background(), "missing"))
+	})
+
+	t.Run("returns redis errors", func(t *testing.T) {
+		casher, server := setupCasher(t)
+
+		server.SetError("READONLY You can't write against a read only replica")
+
+		err := casher.RemoveFromCash(context.Background(), "form-id")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCasher_AddAndGet(t *testing.T) {
+	t.Run("stores payload under the form key template", func(t *testing.T) {
+		casher, server := setupCasher(t)
+
+		err := casher.AddToCash(context.Background(), "form-id", "payload")
+
+		require.NoError(t, err)
+		value, err := server.Get("form:form-id")
+		require.NoError(t, err)
+		assert.Equal(t, "payload", value)
+		assert.Zero(t, server.TTL("form:form-id"))
+	})
+
+	t.Run("reads back cached bytes", func(t *testing.T) {
+		casher, _ := setupCasher(t)
+		ctx := context.Background()
+
+		require.NoError(t, casher.AddToCash(ctx, "form-id", []byte(`{"id":"form-id"}`)))
+
+		data, err := casher.GetCashFor(ctx, "form-id")
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":"form-id"}`, string(data))
+	})
+
+	t.Run("missing key returns redis.Nil", func(t *testing.T) {
+		casher, _ := setupCasher(t)
+
+		data, err := casher.GetCashFor(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, redis.Nil)
+		assert.Nil(t, data)
+	})
+
+	t.Run("returns write errors", func(t *testing.T) {
+		casher, server := setupCasher(t)
+		server.SetError("OOM command not allowed")
+
+		assert.Error(t, casher.AddToCash(context.Background(), "form-id", "payload"))
+	})
+}
+
+func TestCasher_AddToCashIfAbsent(t *testing.T) {
+	casher, server := setupCasher(t)
+	ctx := context.Background()
+
+	stored, err := casher.AddToCashIfAbsent(ctx, "progress", "first", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, stored)
+
+	stored, err = casher.AddToCashIfAbsent(ctx, "progress", "second", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, stored)
+
+	data, err := casher.GetCashFor(ctx, "progress")
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+
+	server.FastForward(time.Minute)
+	assert.False(t, server.Exists(fmt.Sprintf(FORM_KEY_TEMPLATE, "progress")))
+}
+
+func TestCasher_InspectCash(t *testing.T) {
+	t.Run("returns the payload and remaining TTL", func(t *testing.T) {
+		casher, server := setupCasher(t)
+		ctx := context.Background()
+
+		_, err := casher.AddToCashIfAbsent(ctx, "form-id", "payload", time.Minute)
+		require.NoError(t, err)
+		server.FastForward(20 * time.Second)
+
+		data, ttl, err := casher.InspectCash(ctx, "form-id")
+
+		require.NoError(t, err)
+		assert.Equal(t, "payload", string(data))
+		assert.Equal(t, 40*time.Second, ttl)
+	})
+
+	t.Run("reports keys without expiry with a negative TTL", func(t *testing.T) {
+		casher, _ := setupCasher(t)
+		ctx := context.Background()
+
+		require.NoError(t, casher.AddToCash(ctx, "form-id", "payload"))
+
+		_, ttl, err := casher.InspectCash(ctx, "form-id")
+
+		require.NoError(t, err)
+		assert.Negative(t, ttl)
+	})
+
+	t.Run("missing key is not an error", func(t *testing.T) {
+		casher, _ := setupCasher(t)
+
+		data, _, err := casher.InspectCash(context.Background(), "missing")
+
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+	})
+}
+
+func TestCasher_Health(t *testing.T) {
+	t.Run("healthy server records ping time", func(t *testing.T) {
+		casher, _ := setupCasher(t)
+
+		assert.True(t, casher.IsHealthy())
+		assert.False(t, casher.LastHealthy().IsZero())
+	})
+
+	t.Run("unreachable server is unhealthy", func(t *testing.T) {
+		casher, server := setupCasher(t)
+		server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		assert.False(t, casher.IsHealthyContext(ctx))
+		assert.True(t, casher.LastHealthy().IsZero())
+	})
+}
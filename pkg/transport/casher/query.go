@@ -0,0 +1,79 @@
+package casher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// QUERY_KEY_TEMPLATE prefixes cached query results, kept apart from the
+// cached forms so they don't count against the memory quota
+const QUERY_KEY_TEMPLATE = "query:%s"
+
+// QUERY_GENERATION_TEMPLATE is the key of the generation of a query scope.
+// Query keys embed the generation, so bumping it invalidates every result
+// of the scope at once; the old results expire with their TTL.
+const QUERY_GENERATION_TEMPLATE = "query:gen:%s"
+
+// GetQuery retrieves a cached query result
+// Returns nil if the result is not cached
+func (c *Casher) GetQuery(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.client.Get(ctx, fmt.Sprintf(QUERY_KEY_TEMPLATE, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		c.logger.Error("error get cached query",
+			zap.String("key", key),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// SetQuery caches a query result for ttl
+func (c *Casher) SetQuery(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, fmt.Sprintf(QUERY_KEY_TEMPLATE, key), data, ttl).Err(); err != nil {
+		c.logger.Error("error cache query",
+			zap.String("key", key),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// QueryGeneration returns the current generation of a query scope, 0 until
+// it is first invalidated
+func (c *Casher) QueryGeneration(ctx context.Context, scope string) (int64, error) {
+	gen, err := c.client.Get(ctx, fmt.Sprintf(QUERY_GENERATION_TEMPLATE, scope)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		c.logger.Error("error get query generation",
+			zap.String("scope", scope),
+			zap.Error(err))
+		return 0, err
+	}
+
+	return gen, nil
+}
+
+// InvalidateQueries bumps the generation of a query scope, invalidating
+// every result cached for it
+func (c *Casher) InvalidateQueries(ctx context.Context, scope string) error {
+	if err := c.client.Incr(ctx, fmt.Sprintf(QUERY_GENERATION_TEMPLATE, scope)).Err(); err != nil {
+		c.logger.Error("error invalidate queries",
+			zap.String("scope", scope),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
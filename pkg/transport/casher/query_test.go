@@ -0,0 +1,41 @@
+package casher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCasher_Queries(t *testing.T) {
+	casher, server := setupCasher(t)
+	ctx := context.Background()
+
+	data, err := casher.GetQuery(ctx, "forms:0:page")
+	require.NoError(t, err)
+	assert.Nil(t, data, "misses are not an error")
+
+	require.NoError(t, casher.SetQuery(ctx, "forms:0:page", []byte(`{"total":1}`), time.Minute))
+
+	data, err = casher.GetQuery(ctx, "forms:0:page")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total":1}`, string(data))
+	assert.True(t, server.Exists("query:forms:0:page"))
+
+	gen, err := casher.QueryGeneration(ctx, "forms")
+	require.NoError(t, err)
+	assert.Zero(t, gen)
+
+	require.NoError(t, casher.InvalidateQueries(ctx, "forms"))
+
+	gen, err = casher.QueryGeneration(ctx, "forms")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), gen)
+
+	server.FastForward(time.Minute)
+	data, err = casher.GetQuery(ctx, "forms:0:page")
+	require.NoError(t, err)
+	assert.Nil(t, data, "results expire")
+}
@@ -0,0 +1,153 @@
+package casher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// USAGE_SCAN_PATTERN matches every key written by the Casher
+const USAGE_SCAN_PATTERN = "form:*"
+
+// USAGE_SCAN_BATCH is the number of keys sized per round trip
+const USAGE_SCAN_BATCH = 500
+
+// FORM_NAMESPACE is the namespace of cached forms. Other keys, like invite
+// redemptions and progress markers, are grouped by the first segment of
+// their key that isn't an ID.
+const FORM_NAMESPACE = "form"
+
+// Actions taken on writes while over the memory quota
+const (
+	QUOTA_SHORT_TTL = "short_ttl" // Written with the reduced TTL
+	QUOTA_SKIPPED   = "skipped"   // Too large, not cached
+)
+
+// QuotaOptions configures the soft memory quota of the cache. Above it,
+// forms are cached with a shorter TTL and large forms not at all, so the
+// cache doesn't push other data sharing the Redis instance into eviction.
+type QuotaOptions struct {
+	MaxBytes      int64         // Cached bytes above which writes are degraded, 0 disables the quota
+	ReducedTTL    time.Duration // TTL of forms cached while over the quota, 0 keeps them until deleted
+	MaxEntryBytes int64         // Forms larger than this aren't cached while over the quota, 0 caches any size
+}
+
+// SetQuota enables the soft memory quota. Whether the cache is over it is
+// decided by MeasureUsage.
+func (c *Casher) SetQuota(opts QuotaOptions) {
+	c.quota = opts
+}
+
+// OverQuota reports whether the last measured usage exceeded the quota
+func (c *Casher) OverQuota() bool {
+	return c.overQuota.Load()
+}
+
+// MeasureUsage sums the approximate bytes cached per namespace, the length
+// of every key and its value, and exports them as metrics. Keys are scanned,
+// so the result covers every instance sharing the Redis database.
+// Returns:
+//   - map[string]int64: Bytes cached per namespace
+//   - error: Error if Redis can't be scanned
+func (c *Casher) MeasureUsage(ctx context.Context) (map[string]int64, error) {
+	usage := make(map[string]int64)
+
+	keys := make([]string, 0, USAGE_SCAN_BATCH)
+	iter := c.client.Scan(ctx, 0, USAGE_SCAN_PATTERN, USAGE_SCAN_BATCH).Iterator()
+
+	for iter.Next(ctx) {
+		if keys = append(keys, iter.Val()); len(keys) == USAGE_SCAN_BATCH {
+			if err := c.sizeKeys(ctx, keys, usage); err != nil {
+				return nil, err
+			}
+			keys = keys[:0]
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		c.logger.Error("error scan cache usage", zap.Error(err))
+		return nil, fmt.Errorf("failed to scan cached keys: %w", err)
+	}
+
+	if err := c.sizeKeys(ctx, keys, usage); err != nil {
+		return nil, err
+	}
+
+	var total int64
+
+	metrics.CacheBytes.Reset()
+	for namespace, bytes := range usage {
+		metrics.CacheBytes.WithLabelValues(namespace).Set(float64(bytes))
+		total += bytes
+	}
+
+	c.updateQuota(total)
+
+	return usage, nil
+}
+
+// sizeKeys adds the size of keys to usage
+func (c *Casher) sizeKeys(ctx context.Context, keys []string, usage map[string]int64) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+
+	sizes := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		sizes[i] = pipe.StrLen(ctx, key)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("error size cached keys", zap.Error(err))
+		return fmt.Errorf("failed to size cached keys: %w", err)
+	}
+
+	// Keys expiring meanwhile count as empty
+	for i, key := range keys {
+		usage[namespace(key)] += int64(len(key)) + sizes[i].Val()
+	}
+
+	return nil
+}
+
+// updateQuota records whether total exceeds the quota, logging changes
+func (c *Casher) updateQuota(total int64) {
+	over := c.quota.MaxBytes > 0 && total > c.quota.MaxBytes
+	if c.overQuota.Swap(over) == over {
+		return
+	}
+
+	if over {
+		metrics.CacheOverQuota.Set(1)
+		c.logger.Warn("cache is over its memory quota, degrading writes",
+			zap.Int64("bytes", total),
+			zap.Int64("max_bytes", c.quota.MaxBytes))
+		return
+	}
+
+	metrics.CacheOverQuota.Set(0)
+	c.logger.Info("cache is back under its memory quota",
+		zap.Int64("bytes", total),
+		zap.Int64("max_bytes", c.quota.MaxBytes))
+}
+
+// namespace returns the namespace of a cached key
+func namespace(key string) string {
+	rest, _ := strings.CutPrefix(key, fmt.Sprintf(FORM_KEY_TEMPLATE, ""))
+
+	for _, segment := range strings.Split(rest, ":") {
+		if _, err := uuid.Parse(segment); err != nil {
+			return segment
+		}
+	}
+
+	return FORM_NAMESPACE
+}
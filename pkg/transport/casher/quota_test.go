@@ -0,0 +1,95 @@
+package casher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCasher_MeasureUsage(t *testing.T) {
+	casher, server := setupCasher(t)
+	ctx := context.Background()
+	formID := uuid.New().String()
+
+	require.NoError(t, casher.AddToCash(ctx, formID, "0123456789"))
+	_, err := casher.AddToCashIfAbsent(ctx, "invite:"+uuid.New().String()+":redeemed", "1", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, server.Set("ratelimit:other", strings.Repeat("x", 100)))
+
+	usage, err := casher.MeasureUsage(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{
+		FORM_NAMESPACE: int64(len(fmt.Sprintf(FORM_KEY_TEMPLATE, formID)) + 10),
+		"invite":       int64(len("form:invite::redeemed") + 36 + 1),
+	}, usage, "keys of other services are left out")
+	assert.False(t, casher.OverQuota(), "no quota set")
+}
+
+func TestCasher_Quota(t *testing.T) {
+	casher, server := setupCasher(t)
+	ctx := context.Background()
+	casher.SetQuota(QuotaOptions{MaxBytes: 50, ReducedTTL: time.Minute, MaxEntryBytes: 20})
+
+	require.NoError(t, casher.AddToCash(ctx, "large", strings.Repeat("x", 45)))
+	_, err := casher.MeasureUsage(ctx)
+	require.NoError(t, err)
+	require.True(t, casher.OverQuota())
+
+	t.Run("caches with the reduced TTL", func(t *testing.T) {
+		require.NoError(t, casher.AddToCash(ctx, "small", "tiny"))
+		assert.Equal(t, time.Minute, server.TTL(fmt.Sprintf(FORM_KEY_TEMPLATE, "small")))
+	})
+
+	t.Run("drops large values instead of caching them", func(t *testing.T) {
+		require.NoError(t, casher.AddToCash(ctx, "large", strings.Repeat("y", 40)))
+		assert.False(t, server.Exists(fmt.Sprintf(FORM_KEY_TEMPLATE, "large")), "stale copy removed")
+	})
+
+	t.Run("recovers below the quota", func(t *testing.T) {
+		_, err := casher.MeasureUsage(ctx)
+		require.NoError(t, err)
+		assert.False(t, casher.OverQuota())
+
+		require.NoError(t, casher.AddToCash(ctx, "large", strings.Repeat("z", 40)))
+		assert.Zero(t, server.TTL(fmt.Sprintf(FORM_KEY_TEMPLATE, "large")))
+	})
+}
+
+func TestCasher_SampleForms(t *testing.T) {
+	casher, server := setupCasher(t)
+	ctx := context.Background()
+
+	ids := make(map[string]bool)
+	for range 5 {
+		id := uuid.New().String()
+		ids[id] = true
+		require.NoError(t, casher.AddToCash(ctx, id, `{"Title":"t"}`))
+	}
+	require.NoError(t, casher.AddToCash(ctx, uuid.New().String()+":progress:r", "1"))
+	require.NoError(t, server.Set("other", "x"))
+
+	seen := make(map[string]bool)
+	var cursor uint64
+	for {
+		forms, next, err := casher.SampleForms(ctx, cursor, 2)
+		require.NoError(t, err)
+
+		for id, data := range forms {
+			assert.JSONEq(t, `{"Title":"t"}`, string(data))
+			seen[id] = true
+		}
+
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+
+	assert.Equal(t, ids, seen, "only forms are sampled")
+}
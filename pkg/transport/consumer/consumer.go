@@ -2,14 +2,19 @@
 package consumer
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
 	"github.com/Koyo-os/form-service/internal/entity"
 	"github.com/Koyo-os/form-service/pkg/config"
 	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/Koyo-os/form-service/pkg/telemetry"
+	"github.com/Koyo-os/form-service/pkg/transport/envelope"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
 )
@@ -22,19 +27,79 @@ const (
 	// Default retry settings
 	DEFAULT_RECONNECT_DELAY = 5 * time.Second
 	DEFAULT_RETRY_ATTEMPTS  = 3
+
+	// HEARTBEAT_INTERVAL is how often an idle consumer reports activity
+	HEARTBEAT_INTERVAL = 10 * time.Second
 )
 
+type (
+	// connection is the subset of *amqp.Connection used by the consumer
+	connection interface {
+		Channel() (channel, error)
+		IsClosed() bool
+		Close() error
+	}
+
+	// channel is the subset of *amqp.Channel used by the consumer
+	channel interface {
+		ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+		QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+		QueueDeclarePassive(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+		QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+		Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+		Qos(prefetchCount, prefetchSize int, global bool) error
+		Cancel(consumer string, noWait bool) error
+		Close() error
+	}
+
+	// amqpConnection adapts *amqp.Connection to the connection interface
+	amqpConnection struct {
+		*amqp.Connection
+	}
+
+	// binding is a queue-to-exchange binding restored after reconnection
+	binding struct {
+		queue      string
+		routingKey string
+		exchange   string
+		args       amqp.Table // Header match rules of a headers exchange binding
+	}
+)
+
+func (c amqpConnection) Channel() (channel, error) {
+	ch, err := c.Connection.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	return ch, nil
+}
+
 // Consumer represents a RabbitMQ consumer client
 // It maintains connection, channel, and configuration details needed for message consumption
 type Consumer struct {
-	conn         *amqp.Connection // RabbitMQ connection instance
-	channel      *amqp.Channel    // Channel for communication with RabbitMQ
-	logger       *logger.Logger   // Logger instance for error and info logging
-	cfg          *config.Config   // Configuration settings
-	exchanges    map[string]bool  // Track declared exchanges
-	mu           sync.RWMutex     // Mutex for thread-safe operations
-	isConnected  bool             // Connection status flag
-	reconnecting bool             // Reconnection status flag
+	conn         connection                 // RabbitMQ connection instance
+	channel      channel                    // Channel for communication with RabbitMQ
+	dial         func() (connection, error) // Opens a fresh connection on reconnect
+	logger       *logger.Logger             // Logger instance for error and info logging
+	cfg          *config.Config             // Configuration settings
+	exchanges    map[string]bool            // Track declared exchanges
+	headers      map[string]bool            // Track declared headers exchanges
+	bindings     []binding                  // Track queue bindings for rebinding
+	mu           sync.RWMutex               // Mutex for thread-safe operations
+	isConnected  bool                       // Connection status flag
+	reconnecting bool                       // Reconnection status flag
+	heartbeat    func()                     // Reports loop activity, e.g. to a watchdog
+	paused       map[string]map[string]bool // Reasons each paused queue is paused for
+	resumed      chan struct{}              // Closed and replaced whenever a queue is resumed
+	cancels      int                        // Consumer cancellations issued by Pause
+	dedup        *dedup                     // Drops duplicate events while draining a legacy queue
+	loopback     string                     // Queue the published events come back on, see ConsumeLoopback
+
+	sink Sink // Durable buffer events are written to instead of the output channel
+
+	onReconnect func()                          // Called once reconnected, see OnReconnect
+	onPause     func(queue string, paused bool) // Called when an admin pause is made or withdrawn, see OnMaintenance
 }
 
 // Init creates and initializes a new Consumer instance
@@ -44,14 +109,43 @@ func Init(cfg *config.Config, logger *logger.Logger, conn *amqp.Connection) (*Co
 		return nil, fmt.Errorf("invalid parameters: cfg, logger, and conn cannot be nil")
 	}
 
+	return newConsumer(cfg, logger, amqpConnection{conn}, func() (connection, error) {
+		conn, err := amqp.Dial(cfg.Urls.Rabbitmq)
+		if err != nil {
+			return nil, err
+		}
+
+		return amqpConnection{conn}, nil
+	})
+}
+
+// newConsumer opens a channel on conn and declares the request exchange
+func newConsumer(
+	cfg *config.Config,
+	logger *logger.Logger,
+	conn connection,
+	dial func() (connection, error),
+) (*Consumer, error) {
 	consumer := &Consumer{
 		conn:        conn,
+		dial:        dial,
 		logger:      logger,
 		cfg:         cfg,
 		exchanges:   make(map[string]bool),
+		headers:     make(map[string]bool),
 		isConnected: true,
+		heartbeat:   func() {},
+		paused:      make(map[string]map[string]bool),
+		resumed:     make(chan struct{}),
 	}
 
+	if cfg.Topology.LegacyRequestQueue != "" {
+		consumer.dedup = newDedup(time.Duration(cfg.Topology.DedupWindow) * time.Second)
+	}
+
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+
 	if err := consumer.initializeChannel(); err != nil {
 		return nil, fmt.Errorf("failed to initialize channel: %w", err)
 	}
@@ -65,6 +159,7 @@ func Init(cfg *config.Config, logger *logger.Logger, conn *amqp.Connection) (*Co
 }
 
 // initializeChannel creates a new channel and sets up basic configuration
+// Callers must hold c.mu
 func (c *Consumer) initializeChannel() error {
 	channel, err := c.conn.Channel()
 	if err != nil {
@@ -73,14 +168,47 @@ func (c *Consumer) initializeChannel() error {
 	}
 
 	c.channel = channel
+
+	if c.cfg.Delivery.ManualAck && c.cfg.Delivery.Prefetch > 0 {
+		if err := channel.Qos(c.cfg.Delivery.Prefetch, 0, false); err != nil {
+			c.logger.Error("failed to set prefetch", zap.Error(err))
+			return err
+		}
+	}
+
 	return nil
 }
 
 // declareExchange declares an exchange and tracks it
+// Callers must hold c.mu
 func (c *Consumer) declareExchange(exchangeName string) error {
+	if err := c.declare(exchangeName, EXCHANGE_TYPE); err != nil {
+		return err
+	}
+
+	c.exchanges[exchangeName] = true
+
+	return nil
+}
+
+// declareHeadersExchange declares a headers exchange and tracks it
+// Callers must hold c.mu
+func (c *Consumer) declareHeadersExchange(exchangeName string) error {
+	if err := c.declare(exchangeName, amqp.ExchangeHeaders); err != nil {
+		return err
+	}
+
+	c.headers[exchangeName] = true
+
+	return nil
+}
+
+// declare declares a durable exchange of the given kind
+// Callers must hold c.mu
+func (c *Consumer) declare(exchangeName, kind string) error {
 	if err := c.channel.ExchangeDeclare(
 		exchangeName,
-		EXCHANGE_TYPE,
+		kind,
 		true,  // durable
 		false, // auto-delete
 		false, // internal
@@ -93,23 +221,69 @@ func (c *Consumer) declareExchange(exchangeName string) error {
 		return err
 	}
 
-	c.mu.Lock()
-	c.exchanges[exchangeName] = true
-	c.mu.Unlock()
-
 	return nil
 }
 
 // Subscribe sets up a queue and binds it to an exchange with the specified routing key
 // This method handles both queue declaration and queue binding operations
 func (c *Consumer) Subscribe(exchange, routingKey, queueName string) error {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	if !c.isConnected {
 		return fmt.Errorf("consumer is not connected")
 	}
 
+	if err := c.bindQueue(binding{queue: queueName, routingKey: routingKey, exchange: exchange}); err != nil {
+		return err
+	}
+
+	// Track the exchange so it survives reconnection
+	c.exchanges[exchange] = true
+
+	return nil
+}
+
+// SubscribeHeaders declares a headers exchange and binds a queue to it on
+// message headers: with matchAll messages must carry every header value,
+// otherwise any one of them
+func (c *Consumer) SubscribeHeaders(exchange, queueName string, headers map[string]string, matchAll bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.isConnected {
+		return fmt.Errorf("consumer is not connected")
+	}
+
+	if err := c.declareHeadersExchange(exchange); err != nil {
+		return fmt.Errorf("failed to declare headers exchange %s: %w", exchange, err)
+	}
+
+	args := amqp.Table{"x-match": "any"}
+	if matchAll {
+		args["x-match"] = "all"
+	}
+
+	for name, value := range headers {
+		args[name] = value
+	}
+
+	return c.bindQueue(binding{queue: queueName, exchange: exchange, args: args})
+}
+
+// bindQueue declares the queue of b, binds it and tracks the binding so it
+// survives reconnection
+// Callers must hold c.mu
+func (c *Consumer) bindQueue(b binding) error {
+	queueName, routingKey, exchange := b.queue, b.routingKey, b.exchange
+
+	args := c.queueArgs(queueName)
+	if _, ok := args["x-dead-letter-exchange"]; ok {
+		if err := c.declareDeadLetter(); err != nil {
+			return err
+		}
+	}
+
 	// Declare the queue with specified parameters
 	if _, err := c.channel.QueueDeclare(
 		queueName, // name of the queue
@@ -117,7 +291,7 @@ func (c *Consumer) Subscribe(exchange, routingKey, queueName string) error {
 		false,     // autoDelete: queue is deleted when last consumer unsubscribes
 		false,     // exclusive: queue only accessible by connection that created it
 		false,     // noWait: don't wait for server confirmation
-		nil,       // args: additional arguments
+		args,      // args: expiry and dead lettering, see queueArgs
 	); err != nil {
 		c.logger.Error("failed to declare queue", 
 			zap.String("queue", queueName), 
@@ -131,7 +305,7 @@ func (c *Consumer) Subscribe(exchange, routingKey, queueName string) error {
 		routingKey, // key used for routing messages
 		exchange,   // name of the exchange to bind to
 		false,      // noWait: wait for server confirmation
-		nil,        // args: additional arguments
+		b.args,     // args: header match rules, nil for routing key bindings
 	); err != nil {
 		c.logger.Error("failed to bind queue to exchange", 
 			zap.String("queue", queueName),
@@ -141,14 +315,40 @@ func (c *Consumer) Subscribe(exchange, routingKey, queueName string) error {
 		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", queueName, exchange, err)
 	}
 
-	// Track the exchange
-	c.mu.Lock()
-	c.exchanges[exchange] = true
-	c.mu.Unlock()
+	c.trackBinding(b)
 
 	return nil
 }
 
+// trackBinding records a binding once
+// Callers must hold c.mu
+func (c *Consumer) trackBinding(b binding) {
+	for _, existing := range c.bindings {
+		if existing.queue == b.queue && existing.routingKey == b.routingKey &&
+			existing.exchange == b.exchange && maps.Equal(existing.args, b.args) {
+			return
+		}
+	}
+
+	c.bindings = append(c.bindings, b)
+}
+
+// OnActivity registers a function called on every received message and
+// periodically while waiting, so supervisors can tell the loop is alive
+// It must be called before ConsumeMessages
+func (c *Consumer) OnActivity(heartbeat func()) {
+	c.heartbeat = heartbeat
+}
+
+// OnReconnect registers a function called on its own goroutine whenever the
+// consumer reconnected to the broker
+func (c *Consumer) OnReconnect(hook func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onReconnect = hook
+}
+
 // Close gracefully closes the consumer connection and channel
 func (c *Consumer) Close() error {
 	c.mu.Lock()
@@ -187,15 +387,62 @@ func (c *Consumer) IsHealthy() bool {
 	return c.isConnected && c.conn != nil && !c.conn.IsClosed()
 }
 
+// Queues returns the request queues the consumer reads from. The heartbeat
+// control queue isn't one of them, so throttling requests keeps heartbeats
+// flowing.
+func (c *Consumer) Queues() []string {
+	return c.queues()
+}
+
+// queues returns the queues the consumer reads from: the request queue and,
+// during a topology cutover, the legacy request queue
+func (c *Consumer) queues() []string {
+	queues := []string{c.cfg.Queue.Request}
+
+	if legacy := c.cfg.Topology.LegacyRequestQueue; legacy != "" && legacy != c.cfg.Queue.Request {
+		queues = append(queues, legacy)
+	}
+
+	return queues
+}
+
 // ConsumeMessages starts consuming messages from RabbitMQ
 // It implements automatic reconnection and message processing in an infinite loop
 // Messages are decoded into Events and sent to the provided output channel
+// A configured legacy request queue, the heartbeat control queue and the
+// loopback queue are consumed concurrently, see queues
 func (c *Consumer) ConsumeMessages(outputChan chan entity.Event) {
 	if outputChan == nil {
 		c.logger.Error("output channel cannot be nil")
 		return
 	}
 
+	queues := c.queues()
+
+	for _, queue := range queues[1:] {
+		c.logger.Info("consuming legacy request queue", zap.String("queue", queue))
+		go c.consumeQueue(queue, outputChan)
+	}
+
+	if control := c.cfg.Heartbeats.Queue; control != "" {
+		c.logger.Info("consuming heartbeat queue", zap.String("queue", control))
+		go c.consumeQueue(control, outputChan)
+	}
+
+	c.mu.RLock()
+	loopback := c.loopback
+	c.mu.RUnlock()
+
+	if loopback != "" {
+		c.logger.Info("consuming loopback queue", zap.String("queue", loopback))
+		go c.consumeQueue(loopback, outputChan)
+	}
+
+	c.consumeQueue(queues[0], outputChan)
+}
+
+// consumeQueue consumes queue until the process exits, reconnecting as needed
+func (c *Consumer) consumeQueue(queue string, outputChan chan entity.Event) {
 	for {
 		if !c.IsHealthy() {
 			c.logger.Warn("connection is unhealthy, attempting to reconnect...")
@@ -212,7 +459,16 @@ func (c *Consumer) ConsumeMessages(outputChan chan entity.Event) {
 			continue
 		}
 
-		if err := c.startConsuming(outputChan); err != nil {
+		if c.IsPaused(queue) {
+			c.waitForResume(queue)
+			continue
+		}
+
+		if err := c.startConsuming(queue, outputChan); err != nil {
+			if errors.Is(err, ErrPaused) {
+				continue
+			}
+
 			c.logger.Error("consuming stopped with error", zap.Error(err))
 			time.Sleep(DEFAULT_RECONNECT_DELAY)
 		}
@@ -228,22 +484,39 @@ func (c *Consumer) handleReconnection() error {
 		return fmt.Errorf("reconnection already in progress")
 	}
 
+	// Another queue's loop may have reconnected while we waited for the lock
+	if c.isConnected && c.conn != nil && !c.conn.IsClosed() {
+		return nil
+	}
+
 	c.reconnecting = true
 	defer func() { c.reconnecting = false }()
 
 	return c.reconnect()
 }
 
-// startConsuming handles the actual message consumption
-func (c *Consumer) startConsuming(outputChan chan entity.Event) error {
-	msgs, err := c.channel.Consume(
-		c.cfg.Queue.Request, // queue to consume from
-		"",                  // consumer identifier
-		true,                // auto-acknowledge messages
-		false,               // exclusive consumer
-		false,               // no-local flag
-		false,               // no-wait flag
-		nil,                 // arguments
+// startConsuming handles the actual message consumption from queue
+func (c *Consumer) startConsuming(queue string, outputChan chan entity.Event) error {
+	c.mu.RLock()
+	channel := c.channel
+	cancels := c.cancels
+	c.mu.RUnlock()
+
+	if channel == nil {
+		return fmt.Errorf("consumer has no open channel")
+	}
+
+	// Messages are acknowledged on receipt unless they are once handled
+	autoAck := !c.cfg.Delivery.ManualAck
+
+	msgs, err := channel.Consume(
+		queue,              // queue to consume from
+		consumerTag(queue), // consumer identifier, used to cancel on pause
+		autoAck,            // auto-acknowledge messages
+		false,              // exclusive consumer
+		false,              // no-local flag
+		false,              // no-wait flag
+		nil,                // arguments
 	)
 	if err != nil {
 		return fmt.Errorf("failed to register consumer: %w", err)
@@ -251,32 +524,83 @@ func (c *Consumer) startConsuming(outputChan chan entity.Event) error {
 
 	c.logger.Info("successfully connected to RabbitMQ, waiting for messages...")
 
+	ticker := time.NewTicker(HEARTBEAT_INTERVAL)
+	defer ticker.Stop()
+
 	// Process incoming messages
-	for msg := range msgs {
-		if err := c.processMessage(msg, outputChan); err != nil {
-			c.logger.Error("failed to process message", zap.Error(err))
-			// Continue processing other messages even if one fails
+	for {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				if c.wasCancelled(cancels) {
+					return ErrPaused
+				}
+				return fmt.Errorf("message channel closed")
+			}
+
+			c.heartbeat()
+
+			if err := c.processMessage(msg, outputChan); err != nil {
+				c.logger.Error("failed to process message", zap.Error(err))
+				// Continue processing other messages even if one fails
+			}
+
+		case <-ticker.C:
+			c.heartbeat()
 		}
 	}
-
-	return fmt.Errorf("message channel closed")
 }
 
 // processMessage handles individual message processing
-func (c *Consumer) processMessage(msg amqp.Delivery, outputChan chan entity.Event) error {
+func (c *Consumer) processMessage(msg amqp.Delivery, outputChan chan entity.Event) (err error) {
+	_, end := telemetry.StartAMQP(context.Background(), telemetry.AMQP_RECEIVE, msg.Exchange, msg.RoutingKey, msg.Headers)
+	defer func() { end(err) }()
+
+	settle := c.settle(msg)
+
 	event := new(entity.Event)
-	if err := json.Unmarshal(msg.Body, event); err != nil {
+	if _, err := envelope.Unmarshal(msg.Body, event); err != nil {
 		c.logger.Error("failed to unmarshal event",
 			zap.Error(err),
 			zap.ByteString("body", msg.Body))
+		settle(false) // Redelivering it doesn't make it readable
 		return fmt.Errorf("failed to unmarshal message: %w", err)
 	}
 
+	if event.CorrelationID == "" {
+		event.CorrelationID = msg.CorrelationId
+	}
+
 	c.logger.Debug("received new event",
 		zap.String("event_id", event.ID),
 		zap.String("routing_key", event.Type),
 		zap.Time("timestamp", event.Timestamp))
 
+	if c.expired(event) {
+		c.logger.Warn("dropping expired event",
+			zap.String("event_id", event.ID),
+			zap.String("routing_key", event.Type),
+			zap.Time("timestamp", event.Timestamp))
+		metrics.RequestsExpired.Inc()
+		c.expire(msg)
+		return nil
+	}
+
+	if c.dedup != nil && !c.dedup.firstSeen(event.ID) {
+		c.logger.Debug("dropping duplicate event", zap.String("event_id", event.ID))
+		settle(false)
+		return nil
+	}
+
+	if c.sendToSink(*event) {
+		settle(false) // The sink keeps it until handled
+		return nil
+	}
+
+	if c.cfg.Delivery.ManualAck {
+		event.Settle = settle
+	}
+
 	// Non-blocking send to output channel
 	select {
 	case outputChan <- *event:
@@ -284,31 +608,65 @@ func (c *Consumer) processMessage(msg amqp.Delivery, outputChan chan entity.Even
 	default:
 		c.logger.Warn("output channel is full, dropping message",
 			zap.String("event_id", event.ID))
+		settle(true)
 		return fmt.Errorf("output channel is full")
 	}
 }
 
-// rebindExchanges rebinds all tracked exchanges after reconnection
+// settle returns the function settling msg while messages are acknowledged
+// once handled: it acknowledges msg or, with requeue, returns it to the
+// queue. A message failing again after its redelivery is rejected for good,
+// to the dead letter exchange of the queue if there is one, so it doesn't
+// loop forever. Only the first call has an effect.
+func (c *Consumer) settle(msg amqp.Delivery) func(requeue bool) {
+	if !c.cfg.Delivery.ManualAck {
+		return func(bool) {}
+	}
+
+	var once sync.Once
+
+	return func(requeue bool) {
+		once.Do(func() {
+			var err error
+			if requeue {
+				err = msg.Nack(false, !msg.Redelivered)
+			} else {
+				err = msg.Ack(false)
+			}
+
+			if err != nil {
+				c.logger.Warn("failed to settle message",
+					zap.Uint64("delivery_tag", msg.DeliveryTag),
+					zap.Bool("requeue", requeue),
+					zap.Error(err))
+			}
+		})
+	}
+}
+
+// rebindExchanges rebinds all tracked queue bindings after reconnection
 func (c *Consumer) rebindExchanges() error {
 	c.mu.RLock()
-	exchanges := make([]string, 0, len(c.exchanges))
-	for exchange := range c.exchanges {
-		exchanges = append(exchanges, exchange)
+	defer c.mu.RUnlock()
+
+	if c.channel == nil {
+		return fmt.Errorf("consumer has no open channel")
 	}
-	c.mu.RUnlock()
 
-	for _, exchange := range exchanges {
+	for _, b := range c.bindings {
 		if err := c.channel.QueueBind(
-			c.cfg.Queue.Request,
-			EXCHANGE_TYPE,
-			exchange,
+			b.queue,
+			b.routingKey,
+			b.exchange,
 			false,
-			nil,
+			b.args,
 		); err != nil {
 			c.logger.Error("failed to bind queue to exchange",
-				zap.String("exchange", exchange),
+				zap.String("queue", b.queue),
+				zap.String("exchange", b.exchange),
+				zap.String("routing_key", b.routingKey),
 				zap.Error(err))
-			return fmt.Errorf("failed to bind exchange %s: %w", exchange, err)
+			return fmt.Errorf("failed to bind exchange %s: %w", b.exchange, err)
 		}
 	}
 
@@ -317,11 +675,12 @@ func (c *Consumer) rebindExchanges() error {
 
 // reconnect handles the reconnection logic when the RabbitMQ connection is lost
 // It re-establishes the connection, recreates the channel, and redeclares all exchanges
+// Callers must hold c.mu
 func (c *Consumer) reconnect() error {
 	c.cleanup()
 
 	// Establish new connection
-	conn, err := amqp.Dial(c.cfg.Urls.Rabbitmq)
+	conn, err := c.dial()
 	if err != nil {
 		return fmt.Errorf("failed to dial RabbitMQ: %w", err)
 	}
@@ -331,16 +690,15 @@ func (c *Consumer) reconnect() error {
 	// Create new channel
 	if err := c.initializeChannel(); err != nil {
 		c.conn.Close()
+		c.conn = nil
 		return err
 	}
 
 	// Redeclare all exchanges
-	c.mu.RLock()
 	exchanges := make([]string, 0, len(c.exchanges))
 	for exchange := range c.exchanges {
 		exchanges = append(exchanges, exchange)
 	}
-	c.mu.RUnlock()
 
 	for _, exchange := range exchanges {
 		if err := c.declareExchange(exchange); err != nil {
@@ -349,12 +707,25 @@ func (c *Consumer) reconnect() error {
 		}
 	}
 
+	for exchange := range c.headers {
+		if err := c.declareHeadersExchange(exchange); err != nil {
+			c.cleanup()
+			return fmt.Errorf("failed to redeclare headers exchange %s: %w", exchange, err)
+		}
+	}
+
 	c.isConnected = true
 	c.logger.Info("successfully reconnected to RabbitMQ")
+
+	// c.mu is held, the hook may need the consumer
+	if c.onReconnect != nil {
+		go c.onReconnect()
+	}
 	return nil
 }
 
 // cleanup closes existing connections and channels
+// Callers must hold c.mu
 func (c *Consumer) cleanup() {
 	c.isConnected = false
 
@@ -0,0 +1,609 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeChannel is an in-memory AMQP channel test double
+type fakeChannel struct {
+	mu         sync.Mutex
+	exchanges  []string
+	kinds      map[string]string
+	queues     []string
+	queueArgs  map[string]amqp.Table // Arguments each queue was declared with
+	bindings   []binding
+	deliveries chan amqp.Delivery
+	declareErr error
+	queueErr   error
+	bindErr    error
+	consumeErr error
+	cancelled  []string
+	closed     bool
+	depth      map[string]amqp.Queue // Queues reported by passive declares
+	manualAck  bool                  // Whether the last consumer acknowledges messages itself
+	prefetch   int
+}
+
+func newFakeChannel() *fakeChannel {
+	return &fakeChannel{deliveries: make(chan amqp.Delivery, 10)}
+}
+
+func (f *fakeChannel) ExchangeDeclare(name, kind string, _, _, _, _ bool, _ amqp.Table) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.declareErr != nil {
+		return f.declareErr
+	}
+
+	if f.kinds == nil {
+		f.kinds = make(map[string]string)
+	}
+
+	f.exchanges = append(f.exchanges, name)
+	f.kinds[name] = kind
+	return nil
+}
+
+func (f *fakeChannel) QueueDeclare(name string, _, _, _, _ bool, args amqp.Table) (amqp.Queue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.queueErr != nil {
+		return amqp.Queue{}, f.queueErr
+	}
+
+	if f.queueArgs == nil {
+		f.queueArgs = make(map[string]amqp.Table)
+	}
+
+	f.queues = append(f.queues, name)
+	f.queueArgs[name] = args
+	return amqp.Queue{Name: name}, nil
+}
+
+func (f *fakeChannel) QueueDeclarePassive(name string, _, _, _, _ bool, _ amqp.Table) (amqp.Queue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.queueErr != nil {
+		return amqp.Queue{}, f.queueErr
+	}
+
+	queue, ok := f.depth[name]
+	if !ok {
+		return amqp.Queue{}, fmt.Errorf("NOT_FOUND - no queue '%s'", name)
+	}
+
+	return queue, nil
+}
+
+func (f *fakeChannel) QueueBind(name, key, exchange string, _ bool, args amqp.Table) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.bindErr != nil {
+		return f.bindErr
+	}
+
+	f.bindings = append(f.bindings, binding{queue: name, routingKey: key, exchange: exchange, args: args})
+	return nil
+}
+
+func (f *fakeChannel) Consume(_, _ string, autoAck, _, _, _ bool, _ amqp.Table) (<-chan amqp.Delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.consumeErr != nil {
+		return nil, f.consumeErr
+	}
+
+	f.manualAck = !autoAck
+	return f.deliveries, nil
+}
+
+func (f *fakeChannel) Qos(prefetchCount, _ int, _ bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prefetch = prefetchCount
+	return nil
+}
+
+// Cancel closes the current delivery channel, like the broker does for a
+// cancelled consumer, and prepares a fresh one for the next Consume
+func (f *fakeChannel) Cancel(consumer string, _ bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.cancelled = append(f.cancelled, consumer)
+	close(f.deliveries)
+	f.deliveries = make(chan amqp.Delivery, 10)
+
+	return nil
+}
+
+func (f *fakeChannel) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	return nil
+}
+
+// fakeConnection is an in-memory AMQP connection test double
+type fakeConnection struct {
+	mu      sync.Mutex
+	channel *fakeChannel
+	chanErr error
+	closed  bool
+}
+
+func newFakeConnection() *fakeConnection {
+	return &fakeConnection{channel: newFakeChannel()}
+}
+
+func (f *fakeConnection) Channel() (channel, error) {
+	if f.chanErr != nil {
+		return nil, f.chanErr
+	}
+
+	return f.channel, nil
+}
+
+func (f *fakeConnection) IsClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.closed
+}
+
+func (f *fakeConnection) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	return nil
+}
+
+func testConfig() *config.Config {
+	cfg, _ := config.Init("")
+	return cfg
+}
+
+func setupConsumer(t *testing.T, dial func() (connection, error)) (*Consumer, *fakeConnection) {
+	t.Helper()
+
+	conn := newFakeConnection()
+	if dial == nil {
+		dial = func() (connection, error) { return nil, errors.New("no broker") }
+	}
+
+	c, err := newConsumer(testConfig(), &logger.Logger{Logger: zap.NewNop()}, conn, dial)
+	require.NoError(t, err)
+
+	return c, conn
+}
+
+func TestConsumer_Init(t *testing.T) {
+	t.Run("rejects nil parameters", func(t *testing.T) {
+		c, err := Init(nil, nil, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("declares the request exchange", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+
+		assert.Equal(t, []string{"request"}, conn.channel.exchanges)
+		assert.True(t, c.exchanges["request"])
+		assert.True(t, c.IsHealthy())
+	})
+
+	t.Run("returns channel errors", func(t *testing.T) {
+		conn := newFakeConnection()
+		conn.chanErr = errors.New("channel error")
+
+		c, err := newConsumer(testConfig(), &logger.Logger{Logger: zap.NewNop()}, conn, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("cleans up when the exchange can't be declared", func(t *testing.T) {
+		conn := newFakeConnection()
+		conn.channel.declareErr = errors.New("access refused")
+
+		c, err := newConsumer(testConfig(), &logger.Logger{Logger: zap.NewNop()}, conn, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, c)
+		assert.True(t, conn.IsClosed())
+		assert.True(t, conn.channel.closed)
+	})
+}
+
+func TestConsumer_Subscribe(t *testing.T) {
+	t.Run("declares and binds the queue", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+
+		err := c.Subscribe("request", "request.*", "request")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"request"}, conn.channel.queues)
+		assert.Equal(t, []binding{{queue: "request", routingKey: "request.*", exchange: "request"}},
+			conn.channel.bindings)
+		assert.Len(t, c.bindings, 1)
+	})
+
+	t.Run("tracks each binding once", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+
+		require.NoError(t, c.Subscribe("request", "request.*", "request"))
+		require.NoError(t, c.Subscribe("request", "request.*", "request"))
+
+		assert.Len(t, c.bindings, 1)
+	})
+
+	t.Run("fails when disconnected", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+		require.NoError(t, c.Close())
+
+		err := c.Subscribe("request", "request.*", "request")
+
+		assert.ErrorContains(t, err, "not connected")
+	})
+
+	t.Run("returns queue declare errors", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+		conn.channel.queueErr = errors.New("precondition failed")
+
+		err := c.Subscribe("request", "request.*", "request")
+
+		assert.ErrorContains(t, err, "failed to declare queue")
+	})
+
+	t.Run("returns bind errors", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+		conn.channel.bindErr = errors.New("not found")
+
+		err := c.Subscribe("request", "request.*", "request")
+
+		assert.ErrorContains(t, err, "failed to bind queue")
+		assert.Empty(t, c.bindings)
+	})
+}
+
+func TestConsumer_SubscribeHeaders(t *testing.T) {
+	t.Run("declares a headers exchange and binds on the headers", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+
+		err := c.SubscribeHeaders("events", "tenant-a", map[string]string{"tenant": "a", "form_status": "open"}, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, amqp.ExchangeHeaders, conn.channel.kinds["events"])
+		assert.Equal(t, []binding{{
+			queue:    "tenant-a",
+			exchange: "events",
+			args:     amqp.Table{"x-match": "all", "tenant": "a", "form_status": "open"},
+		}}, conn.channel.bindings)
+	})
+
+	t.Run("matches any header", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+
+		require.NoError(t, c.SubscribeHeaders("events", "closed", map[string]string{"form_status": "closed"}, false))
+
+		assert.Equal(t, "any", conn.channel.bindings[0].args["x-match"])
+	})
+
+	t.Run("redeclares the headers exchange on reconnect", func(t *testing.T) {
+		next := newFakeConnection()
+
+		c, conn := setupConsumer(t, func() (connection, error) {
+			return next, nil
+		})
+		require.NoError(t, c.SubscribeHeaders("events", "tenant-a", map[string]string{"tenant": "a"}, true))
+
+		conn.Close()
+		require.NoError(t, c.handleReconnection())
+		require.NoError(t, c.rebindExchanges())
+
+		assert.Equal(t, amqp.ExchangeHeaders, next.channel.kinds["events"])
+		assert.Equal(t, "direct", next.channel.kinds["request"])
+		assert.Len(t, next.channel.bindings, 1)
+	})
+}
+
+func TestConsumer_ProcessMessage(t *testing.T) {
+	t.Run("forwards decoded events", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+		out := make(chan entity.Event, 1)
+
+		event := entity.NewEvent("request.form.created", []byte(`{}`))
+		body, err := json.Marshal(event)
+		require.NoError(t, err)
+
+		require.NoError(t, c.processMessage(amqp.Delivery{Body: body}, out))
+
+		received := <-out
+		assert.Equal(t, event.ID, received.ID)
+		assert.Equal(t, event.Type, received.Type)
+	})
+
+	t.Run("rejects malformed bodies", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+		out := make(chan entity.Event, 1)
+
+		err := c.processMessage(amqp.Delivery{Body: []byte("not json")}, out)
+
+		assert.ErrorContains(t, err, "failed to unmarshal")
+		assert.Empty(t, out)
+	})
+
+	t.Run("drops events when the output channel is full", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+		out := make(chan entity.Event)
+
+		body, _ := json.Marshal(entity.NewEvent("request.form.created", []byte(`{}`)))
+
+		err := c.processMessage(amqp.Delivery{Body: body}, out)
+
+		assert.ErrorContains(t, err, "output channel is full")
+	})
+
+	t.Run("drops duplicates while draining a legacy queue", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Topology.LegacyRequestQueue = "request.v1"
+
+		c, err := newConsumer(cfg, &logger.Logger{Logger: zap.NewNop()}, newFakeConnection(), nil)
+		require.NoError(t, err)
+
+		out := make(chan entity.Event, 2)
+		body, _ := json.Marshal(entity.NewEvent("request.form.created", []byte(`{}`)))
+
+		require.NoError(t, c.processMessage(amqp.Delivery{Body: body}, out))
+		require.NoError(t, c.processMessage(amqp.Delivery{Body: body}, out))
+
+		assert.Len(t, out, 1)
+		assert.Equal(t, []string{"request", "request.v1"}, c.queues())
+	})
+
+	t.Run("writes events to the sink", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+		out := make(chan entity.Event, 1)
+
+		var stored []entity.Event
+		c.SetSink(func(_ context.Context, event entity.Event) error {
+			stored = append(stored, event)
+			return nil
+		})
+
+		event := entity.NewEvent("request.form.created", []byte(`{}`))
+		body, _ := json.Marshal(event)
+
+		require.NoError(t, c.processMessage(amqp.Delivery{Body: body}, out))
+
+		require.Len(t, stored, 1)
+		assert.Equal(t, event.ID, stored[0].ID)
+		assert.Empty(t, out)
+	})
+
+	t.Run("falls back to the output channel when the sink fails", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+		out := make(chan entity.Event, 1)
+
+		c.SetSink(func(context.Context, entity.Event) error {
+			return errors.New("redis is down")
+		})
+
+		body, _ := json.Marshal(entity.NewEvent("request.form.created", []byte(`{}`)))
+
+		require.NoError(t, c.processMessage(amqp.Delivery{Body: body}, out))
+		assert.Len(t, out, 1)
+	})
+}
+
+// fakeAcknowledger records how deliveries were settled
+type fakeAcknowledger struct {
+	acked    []uint64
+	requeued []uint64
+	rejected []uint64
+}
+
+func (f *fakeAcknowledger) Ack(tag uint64, _ bool) error {
+	f.acked = append(f.acked, tag)
+	return nil
+}
+
+func (f *fakeAcknowledger) Nack(tag uint64, _ bool, requeue bool) error {
+	if requeue {
+		f.requeued = append(f.requeued, tag)
+	} else {
+		f.rejected = append(f.rejected, tag)
+	}
+	return nil
+}
+
+func (f *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return f.Nack(tag, false, requeue)
+}
+
+func TestConsumer_ManualAck(t *testing.T) {
+	cfg := testConfig()
+	cfg.Delivery.ManualAck = true
+
+	conn := newFakeConnection()
+	c, err := newConsumer(cfg, &logger.Logger{Logger: zap.NewNop()}, conn, nil)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Delivery.Prefetch, conn.channel.prefetch)
+
+	acks := new(fakeAcknowledger)
+	body, _ := json.Marshal(entity.NewEvent("request.form.created", []byte(`{}`)))
+	delivery := func(tag uint64, redelivered bool) amqp.Delivery {
+		return amqp.Delivery{Acknowledger: acks, DeliveryTag: tag, Redelivered: redelivered, Body: body}
+	}
+
+	t.Run("settles events once handled", func(t *testing.T) {
+		out := make(chan entity.Event, 3)
+
+		require.NoError(t, c.processMessage(delivery(1, false), out))
+		require.NoError(t, c.processMessage(delivery(2, false), out))
+		require.NoError(t, c.processMessage(delivery(3, true), out))
+		assert.Empty(t, acks.acked, "nothing is acknowledged on receipt")
+
+		for _, requeue := range []bool{false, true, true} {
+			event := <-out
+			require.NotNil(t, event.Settle)
+			event.Settle(requeue)
+			event.Settle(false) // Only the first call counts
+		}
+
+		assert.Equal(t, []uint64{1}, acks.acked)
+		assert.Equal(t, []uint64{2}, acks.requeued)
+		assert.Equal(t, []uint64{3}, acks.rejected, "failing again after a redelivery")
+	})
+
+	t.Run("settles events it doesn't forward", func(t *testing.T) {
+		*acks = fakeAcknowledger{}
+
+		require.Error(t, c.processMessage(amqp.Delivery{Acknowledger: acks, DeliveryTag: 4, Body: []byte("not json")}, nil))
+		require.Error(t, c.processMessage(delivery(5, false), make(chan entity.Event)))
+
+		assert.Equal(t, []uint64{4}, acks.acked)
+		assert.Equal(t, []uint64{5}, acks.requeued, "events the listener can't take are redelivered")
+	})
+
+	t.Run("consumes without auto-acknowledgement", func(t *testing.T) {
+		close(conn.channel.deliveries)
+		assert.Error(t, c.startConsuming("request", make(chan entity.Event)))
+		assert.True(t, conn.channel.manualAck)
+	})
+}
+
+func TestConsumer_StartConsuming(t *testing.T) {
+	t.Run("returns when the delivery channel closes", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+		out := make(chan entity.Event, 1)
+
+		body, _ := json.Marshal(entity.NewEvent("request.form.created", []byte(`{}`)))
+		conn.channel.deliveries <- amqp.Delivery{Body: body}
+		close(conn.channel.deliveries)
+
+		err := c.startConsuming(c.cfg.Queue.Request, out)
+
+		assert.ErrorContains(t, err, "message channel closed")
+		assert.Len(t, out, 1)
+	})
+
+	t.Run("returns consume errors", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+		conn.channel.consumeErr = errors.New("queue not found")
+
+		err := c.startConsuming(c.cfg.Queue.Request, make(chan entity.Event))
+
+		assert.ErrorContains(t, err, "failed to register consumer")
+	})
+}
+
+func TestConsumer_Reconnect(t *testing.T) {
+	t.Run("redeclares exchanges and restores bindings", func(t *testing.T) {
+		next := newFakeConnection()
+
+		c, conn := setupConsumer(t, func() (connection, error) {
+			return next, nil
+		})
+		require.NoError(t, c.Subscribe("request", "request.*", "request"))
+
+		conn.Close()
+		require.False(t, c.IsHealthy())
+
+		require.NoError(t, c.handleReconnection())
+		require.NoError(t, c.rebindExchanges())
+
+		assert.True(t, c.IsHealthy())
+		assert.True(t, conn.channel.closed)
+		assert.Equal(t, []string{"request"}, next.channel.exchanges)
+		assert.Equal(t, []binding{{queue: "request", routingKey: "request.*", exchange: "request"}},
+			next.channel.bindings)
+	})
+
+	t.Run("tells the reconnect hook", func(t *testing.T) {
+		next := newFakeConnection()
+
+		c, conn := setupConsumer(t, func() (connection, error) {
+			return next, nil
+		})
+
+		reconnected := make(chan struct{}, 1)
+		c.OnReconnect(func() { reconnected <- struct{}{} })
+
+		conn.Close()
+		require.NoError(t, c.handleReconnection())
+
+		select {
+		case <-reconnected:
+		case <-time.After(time.Second):
+			t.Fatal("reconnect hook not called")
+		}
+	})
+
+	t.Run("stays disconnected when dialing fails", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+
+		conn.Close()
+
+		err := c.handleReconnection()
+
+		assert.ErrorContains(t, err, "failed to dial RabbitMQ")
+		assert.False(t, c.IsHealthy())
+	})
+
+	t.Run("consumes from the new channel after reconnecting", func(t *testing.T) {
+		next := newFakeConnection()
+
+		c, conn := setupConsumer(t, func() (connection, error) {
+			return next, nil
+		})
+
+		conn.Close()
+		close(conn.channel.deliveries)
+
+		out := make(chan entity.Event, 1)
+		go c.ConsumeMessages(out)
+
+		body, _ := json.Marshal(entity.NewEvent("request.form.created", []byte(`{}`)))
+		next.channel.deliveries <- amqp.Delivery{Body: body}
+
+		select {
+		case <-out:
+		case <-time.After(time.Second):
+			t.Fatal("no event consumed after reconnect")
+		}
+	})
+}
+
+func TestConsumer_Close(t *testing.T) {
+	c, conn := setupConsumer(t, nil)
+
+	require.NoError(t, c.Close())
+
+	assert.True(t, conn.IsClosed())
+	assert.True(t, conn.channel.closed)
+	assert.False(t, c.IsHealthy())
+}
@@ -0,0 +1,261 @@
+package consumer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// CONSUMER_TAG_PREFIX prefixes the consumer tag registered for each queue
+	CONSUMER_TAG_PREFIX = "form-service."
+
+	// PAUSE_REASON_ADMIN is the pause reason used by Pause and Resume
+	PAUSE_REASON_ADMIN = "admin"
+)
+
+var (
+	// ErrPaused is returned by startConsuming when its queue was paused
+	ErrPaused = errors.New("consumption paused")
+
+	// ErrUnknownQueue is returned when pausing or resuming a queue the consumer doesn't read
+	ErrUnknownQueue = errors.New("unknown queue")
+)
+
+// consumerTag returns the consumer tag used for queue
+func consumerTag(queue string) string {
+	return CONSUMER_TAG_PREFIX + queue
+}
+
+// consumesQueue reports whether the consumer reads from queue
+func (c *Consumer) consumesQueue(queue string) bool {
+	if queue != "" && queue == c.cfg.Heartbeats.Queue {
+		return true
+	}
+
+	c.mu.RLock()
+	loopback := c.loopback
+	c.mu.RUnlock()
+
+	if queue != "" && queue == loopback {
+		return true
+	}
+
+	for _, consumed := range c.queues() {
+		if queue == consumed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Pause stops consuming from queue by cancelling its consumer tag. Messages
+// stay in the broker until Resume; the connection, health checks and
+// publishing are unaffected.
+func (c *Consumer) Pause(queue string) error {
+	return c.PauseFor(queue, PAUSE_REASON_ADMIN)
+}
+
+// Resume restarts consuming from a queue paused with Pause
+func (c *Consumer) Resume(queue string) error {
+	return c.ResumeFor(queue, PAUSE_REASON_ADMIN)
+}
+
+// PauseFor pauses queue like Pause on behalf of reason. Pauses with
+// different reasons stack: the queue is consumed again only once every
+// reason was resumed, so e.g. an automatic resume can't undo an operator's pause.
+func (c *Consumer) PauseFor(queue, reason string) error {
+	if !c.consumesQueue(queue) {
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, queue)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reasons := c.paused[queue]
+	if reasons[reason] {
+		return nil
+	}
+
+	if reasons == nil {
+		reasons = make(map[string]bool)
+		c.paused[queue] = reasons
+	}
+
+	reasons[reason] = true
+	c.notifyPause(queue, reason, true)
+
+	if len(reasons) > 1 {
+		// Already paused for another reason
+		return nil
+	}
+
+	if c.channel != nil {
+		c.cancels++
+
+		if err := c.channel.Cancel(consumerTag(queue), false); err != nil {
+			c.logger.Error("failed to cancel consumer",
+				zap.String("queue", queue),
+				zap.Error(err))
+			return fmt.Errorf("failed to cancel consumer for %s: %w", queue, err)
+		}
+	}
+
+	c.logger.Warn("consumption paused",
+		zap.String("queue", queue),
+		zap.String("reason", reason))
+
+	return nil
+}
+
+// ResumeFor withdraws a pause of queue made with PauseFor for reason
+func (c *Consumer) ResumeFor(queue, reason string) error {
+	if !c.consumesQueue(queue) {
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, queue)
+	}
+
+	c.mu.Lock()
+	reasons := c.paused[queue]
+	wasPaused := reasons[reason]
+	delete(reasons, reason)
+
+	if wasPaused {
+		c.notifyPause(queue, reason, false)
+	}
+
+	resumed := wasPaused && len(reasons) == 0
+	if resumed {
+		delete(c.paused, queue)
+
+		// Wake every waiting loop; each rechecks its own queue
+		close(c.resumed)
+		c.resumed = make(chan struct{})
+	}
+	c.mu.Unlock()
+
+	if !resumed {
+		return nil
+	}
+
+	c.logger.Info("consumption resumed",
+		zap.String("queue", queue),
+		zap.String("reason", reason))
+
+	return nil
+}
+
+// OnMaintenance registers a function called on its own goroutine whenever
+// an operator takes a queue into maintenance with Pause or out of it with
+// Resume. Automatic pauses, e.g. by a throttle, aren't reported.
+func (c *Consumer) OnMaintenance(hook func(queue string, paused bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onPause = hook
+}
+
+// notifyPause tells the OnMaintenance hook about an admin pause made or
+// withdrawn
+// Callers must hold c.mu
+func (c *Consumer) notifyPause(queue, reason string, paused bool) {
+	if c.onPause != nil && reason == PAUSE_REASON_ADMIN {
+		go c.onPause(queue, paused)
+	}
+}
+
+// IsPaused reports whether consumption of queue is paused for any reason
+func (c *Consumer) IsPaused(queue string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.paused[queue]) > 0
+}
+
+// wasCancelled reports whether Pause cancelled the consumer since cancels
+// was observed. The delivery channel may close after a quick Resume already
+// cleared the paused flag, so the flag alone can't tell a pause from a failure.
+func (c *Consumer) wasCancelled(cancels int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cancels != cancels
+}
+
+// waitForResume blocks until queue is resumed, reporting activity meanwhile
+// so a paused consumer isn't mistaken for a stuck one
+func (c *Consumer) waitForResume(queue string) {
+	ticker := time.NewTicker(HEARTBEAT_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		c.mu.RLock()
+		paused, resumed := len(c.paused[queue]) > 0, c.resumed
+		c.mu.RUnlock()
+
+		if !paused {
+			return
+		}
+
+		c.heartbeat()
+
+		select {
+		case <-resumed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// AdminHandler serves consumption control under prefix:
+//
+//	GET  {prefix}              - pause state of every consumed queue
+//	POST {prefix}pause?queue=  - pause a queue
+//	POST {prefix}resume?queue= - resume a queue
+func (c *Consumer) AdminHandler(prefix string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		action := strings.TrimPrefix(r.URL.Path, prefix)
+
+		if action == "" && r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			status := make(map[string]bool)
+			for _, queue := range c.queues() {
+				status[queue] = c.IsPaused(queue)
+			}
+
+			json.NewEncoder(w).Encode(status)
+			return
+		}
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		queue := r.URL.Query().Get("queue")
+
+		var err error
+		switch action {
+		case "pause":
+			err = c.Pause(queue)
+		case "resume":
+			err = c.Resume(queue)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		switch {
+		case errors.Is(err, ErrUnknownQueue):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package consumer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumer_PauseResume(t *testing.T) {
+	c, conn := setupConsumer(t, nil)
+	queue := c.cfg.Queue.Request
+	out := make(chan entity.Event, 10)
+
+	go c.ConsumeMessages(out)
+
+	body, _ := json.Marshal(entity.NewEvent("request.form.created", []byte(`{}`)))
+
+	conn.channel.mu.Lock()
+	conn.channel.deliveries <- amqp.Delivery{Body: body}
+	conn.channel.mu.Unlock()
+
+	assert.Eventually(t, func() bool { return len(out) == 1 }, time.Second, time.Millisecond)
+
+	require.NoError(t, c.Pause(queue))
+	require.NoError(t, c.Pause(queue), "pausing twice is a no-op")
+
+	assert.True(t, c.IsPaused(queue))
+	assert.Equal(t, []string{consumerTag(queue)}, conn.channel.cancelled)
+	assert.True(t, c.IsHealthy(), "a paused consumer stays healthy")
+
+	require.NoError(t, c.Resume(queue))
+	assert.False(t, c.IsPaused(queue))
+
+	// The consumer re-registers on a fresh delivery channel after resuming
+	assert.Eventually(t, func() bool {
+		conn.channel.mu.Lock()
+		defer conn.channel.mu.Unlock()
+
+		select {
+		case conn.channel.deliveries <- amqp.Delivery{Body: body}:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+
+	assert.Eventually(t, func() bool { return len(out) == 2 }, time.Second, time.Millisecond)
+}
+
+func TestConsumer_PauseReasons(t *testing.T) {
+	c, conn := setupConsumer(t, nil)
+	queue := c.cfg.Queue.Request
+
+	require.NoError(t, c.Pause(queue))
+	require.NoError(t, c.PauseFor(queue, "database"))
+	assert.Len(t, conn.channel.cancelled, 1, "the consumer is cancelled once")
+
+	require.NoError(t, c.ResumeFor(queue, "database"))
+	assert.True(t, c.IsPaused(queue), "the admin pause still holds")
+
+	require.NoError(t, c.Resume(queue))
+	assert.False(t, c.IsPaused(queue))
+}
+
+func TestConsumer_OnMaintenance(t *testing.T) {
+	c, _ := setupConsumer(t, nil)
+	queue := c.cfg.Queue.Request
+
+	toggles := make(chan bool, 10)
+	c.OnMaintenance(func(q string, paused bool) {
+		assert.Equal(t, queue, q)
+		toggles <- paused
+	})
+
+	next := func() bool {
+		select {
+		case paused := <-toggles:
+			return paused
+		case <-time.After(time.Second):
+			t.Fatal("maintenance toggle not reported")
+			return false
+		}
+	}
+
+	require.NoError(t, c.PauseFor(queue, "database"))
+	require.NoError(t, c.Pause(queue))
+	assert.True(t, next(), "admin pauses are reported while paused for another reason")
+
+	require.NoError(t, c.Pause(queue))
+	require.NoError(t, c.ResumeFor(queue, "database"))
+	require.NoError(t, c.Resume(queue))
+	assert.False(t, next())
+
+	assert.Empty(t, toggles, "repeated and automatic pauses are not reported")
+}
+
+func TestConsumer_PauseUnknownQueue(t *testing.T) {
+	c, _ := setupConsumer(t, nil)
+
+	assert.ErrorIs(t, c.Pause("other"), ErrUnknownQueue)
+	assert.ErrorIs(t, c.Resume("other"), ErrUnknownQueue)
+}
+
+func TestConsumer_AdminHandler(t *testing.T) {
+	c, _ := setupConsumer(t, nil)
+	handler := c.AdminHandler("/admin/consumer/")
+	queue := c.cfg.Queue.Request
+
+	do := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodPost, "/admin/consumer/pause?queue="+queue).Code)
+	assert.True(t, c.IsPaused(queue))
+
+	rec := do(http.MethodGet, "/admin/consumer/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"`+queue+`": true}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodPost, "/admin/consumer/resume?queue="+queue).Code)
+	assert.False(t, c.IsPaused(queue))
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/admin/consumer/pause?queue=other").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodGet, "/admin/consumer/pause").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/admin/consumer/drain").Code)
+}
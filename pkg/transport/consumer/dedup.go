@@ -0,0 +1,57 @@
+package consumer
+
+import (
+	"sync"
+	"time"
+)
+
+// DEFAULT_DEDUP_WINDOW is used when no dedup window is configured
+const DEFAULT_DEDUP_WINDOW = 10 * time.Minute
+
+// dedup remembers recently seen event IDs so an event delivered on both the
+// old and the new queue during a topology cutover is only handled once
+type dedup struct {
+	mu        sync.Mutex
+	window    time.Duration
+	seen      map[string]time.Time
+	lastPrune time.Time
+	now       func() time.Time // Replaced in tests
+}
+
+func newDedup(window time.Duration) *dedup {
+	if window <= 0 {
+		window = DEFAULT_DEDUP_WINDOW
+	}
+
+	return &dedup{
+		window:    window,
+		seen:      make(map[string]time.Time),
+		lastPrune: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// firstSeen records id and reports whether it wasn't seen within the window
+func (d *dedup) firstSeen(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+
+	if now.Sub(d.lastPrune) > d.window {
+		for key, at := range d.seen {
+			if now.Sub(at) > d.window {
+				delete(d.seen, key)
+			}
+		}
+		d.lastPrune = now
+	}
+
+	if at, ok := d.seen[id]; ok && now.Sub(at) <= d.window {
+		return false
+	}
+
+	d.seen[id] = now
+
+	return true
+}
@@ -0,0 +1,24 @@
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedup_FirstSeen(t *testing.T) {
+	now := time.Now()
+
+	d := newDedup(time.Minute)
+	d.now = func() time.Time { return now }
+
+	assert.True(t, d.firstSeen("a"))
+	assert.False(t, d.firstSeen("a"))
+	assert.True(t, d.firstSeen("b"))
+
+	now = now.Add(2 * time.Minute)
+
+	assert.True(t, d.firstSeen("a"), "ids are forgotten after the window")
+	assert.Len(t, d.seen, 1, "expired ids are pruned")
+}
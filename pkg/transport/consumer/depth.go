@@ -0,0 +1,40 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// QueueDepth returns the messages ready in the queues the consumer reads
+// from and the consumers attached to them, as reported by the broker. Every
+// instance sees the same depth, so it sizes the fleet rather than one pool.
+// Returns:
+//   - messages: Ready messages over all queues
+//   - consumers: Consumers over all queues
+//   - err: Error if the consumer isn't connected or a queue can't be inspected
+func (c *Consumer) QueueDepth() (messages, consumers int, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.isConnected || c.channel == nil {
+		return 0, 0, errors.New("consumer is not connected")
+	}
+
+	for _, name := range c.queues() {
+		// Passive declares only inspect the queue, whatever its arguments
+		queue, err := c.channel.QueueDeclarePassive(name, true, false, false, false, nil)
+		if err != nil {
+			c.logger.Error("failed to inspect queue",
+				zap.String("queue", name),
+				zap.Error(err))
+			return 0, 0, fmt.Errorf("failed to inspect queue %s: %w", name, err)
+		}
+
+		messages += queue.Messages
+		consumers += queue.Consumers
+	}
+
+	return messages, consumers, nil
+}
@@ -0,0 +1,44 @@
+package consumer
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumer_QueueDepth(t *testing.T) {
+	t.Run("sums the consumed queues", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+		c.cfg.Topology.LegacyRequestQueue = "legacy"
+		conn.channel.depth = map[string]amqp.Queue{
+			c.cfg.Queue.Request: {Messages: 40, Consumers: 2},
+			"legacy":            {Messages: 2, Consumers: 1},
+			"unrelated":         {Messages: 1000},
+		}
+
+		messages, consumers, err := c.QueueDepth()
+
+		require.NoError(t, err)
+		assert.Equal(t, 42, messages)
+		assert.Equal(t, 3, consumers)
+	})
+
+	t.Run("missing queue", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+
+		_, _, err := c.QueueDepth()
+
+		assert.ErrorContains(t, err, "failed to inspect queue")
+	})
+
+	t.Run("disconnected", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+		c.isConnected = false
+
+		_, _, err := c.QueueDepth()
+
+		assert.Error(t, err)
+	})
+}
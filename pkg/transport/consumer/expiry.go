@@ -0,0 +1,130 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+)
+
+// queueArgs returns the arguments a queue is declared with: request queues
+// expire messages after expiry.message_ttl and dead-letter expired and
+// rejected messages to expiry.dead_letter_exchange, the loopback queue
+// expires itself once unused, other queues take none.
+// The broker refuses to redeclare a queue with other arguments, so changing
+// them needs the queue deleted first or a broker policy instead.
+func (c *Consumer) queueArgs(name string) amqp.Table {
+	if name != "" && name == c.loopback {
+		return amqp.Table{"x-expires": int64(LOOPBACK_QUEUE_EXPIRY / time.Millisecond)}
+	}
+
+	if !slices.Contains(c.queues(), name) {
+		return nil
+	}
+
+	args := amqp.Table{}
+
+	if ttl := c.cfg.Expiry.MessageTTL; ttl > 0 {
+		args[amqp.QueueMessageTTLArg] = int64(ttl) * int64(time.Second/time.Millisecond)
+	}
+
+	if exchange := c.cfg.Expiry.DeadLetterExchange; exchange != "" {
+		args["x-dead-letter-exchange"] = exchange
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	return args
+}
+
+// declareDeadLetter declares the dead letter exchange, a fanout keeping the
+// routing keys of the requests, and binds the dead letter queue to it
+// Callers must hold c.mu
+func (c *Consumer) declareDeadLetter() error {
+	exchange := c.cfg.Expiry.DeadLetterExchange
+	if exchange == "" {
+		return nil
+	}
+
+	if err := c.declare(exchange, amqp.ExchangeFanout); err != nil {
+		return fmt.Errorf("failed to declare dead letter exchange %s: %w", exchange, err)
+	}
+
+	queue := c.cfg.Expiry.DeadLetterQueue
+	if queue == "" {
+		return nil
+	}
+
+	if _, err := c.channel.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		c.logger.Error("failed to declare dead letter queue",
+			zap.String("queue", queue),
+			zap.Error(err))
+		return fmt.Errorf("failed to declare dead letter queue %s: %w", queue, err)
+	}
+
+	if err := c.channel.QueueBind(queue, "", exchange, false, nil); err != nil {
+		c.logger.Error("failed to bind dead letter queue",
+			zap.String("queue", queue),
+			zap.String("exchange", exchange),
+			zap.Error(err))
+		return fmt.Errorf("failed to bind dead letter queue %s: %w", queue, err)
+	}
+
+	return nil
+}
+
+// expired reports whether event was sent longer than expiry.max_age ago.
+// Events without a timestamp never expire.
+func (c *Consumer) expired(event *entity.Event) bool {
+	maxAge := time.Duration(c.cfg.Expiry.MaxAge) * time.Second
+
+	return maxAge > 0 && !event.Timestamp.IsZero() && time.Since(event.Timestamp) > maxAge
+}
+
+// expire rejects msg for good, to the dead letter exchange of its queue.
+// Acknowledged on receipt, the message is already gone and only dropped.
+func (c *Consumer) expire(msg amqp.Delivery) {
+	if !c.cfg.Delivery.ManualAck {
+		return
+	}
+
+	if err := msg.Nack(false, false); err != nil {
+		c.logger.Warn("failed to reject expired message",
+			zap.Uint64("delivery_tag", msg.DeliveryTag),
+			zap.Error(err))
+	}
+}
+
+// DeadLetterDepth returns the requests waiting in the dead letter queue, as
+// reported by the broker
+// Returns an error if no dead letter queue is configured, the consumer
+// isn't connected or the queue can't be inspected
+func (c *Consumer) DeadLetterDepth() (int, error) {
+	name := c.cfg.Expiry.DeadLetterQueue
+	if c.cfg.Expiry.DeadLetterExchange == "" || name == "" {
+		return 0, errors.New("no dead letter queue configured")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.isConnected || c.channel == nil {
+		return 0, errors.New("consumer is not connected")
+	}
+
+	queue, err := c.channel.QueueDeclarePassive(name, true, false, false, false, nil)
+	if err != nil {
+		c.logger.Error("failed to inspect queue",
+			zap.String("queue", name),
+			zap.Error(err))
+		return 0, fmt.Errorf("failed to inspect queue %s: %w", name, err)
+	}
+
+	return queue.Messages, nil
+}
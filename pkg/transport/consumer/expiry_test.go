@@ -0,0 +1,83 @@
+package consumer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumer_QueueExpiry(t *testing.T) {
+	t.Run("request queues expire to the dead letter exchange", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+		c.cfg.Expiry.MessageTTL = 30
+		c.cfg.Expiry.DeadLetterExchange = "request.dlx"
+		c.cfg.Expiry.DeadLetterQueue = "request.dlq"
+
+		require.NoError(t, c.Subscribe("request", "request.*", c.cfg.Queue.Request))
+		require.NoError(t, c.Subscribe("heartbeats", "heartbeat", "control"))
+
+		assert.Equal(t, amqp.Table{
+			amqp.QueueMessageTTLArg:  int64(30000),
+			"x-dead-letter-exchange": "request.dlx",
+		}, conn.channel.queueArgs[c.cfg.Queue.Request])
+		assert.Nil(t, conn.channel.queueArgs["control"], "only request queues expire")
+
+		assert.Equal(t, amqp.ExchangeFanout, conn.channel.kinds["request.dlx"])
+		assert.Contains(t, conn.channel.bindings, binding{queue: "request.dlq", exchange: "request.dlx"})
+	})
+
+	t.Run("queues take no arguments by default", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+
+		require.NoError(t, c.Subscribe("request", "request.*", c.cfg.Queue.Request))
+
+		assert.Nil(t, conn.channel.queueArgs[c.cfg.Queue.Request])
+		assert.Equal(t, []string{c.cfg.Queue.Request}, conn.channel.queues)
+	})
+}
+
+func TestConsumer_MaxAge(t *testing.T) {
+	cfg := testConfig()
+	cfg.Delivery.ManualAck = true
+	cfg.Expiry.MaxAge = 60
+
+	c, _ := setupConsumer(t, nil)
+	c.cfg = cfg
+
+	acks := new(fakeAcknowledger)
+	delivery := func(tag uint64, sent time.Time) amqp.Delivery {
+		event := entity.NewEvent("request.form.created", []byte(`{}`))
+		event.Timestamp = sent
+		body, _ := json.Marshal(event)
+		return amqp.Delivery{Acknowledger: acks, DeliveryTag: tag, Body: body}
+	}
+
+	out := make(chan entity.Event, 3)
+
+	require.NoError(t, c.processMessage(delivery(1, time.Now().Add(-time.Hour)), out))
+	require.NoError(t, c.processMessage(delivery(2, time.Now().Add(-time.Second)), out))
+	require.NoError(t, c.processMessage(delivery(3, time.Time{}), out))
+
+	assert.Equal(t, []uint64{1}, acks.rejected, "dead-lettered unhandled")
+	assert.Len(t, out, 2)
+}
+
+func TestConsumer_DeadLetterDepth(t *testing.T) {
+	c, conn := setupConsumer(t, nil)
+
+	_, err := c.DeadLetterDepth()
+	assert.ErrorContains(t, err, "no dead letter queue")
+
+	c.cfg.Expiry.DeadLetterExchange = "request.dlx"
+	c.cfg.Expiry.DeadLetterQueue = "request.dlq"
+	conn.channel.depth = map[string]amqp.Queue{"request.dlq": {Messages: 7}}
+
+	depth, err := c.DeadLetterDepth()
+	require.NoError(t, err)
+	assert.Equal(t, 7, depth)
+}
@@ -0,0 +1,37 @@
+package consumer
+
+import (
+	"fmt"
+	"time"
+)
+
+// LOOPBACK_QUEUE_EXPIRY is how long the broker keeps the loopback queue of an
+// instance that stopped consuming it
+const LOOPBACK_QUEUE_EXPIRY = 10 * time.Minute
+
+// ConsumeLoopback binds queue to the output exchange on each of routingKeys
+// and consumes it alongside the request queues, so the events the service
+// publishes come back to it, see package loopback. Every instance needs a
+// queue of its own; the queues of stopped instances expire.
+// It must be called before ConsumeMessages.
+func (c *Consumer) ConsumeLoopback(queue string, routingKeys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.isConnected {
+		return fmt.Errorf("consumer is not connected")
+	}
+
+	c.loopback = queue
+
+	for _, routingKey := range routingKeys {
+		if err := c.bindQueue(binding{queue: queue, routingKey: routingKey, exchange: c.cfg.Exchange.Output}); err != nil {
+			return err
+		}
+	}
+
+	// Track the exchange so it survives reconnection
+	c.exchanges[c.cfg.Exchange.Output] = true
+
+	return nil
+}
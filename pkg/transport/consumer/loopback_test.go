@@ -0,0 +1,35 @@
+package consumer
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumer_ConsumeLoopback(t *testing.T) {
+	t.Run("binds an expiring queue to the output exchange", func(t *testing.T) {
+		c, conn := setupConsumer(t, nil)
+		c.cfg.Expiry.DeadLetterExchange = "request.dlx"
+
+		require.NoError(t, c.ConsumeLoopback("loopback.host", []string{"form.created", "form.updated"}))
+
+		assert.Equal(t, []binding{
+			{queue: "loopback.host", routingKey: "form.created", exchange: c.cfg.Exchange.Output},
+			{queue: "loopback.host", routingKey: "form.updated", exchange: c.cfg.Exchange.Output},
+		}, conn.channel.bindings)
+		assert.Equal(t, amqp.Table{"x-expires": int64(600000)}, conn.channel.queueArgs["loopback.host"])
+		assert.NotContains(t, conn.channel.kinds, "request.dlx", "the loopback queue isn't dead-lettered")
+		assert.True(t, c.consumesQueue("loopback.host"))
+	})
+
+	t.Run("fails when disconnected", func(t *testing.T) {
+		c, _ := setupConsumer(t, nil)
+		require.NoError(t, c.Close())
+
+		err := c.ConsumeLoopback("loopback.host", []string{"form.created"})
+
+		assert.ErrorContains(t, err, "not connected")
+	})
+}
@@ -0,0 +1,49 @@
+package consumer
+
+import (
+	"context"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"go.uber.org/zap"
+)
+
+// SINK_TIMEOUT bounds a single write to the sink
+const SINK_TIMEOUT = 5 * time.Second
+
+// Sink durably stores a received event, e.g. stream.Buffer.Append
+type Sink func(ctx context.Context, event entity.Event) error
+
+// SetSink makes the consumer write received events to sink instead of the
+// output channel. Messages are acknowledged on delivery, so an event written
+// to the sink survives a crash of the process before it was handled. Events
+// the sink fails to store are sent to the output channel as before.
+func (c *Consumer) SetSink(sink Sink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sink = sink
+}
+
+// sendToSink writes event to the sink and reports whether it was stored
+func (c *Consumer) sendToSink(event entity.Event) bool {
+	c.mu.RLock()
+	sink := c.sink
+	c.mu.RUnlock()
+
+	if sink == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SINK_TIMEOUT)
+	defer cancel()
+
+	if err := sink(ctx, event); err != nil {
+		c.logger.Warn("failed to write event to sink, sending it to the output channel",
+			zap.String("event_id", event.ID),
+			zap.Error(err))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,89 @@
+// Package envelope encodes and decodes the envelope events travel in. Early
+// consumers were built against the Go default casing of its timestamp,
+// TimeStamp, while the service now writes timestamp. Both casings are read,
+// and the casing written is configurable, so those consumers keep working
+// until they are migrated; uses of the old casing are counted to tell when
+// that is done.
+package envelope
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+)
+
+// Casings of the envelope fields
+const (
+	STYLE_SNAKE  = "snake"  // timestamp
+	STYLE_LEGACY = "legacy" // TimeStamp, deprecated
+)
+
+type (
+	// plain is an event without its JSON methods
+	plain entity.Event
+
+	// legacyEvent is an event with the deprecated casing. Timestamp,
+	// shallower than the field of the embedded event, hides it, so only
+	// TimeStamp carries the timestamp.
+	legacyEvent struct {
+		plain
+		Timestamp *time.Time `json:"timestamp,omitempty"`
+		TimeStamp *time.Time `json:"TimeStamp,omitempty"`
+	}
+
+	// anyEvent reads an event in either casing. Exact matches win over the
+	// case-insensitive match of encoding/json, so each casing lands in its
+	// own field.
+	anyEvent struct {
+		plain
+		TimeStamp *time.Time `json:"TimeStamp,omitempty"`
+	}
+)
+
+// ValidStyle checks that style is a known casing, empty meaning STYLE_SNAKE
+func ValidStyle(style string) error {
+	switch style {
+	case "", STYLE_SNAKE, STYLE_LEGACY:
+		return nil
+	default:
+		return fmt.Errorf("unknown envelope style %q", style)
+	}
+}
+
+// Marshal encodes event with the field casing of style, STYLE_SNAKE when empty
+func Marshal(event *entity.Event, style string) ([]byte, error) {
+	if style != STYLE_LEGACY {
+		return json.Marshal((*plain)(event))
+	}
+
+	metrics.LegacyEnvelopes.WithLabelValues("write").Inc()
+
+	timestamp := event.Timestamp
+	return json.Marshal(legacyEvent{plain: plain(*event), TimeStamp: &timestamp})
+}
+
+// Unmarshal decodes an event written in either casing into event
+// Returns whether it used the deprecated casing
+func Unmarshal(data []byte, event *entity.Event) (bool, error) {
+	var decoded anyEvent
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return false, err
+	}
+
+	*event = entity.Event(decoded.plain)
+
+	if decoded.TimeStamp == nil {
+		return false, nil
+	}
+
+	metrics.LegacyEnvelopes.WithLabelValues("read").Inc()
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = *decoded.TimeStamp
+	}
+
+	return true, nil
+}
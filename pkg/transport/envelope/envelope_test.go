@@ -0,0 +1,66 @@
+package envelope
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := &entity.Event{ID: "1", Type: "form.created", Payload: []byte(`{}`), Timestamp: at}
+
+	t.Run("snake", func(t *testing.T) {
+		data, err := Marshal(event, "")
+		require.NoError(t, err)
+
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal(data, &fields))
+		assert.Contains(t, fields, "timestamp")
+		assert.NotContains(t, fields, "TimeStamp")
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		data, err := Marshal(event, STYLE_LEGACY)
+		require.NoError(t, err)
+
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal(data, &fields))
+		assert.Contains(t, fields, "TimeStamp")
+		assert.NotContains(t, fields, "timestamp")
+		assert.Equal(t, "1", fields["id"], "other fields keep their casing")
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, style := range []string{STYLE_SNAKE, STYLE_LEGACY} {
+		t.Run(style, func(t *testing.T) {
+			data, err := Marshal(&entity.Event{ID: "1", Type: "form.created", Payload: []byte(`{}`), Timestamp: at}, style)
+			require.NoError(t, err)
+
+			var event entity.Event
+			legacy, err := Unmarshal(data, &event)
+
+			require.NoError(t, err)
+			assert.Equal(t, style == STYLE_LEGACY, legacy)
+			assert.True(t, at.Equal(event.Timestamp))
+			assert.Equal(t, "form.created", event.Type)
+			assert.Equal(t, []byte(`{}`), event.Payload)
+		})
+	}
+
+	_, err := Unmarshal([]byte(`{`), new(entity.Event))
+	assert.Error(t, err)
+}
+
+func TestValidStyle(t *testing.T) {
+	assert.NoError(t, ValidStyle(""))
+	assert.NoError(t, ValidStyle(STYLE_LEGACY))
+	assert.Error(t, ValidStyle("camel"))
+}
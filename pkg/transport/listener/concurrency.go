@@ -0,0 +1,108 @@
+package listener
+
+import (
+	"sync"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+)
+
+// MAX_DEFERRED_EVENTS bounds the events held back because their type is at
+// its concurrency limit. Once reached the listener stops taking new events
+// until the limited handlers catch up.
+const MAX_DEFERRED_EVENTS = 1000
+
+// limiter caps how many events of a type are handled at once. Events over
+// the cap are deferred instead of occupying a worker, so a storm of heavy
+// requests leaves the other workers to cheap ones.
+type limiter struct {
+	limits map[string]int // Concurrency cap by event type
+
+	mu       sync.Mutex
+	running  map[string]int
+	deferred map[string][]entity.Event
+	pending  int
+
+	wake chan struct{} // Signalled when a limited handler finished
+}
+
+func newLimiter(limits map[string]int) *limiter {
+	return &limiter{
+		limits:   limits,
+		running:  make(map[string]int),
+		deferred: make(map[string][]entity.Event),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// admit reports whether event may be handled now, otherwise it is deferred
+// until a handler of its type finishes
+func (l *limiter) admit(event entity.Event) bool {
+	limit, ok := l.limits[event.Type]
+	if !ok || limit <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.running[event.Type] < limit {
+		l.running[event.Type]++
+		return true
+	}
+
+	l.deferred[event.Type] = append(l.deferred[event.Type], event)
+	l.pending++
+
+	return false
+}
+
+// ready takes the deferred events that fit under their limits again
+func (l *limiter) ready() []entity.Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var events []entity.Event
+
+	for eventType, queued := range l.deferred {
+		n := min(l.limits[eventType]-l.running[eventType], len(queued))
+		if n <= 0 {
+			continue
+		}
+
+		events = append(events, queued[:n]...)
+		l.running[eventType] += n
+		l.pending -= n
+
+		if n == len(queued) {
+			delete(l.deferred, eventType)
+		} else {
+			l.deferred[eventType] = queued[n:]
+		}
+	}
+
+	return events
+}
+
+// release marks a handler of eventType finished
+func (l *limiter) release(eventType string) {
+	if limit, ok := l.limits[eventType]; !ok || limit <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	l.running[eventType]--
+	l.mu.Unlock()
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// backlog is the number of deferred events
+func (l *limiter) backlog() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.pending
+}
@@ -0,0 +1,88 @@
+package listener
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestListener_SetConcurrency(t *testing.T) {
+	cfg, err := config.Init("")
+	require.NoError(t, err)
+
+	events := make(chan entity.Event, 20)
+	list := Init(events, &logger.Logger{Logger: zap.NewNop()}, cfg, nil)
+	list.SetConcurrency(3, map[string]int{"request.import": 1})
+
+	release := make(chan struct{})
+	var imports, maxImports atomic.Int32
+	deleted := make(chan struct{}, 5)
+
+	list.Handle("request.import", "import", func(context.Context, entity.Event) error {
+		n := imports.Add(1)
+		defer imports.Add(-1)
+
+		for {
+			current := maxImports.Load()
+			if n <= current || maxImports.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		<-release
+		return nil
+	})
+	list.Handle("request.delete", "delete", func(context.Context, entity.Event) error {
+		deleted <- struct{}{}
+		return nil
+	})
+
+	go list.Listen(context.Background())
+
+	for range 5 {
+		events <- entity.Event{Type: "request.import", Actor: "alice"}
+	}
+	for range 5 {
+		events <- entity.Event{Type: "request.delete", Actor: "alice"}
+	}
+
+	for range 5 {
+		select {
+		case <-deleted:
+		case <-time.After(time.Second):
+			t.Fatal("deletes starved by imports")
+		}
+	}
+
+	close(release)
+	require.NoError(t, list.Close())
+
+	assert.Equal(t, int32(1), maxImports.Load())
+	assert.Equal(t, int32(0), imports.Load(), "close waits for deferred imports")
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(map[string]int{"request.import": 2})
+
+	assert.True(t, l.admit(entity.Event{Type: "request.delete"}), "unlimited types are always admitted")
+	assert.True(t, l.admit(entity.Event{ID: "1", Type: "request.import"}))
+	assert.True(t, l.admit(entity.Event{ID: "2", Type: "request.import"}))
+	assert.False(t, l.admit(entity.Event{ID: "3", Type: "request.import"}))
+	assert.Equal(t, 1, l.backlog())
+	assert.Empty(t, l.ready())
+
+	l.release("request.import")
+
+	ready := l.ready()
+	require.Len(t, ready, 1)
+	assert.Equal(t, "3", ready[0].ID)
+	assert.Equal(t, 0, l.backlog())
+}
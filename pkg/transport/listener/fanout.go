@@ -0,0 +1,158 @@
+package listener
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/service"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+type (
+	// subscriber is a handler an event is fanned out to besides the handler
+	// of its request type
+	subscriber struct {
+		name    string
+		handler Handler
+		retry   service.RetryPolicy
+	}
+
+	// DeadLetters receives the requests subscribers gave up on
+	DeadLetters func(letter *entity.DeadLetter) error
+)
+
+// Subscribe registers handler, identified by name in logs and metrics, as
+// one more handler of the events of requestType. Every event is fanned out
+// to the handler registered by Handle and to all subscribers concurrently,
+// and is settled once they all returned. Subscribers are isolated from the
+// handler and from each other: their failures, panics included, never fail
+// the event. A subscriber failing is retried by retry, then the event is
+// dead-lettered to it, see OnDeadLetter. Dry-run events only reach the
+// handler, see HandleDryRun. It must be called before Listen.
+func (list *Listener) Subscribe(requestType, name string, handler Handler, retry service.RetryPolicy) {
+	list.subscribers[requestType] = append(list.subscribers[requestType], subscriber{
+		name:    name,
+		handler: handler,
+		retry:   retry,
+	})
+}
+
+// OnDeadLetter registers where the requests subscribers gave up on are
+// sent, e.g. service.PublishDeadLetter; without one they are only logged.
+// It must be called before Listen.
+func (list *Listener) OnDeadLetter(deadLetters DeadLetters) {
+	list.deadLetters = deadLetters
+}
+
+// fanout runs handler, nil for none, and every subscriber on each event
+// but dry-run ones. Returns what handler returned
+func (list *Listener) fanout(handler Handler, subscribers []subscriber) Handler {
+	return func(ctx context.Context, event entity.Event) error {
+		var wg sync.WaitGroup
+
+		fanned := subscribers
+		if event.DryRun {
+			fanned = nil
+		}
+
+		for _, sub := range fanned {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				list.deliver(ctx, sub, event)
+			}()
+		}
+
+		var err error
+		if handler != nil {
+			err = handler(ctx, event)
+		}
+
+		wg.Wait()
+
+		return err
+	}
+}
+
+// deliver calls a subscriber with event until it succeeds, refuses the
+// event or runs out of retries, then dead-letters events it failed
+func (list *Listener) deliver(ctx context.Context, sub subscriber, event entity.Event) {
+	attempts := int(sub.retry.Retries) + 1
+
+	var (
+		err     error
+		attempt int
+	)
+
+	for attempt = 1; ; attempt++ {
+		err = isolated(ctx, sub.handler, event)
+		if Outcome(err) != OUTCOME_ERROR || attempt == attempts || !pause(ctx, sub.retry.Delay) {
+			break
+		}
+	}
+
+	// Refused events end the same way every time
+	if outcome := Outcome(err); outcome == OUTCOME_OK || outcome == OUTCOME_REJECTED {
+		return
+	}
+
+	metrics.ListenerDeadLetters.WithLabelValues(sub.name).Inc()
+
+	fields := []zap.Field{
+		zap.String("subscriber", sub.name),
+		zap.String("event_id", event.ID),
+		zap.String("event_type", event.Type),
+		zap.Int("attempts", attempt),
+		zap.Error(err),
+	}
+
+	if list.deadLetters == nil {
+		list.logger.Error("subscriber gave up on event", fields...)
+		return
+	}
+
+	letter := &entity.DeadLetter{
+		Handler:       sub.name,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		CorrelationID: event.Correlation(),
+		Actor:         event.Actor,
+		Payload:       event.Payload,
+		Error:         err.Error(),
+		Attempts:      attempt,
+		FailedAt:      time.Now(),
+	}
+
+	if err := list.deadLetters(letter); err != nil {
+		list.logger.Error("failed to dead-letter event", append(fields, zap.NamedError("dead_letter_error", err))...)
+	}
+}
+
+// pause waits for d
+// Returns false if ctx was cancelled meanwhile
+func pause(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// isolated calls handler, returning panics as errors
+func isolated(ctx context.Context, handler Handler, event entity.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, event)
+}
@@ -0,0 +1,128 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/service"
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestListener_Subscribe(t *testing.T) {
+	cfg, err := config.Init("")
+	require.NoError(t, err)
+
+	setup := func() (*Listener, func() []*entity.DeadLetter) {
+		list := Init(make(chan entity.Event), &logger.Logger{Logger: zap.NewNop()}, cfg, nil)
+
+		var (
+			mu      sync.Mutex
+			letters []*entity.DeadLetter
+		)
+		list.OnDeadLetter(func(letter *entity.DeadLetter) error {
+			mu.Lock()
+			defer mu.Unlock()
+			letters = append(letters, letter)
+			return nil
+		})
+
+		return list, func() []*entity.DeadLetter {
+			mu.Lock()
+			defer mu.Unlock()
+			return letters
+		}
+	}
+
+	event := entity.Event{ID: "evt-1", Type: "request.test", Payload: []byte(`{"form_id":"f"}`), Actor: "alice"}
+
+	t.Run("runs every subscriber and isolates their failures", func(t *testing.T) {
+		list, letters := setup()
+
+		var handled, flaky, broken, refusing atomic.Int32
+		list.Handle("request.test", "test", func(context.Context, entity.Event) error {
+			handled.Add(1)
+			return nil
+		})
+		list.Subscribe("request.test", "flaky", func(context.Context, entity.Event) error {
+			if flaky.Add(1) == 1 {
+				return errors.New("index down")
+			}
+			return nil
+		}, service.RetryPolicy{Retries: 1})
+		list.Subscribe("request.test", "broken", func(context.Context, entity.Event) error {
+			broken.Add(1)
+			panic("nil map")
+		}, service.RetryPolicy{Retries: 2})
+		list.Subscribe("request.test", "refusing", func(context.Context, entity.Event) error {
+			refusing.Add(1)
+			return rejected(errors.New("not for me"))
+		}, service.RetryPolicy{Retries: 2})
+
+		require.NoError(t, list.handlers()["request.test"](context.Background(), event))
+
+		assert.EqualValues(t, 1, handled.Load())
+		assert.EqualValues(t, 2, flaky.Load(), "failures are retried")
+		assert.EqualValues(t, 3, broken.Load())
+		assert.EqualValues(t, 1, refusing.Load(), "refusals aren't retried")
+
+		require.Len(t, letters(), 1)
+		letter := letters()[0]
+		assert.Equal(t, "broken", letter.Handler)
+		assert.Equal(t, "evt-1", letter.EventID)
+		assert.Equal(t, "alice", letter.Actor)
+		assert.JSONEq(t, `{"form_id":"f"}`, string(letter.Payload))
+		assert.Equal(t, 3, letter.Attempts)
+		assert.Contains(t, letter.Error, "handler panicked: nil map")
+	})
+
+	t.Run("returns what the handler of the request type returned", func(t *testing.T) {
+		list, letters := setup()
+
+		list.Handle("request.test", "test", func(context.Context, entity.Event) error {
+			return errors.New("db down")
+		})
+		list.Subscribe("request.test", "index", func(context.Context, entity.Event) error {
+			return nil
+		}, service.RetryPolicy{})
+
+		assert.EqualError(t, list.handlers()["request.test"](context.Background(), event), "db down")
+		assert.Empty(t, letters())
+	})
+
+	t.Run("fans out request types without a handler", func(t *testing.T) {
+		list, letters := setup()
+
+		list.Subscribe("request.test", "index", func(context.Context, entity.Event) error {
+			return errors.New("index down")
+		}, service.RetryPolicy{})
+
+		assert.NoError(t, list.handlers()["request.test"](context.Background(), event))
+		require.Len(t, letters(), 1)
+		assert.Equal(t, 1, letters()[0].Attempts)
+	})
+
+	t.Run("stops retrying once cancelled", func(t *testing.T) {
+		list, letters := setup()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls atomic.Int32
+		list.Subscribe("request.test", "index", func(context.Context, entity.Event) error {
+			calls.Add(1)
+			cancel()
+			return errors.New("index down")
+		}, service.RetryPolicy{Retries: 5, Delay: time.Hour})
+
+		assert.NoError(t, list.handlers()["request.test"](ctx, event))
+		assert.EqualValues(t, 1, calls.Load())
+		assert.Len(t, letters(), 1)
+	})
+}
@@ -0,0 +1,1709 @@
+package listener
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/question"
+	"github.com/Koyo-os/form-service/internal/service"
+	"github.com/Koyo-os/form-service/pkg/heartbeat"
+	"github.com/Koyo-os/form-service/pkg/loopback"
+	"github.com/Koyo-os/form-service/pkg/webhook"
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+)
+
+// decode unmarshals the event payload into req
+func decode(event entity.Event, req any) error {
+	if err := sonic.Unmarshal(event.Payload, req); err != nil {
+		return invalid(fmt.Errorf("failed to unmarshal payload: %w", err))
+	}
+
+	return nil
+}
+
+// parseID parses an ID named what in the request
+func parseID(what, id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, invalid(fmt.Errorf("failed to parse %s: %w", what, err))
+	}
+
+	return parsed, nil
+}
+
+// handleCreateForm handles form creation events
+func (list *Listener) handleCreateForm(_ context.Context, event entity.Event) error {
+	form := new(entity.Form)
+
+	if err := json.Unmarshal(event.Payload, &form); err != nil {
+		return invalid(fmt.Errorf("failed to unmarshal payload to form: %w", err))
+	}
+
+	if err := list.as(event).CreateFormOnce(event.ID, event.Type, form); err != nil {
+		var quotaErr *service.QuotaExceededError
+		if errors.As(err, &quotaErr) || errors.Is(err, service.ErrSlugTaken) {
+			return rejected(err)
+		}
+		if errors.Is(err, entity.ErrInvalidRules) || errors.Is(err, entity.ErrInvalidNumbering) ||
+			errors.Is(err, entity.ErrInvalidLocale) || errors.Is(err, entity.ErrInvalidSettings) ||
+			errors.Is(err, question.ErrInvalidDefault) || errors.Is(err, entity.ErrInvalidTransition) ||
+			errors.Is(err, entity.ErrInvalidSlug) || errors.Is(err, entity.ErrInvalidFormat) ||
+			errors.Is(err, entity.ErrInvalidMedia) || errors.Is(err, entity.ErrInvalidTheme) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to create form: %w", err)
+	}
+
+	return nil
+}
+
+// handleUpdateForm handles form update events
+func (list *Listener) handleUpdateForm(_ context.Context, event entity.Event) error {
+	form := new(entity.Form)
+
+	if err := json.Unmarshal(event.Payload, &form); err != nil {
+		return invalid(fmt.Errorf("failed to unmarshal payload to form: %w", err))
+	}
+
+	if err := list.as(event).Update(form.ID, form); err != nil {
+		var conflict *entity.VersionConflictError
+		if errors.As(err, &conflict) || errors.Is(err, service.ErrSlugTaken) {
+			return rejected(err)
+		}
+		if errors.Is(err, entity.ErrInvalidSlug) || errors.Is(err, entity.ErrInvalidFormat) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to update form %s: %w", form.ID, err)
+	}
+
+	return nil
+}
+
+// handleSaveForm handles editor saves of a whole form with its questions
+func (list *Listener) handleSaveForm(_ context.Context, event entity.Event) error {
+	form := new(entity.Form)
+
+	if err := json.Unmarshal(event.Payload, &form); err != nil {
+		return invalid(fmt.Errorf("failed to unmarshal payload to form: %w", err))
+	}
+
+	if err := list.as(event).SaveForm(form); err != nil {
+		var conflict *entity.VersionConflictError
+		var quotaErr *service.QuotaExceededError
+		if errors.As(err, &conflict) || errors.As(err, &quotaErr) || errors.Is(err, service.ErrSlugTaken) {
+			return rejected(err)
+		}
+		if errors.Is(err, entity.ErrInvalidRules) || errors.Is(err, entity.ErrInvalidNumbering) ||
+			errors.Is(err, entity.ErrInvalidLocale) || errors.Is(err, entity.ErrInvalidSettings) ||
+			errors.Is(err, question.ErrInvalidDefault) || errors.Is(err, entity.ErrInvalidSlug) ||
+			errors.Is(err, entity.ErrInvalidFormat) || errors.Is(err, entity.ErrInvalidMedia) ||
+			errors.Is(err, entity.ErrInvalidTheme) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to save form %s: %w", form.ID, err)
+	}
+
+	return nil
+}
+
+// handleDeleteForm handles form deletion events
+func (list *Listener) handleDeleteForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if event.DryRun {
+		return list.reportDryRun(event, list.as(event).DryRunDeleteForm(id))
+	}
+
+	if err = list.as(event).DeleteForm(id); err != nil {
+		return fmt.Errorf("failed to delete form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleRestoreForm handles requests to take a form out of the trash
+func (list *Listener) handleRestoreForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).RestoreForm(id); err != nil {
+		var quotaErr *service.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return rejected(err)
+		}
+		if errors.Is(err, service.ErrNotInTrash) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to restore form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handlePurgeForm handles requests to remove a trashed form for good
+func (list *Listener) handlePurgeForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).PurgeForm(id); err != nil {
+		if errors.Is(err, service.ErrNotInTrash) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to purge form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleArchiveForm handles requests to archive a form
+func (list *Listener) handleArchiveForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).ArchiveForm(id); err != nil {
+		if errors.Is(err, entity.ErrInvalidTransition) {
+			return rejected(err)
+		}
+
+		return fmt.Errorf("failed to archive form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleUnarchiveForm handles requests to bring an archived form back
+func (list *Listener) handleUnarchiveForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).UnarchiveForm(id); err != nil {
+		if errors.Is(err, entity.ErrInvalidTransition) {
+			return rejected(err)
+		}
+
+		return fmt.Errorf("failed to unarchive form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleSetStatus handles moves of a form between the states of its lifecycle
+func (list *Listener) handleSetStatus(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+		Status string `json:"status"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).SetStatus(id, req.Status); err != nil {
+		var conflict *entity.VersionConflictError
+		if errors.As(err, &conflict) || errors.Is(err, entity.ErrInvalidTransition) {
+			return rejected(err)
+		}
+		if errors.Is(err, entity.ErrInvalidStatus) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to set status of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleSetSchedule handles changes of when a form opens and closes
+func (list *Listener) handleSetSchedule(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID   string     `json:"form_id"`
+		OpensAt  *time.Time `json:"opens_at"`
+		ClosesAt *time.Time `json:"closes_at"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).SetSchedule(id, req.OpensAt, req.ClosesAt); err != nil {
+		if errors.Is(err, entity.ErrInvalidSchedule) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to set schedule of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleDeleteQuestion handles question deletion events. A question_id
+// selects the question directly; otherwise the legacy form_id + order_number
+// pair is used
+func (list *Listener) handleDeleteQuestion(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		QuestionID  string `json:"question_id"`
+		FormID      string `json:"form_id"`
+		OrderNumber uint   `json:"order_number"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	if req.QuestionID != "" {
+		questionID, err := parseID("question id", req.QuestionID)
+		if err != nil {
+			return err
+		}
+
+		if event.DryRun {
+			return list.reportDryRun(event, list.as(event).DryRunDeleteQuestionByID(questionID))
+		}
+
+		if err = list.as(event).DeleteQuestionByID(questionID); err != nil {
+			return fmt.Errorf("failed to delete question %s: %w", questionID, err)
+		}
+
+		return nil
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if event.DryRun {
+		return list.reportDryRun(event, list.as(event).DryRunDeleteQuestion(id, req.OrderNumber))
+	}
+
+	if err = list.as(event).DeleteQuestion(id, req.OrderNumber); err != nil {
+		return fmt.Errorf("failed to delete question %d of form %s: %w", req.OrderNumber, id, err)
+	}
+
+	return nil
+}
+
+// handleRequestUpload handles upload URL requests for file questions
+func (list *Listener) handleRequestUpload(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		QuestionID  string `json:"question_id"`
+		FileName    string `json:"file_name"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	questionID, err := parseID("question id", req.QuestionID)
+	if err != nil {
+		return err
+	}
+
+	ticket, err := list.as(event).RequestUpload(questionID, req.FileName, req.ContentType, req.Size)
+	if err != nil {
+		return fmt.Errorf("failed to request upload for question %s: %w", questionID, err)
+	}
+
+	ticket.RequestID = event.ID
+
+	if err = list.as(event).PublishUploadTicket(ticket); err != nil {
+		return fmt.Errorf("failed to publish upload ticket %s: %w", ticket.UploadID, err)
+	}
+
+	return nil
+}
+
+// handleCompleteUpload handles notifications that a file was uploaded
+func (list *Listener) handleCompleteUpload(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		UploadID string `json:"upload_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("upload id", req.UploadID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = list.as(event).CompleteUpload(id); err != nil {
+		return fmt.Errorf("failed to complete upload %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleSaveDraft handles saved drafts. Only the position is used, for
+// progress events
+func (list *Listener) handleSaveDraft(_ context.Context, event entity.Event) error {
+	if !list.cfg.Features["progress_events"] {
+		return nil
+	}
+
+	req := new(struct {
+		FormID        string `json:"form_id"`
+		RespondentID  string `json:"respondent_id"`
+		QuestionIndex uint   `json:"question_index"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).TrackDraftProgress(id, req.RespondentID, req.QuestionIndex); err != nil {
+		return fmt.Errorf("failed to track draft progress of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleSetAccess handles changes of the respondent restrictions of a form
+func (list *Listener) handleSetAccess(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID         string   `json:"form_id"`
+		AllowedDomains []string `json:"allowed_domains"`
+		InviteOnly     bool     `json:"invite_only"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).SetAccess(id, req.AllowedDomains, req.InviteOnly); err != nil {
+		return fmt.Errorf("failed to set access of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleCreateInvite handles invite token generation
+func (list *Listener) handleCreateInvite(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID     string `json:"form_id"`
+		Email      string `json:"email"`
+		SingleUse  bool   `json:"single_use"`
+		TTLSeconds int    `json:"ttl_seconds"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	opts := service.InviteOptions{
+		SingleUse: req.SingleUse,
+		TTL:       time.Duration(req.TTLSeconds) * time.Second,
+	}
+
+	if _, err = list.as(event).CreateInvite(id, req.Email, opts); err != nil {
+		return fmt.Errorf("failed to create invite for form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleRevokeInvite handles invite token revocation
+func (list *Listener) handleRevokeInvite(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		InviteID string `json:"invite_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("invite id", req.InviteID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).RevokeInvite(id); err != nil {
+		return fmt.Errorf("failed to revoke invite %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleListInvites handles invite listing for form admins
+func (list *Listener) handleListInvites(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	invites, err := list.as(event).ListInvites(id)
+	if err != nil {
+		return fmt.Errorf("failed to list invites of form %s: %w", id, err)
+	}
+
+	if err = list.as(event).PublishInviteList(&entity.InviteList{
+		RequestID: event.ID,
+		FormID:    req.FormID,
+		Invites:   invites,
+	}); err != nil {
+		return fmt.Errorf("failed to publish invite list of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleTenantSettings handles per-tenant setting overrides. Omitted
+// settings use the defaults
+func (list *Listener) handleTenantSettings(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		TenantID      string `json:"tenant_id"`
+		RateLimit     *int   `json:"rate_limit"`
+		MaxQuestions  *int   `json:"max_questions"`
+		RetentionDays *int   `json:"retention_days"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	if err := list.as(event).UpdateTenantSettings(&entity.TenantSettings{
+		TenantID:      req.TenantID,
+		RateLimit:     req.RateLimit,
+		MaxQuestions:  req.MaxQuestions,
+		RetentionDays: req.RetentionDays,
+	}); err != nil {
+		return fmt.Errorf("failed to update settings of tenant %s: %w", req.TenantID, err)
+	}
+
+	return nil
+}
+
+// handleQuotaGet handles quota lookups, the quota is sent back to the requester
+func (list *Listener) handleQuotaGet(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		Author string `json:"author"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	quota, err := list.as(event).GetQuota(req.Author)
+	if err != nil {
+		return fmt.Errorf("failed to get quota of %s: %w", req.Author, err)
+	}
+
+	quota.RequestID = event.ID
+
+	if err = list.as(event).PublishQuota(quota); err != nil {
+		return fmt.Errorf("failed to publish quota of %s: %w", req.Author, err)
+	}
+
+	return nil
+}
+
+// handleQuotaUpdate handles admin quota overrides. Omitted limits use the
+// defaults
+func (list *Listener) handleQuotaUpdate(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		Author       string `json:"author"`
+		MaxForms     *int   `json:"max_forms"`
+		MaxQuestions *int   `json:"max_questions"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	if err := list.as(event).UpdateQuota(&entity.AuthorQuota{
+		Author:       req.Author,
+		MaxForms:     req.MaxForms,
+		MaxQuestions: req.MaxQuestions,
+	}); err != nil {
+		return fmt.Errorf("failed to update quota of %s: %w", req.Author, err)
+	}
+
+	return nil
+}
+
+// handleQuestionStats handles question statistics lookups for dashboards
+func (list *Listener) handleQuestionStats(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	report, err := list.as(event).QuestionStats(id)
+	if err != nil {
+		return fmt.Errorf("failed to get question stats of form %s: %w", id, err)
+	}
+
+	report.RequestID = event.ID
+
+	if err = list.as(event).PublishQuestionStats(report); err != nil {
+		return fmt.Errorf("failed to publish question stats of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleSubmitResponse handles submitted responses. Rejections are
+// published by the service
+func (list *Listener) handleSubmitResponse(_ context.Context, event entity.Event) error {
+	submission := new(entity.ResponseSubmission)
+
+	if err := decode(event, submission); err != nil {
+		return err
+	}
+
+	submission.RequestID = event.ID
+
+	if err := list.as(event).SubmitResponse(submission); err != nil {
+		if errors.Is(err, service.ErrInvalidResponse) || errors.Is(err, service.ErrAccessDenied) || errors.Is(err, service.ErrRateLimited) {
+			return rejected(err)
+		}
+
+		return fmt.Errorf("failed to submit response to form %s: %w", submission.FormID, err)
+	}
+
+	return nil
+}
+
+// handleListResponses handles response listings for form owners
+func (list *Listener) handleListResponses(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string         `json:"form_id"`
+		Filter *entity.Filter `json:"filter"`
+		Offset int            `json:"offset"`
+		Limit  int            `json:"limit"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	responses, err := list.as(event).ListResponses(id, req.Filter, req.Offset, req.Limit)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidFilter) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to list responses of form %s: %w", id, err)
+	}
+
+	responses.RequestID = event.ID
+
+	if err = list.as(event).PublishResponseList(responses); err != nil {
+		return fmt.Errorf("failed to publish response list of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handlePublishForm handles publishing the current state of a form as a new version
+func (list *Listener) handlePublishForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = list.as(event).PublishForm(id); err != nil {
+		var conflict *entity.VersionConflictError
+		if errors.As(err, &conflict) || errors.Is(err, entity.ErrInvalidTransition) {
+			return rejected(err)
+		}
+
+		return fmt.Errorf("failed to publish form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleFormVersion handles lookups of published form versions. A version
+// of 0 or none returns the latest one.
+func (list *Listener) handleFormVersion(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID  string `json:"form_id"`
+		Version uint   `json:"version"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	version, err := list.as(event).GetFormVersion(id, req.Version)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve version %d of form %s: %w", req.Version, id, err)
+	}
+
+	if err = list.as(event).PublishFormVersion(&entity.FormVersionReply{
+		RequestID: event.ID,
+		Version:   version,
+	}); err != nil {
+		return fmt.Errorf("failed to publish version %d of form %s: %w", version.Version, id, err)
+	}
+
+	return nil
+}
+
+// handleFormAt handles lookups of a form as it was at a past point, a time
+// or a version, e.g. to settle what respondents were shown. Points without
+// a published version are replied without a version.
+func (list *Listener) handleFormAt(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID  string    `json:"form_id"`
+		At      time.Time `json:"at"`
+		Version uint      `json:"version"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if req.At.IsZero() && req.Version == 0 {
+		return invalid(errors.New("either at or version is required"))
+	}
+
+	version, err := list.as(event).GetFormAt(id, req.At, req.Version)
+	if err != nil && !errors.Is(err, service.ErrNoVersionAt) {
+		return fmt.Errorf("failed to retrieve form %s at %s: %w", id, req.At, err)
+	}
+
+	if err = list.as(event).PublishFormVersion(&entity.FormVersionReply{
+		RequestID: event.ID,
+		Version:   version,
+	}); err != nil {
+		return fmt.Errorf("failed to publish form %s at %s: %w", id, req.At, err)
+	}
+
+	return nil
+}
+
+// handlePublicForm handles respondent lookups of forms. Forms that aren't
+// published or are closed are replied without a form.
+func (list *Listener) handlePublicForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	form, err := list.as(event).GetPublicForm(id)
+	if err != nil && !errors.Is(err, service.ErrFormNotPublic) {
+		return fmt.Errorf("failed to retrieve public form %s: %w", id, err)
+	}
+
+	if err = list.as(event).PublishPublicForm(&entity.PublicFormReply{
+		RequestID: event.ID,
+		Form:      form,
+	}); err != nil {
+		return fmt.Errorf("failed to publish public form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleFormMetadata handles public lookups of the metadata of forms by
+// slug. Like respondent lookups they need no actor; forms that aren't public
+// are replied without metadata.
+func (list *Listener) handleFormMetadata(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		Slug string `json:"slug"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	metadata, err := list.as(event).GetFormMetadata(req.Slug)
+	if errors.Is(err, entity.ErrInvalidSlug) {
+		return invalid(err)
+	}
+	if err != nil && !errors.Is(err, service.ErrFormNotPublic) {
+		return fmt.Errorf("failed to retrieve metadata of form %s: %w", req.Slug, err)
+	}
+
+	if err = list.as(event).PublishFormMetadata(&entity.FormMetadataReply{
+		RequestID: event.ID,
+		Slug:      req.Slug,
+		Metadata:  metadata,
+	}); err != nil {
+		return fmt.Errorf("failed to publish metadata of form %s: %w", req.Slug, err)
+	}
+
+	return nil
+}
+
+// handleSetWorkers handles admin resizes of the worker pool. Requests are
+// shared by every instance, so the instance taking the event resizes its
+// pool. An event naming another instance is refused and resizes nothing.
+func (list *Listener) handleSetWorkers(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		Workers  int    `json:"workers"`
+		Instance string `json:"instance"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	if req.Workers < 1 || req.Workers > MAX_WORKERS {
+		return invalid(fmt.Errorf("workers must be between 1 and %d, got %d", MAX_WORKERS, req.Workers))
+	}
+
+	if req.Instance != "" && req.Instance != list.instance {
+		return rejected(fmt.Errorf("addressed to instance %s", req.Instance))
+	}
+
+	list.SetWorkers(req.Workers)
+
+	return nil
+}
+
+// handleInspectCache handles admin lookups of what the cache holds for a form
+func (list *Listener) handleInspectCache(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	entry, err := list.as(event).InspectCache(id)
+	if err != nil {
+		return fmt.Errorf("failed to inspect cached form %s: %w", id, err)
+	}
+
+	if err = list.as(event).PublishCacheEntry(&entity.CacheEntryReply{
+		RequestID: event.ID,
+		Entry:     entry,
+	}); err != nil {
+		return fmt.Errorf("failed to publish cached form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleExportRespondent handles admin requests for everything stored about
+// a respondent, the signed report carrying it is sent back to the requester
+func (list *Listener) handleExportRespondent(_ context.Context, event entity.Event) error {
+	return list.handlePrivacyRequest(event, list.as(event).ExportRespondent)
+}
+
+// handleEraseRespondent handles admin requests to erase everything stored
+// about a respondent, the signed report is sent back to the requester
+func (list *Listener) handleEraseRespondent(_ context.Context, event entity.Event) error {
+	return list.handlePrivacyRequest(event, list.as(event).EraseRespondent)
+}
+
+// handlePrivacyRequest runs a privacy request for the respondent of the event
+// and publishes its report
+func (list *Listener) handlePrivacyRequest(event entity.Event, run func(string) (*entity.PrivacyReport, error)) error {
+	req := new(struct {
+		Respondent string `json:"respondent"` // Respondent ID or email
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	report, err := run(req.Respondent)
+	if err != nil {
+		if errors.Is(err, service.ErrNoRespondent) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to run privacy request: %w", err)
+	}
+
+	report.RequestID = event.ID
+
+	if err = list.as(event).PublishPrivacyReport(report); err != nil {
+		return fmt.Errorf("failed to publish privacy report: %w", err)
+	}
+
+	return nil
+}
+
+// handleCreateTemplate handles form template creation events
+func (list *Listener) handleCreateTemplate(_ context.Context, event entity.Event) error {
+	template := new(entity.Template)
+
+	if err := decode(event, template); err != nil {
+		return err
+	}
+
+	if err := list.as(event).CreateTemplate(template); err != nil {
+		if errors.Is(err, entity.ErrInvalidRules) || errors.Is(err, question.ErrInvalidDefault) ||
+			errors.Is(err, entity.ErrInvalidMedia) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return nil
+}
+
+// handleCreateFromTemplate handles creating a form from a template
+func (list *Listener) handleCreateFromTemplate(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		TemplateID string `json:"template_id"`
+		Author     string `json:"author"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("template id", req.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = list.as(event).CreateFormFromTemplate(id, req.Author); err != nil {
+		var quotaErr *service.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return rejected(err)
+		}
+
+		return fmt.Errorf("failed to create form from template %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleCloneForm handles copying a form into a new form of an author
+func (list *Listener) handleCloneForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+		Author string `json:"author"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = list.as(event).CloneForm(id, req.Author); err != nil {
+		var quotaErr *service.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return rejected(err)
+		}
+
+		return fmt.Errorf("failed to clone form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleCreateSection handles form section creation events
+func (list *Listener) handleCreateSection(_ context.Context, event entity.Event) error {
+	section := new(entity.Section)
+
+	if err := decode(event, section); err != nil {
+		return err
+	}
+
+	if err := list.as(event).CreateSection(section); err != nil {
+		return fmt.Errorf("failed to create section: %w", err)
+	}
+
+	return nil
+}
+
+// handleUpdateSection handles events changing the title and description of
+// a section
+func (list *Listener) handleUpdateSection(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("section id", req.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).UpdateSection(id, req.Title, req.Description); err != nil {
+		return fmt.Errorf("failed to update section %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleDeleteSection handles section deletion events
+func (list *Listener) handleDeleteSection(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		ID string `json:"id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("section id", req.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).DeleteSection(id); err != nil {
+		return fmt.Errorf("failed to delete section %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleReorderSections handles events listing the sections of a form in
+// their new order
+func (list *Listener) handleReorderSections(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID   string   `json:"form_id"`
+		Sections []string `json:"sections"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	order := make([]uuid.UUID, len(req.Sections))
+	for i, section := range req.Sections {
+		if order[i], err = parseID("section id", section); err != nil {
+			return err
+		}
+	}
+
+	if err = list.as(event).ReorderSections(id, order); err != nil {
+		return fmt.Errorf("failed to reorder sections of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleReorderQuestions handles events listing the questions of a form in
+// their new order
+func (list *Listener) handleReorderQuestions(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID    string   `json:"form_id"`
+		Questions []string `json:"questions"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	order := make([]uuid.UUID, len(req.Questions))
+	for i, question := range req.Questions {
+		if order[i], err = parseID("question id", question); err != nil {
+			return err
+		}
+	}
+
+	if err = list.as(event).ReorderQuestions(id, order); err != nil {
+		if errors.Is(err, service.ErrInvalidOrder) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to reorder questions of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleImportQuestions handles events adding many questions to a form at once
+func (list *Listener) handleImportQuestions(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID    string            `json:"form_id"`
+		Questions []entity.Question `json:"questions"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).ImportQuestions(id, req.Questions); err != nil {
+		var quotaErr *service.QuotaExceededError
+		if errors.As(err, &quotaErr) || errors.Is(err, service.ErrLimitExceeded) {
+			return rejected(err)
+		}
+		if errors.Is(err, service.ErrNoQuestions) || errors.Is(err, service.ErrSectionNotInForm) ||
+			errors.Is(err, entity.ErrInvalidRules) || errors.Is(err, question.ErrUnknownType) ||
+			errors.Is(err, question.ErrInvalidOptions) || errors.Is(err, question.ErrInvalidDefault) ||
+			errors.Is(err, entity.ErrInvalidFormat) || errors.Is(err, entity.ErrInvalidMedia) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to import questions into form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleExportForm handles form export requests, the document is sent back
+// to the requester
+func (list *Listener) handleExportForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+		Format string `json:"format"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	document, err := list.as(event).ExportForm(id, req.Format)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownExportFormat) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to export form %s: %w", id, err)
+	}
+
+	if err = list.as(event).PublishFormExport(&entity.FormExport{
+		RequestID: event.ID,
+		FormID:    id.String(),
+		Format:    req.Format,
+		Document:  string(document),
+	}); err != nil {
+		return fmt.Errorf("failed to publish export of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleImportForm handles events creating a form from an export
+func (list *Listener) handleImportForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		Format   string `json:"format"`
+		Document string `json:"document"`
+		Author   string `json:"author"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	if _, err := list.as(event).ImportForm(req.Format, []byte(req.Document), req.Author); err != nil {
+		var quotaErr *service.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return rejected(err)
+		}
+		if errors.Is(err, entity.ErrUnknownExportFormat) || errors.Is(err, entity.ErrInvalidExport) ||
+			errors.Is(err, entity.ErrInvalidRules) || errors.Is(err, entity.ErrInvalidNumbering) ||
+			errors.Is(err, entity.ErrInvalidLocale) || errors.Is(err, entity.ErrInvalidSettings) ||
+			errors.Is(err, question.ErrUnknownType) || errors.Is(err, question.ErrInvalidOptions) ||
+			errors.Is(err, question.ErrInvalidDefault) || errors.Is(err, entity.ErrInvalidFormat) ||
+			errors.Is(err, entity.ErrInvalidMedia) || errors.Is(err, entity.ErrInvalidTheme) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to import form: %w", err)
+	}
+
+	return nil
+}
+
+// handleMoveQuestion handles events grouping a question in a section. An
+// empty section_id takes the question out of its section.
+func (list *Listener) handleMoveQuestion(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		QuestionID string `json:"question_id"`
+		SectionID  string `json:"section_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	questionID, err := parseID("question id", req.QuestionID)
+	if err != nil {
+		return err
+	}
+
+	var section *uuid.UUID
+	if req.SectionID != "" {
+		id, err := parseID("section id", req.SectionID)
+		if err != nil {
+			return err
+		}
+		section = &id
+	}
+
+	if err = list.as(event).MoveQuestion(questionID, section); err != nil {
+		if errors.Is(err, service.ErrSectionNotInForm) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to move question %s: %w", questionID, err)
+	}
+
+	return nil
+}
+
+// handleSetLogic handles events replacing the branches showing and hiding
+// questions of a form
+func (list *Listener) handleSetLogic(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID   string          `json:"form_id"`
+		Branches []entity.Branch `json:"branches"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).SetLogic(id, req.Branches); err != nil {
+		if errors.Is(err, entity.ErrInvalidLogic) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to set logic of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleSetTags handles events replacing the tags of a form
+func (list *Listener) handleSetTags(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string   `json:"form_id"`
+		Tags   []string `json:"tags"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).SetTags(id, req.Tags); err != nil {
+		if errors.Is(err, entity.ErrInvalidTag) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to set tags of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleFormsByTag handles listings of the forms of a tenant with a tag
+func (list *Listener) handleFormsByTag(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		TenantID string         `json:"tenant_id"`
+		Tag      string         `json:"tag"`
+		Filter   *entity.Filter `json:"filter"`
+		Offset   int            `json:"offset"`
+		Limit    int            `json:"limit"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	forms, err := list.as(event).ListFormsByTag(req.TenantID, req.Tag, req.Filter, req.Offset, req.Limit)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidTag) || errors.Is(err, entity.ErrInvalidFilter) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to list forms tagged %q: %w", req.Tag, err)
+	}
+
+	forms.RequestID = event.ID
+
+	if err = list.as(event).PublishFormList(forms); err != nil {
+		return fmt.Errorf("failed to publish forms tagged %q: %w", req.Tag, err)
+	}
+
+	return nil
+}
+
+// handleSetNumbering handles changes of how the questions of a form are numbered
+func (list *Listener) handleSetNumbering(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID    string `json:"form_id"`
+		Numbering string `json:"numbering"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).SetNumbering(id, req.Numbering); err != nil {
+		if errors.Is(err, entity.ErrInvalidNumbering) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to set numbering of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleSettings handles partial updates of the settings of a form
+func (list *Listener) handleSettings(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+		entity.FormSettingsUpdate
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).UpdateSettings(id, req.FormSettingsUpdate); err != nil {
+		if errors.Is(err, entity.ErrInvalidSettings) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to update settings of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleTheme handles partial updates of the theme of a form
+func (list *Listener) handleTheme(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+		entity.ThemeUpdate
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).UpdateTheme(id, req.ThemeUpdate); err != nil {
+		if errors.Is(err, entity.ErrInvalidTheme) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to update theme of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleHeartbeat returns the handler recording consumer heartbeats in tracker.
+// Heartbeats without a time count as sent when the event was.
+func (list *Listener) handleHeartbeat(tracker *heartbeat.Tracker) Handler {
+	return func(_ context.Context, event entity.Event) error {
+		req := new(struct {
+			Consumer string    `json:"consumer"`
+			At       time.Time `json:"at"`
+		})
+
+		if err := decode(event, req); err != nil {
+			return err
+		}
+
+		if req.Consumer == "" {
+			return invalid(errors.New("consumer is required"))
+		}
+
+		at := req.At
+		if at.IsZero() {
+			at = event.Timestamp
+		}
+
+		if !tracker.Record(req.Consumer, at) {
+			return rejected(fmt.Errorf("unknown consumer %s", req.Consumer))
+		}
+
+		return nil
+	}
+}
+
+// handleGetForm handles reads of a form in a locale, falling back to the
+// default language for texts without a translation
+func (list *Listener) handleGetForm(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+		Locale string `json:"locale"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	form, locale, err := list.as(event).GetLocalizedForm(id, req.Locale)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve form %s: %w", id, err)
+	}
+
+	if err = list.as(event).PublishFormReply(&entity.FormReply{
+		RequestID: event.ID,
+		Locale:    locale,
+		Form:      form,
+	}); err != nil {
+		return fmt.Errorf("failed to publish form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleAddCollaborator handles grants of a role on a form to a user
+func (list *Listener) handleAddCollaborator(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+		UserID string `json:"user_id"`
+		Role   string `json:"role"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = list.as(event).AddCollaborator(id, req.UserID, req.Role); err != nil {
+		if errors.Is(err, entity.ErrInvalidRole) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to add collaborator to form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleRemoveCollaborator handles revocations of the role of a user on a form
+func (list *Listener) handleRemoveCollaborator(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+		UserID string `json:"user_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).RemoveCollaborator(id, req.UserID); err != nil {
+		return fmt.Errorf("failed to remove collaborator from form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleListCollaborators handles collaborator listing requests, the listing
+// is sent back to the requester
+func (list *Listener) handleListCollaborators(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID string `json:"form_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	id, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	collaborators, err := list.as(event).ListCollaborators(id)
+	if err != nil {
+		return fmt.Errorf("failed to list collaborators of form %s: %w", id, err)
+	}
+
+	collaborators.RequestID = event.ID
+
+	if err = list.as(event).PublishCollaboratorList(collaborators); err != nil {
+		return fmt.Errorf("failed to publish collaborators of form %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// handleSetWebhook handles webhooks added to a form or changed, the ID of
+// the webhook picks the one to change
+func (list *Listener) handleSetWebhook(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID       string `json:"form_id"`
+		WebhookID    string `json:"webhook_id,omitempty"`
+		URL          string `json:"url"`
+		TemplateKind string `json:"template_kind,omitempty"`
+		Template     string `json:"template,omitempty"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	formID, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	hook := &entity.Webhook{
+		FormID:       formID,
+		URL:          req.URL,
+		TemplateKind: req.TemplateKind,
+		Template:     req.Template,
+	}
+
+	if req.WebhookID != "" {
+		if hook.ID, err = parseID("webhook id", req.WebhookID); err != nil {
+			return err
+		}
+	}
+
+	if _, err = list.as(event).SetWebhook(hook); err != nil {
+		if errors.Is(err, service.ErrWebhooksDisabled) || errors.Is(err, service.ErrAccessDenied) {
+			return rejected(err)
+		}
+		if errors.Is(err, entity.ErrInvalidWebhook) || errors.Is(err, webhook.ErrInvalidTemplate) {
+			return invalid(err)
+		}
+
+		return fmt.Errorf("failed to set webhook of form %s: %w", formID, err)
+	}
+
+	return nil
+}
+
+// handleRemoveWebhook handles removals of a webhook from a form
+func (list *Listener) handleRemoveWebhook(_ context.Context, event entity.Event) error {
+	req := new(struct {
+		FormID    string `json:"form_id"`
+		WebhookID string `json:"webhook_id"`
+	})
+
+	if err := decode(event, req); err != nil {
+		return err
+	}
+
+	formID, err := parseID("form id", req.FormID)
+	if err != nil {
+		return err
+	}
+
+	webhookID, err := parseID("webhook id", req.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	if err = list.as(event).RemoveWebhook(formID, webhookID); err != nil {
+		if errors.Is(err, service.ErrWebhooksDisabled) || errors.Is(err, service.ErrAccessDenied) {
+			return rejected(err)
+		}
+
+		return fmt.Errorf("failed to remove webhook %s from form %s: %w", webhookID, formID, err)
+	}
+
+	return nil
+}
+
+// handleLoopback returns the handler marking the events the service published
+// as come back in verifier. Every instance receives the events of all of
+// them, so events it doesn't expect are not an error.
+func (list *Listener) handleLoopback(verifier *loopback.Verifier) Handler {
+	return func(_ context.Context, event entity.Event) error {
+		verifier.Observe(event.Correlation())
+
+		return nil
+	}
+}
@@ -3,122 +3,334 @@ package listener
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/Koyo-os/form-service/internal/entity"
 	"github.com/Koyo-os/form-service/internal/service"
 	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/heartbeat"
 	"github.com/Koyo-os/form-service/pkg/logger"
-	"github.com/bytedance/sonic"
-	"github.com/google/uuid"
+	"github.com/Koyo-os/form-service/pkg/loopback"
 	"go.uber.org/zap"
 )
 
-// Listener handles incoming events and routes them to appropriate service methods
-type Listener struct {
-	inputChan chan entity.Event // Channel for receiving events
-	logger    *logger.Logger    // Logger for error tracking
-	service   *service.Service  // Service layer for business logic
-	cfg       *config.Config    // Application configuration
-}
+// HEARTBEAT_INTERVAL is how often an idle listener reports activity
+const HEARTBEAT_INTERVAL = 10 * time.Second
+
+type (
+	// route is the handler registered for a request type
+	route struct {
+		name    string
+		handler Handler
+		public  bool // Handles requests of respondents, which name no actor
+		dryRun  bool // Simulates dry-run requests itself
+	}
 
-// Init creates a new Listener instance with all required dependencies
+	// Listener handles incoming events and routes them to appropriate service methods
+	Listener struct {
+		inputChan   chan entity.Event       // Channel for receiving events
+		logger      *logger.Logger          // Logger for error tracking
+		service     *service.Service        // Service layer for business logic
+		cfg         *config.Config          // Application configuration
+		heartbeat   func()                  // Reports loop activity, e.g. to a watchdog
+		routes      map[string]route        // Handlers by request type
+		subscribers map[string][]subscriber // Handlers events are fanned out to besides their route, by request type
+		deadLetters DeadLetters             // Receives the events subscribers gave up on, nil to log them
+		middleware  []Middleware            // Wraps every handler, outermost first
+		workers     int                     // Events handled concurrently
+		limits      map[string]int          // Concurrency caps by request type
+		running     sync.WaitGroup          // Held while Listen runs
+		mu          sync.Mutex              // Guards workers
+		resize      chan int                // Worker pool sizes requested while running
+		load        loadStats               // Counters of handled events
+		instance    string                  // Name of this instance in admin events
+
+		handled func(entity.Event) // Called once an event was handled
+	}
+)
+
+// Init creates a new Listener instance with all required dependencies.
+// Every handler is wrapped in the Logging, Timing and Denials middleware.
 func Init(
 	inputChan chan entity.Event,
 	logger *logger.Logger,
 	cfg *config.Config,
 	service *service.Service,
 ) *Listener {
-	return &Listener{
-		inputChan: inputChan,
-		service:   service,
-		logger:    logger,
-		cfg:       cfg,
+	list := &Listener{
+		inputChan:   inputChan,
+		service:     service,
+		logger:      logger,
+		cfg:         cfg,
+		heartbeat:   func() {},
+		handled:     func(entity.Event) {},
+		routes:      make(map[string]route),
+		subscribers: make(map[string][]subscriber),
+		workers:     1,
+		resize:      make(chan int, 1),
 	}
+
+	list.Use(Logging(logger), Timing(), Denials())
+
+	list.Handle(cfg.Reqs.CreateRequestType, "create_form", list.handleCreateForm)
+	list.Handle(cfg.Reqs.UpdateRequestType, "update_form", list.handleUpdateForm)
+	list.Handle(cfg.Reqs.SaveFormRequestType, "save_form", list.handleSaveForm)
+	list.HandleDryRun(cfg.Reqs.DeleteFormRequestType, "delete_form", list.handleDeleteForm)
+	list.HandleDryRun(cfg.Reqs.DeleteQuestionRequestType, "delete_question", list.handleDeleteQuestion)
+	list.HandlePublic(cfg.Reqs.RequestUploadRequestType, "request_upload", list.handleRequestUpload)
+	list.HandlePublic(cfg.Reqs.CompleteUploadRequestType, "complete_upload", list.handleCompleteUpload)
+	list.HandlePublic(cfg.Reqs.SaveDraftRequestType, "save_draft", list.handleSaveDraft)
+	list.Handle(cfg.Reqs.SetAccessRequestType, "set_access", list.handleSetAccess)
+	list.Handle(cfg.Reqs.CreateInviteRequestType, "create_invite", list.handleCreateInvite)
+	list.Handle(cfg.Reqs.RevokeInviteRequestType, "revoke_invite", list.handleRevokeInvite)
+	list.Handle(cfg.Reqs.ListInvitesRequestType, "list_invites", list.handleListInvites)
+	list.Handle(cfg.Reqs.TenantSettingsRequestType, "tenant_settings", list.handleTenantSettings)
+	list.Handle(cfg.Reqs.QuotaGetRequestType, "get_quota", list.handleQuotaGet)
+	list.Handle(cfg.Reqs.QuotaUpdateRequestType, "update_quota", list.handleQuotaUpdate)
+	list.Handle(cfg.Reqs.QuestionStatsRequestType, "question_stats", list.handleQuestionStats)
+	list.HandlePublic(cfg.Reqs.SubmitResponseRequestType, "submit_response", list.handleSubmitResponse)
+	list.Handle(cfg.Reqs.ListResponsesRequestType, "list_responses", list.handleListResponses)
+	list.Handle(cfg.Reqs.PublishFormRequestType, "publish_form", list.handlePublishForm)
+	list.Handle(cfg.Reqs.FormVersionRequestType, "form_version", list.handleFormVersion)
+	list.HandlePublic(cfg.Reqs.PublicFormRequestType, "public_form", list.handlePublicForm)
+	list.HandlePublic(cfg.Reqs.FormMetadataRequestType, "form_metadata", list.handleFormMetadata)
+	list.Handle(cfg.Reqs.SetTagsRequestType, "set_tags", list.handleSetTags)
+	list.Handle(cfg.Reqs.FormsByTagRequestType, "forms_by_tag", list.handleFormsByTag)
+	list.Handle(cfg.Reqs.NumberingRequestType, "numbering", list.handleSetNumbering)
+	list.Handle(cfg.Reqs.RestoreFormRequestType, "restore_form", list.handleRestoreForm)
+	list.Handle(cfg.Reqs.PurgeFormRequestType, "purge_form", list.handlePurgeForm)
+	list.Handle(cfg.Reqs.ArchiveFormRequestType, "archive_form", list.handleArchiveForm)
+	list.Handle(cfg.Reqs.UnarchiveRequestType, "unarchive_form", list.handleUnarchiveForm)
+	list.Handle(cfg.Reqs.SetStatusRequestType, "set_status", list.handleSetStatus)
+	list.Handle(cfg.Reqs.SetScheduleRequestType, "set_schedule", list.handleSetSchedule)
+	list.Handle(cfg.Reqs.FormAtRequestType, "form_at", list.handleFormAt)
+	list.Handle(cfg.Reqs.GetFormRequestType, "get_form", list.handleGetForm)
+	list.Handle(cfg.Reqs.AddCollaboratorRequestType, "add_collaborator", list.handleAddCollaborator)
+	list.Handle(cfg.Reqs.RemoveCollaboratorRequestType, "remove_collaborator", list.handleRemoveCollaborator)
+	list.Handle(cfg.Reqs.ListCollaboratorsRequestType, "list_collaborators", list.handleListCollaborators)
+	list.Handle(cfg.Reqs.SetWebhookRequestType, "set_webhook", list.handleSetWebhook)
+	list.Handle(cfg.Reqs.RemoveWebhookRequestType, "remove_webhook", list.handleRemoveWebhook)
+	list.Handle(cfg.Reqs.ExportRespondentRequestType, "export_respondent", list.handleExportRespondent)
+	list.Handle(cfg.Reqs.EraseRespondentRequestType, "erase_respondent", list.handleEraseRespondent)
+	list.Handle(cfg.Reqs.SetWorkersRequestType, "set_workers", list.handleSetWorkers)
+	list.Handle(cfg.Reqs.InspectCacheRequestType, "inspect_cache", list.handleInspectCache)
+	list.Handle(cfg.Reqs.CreateTemplateRequestType, "create_template", list.handleCreateTemplate)
+	list.Handle(cfg.Reqs.FromTemplateRequestType, "create_from_template", list.handleCreateFromTemplate)
+	list.Handle(cfg.Reqs.CloneFormRequestType, "clone_form", list.handleCloneForm)
+	list.Handle(cfg.Reqs.SettingsRequestType, "settings", list.handleSettings)
+	list.Handle(cfg.Reqs.ThemeRequestType, "theme", list.handleTheme)
+	list.Handle(cfg.Reqs.CreateSectionRequestType, "create_section", list.handleCreateSection)
+	list.Handle(cfg.Reqs.UpdateSectionRequestType, "update_section", list.handleUpdateSection)
+	list.Handle(cfg.Reqs.DeleteSectionRequestType, "delete_section", list.handleDeleteSection)
+	list.Handle(cfg.Reqs.ReorderSectionRequestType, "reorder_sections", list.handleReorderSections)
+	list.Handle(cfg.Reqs.ReorderQuestionRequestType, "reorder_questions", list.handleReorderQuestions)
+	list.Handle(cfg.Reqs.ImportQuestionsRequestType, "import_questions", list.handleImportQuestions)
+	list.Handle(cfg.Reqs.ExportFormRequestType, "export_form", list.handleExportForm)
+	list.Handle(cfg.Reqs.ImportFormRequestType, "import_form", list.handleImportForm)
+	list.Handle(cfg.Reqs.MoveQuestionRequestType, "move_question", list.handleMoveQuestion)
+	list.Handle(cfg.Reqs.SetLogicRequestType, "set_logic", list.handleSetLogic)
+
+	return list
+}
+
+// Handle registers handler, identified by name in logs and metrics, for
+// events of requestType. Events without an actor are invalid and never
+// reach handler, so user requests can't skip the access checks of the
+// service, see service.As. Dry-run events are invalid as well, as handler
+// would carry them out, see HandleDryRun. A later registration replaces an
+// earlier one. It must be called before Listen.
+func (list *Listener) Handle(requestType, name string, handler Handler) {
+	list.routes[requestType] = route{name: name, handler: handler}
+}
+
+// HandlePublic registers handler like Handle, for events that need no
+// actor: the requests of respondents and the control events of other
+// services.
+func (list *Listener) HandlePublic(requestType, name string, handler Handler) {
+	list.routes[requestType] = route{name: name, handler: handler, public: true}
+}
+
+// HandleDryRun registers handler like Handle, for requests it can simulate:
+// it reports dry-run events, see entity.Event, with reportDryRun instead of
+// carrying them out.
+func (list *Listener) HandleDryRun(requestType, name string, handler Handler) {
+	list.routes[requestType] = route{name: name, handler: handler, dryRun: true}
+}
+
+// Use appends middleware wrapping every handler. Middleware registered
+// first runs outermost. It must be called before Listen.
+func (list *Listener) Use(middleware ...Middleware) {
+	list.middleware = append(list.middleware, middleware...)
+}
+
+// SetConcurrency handles up to workers events at once, at most limits[t] of
+// request type t. Events of a type at its limit wait without occupying a
+// worker. Events handled concurrently may finish out of order; a single
+// worker, the default, handles them one by one. It must be called before Listen.
+func (list *Listener) SetConcurrency(workers int, limits map[string]int) {
+	list.mu.Lock()
+	list.workers = min(max(workers, 1), MAX_WORKERS)
+	list.mu.Unlock()
+
+	list.limits = limits
 }
 
+// SetInstance names this instance, so admin events addressed to other
+// instances are refused
+func (list *Listener) SetInstance(name string) {
+	list.instance = name
+}
+
+// as returns the service acting for the user the event was sent by, see
+// service.As
+func (list *Listener) as(event entity.Event) *service.Service {
+	return list.service.As(event.Actor)
+}
+
+// TrackHeartbeats records the heartbeats downstream consumers send, events
+// of the configured heartbeat type, in tracker. It must be called before Listen.
+func (list *Listener) TrackHeartbeats(tracker *heartbeat.Tracker) {
+	list.HandlePublic(list.cfg.Heartbeats.Type, "heartbeat", list.handleHeartbeat(tracker))
+}
+
+// TrackLoopback marks the events of the types verifier verifies as come back
+// when they arrive on the loopback queue. It must be called before Listen.
+func (list *Listener) TrackLoopback(verifier *loopback.Verifier) {
+	for _, eventType := range verifier.EventTypes() {
+		list.HandlePublic(eventType, "loopback", list.handleLoopback(verifier))
+	}
+}
+
+// OnActivity registers a function called on every handled event and
+// periodically while idle, so supervisors can tell the loop is alive
+func (list *Listener) OnActivity(heartbeat func()) {
+	list.heartbeat = heartbeat
+}
+
+// OnHandled registers a function called with every event once its handler
+// returned, e.g. to acknowledge it in a durable buffer. It must be called
+// before Listen.
+func (list *Listener) OnHandled(handled func(entity.Event)) {
+	list.handled = handled
+}
+
+// Close stops taking events and waits for the handled and deferred ones
 func (list *Listener) Close() error {
 	close(list.inputChan)
+	list.running.Wait()
 
 	return nil
 }
 
+// handlers wraps every registered handler in the middleware and fans the
+// request types with subscribers out to them
+func (list *Listener) handlers() map[string]Handler {
+	handlers := make(map[string]Handler, len(list.routes))
+
+	for requestType, r := range list.routes {
+		handler := r.handler
+		if !r.dryRun {
+			handler = refuseDryRun(handler)
+		}
+		if !r.public {
+			handler = requireActor(handler)
+		}
+
+		handlers[requestType] = list.wrap(r.name, handler)
+	}
+
+	for requestType, subscribers := range list.subscribers {
+		wrapped := make([]subscriber, len(subscribers))
+		for i, sub := range subscribers {
+			sub.handler = list.wrap(sub.name, sub.handler)
+			wrapped[i] = sub
+		}
+
+		handlers[requestType] = list.fanout(handlers[requestType], wrapped)
+	}
+
+	return handlers
+}
+
+// wrap wraps the handler registered under name in the middleware
+func (list *Listener) wrap(name string, handler Handler) Handler {
+	for i := len(list.middleware) - 1; i >= 0; i-- {
+		handler = list.middleware[i](name, handler)
+	}
+
+	return handler
+}
+
 // Listen starts the event listening loop
 // It processes incoming events based on their type and routes them to appropriate handlers
-// The loop continues until the context is cancelled
+// The loop continues until the context is cancelled or the input channel is closed
 func (list *Listener) Listen(ctx context.Context) {
+	list.running.Add(1)
+	defer list.running.Done()
+
+	ticker := time.NewTicker(HEARTBEAT_INTERVAL)
+	defer ticker.Stop()
+
+	handlers := list.handlers()
+	limiter := newLimiter(list.limits)
+
+	list.load.deferred.Store(limiter)
+	list.load.mu.Lock()
+	list.load.since = time.Now()
+	list.load.mu.Unlock()
+
+	workers := newPool(func(event entity.Event) {
+		err := list.track(ctx, handlers, event)
+		limiter.release(event.Type)
+		settle(event, err)
+		list.handled(event)
+	})
+	list.applyResize(workers, list.Workers())
+	defer workers.stop()
+
+	input := list.inputChan
+
 	for {
+		// Stop taking events while too many wait for their limit
+		intake := input
+		if limiter.backlog() >= MAX_DEFERRED_EVENTS {
+			intake = nil
+		}
+
 		select {
-		case event := <-list.inputChan:
-			switch event.Type {
-			case list.cfg.Reqs.CreateRequestType:
-				// Handle form creation events
-				form := new(entity.Form)
-
-				if err := json.Unmarshal(event.Payload, &form); err != nil {
-					list.logger.Error("error unmarshal event payload to form",
-						zap.String("event_type", event.Type),
-						zap.String("event_id", event.ID),
-						zap.Error(err))
-					continue
-				}
+		case event, ok := <-intake:
+			if !ok {
+				list.logger.Info("input channel closed, stopping listener...")
 
-				if err := list.service.CreateForm(form); err != nil {
-					list.logger.Error("error create form", zap.Error(err))
-					continue
+				input = nil
+				if limiter.backlog() == 0 {
+					return
 				}
+				continue
+			}
 
-			case list.cfg.Reqs.UpdateRequestType:
-				// Handle form update events
-				form := new(entity.Form)
+			list.heartbeat()
 
-				if err := json.Unmarshal(event.Payload, &form); err != nil {
-					list.logger.Error("error unmarshal payload to form",
-						zap.String("event_id", event.ID),
-						zap.String("event_type", event.Type),
-						zap.Error(err))
-					continue
-				}
+			if limiter.admit(event) {
+				list.dispatch(workers, event)
+			}
 
-				if err := list.service.Update(form.ID, form); err != nil {
-					list.logger.Error("error update form",
-						zap.String("event_id", event.ID),
-						zap.String("form_id", form.ID.String()),
-						zap.Error(err))
-					continue
-				}
+		case <-limiter.wake:
+			for _, event := range limiter.ready() {
+				list.dispatch(workers, event)
+			}
 
-			case list.cfg.Reqs.DeleteFormRequestType:
-				// Handle form deletion events
-				req := new(struct {
-					FormID string `json:"form_id"`
-				})
-
-				if err := sonic.Unmarshal(event.Payload, req); err != nil {
-					list.logger.Error("error unmarshal request from event payload",
-						zap.String("event_id", event.ID),
-						zap.String("event_type", event.Type),
-						zap.Error(err))
-					continue
-				}
+			if input == nil && limiter.backlog() == 0 {
+				return
+			}
 
-				id, err := uuid.Parse(req.FormID)
-				if err != nil {
-					list.logger.Error("error parse form id",
-						zap.String("event_id", event.ID),
-						zap.String("event_type", event.Type),
-						zap.Error(err))
-					continue
-				}
+		case n := <-list.resize:
+			list.applyResize(workers, n)
 
-				if err = list.service.DeleteForm(id); err != nil {
-					list.logger.Error("error delete form",
-						zap.String("event_id", event.ID),
-						zap.String("form_id", req.FormID),
-						zap.Error(err))
-					continue
-				}
-			}
+		case <-ticker.C:
+			list.heartbeat()
 
 		case <-ctx.Done():
 			list.logger.Info("stopping listeners...")
@@ -126,3 +338,38 @@ func (list *Listener) Listen(ctx context.Context) {
 		}
 	}
 }
+
+// handle passes event to the handler of its type and returns what it
+// returned. Failures are logged by the middleware.
+func (list *Listener) handle(ctx context.Context, handlers map[string]Handler, event entity.Event) error {
+	handler, ok := handlers[event.Type]
+	if !ok {
+		list.logger.Debug("no handler for event type",
+			zap.String("event_id", event.ID),
+			zap.String("event_type", event.Type))
+		return nil
+	}
+
+	return handler(ctx, event)
+}
+
+// settle acknowledges the delivery of a handled event, see entity.Event.
+// Failed events are requeued; rejected and invalid ones are acknowledged,
+// as handling them again ends the same way.
+func settle(event entity.Event, err error) {
+	if event.Settle != nil {
+		event.Settle(Outcome(err) == OUTCOME_ERROR)
+	}
+}
+
+// reportDryRun publishes the simulated outcome of a dry-run request
+func (list *Listener) reportDryRun(event entity.Event, result *entity.DryRunResult) error {
+	result.RequestID = event.ID
+	result.Operation = event.Type
+
+	if err := list.service.PublishDryRun(result); err != nil {
+		return fmt.Errorf("failed to publish dry run result: %w", err)
+	}
+
+	return nil
+}
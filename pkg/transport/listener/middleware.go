@@ -0,0 +1,173 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/service"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// Outcomes of a handled event, as logged and recorded in metrics
+const (
+	OUTCOME_OK       = "ok"
+	OUTCOME_REJECTED = "rejected"
+	OUTCOME_INVALID  = "invalid"
+	OUTCOME_ERROR    = "error"
+)
+
+var (
+	// ErrInvalidEvent marks events whose payload can't be decoded or names
+	// malformed IDs. Retrying them never succeeds.
+	ErrInvalidEvent = errors.New("invalid event")
+
+	// ErrRejected marks requests the service refused on purpose, e.g. over a
+	// quota or failing validation. They are part of normal operation.
+	ErrRejected = errors.New("request rejected")
+
+	// ErrNoActor marks user requests that don't name the user they are made
+	// by, see Handle
+	ErrNoActor = errors.New("request has no actor")
+
+	// ErrDryRunUnsupported marks dry-run requests of a type that can't be
+	// simulated, see HandleDryRun
+	ErrDryRunUnsupported = errors.New("dry run is not supported for the request")
+)
+
+type (
+	// Handler handles one request event. Decoding failures should be returned
+	// via invalid and deliberate refusals via rejected so middleware can tell
+	// them from failures.
+	Handler func(ctx context.Context, event entity.Event) error
+
+	// Middleware wraps the handler registered under name
+	Middleware func(name string, next Handler) Handler
+)
+
+// invalid marks err as caused by a malformed event
+func invalid(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+}
+
+// rejected marks err as a deliberate refusal of the request
+func rejected(err error) error {
+	return fmt.Errorf("%w: %w", ErrRejected, err)
+}
+
+// Outcome classifies the error a handler returned
+func Outcome(err error) string {
+	switch {
+	case err == nil:
+		return OUTCOME_OK
+	case errors.Is(err, ErrRejected):
+		return OUTCOME_REJECTED
+	case errors.Is(err, ErrInvalidEvent):
+		return OUTCOME_INVALID
+	default:
+		return OUTCOME_ERROR
+	}
+}
+
+// Logging logs the receipt and completion of every event with the handler,
+// its outcome, duration and the correlation ID of the event. Rejections are
+// logged at info, invalid events and failures at error level.
+func Logging(logger *logger.Logger) Middleware {
+	return func(name string, next Handler) Handler {
+		return func(ctx context.Context, event entity.Event) error {
+			fields := []zap.Field{
+				zap.String("handler", name),
+				zap.String("event_id", event.ID),
+				zap.String("event_type", event.Type),
+				zap.String("correlation_id", event.Correlation()),
+			}
+
+			logger.Debug("event received", fields...)
+
+			start := time.Now()
+			err := next(ctx, event)
+
+			fields = append(fields,
+				zap.String("outcome", Outcome(err)),
+				zap.Duration("duration", time.Since(start)))
+
+			switch Outcome(err) {
+			case OUTCOME_OK:
+				logger.Info("event handled", fields...)
+			case OUTCOME_REJECTED:
+				logger.Info("event rejected", append(fields, zap.Error(err))...)
+			default:
+				logger.Error("event failed", append(fields, zap.Error(err))...)
+			}
+
+			return err
+		}
+	}
+}
+
+// requireActor refuses events without an actor as invalid before they reach
+// next, see Handle
+func requireActor(next Handler) Handler {
+	return func(ctx context.Context, event entity.Event) error {
+		if event.Actor == "" {
+			return invalid(ErrNoActor)
+		}
+
+		return next(ctx, event)
+	}
+}
+
+// refuseDryRun refuses dry-run events as invalid before they reach next,
+// which would carry them out, see HandleDryRun
+func refuseDryRun(next Handler) Handler {
+	return func(ctx context.Context, event entity.Event) error {
+		if event.DryRun {
+			return invalid(fmt.Errorf("%w: %s", ErrDryRunUnsupported, event.Type))
+		}
+
+		return next(ctx, event)
+	}
+}
+
+// Denials reports requests refused because the actor of the event may not
+// make them, e.g. users who aren't editors of the form or admins, as
+// rejections
+func Denials() Middleware {
+	return func(_ string, next Handler) Handler {
+		return func(ctx context.Context, event entity.Event) error {
+			err := next(ctx, event)
+			if denied(err) && !errors.Is(err, ErrRejected) {
+				return rejected(err)
+			}
+
+			return err
+		}
+	}
+}
+
+// denied reports whether err is a refusal of the service to act for the
+// actor of the event
+func denied(err error) bool {
+	return errors.Is(err, service.ErrNotEditor) || errors.Is(err, service.ErrNotAuthor) ||
+		errors.Is(err, service.ErrNotCollaborator) || errors.Is(err, service.ErrNotAdmin)
+}
+
+// Timing records the duration of every handled event by handler and outcome
+func Timing() Middleware {
+	return func(name string, next Handler) Handler {
+		return func(ctx context.Context, event entity.Event) error {
+			start := time.Now()
+			err := next(ctx, event)
+
+			metrics.ListenerHandlerDuration.
+				WithLabelValues(name, Outcome(err)).
+				Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/internal/service"
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, OUTCOME_OK, Outcome(nil))
+	assert.Equal(t, OUTCOME_REJECTED, Outcome(rejected(errors.New("over quota"))))
+	assert.Equal(t, OUTCOME_INVALID, Outcome(invalid(errors.New("bad json"))))
+	assert.Equal(t, OUTCOME_ERROR, Outcome(errors.New("db down")))
+}
+
+func TestLogging(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	handler := Logging(&logger.Logger{Logger: zap.New(core)})("delete_form", func(context.Context, entity.Event) error {
+		return rejected(errors.New("over quota"))
+	})
+
+	err := handler(context.Background(), entity.Event{ID: "evt-1", Type: "request.form.deleted", CorrelationID: "corr-1"})
+
+	assert.ErrorIs(t, err, ErrRejected)
+	require.Equal(t, 2, logs.Len())
+
+	done := logs.All()[1]
+	assert.Equal(t, "event rejected", done.Message)
+	assert.Equal(t, zap.InfoLevel, done.Level)
+
+	fields := done.ContextMap()
+	assert.Equal(t, "delete_form", fields["handler"])
+	assert.Equal(t, "corr-1", fields["correlation_id"])
+	assert.Equal(t, OUTCOME_REJECTED, fields["outcome"])
+	assert.Contains(t, fields, "duration")
+}
+
+func TestListener_Listen(t *testing.T) {
+	cfg, err := config.Init("")
+	require.NoError(t, err)
+
+	events := make(chan entity.Event, 1)
+	list := Init(events, &logger.Logger{Logger: zap.NewNop()}, cfg, nil)
+
+	var calls []string
+	record := func(label string) Middleware {
+		return func(name string, next Handler) Handler {
+			return func(ctx context.Context, event entity.Event) error {
+				calls = append(calls, label+":"+name)
+				return next(ctx, event)
+			}
+		}
+	}
+
+	handled := make(chan entity.Event, 1)
+	list.Use(record("outer"), record("inner"))
+	list.Handle("request.test", "test", func(_ context.Context, event entity.Event) error {
+		handled <- event
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go list.Listen(ctx)
+
+	events <- entity.Event{ID: "evt-1", Type: "request.test", Actor: "alice"}
+
+	select {
+	case event := <-handled:
+		assert.Equal(t, "evt-1", event.ID)
+	case <-time.After(time.Second):
+		t.Fatal("event was not handled")
+	}
+
+	assert.Equal(t, []string{"outer:test", "inner:test"}, calls)
+}
+
+func TestListener_RequireActor(t *testing.T) {
+	cfg, err := config.Init("")
+	require.NoError(t, err)
+
+	list := Init(make(chan entity.Event), &logger.Logger{Logger: zap.NewNop()}, cfg, nil)
+
+	var handled []string
+	list.Handle("request.test", "test", func(_ context.Context, event entity.Event) error {
+		handled = append(handled, event.ID)
+		return nil
+	})
+	list.HandlePublic("request.public", "public", func(_ context.Context, event entity.Event) error {
+		handled = append(handled, event.ID)
+		return nil
+	})
+
+	handlers := list.handlers()
+
+	err = handlers["request.test"](context.Background(), entity.Event{ID: "anonymous", Type: "request.test"})
+	assert.ErrorIs(t, err, ErrNoActor)
+	assert.Equal(t, OUTCOME_INVALID, Outcome(err))
+
+	require.NoError(t, handlers["request.test"](context.Background(), entity.Event{ID: "acting", Type: "request.test", Actor: "alice"}))
+	require.NoError(t, handlers["request.public"](context.Background(), entity.Event{ID: "respondent", Type: "request.public"}))
+
+	assert.Equal(t, []string{"acting", "respondent"}, handled)
+}
+
+func TestDenials(t *testing.T) {
+	for _, denial := range []error{service.ErrNotEditor, service.ErrNotAuthor, service.ErrNotCollaborator, service.ErrNotAdmin} {
+		handler := Denials()("test", func(context.Context, entity.Event) error {
+			return fmt.Errorf("failed to list responses: %w", denial)
+		})
+
+		assert.Equal(t, OUTCOME_REJECTED, Outcome(handler(context.Background(), entity.Event{})), denial.Error())
+	}
+}
+
+func TestListener_RefuseDryRun(t *testing.T) {
+	cfg, err := config.Init("")
+	require.NoError(t, err)
+
+	list := Init(make(chan entity.Event), &logger.Logger{Logger: zap.NewNop()}, cfg, nil)
+
+	var carriedOut, simulated int
+	list.Handle("request.purge", "purge", func(context.Context, entity.Event) error {
+		carriedOut++
+		return nil
+	})
+	list.HandleDryRun("request.delete", "delete", func(_ context.Context, event entity.Event) error {
+		if event.DryRun {
+			simulated++
+		}
+		return nil
+	})
+
+	handlers := list.handlers()
+
+	err = handlers["request.purge"](context.Background(), entity.Event{Type: "request.purge", Actor: "alice", DryRun: true})
+	assert.ErrorIs(t, err, ErrDryRunUnsupported)
+	assert.Equal(t, OUTCOME_INVALID, Outcome(err))
+	assert.Zero(t, carriedOut, "dry runs are never carried out")
+
+	require.NoError(t, handlers["request.delete"](context.Background(), entity.Event{Type: "request.delete", Actor: "alice", DryRun: true}))
+	assert.Equal(t, 1, simulated)
+}
@@ -0,0 +1,199 @@
+package listener
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// MAX_WORKERS caps the size of the worker pool
+const MAX_WORKERS = 256
+
+type (
+	// Load describes how busy the listener was since the previous sample
+	Load struct {
+		Workers     int           // Size of the worker pool
+		Busy        int           // Workers handling an event right now
+		Deferred    int           // Events waiting for their concurrency limit
+		Handled     int64         // Events handled since the previous sample
+		AvgLatency  time.Duration // Mean handling time of those events
+		Utilization float64       // Share of worker time spent handling events, 0 to 1
+	}
+
+	// pool runs the workers of a listening loop and resizes it on request
+	pool struct {
+		jobs    chan entity.Event
+		retire  chan struct{} // Each token stops one worker once it is idle
+		size    int           // Target number of workers, owned by the loop
+		workers sync.WaitGroup
+		run     func(entity.Event)
+	}
+
+	// loadStats accumulates the counters a Load is computed from
+	loadStats struct {
+		workers  atomic.Int64
+		busy     atomic.Int64
+		handled  atomic.Int64
+		nanos    atomic.Int64            // Handling time of the handled events
+		deferred atomic.Pointer[limiter] // Limiter of the running loop
+		mu       sync.Mutex              // Serializes samples
+		since    time.Time               // When the previous sample was taken
+	}
+)
+
+func newPool(run func(entity.Event)) *pool {
+	return &pool{
+		jobs:   make(chan entity.Event),
+		retire: make(chan struct{}, MAX_WORKERS),
+		run:    run,
+	}
+}
+
+// resize grows or shrinks the pool to n workers. Shrinking lets busy
+// workers finish their event first.
+func (p *pool) resize(n int) {
+	for ; p.size < n; p.size++ {
+		// Cancel a pending retirement before starting a new worker
+		select {
+		case <-p.retire:
+			continue
+		default:
+		}
+
+		p.workers.Add(1)
+		go p.work()
+	}
+
+	for ; p.size > n; p.size-- {
+		p.retire <- struct{}{}
+	}
+}
+
+func (p *pool) work() {
+	defer p.workers.Done()
+
+	for {
+		select {
+		case event, ok := <-p.jobs:
+			if !ok {
+				return
+			}
+			p.run(event)
+		case <-p.retire:
+			return
+		}
+	}
+}
+
+// stop closes the job queue and waits for every worker to finish
+func (p *pool) stop() {
+	close(p.jobs)
+	p.workers.Wait()
+}
+
+// SetWorkers resizes the worker pool to n workers, between 1 and
+// MAX_WORKERS. Unlike SetConcurrency it may be called while Listen runs.
+func (list *Listener) SetWorkers(n int) {
+	n = min(max(n, 1), MAX_WORKERS)
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	list.workers = n
+
+	// Replace a resize the loop didn't pick up yet
+	select {
+	case <-list.resize:
+	default:
+	}
+	list.resize <- n
+}
+
+// Workers returns the configured size of the worker pool
+func (list *Listener) Workers() int {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	return list.workers
+}
+
+// Load samples how busy the listener was since the previous call, or since
+// Listen started for the first one
+func (list *Listener) Load() Load {
+	stats := &list.load
+
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(stats.since)
+	stats.since = now
+
+	load := Load{
+		Workers: int(stats.workers.Load()),
+		Busy:    int(stats.busy.Load()),
+		Handled: stats.handled.Swap(0),
+	}
+
+	nanos := stats.nanos.Swap(0)
+	if load.Handled > 0 {
+		load.AvgLatency = time.Duration(nanos / load.Handled)
+	}
+
+	if capacity := elapsed.Nanoseconds() * int64(load.Workers); capacity > 0 {
+		load.Utilization = min(float64(nanos)/float64(capacity), 1)
+	}
+
+	if l := stats.deferred.Load(); l != nil {
+		load.Deferred = l.backlog()
+	}
+
+	return load
+}
+
+// track runs handle on event, counting it towards the load
+func (list *Listener) track(ctx context.Context, handlers map[string]Handler, event entity.Event) error {
+	list.load.busy.Add(1)
+	start := time.Now()
+
+	err := list.handle(ctx, handlers, event)
+
+	list.load.nanos.Add(int64(time.Since(start)))
+	list.load.handled.Add(1)
+	list.load.busy.Add(-1)
+
+	return err
+}
+
+// dispatch hands event to the next idle worker, resizing the pool meanwhile
+// so a resize isn't stuck behind busy workers
+func (list *Listener) dispatch(p *pool, event entity.Event) {
+	for {
+		select {
+		case p.jobs <- event:
+			return
+		case n := <-list.resize:
+			list.applyResize(p, n)
+		}
+	}
+}
+
+// applyResize resizes the pool of the running loop
+func (list *Listener) applyResize(p *pool, n int) {
+	if n == p.size {
+		return
+	}
+
+	list.logger.Info("resizing worker pool",
+		zap.Int("from", p.size),
+		zap.Int("to", n))
+
+	p.resize(n)
+	list.load.workers.Store(int64(n))
+	metrics.ListenerWorkers.Set(float64(n))
+}
@@ -0,0 +1,172 @@
+package listener
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupListener(t *testing.T) (*Listener, chan entity.Event) {
+	t.Helper()
+
+	cfg, err := config.Init("")
+	require.NoError(t, err)
+
+	events := make(chan entity.Event, 20)
+	return Init(events, &logger.Logger{Logger: zap.NewNop()}, cfg, nil), events
+}
+
+func TestListener_SetWorkers(t *testing.T) {
+	list, events := setupListener(t)
+
+	release := make(chan struct{})
+	var running atomic.Int32
+	list.Handle("request.slow", "slow", func(context.Context, entity.Event) error {
+		running.Add(1)
+		defer running.Add(-1)
+
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go list.Listen(ctx)
+
+	for range 3 {
+		events <- entity.Event{Type: "request.slow", Actor: "alice"}
+	}
+
+	require.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(1), running.Load(), "a single worker by default")
+
+	list.SetWorkers(3)
+
+	require.Eventually(t, func() bool { return running.Load() == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, 3, list.Load().Busy)
+
+	close(release)
+	require.Eventually(t, func() bool { return running.Load() == 0 }, time.Second, time.Millisecond)
+
+	load := list.Load()
+	assert.Equal(t, 3, load.Workers)
+	assert.Equal(t, int64(3), load.Handled)
+	assert.Positive(t, load.AvgLatency)
+	assert.Zero(t, list.Load().Handled, "samples cover the time since the previous one")
+
+	list.SetWorkers(1)
+	require.Eventually(t, func() bool { return list.Load().Workers == 1 }, time.Second, time.Millisecond)
+}
+
+func TestListener_OnHandled(t *testing.T) {
+	list, events := setupListener(t)
+
+	list.Handle("request.failing", "failing", func(context.Context, entity.Event) error {
+		return errors.New("failed")
+	})
+
+	handled := make(chan entity.Event, 2)
+	list.OnHandled(func(event entity.Event) { handled <- event })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go list.Listen(ctx)
+
+	events <- entity.Event{ID: "event-1", Type: "request.failing", StreamID: "1-0", Actor: "alice"}
+	events <- entity.Event{ID: "event-2", Type: "request.unknown"}
+
+	for _, id := range []string{"event-1", "event-2"} {
+		select {
+		case event := <-handled:
+			assert.Equal(t, id, event.ID, "called whatever the outcome")
+		case <-time.After(time.Second):
+			t.Fatal("OnHandled not called")
+		}
+	}
+}
+
+func TestListener_Settle(t *testing.T) {
+	list, events := setupListener(t)
+
+	list.Handle("request.ok", "ok", func(context.Context, entity.Event) error { return nil })
+	list.Handle("request.failing", "failing", func(context.Context, entity.Event) error {
+		return errors.New("failed")
+	})
+	list.Handle("request.invalid", "invalid", func(context.Context, entity.Event) error {
+		return invalid(errors.New("malformed"))
+	})
+	list.Handle("request.rejected", "rejected", func(context.Context, entity.Event) error {
+		return rejected(errors.New("refused"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go list.Listen(ctx)
+
+	tests := map[string]bool{
+		"request.ok":       false,
+		"request.failing":  true,
+		"request.invalid":  false,
+		"request.rejected": false,
+		"request.unknown":  false,
+	}
+
+	for eventType, requeue := range tests {
+		settled := make(chan bool, 2)
+		events <- entity.Event{
+			Type:   eventType,
+			Actor:  "alice",
+			Settle: func(requeue bool) { settled <- requeue },
+		}
+
+		select {
+		case got := <-settled:
+			assert.Equal(t, requeue, got, "requeue of %s", eventType)
+		case <-time.After(time.Second):
+			t.Fatalf("%s not settled", eventType)
+		}
+	}
+}
+
+func TestListener_SetWorkers_Bounds(t *testing.T) {
+	list, _ := setupListener(t)
+
+	list.SetWorkers(0)
+	assert.Equal(t, 1, list.Workers())
+
+	list.SetWorkers(MAX_WORKERS + 1)
+	assert.Equal(t, MAX_WORKERS, list.Workers())
+}
+
+func TestListener_HandleSetWorkers(t *testing.T) {
+	list, _ := setupListener(t)
+	list.SetInstance("form-service-0")
+
+	event := func(payload string) entity.Event {
+		return entity.Event{Type: "request.admin.workers", Payload: json.RawMessage(payload)}
+	}
+
+	require.NoError(t, list.handleSetWorkers(context.Background(), event(`{"workers": 4}`)))
+	assert.Equal(t, 4, list.Workers())
+
+	require.NoError(t, list.handleSetWorkers(context.Background(), event(`{"workers": 2, "instance": "form-service-0"}`)))
+	assert.Equal(t, 2, list.Workers())
+
+	err := list.handleSetWorkers(context.Background(), event(`{"workers": 8, "instance": "form-service-1"}`))
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.Equal(t, 2, list.Workers())
+
+	err = list.handleSetWorkers(context.Background(), event(`{"workers": 0}`))
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+}
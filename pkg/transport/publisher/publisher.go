@@ -2,22 +2,117 @@
 package publisher
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Koyo-os/form-service/internal/entity"
 	"github.com/Koyo-os/form-service/pkg/config"
 	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/Koyo-os/form-service/pkg/metrics"
+	"github.com/Koyo-os/form-service/pkg/telemetry"
+	"github.com/Koyo-os/form-service/pkg/transport/envelope"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
 )
 
+const (
+	// Default reconnection settings, used when the config leaves them empty
+	DEFAULT_RECONNECT_DELAY     = time.Second
+	DEFAULT_MAX_RECONNECT_DELAY = 30 * time.Second
+	DEFAULT_PENDING_BUFFER      = 100
+
+	// HEADER_EVENT_TYPE carries the routing key as a message header, next to
+	// the attributes of the payload, for headers exchanges
+	HEADER_EVENT_TYPE = "event_type"
+
+	// HEADER_SCHEMA_ID carries the schema registry ID of the payload schema
+	HEADER_SCHEMA_ID = "schema_id"
+)
+
+// ErrBufferFull is returned by Publish when the broker is unreachable
+// and the pending buffer has no room left for another message
+var ErrBufferFull = errors.New("publisher is disconnected and pending buffer is full")
+
+type (
+	// connection is the subset of *amqp.Connection used by the publisher
+	connection interface {
+		Channel() (channel, error)
+		NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+		IsClosed() bool
+		Close() error
+	}
+
+	// channel is the subset of *amqp.Channel used by the publisher
+	channel interface {
+		Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+		NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+		Close() error
+	}
+
+	// amqpConnection adapts *amqp.Connection to the connection interface
+	amqpConnection struct {
+		*amqp.Connection
+	}
+
+	// Tracker is told about every event before it is sent, and about the
+	// events whose publication failed, e.g. a loopback.Verifier
+	Tracker interface {
+		Expect(correlationID, eventType string)
+		Forget(correlationID string)
+	}
+
+	// pendingMessage is a message accepted while the broker was unreachable
+	pendingMessage struct {
+		routingKey string
+		eventID    string
+		dedupID    string
+		headers    map[string]string // Attribute headers, see headers
+		body       []byte
+		bufferedAt time.Time
+	}
+)
+
+func (c amqpConnection) Channel() (channel, error) {
+	ch, err := c.Connection.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	return ch, nil
+}
+
 // Publisher handles the publication of events to a message broker
 type Publisher struct {
-	conn    *amqp.Connection // Connection to the message broker
-	channel *amqp.Channel    // Channel for publishing messages
-	logger  *logger.Logger   // Logger for error tracking and debugging
-	cfg     *config.Config   // Configuration settings
+	conn    connection     // Connection to the message broker
+	channel channel        // Channel for publishing messages
+	logger  *logger.Logger // Logger for error tracking and debugging
+	cfg     *config.Config // Configuration settings
+
+	dial    func() (connection, error) // Opens a fresh connection on reconnect
+	pending []pendingMessage           // Messages buffered during an outage
+	store   *pendingStore              // Persists pending, nil keeps it in memory only
+	tracker Tracker                    // Told about published events, nil for none
+	schemas map[string]int             // Payload schema IDs by event type, see UseSchemas
+	mu      sync.RWMutex               // Guards conn, channel, pending and flags
+	done    chan struct{}              // Closed when the publisher shuts down
+
+	pendingLimit      int           // Max number of buffered messages
+	reconnectDelay    time.Duration // Initial delay between reconnect attempts
+	maxReconnectDelay time.Duration // Upper bound for the reconnect backoff
+
+	isConnected bool // Connection status flag
+	closed      bool // Set once Close has been called
+
+	onReconnect func() // Called once re-connected, see OnReconnect
+
+	flushRetry *time.Timer // Retries a flush that failed while connected, nil when none is due
 }
 
 // Init creates and initializes a new Publisher instance
@@ -30,34 +125,141 @@ type Publisher struct {
 //   - *Publisher: Initialized publisher instance
 //   - error: Any error that occurred during initialization
 func Init(cfg *config.Config, logger *logger.Logger, conn *amqp.Connection) (*Publisher, error) {
+	return newPublisher(cfg, logger, amqpConnection{conn}, func() (connection, error) {
+		conn, err := amqp.Dial(cfg.Urls.Rabbitmq)
+		if err != nil {
+			return nil, err
+		}
+
+		return amqpConnection{conn}, nil
+	})
+}
+
+// newPublisher opens a channel on conn and starts watching it for closure
+func newPublisher(
+	cfg *config.Config,
+	logger *logger.Logger,
+	conn connection,
+	dial func() (connection, error),
+) (*Publisher, error) {
 	channel, err := conn.Channel()
 	if err != nil {
 		logger.Error("error opening channel", zap.Error(err))
 		conn.Close()
 		return nil, err
 	}
-	return &Publisher{
-		conn:    conn,
-		channel: channel,
-		logger:  logger,
-		cfg:     cfg,
-	}, nil
+
+	p := &Publisher{
+		conn:              conn,
+		channel:           channel,
+		logger:            logger,
+		cfg:               cfg,
+		dial:              dial,
+		done:              make(chan struct{}),
+		pendingLimit:      DEFAULT_PENDING_BUFFER,
+		reconnectDelay:    DEFAULT_RECONNECT_DELAY,
+		maxReconnectDelay: DEFAULT_MAX_RECONNECT_DELAY,
+		isConnected:       true,
+	}
+
+	if cfg.Publisher.PendingBuffer > 0 {
+		p.pendingLimit = cfg.Publisher.PendingBuffer
+	}
+
+	if cfg.Publisher.ReconnectDelay > 0 {
+		p.reconnectDelay = time.Duration(cfg.Publisher.ReconnectDelay) * time.Second
+	}
+
+	if cfg.Publisher.MaxReconnectDelay > 0 {
+		p.maxReconnectDelay = time.Duration(cfg.Publisher.MaxReconnectDelay) * time.Second
+	}
+
+	if cfg.Publisher.PendingFile != "" {
+		p.store = &pendingStore{path: cfg.Publisher.PendingFile}
+		p.replay()
+	}
+
+	p.watch(conn, channel)
+
+	return p, nil
+}
+
+// replay delivers the events a previous process left in the pending store.
+// It runs before the publisher is handed out, so replayed events go out
+// ahead of new ones
+func (p *Publisher) replay() {
+	stored, err := p.store.load()
+	if err != nil {
+		p.logger.Error("error load pending events", zap.Error(err))
+		return
+	}
+
+	if len(stored) == 0 {
+		p.observeBacklog()
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending = stored
+	flushed := p.flushLocked()
+
+	p.logger.Info("replayed persisted events",
+		zap.Int("flushed", flushed),
+		zap.Int("pending", len(p.pending)))
 }
 
 // Close properly closes the publisher's channel and connection
 // Returns an error if closing either the channel or connection fails
 func (p *Publisher) Close() error {
-	if err := p.channel.Close(); err != nil {
-		p.logger.Error("error closing channel", zap.Error(err))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+	p.isConnected = false
+	close(p.done)
+
+	if p.flushRetry != nil {
+		p.flushRetry.Stop()
+		p.flushRetry = nil
+	}
+
+	if len(p.pending) > 0 {
+		p.logger.Warn("closing publisher with undelivered messages",
+			zap.Int("pending", len(p.pending)),
+			zap.Bool("persisted", p.store != nil))
+	}
+
+	if p.channel != nil {
+		if err := p.channel.Close(); err != nil {
+			p.logger.Error("error closing channel", zap.Error(err))
+		}
 	}
+
+	if p.conn == nil {
+		return nil
+	}
+
 	return p.conn.Close()
 }
 
 func (p *Publisher) IsHealthy() bool {
-	return !p.conn.IsClosed()
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.isConnected && p.conn != nil && !p.conn.IsClosed()
 }
 
 // Publish sends a message to the message broker
+// While the broker is unreachable the message is buffered (up to the configured
+// pending buffer size) and delivered once the connection is re-established.
+// While earlier messages are still buffered it queues up behind them, so
+// events go out in the order they were published
 // Parameters:
 //   - poll: Data to be published (will be JSON encoded)
 //   - routingKey: Routing key for message delivery
@@ -74,9 +276,10 @@ func (p *Publisher) Publish(poll any, routingKey string) error {
 
 	// Create a new event with the JSON payload
 	event := entity.NewEvent(routingKey, pollJson)
+	event.DedupID = dedupID(poll, pollJson, routingKey)
 
 	// Convert the event to JSON
-	eventJson, err := json.Marshal(event)
+	eventJson, err := envelope.Marshal(event, p.cfg.Publisher.Envelope)
 	if err != nil {
 		p.logger.Error("error encode event for publish",
 			zap.String("event_id", event.ID),
@@ -85,21 +288,50 @@ func (p *Publisher) Publish(poll any, routingKey string) error {
 		return err
 	}
 
-	// Publish the event to the message broker
-	err = p.channel.Publish(
-		p.cfg.Exchange.Output, // exchange
-		routingKey,            // routing key
-		false,                 // mandatory
-		false,                 // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        eventJson,
-			Timestamp:   time.Now(),
-		},
-	)
-	if err != nil {
-		p.logger.Error("error publishing event")
-		return err
+	msg := pendingMessage{
+		routingKey: routingKey,
+		eventID:    event.ID,
+		dedupID:    event.DedupID,
+		headers:    headers(poll, routingKey),
+		body:       eventJson,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Expected before sending, the event may come back before send returns
+	if p.tracker != nil {
+		p.tracker.Expect(event.Correlation(), routingKey)
+	}
+
+	if id, ok := p.schemas[routingKey]; ok {
+		msg.headers[HEADER_SCHEMA_ID] = strconv.Itoa(id)
+	}
+
+	if !p.isConnected {
+		return p.forgetFailed(event, p.bufferLocked(msg))
+	}
+
+	if len(p.pending) > 0 {
+		if err = p.bufferLocked(msg); err != nil {
+			return p.forgetFailed(event, err)
+		}
+
+		p.flushLocked()
+		return nil
+	}
+
+	if err = p.send(msg); err != nil {
+		if errors.Is(err, amqp.ErrClosed) {
+			// The close notification has not reached the watcher yet
+			p.isConnected = false
+			return p.forgetFailed(event, p.bufferLocked(msg))
+		}
+
+		p.logger.Error("error publishing event",
+			zap.String("event_id", event.ID),
+			zap.Error(err))
+		return p.forgetFailed(event, err)
 	}
 
 	// Log successful publication
@@ -109,3 +341,341 @@ func (p *Publisher) Publish(poll any, routingKey string) error {
 
 	return nil
 }
+
+// Track tells tracker about every event published from now on. Events
+// buffered during an outage are expected back as well, so an outage outlasting
+// the patience of tracker shows as missing events.
+func (p *Publisher) Track(tracker Tracker) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.tracker = tracker
+}
+
+// OnReconnect registers a function called whenever the publisher got its
+// connection back, after the pending buffer was flushed. It may publish.
+func (p *Publisher) OnReconnect(hook func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.onReconnect = hook
+}
+
+// UseSchemas sends the schema ID of their payload, keyed by event type, in
+// the headers of the events published from now on
+func (p *Publisher) UseSchemas(ids map[string]int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.schemas = ids
+}
+
+// forgetFailed withdraws the expectation of event from the tracker when
+// publishing it failed with err, which the caller reports already
+// Returns err
+// Callers must hold p.mu
+func (p *Publisher) forgetFailed(event *entity.Event, err error) error {
+	if err != nil && p.tracker != nil {
+		p.tracker.Forget(event.Correlation())
+	}
+
+	return err
+}
+
+// dedupID derives the deduplication ID of an event from the entity its
+// payload describes, the revision of it and the operation, so publishing the
+// same revision again, as the retry paths do, yields the same ID. Payloads
+// without a revision fall back to the digest of their encoding.
+func dedupID(poll any, payload []byte, routingKey string) string {
+	var subject string
+	if s, ok := poll.(entity.DedupSubject); ok {
+		subject = s.DedupSubject()
+	}
+
+	if v, ok := poll.(entity.DedupVersioned); ok {
+		return entity.DedupID(subject, strconv.FormatUint(uint64(v.DedupVersion()), 10), routingKey)
+	}
+
+	sum := sha256.Sum256(payload)
+
+	return entity.DedupID(subject, hex.EncodeToString(sum[:]), routingKey)
+}
+
+// headers returns the message headers of an event: its type and the
+// attributes of an Attributed payload
+func headers(poll any, routingKey string) map[string]string {
+	headers := map[string]string{HEADER_EVENT_TYPE: routingKey}
+
+	if a, ok := poll.(entity.Attributed); ok {
+		for name, value := range a.Attributes() {
+			headers[name] = value
+		}
+	}
+
+	return headers
+}
+
+// exchanges returns the exchanges every event is published to: the output
+// exchange, the headers exchange when configured and, while dual publishing
+// during a topology cutover, the legacy one
+func (p *Publisher) exchanges() []string {
+	exchanges := []string{p.cfg.Exchange.Output}
+
+	if headers := p.cfg.Exchange.Headers; headers != "" && headers != p.cfg.Exchange.Output {
+		exchanges = append(exchanges, headers)
+	}
+
+	topology := p.cfg.Topology
+	if topology.DualPublish && topology.LegacyOutputExchange != "" && topology.LegacyOutputExchange != p.cfg.Exchange.Output {
+		exchanges = append(exchanges, topology.LegacyOutputExchange)
+	}
+
+	return exchanges
+}
+
+// send publishes a single prepared message on the current channel
+// The same body, and so the same event ID, goes to every exchange, letting
+// consumers drop the copy they receive twice. A failed send is retried on all
+// exchanges, so a copy may be delivered more than once
+// Callers must hold p.mu
+func (p *Publisher) send(msg pendingMessage) error {
+	for _, exchange := range p.exchanges() {
+		headers := make(amqp.Table, len(msg.headers))
+		for name, value := range msg.headers {
+			headers[name] = value
+		}
+
+		_, end := telemetry.StartAMQP(context.Background(), telemetry.AMQP_PUBLISH, exchange, msg.routingKey, headers)
+
+		err := p.channel.Publish(
+			exchange,       // exchange
+			msg.routingKey, // routing key
+			false,          // mandatory
+			false,          // immediate
+			amqp.Publishing{
+				ContentType: "application/json",
+				MessageId:   msg.dedupID,
+				Headers:     headers,
+				Body:        msg.body,
+				Timestamp:   time.Now(),
+			},
+		)
+
+		end(err)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bufferLocked stores a message until the connection is restored
+// Callers must hold p.mu
+func (p *Publisher) bufferLocked(msg pendingMessage) error {
+	if p.closed {
+		return fmt.Errorf("publisher is closed")
+	}
+
+	if len(p.pending) >= p.pendingLimit {
+		p.logger.Error("dropping event, pending buffer is full",
+			zap.String("event_id", msg.eventID),
+			zap.Int("pending", len(p.pending)))
+		return ErrBufferFull
+	}
+
+	msg.bufferedAt = time.Now()
+	p.pending = append(p.pending, msg)
+	p.persistLocked()
+
+	p.logger.Warn("broker unavailable, event buffered",
+		zap.String("event_id", msg.eventID),
+		zap.Int("pending", len(p.pending)))
+
+	return nil
+}
+
+// watch registers close listeners on the connection and channel and starts
+// a goroutine that triggers the reconnection loop once either of them closes.
+// The goroutine returns when the publisher is closed
+func (p *Publisher) watch(conn connection, channel channel) {
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chanClosed := channel.NotifyClose(make(chan *amqp.Error, 1))
+
+	go func() {
+		var reason *amqp.Error
+
+		select {
+		case <-p.done:
+			return
+		case reason = <-connClosed:
+		case reason = <-chanClosed:
+		}
+
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return
+		}
+		p.isConnected = false
+		p.mu.Unlock()
+
+		p.logger.Warn("publisher connection lost, reconnecting...", zap.Any("reason", reason))
+
+		p.reconnect()
+	}()
+}
+
+// reconnect re-dials the broker with exponential backoff until it succeeds
+// or the publisher is closed, then flushes the pending buffer
+func (p *Publisher) reconnect() {
+	delay := p.reconnectDelay
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-time.After(delay):
+		}
+
+		conn, channel, err := p.open()
+		if err != nil {
+			p.logger.Error("failed to reconnect publisher",
+				zap.Duration("retry_in", delay),
+				zap.Error(err))
+
+			delay = min(delay*2, p.maxReconnectDelay)
+			continue
+		}
+
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			channel.Close()
+			conn.Close()
+			return
+		}
+
+		if p.conn != nil {
+			p.conn.Close()
+		}
+
+		p.conn = conn
+		p.channel = channel
+		p.isConnected = true
+
+		flushed := p.flushLocked()
+		onReconnect := p.onReconnect
+		p.mu.Unlock()
+
+		p.logger.Info("publisher reconnected to RabbitMQ", zap.Int("flushed", flushed))
+
+		if onReconnect != nil {
+			onReconnect()
+		}
+
+		p.watch(conn, channel)
+		return
+	}
+}
+
+// open dials a new connection and opens a channel on it
+func (p *Publisher) open() (connection, channel, error) {
+	conn, err := p.dial()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to dial RabbitMQ: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	return conn, channel, nil
+}
+
+// flushLocked delivers buffered messages in order, keeping the first that
+// fails and those after it. A closed channel is left to the watcher to
+// reconnect; other failures are retried after the reconnect delay
+// Callers must hold p.mu. Returns the number of delivered messages
+func (p *Publisher) flushLocked() int {
+	for i, msg := range p.pending {
+		if err := p.send(msg); err != nil {
+			p.logger.Error("error flushing pending event",
+				zap.String("event_id", msg.eventID),
+				zap.Error(err))
+
+			p.pending = p.pending[i:]
+			p.persistLocked()
+
+			if errors.Is(err, amqp.ErrClosed) {
+				p.isConnected = false
+			} else {
+				p.retryFlushLocked()
+			}
+
+			return i
+		}
+	}
+
+	flushed := len(p.pending)
+	p.pending = nil
+	p.persistLocked()
+
+	return flushed
+}
+
+// retryFlushLocked flushes the pending buffer again after the reconnect
+// delay, unless a retry is due already
+// Callers must hold p.mu
+func (p *Publisher) retryFlushLocked() {
+	if p.flushRetry != nil || p.closed {
+		return
+	}
+
+	p.flushRetry = time.AfterFunc(p.reconnectDelay, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.flushRetry = nil
+		if p.closed || !p.isConnected || len(p.pending) == 0 {
+			return
+		}
+
+		flushed := p.flushLocked()
+		p.logger.Info("retried flushing pending events",
+			zap.Int("flushed", flushed),
+			zap.Int("pending", len(p.pending)))
+	})
+}
+
+// persistLocked writes the pending buffer to the store and updates the
+// backlog metrics. A failed write is logged, the buffer stays in memory
+// Callers must hold p.mu
+func (p *Publisher) persistLocked() {
+	p.observeBacklog()
+
+	if p.store == nil {
+		return
+	}
+
+	if err := p.store.save(p.pending); err != nil {
+		p.logger.Error("error persist pending events",
+			zap.Int("pending", len(p.pending)),
+			zap.Error(err))
+	}
+}
+
+// observeBacklog exports the size and age of the pending buffer
+func (p *Publisher) observeBacklog() {
+	metrics.PublisherBacklog.Set(float64(len(p.pending)))
+
+	if len(p.pending) == 0 {
+		metrics.PublisherBacklogOldest.Set(0)
+		return
+	}
+
+	metrics.PublisherBacklogOldest.Set(float64(p.pending[0].bufferedAt.Unix()))
+}
@@ -0,0 +1,525 @@
+package publisher
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/config"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeChannel is an in-memory AMQP channel test double
+type fakeChannel struct {
+	mu        sync.Mutex
+	published []amqp.Publishing
+	keys      []string
+	exchanges []string
+	err       error
+	fail      func(published int) error // Fails a send after as many successful ones, nil for none
+	notify    []chan *amqp.Error
+	closed    bool
+}
+
+func (f *fakeChannel) Publish(exchange, key string, _, _ bool, msg amqp.Publishing) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	if f.fail != nil {
+		if err := f.fail(len(f.published)); err != nil {
+			return err
+		}
+	}
+
+	f.exchanges = append(f.exchanges, exchange)
+	f.keys = append(f.keys, key)
+	f.published = append(f.published, msg)
+
+	return nil
+}
+
+func (f *fakeChannel) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.notify = append(f.notify, receiver)
+	return receiver
+}
+
+func (f *fakeChannel) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	return nil
+}
+
+func (f *fakeChannel) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.published)
+}
+
+// fakeConnection is an in-memory AMQP connection test double
+type fakeConnection struct {
+	mu      sync.Mutex
+	channel *fakeChannel
+	notify  []chan *amqp.Error
+	closed  bool
+	chanErr error
+}
+
+func newFakeConnection() *fakeConnection {
+	return &fakeConnection{channel: &fakeChannel{}}
+}
+
+func (f *fakeConnection) Channel() (channel, error) {
+	if f.chanErr != nil {
+		return nil, f.chanErr
+	}
+
+	return f.channel, nil
+}
+
+func (f *fakeConnection) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Like amqp091, listeners registered on a closed connection fire immediately
+	if f.closed {
+		close(receiver)
+		return receiver
+	}
+
+	f.notify = append(f.notify, receiver)
+	return receiver
+}
+
+func (f *fakeConnection) IsClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.closed
+}
+
+func (f *fakeConnection) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	return nil
+}
+
+// drop simulates the broker closing the connection
+func (f *fakeConnection) drop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	for _, ch := range f.notify {
+		ch <- &amqp.Error{Code: amqp.ConnectionForced, Reason: "broker restart"}
+	}
+}
+
+func testConfig() *config.Config {
+	cfg, _ := config.Init("")
+	cfg.Publisher.PendingBuffer = 2
+	cfg.Publisher.PendingFile = ""
+	return cfg
+}
+
+func setupPublisher(t *testing.T, dial func() (connection, error)) (*Publisher, *fakeConnection) {
+	t.Helper()
+
+	conn := newFakeConnection()
+	if dial == nil {
+		dial = func() (connection, error) { return nil, errors.New("no broker") }
+	}
+
+	p, err := newPublisher(testConfig(), &logger.Logger{Logger: zap.NewNop()}, conn, dial)
+	require.NoError(t, err)
+
+	p.reconnectDelay = time.Millisecond
+	p.maxReconnectDelay = 5 * time.Millisecond
+
+	t.Cleanup(func() { p.Close() })
+
+	return p, conn
+}
+
+func TestPublisher_Init_ChannelError(t *testing.T) {
+	conn := newFakeConnection()
+	conn.chanErr = errors.New("channel error")
+
+	p, err := newPublisher(testConfig(), &logger.Logger{Logger: zap.NewNop()}, conn, nil)
+
+	assert.Error(t, err)
+	assert.Nil(t, p)
+	assert.True(t, conn.IsClosed())
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	t.Run("wraps payload in an event envelope", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+
+		err := p.Publish(map[string]string{"form_id": "42"}, "form.deleted")
+		require.NoError(t, err)
+
+		require.Equal(t, 1, conn.channel.count())
+		assert.Equal(t, "output", conn.channel.exchanges[0])
+		assert.Equal(t, "form.deleted", conn.channel.keys[0])
+
+		var event entity.Event
+		require.NoError(t, json.Unmarshal(conn.channel.published[0].Body, &event))
+		assert.Equal(t, "form.deleted", event.Type)
+		assert.JSONEq(t, `{"form_id":"42"}`, string(event.Payload))
+		assert.NoError(t, event.Validate())
+	})
+
+	t.Run("publishes to the legacy exchange while dual publishing", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		p.cfg.Topology.DualPublish = true
+		p.cfg.Topology.LegacyOutputExchange = "output.v1"
+
+		require.NoError(t, p.Publish("payload", "form.created"))
+
+		require.Equal(t, 2, conn.channel.count())
+		assert.Equal(t, []string{"output", "output.v1"}, conn.channel.exchanges)
+		assert.Equal(t, conn.channel.published[0].Body, conn.channel.published[1].Body)
+	})
+
+	t.Run("sends attribute headers and publishes to the headers exchange", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		p.cfg.Exchange.Headers = "events"
+
+		form := &entity.Form{ID: uuid.New(), TenantID: "acme", Status: entity.StatusClosed}
+		require.NoError(t, p.Publish(form, "form.updated"))
+
+		require.Equal(t, 2, conn.channel.count())
+		assert.Equal(t, []string{"output", "events"}, conn.channel.exchanges)
+		assert.Equal(t, amqp.Table{
+			HEADER_EVENT_TYPE:          "form.updated",
+			entity.AttributeTenant:     "acme",
+			entity.AttributeFormStatus: "closed",
+		}, conn.channel.published[1].Headers)
+	})
+
+	t.Run("sends the schema id of the payload", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		p.UseSchemas(map[string]int{"form.deleted": 7})
+
+		require.NoError(t, p.Publish(map[string]string{"form_id": "42"}, "form.deleted"))
+		require.NoError(t, p.Publish("payload", "form.created"))
+
+		assert.Equal(t, "7", conn.channel.published[0].Headers[HEADER_SCHEMA_ID])
+		assert.NotContains(t, conn.channel.published[1].Headers, HEADER_SCHEMA_ID)
+	})
+
+	t.Run("republishing the same change keeps the dedup id", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		form := &entity.Form{ID: uuid.New(), Title: "Survey", Version: 1}
+
+		require.NoError(t, p.Publish(form, "form.updated"))
+		form.UpdatedAt = time.Now() // Encoded differently, same revision
+		require.NoError(t, p.Publish(form, "form.updated"))
+		form.Title, form.Version = "Renamed survey", 2
+		require.NoError(t, p.Publish(form, "form.updated"))
+		require.NoError(t, p.Publish(form, "form.deleted"))
+
+		events := make([]entity.Event, conn.channel.count())
+		for i, msg := range conn.channel.published {
+			require.NoError(t, json.Unmarshal(msg.Body, &events[i]))
+			assert.Equal(t, events[i].DedupID, msg.MessageId)
+		}
+
+		assert.NotEqual(t, events[0].ID, events[1].ID)
+		assert.Equal(t, events[0].DedupID, events[1].DedupID)
+		assert.NotEqual(t, events[1].DedupID, events[2].DedupID, "new version")
+		assert.NotEqual(t, events[2].DedupID, events[3].DedupID, "new operation")
+	})
+
+	t.Run("returns encode errors", func(t *testing.T) {
+		p, _ := setupPublisher(t, nil)
+
+		err := p.Publish(make(chan int), "form.created")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns broker errors other than closed", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		conn.channel.err = errors.New("exchange not found")
+
+		err := p.Publish("payload", "form.created")
+
+		assert.EqualError(t, err, "exchange not found")
+		assert.True(t, p.IsHealthy())
+	})
+
+	t.Run("buffers when channel reports closed", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		conn.channel.err = amqp.ErrClosed
+
+		err := p.Publish("payload", "form.created")
+
+		assert.NoError(t, err)
+		assert.False(t, p.IsHealthy())
+		assert.Len(t, p.pending, 1)
+	})
+
+	t.Run("fails once the pending buffer is full", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		conn.channel.err = amqp.ErrClosed
+
+		require.NoError(t, p.Publish("one", "form.created"))
+		require.NoError(t, p.Publish("two", "form.created"))
+
+		err := p.Publish("three", "form.created")
+
+		assert.ErrorIs(t, err, ErrBufferFull)
+	})
+}
+
+// fakeTracker records the events a publisher tracks
+type fakeTracker struct {
+	expected  map[string]string // Event types by correlation ID
+	forgotten []string
+}
+
+func (f *fakeTracker) Expect(correlationID, eventType string) {
+	f.expected[correlationID] = eventType
+}
+
+func (f *fakeTracker) Forget(correlationID string) {
+	f.forgotten = append(f.forgotten, correlationID)
+}
+
+func TestPublisher_Track(t *testing.T) {
+	t.Run("expects published events by correlation id", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		tracker := &fakeTracker{expected: make(map[string]string)}
+		p.Track(tracker)
+
+		require.NoError(t, p.Publish("payload", "form.created"))
+
+		var event entity.Event
+		require.NoError(t, json.Unmarshal(conn.channel.published[0].Body, &event))
+		assert.Equal(t, map[string]string{event.Correlation(): "form.created"}, tracker.expected)
+		assert.Empty(t, tracker.forgotten)
+	})
+
+	t.Run("keeps expecting buffered events", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		tracker := &fakeTracker{expected: make(map[string]string)}
+		p.Track(tracker)
+		conn.channel.err = amqp.ErrClosed
+
+		require.NoError(t, p.Publish("payload", "form.created"))
+
+		assert.Len(t, tracker.expected, 1)
+		assert.Empty(t, tracker.forgotten)
+	})
+
+	t.Run("forgets events that failed to publish", func(t *testing.T) {
+		p, conn := setupPublisher(t, nil)
+		tracker := &fakeTracker{expected: make(map[string]string)}
+		p.Track(tracker)
+		conn.channel.err = errors.New("exchange not found")
+
+		require.Error(t, p.Publish("payload", "form.created"))
+
+		require.Len(t, tracker.expected, 1)
+		for correlationID := range tracker.expected {
+			assert.Equal(t, []string{correlationID}, tracker.forgotten)
+		}
+	})
+}
+
+func TestPublisher_Reconnect(t *testing.T) {
+	t.Run("re-dials after close and flushes pending messages", func(t *testing.T) {
+		next := newFakeConnection()
+		dialed := make(chan struct{}, 1)
+
+		p, conn := setupPublisher(t, func() (connection, error) {
+			dialed <- struct{}{}
+			return next, nil
+		})
+
+		conn.channel.err = amqp.ErrClosed
+		require.NoError(t, p.Publish("buffered", "form.updated"))
+
+		conn.drop()
+
+		select {
+		case <-dialed:
+		case <-time.After(time.Second):
+			t.Fatal("publisher did not re-dial")
+		}
+
+		assert.Eventually(t, p.IsHealthy, time.Second, time.Millisecond)
+		assert.Equal(t, 1, next.channel.count())
+
+		require.NoError(t, p.Publish("fresh", "form.updated"))
+		assert.Equal(t, 2, next.channel.count())
+	})
+
+	t.Run("keeps the order of events when a flush fails midway", func(t *testing.T) {
+		var failing atomic.Bool
+		failing.Store(true)
+
+		next := newFakeConnection()
+		next.channel.fail = func(published int) error {
+			if failing.Load() && published == 1 {
+				return errors.New("channel flow")
+			}
+			return nil
+		}
+
+		p, conn := setupPublisher(t, func() (connection, error) { return next, nil })
+
+		conn.channel.err = amqp.ErrClosed
+		require.NoError(t, p.Publish("first", "form.created"))
+		require.NoError(t, p.Publish("second", "form.updated"))
+
+		conn.drop()
+		require.Eventually(t, p.IsHealthy, time.Second, time.Millisecond)
+		require.Equal(t, 1, next.channel.count(), "the flush stops at the failing event")
+
+		require.NoError(t, p.Publish("third", "form.deleted"))
+		assert.Equal(t, 1, next.channel.count(), "new events queue up behind the backlog")
+
+		failing.Store(false)
+
+		require.Eventually(t, func() bool { return next.channel.count() == 3 }, time.Second, time.Millisecond,
+			"the backlog is retried without another reconnect")
+		next.channel.mu.Lock()
+		assert.Equal(t, []string{"form.created", "form.updated", "form.deleted"}, next.channel.keys)
+		next.channel.mu.Unlock()
+	})
+
+	t.Run("tells the reconnect hook, which may publish", func(t *testing.T) {
+		next := newFakeConnection()
+
+		p, conn := setupPublisher(t, func() (connection, error) {
+			return next, nil
+		})
+
+		reconnected := make(chan error, 1)
+		p.OnReconnect(func() { reconnected <- p.Publish("reconnected", "ops.broker.reconnected") })
+
+		conn.drop()
+
+		select {
+		case err := <-reconnected:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("reconnect hook not called")
+		}
+		assert.Equal(t, 1, next.channel.count())
+	})
+
+	t.Run("keeps retrying with backoff while the broker is down", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			attempts int
+		)
+		next := newFakeConnection()
+
+		p, conn := setupPublisher(t, func() (connection, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			attempts++
+			if attempts < 3 {
+				return nil, errors.New("connection refused")
+			}
+			return next, nil
+		})
+
+		conn.drop()
+
+		assert.Eventually(t, p.IsHealthy, time.Second, time.Millisecond)
+
+		mu.Lock()
+		assert.Equal(t, 3, attempts)
+		mu.Unlock()
+	})
+
+	t.Run("stops reconnecting once closed", func(t *testing.T) {
+		dialed := make(chan struct{}, 10)
+
+		p, conn := setupPublisher(t, func() (connection, error) {
+			dialed <- struct{}{}
+			return nil, errors.New("connection refused")
+		})
+
+		require.NoError(t, p.Close())
+		conn.drop()
+
+		select {
+		case <-dialed:
+			t.Fatal("publisher re-dialed after close")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		assert.Error(t, p.Publish("late", "form.updated"))
+	})
+}
+
+func TestPublisher_PendingFile(t *testing.T) {
+	cfg := testConfig()
+	cfg.Publisher.PendingFile = filepath.Join(t.TempDir(), "pending.json")
+	log := &logger.Logger{Logger: zap.NewNop()}
+
+	// The first process buffers an event and dies before the broker returns
+	conn := newFakeConnection()
+	p, err := newPublisher(cfg, log, conn, func() (connection, error) { return nil, errors.New("no broker") })
+	require.NoError(t, err)
+
+	conn.channel.err = amqp.ErrClosed
+	require.NoError(t, p.Publish("payload", "form.created"))
+	require.NoError(t, p.Close())
+	assert.FileExists(t, cfg.Publisher.PendingFile)
+
+	// The next one replays it on startup
+	next := newFakeConnection()
+	p, err = newPublisher(cfg, log, next, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { p.Close() })
+
+	require.Equal(t, 1, next.channel.count())
+	assert.Equal(t, "form.created", next.channel.keys[0])
+
+	var event entity.Event
+	require.NoError(t, json.Unmarshal(next.channel.published[0].Body, &event))
+	assert.JSONEq(t, `"payload"`, string(event.Payload))
+
+	assert.Empty(t, p.pending)
+	assert.NoFileExists(t, cfg.Publisher.PendingFile)
+}
+
+func TestPublisher_Close(t *testing.T) {
+	p, conn := setupPublisher(t, nil)
+
+	assert.NoError(t, p.Close())
+	assert.NoError(t, p.Close())
+
+	assert.True(t, conn.IsClosed())
+	assert.True(t, conn.channel.closed)
+	assert.False(t, p.IsHealthy())
+}
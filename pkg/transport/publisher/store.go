@@ -0,0 +1,103 @@
+package publisher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type (
+	// pendingStore persists the pending buffer to a file so events buffered
+	// during an outage survive a restart
+	pendingStore struct {
+		path string
+	}
+
+	// storedMessage is the on-disk form of a pendingMessage
+	storedMessage struct {
+		RoutingKey string            `json:"routing_key"`
+		EventID    string            `json:"event_id"`
+		DedupID    string            `json:"dedup_id,omitempty"`
+		Headers    map[string]string `json:"headers,omitempty"`
+		Body       []byte            `json:"body"`
+		BufferedAt time.Time         `json:"buffered_at"`
+	}
+)
+
+// load reads the persisted buffer, a missing file is an empty buffer
+func (s *pendingStore) load() ([]pendingMessage, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pending events: %w", err)
+	}
+
+	var stored []storedMessage
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode pending events: %w", err)
+	}
+
+	msgs := make([]pendingMessage, len(stored))
+	for i, m := range stored {
+		msgs[i] = pendingMessage{
+			routingKey: m.RoutingKey,
+			eventID:    m.EventID,
+			dedupID:    m.DedupID,
+			headers:    m.Headers,
+			body:       m.Body,
+			bufferedAt: m.BufferedAt,
+		}
+	}
+
+	return msgs, nil
+}
+
+// save replaces the persisted buffer with msgs. The file is written next to
+// its final path and renamed, so a crash never leaves a truncated buffer
+func (s *pendingStore) save(msgs []pendingMessage) error {
+	if len(msgs) == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove pending events: %w", err)
+		}
+
+		return nil
+	}
+
+	stored := make([]storedMessage, len(msgs))
+	for i, m := range msgs {
+		stored[i] = storedMessage{
+			RoutingKey: m.routingKey,
+			EventID:    m.eventID,
+			DedupID:    m.dedupID,
+			Headers:    m.headers,
+			Body:       m.body,
+			BufferedAt: m.bufferedAt,
+		}
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode pending events: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create pending events directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write pending events: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace pending events: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,318 @@
+// Package stream buffers request events in a Redis stream between the
+// consumer and the listener. Events are acknowledged and deleted from the
+// stream once handled, so events pulled from RabbitMQ but not handled when
+// the process dies stay pending and are claimed again by the next reader of
+// the consumer group. Delivery is at least once: an event handled right before
+// a crash may be handled twice.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// EVENT_FIELD is the stream entry field holding the encoded event
+	EVENT_FIELD = "event"
+
+	// Defaults of Options
+	DEFAULT_STREAM     = "form-service:requests"
+	DEFAULT_GROUP      = "listener"
+	DEFAULT_BATCH      = 50
+	DEFAULT_BLOCK      = 2 * time.Second
+	DEFAULT_CLAIM_IDLE = time.Minute
+
+	// RETRY_DELAY is the pause after a failed read
+	RETRY_DELAY = time.Second
+)
+
+// ErrStreamFull is returned by Append while the stream holds MaxLen entries
+var ErrStreamFull = errors.New("stream is full")
+
+// appendCapped adds an entry to the stream unless it holds ARGV[1] entries
+// already, 0 for no cap. Checked and added atomically, so instances
+// appending concurrently don't overshoot the cap.
+var appendCapped = redis.NewScript(`
+local max = tonumber(ARGV[1])
+if max > 0 and redis.call('XLEN', KEYS[1]) >= max then
+	return false
+end
+return redis.call('XADD', KEYS[1], '*', ARGV[2], ARGV[3])
+`)
+
+type (
+	// Options configures a Buffer
+	Options struct {
+		Stream    string        // Stream key
+		Group     string        // Consumer group shared by every instance
+		Consumer  string        // Name of this reader in the group, e.g. the host name
+		MaxLen    int64         // Cap on the entries not handled yet, Append fails beyond it; 0 doesn't cap the stream
+		Batch     int64         // Entries read at once
+		Block     time.Duration // How long a read waits for new entries
+		ClaimIdle time.Duration // Pending entries idle for longer are claimed from their crashed reader; keep it above the longest queueing plus handling time
+	}
+
+	// Buffer is a Redis stream read by a consumer group
+	Buffer struct {
+		client    *redis.Client
+		logger    *logger.Logger
+		opts      Options
+		heartbeat func() // Reports loop activity, e.g. to a watchdog
+
+		running sync.WaitGroup // Held while Run runs
+
+		mu       sync.Mutex
+		inflight map[string]bool // Entries passed on and not acknowledged yet
+	}
+)
+
+// New creates a stream buffer, applying the defaults to unset options
+func New(client *redis.Client, logger *logger.Logger, opts Options) *Buffer {
+	if opts.Stream == "" {
+		opts.Stream = DEFAULT_STREAM
+	}
+	if opts.Group == "" {
+		opts.Group = DEFAULT_GROUP
+	}
+	if opts.Batch <= 0 {
+		opts.Batch = DEFAULT_BATCH
+	}
+	if opts.Block <= 0 {
+		opts.Block = DEFAULT_BLOCK
+	}
+	if opts.ClaimIdle <= 0 {
+		opts.ClaimIdle = DEFAULT_CLAIM_IDLE
+	}
+
+	return &Buffer{
+		client:    client,
+		logger:    logger,
+		opts:      opts,
+		heartbeat: func() {},
+		inflight:  make(map[string]bool),
+	}
+}
+
+// OnActivity registers a function called after every read, at least once
+// per Block, so supervisors can tell Run is alive. It must be called before Run.
+func (b *Buffer) OnActivity(heartbeat func()) {
+	b.heartbeat = heartbeat
+}
+
+// Append adds event to the stream. It fails with ErrStreamFull once the
+// stream holds MaxLen entries instead of trimming entries not handled yet.
+func (b *Buffer) Append(ctx context.Context, event entity.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	err = appendCapped.Run(ctx, b.client, []string{b.opts.Stream}, b.opts.MaxLen, EVENT_FIELD, data).Err()
+	if errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to append event to stream: %w", ErrStreamFull)
+	}
+	if err != nil {
+		b.logger.Error("error append event to stream",
+			zap.String("event_id", event.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to append event to stream: %w", err)
+	}
+
+	return nil
+}
+
+// Ack marks a handled event read from the stream done and deletes its
+// entry, so the stream only holds events not handled yet. Events not read
+// from the stream are ignored.
+func (b *Buffer) Ack(event entity.Event) {
+	if event.StreamID == "" {
+		return
+	}
+
+	b.mu.Lock()
+	delete(b.inflight, event.StreamID)
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.opts.Block)
+	defer cancel()
+
+	pipe := b.client.TxPipeline()
+	pipe.XAck(ctx, b.opts.Stream, b.opts.Group, event.StreamID)
+	pipe.XDel(ctx, b.opts.Stream, event.StreamID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		// The entry stays pending and is handled again once claimed
+		b.logger.Error("error acknowledge stream entry",
+			zap.String("event_id", event.ID),
+			zap.String("stream_id", event.StreamID),
+			zap.Error(err))
+	}
+}
+
+// Run reads the stream into out until ctx is done. It first claims the
+// entries left pending by crashed readers, including a previous run of
+// this one, then reads new entries.
+func (b *Buffer) Run(ctx context.Context, out chan<- entity.Event) {
+	b.running.Add(1)
+	defer b.running.Done()
+
+	for ctx.Err() == nil {
+		if err := b.createGroup(ctx); err != nil {
+			b.logger.Error("error create stream consumer group", zap.Error(err))
+			sleep(ctx, RETRY_DELAY)
+			continue
+		}
+		break
+	}
+
+	lastClaim := time.Time{}
+
+	for ctx.Err() == nil {
+		b.heartbeat()
+
+		if time.Since(lastClaim) >= b.opts.ClaimIdle {
+			if err := b.claim(ctx, out); err != nil {
+				b.logger.Error("error claim pending stream entries", zap.Error(err))
+			}
+			lastClaim = time.Now()
+		}
+
+		if err := b.read(ctx, out); err != nil && ctx.Err() == nil {
+			b.logger.Error("error read stream", zap.Error(err))
+			sleep(ctx, RETRY_DELAY)
+		}
+	}
+}
+
+// Close waits for Run to return. Cancel its context first.
+func (b *Buffer) Close() error {
+	b.running.Wait()
+	return nil
+}
+
+// createGroup creates the consumer group, reading the stream from its start
+func (b *Buffer) createGroup(ctx context.Context) error {
+	err := b.client.XGroupCreateMkStream(ctx, b.opts.Stream, b.opts.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	return nil
+}
+
+// claim takes over the entries idle for longer than ClaimIdle and passes
+// them on
+func (b *Buffer) claim(ctx context.Context, out chan<- entity.Event) error {
+	start := "0-0"
+
+	for {
+		messages, next, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   b.opts.Stream,
+			Group:    b.opts.Group,
+			Consumer: b.opts.Consumer,
+			MinIdle:  b.opts.ClaimIdle,
+			Start:    start,
+			Count:    b.opts.Batch,
+		}).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(messages) > 0 {
+			b.logger.Warn("claimed pending stream entries", zap.Int("count", len(messages)))
+		}
+
+		if err = b.deliver(ctx, messages, out); err != nil {
+			return err
+		}
+
+		if next == "0-0" || len(messages) == 0 {
+			return nil
+		}
+		start = next
+	}
+}
+
+// read waits for new entries and passes them on
+func (b *Buffer) read(ctx context.Context, out chan<- entity.Event) error {
+	streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    b.opts.Group,
+		Consumer: b.opts.Consumer,
+		Streams:  []string{b.opts.Stream, ">"},
+		Count:    b.opts.Batch,
+		Block:    b.opts.Block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil // Nothing new
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, stream := range streams {
+		if err = b.deliver(ctx, stream.Messages, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliver decodes entries and sends them to out. Undecodable entries are
+// acknowledged and dropped, they would never decode.
+func (b *Buffer) deliver(ctx context.Context, messages []redis.XMessage, out chan<- entity.Event) error {
+	for _, msg := range messages {
+		var event entity.Event
+
+		data, _ := msg.Values[EVENT_FIELD].(string)
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			b.logger.Error("dropping undecodable stream entry",
+				zap.String("stream_id", msg.ID),
+				zap.Error(err))
+			b.Ack(entity.Event{StreamID: msg.ID})
+			continue
+		}
+
+		// Entries of this reader still being handled are claimed back
+		// when they took long, don't hand them out twice
+		b.mu.Lock()
+		handling := b.inflight[msg.ID]
+		b.inflight[msg.ID] = true
+		b.mu.Unlock()
+
+		if handling {
+			continue
+		}
+
+		event.StreamID = msg.ID
+
+		select {
+		case out <- event:
+		case <-ctx.Done():
+			b.mu.Lock()
+			delete(b.inflight, msg.ID)
+			b.mu.Unlock()
+			return ctx.Err() // Stays pending for the next run
+		}
+	}
+
+	return nil
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,172 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupBuffer(t *testing.T, consumer string) (*Buffer, *redis.Client) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return New(client, &logger.Logger{Logger: zap.NewNop()}, Options{
+		Consumer:  consumer,
+		Block:     20 * time.Millisecond,
+		ClaimIdle: 50 * time.Millisecond,
+	}), client
+}
+
+// run runs buffer until the test ends
+func run(t *testing.T, buffer *Buffer) chan entity.Event {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan entity.Event, 10)
+
+	go buffer.Run(ctx, out)
+	t.Cleanup(func() {
+		cancel()
+		buffer.Close()
+	})
+
+	return out
+}
+
+func receive(t *testing.T, out chan entity.Event) entity.Event {
+	t.Helper()
+
+	select {
+	case event := <-out:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event delivered")
+		return entity.Event{}
+	}
+}
+
+func pending(t *testing.T, client *redis.Client) int64 {
+	t.Helper()
+
+	summary, err := client.XPending(context.Background(), DEFAULT_STREAM, DEFAULT_GROUP).Result()
+	require.NoError(t, err)
+
+	return summary.Count
+}
+
+func TestBuffer_DeliversAndAcks(t *testing.T) {
+	buffer, client := setupBuffer(t, "a")
+	out := run(t, buffer)
+
+	require.NoError(t, buffer.Append(context.Background(), entity.Event{
+		ID:      "event-1",
+		Type:    "request.form.created",
+		Payload: []byte(`{"title":"Survey"}`),
+	}))
+
+	event := receive(t, out)
+	assert.Equal(t, "event-1", event.ID)
+	assert.Equal(t, "request.form.created", event.Type)
+	assert.JSONEq(t, `{"title":"Survey"}`, string(event.Payload))
+	require.NotEmpty(t, event.StreamID)
+	assert.EqualValues(t, 1, pending(t, client), "unacknowledged until handled")
+
+	buffer.Ack(event)
+	assert.EqualValues(t, 0, pending(t, client))
+	assert.EqualValues(t, 0, client.XLen(context.Background(), DEFAULT_STREAM).Val(), "handled entries are deleted")
+}
+
+func TestBuffer_AppendFailsWhenFull(t *testing.T) {
+	buffer, client := setupBuffer(t, "a")
+	buffer.opts.MaxLen = 2
+	ctx := context.Background()
+
+	require.NoError(t, buffer.Append(ctx, entity.Event{ID: "event-1"}))
+	require.NoError(t, buffer.Append(ctx, entity.Event{ID: "event-2"}))
+
+	err := buffer.Append(ctx, entity.Event{ID: "event-3"})
+	assert.ErrorIs(t, err, ErrStreamFull)
+	assert.EqualValues(t, 2, client.XLen(ctx, DEFAULT_STREAM).Val(), "entries not handled yet are kept")
+
+	out := run(t, buffer)
+	buffer.Ack(receive(t, out))
+
+	assert.NoError(t, buffer.Append(ctx, entity.Event{ID: "event-3"}), "handled entries free their place")
+}
+
+func TestBuffer_ClaimsEntriesOfCrashedReader(t *testing.T) {
+	buffer, client := setupBuffer(t, "b")
+	ctx := context.Background()
+
+	require.NoError(t, buffer.createGroup(ctx))
+	require.NoError(t, buffer.Append(ctx, entity.Event{ID: "event-1", Type: "request.form.created"}))
+
+	// Reader a takes the entry and dies before acknowledging it
+	_, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    DEFAULT_GROUP,
+		Consumer: "a",
+		Streams:  []string{DEFAULT_STREAM, ">"},
+	}).Result()
+	require.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	out := run(t, buffer)
+
+	event := receive(t, out)
+	assert.Equal(t, "event-1", event.ID)
+
+	buffer.Ack(event)
+	assert.EqualValues(t, 0, pending(t, client))
+}
+
+func TestBuffer_DoesNotRedeliverEventsBeingHandled(t *testing.T) {
+	buffer, _ := setupBuffer(t, "a")
+	out := run(t, buffer)
+
+	require.NoError(t, buffer.Append(context.Background(), entity.Event{ID: "event-1"}))
+	receive(t, out)
+
+	// Handling outlasts ClaimIdle, the entry is claimed back but not handed out again
+	select {
+	case event := <-out:
+		t.Fatalf("event %s delivered twice", event.ID)
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestBuffer_DropsUndecodableEntries(t *testing.T) {
+	buffer, client := setupBuffer(t, "a")
+	ctx := context.Background()
+
+	require.NoError(t, client.XAdd(ctx, &redis.XAddArgs{
+		Stream: DEFAULT_STREAM,
+		Values: map[string]any{EVENT_FIELD: "not json"},
+	}).Err())
+	require.NoError(t, buffer.Append(ctx, entity.Event{ID: "event-1"}))
+
+	out := run(t, buffer)
+
+	assert.Equal(t, "event-1", receive(t, out).ID)
+	assert.EqualValues(t, 1, pending(t, client), "the undecodable entry is acknowledged")
+}
+
+func TestBuffer_AckIgnoresEventsNotFromStream(t *testing.T) {
+	buffer, client := setupBuffer(t, "a")
+
+	require.NoError(t, buffer.createGroup(context.Background()))
+	buffer.Ack(entity.Event{ID: "event-1"})
+
+	assert.EqualValues(t, 0, pending(t, client))
+}
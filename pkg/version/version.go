@@ -0,0 +1,79 @@
+// Package version describes the running build of the service: the commit it
+// was built from, the database schema it expects and the enabled features.
+//
+// GitSHA and BuildTime are set at link time, e.g.
+//
+//	go build -ldflags "-X github.com/Koyo-os/form-service/pkg/version.GitSHA=$(git rev-parse HEAD) \
+//	  -X github.com/Koyo-os/form-service/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+)
+
+// UNKNOWN is reported for build details that were not provided
+const UNKNOWN = "unknown"
+
+var (
+	GitSHA    = UNKNOWN // Commit the binary was built from
+	BuildTime = UNKNOWN // UTC build timestamp
+)
+
+// Info is the version report served on /version and published on startup
+type Info struct {
+	Service       string   `json:"service"`
+	GitSHA        string   `json:"git_sha"`
+	BuildTime     string   `json:"build_time"`
+	GoVersion     string   `json:"go_version"`
+	SchemaVersion int      `json:"schema_version"` // Schema version recorded in the database
+	Features      []string `json:"features"`       // Enabled feature flags, sorted
+}
+
+// Get builds the version report. When GitSHA or BuildTime were not set via
+// ldflags, the VCS information embedded by the Go toolchain is used instead.
+func Get(service string, schemaVersion int, features map[string]bool) Info {
+	info := Info{
+		Service:       service,
+		GitSHA:        GitSHA,
+		BuildTime:     BuildTime,
+		GoVersion:     runtime.Version(),
+		SchemaVersion: schemaVersion,
+		Features:      []string{},
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.GitSHA == UNKNOWN:
+				info.GitSHA = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == UNKNOWN:
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	for name, enabled := range features {
+		if enabled {
+			info.Features = append(info.Features, name)
+		}
+	}
+
+	sort.Strings(info.Features)
+
+	return info
+}
+
+// Handler serves info as JSON
+func Handler(info Info) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(info); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
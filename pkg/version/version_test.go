@@ -0,0 +1,38 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	GitSHA, BuildTime = "abc123", "2025-01-01T00:00:00Z"
+	t.Cleanup(func() { GitSHA, BuildTime = UNKNOWN, UNKNOWN })
+
+	info := Get("form-service", 3, map[string]bool{"watchdog": true, "dry_run": true, "outbox": false})
+
+	assert.Equal(t, "form-service", info.Service)
+	assert.Equal(t, "abc123", info.GitSHA)
+	assert.Equal(t, "2025-01-01T00:00:00Z", info.BuildTime)
+	assert.Equal(t, 3, info.SchemaVersion)
+	assert.Equal(t, []string{"dry_run", "watchdog"}, info.Features)
+}
+
+func TestHandler(t *testing.T) {
+	info := Get("form-service", 1, nil)
+
+	rec := httptest.NewRecorder()
+	Handler(info).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var got Info
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, info, got)
+}
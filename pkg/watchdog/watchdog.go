@@ -0,0 +1,209 @@
+// Package watchdog supervises long-running loops such as the listener and
+// consumer goroutines. It records their last activity, reports them as
+// unhealthy once they exit or go silent, and can restart them or stop the
+// process depending on the configured policy.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Policy decides what happens when a supervised loop exits
+type Policy string
+
+const (
+	// PolicyNone only reports the loop as unhealthy
+	PolicyNone Policy = "none"
+	// PolicyRestart restarts the loop after the restart delay
+	PolicyRestart Policy = "restart"
+	// PolicyExit terminates the process so the orchestrator can restart it
+	PolicyExit Policy = "exit"
+)
+
+// DEFAULT_RESTART_DELAY is used when no restart delay is configured
+const DEFAULT_RESTART_DELAY = time.Second
+
+type (
+	// Options configures a Watchdog
+	Options struct {
+		Policy       Policy        // Reaction to a loop exiting
+		RestartDelay time.Duration // Pause before restarting a loop
+		MaxIdle      time.Duration // Max time without activity, 0 disables the check
+	}
+
+	// loopState is the tracked state of a single supervised loop
+	loopState struct {
+		running      bool
+		lastActivity time.Time
+		restarts     int
+	}
+
+	// Watchdog tracks supervised loops and implements health.Healther
+	Watchdog struct {
+		mu     sync.RWMutex
+		loops  map[string]*loopState
+		opts   Options
+		logger *logger.Logger
+		exit   func(code int) // Terminates the process, replaced in tests
+	}
+)
+
+// New creates a watchdog with the given options
+func New(logger *logger.Logger, opts Options) *Watchdog {
+	if opts.Policy == "" {
+		opts.Policy = PolicyNone
+	}
+
+	if opts.RestartDelay <= 0 {
+		opts.RestartDelay = DEFAULT_RESTART_DELAY
+	}
+
+	return &Watchdog{
+		loops:  make(map[string]*loopState),
+		opts:   opts,
+		logger: logger,
+		exit:   os.Exit,
+	}
+}
+
+// Go runs loop in a new goroutine under supervision. A panic inside the loop
+// is recovered and treated like the loop returning. The loop is not restarted
+// once ctx is done.
+func (w *Watchdog) Go(ctx context.Context, name string, loop func(ctx context.Context)) {
+	w.mu.Lock()
+	state, ok := w.loops[name]
+	if !ok {
+		state = &loopState{}
+		w.loops[name] = state
+	}
+	state.running = true
+	state.lastActivity = time.Now()
+	w.mu.Unlock()
+
+	go w.supervise(ctx, name, loop)
+}
+
+// supervise runs the loop and applies the exit policy when it returns
+func (w *Watchdog) supervise(ctx context.Context, name string, loop func(ctx context.Context)) {
+	err := run(ctx, loop)
+
+	w.mu.Lock()
+	state := w.loops[name]
+	state.running = false
+	w.mu.Unlock()
+
+	if ctx.Err() != nil {
+		w.logger.Info("supervised loop stopped", zap.String("loop", name))
+		return
+	}
+
+	w.logger.Error("supervised loop exited unexpectedly",
+		zap.String("loop", name),
+		zap.String("policy", string(w.opts.Policy)),
+		zap.Error(err))
+
+	switch w.opts.Policy {
+	case PolicyRestart:
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.opts.RestartDelay):
+		}
+
+		w.mu.Lock()
+		state.restarts++
+		w.mu.Unlock()
+
+		w.logger.Warn("restarting supervised loop", zap.String("loop", name))
+		w.Go(ctx, name, loop)
+
+	case PolicyExit:
+		w.exit(1)
+	}
+}
+
+// run calls loop and converts a panic into an error
+func run(ctx context.Context, loop func(ctx context.Context)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	loop(ctx)
+
+	return fmt.Errorf("loop returned")
+}
+
+// Beat records activity for the named loop
+func (w *Watchdog) Beat(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if state, ok := w.loops[name]; ok {
+		state.lastActivity = time.Now()
+	}
+}
+
+// Heartbeat returns a function that records activity for the named loop,
+// suitable for passing to components that shouldn't know about the watchdog
+func (w *Watchdog) Heartbeat(name string) func() {
+	return func() {
+		w.Beat(name)
+	}
+}
+
+// IsHealthy reports whether every supervised loop is running and, when
+// MaxIdle is set, has shown activity recently
+func (w *Watchdog) IsHealthy() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for name, state := range w.loops {
+		if !state.running {
+			w.logger.Warn("supervised loop is not running", zap.String("loop", name))
+			return false
+		}
+
+		if w.opts.MaxIdle > 0 && time.Since(state.lastActivity) > w.opts.MaxIdle {
+			w.logger.Warn("supervised loop is idle",
+				zap.String("loop", name),
+				zap.Time("last_activity", state.lastActivity))
+			return false
+		}
+	}
+
+	return true
+}
+
+// LastActivity returns the last recorded activity of the named loop
+func (w *Watchdog) LastActivity(name string) (time.Time, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	state, ok := w.loops[name]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return state.lastActivity, true
+}
+
+// Restarts returns how many times the named loop has been restarted
+func (w *Watchdog) Restarts(name string) int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if state, ok := w.loops[name]; ok {
+		return state.restarts
+	}
+
+	return 0
+}
@@ -0,0 +1,112 @@
+package watchdog
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Koyo-os/form-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newTestWatchdog(opts Options) *Watchdog {
+	return New(&logger.Logger{Logger: zap.NewNop()}, opts)
+}
+
+func TestWatchdog_Health(t *testing.T) {
+	t.Run("running loop is healthy", func(t *testing.T) {
+		w := newTestWatchdog(Options{})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		w.Go(ctx, "listener", func(ctx context.Context) { <-ctx.Done() })
+
+		assert.True(t, w.IsHealthy())
+	})
+
+	t.Run("exited loop is unhealthy", func(t *testing.T) {
+		w := newTestWatchdog(Options{})
+
+		w.Go(context.Background(), "listener", func(context.Context) {})
+
+		assert.Eventually(t, func() bool { return !w.IsHealthy() }, time.Second, time.Millisecond)
+	})
+
+	t.Run("panicking loop is unhealthy", func(t *testing.T) {
+		w := newTestWatchdog(Options{})
+
+		w.Go(context.Background(), "consumer", func(context.Context) { panic("boom") })
+
+		assert.Eventually(t, func() bool { return !w.IsHealthy() }, time.Second, time.Millisecond)
+	})
+
+	t.Run("idle loop is unhealthy once MaxIdle passes", func(t *testing.T) {
+		w := newTestWatchdog(Options{MaxIdle: 20 * time.Millisecond})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		w.Go(ctx, "listener", func(ctx context.Context) { <-ctx.Done() })
+
+		assert.True(t, w.IsHealthy())
+		assert.Eventually(t, func() bool { return !w.IsHealthy() }, time.Second, time.Millisecond)
+
+		w.Beat("listener")
+		assert.True(t, w.IsHealthy())
+	})
+}
+
+func TestWatchdog_Policies(t *testing.T) {
+	t.Run("restart policy restarts the loop", func(t *testing.T) {
+		w := newTestWatchdog(Options{Policy: PolicyRestart, RestartDelay: time.Millisecond})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var runs atomic.Int32
+		w.Go(ctx, "listener", func(ctx context.Context) {
+			if runs.Add(1) < 3 {
+				return
+			}
+			<-ctx.Done()
+		})
+
+		assert.Eventually(t, func() bool { return runs.Load() == 3 }, time.Second, time.Millisecond)
+		assert.Eventually(t, w.IsHealthy, time.Second, time.Millisecond)
+		assert.Equal(t, 2, w.Restarts("listener"))
+	})
+
+	t.Run("exit policy terminates the process", func(t *testing.T) {
+		w := newTestWatchdog(Options{Policy: PolicyExit})
+
+		exited := make(chan int, 1)
+		w.exit = func(code int) { exited <- code }
+
+		w.Go(context.Background(), "consumer", func(context.Context) {})
+
+		select {
+		case code := <-exited:
+			assert.Equal(t, 1, code)
+		case <-time.After(time.Second):
+			t.Fatal("process was not terminated")
+		}
+	})
+
+	t.Run("cancelled context stops without applying the policy", func(t *testing.T) {
+		w := newTestWatchdog(Options{Policy: PolicyExit})
+		w.exit = func(int) { t.Error("exit called on shutdown") }
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		w.Go(ctx, "listener", func(ctx context.Context) {
+			<-ctx.Done()
+			close(done)
+		})
+
+		cancel()
+		<-done
+
+		assert.Eventually(t, func() bool { return !w.IsHealthy() }, time.Second, time.Millisecond)
+	})
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/Koyo-os/form-service/internal/entity"
+)
+
+// ErrPrivateAddress is returned for webhooks dialing an address that isn't
+// public, see entity.PublicAddress
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+type (
+	// Event is the event rendered into webhook bodies, in the shape of the
+	// events the service publishes
+	Event struct {
+		ID        string    `json:"id"`
+		Type      string    `json:"type"`
+		Timestamp time.Time `json:"timestamp"`
+		Payload   any       `json:"payload"`
+	}
+
+	// Sender posts rendered bodies to webhook URLs
+	Sender struct {
+		client *http.Client
+		public func(netip.Addr) bool // Addresses webhooks may dial
+	}
+)
+
+// NewSender creates a sender giving up on a webhook after timeout. Only
+// public addresses are dialed, checked on every connection so neither
+// redirects nor hosts resolving differently than when the webhook was saved
+// reach the service's own network. Proxies aren't used, as they would dial
+// in its place.
+func NewSender(timeout time.Duration) *Sender {
+	s := &Sender{public: entity.PublicAddress}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Control: s.control}).DialContext
+
+	s.client = &http.Client{Timeout: timeout, Transport: transport}
+	return s
+}
+
+// control refuses connections to addresses that aren't public, with the
+// host already resolved
+func (s *Sender) control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("failed to parse address: %w", err)
+	}
+
+	if !s.public(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// Send posts body to url as JSON. Responses other than 2xx are errors.
+func (s *Sender) Send(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drained so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, MAX_BODY_SIZE))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSender creates a sender allowed to dial the loopback servers of
+// the tests
+func newTestSender() *Sender {
+	sender := NewSender(time.Second)
+	sender.public = func(netip.Addr) bool { return true }
+	return sender
+}
+
+func TestSender_Send(t *testing.T) {
+	t.Run("posts the body as JSON", func(t *testing.T) {
+		var contentType, body string
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			contentType, body = r.Header.Get("Content-Type"), string(data)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		err := newTestSender().Send(context.Background(), server.URL, []byte(`{"form":"f-1"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", contentType)
+		assert.Equal(t, `{"form":"f-1"}`, body)
+	})
+
+	t.Run("fails on error statuses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		err := newTestSender().Send(context.Background(), server.URL, []byte(`{}`))
+
+		assert.ErrorContains(t, err, "502")
+	})
+}
+
+func TestSender_SendPrivate(t *testing.T) {
+	posted := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		posted = true
+	}))
+	defer server.Close()
+
+	err := NewSender(time.Second).Send(context.Background(), server.URL, []byte(`{}`))
+
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+	assert.False(t, posted, "loopback isn't dialed")
+}
@@ -0,0 +1,266 @@
+// Package webhook shapes and posts the bodies sent to webhooks. Form owners
+// attach a payload template to a webhook so the body matches what their
+// downstream system expects instead of the internal event format.
+//
+// The service delivers webhooks from a subscriber on the event bus, which
+// renders each event through the template of its webhook and posts the
+// body with a Sender.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Template kinds
+const (
+	KIND_NONE     = ""         // The event is posted as is
+	KIND_GO       = "go"       // A Go text/template producing the body
+	KIND_JSONPATH = "jsonpath" // A JSON object mapping body fields to paths in the event
+)
+
+// MAX_BODY_SIZE bounds the size of a rendered body
+const MAX_BODY_SIZE = 1 << 20
+
+// ErrInvalidTemplate is returned for templates that fail to compile
+var ErrInvalidTemplate = errors.New("invalid webhook template")
+
+type (
+	// Template renders events into webhook bodies. The zero value posts
+	// events unchanged.
+	Template struct {
+		kind    string
+		tmpl    *template.Template
+		mapping any // Decoded mapping: map[string]any, []any or path strings
+	}
+
+	// pathStep is one step of a JSONPath: a field name or an array index
+	pathStep struct {
+		field string
+		index int
+		isIdx bool
+	}
+)
+
+// Compile parses a payload template of the given kind.
+//
+// Go templates see the event decoded from JSON and have a json function
+// encoding a value, e.g. {"text": {{json .payload.title}}}.
+//
+// JSONPath mappings are JSON documents whose strings starting with "$" are
+// replaced by the value at that path in the event, e.g.
+// {"title": "$.payload.title", "first": "$.payload.answers[0].value"}.
+// Other values are copied as they are.
+func Compile(kind, source string) (*Template, error) {
+	switch kind {
+	case KIND_NONE:
+		return &Template{}, nil
+
+	case KIND_GO:
+		tmpl, err := template.New("webhook").
+			Option("missingkey=zero").
+			Funcs(template.FuncMap{"json": encode}).
+			Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+		}
+
+		return &Template{kind: kind, tmpl: tmpl}, nil
+
+	case KIND_JSONPATH:
+		var mapping any
+		if err := json.Unmarshal([]byte(source), &mapping); err != nil {
+			return nil, fmt.Errorf("%w: mapping is not JSON: %w", ErrInvalidTemplate, err)
+		}
+
+		if err := checkPaths(mapping); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+		}
+
+		return &Template{kind: kind, mapping: mapping}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidTemplate, kind)
+	}
+}
+
+// Render produces the webhook body for event, which must encode to JSON.
+// The body is always a JSON document.
+func (t *Template) Render(event any) ([]byte, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	if t == nil || t.kind == KIND_NONE {
+		return raw, nil
+	}
+
+	var data any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	var body []byte
+
+	switch t.kind {
+	case KIND_GO:
+		var buf bytes.Buffer
+		if err := t.tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to execute template: %w", err)
+		}
+
+		body = buf.Bytes()
+		if !json.Valid(body) {
+			return nil, errors.New("template did not produce JSON")
+		}
+
+	case KIND_JSONPATH:
+		body, err = json.Marshal(apply(t.mapping, data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode body: %w", err)
+		}
+	}
+
+	if len(body) > MAX_BODY_SIZE {
+		return nil, fmt.Errorf("body of %d bytes exceeds %d", len(body), MAX_BODY_SIZE)
+	}
+
+	return body, nil
+}
+
+// encode is the json template function
+func encode(value any) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// checkPaths validates every path of a mapping
+func checkPaths(mapping any) error {
+	switch m := mapping.(type) {
+	case map[string]any:
+		for _, value := range m {
+			if err := checkPaths(value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range m {
+			if err := checkPaths(value); err != nil {
+				return err
+			}
+		}
+	case string:
+		if strings.HasPrefix(m, "$") {
+			if _, err := parsePath(m); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply replaces the paths of a mapping by their values in data. Paths
+// that don't resolve become null.
+func apply(mapping, data any) any {
+	switch m := mapping.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(m))
+		for key, value := range m {
+			out[key] = apply(value, data)
+		}
+		return out
+	case []any:
+		out := make([]any, len(m))
+		for i, value := range m {
+			out[i] = apply(value, data)
+		}
+		return out
+	case string:
+		if !strings.HasPrefix(m, "$") {
+			return m
+		}
+
+		steps, _ := parsePath(m)
+		return lookup(data, steps)
+	default:
+		return m
+	}
+}
+
+// parsePath parses the dotted subset of JSONPath: $, .field and [index]
+func parsePath(path string) ([]pathStep, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("path %q must start with $", path)
+	}
+
+	var steps []pathStep
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			if end == 0 {
+				return nil, fmt.Errorf("path %q has an empty field", path)
+			}
+
+			steps = append(steps, pathStep{field: rest[:end]})
+			rest = rest[end:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q has an unclosed index", path)
+			}
+
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path %q has an invalid index %q", path, rest[1:end])
+			}
+
+			steps = append(steps, pathStep{index: index, isIdx: true})
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("path %q is malformed at %q", path, rest)
+		}
+	}
+
+	return steps, nil
+}
+
+// lookup follows steps through data, nil when a step doesn't resolve
+func lookup(data any, steps []pathStep) any {
+	for _, step := range steps {
+		if step.isIdx {
+			items, ok := data.([]any)
+			if !ok || step.index >= len(items) {
+				return nil
+			}
+
+			data = items[step.index]
+			continue
+		}
+
+		fields, ok := data.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		data = fields[step.field]
+	}
+
+	return data
+}
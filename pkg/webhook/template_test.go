@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEvent = map[string]any{
+	"type": "form.response.accepted",
+	"payload": map[string]any{
+		"form_id": "f-1",
+		"answers": []any{
+			map[string]any{"order_number": 1, "value": "hello"},
+		},
+	},
+}
+
+func TestTemplate_Render(t *testing.T) {
+	t.Run("posts the event unchanged without a template", func(t *testing.T) {
+		tmpl, err := Compile(KIND_NONE, "")
+		require.NoError(t, err)
+
+		body, err := tmpl.Render(testEvent)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"form.response.accepted","payload":{"form_id":"f-1","answers":[{"order_number":1,"value":"hello"}]}}`, string(body))
+	})
+
+	t.Run("renders go templates", func(t *testing.T) {
+		tmpl, err := Compile(KIND_GO, `{"text": {{json .payload.form_id}}, "kind": "{{.type}}"}`)
+		require.NoError(t, err)
+
+		body, err := tmpl.Render(testEvent)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"text":"f-1","kind":"form.response.accepted"}`, string(body))
+	})
+
+	t.Run("rejects go templates producing something else than JSON", func(t *testing.T) {
+		tmpl, err := Compile(KIND_GO, `form {{.payload.form_id}}`)
+		require.NoError(t, err)
+
+		_, err = tmpl.Render(testEvent)
+		assert.Error(t, err)
+	})
+
+	t.Run("applies jsonpath mappings", func(t *testing.T) {
+		tmpl, err := Compile(KIND_JSONPATH, `{
+			"form": "$.payload.form_id",
+			"first": {"answer": "$.payload.answers[0].value"},
+			"missing": "$.payload.nope[3]",
+			"source": "forms"
+		}`)
+		require.NoError(t, err)
+
+		body, err := tmpl.Render(testEvent)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"form":"f-1","first":{"answer":"hello"},"missing":null,"source":"forms"}`, string(body))
+	})
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for name, tc := range map[string]struct{ kind, source string }{
+		"unknown kind":       {"xslt", ""},
+		"broken go template": {KIND_GO, "{{.payload"},
+		"mapping not json":   {KIND_JSONPATH, "{"},
+		"unclosed index":     {KIND_JSONPATH, `{"a": "$.answers[0"}`},
+		"empty field":        {KIND_JSONPATH, `{"a": "$..title"}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile(tc.kind, tc.source)
+			assert.ErrorIs(t, err, ErrInvalidTemplate)
+		})
+	}
+}